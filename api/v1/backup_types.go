@@ -18,6 +18,8 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // BackupPhase is the phase of the backup
@@ -43,10 +45,67 @@ const (
 	BackupPhaseWalArchivingFailing = "walArchivingFailing"
 )
 
+// BackupMethod is the way a physical base backup of the PostgreSQL
+// instance is taken
+type BackupMethod string
+
+const (
+	// BackupMethodBarmanObjectStore means using barman-cloud-backup to store
+	// the backup in an object store
+	BackupMethodBarmanObjectStore BackupMethod = "barmanObjectStore"
+
+	// BackupMethodVolumeSnapshot means taking a snapshot of the volumes of
+	// the instance via the Kubernetes VolumeSnapshot API
+	BackupMethodVolumeSnapshot BackupMethod = "volumeSnapshot"
+
+	// BackupMethodPlugin means delegating the backup to one of the
+	// plugins running as sidecars of the instances
+	BackupMethodPlugin BackupMethod = "plugin"
+)
+
+// BackupPluginConfiguration selects the plugin taking the backup, or
+// storing the backups to recover from
+type BackupPluginConfiguration struct {
+	// The name of the plugin, as declared in the `plugins` section
+	// of the cluster
+	Name string `json:"name"`
+
+	// Parameters passed to the plugin, overriding the ones declared in
+	// the cluster
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// BackupSnapshotElementStatus is a volume snapshot that is part of a backup
+type BackupSnapshotElementStatus struct {
+	// The name of the VolumeSnapshot
+	Name string `json:"name"`
+
+	// The role of the snapshotted volume, `PG_DATA`, `PG_WAL` or
+	// `PG_TABLESPACE`
+	Type string `json:"type"`
+
+	// The name of the tablespace stored in the snapshotted volume,
+	// when its role is `PG_TABLESPACE`
+	// +optional
+	TablespaceName string `json:"tablespaceName,omitempty"`
+}
+
 // BackupSpec defines the desired state of Backup
 type BackupSpec struct {
 	// The cluster to backup
 	Cluster LocalObjectReference `json:"cluster,omitempty"`
+
+	// The backup method to be used, `barmanObjectStore`, `volumeSnapshot`
+	// or `plugin`. Defaults to `barmanObjectStore`
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin
+	// +kubebuilder:default:=barmanObjectStore
+	// +optional
+	Method BackupMethod `json:"method,omitempty"`
+
+	// The plugin taking the backup, required by the `plugin` method
+	// +optional
+	PluginConfiguration *BackupPluginConfiguration `json:"pluginConfiguration,omitempty"`
 }
 
 // BackupStatus defines the observed state of Backup
@@ -75,6 +134,10 @@ type BackupStatus struct {
 	// Encryption method required to S3 API
 	Encryption string `json:"encryption,omitempty"`
 
+	// The client-side encryption of the WAL files and of the base backup
+	// in the object store
+	ClientSideEncryption *ClientSideEncryptionConfiguration `json:"clientSideEncryption,omitempty"`
+
 	// The ID of the Barman backup
 	BackupID string `json:"backupId,omitempty"`
 
@@ -110,6 +173,95 @@ type BackupStatus struct {
 
 	// Information to identify the instance where the backup has been taken from
 	InstanceID *InstanceID `json:"instanceID,omitempty"`
+
+	// The results of the hooks executed around the backup
+	Hooks []BackupHookStatus `json:"hooks,omitempty"`
+
+	// The size and the duration of the backup
+	Statistics *BackupStatistics `json:"statistics,omitempty"`
+
+	// The backup method that has been used
+	Method BackupMethod `json:"method,omitempty"`
+
+	// True if the backup has been taken while PostgreSQL was running,
+	// between pg_backup_start and pg_backup_stop. Only used by the
+	// `volumeSnapshot` method
+	Online *bool `json:"online,omitempty"`
+
+	// The volume snapshots composing the backup. Only used by the
+	// `volumeSnapshot` method
+	Snapshots []BackupSnapshotElementStatus `json:"snapshots,omitempty"`
+
+	// The content of the backup_label file returned by pg_backup_stop,
+	// needed to restore an online backup taken with the `volumeSnapshot`
+	// method
+	BackupLabelFile []byte `json:"backupLabelFile,omitempty"`
+
+	// The content of the tablespace_map file returned by pg_backup_stop,
+	// needed to restore an online backup taken with the `volumeSnapshot`
+	// method
+	TablespaceMapFile []byte `json:"tablespaceMapFile,omitempty"`
+
+	// The name of the plugin that has taken the backup. Only used by
+	// the `plugin` method
+	PluginName string `json:"pluginName,omitempty"`
+
+	// Opaque data returned by the plugin, needed to restore the backup.
+	// Only used by the `plugin` method
+	PluginMetadata map[string]string `json:"pluginMetadata,omitempty"`
+}
+
+// BackupStatistics contains the size and the duration of a backup
+type BackupStatistics struct {
+	// The size in bytes of the databases when the backup was started,
+	// before any compression
+	DataSize int64 `json:"dataSize,omitempty"`
+
+	// The size in bytes of the backup, as reported by Barman. It
+	// reflects the compression of the data, and is not reported by
+	// every version of Barman
+	Size *int64 `json:"size,omitempty"`
+
+	// The size in bytes of the data actually copied, when Barman
+	// deduplicates the files not changed since the previous backup, as
+	// it happens with incremental backup methods
+	DeduplicatedSize *int64 `json:"deduplicatedSize,omitempty"`
+
+	// The time spent copying the data
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// BackupHookStage is the moment when a backup hook is executed
+type BackupHookStage string
+
+const (
+	// BackupHookStagePre is used for the hooks executed before the backup
+	BackupHookStagePre BackupHookStage = "pre"
+
+	// BackupHookStagePost is used for the hooks executed after the backup
+	BackupHookStagePost BackupHookStage = "post"
+)
+
+// BackupHookStatus is the result of the execution of a backup hook
+type BackupHookStatus struct {
+	// The name of the hook
+	Name string `json:"name"`
+
+	// When the hook has been executed, `pre` or `post`
+	Stage BackupHookStage `json:"stage"`
+
+	// When the hook was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the hook was terminated
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The error returned by the hook, if any
+	Error string `json:"error,omitempty"`
+
+	// True if the hook failure has been ignored as requested
+	// by its failure policy
+	Ignored bool `json:"ignored,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
@@ -173,6 +325,47 @@ func (backupStatus *BackupStatus) SetAsCompleted() {
 	backupStatus.Error = ""
 }
 
+// GetMethod returns the backup method, defaulting to barmanObjectStore
+// for the backups created before the field was introduced
+func (backup *Backup) GetMethod() BackupMethod {
+	if backup.Spec.Method == "" {
+		return BackupMethodBarmanObjectStore
+	}
+	return backup.Spec.Method
+}
+
+// IsVolumeSnapshot returns true if the backup is made of volume snapshots
+func (backup *Backup) IsVolumeSnapshot() bool {
+	return backup.GetMethod() == BackupMethodVolumeSnapshot
+}
+
+// IsPlugin returns true if the backup is taken by a plugin
+func (backup *Backup) IsPlugin() bool {
+	return backup.GetMethod() == BackupMethodPlugin
+}
+
+// GetSnapshotName returns the name of the snapshot of the volume with the
+// passed role, or an empty string if there is none
+func (backupStatus *BackupStatus) GetSnapshotName(snapshotType string) string {
+	for _, snapshot := range backupStatus.Snapshots {
+		if snapshot.Type == snapshotType {
+			return snapshot.Name
+		}
+	}
+	return ""
+}
+
+// GetTablespaceSnapshotName returns the name of the snapshot of the volume
+// storing the passed tablespace, or an empty string if there is none
+func (backupStatus *BackupStatus) GetTablespaceSnapshotName(tablespaceName string) string {
+	for _, snapshot := range backupStatus.Snapshots {
+		if snapshot.Type == string(utils.PVCRolePgTablespace) && snapshot.TablespaceName == tablespaceName {
+			return snapshot.Name
+		}
+	}
+	return ""
+}
+
 // IsDone check if a backup is completed or still in progress
 func (backupStatus *BackupStatus) IsDone() bool {
 	return backupStatus.Phase == BackupPhaseCompleted || backupStatus.Phase == BackupPhaseFailed
@@ -17,7 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -51,7 +54,16 @@ var _ webhook.Validator = &Backup{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Backup) ValidateCreate() error {
 	backupLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	return nil
+
+	allErrs := validateBackupPluginConfiguration(
+		r.Spec.Method, r.Spec.PluginConfiguration, field.NewPath("spec", "pluginConfiguration"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "Backup"},
+		r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -65,3 +77,27 @@ func (r *Backup) ValidateDelete() error {
 	backupLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
 	return nil
 }
+
+// validateBackupPluginConfiguration ensures that the plugin taking the
+// backup is selected when, and only when, the `plugin` method is used
+func validateBackupPluginConfiguration(
+	method BackupMethod,
+	configuration *BackupPluginConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	var result field.ErrorList
+
+	switch {
+	case method == BackupMethodPlugin && (configuration == nil || configuration.Name == ""):
+		result = append(result, field.Required(
+			path.Child("name"),
+			"the plugin taking the backup is required by the plugin method"))
+	case method != BackupMethodPlugin && configuration != nil:
+		result = append(result, field.Invalid(
+			path,
+			configuration.Name,
+			"the plugin configuration is only used by the plugin method"))
+	}
+
+	return result
+}
@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup plugin configuration validation", func() {
+	It("requires the plugin with the plugin method", func() {
+		backup := &Backup{Spec: BackupSpec{Method: BackupMethodPlugin}}
+		Expect(backup.ValidateCreate()).To(HaveOccurred())
+
+		backup.Spec.PluginConfiguration = &BackupPluginConfiguration{Name: "pgbackrest"}
+		Expect(backup.ValidateCreate()).To(Succeed())
+	})
+
+	It("rejects the plugin configuration with the other methods", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:              BackupMethodVolumeSnapshot,
+				PluginConfiguration: &BackupPluginConfiguration{Name: "pgbackrest"},
+			},
+		}
+		Expect(backup.ValidateCreate()).To(HaveOccurred())
+
+		backup.Spec.PluginConfiguration = nil
+		Expect(backup.ValidateCreate()).To(Succeed())
+	})
+
+	It("is applied to the scheduled backups", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Method:   BackupMethodPlugin,
+			},
+		}
+		Expect(schedule.ValidateCreate()).To(HaveOccurred())
+
+		schedule.Spec.PluginConfiguration = &BackupPluginConfiguration{Name: "pgbackrest"}
+		Expect(schedule.ValidateCreate()).To(Succeed())
+		Expect(schedule.CreateBackup("backup").Spec.PluginConfiguration.Name).To(Equal("pgbackrest"))
+	})
+})
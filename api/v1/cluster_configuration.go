@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -25,6 +27,10 @@ import (
 // the electable sync replicas given the requested min, max, the number of ready replicas in the cluster and the sync
 // replicas constraints (if any)
 func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncReplicas []string) {
+	if cluster.Spec.PostgresConfiguration.Synchronous != nil {
+		return cluster.getSynchronousReplicaData()
+	}
+
 	// We start with the number of healthy replicas (healthy pods minus one)
 	// and verify it is greater than 0 and between minSyncReplicas and maxSyncReplicas.
 	// Formula: 1 <= minSyncReplicas <= SyncReplicas <= maxSyncReplicas < readyReplicas
@@ -49,7 +55,7 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 			"maxSyncReplicas", cluster.Spec.MaxSyncReplicas)
 	}
 
-	electableSyncReplicas = cluster.getElectableSyncReplicas()
+	electableSyncReplicas = cluster.getElectableSyncReplicas(cluster.Status.InstancesStatus[utils.PodHealthy])
 	numberOfElectableSyncReplicas := len(electableSyncReplicas)
 	if numberOfElectableSyncReplicas < syncReplicas {
 		log.Warning("lowering sync replicas due to not enough electable instances for sync replication "+
@@ -63,14 +69,76 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
-// getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
-func (cluster *Cluster) getElectableSyncReplicas() []string {
+// getSynchronousReplicaData computes the number of synchronous replicas
+// and the names of the electable instances given the synchronous
+// replication configuration. When the synchronous replication is required,
+// every instance is electable, even if not ready, and the number of
+// synchronous replicas is never lowered
+func (cluster *Cluster) getSynchronousReplicaData() (syncReplicas int, electableSyncReplicas []string) {
+	config := cluster.Spec.PostgresConfiguration.Synchronous
+
+	if config.IsRequired() {
+		electableSyncReplicas = cluster.getElectableSyncReplicas(cluster.Status.InstanceNames)
+		if len(electableSyncReplicas) == 0 {
+			// Dropping the synchronous standbys would allow writes
+			// without the required durability
+			log.Warning("no electable sync replicas given the constraints, " +
+				"falling back to every instance to keep the synchronous replication required")
+			electableSyncReplicas = cluster.getNonPrimaryInstances(cluster.Status.InstanceNames)
+		}
+	} else {
+		electableSyncReplicas = cluster.getElectableSyncReplicas(cluster.Status.InstancesStatus[utils.PodHealthy])
+	}
+
+	if config.MaxStandbyNamesFromCluster != nil && len(electableSyncReplicas) > *config.MaxStandbyNamesFromCluster {
+		sort.Strings(electableSyncReplicas)
+		electableSyncReplicas = electableSyncReplicas[:*config.MaxStandbyNamesFromCluster]
+	}
+
+	syncReplicas = config.Number
+	if !config.IsRequired() && len(electableSyncReplicas) < syncReplicas {
+		log.Warning("lowering sync replicas due to not enough ready electable instances",
+			"number", config.Number,
+			"electableSyncReplicas", len(electableSyncReplicas))
+		syncReplicas = len(electableSyncReplicas)
+	}
+
+	return syncReplicas, electableSyncReplicas
+}
+
+// GetRequiredLocalSyncReplicas returns the number of instances of the
+// cluster which need to be synchronous standbys when the synchronous
+// replication is required, and zero otherwise
+func (cluster *Cluster) GetRequiredLocalSyncReplicas() int {
+	config := cluster.Spec.PostgresConfiguration.Synchronous
+	if config == nil || !config.IsRequired() {
+		return 0
+	}
+
+	required := config.Number - len(config.StandbyNamesPre) - len(config.StandbyNamesPost)
+	if required < 0 {
+		return 0
+	}
+	return required
+}
+
+// getNonPrimaryInstances filters out the current primary and the
+// restricted replicas from the passed instances
+func (cluster *Cluster) getNonPrimaryInstances(instances []string) []string {
 	var nonPrimaryInstances []string
-	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
+	for _, instance := range instances {
+		// Restricted replicas are never promoted, and their workload
+		// should not slow down the commits on the primary
+		if cluster.Status.CurrentPrimary != instance && !cluster.IsRestrictedReplica(instance) {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
+	return nonPrimaryInstances
+}
+
+// getElectableSyncReplicas computes the names of the passed instances that can be elected to sync replicas
+func (cluster *Cluster) getElectableSyncReplicas(instances []string) []string {
+	nonPrimaryInstances := cluster.getNonPrimaryInstances(instances)
 
 	topology := cluster.Status.Topology
 	// We need to include every replica inside the list of possible synchronous standbys if we have no constraints
@@ -31,6 +31,15 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{"example-2", "example-3"}))
 	})
 
+	It("should not return the restricted replicas as electable", func() {
+		cluster := createFakeCluster("example")
+		cluster.Name = "example"
+		cluster.Spec.RestrictedReplicas = &RestrictedReplicasConfiguration{Instances: []int{3}}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
 	It("should return only the pod in the different AZ", func() {
 		const (
 			primaryPod     = "example-1"
@@ -80,3 +89,99 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
 })
+
+var _ = Describe("synchronous replication configuration", func() {
+	newCluster := func(config SynchronousReplicaConfiguration) *Cluster {
+		cluster := createFakeCluster("example")
+		cluster.Spec.MinSyncReplicas = 0
+		cluster.Spec.MaxSyncReplicas = 0
+		cluster.Spec.PostgresConfiguration.Synchronous = &config
+		cluster.Status.InstanceNames = []string{"example-1", "example-2", "example-3"}
+		return cluster
+	}
+
+	It("lowers the number of synchronous replicas to the ready instances when preferred", func() {
+		cluster := newCluster(SynchronousReplicaConfiguration{
+			Method:         SynchronousReplicaConfigurationMethodAny,
+			Number:         2,
+			DataDurability: DataDurabilityLevelPreferred,
+		})
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"example-1", "example-2"},
+			utils.PodFailed:  {"example-3"},
+		}
+
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
+	It("doesn't count the external standbys in the lowered number when preferred", func() {
+		cluster := newCluster(SynchronousReplicaConfiguration{
+			Method:          SynchronousReplicaConfigurationMethodAny,
+			Number:          2,
+			StandbyNamesPre: []string{"dr-site"},
+			DataDurability:  DataDurabilityLevelPreferred,
+		})
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"example-1", "example-2"},
+			utils.PodFailed:  {"example-3"},
+		}
+
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+		Expect(cluster.GetRequiredLocalSyncReplicas()).To(BeZero())
+	})
+
+	It("keeps the unavailable instances and the number of synchronous replicas when required", func() {
+		cluster := newCluster(SynchronousReplicaConfiguration{
+			Method:         SynchronousReplicaConfigurationMethodFirst,
+			Number:         2,
+			DataDurability: DataDurabilityLevelRequired,
+		})
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"example-1"},
+			utils.PodFailed:  {"example-2", "example-3"},
+		}
+
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+	})
+
+	It("limits the number of instances of the cluster in the standby names", func() {
+		maxStandbyNames := 1
+		cluster := newCluster(SynchronousReplicaConfiguration{
+			Method:                     SynchronousReplicaConfigurationMethodAny,
+			Number:                     2,
+			MaxStandbyNamesFromCluster: &maxStandbyNames,
+			StandbyNamesPost:           []string{"dr-site"},
+			DataDurability:             DataDurabilityLevelRequired,
+		})
+
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2"}))
+		Expect(cluster.GetRequiredLocalSyncReplicas()).To(Equal(1))
+	})
+})
+
+var _ = Describe("synchronized standby slots", func() {
+	It("lists the high availability slots of the instances which can be promoted", func() {
+		cluster := createFakeCluster("example")
+		cluster.Name = "example"
+		cluster.Status.InstanceNames = []string{"example-1", "example-2", "example-3", "example-4"}
+		cluster.Spec.RestrictedReplicas = &RestrictedReplicasConfiguration{Instances: []int{4}}
+		cluster.Spec.ReplicationSlots = nil
+		Expect(cluster.GetSynchronizedStandbySlots("example-1")).To(BeEmpty())
+
+		cluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{Enabled: true},
+		}
+		Expect(cluster.GetSynchronizedStandbySlots("example-1")).To(Equal([]string{
+			"_cnpg_example_2",
+			"_cnpg_example_3",
+		}))
+	})
+})
@@ -20,15 +20,20 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -73,11 +78,30 @@ const (
 	// service name for every ready node that you can use to read data (excluding the primary)
 	ServiceReadOnlySuffix = "-ro"
 
+	// ServiceRestrictedSuffix is the suffix appended to the cluster name to
+	// get the service name for every ready restricted replica
+	ServiceRestrictedSuffix = "-restricted"
+
+	// ServiceMetricsSuffix is the suffix appended to the cluster name to
+	// get the name of the headless service exposing the metrics of every
+	// instance
+	ServiceMetricsSuffix = "-metrics"
+
 	// ServiceReadWriteSuffix is the suffix appended to the cluster name to get
 	// the se service name for every node that you can use to read and write
 	// data
 	ServiceReadWriteSuffix = "-rw"
 
+	// EndpointsRegistryConfigMapName is the name of the ConfigMap, kept
+	// in each namespace, mapping the clusters publishing their endpoints
+	// to the connection parameters of their read-only instances
+	EndpointsRegistryConfigMapName = "cnpg-endpoints"
+
+	// ServiceDiscoveryConfigMapSuffix is the suffix appended to the cluster
+	// name to get the name of the ConfigMap containing the service
+	// discovery records
+	ServiceDiscoveryConfigMapSuffix = "-discovery"
+
 	// ClusterSecretSuffix is the suffix appended to the cluster name to
 	// get the name of the pull secret
 	ClusterSecretSuffix = "-pull-secret"
@@ -106,6 +130,10 @@ const (
 
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
+
+	// MonitoringRoleName is the name of the role, member of `pg_monitor`,
+	// used to execute the monitoring queries
+	MonitoringRoleName = "cnpg_monitor"
 )
 
 // ClusterSpec defines the desired state of Cluster
@@ -121,6 +149,12 @@ type ClusterSpec struct {
 	// (`<image>:<tag>@sha256:<digestValue>`)
 	ImageName string `json:"imageName,omitempty"`
 
+	// The image catalog, and the PostgreSQL major version within it,
+	// providing the container image. It cannot be used together with
+	// `imageName`
+	// +optional
+	ImageCatalogRef *ImageCatalogRef `json:"imageCatalogRef,omitempty"`
+
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
 	// If not defined, it defaults to `IfNotPresent`.
@@ -129,6 +163,12 @@ type ClusterSpec struct {
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
+	// The configuration of the major version upgrades. When set, changing
+	// `imageName` to a newer PostgreSQL major version upgrades the data
+	// of the primary instance with `pg_upgrade` and re-clones the replicas
+	// +optional
+	MajorVersionUpgrade *MajorVersionUpgradeConfiguration `json:"majorVersionUpgrade,omitempty"`
+
 	// The UID of the `postgres` user inside the image, defaults to `26`
 	// +kubebuilder:default:=26
 	PostgresUID int64 `json:"postgresUID,omitempty"`
@@ -142,6 +182,21 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=1
 	Instances int `json:"instances"`
 
+	// The prefix of the names of the instances, which are also the names of
+	// their Pods and PVCs, followed by a dash and the instance ordinal.
+	// Defaults to the name of the cluster. Cannot be updated.
+	// +kubebuilder:validation:MaxLength=50
+	// +optional
+	InstanceNamePrefix string `json:"instanceNamePrefix,omitempty"`
+
+	// The minimum number of digits of the ordinal in the instance names,
+	// which is padded with leading zeros (i.e. `2` generates `-01`, `-02`
+	// and so on). Defaults to `0`, meaning no padding. Cannot be updated.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	// +optional
+	InstanceOrdinalPadding int `json:"instanceOrdinalPadding,omitempty"`
+
 	// Minimum number of instances required in synchronous replication with the
 	// primary. Undefined or 0 allow writes to complete when no standby is
 	// available.
@@ -163,6 +218,46 @@ type ClusterSpec struct {
 	// Replication slots management configuration
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Configuration of the streaming replication connections
+	// +optional
+	Replication *ReplicationConfiguration `json:"replication,omitempty"`
+
+	// Configuration of the restricted replicas, i.e. replicas that are
+	// never promoted, are excluded from the `-ro` service and are exposed
+	// through the dedicated `-restricted` service, typically for analytical
+	// workloads
+	// +optional
+	RestrictedReplicas *RestrictedReplicasConfiguration `json:"restrictedReplicas,omitempty"`
+
+	// The priorities of the instances when a new primary is elected.
+	// Between replicas having received the same WAL, the one with the
+	// highest priority is promoted. Instances not listed here have
+	// priority 0
+	// +optional
+	PromotionPriorities []PromotionPriority `json:"promotionPriorities,omitempty"`
+
+	// The strategy used to re-attach a former primary to the cluster after
+	// a failover or a switchover: `rewind` (default) aligns its data with
+	// the new primary using pg_rewind, while `reclone` clones the data from
+	// the new primary again. The `rewind` strategy requires `wal_log_hints`
+	// to be enabled, unless data checksums are
+	// +kubebuilder:validation:Enum:=rewind;reclone
+	// +optional
+	ReattachStrategy ReattachStrategy `json:"reattachStrategy,omitempty"`
+
+	// When enabled, the operator publishes the connection parameters of
+	// the replicas currently selected by the `-ro` service in the
+	// `cnpg-endpoints` ConfigMap of the namespace, letting the
+	// `postgres_fdw` servers defined in other clusters follow the
+	// failovers and switchovers of this one
+	// +optional
+	PublishEndpoints bool `json:"publishEndpoints,omitempty"`
+
+	// The configuration of the service discovery records published in
+	// the `serviceDiscovery` section of the status
+	// +optional
+	ServiceDiscovery *ServiceDiscoveryConfiguration `json:"serviceDiscovery,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -199,6 +294,24 @@ type ClusterSpec struct {
 	// Configuration of the storage for PostgreSQL WAL (Write-Ahead Log)
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// The check of the performance of the storage, run while
+	// bootstrapping every new instance
+	// +optional
+	StorageBenchmark *StorageBenchmarkConfiguration `json:"storageBenchmark,omitempty"`
+
+	// The tablespaces of the cluster, each of them stored in a dedicated
+	// PVC of every instance. Tablespaces cannot be added or removed after
+	// the cluster has been created
+	// +optional
+	Tablespaces []TablespaceConfiguration `json:"tablespaces,omitempty"`
+
+	// When set to `on`, every instance of the cluster is shut down and its
+	// Pod removed, while the PVCs are retained to resume the cluster when
+	// the field is set back to `off` (default)
+	// +kubebuilder:validation:Enum:=on;off
+	// +optional
+	Hibernation HibernationMode `json:"hibernation,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 30)
 	// +kubebuilder:default:=30
@@ -216,6 +329,56 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=40000000
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// Configuration of the draining of the client connections from the
+	// primary instance before it gets demoted during a switchover
+	// +optional
+	ConnectionDraining *ConnectionDrainingConfiguration `json:"connectionDraining,omitempty"`
+
+	// The handling of the prepared transactions and of the logical
+	// replication workers found on the primary instance before it gets
+	// demoted during a switchover
+	// +optional
+	SwitchoverGuardrail *SwitchoverGuardrailConfiguration `json:"switchoverGuardrail,omitempty"`
+
+	// The checkpoint requested on an instance ahead of the planned
+	// restarts and switchovers, to reduce the time needed by its
+	// shutdown checkpoint
+	// +optional
+	ShutdownCheckpoint *ShutdownCheckpointConfiguration `json:"shutdownCheckpoint,omitempty"`
+
+	// An external witness that the operator consults before promoting
+	// a replica during a failover, to avoid a split-brain when the operator
+	// loses contact with a primary that is still running
+	// +optional
+	FailoverWitness *FailoverWitnessConfiguration `json:"failoverWitness,omitempty"`
+
+	// The maximum difference, in seconds, between the clock of an instance
+	// and the one of the operator. Above it, the `ClockSynchronized`
+	// condition is set to false and the instance is promoted during a
+	// failover only when no other replica is equally up to date (default 5)
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxClockSkew int32 `json:"maxClockSkew,omitempty"`
+
+	// When enabled, the Pods of the instances are created with the
+	// `cnpg.io/streaming` readiness gate, and a replica becomes ready only
+	// once it is streaming from the primary. The condition is always set by
+	// the operator, even when the readiness gate is disabled (default `false`)
+	// +optional
+	EnableStreamingReadinessGate bool `json:"enableStreamingReadinessGate,omitempty"`
+
+	// The network isolation check run by the instance manager of the
+	// primary instance, which is shut down or made read-only when it can
+	// reach neither the Kubernetes API server nor a quorum of its replicas
+	// +optional
+	IsolationCheck *IsolationCheckConfiguration `json:"isolationCheck,omitempty"`
+
+	// Executables run by the instance manager when the instance is
+	// promoted, demoted or shut down, to notify external systems
+	// +optional
+	InstanceHooks *InstanceHooksConfiguration `json:"instanceHooks,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -226,6 +389,13 @@ type ClusterSpec struct {
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Overrides of the resources and of the storage size for a subset of
+	// the instances, selected by their ordinal or by their role. The first
+	// override matching the ordinal of an instance is used, otherwise the
+	// first one matching its role.
+	// +optional
+	InstanceOverrides []InstanceOverride `json:"instanceOverrides,omitempty"`
+
 	// Strategy to follow to upgrade the primary server during a rolling
 	// update procedure, after all replicas have been successfully updated:
 	// it can be automated (`unsupervised` - default) or manual (`supervised`)
@@ -243,9 +413,25 @@ type ClusterSpec struct {
 	// The configuration to be used for backups
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
+	// The plugins providing alternative backup, WAL archiving and recovery
+	// engines. Each plugin runs as a sidecar container of the instances
+	// +optional
+	Plugins []PluginConfiguration `json:"plugins,omitempty"`
+
+	// The actions to be taken by the operator when the cluster is deleted
+	// +optional
+	DeletionPolicy *DeletionPolicyConfiguration `json:"deletionPolicy,omitempty"`
+
 	// Define a maintenance window for the Kubernetes nodes
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// The recurring windows of time in which the operator is allowed to
+	// restart the instances and to switch over the primary to apply image
+	// updates and configuration changes. When empty, these operations are
+	// executed as soon as they are needed. Failovers are never deferred
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
 
@@ -256,6 +442,11 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=info
 	// +kubebuilder:validation:Enum:=error;warning;info;debug;trace
 	LogLevel string `json:"logLevel,omitempty"`
+
+	// The configuration of the Kubernetes resources managed by the operator
+	// for this cluster
+	// +optional
+	Managed *ManagedConfiguration `json:"managed,omitempty"`
 }
 
 const (
@@ -295,6 +486,41 @@ const (
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
+
+	// PhaseSplitBrain for a cluster where more than one instance is running
+	// as a primary, and the reconciliation has been paused
+	PhaseSplitBrain = "Split-brain detected, needs manual intervention"
+
+	// PhaseMajorUpgrade for a cluster whose data is being upgraded to a new
+	// PostgreSQL major version
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseHibernating for a cluster whose instances are being shut down
+	// as its hibernation has been requested
+	PhaseHibernating = "Hibernation in progress"
+
+	// PhaseHibernated for a cluster having every instance shut down, with
+	// only the PVCs retained
+	PhaseHibernated = "Cluster in hibernation"
+
+	// PhaseImageCatalogError for a cluster whose image cannot be resolved
+	// from the referenced image catalog
+	PhaseImageCatalogError = "Cannot retrieve the image from the catalog"
+
+	// PhaseResumingFromHibernation for a hibernated cluster whose instances
+	// are being recreated from the retained PVCs
+	PhaseResumingFromHibernation = "Resuming from hibernation"
+)
+
+// HibernationMode tells whether the hibernation of a cluster is requested
+type HibernationMode string
+
+const (
+	// HibernationOn requests the cluster to be hibernated
+	HibernationOn HibernationMode = "on"
+
+	// HibernationOff requests the cluster to be running
+	HibernationOff HibernationMode = "off"
 )
 
 // PodTopologyLabels represent the topology of a Pod. map[labelName]labelValue
@@ -338,6 +564,20 @@ type ClusterStatus struct {
 	// the reported state of the instances during the last reconciliation loop
 	InstancesReportedState map[PodName]InstanceReportedState `json:"instancesReportedState,omitempty"`
 
+	// The replicas selected by the `-ro` service during the last
+	// reconciliation loop
+	// +optional
+	ReadOnlyServiceMembers []string `json:"readOnlyServiceMembers,omitempty"`
+
+	// The replicas selected by the `-ro` service during the last
+	// reconciliation loop, sorted by ascending number of client connections
+	// +optional
+	ReadOnlyServiceMembersByConnections []string `json:"readOnlyServiceMembersByConnections,omitempty"`
+
+	// The status of the PostgreSQL major version upgrade, if any
+	// +optional
+	MajorVersionUpgrade *MajorVersionUpgradeStatus `json:"majorVersionUpgrade,omitempty"`
+
 	// The timeline of the Postgres cluster
 	TimelineID int `json:"timelineID,omitempty"`
 
@@ -382,6 +622,9 @@ type ClusterStatus struct {
 	// Current list of read pods
 	ReadService string `json:"readService,omitempty"`
 
+	// The host names and the port to be used to reach the cluster
+	ServiceDiscovery *ServiceDiscoveryStatus `json:"serviceDiscovery,omitempty"`
+
 	// Current phase of the cluster
 	Phase string `json:"phase,omitempty"`
 
@@ -406,6 +649,9 @@ type ClusterStatus struct {
 	// The first recoverability point, stored as a date in RFC3339 format
 	FirstRecoverabilityPoint string `json:"firstRecoverabilityPoint,omitempty"`
 
+	// The size and the duration of the last completed backup
+	LastBackupStatistics *BackupStatistics `json:"lastBackupStatistics,omitempty"`
+
 	// The commit hash number of which this operator running
 	CommitHash string `json:"cloudNativePGCommitHash,omitempty"`
 
@@ -432,6 +678,114 @@ type ClusterStatus struct {
 
 	// List of instance names in the cluster
 	InstanceNames []string `json:"instanceNames,omitempty"`
+
+	// The pg_hba.conf entries rendered from the `pg_hba_rules`, including
+	// the addresses taken from the referenced Kubernetes resources
+	// +optional
+	PgHBARules []string `json:"pgHBARules,omitempty"`
+
+	// The token written by the designated primary after the demotion of
+	// this cluster to a replica cluster, containing the information about
+	// the shutdown checkpoint of the former primary. It is meant to be used
+	// as the promotion token of the replica cluster taking its place
+	// +optional
+	DemotionToken string `json:"demotionToken,omitempty"`
+
+	// The last promotion token consumed by the promotion of this cluster
+	// +optional
+	LastPromotionToken string `json:"lastPromotionToken,omitempty"`
+
+	// The results of the storage benchmark run while bootstrapping
+	// each instance, indexed by instance name
+	// +optional
+	StorageBenchmarks map[string]StorageBenchmarkResult `json:"storageBenchmarks,omitempty"`
+
+	// The operations deferred until the next maintenance window
+	// +optional
+	PendingMaintenance *PendingMaintenanceStatus `json:"pendingMaintenance,omitempty"`
+
+	// The container image resolved from the image catalog
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The status of the plugins, as reported by the instances
+	// +optional
+	PluginStatus []PluginStatus `json:"pluginStatus,omitempty"`
+
+	// The declared logical replication slots which have been created on the
+	// primary instance. With the failover slots, they are not created again
+	// on a new primary where they have not been synchronized
+	// +optional
+	LogicalReplicationSlots []string `json:"logicalReplicationSlots,omitempty"`
+}
+
+// PluginConfiguration declares a plugin running as a sidecar container
+// of the instances, serving its gRPC services on a unix socket
+type PluginConfiguration struct {
+	// The name of the plugin, used to name its container and its socket
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The container image of the plugin
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// The environment variables of the plugin container
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Parameters passed to the plugin with every request
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// When true, the plugin is used to archive the WAL files of the
+	// cluster and to restore them. Only one plugin can be the WAL
+	// archiver, and it can't be used together with `barmanObjectStore`
+	// +optional
+	IsWALArchiver bool `json:"isWALArchiver,omitempty"`
+}
+
+// PluginStatus is the status of a plugin, as reported by the instances
+type PluginStatus struct {
+	// The name of the plugin
+	Name string `json:"name"`
+
+	// The version of the plugin
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The services implemented by the plugin
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// True when the plugin is ready on every instance
+	Ready bool `json:"ready"`
+
+	// The reason why the plugin is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImageCatalogRef refers to the image catalog providing the container
+// image, and to the PostgreSQL major version to be used
+type ImageCatalogRef struct {
+	// The ImageCatalog, in the namespace of the cluster, or the
+	// ClusterImageCatalog providing the image
+	corev1.TypedLocalObjectReference `json:",inline"`
+
+	// The PostgreSQL major version to be used
+	// +kubebuilder:validation:Minimum=10
+	Major int `json:"major"`
+}
+
+// PendingMaintenanceStatus contains the operations waiting for the next
+// maintenance window of the cluster
+type PendingMaintenanceStatus struct {
+	// The operations waiting for the next maintenance window
+	Operations []string `json:"operations,omitempty"`
+
+	// The beginning of the next maintenance window, in RFC3339 format
+	NextWindow string `json:"nextWindow,omitempty"`
 }
 
 // InstanceReportedState describes the last reported state of an instance during a reconciliation loop
@@ -440,6 +794,54 @@ type InstanceReportedState struct {
 	IsPrimary bool `json:"isPrimary"`
 	// indicates on which TimelineId the instance is
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// reports that the replica cannot follow the primary because their
+	// timelines diverged
+	// +optional
+	TimelineDivergence *TimelineDivergence `json:"timelineDivergence,omitempty"`
+	// reports the long-running transactions and the sessions blocked by
+	// locks detected in the instance
+	// +optional
+	LongRunningTransactions *LongRunningTransactionsState `json:"longRunningTransactions,omitempty"`
+}
+
+// TimelineDivergenceRemediation is the action suggested to fix a replica
+// whose timeline diverged from the one of the primary
+type TimelineDivergenceRemediation string
+
+const (
+	// TimelineDivergenceRemediationRewind means that the replica can be
+	// resynchronized with the primary using pg_rewind
+	TimelineDivergenceRemediationRewind TimelineDivergenceRemediation = "rewind"
+
+	// TimelineDivergenceRemediationReclone means that the replica needs to
+	// be cloned again from the primary
+	TimelineDivergenceRemediationReclone TimelineDivergenceRemediation = "reclone"
+)
+
+// TimelineDivergence describes why a replica cannot follow the timeline of
+// the primary
+type TimelineDivergence struct {
+	// The timeline of the replica
+	InstanceTimeline int `json:"instanceTimeline"`
+
+	// The timeline of the primary
+	PrimaryTimeline int `json:"primaryTimeline"`
+
+	// The position reached by the replica
+	// +optional
+	InstanceLSN string `json:"instanceLSN,omitempty"`
+
+	// The position where the primary abandoned the timeline of the replica,
+	// when the timeline of the replica is part of the history of the primary
+	// +optional
+	DivergencePoint string `json:"divergencePoint,omitempty"`
+
+	// The suggested remediation, `rewind` or `reclone`
+	Remediation TimelineDivergenceRemediation `json:"remediation"`
+
+	// A human-readable description of the divergence
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -454,6 +856,50 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionSplitBrain represents whether more than one instance has been
+	// detected running as a primary, pausing the reconciliation loop
+	ConditionSplitBrain ClusterConditionType = "SplitBrain"
+	// ConditionAnonymized represents whether the data recovered from a
+	// backup has been anonymized
+	ConditionAnonymized ClusterConditionType = "Anonymized"
+	// ConditionOptimizerStatistics represents whether the optimizer statistics
+	// of the databases imported during the bootstrap have been generated
+	ConditionOptimizerStatistics ClusterConditionType = "OptimizerStatistics"
+	// ConditionMonitoringQueries represents whether the monitoring queries
+	// executed by the primary instance are valid
+	ConditionMonitoringQueries ClusterConditionType = "MonitoringQueries"
+	// ConditionReplicationEncrypted represents whether every streaming
+	// replication connection to the primary instance is encrypted
+	ConditionReplicationEncrypted ClusterConditionType = "ReplicationEncrypted"
+	// ConditionBackupConsistency represents whether the last verification
+	// of the backups and of the WAL archive found them consistent
+	ConditionBackupConsistency ClusterConditionType = "BackupConsistency"
+	// ConditionClockSynchronized represents whether the clocks of the
+	// instances are synchronized with the one of the operator
+	ConditionClockSynchronized ClusterConditionType = "ClockSynchronized"
+	// ConditionHibernated represents whether every instance of the cluster
+	// has been shut down following a hibernation request
+	ConditionHibernated ClusterConditionType = "Hibernated"
+	// ConditionRewindAvailable represents whether the settings of the
+	// instances allow a former primary to be re-attached using pg_rewind
+	ConditionRewindAvailable ClusterConditionType = "RewindAvailable"
+	// ConditionLogicalDecoding represents whether the declared logical
+	// replication slots exist on the primary instance
+	ConditionLogicalDecoding ClusterConditionType = "LogicalDecoding"
+	// ConditionAdmissionPolicies represents whether the Pods and the Jobs
+	// of the cluster are admitted by the policy engines installed in the
+	// Kubernetes cluster, according to a dry-run of their creation
+	ConditionAdmissionPolicies ClusterConditionType = "AdmissionPolicies"
+	// ConditionStorageCapacity represents whether the volumes of the
+	// instances are expected to have free space for the configured number
+	// of days, at their current growth rate
+	ConditionStorageCapacity ClusterConditionType = "StorageCapacity"
+	// ConditionSynchronousCommit represents whether the default
+	// synchronous_commit settings of databases and roles have been applied
+	ConditionSynchronousCommit ClusterConditionType = "SynchronousCommit"
+	// ConditionPgHBARules represents whether the addresses of the
+	// `pg_hba_rules` have been read and rendered into pg_hba.conf entries
+	ConditionPgHBARules ClusterConditionType = "PgHBARules"
 )
 
 // ConditionStatus defines conditions of resources
@@ -494,11 +940,160 @@ const (
 	// the WAL archiving is not working correctly
 	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
 
+	// ConditionReasonContinuousArchivingPaused means that the condition
+	// changed because the WAL archiving has been paused by the user
+	ConditionReasonContinuousArchivingPaused ConditionReason = "ContinuousArchivingPaused"
+
 	// ClusterReady means that the condition changed because the cluster is ready and working properly
 	ClusterReady ConditionReason = "ClusterIsReady"
 
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// ConditionReasonSplitBrainDetected means that the condition changed because
+	// more than one instance is running as a primary
+	ConditionReasonSplitBrainDetected ConditionReason = "SplitBrainDetected"
+
+	// ConditionReasonSplitBrainAcknowledged means that the condition changed
+	// because the user acknowledged the split-brain
+	ConditionReasonSplitBrainAcknowledged ConditionReason = "SplitBrainAcknowledged"
+
+	// ConditionReasonInvalidImage means that the condition changed because
+	// the image of an instance cannot be pulled or is not valid
+	ConditionReasonInvalidImage ConditionReason = "InvalidImage"
+
+	// ConditionReasonPVCProvisioningFailed means that the condition changed
+	// because a PVC of an instance cannot be created
+	ConditionReasonPVCProvisioningFailed ConditionReason = "PVCProvisioningFailed"
+
+	// ConditionReasonNoActiveInstances means that the condition changed because
+	// no instance of the cluster is active
+	ConditionReasonNoActiveInstances ConditionReason = "NoActiveInstances"
+
+	// ConditionReasonArchiveUnreachable means that the condition has changed
+	// because the object store used for WAL archiving cannot be reached
+	ConditionReasonArchiveUnreachable ConditionReason = "ArchiveUnreachable"
+
+	// ConditionReasonWebhookCertMissing means that the condition changed
+	// because the certificate of the operator webhooks is missing
+	ConditionReasonWebhookCertMissing ConditionReason = "WebhookCertMissing"
+
+	// ConditionReasonAnonymizationCompleted means that the condition changed
+	// because the recovered data has been anonymized
+	ConditionReasonAnonymizationCompleted ConditionReason = "AnonymizationCompleted"
+
+	// ConditionReasonAnonymizationFailed means that the condition changed
+	// because the anonymization of the recovered data failed
+	ConditionReasonAnonymizationFailed ConditionReason = "AnonymizationFailed"
+
+	// ConditionReasonOptimizerStatisticsGenerating means that the condition
+	// changed because the optimizer statistics are being generated
+	ConditionReasonOptimizerStatisticsGenerating ConditionReason = "OptimizerStatisticsGenerating"
+
+	// ConditionReasonOptimizerStatisticsGenerated means that the condition
+	// changed because the optimizer statistics have been generated
+	ConditionReasonOptimizerStatisticsGenerated ConditionReason = "OptimizerStatisticsGenerated"
+
+	// ConditionReasonOptimizerStatisticsFailed means that the condition
+	// changed because the optimizer statistics cannot be generated
+	ConditionReasonOptimizerStatisticsFailed ConditionReason = "OptimizerStatisticsFailed"
+
+	// ConditionReasonMonitoringQueriesValid means that the condition changed
+	// because every monitoring query has been successfully validated
+	ConditionReasonMonitoringQueriesValid ConditionReason = "MonitoringQueriesValid"
+
+	// ConditionReasonMonitoringQueriesInvalid means that the condition changed
+	// because some monitoring queries cannot be loaded or executed
+	ConditionReasonMonitoringQueriesInvalid ConditionReason = "MonitoringQueriesInvalid"
+
+	// ConditionReasonReplicationEncrypted means that the condition changed
+	// because every streaming replication connection is encrypted
+	ConditionReasonReplicationEncrypted ConditionReason = "ReplicationEncrypted"
+
+	// ConditionReasonReplicationUnencrypted means that the condition changed
+	// because some streaming replication connections are not encrypted
+	ConditionReasonReplicationUnencrypted ConditionReason = "ReplicationUnencrypted"
+
+	// ConditionReasonBackupsConsistent means that the condition changed
+	// because the verification found every backup and WAL file
+	ConditionReasonBackupsConsistent ConditionReason = "BackupsConsistent"
+
+	// ConditionReasonBackupsInconsistent means that the condition changed
+	// because the verification found incomplete backups or missing WAL files
+	ConditionReasonBackupsInconsistent ConditionReason = "BackupsInconsistent"
+
+	// ConditionReasonBackupVerificationFailed means that the condition
+	// changed because the verification could not be completed
+	ConditionReasonBackupVerificationFailed ConditionReason = "BackupVerificationFailed"
+
+	// ConditionReasonClockSynchronized means that the condition changed
+	// because the clocks of every instance are synchronized
+	ConditionReasonClockSynchronized ConditionReason = "ClockSynchronized"
+
+	// ConditionReasonClockSkewDetected means that the condition changed
+	// because the clock of some instances is skewed
+	ConditionReasonClockSkewDetected ConditionReason = "ClockSkewDetected"
+
+	// ConditionReasonHibernationInProgress means that the condition changed
+	// because the instances are being shut down
+	ConditionReasonHibernationInProgress ConditionReason = "HibernationInProgress"
+
+	// ConditionReasonHibernated means that the condition changed because
+	// every instance has been shut down
+	ConditionReasonHibernated ConditionReason = "Hibernated"
+
+	// ConditionReasonRewindPrerequisitesMet means that the condition changed
+	// because the settings of every instance allow pg_rewind to be used
+	ConditionReasonRewindPrerequisitesMet ConditionReason = "RewindPrerequisitesMet"
+
+	// ConditionReasonRewindNotPossible means that the condition changed
+	// because the settings of some instances make pg_rewind fail
+	ConditionReasonRewindNotPossible ConditionReason = "RewindNotPossible"
+
+	// ConditionReasonLogicalReplicationSlotsReady means that the condition
+	// changed because every declared logical replication slot exists
+	ConditionReasonLogicalReplicationSlotsReady ConditionReason = "LogicalReplicationSlotsReady"
+
+	// ConditionReasonLogicalReplicationSlotsFailed means that the condition
+	// changed because some logical replication slots cannot be created,
+	// for example when their output plugin is not available
+	ConditionReasonLogicalReplicationSlotsFailed ConditionReason = "LogicalReplicationSlotsFailed"
+
+	// ConditionReasonResourcesAdmitted means that the condition changed
+	// because the dry-run of the creation of a child resource succeeded
+	ConditionReasonResourcesAdmitted ConditionReason = "ResourcesAdmitted"
+
+	// ConditionReasonResourceRejected means that the condition changed
+	// because an admission policy rejected the dry-run of the creation of
+	// a child resource
+	ConditionReasonResourceRejected ConditionReason = "ResourceRejected"
+
+	// ConditionReasonStorageCapacitySufficient means that the condition
+	// changed because no volume is expected to be full within the
+	// configured number of days
+	ConditionReasonStorageCapacitySufficient ConditionReason = "StorageCapacitySufficient"
+
+	// ConditionReasonVolumesFillingUp means that the condition changed
+	// because some volumes are expected to be full within the configured
+	// number of days, at their current growth rate
+	ConditionReasonVolumesFillingUp ConditionReason = "VolumesFillingUp"
+
+	// ConditionReasonSynchronousCommitApplied means that the condition
+	// changed because every synchronous_commit setting has been applied
+	ConditionReasonSynchronousCommitApplied ConditionReason = "SynchronousCommitApplied"
+
+	// ConditionReasonSynchronousCommitFailed means that the condition
+	// changed because some synchronous_commit settings cannot be applied
+	ConditionReasonSynchronousCommitFailed ConditionReason = "SynchronousCommitFailed"
+
+	// ConditionReasonPgHBARulesApplied means that the condition changed
+	// because every `pg_hba_rules` rule has been rendered
+	ConditionReasonPgHBARulesApplied ConditionReason = "PgHBARulesApplied"
+
+	// ConditionReasonPgHBARulesInvalid means that the condition changed
+	// because the addresses of a `pg_hba_rules` rule cannot be read, and
+	// the previously rendered entries are kept
+	ConditionReasonPgHBARulesInvalid ConditionReason = "PgHBARulesInvalid"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -530,14 +1125,79 @@ type ReplicaClusterConfiguration struct {
 	// The name of the external cluster which is the replication origin
 	// +kubebuilder:validation:MinLength=1
 	Source string `json:"source"`
+
+	// The demotion token written in the status of the former primary
+	// cluster. When set while disabling the replica mode, the
+	// designated primary is promoted only after having replayed the WALs
+	// up to the shutdown checkpoint of the former primary
+	// +optional
+	PromotionToken string `json:"promotionToken,omitempty"`
 }
 
 // DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
 const DefaultReplicationSlotsUpdateInterval = 30
 
+// RestrictedReplicasConfiguration contains the configuration of the
+// replicas that are never promoted to primary
+type RestrictedReplicasConfiguration struct {
+	// The ordinals of the restricted instances, i.e. `3` for the instance
+	// named `cluster-example-3`
+	// +kubebuilder:validation:MinItems=1
+	Instances []int `json:"instances"`
+
+	// PostgreSQL configuration options (postgresql.conf) applied to the
+	// restricted instances only, overriding the ones of the cluster
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// PromotionPriority sets the priority of an instance when a new primary
+// is elected
+type PromotionPriority struct {
+	// The ordinal of the instance, i.e. `2` for the instance named
+	// `cluster-example-2`
+	// +kubebuilder:validation:Minimum=1
+	Instance int `json:"instance"`
+
+	// The priority of the instance, the highest one being preferred
+	Priority int32 `json:"priority"`
+}
+
 // DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
 const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
 
+// ReplicationTLSMode defines how the replicas and the poolers verify
+// the TLS connection to the primary
+type ReplicationTLSMode string
+
+const (
+	// ReplicationTLSModeVerifyCA requires an encrypted connection and
+	// verifies the server certificate against the server CA
+	ReplicationTLSModeVerifyCA ReplicationTLSMode = "verify-ca"
+
+	// ReplicationTLSModeVerifyFull requires an encrypted connection and
+	// verifies both the server certificate and the server host name
+	ReplicationTLSModeVerifyFull ReplicationTLSMode = "verify-full"
+
+	// ReplicationTLSModeAllowFallback uses an encrypted connection when
+	// the server supports it, falling back to an unencrypted one otherwise.
+	// The server certificate is not verified
+	ReplicationTLSModeAllowFallback ReplicationTLSMode = "allow-fallback"
+)
+
+// ReplicationConfiguration encapsulates the configuration of the
+// streaming replication connections
+type ReplicationConfiguration struct {
+	// How the replicas and the poolers verify the TLS connection to the
+	// primary: `verify-ca` (default) checks the server certificate,
+	// `verify-full` also checks the host name, while `allow-fallback`
+	// accepts unencrypted connections
+	// +kubebuilder:validation:Enum:=verify-ca;verify-full;allow-fallback
+	// +kubebuilder:default:=verify-ca
+	// +optional
+	TLSMode ReplicationTLSMode `json:"tlsMode,omitempty"`
+}
+
 // ReplicationSlotsConfiguration encapsulates the configuration
 // of replication slots
 type ReplicationSlotsConfiguration struct {
@@ -646,6 +1306,73 @@ type NodeMaintenanceWindow struct {
 	ReusePVC *bool `json:"reusePVC"`
 }
 
+// MaintenanceWindow is a recurring window of time in which the operator
+// is allowed to execute the disruptive operations that are not urgent
+type MaintenanceWindow struct {
+	// The beginning of the window, in the standard cron format with five
+	// fields: minute, hour, day of month, month and day of week
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The duration of the window
+	Duration metav1.Duration `json:"duration"`
+
+	// The IANA name of the time zone the schedule is expressed in,
+	// defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// getSchedule parses the schedule of the window, in its time zone
+func (window MaintenanceWindow) getSchedule() (cron.Schedule, *time.Location, error) {
+	location := time.UTC
+	if window.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schedule, location, nil
+}
+
+// GetCurrentOrNextStart returns the beginning of the window containing
+// the passed time, or the beginning of the next one and false if the
+// passed time is outside the window
+func (window MaintenanceWindow) GetCurrentOrNextStart(now time.Time) (time.Time, bool, error) {
+	schedule, location, err := window.getSchedule()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// The first window beginning after the passed time, minus the duration,
+	// contains the passed time unless it starts later
+	start := schedule.Next(now.In(location).Add(-window.Duration.Duration))
+	if start.IsZero() {
+		return time.Time{}, false, fmt.Errorf("the schedule %q never starts", window.Schedule)
+	}
+	return start, !start.After(now), nil
+}
+
+// ReattachStrategy contains the strategy to follow when re-attaching a
+// former primary to the cluster
+type ReattachStrategy string
+
+const (
+	// ReattachStrategyRewind means that the former primary is aligned with
+	// the new one using pg_rewind (`rewind`, default)
+	ReattachStrategyRewind ReattachStrategy = "rewind"
+
+	// ReattachStrategyReclone means that the data of the former primary is
+	// discarded and cloned again from the new primary (`reclone`)
+	ReattachStrategyReclone ReattachStrategy = "reclone"
+)
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -681,6 +1408,19 @@ const (
 	// is gracefully shutdown during a switchover.
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultMaxSwitchoverDelay = 40000000
+
+	// DefaultMaxClockSkew is the default maximum difference in seconds
+	// between the clock of an instance and the one of the operator
+	DefaultMaxClockSkew = 5
+
+	// DefaultConnectionDrainingGracePeriod is the default time in seconds
+	// active client sessions are given to complete before being terminated
+	// when the client connections are drained during a switchover
+	DefaultConnectionDrainingGracePeriod = 30
+
+	// DefaultConnectionsUsageWarningThreshold is the default percentage
+	// of the available connection slots over which a warning is raised
+	DefaultConnectionsUsageWarningThreshold = 80
 )
 
 // PostgresConfiguration defines the PostgreSQL configuration
@@ -693,10 +1433,54 @@ type PostgresConfiguration struct {
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL Host Based Authentication rules, validated by the operator
+	// and rendered into pg_hba.conf entries, which are appended after the
+	// `pg_hba` ones
+	// +optional
+	PgHBARules []PgHBARule `json:"pg_hba_rules,omitempty"`
+
+	// PostgreSQL user name maps (pg_ident.conf), which can be used through
+	// the `map` option of the `pg_hba_rules`
+	// +optional
+	PgIdent []PgIdentMap `json:"pg_ident,omitempty"`
+
+	// The management of the server-side connection limits. The values set
+	// here are applied to the `max_connections` and
+	// `superuser_reserved_connections` parameters, that cannot be set in
+	// `parameters` at the same time
+	// +optional
+	Connections *ConnectionsConfiguration `json:"connections,omitempty"`
+
+	// The tuning of the WAL replay performed by the replicas and during
+	// the recovery from a backup. The values set here cannot be set in
+	// `parameters` at the same time
+	// +optional
+	RecoveryTuning *RecoveryTuningConfiguration `json:"recoveryTuning,omitempty"`
+
 	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
 	// set up.
 	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
 
+	// Configuration of the synchronous replication, replacing the
+	// `minSyncReplicas` and `maxSyncReplicas` fields, which cannot be used
+	// together with it
+	// +optional
+	Synchronous *SynchronousReplicaConfiguration `json:"synchronous,omitempty"`
+
+	// The default `synchronous_commit` setting of databases and roles,
+	// managed declaratively. When this section is present, the operator owns
+	// every `synchronous_commit` default set by `ALTER DATABASE` and
+	// `ALTER ROLE`, resetting the ones that are not listed
+	// +optional
+	SynchronousCommit *SynchronousCommitConfiguration `json:"synchronousCommit,omitempty"`
+
+	// The configuration parameters and the default privileges of
+	// databases, managed declaratively. The operator owns the settings set
+	// by `ALTER DATABASE` on the listed databases, resetting the ones that
+	// are not included in `parameters`
+	// +optional
+	DatabaseDefaults []DatabaseDefaultsConfiguration `json:"databaseDefaults,omitempty"`
+
 	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
 	// Default value is 40000000, greater than one year in seconds,
 	// big enough to simulate an infinite timeout
@@ -707,53 +1491,810 @@ type PostgresConfiguration struct {
 	// +optional
 	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
 
+	// Extensions delivered by an image or by a volume, made available to
+	// PostgreSQL without rebuilding the operand image. The libraries are
+	// loaded through the `dynamic_library_path` parameter, that cannot be
+	// set in `parameters` at the same time
+	// +optional
+	Extensions []ExtensionConfiguration `json:"extensions,omitempty"`
+
 	// Options to specify LDAP configuration
 	// +optional
 	LDAP *LDAPConfig `json:"ldap,omitempty"`
+
+	// Relations to be loaded in the shared buffers of a newly promoted
+	// primary, so that read latencies recover faster after a failover
+	// or a switchover
+	// +optional
+	Prewarm *PrewarmConfiguration `json:"prewarm,omitempty"`
+
+	// The logical decoding configuration, such as the logical replication
+	// slots used by change data capture tools
+	// +optional
+	LogicalDecoding *LogicalDecodingConfiguration `json:"logicalDecoding,omitempty"`
 }
 
-// BootstrapConfiguration contains information about how to create the PostgreSQL
-// cluster. Only a single bootstrap method can be defined among the supported
-// ones. `initdb` will be used as the bootstrap method if left
-// unspecified. Refer to the Bootstrap page of the documentation for more
-// information.
-type BootstrapConfiguration struct {
-	// Bootstrap the cluster via initdb
-	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+// ConnectionDrainingConfiguration controls how the client connections are
+// drained from the primary instance before demoting it during a switchover
+type ConnectionDrainingConfiguration struct {
+	// Whether client connections should be drained before the primary
+	// instance is demoted (default: `false`)
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 
-	// Bootstrap the cluster from a backup
-	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+	// The time in seconds active client sessions are given to complete
+	// before being terminated. Idle sessions are terminated immediately
+	// (default: `30`)
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	GracePeriod int32 `json:"gracePeriod,omitempty"`
 
-	// Bootstrap the cluster taking a physical backup of another compatible
-	// PostgreSQL instance
-	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+	// Whether the PgBouncer poolers pointing to this cluster should be
+	// paused while the switchover is in progress (default: `false`)
+	// +optional
+	PausePoolers bool `json:"pausePoolers,omitempty"`
 }
 
-// LDAPScheme defines the possible schemes for LDAP
-type LDAPScheme string
+// InstanceHookEvent is an event of the life of an instance triggering
+// the execution of the instance hooks
+type InstanceHookEvent string
 
-// These are the valid LDAP schemes
 const (
-	LDAPSchemeLDAP  LDAPScheme = "ldap"
-	LDAPSchemeLDAPS LDAPScheme = "ldaps"
-)
+	// InstanceHookEventPrePromote is the event raised before a replica
+	// is promoted to primary
+	InstanceHookEventPrePromote InstanceHookEvent = "prePromote"
 
-// LDAPConfig contains the parameters needed for LDAP authentication
-type LDAPConfig struct {
-	// LDAP hostname or IP address
-	Server string `json:"server,omitempty"`
-	// LDAP server port
-	Port int `json:"port,omitempty"`
+	// InstanceHookEventPostPromote is the event raised after a replica
+	// has been promoted to primary
+	InstanceHookEventPostPromote InstanceHookEvent = "postPromote"
 
-	// LDAP schema to be used, possible options are `ldap` and `ldaps`
-	// +kubebuilder:validation:Enum=ldap;ldaps
-	Scheme LDAPScheme `json:"scheme,omitempty"`
+	// InstanceHookEventPreDemote is the event raised before a primary
+	// is shut down to be demoted to replica
+	InstanceHookEventPreDemote InstanceHookEvent = "preDemote"
 
-	// Set to 'true' to enable LDAP over TLS. 'false' is default
-	TLS bool `json:"tls,omitempty"`
+	// InstanceHookEventPreShutdown is the event raised before the instance
+	// is shut down because the Pod is being terminated
+	InstanceHookEventPreShutdown InstanceHookEvent = "preShutdown"
+)
 
-	// Bind as authentication configuration
-	BindAsAuth *LDAPBindAsAuth `json:"bindAsAuth,omitempty"`
+// DefaultInstanceHookTimeout is the default timeout, in seconds, of an
+// instance hook
+const DefaultInstanceHookTimeout = 10
+
+// InstanceHooksMountPath is the directory where the keys of the instance
+// hooks ConfigMap are mounted
+const InstanceHooksMountPath = "/hooks"
+
+// InstanceHooksConfiguration contains the hooks executed by the instance
+// manager, inside the PostgreSQL container, when the role or the state of
+// the instance changes. The failure of a hook is logged and never stops
+// the operation that triggered it
+type InstanceHooksConfiguration struct {
+	// The ConfigMap containing the scripts of the hooks. Every key is
+	// mounted as an executable file in the `/hooks` directory
+	// +optional
+	ScriptsConfigMap *LocalObjectReference `json:"scriptsConfigMap,omitempty"`
+
+	// Hooks executed, in order, before a replica is promoted to primary
+	// +optional
+	PrePromote []InstanceHook `json:"prePromote,omitempty"`
+
+	// Hooks executed, in order, after a replica has been promoted to primary
+	// +optional
+	PostPromote []InstanceHook `json:"postPromote,omitempty"`
+
+	// Hooks executed, in order, before the former primary is shut down
+	// to be demoted to replica
+	// +optional
+	PreDemote []InstanceHook `json:"preDemote,omitempty"`
+
+	// Hooks executed, in order, before the instance is shut down because
+	// its Pod is being terminated. Their duration is subtracted from the
+	// time available for the shutdown
+	// +optional
+	PreShutdown []InstanceHook `json:"preShutdown,omitempty"`
+}
+
+// GetHooks returns the hooks to be executed for the passed event
+func (configuration *InstanceHooksConfiguration) GetHooks(event InstanceHookEvent) []InstanceHook {
+	if configuration == nil {
+		return nil
+	}
+
+	switch event {
+	case InstanceHookEventPrePromote:
+		return configuration.PrePromote
+	case InstanceHookEventPostPromote:
+		return configuration.PostPromote
+	case InstanceHookEventPreDemote:
+		return configuration.PreDemote
+	case InstanceHookEventPreShutdown:
+		return configuration.PreShutdown
+	default:
+		return nil
+	}
+}
+
+// InstanceHook is a command executed by the instance manager when an
+// instance event happens. The event, the cluster, the namespace and the
+// Pod are passed in the `CNPG_HOOK_EVENT`, `CNPG_CLUSTER_NAME`,
+// `CNPG_NAMESPACE` and `CNPG_POD_NAME` environment variables
+type InstanceHook struct {
+	// The name of the hook, used in the logs
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The command to be executed, with its arguments. Scripts
+	// taken from `scriptsConfigMap` are in the `/hooks` directory
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// The maximum number of seconds the hook is allowed to run,
+	// defaults to 10
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the maximum duration of the hook
+func (hook InstanceHook) GetTimeout() time.Duration {
+	if hook.Timeout < 1 {
+		return DefaultInstanceHookTimeout * time.Second
+	}
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// ShutdownCheckpointConfiguration controls the checkpoint requested on an
+// instance before a planned shutdown, while it is still serving its
+// clients, so that the shutdown checkpoint has little data left to write
+type ShutdownCheckpointConfiguration struct {
+	// Whether a checkpoint should be requested before the planned
+	// restarts and switchovers (default: `false`)
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The value of `max_wal_size` applied to the instance from the
+	// checkpoint until its shutdown, to avoid the WAL written meanwhile
+	// triggering another checkpoint (i.e. '4GB'). The configured value
+	// is restored when the instance is started again
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*(kB|MB|GB|TB)$
+	// +optional
+	MaxWalSize string `json:"maxWalSize,omitempty"`
+}
+
+// SwitchoverGuardrailPolicy is the action taken on the prepared
+// transactions and on the logical replication workers found on the primary
+// instance before it gets demoted during a switchover
+type SwitchoverGuardrailPolicy string
+
+const (
+	// SwitchoverGuardrailPolicyReport logs the prepared transactions and
+	// the logical replication workers, and goes on with the switchover
+	SwitchoverGuardrailPolicyReport SwitchoverGuardrailPolicy = "report"
+
+	// SwitchoverGuardrailPolicyAbort rolls back the prepared transactions
+	// and terminates the logical replication workers before going on with
+	// the switchover
+	SwitchoverGuardrailPolicyAbort SwitchoverGuardrailPolicy = "abort"
+)
+
+// SwitchoverGuardrailConfiguration controls how the primary instance
+// handles, before being demoted during a switchover, the prepared
+// transactions left open and the sessions applying changes through a
+// replication origin
+type SwitchoverGuardrailConfiguration struct {
+	// The action taken on the prepared transactions and on the logical
+	// replication workers: `report` (default) logs them, while `abort`
+	// rolls back the prepared transactions and terminates the workers
+	// +kubebuilder:validation:Enum=report;abort
+	// +kubebuilder:default:=report
+	// +optional
+	Policy SwitchoverGuardrailPolicy `json:"policy,omitempty"`
+}
+
+// PgUpgradeMethod is the way pg_upgrade transfers the data files to
+// the new data directory
+type PgUpgradeMethod string
+
+const (
+	// PgUpgradeMethodLink means that the data files are hard linked
+	// to the new data directory
+	PgUpgradeMethodLink PgUpgradeMethod = "link"
+
+	// PgUpgradeMethodCopy means that the data files are copied to the
+	// new data directory
+	PgUpgradeMethodCopy PgUpgradeMethod = "copy"
+)
+
+// MajorVersionUpgradeConfiguration configures how the data of a cluster
+// is upgraded to a new PostgreSQL major version
+type MajorVersionUpgradeConfiguration struct {
+	// The way `pg_upgrade` transfers the data files: `link` (default)
+	// creates hard links and is almost instantaneous, `copy` needs twice
+	// the space on the volume but keeps the old data files untouched
+	// +kubebuilder:validation:Enum=link;copy
+	// +kubebuilder:default:=link
+	// +optional
+	Method PgUpgradeMethod `json:"method,omitempty"`
+}
+
+// MajorVersionUpgradePhase is the phase of a major version upgrade
+type MajorVersionUpgradePhase string
+
+const (
+	// MajorVersionUpgradePhaseRunning means that the upgrade job is running
+	// against the volume of the primary instance
+	MajorVersionUpgradePhaseRunning MajorVersionUpgradePhase = "Running"
+
+	// MajorVersionUpgradePhaseFailed means that the upgrade job failed and
+	// the instances have been restarted with the previous image
+	MajorVersionUpgradePhaseFailed MajorVersionUpgradePhase = "Failed"
+)
+
+// MajorVersionUpgradeStatus is the status of a major version upgrade
+type MajorVersionUpgradeStatus struct {
+	// The image the instances were running before the upgrade
+	SourceImage string `json:"sourceImage"`
+
+	// The image containing the new PostgreSQL major version
+	TargetImage string `json:"targetImage"`
+
+	// The instance whose data is being upgraded
+	Primary string `json:"primary"`
+
+	// The phase of the upgrade
+	Phase MajorVersionUpgradePhase `json:"phase"`
+
+	// A human-readable message explaining why the upgrade failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DefaultFailoverWitnessLeaseDuration is the default duration, in seconds,
+// of the lease held by the primary instance on the failover witness
+const DefaultFailoverWitnessLeaseDuration = 30
+
+// FailoverWitnessConfiguration configures the failover witness, a
+// `coordination.k8s.io/v1` Lease that the primary instance keeps renewing
+// and that prevents the operator from promoting a replica until it expires
+type FailoverWitnessConfiguration struct {
+	// The name of the Lease used as a witness
+	// +kubebuilder:validation:MinLength=1
+	LeaseName string `json:"leaseName"`
+
+	// The namespace containing the Lease. Can only be specified together
+	// with `kubeconfigSecret`, otherwise the namespace of the cluster is used
+	// +optional
+	LeaseNamespace string `json:"leaseNamespace,omitempty"`
+
+	// The secret containing the kubeconfig used to reach the Kubernetes
+	// cluster hosting the Lease, ideally running in a different failure
+	// domain. When not specified, the Lease is kept in the Kubernetes cluster
+	// where the instances are running
+	// +optional
+	KubeconfigSecret *SecretKeySelector `json:"kubeconfigSecret,omitempty"`
+
+	// The number of seconds after which the Lease, if not renewed by
+	// the primary instance, expires allowing a failover (default: `30`)
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	LeaseDuration int32 `json:"leaseDuration,omitempty"`
+}
+
+// GetLeaseNamespace returns the namespace of the witness Lease
+func (witness *FailoverWitnessConfiguration) GetLeaseNamespace(clusterNamespace string) string {
+	if witness.KubeconfigSecret == nil || witness.LeaseNamespace == "" {
+		return clusterNamespace
+	}
+	return witness.LeaseNamespace
+}
+
+// GetLeaseDuration returns the duration of the witness Lease
+func (witness *FailoverWitnessConfiguration) GetLeaseDuration() time.Duration {
+	if witness.LeaseDuration <= 0 {
+		return DefaultFailoverWitnessLeaseDuration * time.Second
+	}
+	return time.Duration(witness.LeaseDuration) * time.Second
+}
+
+const (
+	// DefaultIsolationCheckInterval is the default interval, in seconds,
+	// between two isolation checks of the primary instance
+	DefaultIsolationCheckInterval = 10
+
+	// DefaultIsolationCheckTimeout is the default time, in seconds, given to
+	// the Kubernetes API server to answer during an isolation check
+	DefaultIsolationCheckTimeout = 2
+)
+
+// IsolationCheckAction is the action taken by the primary instance when
+// it detects to be isolated
+type IsolationCheckAction string
+
+const (
+	// IsolationCheckActionShutdown means that an isolated primary
+	// instance is shut down
+	IsolationCheckActionShutdown IsolationCheckAction = "shutdown"
+
+	// IsolationCheckActionReadOnly means that an isolated primary instance
+	// only accepts read-only transactions until it's reachable again
+	IsolationCheckActionReadOnly IsolationCheckAction = "readOnly"
+)
+
+// IsolationCheckConfiguration configures how the primary instance detects
+// a network partition isolating it from the Kubernetes API server and from
+// its replicas
+type IsolationCheckConfiguration struct {
+	// Whether the isolation check is enabled (default: `false`)
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of seconds between two isolation checks (default: `10`)
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Interval int32 `json:"interval,omitempty"`
+
+	// The number of seconds after which the Kubernetes API server is
+	// considered unreachable (default: `2`)
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+
+	// The minimum number of replicas that must be streaming from the primary
+	// for it to be considered healthy while the Kubernetes API server is not
+	// reachable. Defaults to the number of replicas needed to form a majority
+	// together with the primary
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PeerQuorum *int32 `json:"peerQuorum,omitempty"`
+
+	// The action taken by an isolated primary instance, `shutdown` or
+	// `readOnly` (default: `shutdown`)
+	// +kubebuilder:validation:Enum:=shutdown;readOnly
+	// +kubebuilder:default:=shutdown
+	// +optional
+	Action IsolationCheckAction `json:"action,omitempty"`
+}
+
+// GetInterval returns the interval between two isolation checks
+func (check *IsolationCheckConfiguration) GetInterval() time.Duration {
+	if check.Interval <= 0 {
+		return DefaultIsolationCheckInterval * time.Second
+	}
+	return time.Duration(check.Interval) * time.Second
+}
+
+// GetTimeout returns the time given to the Kubernetes API server to answer
+func (check *IsolationCheckConfiguration) GetTimeout() time.Duration {
+	if check.Timeout <= 0 {
+		return DefaultIsolationCheckTimeout * time.Second
+	}
+	return time.Duration(check.Timeout) * time.Second
+}
+
+// GetAction returns the action taken by an isolated primary instance
+func (check *IsolationCheckConfiguration) GetAction() IsolationCheckAction {
+	if check.Action == "" {
+		return IsolationCheckActionShutdown
+	}
+	return check.Action
+}
+
+// PrewarmConfiguration contains the list of relations that the instance
+// manager loads into the shared buffers via `pg_prewarm` right after
+// the promotion of an instance
+type PrewarmConfiguration struct {
+	// The list of relations (tables or indexes) to be prewarmed
+	// +optional
+	Relations []PrewarmRelation `json:"relations,omitempty"`
+
+	// The maximum number of relations to be prewarmed concurrently
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxParallel int `json:"maxParallel,omitempty"`
+}
+
+// PrewarmRelation identifies a relation to be prewarmed
+type PrewarmRelation struct {
+	// The name of the database containing the relation
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// The name of the relation, optionally qualified with its schema
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// BootstrapConfiguration contains information about how to create the PostgreSQL
+// cluster. Only a single bootstrap method can be defined among the supported
+// ones. `initdb` will be used as the bootstrap method if left
+// unspecified. Refer to the Bootstrap page of the documentation for more
+// information.
+type BootstrapConfiguration struct {
+	// Bootstrap the cluster via initdb
+	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+
+	// Bootstrap the cluster from a backup
+	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+
+	// Bootstrap the cluster taking a physical backup of another compatible
+	// PostgreSQL instance
+	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+}
+
+// LDAPScheme defines the possible schemes for LDAP
+type LDAPScheme string
+
+// These are the valid LDAP schemes
+const (
+	LDAPSchemeLDAP  LDAPScheme = "ldap"
+	LDAPSchemeLDAPS LDAPScheme = "ldaps"
+)
+
+// ConnectionsConfiguration contains the server-side connection limits
+// of the PostgreSQL instances
+type ConnectionsConfiguration struct {
+	// The maximum number of concurrent connections to the PostgreSQL
+	// instances (`max_connections`). Growing it requires a restart of
+	// every instance, which the operator orchestrates starting from the
+	// replicas
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections *int32 `json:"maxConnections,omitempty"`
+
+	// The number of connection slots reserved for the superusers
+	// (`superuser_reserved_connections`). It must be lower than
+	// `maxConnections`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuperuserReservedConnections *int32 `json:"superuserReservedConnections,omitempty"`
+
+	// The percentage of the available connection slots over which the
+	// `cnpg_collector_connections_usage_warning` metric is raised.
+	// Default: 80
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	UsageWarningThreshold int32 `json:"usageWarningThreshold,omitempty"`
+}
+
+// RecoveryStorageProfile is the kind of storage hosting the PostgreSQL
+// data, used to choose the defaults of the WAL replay settings
+type RecoveryStorageProfile string
+
+const (
+	// RecoveryStorageProfileSSD is a local solid state storage
+	RecoveryStorageProfileSSD RecoveryStorageProfile = "ssd"
+
+	// RecoveryStorageProfileNetwork is a network attached storage
+	RecoveryStorageProfileNetwork RecoveryStorageProfile = "network"
+
+	// RecoveryStorageProfileHDD is a storage based on rotational disks
+	RecoveryStorageProfileHDD RecoveryStorageProfile = "hdd"
+)
+
+// RecoveryPrefetch is the value of the recovery_prefetch parameter
+type RecoveryPrefetch string
+
+const (
+	// RecoveryPrefetchOff disables the prefetching of the blocks
+	// referenced in the WAL
+	RecoveryPrefetchOff RecoveryPrefetch = "off"
+
+	// RecoveryPrefetchOn enables the prefetching of the blocks referenced
+	// in the WAL, failing when not supported by the platform
+	RecoveryPrefetchOn RecoveryPrefetch = "on"
+
+	// RecoveryPrefetchTry enables the prefetching of the blocks referenced
+	// in the WAL when supported by the platform
+	RecoveryPrefetchTry RecoveryPrefetch = "try"
+)
+
+// recoveryStorageProfile contains the defaults of a storage profile
+type recoveryStorageProfile struct {
+	prefetch                 RecoveryPrefetch
+	maintenanceIOConcurrency int
+}
+
+// recoveryStorageProfiles are the WAL replay defaults per storage
+// profile. Storages serving many concurrent requests benefit from a
+// higher number of prefetched blocks
+var recoveryStorageProfiles = map[RecoveryStorageProfile]recoveryStorageProfile{
+	RecoveryStorageProfileSSD:     {prefetch: RecoveryPrefetchTry, maintenanceIOConcurrency: 200},
+	RecoveryStorageProfileNetwork: {prefetch: RecoveryPrefetchTry, maintenanceIOConcurrency: 64},
+	RecoveryStorageProfileHDD:     {prefetch: RecoveryPrefetchTry, maintenanceIOConcurrency: 10},
+}
+
+// SynchronousCommitConfiguration contains the default `synchronous_commit`
+// settings of databases and roles, letting applications trade durability
+// for latency
+type SynchronousCommitConfiguration struct {
+	// The default settings, applied to the sessions matching both the
+	// database and the role. At least one of them must be specified
+	// +optional
+	Defaults []SynchronousCommitDefault `json:"defaults,omitempty"`
+}
+
+// SynchronousCommitDefault is the default `synchronous_commit` setting of
+// a database, of a role, or of a role in a database
+type SynchronousCommitDefault struct {
+	// The database the setting applies to, as in `ALTER DATABASE`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The role the setting applies to, as in `ALTER ROLE`
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// The value of `synchronous_commit`
+	// +kubebuilder:validation:Enum=on;off;local;remote_write;remote_apply
+	Value string `json:"value"`
+}
+
+// DatabaseDefaultsConfiguration contains the configuration parameters and
+// the default privileges of a database
+type DatabaseDefaultsConfiguration struct {
+	// The name of the database, which needs to exist
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The default values of the configuration parameters in the database,
+	// as in `ALTER DATABASE ... SET`, such as `search_path` and
+	// `statement_timeout`. The `synchronous_commit` parameter is managed
+	// by the `synchronousCommit` section
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The privileges granted on the objects that will be created in the
+	// database, as in `ALTER DEFAULT PRIVILEGES`. The privileges of the
+	// entries removed from this list are not revoked
+	// +optional
+	DefaultPrivileges []DefaultPrivilege `json:"defaultPrivileges,omitempty"`
+}
+
+// DefaultPrivilegeObjectType is the type of the objects a default
+// privilege applies to
+type DefaultPrivilegeObjectType string
+
+const (
+	// DefaultPrivilegeObjectTypeTables applies to tables and views
+	DefaultPrivilegeObjectTypeTables DefaultPrivilegeObjectType = "tables"
+
+	// DefaultPrivilegeObjectTypeSequences applies to sequences
+	DefaultPrivilegeObjectTypeSequences DefaultPrivilegeObjectType = "sequences"
+
+	// DefaultPrivilegeObjectTypeFunctions applies to functions and procedures
+	DefaultPrivilegeObjectTypeFunctions DefaultPrivilegeObjectType = "functions"
+
+	// DefaultPrivilegeObjectTypeTypes applies to types and domains
+	DefaultPrivilegeObjectTypeTypes DefaultPrivilegeObjectType = "types"
+
+	// DefaultPrivilegeObjectTypeSchemas applies to schemas
+	DefaultPrivilegeObjectTypeSchemas DefaultPrivilegeObjectType = "schemas"
+)
+
+// DefaultPrivilege is a set of privileges a role is granted on the
+// objects of a type that will be created by another role
+type DefaultPrivilege struct {
+	// The role creating the objects. The owner of the database is used
+	// when not specified
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// The schema the objects are created in. The privileges apply to the
+	// objects created in every schema when not specified
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The type of the objects
+	// +kubebuilder:validation:Enum=tables;sequences;functions;types;schemas
+	ObjectType DefaultPrivilegeObjectType `json:"objectType"`
+
+	// The privileges to grant, such as `SELECT` or `USAGE`, or `ALL`
+	// +kubebuilder:validation:MinItems=1
+	Privileges []string `json:"privileges"`
+
+	// The role the privileges are granted to, or `PUBLIC`
+	// +kubebuilder:validation:MinLength=1
+	Grantee string `json:"grantee"`
+}
+
+// defaultPrivilegesByObjectType are the privileges that can be granted
+// on each type of object
+var defaultPrivilegesByObjectType = map[DefaultPrivilegeObjectType][]string{
+	DefaultPrivilegeObjectTypeTables: {
+		"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER",
+	},
+	DefaultPrivilegeObjectTypeSequences: {"USAGE", "SELECT", "UPDATE"},
+	DefaultPrivilegeObjectTypeFunctions: {"EXECUTE"},
+	DefaultPrivilegeObjectTypeTypes:     {"USAGE"},
+	DefaultPrivilegeObjectTypeSchemas:   {"USAGE", "CREATE"},
+}
+
+// GetPrivileges returns the privileges of the default privilege, in upper
+// case, with `ALL` expanded to every privilege of the object type
+func (privilege DefaultPrivilege) GetPrivileges() []string {
+	result := make([]string, 0, len(privilege.Privileges))
+	for _, name := range privilege.Privileges {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "ALL" || name == "ALL PRIVILEGES" {
+			return defaultPrivilegesByObjectType[privilege.ObjectType]
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// RecoveryTuningConfiguration contains the settings of the WAL replay
+// performed by the replicas and during the recovery from a backup
+type RecoveryTuningConfiguration struct {
+	// The kind of storage hosting the PostgreSQL data (`ssd`, `network`
+	// or `hdd`), used to choose the defaults of `recovery_prefetch` and
+	// `maintenance_io_concurrency`
+	// +kubebuilder:validation:Enum:=ssd;network;hdd
+	// +optional
+	StorageProfile RecoveryStorageProfile `json:"storageProfile,omitempty"`
+
+	// Whether to prefetch the blocks referenced in the WAL that are not
+	// yet in the buffer pool (`recovery_prefetch`). Requires PostgreSQL 15+
+	// +kubebuilder:validation:Enum:=off;on;try
+	// +optional
+	Prefetch RecoveryPrefetch `json:"prefetch,omitempty"`
+
+	// The number of concurrent I/O requests used to prefetch the blocks
+	// referenced in the WAL (`maintenance_io_concurrency`).
+	// Requires PostgreSQL 13+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaintenanceIOConcurrency *int32 `json:"maintenanceIOConcurrency,omitempty"`
+
+	// The maximum number of parallel workers (`max_parallel_workers`),
+	// available to the queries running on the replicas while they are
+	// replaying the WAL
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1024
+	// +optional
+	MaxParallelWorkers *int32 `json:"maxParallelWorkers,omitempty"`
+}
+
+// ExtensionConfiguration is an extension delivered by an image or by a volume.
+// The files of the extension are expected in two directories: `lib`, containing
+// the shared libraries, and `share`, containing the control and the SQL files
+type ExtensionConfiguration struct {
+	// The name of the extension, used to name the volume containing it
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength:=50
+	Name string `json:"name"`
+
+	// The image containing the extension files in the `/extension`
+	// directory. The files are copied into the Pods by an init container
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The volume containing the extension files, like a volume
+	// populated by a sidecar or provided by a CSI driver
+	// +optional
+	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty"`
+}
+
+// PgHBARule is a Host Based Authentication rule, rendered into a
+// pg_hba.conf entry for each of its addresses
+type PgHBARule struct {
+	// The connection type matched by the rule, one of `host`, `hostssl`,
+	// `hostnossl`, `hostgssenc` and `hostnogssenc`
+	// +kubebuilder:default:=host
+	// +kubebuilder:validation:Enum:=host;hostssl;hostnossl;hostgssenc;hostnogssenc
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// The databases matched by the rule, defaults to `all`
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// The users matched by the rule, defaults to `all`
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// The client address matched by the rule, as a CIDR, a host name or one
+	// of `all`, `samehost` and `samenet`. Cannot be set together with
+	// `addressesFrom`
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// The Kubernetes resources the client addresses are taken from, as
+	// an alternative to `address`
+	// +optional
+	AddressesFrom *PgHBAAddressesSource `json:"addressesFrom,omitempty"`
+
+	// The authentication method, as in the `auth-method` field
+	// of pg_hba.conf
+	// +kubebuilder:validation:Enum:=trust;reject;scram-sha-256;md5;password;gss;sspi;ident;ldap;radius;cert;pam;bsd
+	Method string `json:"method"`
+
+	// The options of the authentication method, like `clientcert` or `map`
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// PgHBAAddressesSource contains the Kubernetes resources the client addresses
+// of a `pg_hba_rules` rule are taken from. The addresses of all of them are
+// matched by the rule
+type PgHBAAddressesSource struct {
+	// The key of a ConfigMap, in the namespace of the cluster, containing
+	// a list of CIDRs, one for each line. Empty lines and lines starting
+	// with `#` are ignored
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// The running Pods in the namespaces matching this selector. When not
+	// set and `podSelector` is set, the namespace of the cluster is used
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Restricts the Pods to the ones matching this selector
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// The ready endpoint addresses of these Services, in the namespace
+	// of the cluster
+	// +optional
+	Services []string `json:"services,omitempty"`
+
+	// The `ipBlock` CIDRs of the ingress rules of these NetworkPolicies,
+	// in the namespace of the cluster. The `except` CIDRs are rendered as
+	// `reject` entries placed before the others
+	// +optional
+	NetworkPolicies []string `json:"networkPolicies,omitempty"`
+}
+
+// PgIdentMap is an entry of a PostgreSQL user name map
+type PgIdentMap struct {
+	// The name of the map. The `local` map is reserved to the operator
+	MapName string `json:"mapName"`
+
+	// The user name of the operating system, or a regular expression if
+	// it starts with a slash
+	SystemUsername string `json:"systemUsername"`
+
+	// The PostgreSQL user the system user can connect as
+	DatabaseUsername string `json:"databaseUsername"`
+}
+
+// HasPodReferences checks whether the addresses of Pods are matched
+func (source *PgHBAAddressesSource) HasPodReferences() bool {
+	return source.NamespaceSelector != nil || source.PodSelector != nil
+}
+
+// HasResourceReferences checks whether the addresses are taken from
+// Pods, Services or NetworkPolicies, whose changes are not watched
+func (source *PgHBAAddressesSource) HasResourceReferences() bool {
+	return source.HasPodReferences() || len(source.Services) > 0 || len(source.NetworkPolicies) > 0
+}
+
+// LDAPConfig contains the parameters needed for LDAP authentication
+type LDAPConfig struct {
+	// LDAP hostname or IP address
+	Server string `json:"server,omitempty"`
+	// LDAP server port
+	Port int `json:"port,omitempty"`
+
+	// LDAP schema to be used, possible options are `ldap` and `ldaps`
+	// +kubebuilder:validation:Enum=ldap;ldaps
+	Scheme LDAPScheme `json:"scheme,omitempty"`
+
+	// Set to 'true' to enable LDAP over TLS. 'false' is default
+	TLS bool `json:"tls,omitempty"`
+
+	// Bind as authentication configuration
+	BindAsAuth *LDAPBindAsAuth `json:"bindAsAuth,omitempty"`
 
 	// Bind+Search authentication configuration
 	BindSearchAuth *LDAPBindSearchAuth `json:"bindSearchAuth,omitempty"`
@@ -820,8 +2361,41 @@ type CertificatesConfiguration struct {
 	// this can be omitted.<br />
 	ClientCASecret string `json:"clientCASecret,omitempty"`
 
-	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
-	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
+	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// Delegates the issuance and the rotation of the server TLS certificate
+	// and of the `streaming_replica` client certificate to cert-manager,
+	// instead of using the CA generated by the operator. It cannot be used
+	// together with the secrets above
+	// +optional
+	CertManager *CertManagerConfiguration `json:"certManager,omitempty"`
+}
+
+// CertManagerConfiguration contains the configuration of the cert-manager
+// Certificates created by the operator
+type CertManagerConfiguration struct {
+	// The Issuer or ClusterIssuer signing the certificates. The secrets
+	// written by the issuer must contain the `ca.crt` key
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// Name of the issuer
+	Name string `json:"name"`
+
+	// Kind of the issuer, `Issuer` or `ClusterIssuer`. Defaults to `Issuer`
+	// +kubebuilder:validation:Enum:=Issuer;ClusterIssuer
+	// +kubebuilder:default:=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer. Defaults to `cert-manager.io`, and can be changed
+	// to use an external issuer
+	// +kubebuilder:default:=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
 }
 
 // CertificatesStatus contains configuration certificates and related expiration dates.
@@ -877,6 +2451,18 @@ type BootstrapInitDB struct {
 	// +kubebuilder:validation:Maximum=1024
 	WalSegmentSize int `json:"walSegmentSize,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb,
+	// one of `libc` or `icu`. Requires PostgreSQL 15 or above
+	// (default: empty, resulting in PostgreSQL default: `libc`)
+	// +kubebuilder:validation:Enum=libc;icu
+	// +optional
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb. Required
+	// when `localeProvider` is set to `icu`, forbidden otherwise
+	// +optional
+	ICULocale string `json:"icuLocale,omitempty"`
+
 	// List of SQL queries to be executed as a superuser immediately
 	// after the cluster has been created - to be used with extreme care
 	// (by default empty)
@@ -990,6 +2576,38 @@ type BootstrapRecovery struct {
 	// created from scratch
 	// +optional
 	Secret *LocalObjectReference `json:"secret,omitempty"`
+
+	// The anonymization of the recovered data, executed before the cluster
+	// starts accepting connections. It is meant for the copies of a
+	// production cluster used for development, testing and analytics
+	// +optional
+	Anonymization *RecoveryAnonymization `json:"anonymization,omitempty"`
+}
+
+// RecoveryAnonymization contains the configuration of the anonymization
+// of the data recovered from a backup
+type RecoveryAnonymization struct {
+	// Enables the anonymization of the recovered data. The cluster is not
+	// started if the anonymization fails
+	Enabled bool `json:"enabled"`
+
+	// The database to be anonymized. Defaults to the application database,
+	// or to `app` when the recovery doesn't configure it
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Runs the static masking of the `postgresql_anonymizer` extension,
+	// applying the masking rules declared with security labels in the
+	// recovered database. The extension must be available in the
+	// PostgreSQL image
+	// +optional
+	UseAnonymizerExtension bool `json:"useAnonymizerExtension,omitempty"`
+
+	// SQL scripts, stored in Secrets or ConfigMaps, to be executed in the
+	// anonymized database after the static masking. The Secrets are
+	// executed before the ConfigMaps, in the order they are listed
+	// +optional
+	SQLRefs *PostInitApplicationSQLRefs `json:"sqlRefs,omitempty"`
 }
 
 // BackupSource contains the backup we need to restore from, plus some
@@ -1057,6 +2675,46 @@ type RecoveryTarget struct {
 	Exclusive *bool `json:"exclusive,omitempty"`
 }
 
+// StorageBenchmarkConfiguration contains the configuration of the
+// pg_test_fsync run on the volumes of the new instances, before their
+// data directory is created
+type StorageBenchmarkConfiguration struct {
+	// Whether the storage benchmark is run
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled"`
+
+	// The duration of each test of pg_test_fsync, in seconds, default 2
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	SecondsPerTest int32 `json:"secondsPerTest,omitempty"`
+
+	// The minimum number of 8kB writes per second synced with `fdatasync`,
+	// the default `wal_sync_method` on Linux. When set, the bootstrap of
+	// an instance fails if its storage doesn't reach this rate
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinFdatasyncOpsPerSecond int64 `json:"minFdatasyncOpsPerSecond,omitempty"`
+}
+
+// StorageBenchmarkResult is the result of the storage benchmark
+// of an instance
+type StorageBenchmarkResult struct {
+	// The time when the benchmark has been run, in RFC3339 format
+	Timestamp string `json:"timestamp"`
+
+	// The number of 8kB writes per second synced with each of the
+	// methods of `wal_sync_method` supported by the volume
+	// +optional
+	OpsPerSecond map[string]int64 `json:"opsPerSecond,omitempty"`
+
+	// The error preventing the benchmark from being run, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
 // StorageConfiguration is the configuration of the storage of the PostgreSQL instances
 type StorageConfiguration struct {
 	// StorageClass to use for database data (`PGDATA`). Applied after
@@ -1079,6 +2737,41 @@ type StorageConfiguration struct {
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// VolumeAttributesClass to use for the generated PVCs, defining the
+	// IOPS and throughput provisioned by the CSI driver. Changes to this
+	// field are applied in place to the created PVCs, and it cannot be
+	// removed once set. Requires Kubernetes 1.29 or later with the
+	// `VolumeAttributesClass` feature gate enabled
+	// +optional
+	VolumeAttributesClassName *string `json:"volumeAttributesClassName,omitempty"`
+
+	// VolumeAttributesClass applied to the PVCs of an instance while a base
+	// backup is taken from it, or while a new replica is cloned from it,
+	// and replaced by `volumeAttributesClassName` at the end. It allows
+	// raising the IOPS and the throughput only when they are needed.
+	// Requires `volumeAttributesClassName` to be set
+	// +optional
+	BackupVolumeAttributesClassName *string `json:"backupVolumeAttributesClassName,omitempty"`
+}
+
+// TablespaceConfiguration is the configuration of a tablespace, which is
+// created by the operator in a dedicated volume
+type TablespaceConfiguration struct {
+	// The name of the tablespace. It must be made of lowercase letters,
+	// digits and underscores, and cannot start with `pg_`. The name is
+	// part of the name of the Pod volume, and cannot exceed 59 characters
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=59
+	Name string `json:"name"`
+
+	// The configuration of the storage of the tablespace
+	Storage StorageConfiguration `json:"storage"`
+
+	// When true, the tablespace is added to the `temp_tablespaces`
+	// parameter, and is used to store the temporary objects
+	// +optional
+	Temporary bool `json:"temporary,omitempty"`
 }
 
 // SyncReplicaElectionConstraints contains the constraints for sync replicas election.
@@ -1095,6 +2788,194 @@ type SyncReplicaElectionConstraints struct {
 	NodeLabelsAntiAffinity []string `json:"nodeLabelsAntiAffinity,omitempty"`
 }
 
+// LogicalDecodingConfiguration contains the logical replication slots
+// which are kept on the primary instance, surviving failovers, switchovers
+// and the re-creation of the cluster
+type LogicalDecodingConfiguration struct {
+	// The logical replication slots, created on the primary instance when
+	// missing. The slots removed from this list are not dropped
+	// +optional
+	Slots []LogicalReplicationSlot `json:"slots,omitempty"`
+
+	// Whether the logical replication slots are synchronized to the
+	// replicas, so that they can be resumed on the new primary after a
+	// failover. The changes are sent to the clients only after being
+	// received by every replica. Requires PostgreSQL 17 and the high
+	// availability replication slots
+	// +optional
+	FailoverSlots bool `json:"failoverSlots,omitempty"`
+}
+
+// AreFailoverSlotsEnabled checks whether the logical replication slots are
+// synchronized to the replicas
+func (configuration *LogicalDecodingConfiguration) AreFailoverSlotsEnabled() bool {
+	return configuration != nil && configuration.FailoverSlots && len(configuration.Slots) > 0
+}
+
+// LogicalReplicationSlot is a logical replication slot, decoding the
+// changes of a database with an output plugin
+type LogicalReplicationSlot struct {
+	// The name of the replication slot
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]{1,63}$`
+	Name string `json:"name"`
+
+	// The database whose changes are decoded
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// The output plugin decoding the changes, such as `pgoutput`, which is
+	// included in PostgreSQL, or `wal2json`, which needs to be available
+	// in the image or in one of the extensions
+	// +kubebuilder:default:=pgoutput
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// DefaultLogicalDecodingPlugin is the output plugin of the logical
+// replication slots, unless specified
+const DefaultLogicalDecodingPlugin = "pgoutput"
+
+// GetPlugin returns the output plugin of the logical replication slot
+func (slot LogicalReplicationSlot) GetPlugin() string {
+	if slot.Plugin != "" {
+		return slot.Plugin
+	}
+	return DefaultLogicalDecodingPlugin
+}
+
+// SynchronousReplicaConfigurationMethod is the method used to select the
+// synchronous standbys among the listed ones
+type SynchronousReplicaConfigurationMethod string
+
+const (
+	// SynchronousReplicaConfigurationMethodAny means quorum-based
+	// synchronous replication, where the transactions wait for any of the
+	// listed standbys
+	SynchronousReplicaConfigurationMethodAny SynchronousReplicaConfigurationMethod = "any"
+
+	// SynchronousReplicaConfigurationMethodFirst means priority-based
+	// synchronous replication, where the transactions wait for the first
+	// listed standbys which are connected
+	SynchronousReplicaConfigurationMethodFirst SynchronousReplicaConfigurationMethod = "first"
+)
+
+// DataDurabilityLevel specifies how the synchronous replication behaves
+// when the synchronous standbys are not available
+type DataDurabilityLevel string
+
+const (
+	// DataDurabilityLevelPreferred means that the number of synchronous
+	// standbys is lowered to the number of ready instances, allowing the
+	// writes to complete when the standbys are not available
+	DataDurabilityLevelPreferred DataDurabilityLevel = "preferred"
+
+	// DataDurabilityLevelRequired means that the number of synchronous
+	// standbys is never lowered, blocking the writes when the standbys are
+	// not available
+	DataDurabilityLevelRequired DataDurabilityLevel = "required"
+)
+
+// SynchronousReplicaConfiguration contains the configuration of the
+// synchronous replication, which is used to generate the
+// `synchronous_standby_names` parameter
+type SynchronousReplicaConfiguration struct {
+	// The method used to select the synchronous standbys, as in
+	// `synchronous_standby_names`
+	// +kubebuilder:validation:Enum=any;first
+	Method SynchronousReplicaConfigurationMethod `json:"method"`
+
+	// The number of synchronous standbys the transactions need to wait for
+	// +kubebuilder:validation:Minimum=1
+	Number int `json:"number"`
+
+	// The maximum number of instances of the cluster included in the list
+	// of the standby names. Every instance is included when not specified
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxStandbyNamesFromCluster *int `json:"maxStandbyNamesFromCluster,omitempty"`
+
+	// The standby names, such as the `application_name` of external
+	// standbys, listed before the instances of the cluster
+	// +optional
+	StandbyNamesPre []string `json:"standbyNamesPre,omitempty"`
+
+	// The standby names, such as the `application_name` of external
+	// standbys, listed after the instances of the cluster
+	// +optional
+	StandbyNamesPost []string `json:"standbyNamesPost,omitempty"`
+
+	// Whether the synchronous replication is `preferred`, lowering the number
+	// of synchronous standbys to the ready instances, or `required`, never
+	// lowering it even when this blocks the writes
+	// +kubebuilder:validation:Enum=preferred;required
+	// +kubebuilder:default:=preferred
+	// +optional
+	DataDurability DataDurabilityLevel `json:"dataDurability,omitempty"`
+}
+
+// IsRequired checks whether the synchronous replication is never lowered
+// to the available standbys
+func (configuration *SynchronousReplicaConfiguration) IsRequired() bool {
+	return configuration.DataDurability == DataDurabilityLevelRequired
+}
+
+// InstanceOverrideRole is the role of the instances an override applies to
+type InstanceOverrideRole string
+
+const (
+	// InstanceOverrideRolePrimary selects the primary instance
+	InstanceOverrideRolePrimary InstanceOverrideRole = "primary"
+
+	// InstanceOverrideRoleReplica selects the replica instances
+	InstanceOverrideRoleReplica InstanceOverrideRole = "replica"
+)
+
+// InstanceOverride contains the configuration, overriding the one in the
+// cluster specification, of some instances of the cluster
+type InstanceOverride struct {
+	// The ordinals of the instances this override applies to, i.e. `3` for
+	// the instance named `cluster-example-3`
+	// +optional
+	Instances []int `json:"instances,omitempty"`
+
+	// The role of the instances this override applies to, evaluated when
+	// the Pod or the PVC of an instance is created. Instances selected by
+	// their ordinal have precedence over the ones selected by their role
+	// +kubebuilder:validation:Enum=primary;replica
+	// +optional
+	Role InstanceOverrideRole `json:"role,omitempty"`
+
+	// Resources requirements of the selected instances, replacing the
+	// ones of the cluster
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Size of the storage of the selected instances, replacing the one
+	// of the cluster. It applies to the PGDATA volume only
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
+}
+
+// DeletionPolicyConfiguration contains the actions to be taken by the
+// operator before releasing the resources of a deleted cluster
+type DeletionPolicyConfiguration struct {
+	// When enabled, the operator takes a final backup of the cluster, and
+	// verifies it, before releasing the resources of the deleted cluster.
+	// It requires the backup section with the `barmanObjectStore`
+	// configuration
+	// +optional
+	FinalBackup bool `json:"finalBackup,omitempty"`
+
+	// The maximum time, in seconds, to wait for the final backup to be
+	// completed, after which the resources of the cluster are released
+	// anyway (default 3600)
+	// +kubebuilder:default:=3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FinalBackupTimeout int32 `json:"finalBackupTimeout,omitempty"`
+}
+
 // AffinityConfiguration contains the info we need to create the
 // affinity rules for Pods
 type AffinityConfiguration struct {
@@ -1241,6 +3122,36 @@ type BarmanObjectStoreConfiguration struct {
 	// HistoryTags is a list of key value pairs that will be passed to the
 	// Barman --history-tags option.
 	HistoryTags map[string]string `json:"historyTags,omitempty"`
+
+	// The client-side encryption of the WAL files and of the base backups
+	// with the keys provided by the user, before they are uploaded to the
+	// object store
+	// +optional
+	Encryption *ClientSideEncryptionConfiguration `json:"encryption,omitempty"`
+
+	// When enabled, the instance manager creates the bucket (or the
+	// container) in the destination path if it doesn't exist, before
+	// archiving the first WAL file. The lifecycle rules of a created bucket
+	// expire the noncurrent objects according to the retention policy.
+	// Disabled by default
+	// +optional
+	CreateBucket bool `json:"createBucket,omitempty"`
+}
+
+// ClientSideEncryptionConfiguration contains the keys used to encrypt the
+// WAL files and the base backups before they are uploaded to the object store
+type ClientSideEncryptionConfiguration struct {
+	// The secret containing the encryption keys. Every entry of the secret
+	// is a 32 bytes long AES-256 key, named by its key in the secret.
+	// The keys that are not active are only used to decrypt the files
+	// stored before a key rotation
+	KeysSecret LocalObjectReference `json:"keysSecret"`
+
+	// The name of the key, in the keys secret, used to encrypt the new
+	// WAL files and base backups
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[^:,]+$`
+	ActiveKey string `json:"activeKey"`
 }
 
 // BackupConfiguration defines how the backup of the cluster are taken.
@@ -1252,12 +3163,314 @@ type BackupConfiguration struct {
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
 	// RetentionPolicy is the retention policy to be used for backups
-	// and WALs (i.e. '60d'). The retention policy is expressed in the form
-	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
-	// days, weeks, months.
-	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// and WALs (i.e. '60d' or '7b'). The retention policy is expressed in
+	// the form of `XXu` where `XX` is a positive integer and `u` is in
+	// `[dwmb]` - days, weeks, months for a recovery window, or the number
+	// of base backups to be kept.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwmb]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+
+	// Hooks to be executed by the instance manager around the backup,
+	// to bring the applications to a consistent state while the backup
+	// is being taken
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// The periodic verification of the consistency of the backups
+	// and of the WAL archive in the object store
+	// +optional
+	Verification *BackupVerificationConfiguration `json:"verification,omitempty"`
+
+	// The configuration of the backups taken with the `volumeSnapshot`
+	// method, requiring the VolumeSnapshot API in the Kubernetes cluster
+	// +optional
+	VolumeSnapshot *VolumeSnapshotConfiguration `json:"volumeSnapshot,omitempty"`
+
+	// The target recovery point objective, in seconds. When set, the
+	// primary switches to a new WAL segment every half of this interval
+	// (overriding the `archive_timeout` parameter), and the instances
+	// expose it through the `cnpg_collector_target_rpo_seconds` metric
+	// to drive the alerting on the WAL archive
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	TargetRPO int32 `json:"targetRPO,omitempty"`
+
+	// The integration with Velero: when set, the Pods of the instances
+	// are annotated with the hooks preparing them to be backed up by
+	// Velero, and the Jobs are excluded from the Velero backups
+	// +optional
+	Velero *VeleroConfiguration `json:"velero,omitempty"`
+}
+
+// VeleroConfiguration contains the configuration of the hooks run by
+// Velero while backing up the Pods of the instances
+type VeleroConfiguration struct {
+	// When enabled, the pre-backup hook fences one replica at a time,
+	// shutting down PostgreSQL while its volumes are being backed up.
+	// This is needed by the file system backups of Velero, and by the
+	// volume snapshots when the WAL or the tablespaces are stored in
+	// separate volumes, which are not snapshotted atomically
+	// +optional
+	FenceReplicas bool `json:"fenceReplicas,omitempty"`
+
+	// The number of seconds after which the replica fenced by the
+	// pre-backup hook is unfenced by the operator, in case the post-backup
+	// hook is not run because the backup failed
+	// +kubebuilder:default:=3600
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	FenceTimeout int32 `json:"fenceTimeout,omitempty"`
+}
+
+// DefaultVeleroFenceTimeout is the default number of seconds after which
+// the replica fenced by the Velero pre-backup hook is unfenced
+const DefaultVeleroFenceTimeout = 3600
+
+// GetFenceTimeout gets the time after which the replica fenced by the
+// Velero pre-backup hook is unfenced
+func (configuration *VeleroConfiguration) GetFenceTimeout() time.Duration {
+	if configuration.FenceTimeout == 0 {
+		return DefaultVeleroFenceTimeout * time.Second
+	}
+	return time.Duration(configuration.FenceTimeout) * time.Second
+}
+
+// VolumeSnapshotConfiguration contains the configuration of the backups
+// taken by snapshotting the volumes of the instance
+type VolumeSnapshotConfiguration struct {
+	// The VolumeSnapshotClass used for the snapshots of the PGDATA volume.
+	// When empty, the default class of the CSI driver is used
+	// +optional
+	ClassName string `json:"className,omitempty"`
+
+	// The VolumeSnapshotClass used for the snapshots of the WAL volume.
+	// When empty, the class used for the PGDATA volume is used
+	// +optional
+	WalClassName string `json:"walClassName,omitempty"`
+
+	// If true, the snapshots are taken between pg_backup_start and
+	// pg_backup_stop (hot backup). If false, the PGDATA volume is
+	// snapshotted while PostgreSQL is running and the restore relies on
+	// crash recovery (crash-consistent backup), which requires the WAL
+	// files and the tablespaces to be stored in the PGDATA volume.
+	// Defaults to true
+	// +kubebuilder:default:=true
+	// +optional
+	Online *bool `json:"online,omitempty"`
+
+	// Request an immediate checkpoint when starting an online backup,
+	// instead of waiting for the next scheduled one
+	// +optional
+	ImmediateCheckpoint bool `json:"immediateCheckpoint,omitempty"`
+}
+
+// GetWalClassName returns the VolumeSnapshotClass to be used for the
+// snapshots of the WAL volume
+func (configuration *VolumeSnapshotConfiguration) GetWalClassName() string {
+	if configuration.WalClassName != "" {
+		return configuration.WalClassName
+	}
+	return configuration.ClassName
+}
+
+// IsOnline returns true if the snapshots are taken as hot backups
+func (configuration *VolumeSnapshotConfiguration) IsOnline() bool {
+	return configuration.Online == nil || *configuration.Online
+}
+
+// BackupVerificationConfiguration contains the configuration of the
+// periodic verification of the backup catalog and of the WAL archive
+type BackupVerificationConfiguration struct {
+	// If enabled, the primary instance periodically verifies that every
+	// backup in the object store is complete and that the WAL files
+	// needed to recover from it are available
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled"`
+
+	// The number of seconds between two verifications, default 86400
+	// +kubebuilder:default:=86400
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	Interval int32 `json:"interval,omitempty"`
+
+	// The maximum number of WAL files being fetched in parallel
+	// while verifying the WAL archive, default 4
+	// +kubebuilder:default:=4
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxParallel int `json:"maxParallel,omitempty"`
+}
+
+// BackupHookFailurePolicy is the action to be taken when a backup hook fails
+type BackupHookFailurePolicy string
+
+const (
+	// BackupHookFailurePolicyFail marks the backup as failed when the hook fails
+	BackupHookFailurePolicyFail BackupHookFailurePolicy = "fail"
+
+	// BackupHookFailurePolicyIgnore records the hook failure in the backup
+	// status and goes on with the backup
+	BackupHookFailurePolicyIgnore BackupHookFailurePolicy = "ignore"
+)
+
+// DefaultBackupHookTimeout is the default timeout, in seconds, of a backup hook
+const DefaultBackupHookTimeout = 60
+
+// BackupHooks contains the hooks executed around a backup
+type BackupHooks struct {
+	// Hooks executed, in order, before the backup is started
+	// +optional
+	Pre []BackupHook `json:"pre,omitempty"`
+
+	// Hooks executed, in order, after the backup command has terminated,
+	// regardless of its result
+	// +optional
+	Post []BackupHook `json:"post,omitempty"`
+}
+
+// BackupHook is an action executed by the instance manager, inside
+// the PostgreSQL container, before or after a backup. Exactly one
+// of `sql` and `command` must be specified
+type BackupHook struct {
+	// The name of the hook, used to report its result in the backup status
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The SQL statement to be executed by the superuser. Every hook is
+	// executed in a dedicated session. The session of a pre-backup hook
+	// is kept open, inside a transaction, until the backup is complete,
+	// so that the locks taken by the statement are held during the backup
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// The database where the SQL statement is executed, defaults to `postgres`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The command to be executed, with its arguments
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// The maximum number of seconds the hook is allowed to run,
+	// defaults to 60
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+
+	// What to do when the hook fails or times out: `fail` (default)
+	// marks the backup as failed, while `ignore` just records the
+	// error in the backup status
+	// +kubebuilder:validation:Enum=fail;ignore
+	// +kubebuilder:default:=fail
+	// +optional
+	FailurePolicy BackupHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// GetTimeout returns the maximum duration of the hook
+func (hook BackupHook) GetTimeout() time.Duration {
+	if hook.Timeout < 1 {
+		return DefaultBackupHookTimeout * time.Second
+	}
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// GetDatabase returns the database where the SQL statement of the hook
+// is executed
+func (hook BackupHook) GetDatabase() string {
+	if hook.Database == "" {
+		return "postgres"
+	}
+	return hook.Database
+}
+
+// IsFailureIgnored checks if a failure of this hook should not
+// cause the backup to fail
+func (hook BackupHook) IsFailureIgnored() bool {
+	return hook.FailurePolicy == BackupHookFailurePolicyIgnore
+}
+
+// ManagedConfiguration contains the configuration of the Kubernetes
+// resources managed by the operator for a cluster
+type ManagedConfiguration struct {
+	// The configuration of the services created for the cluster
+	// +optional
+	Services *ManagedServices `json:"services,omitempty"`
+
+	// The instances to be fenced, or `*` to fence every instance. The
+	// PostgreSQL server of a fenced instance is shut down, while its Pod
+	// is kept running, and the instance is excluded by the failover and
+	// the switchover. The instances fenced through the
+	// `cnpg.io/fencedInstances` annotation are fenced too
+	// +optional
+	FencedInstances []string `json:"fencedInstances,omitempty"`
+}
+
+// ManagedServices contains the configuration of the services created
+// for a cluster
+type ManagedServices struct {
+	// The configuration of the `-ro` service
+	// +optional
+	ReadOnly *ReadOnlyServiceConfiguration `json:"readOnly,omitempty"`
+
+	// The IP family policy of the services created for the cluster, one of
+	// `SingleStack`, `PreferDualStack` or `RequireDualStack`. Defaults to
+	// the policy of the Kubernetes cluster, usually `SingleStack`
+	// +kubebuilder:validation:Enum:=SingleStack;PreferDualStack;RequireDualStack
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// The IP families of the services created for the cluster, in order
+	// of preference (i.e. `IPv6` and then `IPv4`). Defaults to the
+	// families of the Kubernetes cluster. The first family cannot be changed
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+}
+
+// ReadOnlyServiceSelectorPolicy specifies which instances are selected
+// by the `-ro` service
+type ReadOnlyServiceSelectorPolicy string
+
+const (
+	// ReadOnlyServiceSelectorPolicyReplicas selects only the replicas
+	ReadOnlyServiceSelectorPolicyReplicas ReadOnlyServiceSelectorPolicy = "replicas"
+
+	// ReadOnlyServiceSelectorPolicyReplicasWithPrimaryFallback selects the
+	// replicas, falling back to the primary when no replica is healthy
+	ReadOnlyServiceSelectorPolicyReplicasWithPrimaryFallback ReadOnlyServiceSelectorPolicy = "replicasWithPrimaryFallback"
+
+	// ReadOnlyServiceSelectorPolicySessionPinned selects the replicas,
+	// pinning every client to the same replica
+	ReadOnlyServiceSelectorPolicySessionPinned ReadOnlyServiceSelectorPolicy = "sessionPinned"
+)
+
+// DefaultDelayedReplicaThreshold is the default replay lag above which
+// a replica is considered delayed
+var DefaultDelayedReplicaThreshold = resource.MustParse("64Mi")
+
+// ReadOnlyServiceConfiguration contains the routing policy of
+// the `-ro` service
+type ReadOnlyServiceConfiguration struct {
+	// Which instances are selected by the service: only the replicas
+	// (`replicas` - default), the replicas or, when no replica is healthy,
+	// the primary (`replicasWithPrimaryFallback`), or the replicas with
+	// every client pinned to the same replica (`sessionPinned`)
+	// +kubebuilder:default:=replicas
+	// +kubebuilder:validation:Enum:=replicas;replicasWithPrimaryFallback;sessionPinned
+	// +optional
+	SelectorPolicy ReadOnlyServiceSelectorPolicy `json:"selectorPolicy,omitempty"`
+
+	// When enabled, the replicas whose replay lag is above the
+	// `delayedReplicaThreshold` are removed from the service
+	// +optional
+	ExcludeDelayedReplicas bool `json:"excludeDelayedReplicas,omitempty"`
+
+	// The amount of WAL that a replica is allowed to be behind the primary
+	// before being considered delayed (default 64Mi)
+	// +optional
+	DelayedReplicaThreshold *resource.Quantity `json:"delayedReplicaThreshold,omitempty"`
 }
 
 // WalBackupConfiguration is the configuration of the backup of the
@@ -1283,6 +3496,32 @@ type WalBackupConfiguration struct {
 	// value - with 1 being the minimum accepted value.
 	// +kubebuilder:validation:Minimum=1
 	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// Configure a managed `pg_receivewal` process, running in the primary
+	// instance, that streams the WAL being written into the object store
+	// while the segment is still open. This reduces the amount of data
+	// that can be lost if the whole cluster is lost, especially for clusters
+	// with a low write workload, where a WAL segment can take a long time
+	// to be completed and archived
+	// +optional
+	Streaming *WalStreamingConfiguration `json:"streaming,omitempty"`
+}
+
+// WalStreamingConfiguration is the configuration of the WAL streaming
+// archive, where the partial WAL segment being written is periodically
+// uploaded to the object store
+type WalStreamingConfiguration struct {
+	// Enable the managed `pg_receivewal` process streaming the WAL into
+	// the object store
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled"`
+
+	// The number of seconds between two uploads of the partial WAL
+	// segment being streamed, default 10
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PartialUploadInterval int32 `json:"partialUploadInterval,omitempty"`
 }
 
 // DataBackupConfiguration is the configuration of the backup of
@@ -1397,6 +3636,87 @@ type MonitoringConfiguration struct {
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// Enable or disable the `ServiceMonitor`, scraping the metrics of the
+	// instances through the `-metrics` headless service. It can be
+	// enabled instead of, or together with, the `PodMonitor`
+	// +kubebuilder:default:=false
+	// +optional
+	EnableServiceMonitor bool `json:"enableServiceMonitor,omitempty"`
+
+	// The detection of the long-running transactions, of the sessions
+	// blocked by locks and of the prepared transactions left open
+	// +optional
+	LongRunningTransactions *LongRunningTransactionsConfiguration `json:"longRunningTransactions,omitempty"`
+
+	// The forecast of the time left before the volumes of the instances
+	// are full, at their current growth rate
+	// +optional
+	StorageForecast *StorageForecastConfiguration `json:"storageForecast,omitempty"`
+}
+
+// DefaultStorageForecastWarningDays is the default number of days under
+// which a volume expected to be full raises a warning
+const DefaultStorageForecastWarningDays = 7
+
+// StorageForecastConfiguration controls the warning raised when the
+// volumes of the instances are expected to be full soon
+type StorageForecastConfiguration struct {
+	// The number of days under which a volume expected to be full at its
+	// current growth rate sets the `StorageCapacity` condition of the
+	// cluster to false (default: `7`)
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WarningDays int32 `json:"warningDays,omitempty"`
+}
+
+// DefaultLongRunningTransactionsThreshold is the default age, in seconds,
+// over which a transaction is considered long-running
+const DefaultLongRunningTransactionsThreshold = 300
+
+// LongRunningTransactionsConfiguration controls the detection of the
+// transactions and of the locks that prevent vacuum from removing dead rows
+// and delay switchovers
+type LongRunningTransactionsConfiguration struct {
+	// The age, in seconds, over which a transaction or a prepared
+	// transaction is considered long-running (default: `300`)
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+
+	// Whether the operator should raise a warning event on the cluster
+	// when an instance starts reporting long-running transactions, prepared
+	// transactions or sessions blocked by locks, and a normal event when
+	// it stops (default: `false`)
+	// +optional
+	EmitEvents bool `json:"emitEvents,omitempty"`
+}
+
+// LongRunningTransactionsState contains the long-running transactions and
+// the sessions blocked by locks detected in an instance
+type LongRunningTransactionsState struct {
+	// The start time of the oldest transaction, or prepared transaction,
+	// older than the threshold. Its age is the time elapsed since then
+	// +optional
+	OldestTransactionStart *metav1.Time `json:"oldestTransactionStart,omitempty"`
+
+	// The number of transactions and prepared transactions older than
+	// the threshold
+	Transactions int `json:"transactions"`
+
+	// The number of sessions waiting for a lock held by another session
+	BlockedSessions int `json:"blockedSessions"`
+}
+
+// GetStorageForecastWarningDays returns the number of days under which
+// a volume expected to be full raises a warning
+func (m *MonitoringConfiguration) GetStorageForecastWarningDays() int32 {
+	if m == nil || m.StorageForecast == nil || m.StorageForecast.WarningDays == 0 {
+		return DefaultStorageForecastWarningDays
+	}
+	return m.StorageForecast.WarningDays
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -1430,6 +3750,11 @@ type ExternalCluster struct {
 
 	// The configuration for the barman-cloud tool suite
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The plugin storing the backups and the WAL files of this server,
+	// used as an alternative to `barmanObjectStore`
+	// +optional
+	PluginConfiguration *BackupPluginConfiguration `json:"pluginConfiguration,omitempty"`
 }
 
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
@@ -1518,13 +3843,184 @@ type ConfigMapResourceVersion struct {
 }
 
 // GetImageName get the name of the image that should be used
-// to create the pods
+// to create the pods. While a failed major version upgrade has not
+// been retried or rolled back, it is the image the instances were running
+// before the upgrade, as their data has not been upgraded
 func (cluster *Cluster) GetImageName() string {
+	if upgrade := cluster.Status.MajorVersionUpgrade; upgrade != nil &&
+		upgrade.Phase == MajorVersionUpgradePhaseFailed {
+		return configuration.Current.RewriteImageName(upgrade.SourceImage)
+	}
+
+	return cluster.GetRequestedImageName()
+}
+
+// GetRequestedImageName gets the name of the image requested in the
+// specification or resolved from the image catalog, or the default one
+func (cluster *Cluster) GetRequestedImageName() string {
+	if cluster.Spec.ImageCatalogRef != nil {
+		return configuration.Current.RewriteImageName(cluster.Status.Image)
+	}
+
 	if len(cluster.Spec.ImageName) > 0 {
-		return cluster.Spec.ImageName
+		return configuration.Current.RewriteImageName(cluster.Spec.ImageName)
+	}
+
+	return configuration.Current.RewriteImageName(configuration.Current.PostgresImageName)
+}
+
+// IsMajorVersionUpgradeEnabled checks if changing the image to a new
+// PostgreSQL major version upgrades the data of the cluster
+func (cluster *Cluster) IsMajorVersionUpgradeEnabled() bool {
+	return cluster.Spec.MajorVersionUpgrade != nil
+}
+
+// GetPgUpgradeMethod gets the way pg_upgrade transfers the data files
+func (cluster *Cluster) GetPgUpgradeMethod() PgUpgradeMethod {
+	if cluster.Spec.MajorVersionUpgrade == nil || cluster.Spec.MajorVersionUpgrade.Method == "" {
+		return PgUpgradeMethodLink
+	}
+
+	return cluster.Spec.MajorVersionUpgrade.Method
+}
+
+// GetInstanceOverride gets the override applying to the instance with
+// the passed ordinal and role, if any
+func (cluster *Cluster) GetInstanceOverride(nodeSerial int, role InstanceOverrideRole) *InstanceOverride {
+	for idx := range cluster.Spec.InstanceOverrides {
+		for _, instance := range cluster.Spec.InstanceOverrides[idx].Instances {
+			if instance == nodeSerial {
+				return &cluster.Spec.InstanceOverrides[idx]
+			}
+		}
+	}
+
+	for idx := range cluster.Spec.InstanceOverrides {
+		if role != "" && cluster.Spec.InstanceOverrides[idx].Role == role {
+			return &cluster.Spec.InstanceOverrides[idx]
+		}
+	}
+
+	return nil
+}
+
+// GetInstanceResources gets the resources requirements of the instance
+// with the passed ordinal and role
+func (cluster *Cluster) GetInstanceResources(nodeSerial int, role InstanceOverrideRole) corev1.ResourceRequirements {
+	if override := cluster.GetInstanceOverride(nodeSerial, role); override != nil && override.Resources != nil {
+		return *override.Resources
+	}
+
+	return cluster.Spec.Resources
+}
+
+// GetInstanceStorageConfiguration gets the configuration of the PGDATA
+// storage of the instance with the passed ordinal and role
+func (cluster *Cluster) GetInstanceStorageConfiguration(
+	nodeSerial int,
+	role InstanceOverrideRole,
+) StorageConfiguration {
+	storage := cluster.Spec.StorageConfiguration
+	if override := cluster.GetInstanceOverride(nodeSerial, role); override != nil && override.StorageSize != "" {
+		storage.Size = override.StorageSize
+	}
+
+	return storage
+}
+
+// IsRestrictedReplica checks whether the passed instance is a restricted
+// replica, that can never be promoted
+func (cluster *Cluster) IsRestrictedReplica(instanceName string) bool {
+	if cluster.Spec.RestrictedReplicas == nil {
+		return false
+	}
+
+	for _, nodeSerial := range cluster.Spec.RestrictedReplicas.Instances {
+		if cluster.GetInstanceName(nodeSerial) == instanceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPromotionPriority gets the priority of the passed instance when a
+// new primary is elected
+func (cluster *Cluster) GetPromotionPriority(instanceName string) int32 {
+	for _, item := range cluster.Spec.PromotionPriorities {
+		if cluster.GetInstanceName(item.Instance) == instanceName {
+			return item.Priority
+		}
+	}
+
+	return 0
+}
+
+// GetInstancePostgresqlParameters gets the PostgreSQL configuration of the
+// passed instance, including the parameters of the restricted replicas
+// when the instance is one of them
+func (cluster *Cluster) GetInstancePostgresqlParameters(instanceName string) map[string]string {
+	parameters := cluster.GetPostgresqlParameters()
+	if !cluster.IsRestrictedReplica(instanceName) || len(cluster.Spec.RestrictedReplicas.Parameters) == 0 {
+		return parameters
+	}
+
+	result := make(map[string]string, len(parameters)+len(cluster.Spec.RestrictedReplicas.Parameters))
+	for key, value := range parameters {
+		result[key] = value
+	}
+	for key, value := range cluster.Spec.RestrictedReplicas.Parameters {
+		result[key] = value
+	}
+
+	return result
+}
+
+// IsFinalBackupEnabled checks whether a final backup should be taken
+// when the cluster is deleted
+func (cluster *Cluster) IsFinalBackupEnabled() bool {
+	return cluster.Spec.DeletionPolicy != nil && cluster.Spec.DeletionPolicy.FinalBackup
+}
+
+// GetFinalBackupTimeout gets the maximum time to wait for the final
+// backup to be completed
+func (cluster *Cluster) GetFinalBackupTimeout() time.Duration {
+	if cluster.Spec.DeletionPolicy == nil || cluster.Spec.DeletionPolicy.FinalBackupTimeout <= 0 {
+		return time.Hour
+	}
+
+	return time.Duration(cluster.Spec.DeletionPolicy.FinalBackupTimeout) * time.Second
+}
+
+// GetFinalBackupName gets the name of the final backup taken when the
+// cluster is deleted. The name depends on the deletion time, so that it
+// doesn't match the final backup of a previous cluster with the same name
+func (cluster *Cluster) GetFinalBackupName() string {
+	if cluster.DeletionTimestamp == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s-final-%s", cluster.Name, cluster.DeletionTimestamp.UTC().Format("20060102150405"))
+}
+
+// GetInstanceRole gets the role of the passed instance to be used to
+// select the instance overrides
+func (cluster *Cluster) GetInstanceRole(instanceName string) InstanceOverrideRole {
+	if cluster.Status.TargetPrimary == instanceName {
+		return InstanceOverrideRolePrimary
+	}
+
+	return InstanceOverrideRoleReplica
+}
+
+// GetImagePullPolicy gets the pull policy of the images used in the Pods,
+// defaulting to the one set in the operator configuration
+func (cluster *Cluster) GetImagePullPolicy() corev1.PullPolicy {
+	if cluster.Spec.ImagePullPolicy != "" {
+		return cluster.Spec.ImagePullPolicy
 	}
 
-	return configuration.Current.PostgresImageName
+	return configuration.Current.GetImagePullPolicy()
 }
 
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
@@ -1537,7 +4033,14 @@ func (cluster *Cluster) GetImageName() string {
 func (cluster *Cluster) GetPostgresqlVersion() (int, error) {
 	image := cluster.GetImageName()
 	tag := utils.GetImageTag(image)
-	return postgres.GetPostgresVersionFromTag(tag)
+	version, err := postgres.GetPostgresVersionFromTag(tag)
+	if err != nil && cluster.Spec.ImageCatalogRef != nil {
+		// The image has not been resolved from the catalog yet, or
+		// its tag doesn't contain the version: only the requested
+		// major version is known
+		return cluster.Spec.ImageCatalogRef.Major * 10000, nil
+	}
+	return version, err
 }
 
 // GetImagePullSecret get the name of the pull secret to use
@@ -1632,119 +4135,494 @@ func (cluster *Cluster) GetApplicationDatabaseName() string {
 	return ""
 }
 
-// GetApplicationDatabaseOwner get the owner user of the application database for a specific bootstrap
-func (cluster *Cluster) GetApplicationDatabaseOwner() string {
-	bootstrap := cluster.Spec.Bootstrap
-	if bootstrap == nil {
-		return ""
+// GetApplicationDatabaseOwner get the owner user of the application database for a specific bootstrap
+func (cluster *Cluster) GetApplicationDatabaseOwner() string {
+	bootstrap := cluster.Spec.Bootstrap
+	if bootstrap == nil {
+		return ""
+	}
+
+	if bootstrap.Recovery != nil && bootstrap.Recovery.Owner != "" {
+		return bootstrap.Recovery.Owner
+	}
+
+	if bootstrap.PgBaseBackup != nil && bootstrap.PgBaseBackup.Owner != "" {
+		return bootstrap.PgBaseBackup.Owner
+	}
+
+	if bootstrap.InitDB != nil && bootstrap.InitDB.Owner != "" {
+		return bootstrap.InitDB.Owner
+	}
+
+	return ""
+}
+
+// GetServerCASecretName get the name of the secret containing the CA
+// of the cluster
+func (cluster *Cluster) GetServerCASecretName() string {
+	if cluster.IsCertManagerEnabled() {
+		// The secrets written by cert-manager contain the CA too
+		return cluster.GetServerTLSSecretName()
+	}
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
+		return cluster.Spec.Certificates.ServerCASecret
+	}
+	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
+}
+
+// IsCertManagerEnabled returns true if the certificates of the cluster
+// are issued by cert-manager
+func (cluster *Cluster) IsCertManagerEnabled() bool {
+	return cluster.Spec.Certificates != nil && cluster.Spec.Certificates.CertManager != nil
+}
+
+// GetServerTLSSecretName get the name of the secret containing the
+// certificate that is used for the PostgreSQL servers
+func (cluster *Cluster) GetServerTLSSecretName() string {
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerTLSSecret != "" {
+		return cluster.Spec.Certificates.ServerTLSSecret
+	}
+	return fmt.Sprintf("%v%v", cluster.Name, ServerSecretSuffix)
+}
+
+// GetClientCASecretName get the name of the secret containing the CA
+// of the cluster
+func (cluster *Cluster) GetClientCASecretName() string {
+	if cluster.IsCertManagerEnabled() {
+		// The secrets written by cert-manager contain the CA too
+		return cluster.GetReplicationSecretName()
+	}
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ClientCASecret != "" {
+		return cluster.Spec.Certificates.ClientCASecret
+	}
+	return fmt.Sprintf("%v%v", cluster.Name, ClientCaSecretSuffix)
+}
+
+// GetFixedInheritedAnnotations gets the annotations that should be
+// inherited by all resources according the cluster spec
+func (cluster *Cluster) GetFixedInheritedAnnotations() map[string]string {
+	if cluster.Spec.InheritedMetadata == nil || cluster.Spec.InheritedMetadata.Annotations == nil {
+		return nil
+	}
+	return cluster.Spec.InheritedMetadata.Annotations
+}
+
+// GetFixedInheritedLabels gets the labels that should be
+// inherited by all resources according the cluster spec
+func (cluster *Cluster) GetFixedInheritedLabels() map[string]string {
+	if cluster.Spec.InheritedMetadata == nil || cluster.Spec.InheritedMetadata.Labels == nil {
+		return nil
+	}
+	return cluster.Spec.InheritedMetadata.Labels
+}
+
+// GetReplicationSecretName get the name of the secret for the replication user
+func (cluster *Cluster) GetReplicationSecretName() string {
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationTLSSecret != "" {
+		return cluster.Spec.Certificates.ReplicationTLSSecret
+	}
+	return fmt.Sprintf("%v%v", cluster.Name, ReplicationSecretSuffix)
+}
+
+// GetServiceAnyName return the name of the service that is used as DNS
+// domain for all the nodes, even if they are not ready
+func (cluster *Cluster) GetServiceAnyName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
+}
+
+// GetServiceReadName return the name of the service that is used for
+// read transactions (including the primary)
+func (cluster *Cluster) GetServiceReadName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadSuffix)
+}
+
+// GetServiceReadOnlyName return the name of the service that is used for
+// read-only transactions (excluding the primary)
+func (cluster *Cluster) GetServiceReadOnlyName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadOnlySuffix)
+}
+
+// GetServiceRestrictedName return the name of the service that is used
+// for the restricted replicas
+func (cluster *Cluster) GetServiceRestrictedName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceRestrictedSuffix)
+}
+
+// GetServiceMetricsName return the name of the headless service used to
+// scrape the metrics of the instances
+func (cluster *Cluster) GetServiceMetricsName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceMetricsSuffix)
+}
+
+// GetServiceReadWriteName return the name of the service that is used for
+// read-write transactions
+func (cluster *Cluster) GetServiceReadWriteName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteSuffix)
+}
+
+// ServiceDiscoveryConfiguration contains the configuration of the service
+// discovery records of the cluster
+type ServiceDiscoveryConfiguration struct {
+	// The DNS domain of the Kubernetes cluster, such as `cluster.local`.
+	// When set, the published host names are fully qualified
+	// +optional
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+
+	// If true, the service discovery records are also published in the
+	// `<cluster>-discovery` ConfigMap, which can be read by the
+	// applications without access to the Cluster resource
+	// +optional
+	PublishConfigMap bool `json:"publishConfigMap,omitempty"`
+}
+
+// ServiceDiscoveryStatus contains the host names and the port of the
+// services of the cluster, letting the external automation connect to it
+// without depending on the naming conventions of the operator
+type ServiceDiscoveryStatus struct {
+	// The host name of the `-rw` service, pointing to the primary instance
+	ReadWrite string `json:"readWrite,omitempty"`
+
+	// The host name of the `-ro` service, pointing to the replicas
+	ReadOnly string `json:"readOnly,omitempty"`
+
+	// The host name of the `-r` service, pointing to every instance
+	Read string `json:"read,omitempty"`
+
+	// The port where PostgreSQL is listening
+	Port int32 `json:"port,omitempty"`
+
+	// The host name of the current primary instance
+	CurrentPrimary string `json:"currentPrimary,omitempty"`
+}
+
+// GetServiceDiscoveryConfigMapName returns the name of the ConfigMap
+// containing the service discovery records of the cluster
+func (cluster *Cluster) GetServiceDiscoveryConfigMapName() string {
+	return cluster.Name + ServiceDiscoveryConfigMapSuffix
+}
+
+// IsServiceDiscoveryConfigMapPublished returns true if the service discovery
+// records are published in a ConfigMap
+func (cluster *Cluster) IsServiceDiscoveryConfigMapPublished() bool {
+	return cluster.Spec.ServiceDiscovery != nil && cluster.Spec.ServiceDiscovery.PublishConfigMap
+}
+
+// getServiceHostName returns the host name of the passed service of the
+// cluster, fully qualified when the DNS domain is configured
+func (cluster *Cluster) getServiceHostName(serviceName string) string {
+	hostName := fmt.Sprintf("%s.%s.svc", serviceName, cluster.Namespace)
+	if cluster.Spec.ServiceDiscovery != nil && cluster.Spec.ServiceDiscovery.ClusterDomain != "" {
+		hostName += "." + strings.TrimSuffix(cluster.Spec.ServiceDiscovery.ClusterDomain, ".")
+	}
+	return hostName
+}
+
+// BuildServiceDiscoveryStatus builds the service discovery records of the
+// cluster. The current primary instance is reached through the headless
+// `-any` service
+func (cluster *Cluster) BuildServiceDiscoveryStatus() *ServiceDiscoveryStatus {
+	status := &ServiceDiscoveryStatus{
+		ReadWrite: cluster.getServiceHostName(cluster.GetServiceReadWriteName()),
+		ReadOnly:  cluster.getServiceHostName(cluster.GetServiceReadOnlyName()),
+		Read:      cluster.getServiceHostName(cluster.GetServiceReadName()),
+		Port:      postgres.ServerPort,
+	}
+	if cluster.Status.CurrentPrimary != "" {
+		status.CurrentPrimary = cluster.GetInstanceHostName(cluster.Status.CurrentPrimary)
+	}
+	return status
+}
+
+// GetInstanceHostName returns the host name of the passed instance,
+// resolved through the headless `-any` service
+func (cluster *Cluster) GetInstanceHostName(podName string) string {
+	return podName + "." + cluster.getServiceHostName(cluster.GetServiceAnyName())
+}
+
+// GetMaxStartDelay get the amount of time of startDelay config option
+func (cluster *Cluster) GetMaxStartDelay() int32 {
+	if cluster.Spec.MaxStartDelay > 0 {
+		return cluster.Spec.MaxStartDelay
+	}
+	return 30
+}
+
+// GetSpecChangeTimestamp returns the time of the last change of the
+// cluster, excluding its status, as recorded by the API server in the
+// managed fields. The creation time is used when this information is missing
+func (cluster *Cluster) GetSpecChangeTimestamp() time.Time {
+	result := cluster.CreationTimestamp.Time
+	for _, entry := range cluster.ManagedFields {
+		if entry.Subresource != "" || entry.Time == nil {
+			continue
+		}
+		if entry.Time.After(result) {
+			result = entry.Time.Time
+		}
+	}
+
+	return result
+}
+
+// GetMaxStopDelay get the amount of time PostgreSQL has to stop
+func (cluster *Cluster) GetMaxStopDelay() int32 {
+	if cluster.Spec.MaxStopDelay > 0 {
+		return cluster.Spec.MaxStopDelay
+	}
+	return 30
+}
+
+// GetReplicationSSLMode returns the libpq "sslmode" to be used by the
+// replicas and the poolers to connect to the primary
+func (cluster *Cluster) GetReplicationSSLMode() string {
+	if cluster.Spec.Replication == nil {
+		return "verify-ca"
+	}
+
+	switch cluster.Spec.Replication.TLSMode {
+	case ReplicationTLSModeVerifyFull:
+		return "verify-full"
+	case ReplicationTLSModeAllowFallback:
+		return "prefer"
+	default:
+		return "verify-ca"
+	}
+}
+
+// IsConnectionDrainingEnabled checks if the client connections should be
+// drained from the primary before demoting it during a switchover
+func (cluster *Cluster) IsConnectionDrainingEnabled() bool {
+	return cluster.Spec.ConnectionDraining != nil && cluster.Spec.ConnectionDraining.Enabled
+}
+
+// GetConnectionDrainingGracePeriod gets the amount of time active client
+// sessions are given to complete before being terminated during a switchover
+func (cluster *Cluster) GetConnectionDrainingGracePeriod() time.Duration {
+	if cluster.Spec.ConnectionDraining != nil && cluster.Spec.ConnectionDraining.GracePeriod > 0 {
+		return time.Duration(cluster.Spec.ConnectionDraining.GracePeriod) * time.Second
+	}
+	return DefaultConnectionDrainingGracePeriod * time.Second
+}
+
+// IsShutdownCheckpointEnabled checks if a checkpoint should be requested
+// on the instances before the planned restarts and switchovers
+func (cluster *Cluster) IsShutdownCheckpointEnabled() bool {
+	return cluster.Spec.ShutdownCheckpoint != nil && cluster.Spec.ShutdownCheckpoint.Enabled
+}
+
+// GetShutdownCheckpointMaxWalSize gets the value of `max_wal_size` applied
+// to the instances from the checkpoint requested before a planned shutdown,
+// or an empty string when it should not be changed
+func (cluster *Cluster) GetShutdownCheckpointMaxWalSize() string {
+	if !cluster.IsShutdownCheckpointEnabled() {
+		return ""
+	}
+	return cluster.Spec.ShutdownCheckpoint.MaxWalSize
+}
+
+// GetSwitchoverGuardrailPolicy gets the action taken on the prepared
+// transactions and on the logical replication workers of the primary
+// instance before it gets demoted during a switchover
+func (cluster *Cluster) GetSwitchoverGuardrailPolicy() SwitchoverGuardrailPolicy {
+	if cluster.Spec.SwitchoverGuardrail == nil || cluster.Spec.SwitchoverGuardrail.Policy == "" {
+		return SwitchoverGuardrailPolicyReport
+	}
+	return cluster.Spec.SwitchoverGuardrail.Policy
+}
+
+// ShouldPausePoolersDuringSwitchover checks if the PgBouncer poolers
+// pointing to this cluster should be paused while a switchover is in progress
+func (cluster *Cluster) ShouldPausePoolersDuringSwitchover() bool {
+	return cluster.IsConnectionDrainingEnabled() && cluster.Spec.ConnectionDraining.PausePoolers
+}
+
+// IsIsolationCheckEnabled checks if the primary instance should detect
+// being isolated by a network partition
+func (cluster *Cluster) IsIsolationCheckEnabled() bool {
+	return cluster.Spec.IsolationCheck != nil && cluster.Spec.IsolationCheck.Enabled
+}
+
+// GetIsolationCheckPeerQuorum gets the minimum number of replicas that must
+// be streaming from a primary that cannot reach the Kubernetes API server
+func (cluster *Cluster) GetIsolationCheckPeerQuorum() int {
+	if cluster.Spec.IsolationCheck != nil && cluster.Spec.IsolationCheck.PeerQuorum != nil {
+		return int(*cluster.Spec.IsolationCheck.PeerQuorum)
+	}
+	return cluster.Spec.Instances / 2
+}
+
+// GetReadOnlyServiceConfiguration returns the configuration of the `-ro`
+// service, or nil if the defaults apply
+func (cluster *Cluster) GetReadOnlyServiceConfiguration() *ReadOnlyServiceConfiguration {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
 	}
+	return cluster.Spec.Managed.Services.ReadOnly
+}
 
-	if bootstrap.Recovery != nil && bootstrap.Recovery.Owner != "" {
-		return bootstrap.Recovery.Owner
+// GetServicesIPFamilyPolicy returns the IP family policy of the services
+// created for the cluster, or nil if the default of the Kubernetes cluster
+// is used
+func (cluster *Cluster) GetServicesIPFamilyPolicy() *corev1.IPFamilyPolicy {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
 	}
+	return cluster.Spec.Managed.Services.IPFamilyPolicy
+}
 
-	if bootstrap.PgBaseBackup != nil && bootstrap.PgBaseBackup.Owner != "" {
-		return bootstrap.PgBaseBackup.Owner
+// GetServicesIPFamilies returns the IP families of the services created
+// for the cluster, or nil if the default of the Kubernetes cluster is used
+func (cluster *Cluster) GetServicesIPFamilies() []corev1.IPFamily {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
 	}
+	return cluster.Spec.Managed.Services.IPFamilies
+}
 
-	if bootstrap.InitDB != nil && bootstrap.InitDB.Owner != "" {
-		return bootstrap.InitDB.Owner
+// GetPostgresqlParameters returns the PostgreSQL configuration parameters
+// requested by the user, including the ones managed through the
+// connections and recovery tuning configurations. The defaults of the
+// recovery storage profile are overridden by the user parameters
+func (cluster *Cluster) GetPostgresqlParameters() map[string]string {
+	profileDefaults := cluster.getRecoveryStorageProfileParameters()
+	managed := cluster.getManagedPostgresqlParameters()
+	if len(profileDefaults) == 0 && len(managed) == 0 {
+		return cluster.Spec.PostgresConfiguration.Parameters
 	}
 
-	return ""
+	parameters := make(map[string]string,
+		len(cluster.Spec.PostgresConfiguration.Parameters)+len(profileDefaults)+len(managed))
+	for key, value := range profileDefaults {
+		parameters[key] = value
+	}
+	for key, value := range cluster.Spec.PostgresConfiguration.Parameters {
+		parameters[key] = value
+	}
+	for key, value := range managed {
+		parameters[key] = value
+	}
+	return parameters
 }
 
-// GetServerCASecretName get the name of the secret containing the CA
-// of the cluster
-func (cluster *Cluster) GetServerCASecretName() string {
-	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
-		return cluster.Spec.Certificates.ServerCASecret
+// getManagedPostgresqlParameters returns the PostgreSQL configuration
+// parameters explicitly set through the dedicated sections of the
+// `postgresql` stanza
+func (cluster *Cluster) getManagedPostgresqlParameters() map[string]string {
+	parameters := make(map[string]string)
+
+	if connections := cluster.Spec.PostgresConfiguration.Connections; connections != nil {
+		if connections.MaxConnections != nil {
+			parameters["max_connections"] = strconv.Itoa(int(*connections.MaxConnections))
+		}
+		if connections.SuperuserReservedConnections != nil {
+			parameters["superuser_reserved_connections"] = strconv.Itoa(int(*connections.SuperuserReservedConnections))
+		}
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
-}
 
-// GetServerTLSSecretName get the name of the secret containing the
-// certificate that is used for the PostgreSQL servers
-func (cluster *Cluster) GetServerTLSSecretName() string {
-	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerTLSSecret != "" {
-		return cluster.Spec.Certificates.ServerTLSSecret
+	if extensions := cluster.Spec.PostgresConfiguration.Extensions; len(extensions) > 0 {
+		names := make([]string, 0, len(extensions))
+		for _, extension := range extensions {
+			names = append(names, extension.Name)
+		}
+		parameters["dynamic_library_path"] = postgres.BuildDynamicLibraryPath(names)
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ServerSecretSuffix)
-}
 
-// GetClientCASecretName get the name of the secret containing the CA
-// of the cluster
-func (cluster *Cluster) GetClientCASecretName() string {
-	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ClientCASecret != "" {
-		return cluster.Spec.Certificates.ClientCASecret
+	if tuning := cluster.Spec.PostgresConfiguration.RecoveryTuning; tuning != nil {
+		if tuning.Prefetch != "" {
+			parameters["recovery_prefetch"] = string(tuning.Prefetch)
+		}
+		if tuning.MaintenanceIOConcurrency != nil {
+			parameters["maintenance_io_concurrency"] = strconv.Itoa(int(*tuning.MaintenanceIOConcurrency))
+		}
+		if tuning.MaxParallelWorkers != nil {
+			parameters["max_parallel_workers"] = strconv.Itoa(int(*tuning.MaxParallelWorkers))
+		}
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ClientCaSecretSuffix)
+
+	return parameters
 }
 
-// GetFixedInheritedAnnotations gets the annotations that should be
-// inherited by all resources according the cluster spec
-func (cluster *Cluster) GetFixedInheritedAnnotations() map[string]string {
-	if cluster.Spec.InheritedMetadata == nil || cluster.Spec.InheritedMetadata.Annotations == nil {
+// getRecoveryStorageProfileParameters returns the recovery parameters
+// suggested by the storage profile, skipping the ones not supported by
+// the PostgreSQL version in use
+func (cluster *Cluster) getRecoveryStorageProfileParameters() map[string]string {
+	tuning := cluster.Spec.PostgresConfiguration.RecoveryTuning
+	if tuning == nil || tuning.StorageProfile == "" {
 		return nil
 	}
-	return cluster.Spec.InheritedMetadata.Annotations
-}
 
-// GetFixedInheritedLabels gets the labels that should be
-// inherited by all resources according the cluster spec
-func (cluster *Cluster) GetFixedInheritedLabels() map[string]string {
-	if cluster.Spec.InheritedMetadata == nil || cluster.Spec.InheritedMetadata.Labels == nil {
+	profile, ok := recoveryStorageProfiles[tuning.StorageProfile]
+	if !ok {
 		return nil
 	}
-	return cluster.Spec.InheritedMetadata.Labels
-}
 
-// GetReplicationSecretName get the name of the secret for the replication user
-func (cluster *Cluster) GetReplicationSecretName() string {
-	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationTLSSecret != "" {
-		return cluster.Spec.Certificates.ReplicationTLSSecret
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return nil
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ReplicationSecretSuffix)
-}
 
-// GetServiceAnyName return the name of the service that is used as DNS
-// domain for all the nodes, even if they are not ready
-func (cluster *Cluster) GetServiceAnyName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
+	parameters := make(map[string]string)
+	if version >= 130000 {
+		parameters["maintenance_io_concurrency"] = strconv.Itoa(profile.maintenanceIOConcurrency)
+	}
+	if version >= 150000 {
+		parameters["recovery_prefetch"] = string(profile.prefetch)
+	}
+	return parameters
 }
 
-// GetServiceReadName return the name of the service that is used for
-// read transactions (including the primary)
-func (cluster *Cluster) GetServiceReadName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadSuffix)
+// GetLongRunningTransactionsThreshold returns the age, in seconds, over
+// which a transaction is considered long-running
+func (cluster *Cluster) GetLongRunningTransactionsThreshold() int32 {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.LongRunningTransactions == nil ||
+		cluster.Spec.Monitoring.LongRunningTransactions.Threshold < 1 {
+		return DefaultLongRunningTransactionsThreshold
+	}
+	return cluster.Spec.Monitoring.LongRunningTransactions.Threshold
 }
 
-// GetServiceReadOnlyName return the name of the service that is used for
-// read-only transactions (excluding the primary)
-func (cluster *Cluster) GetServiceReadOnlyName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadOnlySuffix)
+// AreLongRunningTransactionsEventsEnabled checks whether the operator
+// should raise events for the long-running transactions and locks
+func (cluster *Cluster) AreLongRunningTransactionsEventsEnabled() bool {
+	return cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.LongRunningTransactions != nil &&
+		cluster.Spec.Monitoring.LongRunningTransactions.EmitEvents
 }
 
-// GetServiceReadWriteName return the name of the service that is used for
-// read-write transactions
-func (cluster *Cluster) GetServiceReadWriteName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteSuffix)
+// GetConnectionsUsageWarningThreshold returns the percentage of the
+// available connection slots over which a warning is raised
+func (cluster *Cluster) GetConnectionsUsageWarningThreshold() int32 {
+	connections := cluster.Spec.PostgresConfiguration.Connections
+	if connections == nil || connections.UsageWarningThreshold == 0 {
+		return DefaultConnectionsUsageWarningThreshold
+	}
+	return connections.UsageWarningThreshold
 }
 
-// GetMaxStartDelay get the amount of time of startDelay config option
-func (cluster *Cluster) GetMaxStartDelay() int32 {
-	if cluster.Spec.MaxStartDelay > 0 {
-		return cluster.Spec.MaxStartDelay
+// GetReadOnlyServiceSelectorPolicy returns the policy used to select the
+// instances of the `-ro` service
+func (cluster *Cluster) GetReadOnlyServiceSelectorPolicy() ReadOnlyServiceSelectorPolicy {
+	configuration := cluster.GetReadOnlyServiceConfiguration()
+	if configuration == nil || configuration.SelectorPolicy == "" {
+		return ReadOnlyServiceSelectorPolicyReplicas
 	}
-	return 30
+	return configuration.SelectorPolicy
 }
 
-// GetMaxStopDelay get the amount of time PostgreSQL has to stop
-func (cluster *Cluster) GetMaxStopDelay() int32 {
-	if cluster.Spec.MaxStopDelay > 0 {
-		return cluster.Spec.MaxStopDelay
+// ShouldExcludeDelayedReplicas checks if the delayed replicas should be
+// removed from the `-ro` service
+func (cluster *Cluster) ShouldExcludeDelayedReplicas() bool {
+	configuration := cluster.GetReadOnlyServiceConfiguration()
+	return configuration != nil && configuration.ExcludeDelayedReplicas
+}
+
+// GetDelayedReplicaThreshold returns the replay lag, in bytes, above which
+// a replica is considered delayed
+func (cluster *Cluster) GetDelayedReplicaThreshold() int64 {
+	configuration := cluster.GetReadOnlyServiceConfiguration()
+	if configuration == nil || configuration.DelayedReplicaThreshold == nil {
+		return DefaultDelayedReplicaThreshold.Value()
 	}
-	return 30
+	return configuration.DelayedReplicaThreshold.Value()
 }
 
 // GetMaxSwitchoverDelay get the amount of time PostgreSQL has to stop before switchover
@@ -1755,6 +4633,40 @@ func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	return DefaultMaxSwitchoverDelay
 }
 
+// GetMaxClockSkew returns the maximum difference between the clock of
+// an instance and the one of the operator
+func (cluster *Cluster) GetMaxClockSkew() time.Duration {
+	if cluster.Spec.MaxClockSkew > 0 {
+		return time.Duration(cluster.Spec.MaxClockSkew) * time.Second
+	}
+	return DefaultMaxClockSkew * time.Second
+}
+
+// GetReattachStrategy gets the strategy used to re-attach a former
+// primary, defaulting to rewind
+func (cluster *Cluster) GetReattachStrategy() ReattachStrategy {
+	if cluster.Spec.ReattachStrategy == "" {
+		return ReattachStrategyRewind
+	}
+
+	return cluster.Spec.ReattachStrategy
+}
+
+// IsDataChecksumsEnabled checks whether the cluster has been created with
+// data checksums enabled. This is only known for the clusters bootstrapped
+// with initdb
+func (cluster *Cluster) IsDataChecksumsEnabled() bool {
+	return cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil &&
+		cluster.Spec.Bootstrap.InitDB.DataChecksums != nil && *cluster.Spec.Bootstrap.InitDB.DataChecksums
+}
+
+// IsWalLogHintsRequired checks whether wal_log_hints must be enabled for
+// pg_rewind to work, which happens when the former primaries are rewound
+// and the data checksums are not known to be enabled
+func (cluster *Cluster) IsWalLogHintsRequired() bool {
+	return cluster.GetReattachStrategy() == ReattachStrategyRewind && !cluster.IsDataChecksumsEnabled()
+}
+
 // GetPrimaryUpdateStrategy get the cluster primary update strategy,
 // defaulting to unsupervised
 func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
@@ -1777,6 +4689,31 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// IsInMaintenanceWindow checks whether the operator is allowed to execute
+// the non-urgent disruptive operations at the passed time. When this is
+// not the case, the beginning of the next maintenance window is returned
+func (cluster *Cluster) IsInMaintenanceWindow(now time.Time) (bool, time.Time, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var nextStart time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		start, inProgress, err := window.GetCurrentOrNextStart(now)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		if inProgress {
+			return true, time.Time{}, nil
+		}
+		if nextStart.IsZero() || start.Before(nextStart) {
+			nextStart = start
+		}
+	}
+
+	return false, nextStart, nil
+}
+
 // IsNodeMaintenanceWindowInProgress check if the upgrade mode is active or not
 func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
@@ -1801,13 +4738,128 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
-// IsInstanceFenced check if in a given instance should be fenced
-func (cluster *Cluster) IsInstanceFenced(instance string) bool {
+// IsHibernationRequested checks if the instances of the cluster should
+// be shut down
+func (cluster *Cluster) IsHibernationRequested() bool {
+	return cluster.Spec.Hibernation == HibernationOn
+}
+
+// GetWalArchivingPauseMaxSize gets the maximum size of the WAL files kept
+// waiting to be archived while the WAL archiving is paused. Unless set by
+// the user, it is half of the size of the volume storing the WAL files.
+// A nil value is returned when the size of the volume is unknown
+func (cluster *Cluster) GetWalArchivingPauseMaxSize() (*resource.Quantity, error) {
+	if value, ok := cluster.Annotations[utils.WalArchivingPausedMaxSizeAnnotationName]; ok {
+		maxSize, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for the %s annotation: %w",
+				utils.WalArchivingPausedMaxSizeAnnotationName, err)
+		}
+		return &maxSize, nil
+	}
+
+	volumeSize := cluster.Spec.StorageConfiguration.Size
+	if cluster.ShouldCreateWalArchiveVolume() {
+		volumeSize = cluster.Spec.WalStorage.Size
+	}
+	size, err := resource.ParseQuantity(volumeSize)
+	if err != nil {
+		return nil, nil
+	}
+	return resource.NewQuantity(size.Value()/2, resource.BinarySI), nil
+}
+
+// GetFencedInstances returns the instances fenced through the
+// `spec.managed.fencedInstances` option and the fencing annotation.
+// The instances in the option are returned even if the annotation is
+// invalid
+func (cluster *Cluster) GetFencedInstances() (*stringset.Data, error) {
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
 	if err != nil {
-		return false
+		fencedInstances = stringset.New()
+	}
+
+	if cluster.Spec.Managed != nil {
+		for _, instance := range cluster.Spec.Managed.FencedInstances {
+			fencedInstances.Put(instance)
+		}
+	}
+
+	return fencedInstances, err
+}
+
+// IsVeleroEnabled checks whether the Pods of the instances should be
+// prepared to be backed up by Velero
+func (cluster *Cluster) IsVeleroEnabled() bool {
+	return cluster.Spec.Backup != nil && cluster.Spec.Backup.Velero != nil
+}
+
+// HasBackupVolumeAttributesClass checks whether any volume of the cluster
+// uses a different VolumeAttributesClass during the base backups
+func (cluster *Cluster) HasBackupVolumeAttributesClass() bool {
+	if cluster.Spec.StorageConfiguration.BackupVolumeAttributesClassName != nil {
+		return true
+	}
+	if cluster.Spec.WalStorage != nil && cluster.Spec.WalStorage.BackupVolumeAttributesClassName != nil {
+		return true
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		if tablespace.Storage.BackupVolumeAttributesClassName != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPlugin gets the configuration of the plugin with the passed name
+func (cluster *Cluster) GetPlugin(name string) (*PluginConfiguration, bool) {
+	for idx := range cluster.Spec.Plugins {
+		if cluster.Spec.Plugins[idx].Name == name {
+			return &cluster.Spec.Plugins[idx], true
+		}
+	}
+	return nil, false
+}
+
+// GetPluginNames gets the names of the plugins running as sidecars of
+// the instances
+func (cluster *Cluster) GetPluginNames() []string {
+	names := make([]string, 0, len(cluster.Spec.Plugins))
+	for _, plugin := range cluster.Spec.Plugins {
+		names = append(names, plugin.Name)
+	}
+	return names
+}
+
+// GetWALArchiverPlugin gets the plugin archiving the WAL files of the
+// cluster, if any
+func (cluster *Cluster) GetWALArchiverPlugin() *PluginConfiguration {
+	for idx := range cluster.Spec.Plugins {
+		if cluster.Spec.Plugins[idx].IsWALArchiver {
+			return &cluster.Spec.Plugins[idx]
+		}
 	}
+	return nil
+}
+
+// GetPluginParameters gets the parameters of a request to a plugin,
+// merging the ones declared in the cluster with the passed overrides
+func (cluster *Cluster) GetPluginParameters(name string, overrides map[string]string) map[string]string {
+	parameters := make(map[string]string)
+	if plugin, ok := cluster.GetPlugin(name); ok {
+		for key, value := range plugin.Parameters {
+			parameters[key] = value
+		}
+	}
+	for key, value := range overrides {
+		parameters[key] = value
+	}
+	return parameters
+}
 
+// IsInstanceFenced check if in a given instance should be fenced
+func (cluster *Cluster) IsInstanceFenced(instance string) bool {
+	fencedInstances, _ := cluster.GetFencedInstances()
 	if fencedInstances.Has(utils.FenceAllServers) {
 		return true
 	}
@@ -1864,6 +4916,40 @@ func (cluster *Cluster) ShouldCreateApplicationDatabase() bool {
 		cluster.ShouldPgBaseBackupCreateApplicationDatabase()
 }
 
+// GetRecoveryAnonymization returns the anonymization configuration of
+// the recovery bootstrap, or nil if the anonymization is not enabled
+func (cluster *Cluster) GetRecoveryAnonymization() *RecoveryAnonymization {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return nil
+	}
+
+	anonymization := cluster.Spec.Bootstrap.Recovery.Anonymization
+	if anonymization == nil || !anonymization.Enabled {
+		return nil
+	}
+	return anonymization
+}
+
+// GetRecoveryAnonymizationDatabase returns the name of the database to be
+// anonymized after the recovery
+func (cluster *Cluster) GetRecoveryAnonymizationDatabase() string {
+	if anonymization := cluster.GetRecoveryAnonymization(); anonymization != nil && anonymization.Database != "" {
+		return anonymization.Database
+	}
+	if database := cluster.GetApplicationDatabaseName(); database != "" {
+		return database
+	}
+	return DefaultApplicationDatabaseName
+}
+
+// ShouldRecoveryRunAnonymizationSQLRefs returns true if the anonymization
+// of the recovered data includes SQL scripts stored in Secrets or ConfigMaps
+func (cluster *Cluster) ShouldRecoveryRunAnonymizationSQLRefs() bool {
+	anonymization := cluster.GetRecoveryAnonymization()
+	return anonymization != nil && anonymization.SQLRefs != nil &&
+		(len(anonymization.SQLRefs.SecretRefs) != 0 || len(anonymization.SQLRefs.ConfigMapRefs) != 0)
+}
+
 // ShouldInitDBRunPostInitApplicationSQLRefs returns true if for this cluster,
 // during the bootstrap phase using initDB, we need to run post application
 // SQL files from provided references.
@@ -1943,6 +5029,29 @@ func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// GetTablespace returns the configuration of the tablespace with the
+// passed name, or nil if there is no such tablespace
+func (cluster *Cluster) GetTablespace(name string) *TablespaceConfiguration {
+	for idx := range cluster.Spec.Tablespaces {
+		if cluster.Spec.Tablespaces[idx].Name == name {
+			return &cluster.Spec.Tablespaces[idx]
+		}
+	}
+	return nil
+}
+
+// GetTemporaryTablespaceNames returns the names of the tablespaces used
+// to store the temporary objects
+func (cluster *Cluster) GetTemporaryTablespaceNames() []string {
+	var result []string
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		if tablespace.Temporary {
+			result = append(result, tablespace.Name)
+		}
+	}
+	return result
+}
+
 // GetWalArchiveVolumeSuffix gets the wal archive volume name suffix
 func (cluster *Cluster) GetWalArchiveVolumeSuffix() string {
 	return "-wal"
@@ -1978,13 +5087,57 @@ func (cluster Cluster) ExternalCluster(name string) (ExternalCluster, bool) {
 	return ExternalCluster{}, false
 }
 
+// GetInstanceNamePrefix returns the prefix of the names of the instances
+func (cluster Cluster) GetInstanceNamePrefix() string {
+	if cluster.Spec.InstanceNamePrefix != "" {
+		return cluster.Spec.InstanceNamePrefix
+	}
+
+	return cluster.Name
+}
+
+// GetInstanceName returns the name of the instance having the passed serial,
+// which is also the name of its Pod and the base name of its PVCs
+func (cluster Cluster) GetInstanceName(nodeSerial int) string {
+	return fmt.Sprintf("%s-%0*d", cluster.GetInstanceNamePrefix(), cluster.Spec.InstanceOrdinalPadding, nodeSerial)
+}
+
 // IsReplica checks if this is a replica cluster or not
 func (cluster Cluster) IsReplica() bool {
 	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
 }
 
+// GetPromotionToken gets the token the designated primary has to
+// verify before being promoted, if any
+func (cluster Cluster) GetPromotionToken() string {
+	if cluster.Spec.ReplicaCluster == nil || cluster.Spec.ReplicaCluster.Enabled {
+		return ""
+	}
+	return cluster.Spec.ReplicaCluster.PromotionToken
+}
+
 var slotNameNegativeRegex = regexp.MustCompile("[^a-z0-9_]+")
 
+// GetSynchronizedStandbySlots returns the high availability replication
+// slots of the instances which can be promoted, except the passed one.
+// The logical replication slots synchronized to the replicas don't send
+// the changes to the clients before they have been received through these
+// slots
+func (cluster Cluster) GetSynchronizedStandbySlots(instanceName string) []string {
+	var slots []string
+	for _, name := range cluster.Status.InstanceNames {
+		if name == instanceName || cluster.IsRestrictedReplica(name) {
+			continue
+		}
+		if slotName := cluster.GetSlotNameFromInstanceName(name); slotName != "" {
+			slots = append(slots, slotName)
+		}
+	}
+
+	sort.Strings(slots)
+	return slots
+}
+
 // GetSlotNameFromInstanceName returns the slot name, given the instance name.
 // It returns an empty string if High Availability Replication Slots are disabled
 func (cluster Cluster) GetSlotNameFromInstanceName(instanceName string) string {
@@ -2024,6 +5177,20 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 		fmt.Sprintf("%v.%v.svc", cluster.GetServiceReadOnlyName(), cluster.Namespace),
 	}
 
+	if cluster.Spec.RestrictedReplicas != nil {
+		defaultAltDNSNames = append(defaultAltDNSNames,
+			cluster.GetServiceRestrictedName(),
+			fmt.Sprintf("%v.%v", cluster.GetServiceRestrictedName(), cluster.Namespace),
+			fmt.Sprintf("%v.%v.svc", cluster.GetServiceRestrictedName(), cluster.Namespace),
+		)
+	}
+
+	// the poolers balancing their connections across the replicas
+	// connect to the instances directly
+	for _, instanceName := range cluster.Status.InstanceNames {
+		defaultAltDNSNames = append(defaultAltDNSNames, cluster.GetInstanceHostName(instanceName))
+	}
+
 	if cluster.Spec.Certificates == nil {
 		return defaultAltDNSNames
 	}
@@ -2074,6 +5241,14 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	if _, ok := cluster.Status.ConfigMapResourceVersion.Metrics[config]; ok {
 		return true
 	}
+
+	for _, rule := range cluster.Spec.PostgresConfiguration.PgHBARules {
+		if rule.AddressesFrom != nil && rule.AddressesFrom.ConfigMapKeyRef != nil &&
+			rule.AddressesFrom.ConfigMapKeyRef.Name == config {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -2086,6 +5261,16 @@ func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	return false
 }
 
+// IsServiceMonitorEnabled checks if the ServiceMonitor object, and the
+// metrics service it scrapes, need to be created
+func (cluster *Cluster) IsServiceMonitorEnabled() bool {
+	if cluster.Spec.Monitoring != nil {
+		return cluster.Spec.Monitoring.EnableServiceMonitor
+	}
+
+	return false
+}
+
 // LogTimestampsWithMessage prints useful information about timestamps in stdout
 func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage string) {
 	contextLogger := log.FromContext(ctx)
@@ -2159,6 +5344,96 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// IsVolumeSnapshotBackupConfigured returns true if the backups can be
+// taken with the volumeSnapshot method, false otherwise
+func (backupConfiguration *BackupConfiguration) IsVolumeSnapshotBackupConfigured() bool {
+	return backupConfiguration != nil && backupConfiguration.VolumeSnapshot != nil
+}
+
+// GetTargetRPO gets the target recovery point objective,
+// zero when not set
+func (backupConfiguration *BackupConfiguration) GetTargetRPO() time.Duration {
+	if backupConfiguration == nil || backupConfiguration.TargetRPO <= 0 {
+		return 0
+	}
+
+	return time.Duration(backupConfiguration.TargetRPO) * time.Second
+}
+
+// GetArchiveTimeout gets the value of the archive_timeout parameter
+// derived from the target RPO, leaving half of it to archive the WAL
+// segment. An empty string is returned when no target RPO is set
+func (backupConfiguration *BackupConfiguration) GetArchiveTimeout() string {
+	targetRPO := backupConfiguration.GetTargetRPO()
+	if targetRPO == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%ds", int(targetRPO.Seconds())/2)
+}
+
+// IsEnabled checks whether the storage benchmark has to be run
+func (configuration *StorageBenchmarkConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetSecondsPerTest gets the duration of each test of pg_test_fsync
+func (configuration *StorageBenchmarkConfiguration) GetSecondsPerTest() int32 {
+	if configuration == nil || configuration.SecondsPerTest <= 0 {
+		return 2
+	}
+
+	return configuration.SecondsPerTest
+}
+
+// IsWalStreamingEnabled returns true if the partial WAL segments are
+// streamed into the object store
+func (configuration *BarmanObjectStoreConfiguration) IsWalStreamingEnabled() bool {
+	return configuration != nil &&
+		configuration.Wal != nil &&
+		configuration.Wal.Streaming != nil &&
+		configuration.Wal.Streaming.Enabled
+}
+
+// GetPartialUploadInterval gets the interval between two uploads
+// of the partial WAL segment being streamed
+func (configuration *WalStreamingConfiguration) GetPartialUploadInterval() time.Duration {
+	if configuration == nil || configuration.PartialUploadInterval <= 0 {
+		return 10 * time.Second
+	}
+
+	return time.Duration(configuration.PartialUploadInterval) * time.Second
+}
+
+// IsVerificationEnabled checks whether the periodic verification of the
+// consistency of the backups is enabled
+func (backupConfiguration *BackupConfiguration) IsVerificationEnabled() bool {
+	return backupConfiguration != nil &&
+		backupConfiguration.BarmanObjectStore != nil &&
+		backupConfiguration.Verification != nil &&
+		backupConfiguration.Verification.Enabled
+}
+
+// GetInterval gets the interval between two verifications
+// of the backups
+func (configuration *BackupVerificationConfiguration) GetInterval() time.Duration {
+	if configuration == nil || configuration.Interval <= 0 {
+		return 24 * time.Hour
+	}
+
+	return time.Duration(configuration.Interval) * time.Second
+}
+
+// GetMaxParallel gets the maximum number of WAL files being
+// fetched in parallel during the verification
+func (configuration *BackupVerificationConfiguration) GetMaxParallel() int {
+	if configuration == nil || configuration.MaxParallel <= 0 {
+		return 4
+	}
+
+	return configuration.MaxParallel
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -17,8 +17,13 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -56,6 +61,16 @@ var _ = Describe("PostgreSQL cluster type", func() {
 	It("correctly set the name of the secret containing the certificate for PostgreSQL", func() {
 		Expect(postgresql.GetServerTLSSecretName()).To(Equal("clustername-server"))
 	})
+
+	It("reads the CAs from the secrets written by cert-manager", func() {
+		withCertManager := postgresql.DeepCopy()
+		withCertManager.Spec.Certificates = &CertificatesConfiguration{
+			CertManager: &CertManagerConfiguration{IssuerRef: CertManagerIssuerReference{Name: "issuer"}},
+		}
+		Expect(withCertManager.IsCertManagerEnabled()).To(BeTrue())
+		Expect(withCertManager.GetServerCASecretName()).To(Equal("clustername-server"))
+		Expect(withCertManager.GetClientCASecretName()).To(Equal("clustername-replication"))
+	})
 })
 
 var _ = Describe("PostgreSQL services name", func() {
@@ -134,6 +149,49 @@ var _ = Describe("Node maintenance window", func() {
 	})
 })
 
+var _ = Describe("Maintenance windows", func() {
+	// Saturday, 2 a.m. to 6 a.m.
+	cluster := &Cluster{Spec: ClusterSpec{
+		MaintenanceWindows: []MaintenanceWindow{
+			{
+				Schedule: "0 2 * * 6",
+				Duration: v1.Duration{Duration: 4 * time.Hour},
+			},
+		},
+	}}
+
+	It("allows everything when no window is defined", func() {
+		inWindow, _, err := (&Cluster{}).IsInMaintenanceWindow(time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeTrue())
+	})
+
+	It("detects when a window is in progress", func() {
+		now := time.Date(2022, 12, 3, 3, 30, 0, 0, time.UTC)
+		inWindow, _, err := cluster.IsInMaintenanceWindow(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeTrue())
+	})
+
+	It("returns the beginning of the next window", func() {
+		now := time.Date(2022, 12, 3, 6, 30, 0, 0, time.UTC)
+		inWindow, nextWindow, err := cluster.IsInMaintenanceWindow(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inWindow).To(BeFalse())
+		Expect(nextWindow.Equal(time.Date(2022, 12, 10, 2, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("uses the time zone of the window", func() {
+		window := cluster.Spec.MaintenanceWindows[0]
+		window.TimeZone = "Europe/Rome"
+		now := time.Date(2022, 12, 3, 1, 30, 0, 0, time.UTC)
+		start, inProgress, err := window.GetCurrentOrNextStart(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeTrue())
+		Expect(start.Equal(time.Date(2022, 12, 3, 1, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+})
+
 var _ = Describe("Bootstrap via initdb", func() {
 	It("will create an application database if specified", func() {
 		cluster := Cluster{
@@ -395,6 +453,18 @@ var _ = Describe("look up for secrets", func() {
 	It("retrieves all names needed to build a server CA certificate are 9", func() {
 		Expect(len(cluster.GetClusterAltDNSNames())).To(Equal(9))
 	})
+	It("includes the host names of the instances", func() {
+		clusterWithInstances := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "clustername", Namespace: "default"},
+			Status:     ClusterStatus{InstanceNames: []string{"clustername-1", "clustername-2"}},
+		}
+		names := clusterWithInstances.GetClusterAltDNSNames()
+		Expect(names).To(HaveLen(11))
+		Expect(names).To(ContainElements(
+			"clustername-1.clustername-any.default.svc",
+			"clustername-2.clustername-any.default.svc",
+		))
+	})
 })
 
 var _ = Describe("A secret resource version", func() {
@@ -782,6 +852,36 @@ var _ = Describe("Fencing annotation", func() {
 			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
 		})
 	})
+
+	When("the instances are fenced in the specification", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation: "[\"one\"]",
+				},
+			},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{FencedInstances: []string{"two"}},
+			},
+		}
+
+		It("merges them with the annotation", func() {
+			Expect(cluster.IsInstanceFenced("one")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("two")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("three")).To(BeFalse())
+
+			fencedInstances, err := cluster.GetFencedInstances()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fencedInstances.ToSortedList()).To(Equal([]string{"one", "two"}))
+		})
+
+		It("uses them even if the annotation is invalid", func() {
+			invalidCluster := cluster.DeepCopy()
+			invalidCluster.Annotations[utils.FencedInstanceAnnotation] = "one"
+			Expect(invalidCluster.IsInstanceFenced("one")).To(BeFalse())
+			Expect(invalidCluster.IsInstanceFenced("two")).To(BeTrue())
+		})
+	})
 })
 
 var _ = Describe("Barman credentials", func() {
@@ -843,3 +943,556 @@ var _ = Describe("Replication slots names for instances", func() {
 			"_232_test_cluster_example_1"))
 	})
 })
+
+var _ = Describe("Isolation check peer quorum", func() {
+	It("defaults to the replicas needed to form a majority with the primary", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 2, IsolationCheck: &IsolationCheckConfiguration{}}}
+		Expect(cluster.GetIsolationCheckPeerQuorum()).To(Equal(1))
+
+		cluster.Spec.Instances = 3
+		Expect(cluster.GetIsolationCheckPeerQuorum()).To(Equal(1))
+
+		cluster.Spec.Instances = 5
+		Expect(cluster.GetIsolationCheckPeerQuorum()).To(Equal(2))
+	})
+
+	It("uses the configured peer quorum", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			Instances:      5,
+			IsolationCheck: &IsolationCheckConfiguration{PeerQuorum: pointer.Int32(4)},
+		}}
+		Expect(cluster.GetIsolationCheckPeerQuorum()).To(Equal(4))
+	})
+})
+
+var _ = Describe("Instance names", func() {
+	It("are built from the cluster name by default", func() {
+		cluster := Cluster{ObjectMeta: v1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.GetInstanceName(3)).To(Equal("cluster-example-3"))
+	})
+
+	It("use the configured prefix and ordinal padding", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+			Spec:       ClusterSpec{InstanceNamePrefix: "pg-legacy", InstanceOrdinalPadding: 2},
+		}
+		Expect(cluster.GetInstanceName(3)).To(Equal("pg-legacy-03"))
+		Expect(cluster.GetInstanceName(123)).To(Equal("pg-legacy-123"))
+	})
+})
+
+var _ = Describe("connections configuration", func() {
+	It("returns the user parameters when the connections are not managed", func() {
+		cluster := Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+		}}}
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{"work_mem": "8MB"}))
+		Expect(cluster.GetConnectionsUsageWarningThreshold()).To(
+			BeEquivalentTo(DefaultConnectionsUsageWarningThreshold))
+	})
+
+	It("adds the managed connections parameters", func() {
+		cluster := Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+			Connections: &ConnectionsConfiguration{
+				MaxConnections:               pointer.Int32(300),
+				SuperuserReservedConnections: pointer.Int32(5),
+				UsageWarningThreshold:        90,
+			},
+		}}}
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{
+			"work_mem":                       "8MB",
+			"max_connections":                "300",
+			"superuser_reserved_connections": "5",
+		}))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+		Expect(cluster.GetConnectionsUsageWarningThreshold()).To(BeEquivalentTo(90))
+	})
+})
+
+var _ = Describe("recovery tuning configuration", func() {
+	newCluster := func(imageName string, tuning *RecoveryTuningConfiguration, parameters map[string]string) Cluster {
+		return Cluster{Spec: ClusterSpec{
+			ImageName: imageName,
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters:     parameters,
+				RecoveryTuning: tuning,
+			},
+		}}
+	}
+
+	It("applies the defaults of the storage profile", func() {
+		cluster := newCluster("postgres:15.1",
+			&RecoveryTuningConfiguration{StorageProfile: RecoveryStorageProfileSSD}, nil)
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{
+			"recovery_prefetch":          "try",
+			"maintenance_io_concurrency": "200",
+		}))
+	})
+
+	It("skips the defaults not supported by the PostgreSQL version", func() {
+		cluster := newCluster("postgres:13.9",
+			&RecoveryTuningConfiguration{StorageProfile: RecoveryStorageProfileNetwork}, nil)
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{
+			"maintenance_io_concurrency": "64",
+		}))
+	})
+
+	It("lets the user parameters and the explicit settings override the profile", func() {
+		cluster := newCluster("postgres:15.1",
+			&RecoveryTuningConfiguration{
+				StorageProfile:     RecoveryStorageProfileHDD,
+				Prefetch:           RecoveryPrefetchOff,
+				MaxParallelWorkers: pointer.Int32(4),
+			},
+			map[string]string{"maintenance_io_concurrency": "2", "max_parallel_workers": "32"})
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{
+			"recovery_prefetch":          "off",
+			"maintenance_io_concurrency": "2",
+			"max_parallel_workers":       "4",
+		}))
+	})
+})
+
+var _ = Describe("extensions configuration", func() {
+	It("loads the libraries of the extensions", func() {
+		cluster := Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Extensions: []ExtensionConfiguration{
+				{Name: "pgvector", Image: "pgvector:0.3.2"},
+				{Name: "postgis", VolumeSource: &corev1.VolumeSource{}},
+			},
+		}}}
+		Expect(cluster.GetPostgresqlParameters()).To(Equal(map[string]string{
+			"dynamic_library_path": "$libdir:/extensions/pgvector/lib:/extensions/postgis/lib",
+		}))
+	})
+})
+
+var _ = Describe("recovery anonymization", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			Recovery: &BootstrapRecovery{Anonymization: &RecoveryAnonymization{UseAnonymizerExtension: true}},
+		}}}
+		Expect(cluster.GetRecoveryAnonymization()).To(BeNil())
+		Expect(cluster.ShouldRecoveryRunAnonymizationSQLRefs()).To(BeFalse())
+	})
+
+	It("anonymizes the application database by default", func() {
+		cluster := Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			Recovery: &BootstrapRecovery{Anonymization: &RecoveryAnonymization{Enabled: true}},
+		}}}
+		Expect(cluster.GetRecoveryAnonymizationDatabase()).To(Equal("app"))
+
+		cluster.Spec.Bootstrap.Recovery.Database = "shop"
+		Expect(cluster.GetRecoveryAnonymizationDatabase()).To(Equal("shop"))
+
+		cluster.Spec.Bootstrap.Recovery.Anonymization.Database = "crm"
+		Expect(cluster.GetRecoveryAnonymizationDatabase()).To(Equal("crm"))
+	})
+})
+
+var _ = Describe("long-running transactions configuration", func() {
+	It("uses the default threshold when not configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetLongRunningTransactionsThreshold()).To(
+			BeEquivalentTo(DefaultLongRunningTransactionsThreshold))
+		Expect(cluster.AreLongRunningTransactionsEventsEnabled()).To(BeFalse())
+	})
+
+	It("uses the configured threshold and events", func() {
+		cluster := Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			LongRunningTransactions: &LongRunningTransactionsConfiguration{Threshold: 60, EmitEvents: true},
+		}}}
+		Expect(cluster.GetLongRunningTransactionsThreshold()).To(BeEquivalentTo(60))
+		Expect(cluster.AreLongRunningTransactionsEventsEnabled()).To(BeTrue())
+	})
+})
+
+var _ = Describe("switchover guardrail", func() {
+	It("reports the prepared transactions by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetSwitchoverGuardrailPolicy()).To(Equal(SwitchoverGuardrailPolicyReport))
+		cluster.Spec.SwitchoverGuardrail = &SwitchoverGuardrailConfiguration{}
+		Expect(cluster.GetSwitchoverGuardrailPolicy()).To(Equal(SwitchoverGuardrailPolicyReport))
+	})
+
+	It("uses the configured policy", func() {
+		cluster := Cluster{Spec: ClusterSpec{SwitchoverGuardrail: &SwitchoverGuardrailConfiguration{
+			Policy: SwitchoverGuardrailPolicyAbort,
+		}}}
+		Expect(cluster.GetSwitchoverGuardrailPolicy()).To(Equal(SwitchoverGuardrailPolicyAbort))
+	})
+})
+
+var _ = Describe("shutdown checkpoint", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsShutdownCheckpointEnabled()).To(BeFalse())
+		Expect(cluster.GetShutdownCheckpointMaxWalSize()).To(BeEmpty())
+	})
+
+	It("applies max_wal_size only when enabled", func() {
+		cluster := Cluster{Spec: ClusterSpec{ShutdownCheckpoint: &ShutdownCheckpointConfiguration{
+			MaxWalSize: "4GB",
+		}}}
+		Expect(cluster.IsShutdownCheckpointEnabled()).To(BeFalse())
+		Expect(cluster.GetShutdownCheckpointMaxWalSize()).To(BeEmpty())
+
+		cluster.Spec.ShutdownCheckpoint.Enabled = true
+		Expect(cluster.IsShutdownCheckpointEnabled()).To(BeTrue())
+		Expect(cluster.GetShutdownCheckpointMaxWalSize()).To(Equal("4GB"))
+	})
+})
+
+var _ = Describe("image defaults from the operator configuration", func() {
+	var previousConfiguration configuration.Data
+
+	BeforeEach(func() {
+		previousConfiguration = *configuration.Current
+		configuration.Current.ImageRegistryMirrors = []string{"ghcr.io=mirror.example.com"}
+		configuration.Current.ImagePullPolicy = string(corev1.PullAlways)
+	})
+
+	AfterEach(func() {
+		*configuration.Current = previousConfiguration
+	})
+
+	It("rewrites the image name using the registry mirrors", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:15"}}
+		Expect(cluster.GetImageName()).To(Equal("mirror.example.com/cloudnative-pg/postgresql:15"))
+	})
+
+	It("defaults the pull policy only when not set in the cluster", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetImagePullPolicy()).To(Equal(corev1.PullAlways))
+
+		cluster.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+		Expect(cluster.GetImagePullPolicy()).To(Equal(corev1.PullIfNotPresent))
+	})
+
+	It("rewrites the source image of a failed major version upgrade", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:16"},
+			Status: ClusterStatus{MajorVersionUpgrade: &MajorVersionUpgradeStatus{
+				SourceImage: "ghcr.io/cloudnative-pg/postgresql:15",
+				TargetImage: "ghcr.io/cloudnative-pg/postgresql:16",
+				Phase:       MajorVersionUpgradePhaseFailed,
+			}},
+		}
+		Expect(cluster.GetImageName()).To(Equal("mirror.example.com/cloudnative-pg/postgresql:15"))
+	})
+})
+
+var _ = Describe("WAL streaming", func() {
+	It("is disabled by default", func() {
+		var configuration *BarmanObjectStoreConfiguration
+		Expect(configuration.IsWalStreamingEnabled()).To(BeFalse())
+		Expect((&BarmanObjectStoreConfiguration{}).IsWalStreamingEnabled()).To(BeFalse())
+		Expect((&BarmanObjectStoreConfiguration{
+			Wal: &WalBackupConfiguration{Streaming: &WalStreamingConfiguration{}},
+		}).IsWalStreamingEnabled()).To(BeFalse())
+	})
+
+	It("can be enabled", func() {
+		Expect((&BarmanObjectStoreConfiguration{
+			Wal: &WalBackupConfiguration{Streaming: &WalStreamingConfiguration{Enabled: true}},
+		}).IsWalStreamingEnabled()).To(BeTrue())
+	})
+
+	It("uploads the partial WAL segment every 10 seconds by default", func() {
+		var configuration *WalStreamingConfiguration
+		Expect(configuration.GetPartialUploadInterval()).To(Equal(10 * time.Second))
+		configuration = &WalStreamingConfiguration{PartialUploadInterval: 3}
+		Expect(configuration.GetPartialUploadInterval()).To(Equal(3 * time.Second))
+	})
+})
+
+var _ = Describe("Target RPO", func() {
+	It("is not set by default", func() {
+		var configuration *BackupConfiguration
+		Expect(configuration.GetTargetRPO()).To(BeZero())
+		Expect(configuration.GetArchiveTimeout()).To(BeEmpty())
+		Expect((&BackupConfiguration{}).GetArchiveTimeout()).To(BeEmpty())
+	})
+
+	It("derives the archive_timeout as half of the target", func() {
+		configuration := &BackupConfiguration{TargetRPO: 300}
+		Expect(configuration.GetTargetRPO()).To(Equal(5 * time.Minute))
+		Expect(configuration.GetArchiveTimeout()).To(Equal("150s"))
+	})
+})
+
+var _ = Describe("Instance overrides", func() {
+	cluster := &Cluster{
+		Spec: ClusterSpec{
+			StorageConfiguration: StorageConfiguration{Size: "10Gi"},
+			InstanceOverrides: []InstanceOverride{
+				{Role: InstanceOverrideRoleReplica, StorageSize: "5Gi"},
+				{Instances: []int{3}, StorageSize: "50Gi"},
+			},
+		},
+		Status: ClusterStatus{TargetPrimary: "cluster-1"},
+	}
+
+	It("selects the instances by ordinal before selecting them by role", func() {
+		Expect(cluster.GetInstanceOverride(3, InstanceOverrideRoleReplica)).
+			To(Equal(&cluster.Spec.InstanceOverrides[1]))
+		Expect(cluster.GetInstanceOverride(2, InstanceOverrideRoleReplica)).
+			To(Equal(&cluster.Spec.InstanceOverrides[0]))
+		Expect(cluster.GetInstanceOverride(1, InstanceOverrideRolePrimary)).To(BeNil())
+		Expect(cluster.GetInstanceOverride(1, "")).To(BeNil())
+	})
+
+	It("overrides the storage size", func() {
+		Expect(cluster.GetInstanceStorageConfiguration(3, InstanceOverrideRoleReplica).Size).To(Equal("50Gi"))
+		Expect(cluster.GetInstanceStorageConfiguration(2, InstanceOverrideRoleReplica).Size).To(Equal("5Gi"))
+		Expect(cluster.GetInstanceStorageConfiguration(1, InstanceOverrideRolePrimary).Size).To(Equal("10Gi"))
+		Expect(cluster.Spec.StorageConfiguration.Size).To(Equal("10Gi"))
+	})
+
+	It("detects the role of the instances", func() {
+		Expect(cluster.GetInstanceRole("cluster-1")).To(Equal(InstanceOverrideRolePrimary))
+		Expect(cluster.GetInstanceRole("cluster-2")).To(Equal(InstanceOverrideRoleReplica))
+	})
+})
+
+var _ = Describe("Restricted replicas", func() {
+	cluster := &Cluster{
+		ObjectMeta: v1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: ClusterSpec{
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: map[string]string{"work_mem": "4MB", "shared_buffers": "1GB"},
+			},
+			RestrictedReplicas: &RestrictedReplicasConfiguration{
+				Instances:  []int{3},
+				Parameters: map[string]string{"work_mem": "256MB"},
+			},
+		},
+	}
+
+	It("detects the restricted replicas", func() {
+		Expect(cluster.IsRestrictedReplica("cluster-3")).To(BeTrue())
+		Expect(cluster.IsRestrictedReplica("cluster-2")).To(BeFalse())
+		Expect((&Cluster{}).IsRestrictedReplica("cluster-3")).To(BeFalse())
+	})
+
+	It("gets the promotion priority of the instances", func() {
+		cluster := &Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster"},
+			Spec: ClusterSpec{
+				PromotionPriorities: []PromotionPriority{{Instance: 2, Priority: 10}},
+			},
+		}
+		Expect(cluster.GetPromotionPriority("cluster-2")).To(BeEquivalentTo(10))
+		Expect(cluster.GetPromotionPriority("cluster-3")).To(BeZero())
+	})
+
+	It("applies the parameters of the restricted replicas to them only", func() {
+		Expect(cluster.GetInstancePostgresqlParameters("cluster-3")).To(Equal(map[string]string{
+			"work_mem":       "256MB",
+			"shared_buffers": "1GB",
+		}))
+		Expect(cluster.GetInstancePostgresqlParameters("cluster-2")).
+			To(Equal(cluster.Spec.PostgresConfiguration.Parameters))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters["work_mem"]).To(Equal("4MB"))
+	})
+
+	It("includes the restricted service in the certificate names", func() {
+		Expect(cluster.GetServiceRestrictedName()).To(Equal("cluster-restricted"))
+		Expect(cluster.GetClusterAltDNSNames()).To(ContainElements(
+			"cluster-restricted",
+			"cluster-restricted.default",
+			"cluster-restricted.default.svc",
+		))
+	})
+})
+
+var _ = Describe("Final backup", func() {
+	It("is disabled by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.IsFinalBackupEnabled()).To(BeFalse())
+		Expect(cluster.GetFinalBackupTimeout()).To(Equal(time.Hour))
+		Expect(cluster.GetFinalBackupName()).To(BeEmpty())
+	})
+
+	It("is named after the deletion time", func() {
+		deletion := v1.NewTime(time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC))
+		cluster := &Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster", DeletionTimestamp: &deletion},
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicyConfiguration{FinalBackup: true, FinalBackupTimeout: 600},
+			},
+		}
+		Expect(cluster.IsFinalBackupEnabled()).To(BeTrue())
+		Expect(cluster.GetFinalBackupTimeout()).To(Equal(10 * time.Minute))
+		Expect(cluster.GetFinalBackupName()).To(Equal("cluster-final-20260304050607"))
+	})
+})
+
+var _ = Describe("Spec change timestamp", func() {
+	creation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("defaults to the creation time", func() {
+		cluster := Cluster{ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(creation)}}
+		Expect(cluster.GetSpecChangeTimestamp()).To(Equal(creation))
+	})
+
+	It("ignores the changes of the status", func() {
+		specChange := v1.NewTime(creation.Add(time.Hour))
+		statusChange := v1.NewTime(creation.Add(2 * time.Hour))
+		cluster := Cluster{ObjectMeta: v1.ObjectMeta{
+			CreationTimestamp: v1.NewTime(creation),
+			ManagedFields: []v1.ManagedFieldsEntry{
+				{Manager: "kubectl", Time: &specChange},
+				{Manager: "manager", Subresource: "status", Time: &statusChange},
+			},
+		}}
+		Expect(cluster.GetSpecChangeTimestamp()).To(Equal(specChange.Time))
+	})
+})
+
+var _ = Describe("replication TLS mode", func() {
+	It("defaults to verify-ca", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetReplicationSSLMode()).To(Equal("verify-ca"))
+	})
+
+	It("maps the TLS mode to the libpq sslmode", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			Replication: &ReplicationConfiguration{TLSMode: ReplicationTLSModeVerifyFull},
+		}}
+		Expect(cluster.GetReplicationSSLMode()).To(Equal("verify-full"))
+		cluster.Spec.Replication.TLSMode = ReplicationTLSModeAllowFallback
+		Expect(cluster.GetReplicationSSLMode()).To(Equal("prefer"))
+	})
+})
+
+var _ = Describe("backup verification configuration", func() {
+	It("is disabled by default", func() {
+		Expect((&BackupConfiguration{}).IsVerificationEnabled()).To(BeFalse())
+		Expect((&BackupConfiguration{
+			Verification: &BackupVerificationConfiguration{Enabled: true},
+		}).IsVerificationEnabled()).To(BeFalse())
+		Expect((&BackupConfiguration{
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+			Verification:      &BackupVerificationConfiguration{Enabled: true},
+		}).IsVerificationEnabled()).To(BeTrue())
+	})
+
+	It("has default values", func() {
+		var configuration *BackupVerificationConfiguration
+		Expect(configuration.GetInterval()).To(Equal(24 * time.Hour))
+		Expect(configuration.GetMaxParallel()).To(Equal(4))
+
+		configuration = &BackupVerificationConfiguration{Interval: 3600, MaxParallel: 8}
+		Expect(configuration.GetInterval()).To(Equal(time.Hour))
+		Expect(configuration.GetMaxParallel()).To(Equal(8))
+	})
+})
+
+var _ = Describe("Service discovery records", func() {
+	cluster := Cluster{
+		ObjectMeta: v1.ObjectMeta{Name: "clustername", Namespace: "default"},
+		Status:     ClusterStatus{CurrentPrimary: "clustername-1"},
+	}
+
+	It("publishes the host names of the services", func() {
+		Expect(cluster.BuildServiceDiscoveryStatus()).To(Equal(&ServiceDiscoveryStatus{
+			ReadWrite:      "clustername-rw.default.svc",
+			ReadOnly:       "clustername-ro.default.svc",
+			Read:           "clustername-r.default.svc",
+			Port:           5432,
+			CurrentPrimary: "clustername-1.clustername-any.default.svc",
+		}))
+	})
+
+	It("fully qualifies the host names when the DNS domain is configured", func() {
+		qualified := cluster.DeepCopy()
+		qualified.Spec.ServiceDiscovery = &ServiceDiscoveryConfiguration{ClusterDomain: "cluster.local."}
+		status := qualified.BuildServiceDiscoveryStatus()
+		Expect(status.ReadWrite).To(Equal("clustername-rw.default.svc.cluster.local"))
+		Expect(status.CurrentPrimary).To(Equal("clustername-1.clustername-any.default.svc.cluster.local"))
+	})
+
+	It("doesn't publish the primary during the bootstrap", func() {
+		bootstrapping := cluster.DeepCopy()
+		bootstrapping.Status.CurrentPrimary = ""
+		Expect(bootstrapping.BuildServiceDiscoveryStatus().CurrentPrimary).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WAL archiving pause maximum size", func() {
+	It("is half of the size of the volume storing the WAL files by default", func() {
+		cluster := Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{Size: "10Gi"}}}
+		maxSize, err := cluster.GetWalArchivingPauseMaxSize()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(maxSize.String()).To(Equal("5Gi"))
+
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "2Gi"}
+		maxSize, err = cluster.GetWalArchivingPauseMaxSize()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(maxSize.String()).To(Equal("1Gi"))
+	})
+
+	It("is unknown when the size of the volume is not set", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetWalArchivingPauseMaxSize()).To(BeNil())
+	})
+
+	It("can be set by the user", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{utils.WalArchivingPausedMaxSizeAnnotationName: "3Gi"},
+			},
+			Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{Size: "10Gi"}},
+		}
+		maxSize, err := cluster.GetWalArchivingPauseMaxSize()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(maxSize.String()).To(Equal("3Gi"))
+
+		cluster.Annotations[utils.WalArchivingPausedMaxSizeAnnotationName] = "a lot"
+		_, err = cluster.GetWalArchivingPauseMaxSize()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Plugins", func() {
+	cluster := Cluster{
+		Spec: ClusterSpec{
+			Plugins: []PluginConfiguration{
+				{
+					Name:       "pgbackrest",
+					Image:      "pgbackrest:latest",
+					Parameters: map[string]string{"repo": "s3", "retention": "7"},
+				},
+				{
+					Name:          "walg",
+					Image:         "walg:latest",
+					IsWALArchiver: true,
+				},
+			},
+		},
+	}
+
+	It("finds the plugins by name", func() {
+		plugin, ok := cluster.GetPlugin("pgbackrest")
+		Expect(ok).To(BeTrue())
+		Expect(plugin.Image).To(Equal("pgbackrest:latest"))
+
+		_, ok = cluster.GetPlugin("barman")
+		Expect(ok).To(BeFalse())
+
+		Expect(cluster.GetPluginNames()).To(Equal([]string{"pgbackrest", "walg"}))
+	})
+
+	It("finds the WAL archiver", func() {
+		Expect(cluster.GetWALArchiverPlugin().Name).To(Equal("walg"))
+		Expect((&Cluster{}).GetWALArchiverPlugin()).To(BeNil())
+	})
+
+	It("merges the parameters of the requests", func() {
+		Expect(cluster.GetPluginParameters("pgbackrest", map[string]string{"retention": "30", "type": "full"})).
+			To(Equal(map[string]string{"repo": "s3", "retention": "30", "type": "full"}))
+		Expect(cluster.GetPluginParameters("walg", nil)).To(BeEmpty())
+	})
+})
@@ -19,13 +19,18 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -84,8 +89,9 @@ func (r *Cluster) SetDefaults() {
 }
 
 func (r *Cluster) setDefaults(preserveUserSettings bool) {
-	// Defaulting the image name if not specified
-	if r.Spec.ImageName == "" {
+	// Defaulting the image name if not specified, unless
+	// the image is taken from a catalog
+	if r.Spec.ImageName == "" && r.Spec.ImageCatalogRef == nil {
 		r.Spec.ImageName = configuration.Current.PostgresImageName
 	}
 
@@ -120,6 +126,8 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 			UserSettings:                  r.Spec.PostgresConfiguration.Parameters,
 			IsReplicaCluster:              r.IsReplica(),
 			PreserveFixedSettingsFromUser: preserveUserSettings,
+			DefaultArchiveTimeout:         configuration.Current.DefaultArchiveTimeout,
+			ArchiveTimeout:                r.Spec.Backup.GetArchiveTimeout(),
 		}
 		sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
 		r.Spec.PostgresConfiguration.Parameters = sanitizedParameters
@@ -253,7 +261,7 @@ var _ webhook.Validator = &Cluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateCreate() error {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	allErrs := r.Validate()
+	allErrs := append(r.Validate(), r.validateReplicaModeBootstrap()...)
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -275,13 +283,17 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateCerts,
 		r.validateBootstrapMethod,
 		r.validateImageName,
+		r.validateImageCatalogRef,
 		r.validateImagePullPolicy,
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
+		r.validateSynchronousReplicaConfiguration,
 		r.validateStorageSize,
 		r.validateWalStorageSize,
+		r.validateTablespaces,
+		r.validateBackupVolumeAttributesClasses,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateBootstrapRecoverySource,
@@ -290,9 +302,34 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
+		r.validatePlugins,
 		r.validateConfiguration,
 		r.validateLDAP,
 		r.validateReplicationSlots,
+		r.validateReadOnlyService,
+		r.validateFailoverWitness,
+		r.validateIsolationCheck,
+		r.validateInstanceNaming,
+		r.validateServicesIPFamilies,
+		r.validatePgHBARules,
+		r.validatePgIdent,
+		r.validateConnections,
+		r.validateRecoveryTuning,
+		r.validateExpiration,
+		r.validateRecoveryAnonymization,
+		r.validateExtensions,
+		r.validateInstanceHooks,
+		r.validateSynchronousCommit,
+		r.validateDatabaseDefaults,
+		r.validateLogicalDecoding,
+		r.validateInstanceOverrides,
+		r.validateRestrictedReplicas,
+		r.validateDeletionPolicy,
+		r.validatePromotionPriorities,
+		r.validateReattachStrategy,
+		r.validateFencedInstances,
+		r.validateMaintenanceWindows,
+		r.validateVelero,
 	}
 
 	for _, validate := range validations {
@@ -332,13 +369,19 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 			"old", old)
 		return nil
 	}
-	allErrs = append(allErrs, r.validateImageChange(old.Spec.ImageName)...)
+	if r.Spec.ImageCatalogRef != nil || old.Spec.ImageCatalogRef != nil {
+		allErrs = append(allErrs, r.validateImageCatalogChange(old)...)
+	} else {
+		allErrs = append(allErrs, r.validateImageChange(old.Spec.ImageName)...)
+	}
 	allErrs = append(allErrs, r.validateConfigurationChange(old)...)
 	allErrs = append(allErrs, r.validateStorageChange(old)...)
 	allErrs = append(allErrs, r.validateWalStorageChange(old)...)
-	allErrs = append(allErrs, r.validateReplicaModeChange(old)...)
+	allErrs = append(allErrs, r.validateTablespacesChange(old)...)
 	allErrs = append(allErrs, r.validateUnixPermissionIdentifierChange(old)...)
 	allErrs = append(allErrs, r.validateReplicationSlotsChange(old)...)
+	allErrs = append(allErrs, r.validateInstanceNamingChange(old)...)
+	allErrs = append(allErrs, r.validateServicesIPFamiliesChange(old)...)
 	return allErrs
 }
 
@@ -406,28 +449,118 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
-	if initDBOptions.PostInitApplicationSQLRefs != nil {
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "secretRefs"),
-						item,
-						"key and name must be specified"))
-			}
-		}
+	result = append(result, r.validateInitDBLocaleProvider()...)
 
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.ConfigMapRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "configMapRefs"),
-						item,
-						"key and name must be specified"))
-			}
-		}
+	result = append(result, validateSQLRefs(
+		initDBOptions.PostInitApplicationSQLRefs,
+		field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs"))...)
+
+	return result
+}
+
+// validateSQLRefs checks that the references to the SQL scripts stored
+// in Secrets and ConfigMaps are complete
+func validateSQLRefs(refs *PostInitApplicationSQLRefs, path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if refs == nil {
+		return result
+	}
+
+	for idx, item := range refs.SecretRefs {
+		result = append(result, validateKeySelector(path.Child("secretRefs").Index(idx), item.Name, item.Key)...)
+	}
+
+	for idx, item := range refs.ConfigMapRefs {
+		result = append(result, validateKeySelector(path.Child("configMapRefs").Index(idx), item.Name, item.Key)...)
+	}
+
+	return result
+}
+
+// validateKeySelector checks that both the name and the key of a
+// reference to a Secret or a ConfigMap are specified
+func validateKeySelector(path *field.Path, name, key string) field.ErrorList {
+	var result field.ErrorList
+
+	if name == "" {
+		result = append(result, field.Required(path.Child("name"), "key and name must be specified"))
+	}
+	if key == "" {
+		result = append(result, field.Required(path.Child("key"), "key and name must be specified"))
+	}
+
+	return result
+}
+
+// validateRecoveryAnonymization checks that the anonymization of the
+// recovered data has something to execute, and that it is not requested
+// for a replica cluster, which must be a copy of its source
+func (r *Cluster) validateRecoveryAnonymization() field.ErrorList {
+	anonymization := r.GetRecoveryAnonymization()
+	if anonymization == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "bootstrap", "recovery", "anonymization")
+
+	if r.IsReplica() {
+		result = append(result, field.Forbidden(
+			path,
+			"the anonymization is not supported by replica clusters"))
+	}
+
+	if !anonymization.UseAnonymizerExtension && !r.ShouldRecoveryRunAnonymizationSQLRefs() {
+		result = append(result, field.Required(
+			path,
+			"at least one of useAnonymizerExtension and sqlRefs is required"))
+	}
+
+	return append(result, validateSQLRefs(anonymization.SQLRefs, path.Child("sqlRefs"))...)
+}
+
+// validateInitDBLocaleProvider checks the consistency of the ICU related
+// initdb options, which are only supported since PostgreSQL 15
+func (r *Cluster) validateInitDBLocaleProvider() field.ErrorList {
+	var result field.ErrorList
+
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	if initDBOptions.LocaleProvider == "" && initDBOptions.ICULocale == "" {
+		return result
+	}
+
+	if initDBOptions.LocaleProvider == "icu" && initDBOptions.ICULocale == "" {
+		result = append(
+			result,
+			field.Required(
+				field.NewPath("spec", "bootstrap", "initdb", "icuLocale"),
+				"icuLocale is required when localeProvider is icu"))
+	}
+
+	if initDBOptions.LocaleProvider != "icu" && initDBOptions.ICULocale != "" {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "icuLocale"),
+				initDBOptions.ICULocale,
+				"icuLocale can be specified only when localeProvider is icu"))
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	if psqlVersion < 150000 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "localeProvider"),
+				initDBOptions.LocaleProvider,
+				"localeProvider and icuLocale require PostgreSQL 15 or above"))
 	}
 
 	return result
@@ -617,6 +750,10 @@ func (r *Cluster) validateCerts() field.ErrorList {
 		return result
 	}
 
+	if certificates.CertManager != nil {
+		return validateCertManager(certificates)
+	}
+
 	if certificates.ServerTLSSecret != "" {
 		// Currently names are not validated, maybe add this check in future
 		if len(certificates.ServerAltDNSNames) != 0 {
@@ -652,6 +789,43 @@ func (r *Cluster) validateCerts() field.ErrorList {
 	return result
 }
 
+// validateCertManager validates the delegation of the certificates to
+// cert-manager, which issues both the server and the client certificates
+func validateCertManager(certificates *CertificatesConfiguration) field.ErrorList {
+	var result field.ErrorList
+	path := field.NewPath("spec", "certificates")
+
+	if certificates.CertManager.IssuerRef.Name == "" {
+		result = append(
+			result,
+			field.Required(
+				path.Child("certManager", "issuerRef", "name"),
+				"The name of the issuer is required"))
+	}
+
+	userProvidedSecrets := []struct {
+		field  string
+		secret string
+	}{
+		{field: "serverCASecret", secret: certificates.ServerCASecret},
+		{field: "serverTLSSecret", secret: certificates.ServerTLSSecret},
+		{field: "clientCASecret", secret: certificates.ClientCASecret},
+		{field: "replicationTLSSecret", secret: certificates.ReplicationTLSSecret},
+	}
+	for _, userProvidedSecret := range userProvidedSecrets {
+		if userProvidedSecret.secret != "" {
+			result = append(
+				result,
+				field.Invalid(
+					path.Child(userProvidedSecret.field),
+					userProvidedSecret.secret,
+					"User provided certificates can't be used when the certificates are issued by cert-manager"))
+		}
+	}
+
+	return result
+}
+
 // ValidateSuperuserSecret validate super user secret value
 func (r *Cluster) validateSuperuserSecret() field.ErrorList {
 	var result field.ErrorList
@@ -794,6 +968,46 @@ func (r *Cluster) validateImageName() field.ErrorList {
 	return result
 }
 
+// validateImageCatalogRef validates the reference to the image catalog,
+// which cannot be used together with the image name
+func (r *Cluster) validateImageCatalogRef() field.ErrorList {
+	ref := r.Spec.ImageCatalogRef
+	if ref == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "imageCatalogRef")
+	if r.Spec.ImageName != "" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "imageName"),
+			r.Spec.ImageName,
+			"imageName and imageCatalogRef are mutually exclusive"))
+	}
+
+	if ref.Kind != ImageCatalogKind && ref.Kind != ClusterImageCatalogKind {
+		result = append(result, field.NotSupported(
+			path.Child("kind"),
+			ref.Kind,
+			[]string{ImageCatalogKind, ClusterImageCatalogKind}))
+	}
+
+	if ref.APIGroup == nil || *ref.APIGroup != GroupVersion.Group {
+		result = append(result, field.Invalid(
+			path.Child("apiGroup"),
+			ref.APIGroup,
+			fmt.Sprintf("the image catalogs belong to the %s API group", GroupVersion.Group)))
+	}
+
+	if ref.Name == "" {
+		result = append(result, field.Required(
+			path.Child("name"),
+			"the name of the image catalog is required"))
+	}
+
+	return result
+}
+
 // validateImagePullPolicy validates the image pull policy,
 // ensuring it is one of "Always", "Never" or "IfNotPresent" when defined
 func (r *Cluster) validateImagePullPolicy() field.ErrorList {
@@ -805,15 +1019,17 @@ func (r *Cluster) validateImagePullPolicy() field.ErrorList {
 	default:
 		return append(
 			result,
-			field.Invalid(
+			field.NotSupported(
 				field.NewPath("spec", "imagePullPolicy"),
 				r.Spec.ImagePullPolicy,
-				fmt.Sprintf("invalid imagePullPolicy, if defined must be one of '%s', '%s' or '%s'",
-					v1.PullAlways, v1.PullNever, v1.PullIfNotPresent)))
+				[]string{string(v1.PullAlways), string(v1.PullNever), string(v1.PullIfNotPresent)}))
 	}
 }
 
-// validateConfiguration determines whether a PostgreSQL configuration is valid
+// validateConfiguration determines whether a PostgreSQL configuration is valid.
+// The parameters set through the dedicated sections of the `postgresql`
+// stanza and by the recovery storage profile are validated too, as they are
+// merged with the ones set by the user
 func (r *Cluster) validateConfiguration() field.ErrorList {
 	var result field.ErrorList
 
@@ -823,15 +1039,16 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 		// validateImageName function
 		return result
 	}
+	parameters := r.GetPostgresqlParameters()
 	info := postgres.ConfigurationInfo{
 		Settings:         postgres.CnpgConfigurationSettings,
 		MajorVersion:     psqlVersion,
-		UserSettings:     r.Spec.PostgresConfiguration.Parameters,
+		UserSettings:     parameters,
 		IsReplicaCluster: r.IsReplica(),
 	}
 	sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
 
-	for key, value := range r.Spec.PostgresConfiguration.Parameters {
+	for key, value := range parameters {
 		_, isFixed := postgres.FixedConfigurationParameters[key]
 		sanitizedValue, presentInSanitizedConfiguration := sanitizedParameters[key]
 		if isFixed && (!presentInSanitizedConfiguration || value != sanitizedValue) {
@@ -917,7 +1134,7 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 				field.NewPath("spec", "imageName"),
 				r.Spec.ImageName,
 				fmt.Sprintf("wrong version: %v", err.Error())))
-	} else if !status {
+	} else if !status && !r.isMajorVersionUpgradeAllowed(old, newVersion) {
 		result = append(
 			result,
 			field.Invalid(
@@ -930,6 +1147,88 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 	return result
 }
 
+// validateImageCatalogChange validates the change of the PostgreSQL major
+// version when the image is taken from a catalog, before and/or after the
+// change. The image resolved from the catalog is not known at admission time,
+// so the major versions are compared following the same rules of the images
+func (r *Cluster) validateImageCatalogChange(old *Cluster) field.ErrorList {
+	oldMajor, err := old.getRequestedMajorVersion()
+	if err != nil {
+		// The previous image name was not valid, nothing to compare
+		return nil
+	}
+	newMajor, err := r.getRequestedMajorVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	if oldMajor == newMajor {
+		return nil
+	}
+
+	path := field.NewPath("spec", "imageCatalogRef", "major")
+	if r.Spec.ImageCatalogRef == nil {
+		path = field.NewPath("spec", "imageName")
+	}
+
+	// The rollback of a failed major version upgrade is driven by the image
+	// name, as the catalog could have been changed in the meantime
+	if upgrade := r.Status.MajorVersionUpgrade; upgrade != nil &&
+		upgrade.Phase == MajorVersionUpgradePhaseFailed &&
+		r.Spec.ImageCatalogRef == nil && r.Spec.ImageName == upgrade.SourceImage {
+		return nil
+	}
+
+	if newMajor < oldMajor || !r.IsMajorVersionUpgradeEnabled() || r.IsHibernationRequested() {
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				newMajor,
+				fmt.Sprintf("can't upgrade between PostgreSQL %d and %d", oldMajor, newMajor)),
+		}
+	}
+
+	return nil
+}
+
+// getRequestedMajorVersion gets the PostgreSQL major version requested in
+// the specification, either through the image catalog or the image name
+func (r *Cluster) getRequestedMajorVersion() (int, error) {
+	if r.Spec.ImageCatalogRef != nil {
+		return r.Spec.ImageCatalogRef.Major, nil
+	}
+
+	imageName := r.Spec.ImageName
+	if imageName == "" {
+		imageName = configuration.Current.PostgresImageName
+	}
+
+	return postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(imageName))
+}
+
+// isMajorVersionUpgradeAllowed checks if the image can be changed to
+// a different PostgreSQL major version. This happens when the major
+// version upgrades are enabled, the cluster is not hibernated and the major
+// version increases, or when a failed major version upgrade is being rolled
+// back
+func (r *Cluster) isMajorVersionUpgradeAllowed(old, newVersion string) bool {
+	if upgrade := r.Status.MajorVersionUpgrade; upgrade != nil &&
+		upgrade.Phase == MajorVersionUpgradePhaseFailed &&
+		r.GetRequestedImageName() == upgrade.SourceImage {
+		return true
+	}
+
+	// The upgrade is driven from the running primary instance
+	if !r.IsMajorVersionUpgradeEnabled() || r.IsHibernationRequested() {
+		return false
+	}
+
+	isMajorUpgrade, err := postgres.IsMajorVersionUpgrade(old, newVersion)
+	return err == nil && isMajorUpgrade
+}
+
 // Validate the recovery target to ensure that the mutual exclusivity
 // of options is respected and plus validating the format of targetTime
 // if specified
@@ -1091,6 +1390,56 @@ func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
 	return result
 }
 
+// validateSynchronousReplicaConfiguration validates the synchronous
+// replication configuration, ensuring the number of synchronous standbys
+// can be reached by the listed standbys
+func (r *Cluster) validateSynchronousReplicaConfiguration() field.ErrorList {
+	config := r.Spec.PostgresConfiguration.Synchronous
+	if config == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "synchronous")
+
+	if r.Spec.MinSyncReplicas > 0 || r.Spec.MaxSyncReplicas > 0 {
+		result = append(result, field.Forbidden(
+			path,
+			"cannot be used together with minSyncReplicas and maxSyncReplicas"))
+	}
+
+	externalStandbys := len(config.StandbyNamesPre) + len(config.StandbyNamesPost)
+
+	standbyNames := stringset.New()
+	for _, list := range []struct {
+		name  string
+		names []string
+	}{
+		{name: "standbyNamesPre", names: config.StandbyNamesPre},
+		{name: "standbyNamesPost", names: config.StandbyNamesPost},
+	} {
+		for idx, name := range list.names {
+			if standbyNames.Has(name) {
+				result = append(result, field.Duplicate(path.Child(list.name).Index(idx), name))
+			}
+			standbyNames.Put(name)
+		}
+	}
+
+	localStandbys := r.Spec.Instances - 1
+	if config.MaxStandbyNamesFromCluster != nil && *config.MaxStandbyNamesFromCluster < localStandbys {
+		localStandbys = *config.MaxStandbyNamesFromCluster
+	}
+	if config.Number > localStandbys+externalStandbys {
+		result = append(result, field.Invalid(
+			path.Child("number"),
+			config.Number,
+			"the number of synchronous standbys must not be greater than the listed standbys"))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateStorageSize() field.ErrorList {
 	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
 }
@@ -1105,6 +1454,57 @@ func (r *Cluster) validateWalStorageSize() field.ErrorList {
 	return result
 }
 
+// validateTablespaces validates the names and the storage of the tablespaces
+func (r *Cluster) validateTablespaces() field.ErrorList {
+	var result field.ErrorList
+
+	names := stringset.New()
+	for idx, tablespace := range r.Spec.Tablespaces {
+		namePath := field.NewPath("spec", "tablespaces").Index(idx).Child("name")
+		switch {
+		case strings.HasPrefix(tablespace.Name, "pg_"):
+			result = append(result, field.Invalid(
+				namePath,
+				tablespace.Name,
+				"the names starting with pg_ are reserved for the system tablespaces"))
+		case names.Has(tablespace.Name):
+			result = append(result, field.Duplicate(namePath, tablespace.Name))
+		}
+		names.Put(tablespace.Name)
+
+		result = append(result, validateStorageConfigurationSize(
+			fmt.Sprintf("tablespaces[%d].storage", idx), tablespace.Storage)...)
+	}
+
+	return result
+}
+
+// validateBackupVolumeAttributesClasses checks that the VolumeAttributesClass
+// used during the base backups can be replaced at the end, as Kubernetes
+// doesn't allow removing it from a PVC
+func (r *Cluster) validateBackupVolumeAttributesClasses() field.ErrorList {
+	var result field.ErrorList
+
+	check := func(storagePath *field.Path, storage *StorageConfiguration) {
+		if storage.BackupVolumeAttributesClassName != nil && storage.VolumeAttributesClassName == nil {
+			result = append(result, field.Invalid(
+				storagePath.Child("backupVolumeAttributesClassName"),
+				*storage.BackupVolumeAttributesClassName,
+				"requires volumeAttributesClassName to be set"))
+		}
+	}
+
+	check(field.NewPath("spec", "storage"), &r.Spec.StorageConfiguration)
+	if r.Spec.WalStorage != nil {
+		check(field.NewPath("spec", "walStorage"), r.Spec.WalStorage)
+	}
+	for idx := range r.Spec.Tablespaces {
+		check(field.NewPath("spec", "tablespaces").Index(idx).Child("storage"), &r.Spec.Tablespaces[idx].Storage)
+	}
+
+	return result
+}
+
 func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
 	var result field.ErrorList
 
@@ -1167,11 +1567,16 @@ func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
 		)
 	}
 
-	// We need to make sure that only the size of the volume can change
+	// We need to make sure that only the size and the VolumeAttributesClasses
+	// of the volume can change
 	oldNormalized := old.Spec.WalStorage.DeepCopy()
 	oldNormalized.Size = ""
+	oldNormalized.VolumeAttributesClassName = nil
+	oldNormalized.BackupVolumeAttributesClassName = nil
 	newNormalized := r.Spec.WalStorage.DeepCopy()
 	newNormalized.Size = ""
+	newNormalized.VolumeAttributesClassName = nil
+	newNormalized.BackupVolumeAttributesClassName = nil
 
 	if !reflect.DeepEqual(oldNormalized, newNormalized) {
 		result = append(result, field.Invalid(
@@ -1187,6 +1592,53 @@ func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
 	return append(result, storageErrs...)
 }
 
+// validateTablespacesChange prevents adding and removing tablespaces, as
+// every instance would miss the corresponding PVC, and checks that only the
+// size of their volumes is changed
+func (r *Cluster) validateTablespacesChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if len(old.Spec.Tablespaces) != len(r.Spec.Tablespaces) {
+		return append(result, field.Invalid(
+			field.NewPath("spec", "tablespaces"),
+			r.Spec.Tablespaces,
+			"tablespaces can only be set at cluster creation"))
+	}
+
+	for idx, tablespace := range r.Spec.Tablespaces {
+		tablespacePath := field.NewPath("spec", "tablespaces").Index(idx)
+		oldTablespace := old.GetTablespace(tablespace.Name)
+		if oldTablespace == nil {
+			result = append(result, field.Invalid(
+				tablespacePath.Child("name"),
+				tablespace.Name,
+				"tablespaces can only be set at cluster creation"))
+			continue
+		}
+
+		// Only the size and the VolumeAttributesClasses of the volume can change
+		oldNormalized := oldTablespace.Storage.DeepCopy()
+		oldNormalized.Size = ""
+		oldNormalized.VolumeAttributesClassName = nil
+		oldNormalized.BackupVolumeAttributesClassName = nil
+		newNormalized := tablespace.Storage.DeepCopy()
+		newNormalized.Size = ""
+		newNormalized.VolumeAttributesClassName = nil
+		newNormalized.BackupVolumeAttributesClassName = nil
+		if !reflect.DeepEqual(oldNormalized, newNormalized) {
+			result = append(result, field.Invalid(
+				tablespacePath.Child("storage"),
+				tablespace.Storage,
+				"cannot change the storage of a tablespace after initialization"))
+		}
+
+		result = append(result, validateStorageConfigurationChange(
+			fmt.Sprintf("tablespaces[%d].storage", idx), oldTablespace.Storage, tablespace.Storage)...)
+	}
+
+	return result
+}
+
 // validateStorageConfigurationChange generates an error list by comparing two StorageConfiguration
 func validateStorageConfigurationChange(
 	structPath string,
@@ -1195,6 +1647,14 @@ func validateStorageConfigurationChange(
 ) field.ErrorList {
 	var result field.ErrorList
 
+	// Kubernetes doesn't allow the VolumeAttributesClass of a PVC to be removed
+	if oldStorage.VolumeAttributesClassName != nil && newStorage.VolumeAttributesClassName == nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", structPath, "volumeAttributesClassName"),
+			newStorage.VolumeAttributesClassName,
+			"the VolumeAttributesClass cannot be removed once set"))
+	}
+
 	oldSize, err := resource.ParseQuantity(oldStorage.Size)
 	if err != nil {
 		// Can't read the old size, so can't tell if the new size is greater
@@ -1202,9 +1662,8 @@ func validateStorageConfigurationChange(
 		return result
 	}
 
-	result = append(result, validateStorageConfigurationSize(structPath, newStorage)...)
-	if len(result) != 0 {
-		return result
+	if sizeErrs := validateStorageConfigurationSize(structPath, newStorage); len(sizeErrs) != 0 {
+		return append(result, sizeErrs...)
 	}
 
 	newSize, _ := resource.ParseQuantity(newStorage.Size)
@@ -1272,31 +1731,89 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
 	var result field.ErrorList
 
-	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
+	if externalCluster.ConnectionParameters == nil &&
+		externalCluster.BarmanObjectStore == nil &&
+		externalCluster.PluginConfiguration == nil {
 		result = append(result,
 			field.Invalid(
 				path,
 				externalCluster,
-				"one of connectionParameters and barmanObjectStore is required"))
+				"one of connectionParameters, barmanObjectStore and pluginConfiguration is required"))
+	}
+
+	if externalCluster.PluginConfiguration != nil {
+		if externalCluster.BarmanObjectStore != nil {
+			result = append(result, field.Invalid(
+				path.Child("pluginConfiguration"),
+				externalCluster.PluginConfiguration.Name,
+				"barmanObjectStore and pluginConfiguration are mutually exclusive"))
+		}
+
+		if _, found := r.GetPlugin(externalCluster.PluginConfiguration.Name); !found {
+			result = append(result, field.Invalid(
+				path.Child("pluginConfiguration", "name"),
+				externalCluster.PluginConfiguration.Name,
+				"the plugin must be declared in spec.plugins"))
+		}
 	}
 
 	return result
 }
 
-// Check replica mode is enabled only at cluster creation time
-func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
+// maxPluginNameLength is the maximum length of the name of a plugin,
+// keeping the name of its container a valid DNS label
+const maxPluginNameLength = 56
+
+// validatePlugins validates the plugins running as sidecars of the
+// instances, ensuring that at most one of them archives the WAL files
+func (r *Cluster) validatePlugins() field.ErrorList {
 	var result field.ErrorList
-	// if we are not specifying any replica cluster configuration or disabling it, nothing to do
-	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
-		return result
-	}
 
-	// otherwise if it was not defined before or it was just not enabled, add an error
-	if old.Spec.ReplicaCluster == nil || !old.Spec.ReplicaCluster.Enabled {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "replicaCluster"),
-			r.Spec.ReplicaCluster,
-			"Can not enable replication on existing clusters"))
+	names := stringset.New()
+	walArchivers := 0
+	for idx, plugin := range r.Spec.Plugins {
+		path := field.NewPath("spec", "plugins").Index(idx)
+
+		if errs := validationutil.IsDNS1123Label(plugin.Name); len(errs) > 0 {
+			result = append(result, field.Invalid(
+				path.Child("name"),
+				plugin.Name,
+				"the name of a plugin must be a valid DNS label"))
+		} else if len(plugin.Name) > maxPluginNameLength {
+			result = append(result, field.TooLong(
+				path.Child("name"),
+				plugin.Name,
+				maxPluginNameLength))
+		}
+
+		if names.Has(plugin.Name) {
+			result = append(result, field.Duplicate(path.Child("name"), plugin.Name))
+		}
+		names.Put(plugin.Name)
+
+		if plugin.Image == "" {
+			result = append(result, field.Required(
+				path.Child("image"),
+				"the image of the plugin is required"))
+		}
+
+		if !plugin.IsWALArchiver {
+			continue
+		}
+
+		walArchivers++
+		if walArchivers > 1 {
+			result = append(result, field.Invalid(
+				path.Child("isWALArchiver"),
+				plugin.IsWALArchiver,
+				"only one plugin can archive the WAL files"))
+		}
+		if r.Spec.Backup != nil && r.Spec.Backup.BarmanObjectStore != nil {
+			result = append(result, field.Invalid(
+				path.Child("isWALArchiver"),
+				plugin.IsWALArchiver,
+				"the WAL files can't be archived by a plugin when barmanObjectStore is configured"))
+		}
 	}
 
 	return result
@@ -1322,39 +1839,134 @@ func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.Err
 	return result
 }
 
-// Check if the replica mode is used with an incompatible bootstrap
-// method
-func (r *Cluster) validateReplicaMode() field.ErrorList {
+// validateExpiration checks that the expiration time of the cluster, if
+// set, can be parsed
+func (r *Cluster) validateExpiration() field.ErrorList {
+	value, ok := r.Annotations[utils.ClusterExpirationAnnotationName]
+	if !ok {
+		return nil
+	}
+
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("metadata", "annotations", utils.ClusterExpirationAnnotationName),
+			value,
+			"must be a time in RFC3339 format")}
+	}
+
+	return nil
+}
+
+// validateInstanceNaming checks that the instance names, which are also used
+// as Pod hostnames, are valid DNS labels
+func (r *Cluster) validateInstanceNaming() field.ErrorList {
 	var result field.ErrorList
 
-	if r.Spec.ReplicaCluster == nil {
-		return result
+	if r.Spec.InstanceNamePrefix == "" {
+		return nil
 	}
 
-	if r.Spec.Bootstrap == nil {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "bootstrap"),
-			r.Spec.ReplicaCluster,
-			"bootstrap configuration is required for replica mode"))
-	} else if r.Spec.Bootstrap.PgBaseBackup == nil && r.Spec.Bootstrap.Recovery == nil {
+	if errs := validationutil.IsDNS1035Label(r.Spec.InstanceNamePrefix); len(errs) > 0 {
 		result = append(result, field.Invalid(
-			field.NewPath("spec", "replicaCluster"),
-			r.Spec.ReplicaCluster,
-			"replica mode is compatible only with bootstrap using pg_basebackup or recovery"))
+			field.NewPath("spec", "instanceNamePrefix"),
+			r.Spec.InstanceNamePrefix,
+			"the instance name prefix must be a valid DNS label"))
 	}
-	_, found := r.ExternalCluster(r.Spec.ReplicaCluster.Source)
-	if !found {
-		result = append(
-			result,
-			field.Invalid(
-				field.NewPath("spec", "replicaCluster", "primaryServerName"),
-				r.Spec.ReplicaCluster.Source,
-				fmt.Sprintf("External cluster %v not found", r.Spec.ReplicaCluster.Source)))
+
+	if len(r.Spec.InstanceNamePrefix) > 50 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "instanceNamePrefix"),
+			r.Spec.InstanceNamePrefix,
+			"the maximum length of the instance name prefix is 50 characters"))
 	}
 
 	return result
 }
 
+// validateInstanceNamingChange checks that the instance naming policy is not
+// changed, as the existing Pods and PVCs could not be found anymore and the
+// new names could collide with other instances
+func (r *Cluster) validateInstanceNamingChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if r.GetInstanceNamePrefix() != old.GetInstanceNamePrefix() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "instanceNamePrefix"),
+			r.Spec.InstanceNamePrefix,
+			"instanceNamePrefix is an immutable field in the spec"))
+	}
+
+	if r.Spec.InstanceOrdinalPadding != old.Spec.InstanceOrdinalPadding {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "instanceOrdinalPadding"),
+			r.Spec.InstanceOrdinalPadding,
+			"instanceOrdinalPadding is an immutable field in the spec"))
+	}
+
+	return result
+}
+
+// Check if the replica mode is used with an incompatible bootstrap
+// method
+func (r *Cluster) validateReplicaMode() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.ReplicaCluster == nil {
+		return result
+	}
+
+	if token := r.Spec.ReplicaCluster.PromotionToken; token != "" {
+		if r.Spec.ReplicaCluster.Enabled {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "replicaCluster", "promotionToken"),
+				token,
+				"the promotion token can be used only when disabling the replica mode"))
+		} else if _, err := postgres.DecodeDemotionToken(token); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "replicaCluster", "promotionToken"),
+				token,
+				err.Error()))
+		}
+	}
+
+	_, found := r.ExternalCluster(r.Spec.ReplicaCluster.Source)
+	if !found {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "replicaCluster", "primaryServerName"),
+				r.Spec.ReplicaCluster.Source,
+				fmt.Sprintf("External cluster %v not found", r.Spec.ReplicaCluster.Source)))
+	}
+
+	return result
+}
+
+// validateReplicaModeBootstrap checks that a cluster created in replica
+// mode is bootstrapped from its source. An existing cluster can instead
+// be demoted to a replica cluster whatever its bootstrap method
+func (r *Cluster) validateReplicaModeBootstrap() field.ErrorList {
+	if !r.IsReplica() {
+		return nil
+	}
+
+	if r.Spec.Bootstrap == nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "bootstrap"),
+			r.Spec.ReplicaCluster,
+			"bootstrap configuration is required for replica mode")}
+	}
+
+	if r.Spec.Bootstrap.PgBaseBackup == nil && r.Spec.Bootstrap.Recovery == nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "replicaCluster"),
+			r.Spec.ReplicaCluster,
+			"replica mode is compatible only with bootstrap using pg_basebackup or recovery")}
+	}
+
+	return nil
+}
+
 // validateTolerations check and validate the tolerations field
 // This code is almost a verbatim copy of
 // https://github.com/kubernetes/kubernetes/blob/4d38d21/pkg/apis/core/validation/validation.go#L3147
@@ -1448,11 +2060,10 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 	if r.Spec.Affinity.PodAntiAffinityType != PodAntiAffinityTypePreferred &&
 		r.Spec.Affinity.PodAntiAffinityType != PodAntiAffinityTypeRequired &&
 		r.Spec.Affinity.PodAntiAffinityType != "" {
-		allErrors = append(allErrors, field.Invalid(
+		allErrors = append(allErrors, field.NotSupported(
 			path,
 			r.Spec.Affinity.PodAntiAffinityType,
-			fmt.Sprintf("pod anti-affinity type must be '%s' (default if empty) or '%s'",
-				PodAntiAffinityTypePreferred, PodAntiAffinityTypeRequired),
+			[]string{PodAntiAffinityTypePreferred, PodAntiAffinityTypeRequired},
 		))
 	}
 	return allErrors
@@ -1460,12 +2071,41 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 
 // validateBackupConfiguration validates the backup configuration
 func (r *Cluster) validateBackupConfiguration() field.ErrorList {
-	allErrors := field.ErrorList{}
+	var allErrors field.ErrorList
 
-	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+	if r.Spec.Backup == nil {
 		return nil
 	}
 
+	// The WAL files needed by an online backup are fetched from the
+	// WAL archive while restoring
+	if r.Spec.Backup.VolumeSnapshot.requiresWalArchive() && r.Spec.Backup.BarmanObjectStore == nil {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "volumeSnapshot", "online"),
+			true,
+			"online volume snapshot backups require the WAL archive to be configured in barmanObjectStore",
+		))
+	}
+
+	// The volumes are snapshotted one at a time, and only the snapshot
+	// of a single volume is consistent without an online backup
+	if r.Spec.Backup.VolumeSnapshot != nil && !r.Spec.Backup.VolumeSnapshot.IsOnline() &&
+		(r.ShouldCreateWalArchiveVolume() || len(r.Spec.Tablespaces) > 0) {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "volumeSnapshot", "online"),
+			false,
+			"crash-consistent volume snapshot backups can't be taken when the WAL files "+
+				"or the tablespaces are stored in dedicated volumes",
+		))
+	}
+
+	hooksPath := field.NewPath("spec", "backup", "hooks")
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		// The hooks are also used by the backups taken with the
+		// volumeSnapshot method
+		return append(allErrors, r.Spec.Backup.Hooks.validate(hooksPath)...)
+	}
+
 	credentialsCount := 0
 	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
 		credentialsCount++
@@ -1499,6 +2139,17 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		))
 	}
 
+	// The encrypted base backups are decrypted while being extracted by
+	// tar, which can't decompress the snappy format
+	if barmanObjectStore := r.Spec.Backup.BarmanObjectStore; barmanObjectStore.Encryption != nil &&
+		barmanObjectStore.Data != nil && barmanObjectStore.Data.Compression == CompressionTypeSnappy {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "compression"),
+			barmanObjectStore.Data.Compression,
+			"the snappy compression can't be used together with the client-side encryption",
+		))
+	}
+
 	if r.Spec.Backup.RetentionPolicy != "" {
 		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
 		if err != nil {
@@ -1510,9 +2161,1005 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		}
 	}
 
+	allErrors = append(allErrors, r.Spec.Backup.Hooks.validate(hooksPath)...)
+
 	return allErrors
 }
 
+// requiresWalArchive returns true if the backups taken with the
+// volumeSnapshot method are online
+func (configuration *VolumeSnapshotConfiguration) requiresWalArchive() bool {
+	return configuration != nil && configuration.IsOnline()
+}
+
+// validate checks that every backup hook has a unique name and exactly one
+// action between a SQL statement and a command
+func (hooks *BackupHooks) validate(path *field.Path) field.ErrorList {
+	if hooks == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	validateList := func(hookList []BackupHook, listPath *field.Path) {
+		names := make(map[string]bool, len(hookList))
+		for idx, hook := range hookList {
+			hookPath := listPath.Index(idx)
+			if names[hook.Name] {
+				result = append(result, field.Duplicate(hookPath.Child("name"), hook.Name))
+			}
+			names[hook.Name] = true
+
+			hasSQL := hook.SQL != ""
+			hasCommand := len(hook.Command) > 0
+			if hasSQL == hasCommand {
+				result = append(result, field.Invalid(
+					hookPath,
+					hook.Name,
+					"one and only one of sql and command is required"))
+			}
+			if hasCommand && hook.Database != "" {
+				result = append(result, field.Invalid(
+					hookPath.Child("database"),
+					hook.Database,
+					"database can only be specified together with sql"))
+			}
+		}
+	}
+
+	validateList(hooks.Pre, path.Child("pre"))
+	validateList(hooks.Post, path.Child("post"))
+
+	return result
+}
+
+// validateInstanceHooks checks that the instance hooks have unique names
+// for every event, and that the pre-shutdown hooks leave time to shut down
+// the instance within the stop delay
+func (r *Cluster) validateInstanceHooks() field.ErrorList {
+	hooks := r.Spec.InstanceHooks
+	if hooks == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "instanceHooks")
+	validateList := func(hookList []InstanceHook, listPath *field.Path) {
+		names := make(map[string]bool, len(hookList))
+		for idx, hook := range hookList {
+			if names[hook.Name] {
+				result = append(result, field.Duplicate(listPath.Index(idx).Child("name"), hook.Name))
+			}
+			names[hook.Name] = true
+		}
+	}
+
+	validateList(hooks.PrePromote, path.Child("prePromote"))
+	validateList(hooks.PostPromote, path.Child("postPromote"))
+	validateList(hooks.PreDemote, path.Child("preDemote"))
+	validateList(hooks.PreShutdown, path.Child("preShutdown"))
+
+	// The pre-shutdown hooks can take at most a quarter of the stop delay,
+	// as their duration is subtracted from the time given to the checkpoint
+	// and to the smart shutdown
+	var preShutdownTimeout time.Duration
+	for _, hook := range hooks.PreShutdown {
+		preShutdownTimeout += hook.GetTimeout()
+	}
+	stopDelay := time.Duration(r.GetMaxStopDelay()) * time.Second
+	if maxPreShutdownTimeout := stopDelay / 4; preShutdownTimeout > maxPreShutdownTimeout {
+		result = append(result, field.Invalid(
+			path.Child("preShutdown"),
+			preShutdownTimeout.String(),
+			fmt.Sprintf("the sum of the timeouts of the pre-shutdown hooks must not exceed "+
+				"a quarter of stopDelay (%v)", maxPreShutdownTimeout)))
+	}
+
+	return result
+}
+
+// validateFailoverWitness validates the configuration of the failover witness
+func (r *Cluster) validateFailoverWitness() field.ErrorList {
+	witness := r.Spec.FailoverWitness
+	if witness == nil {
+		return nil
+	}
+
+	if witness.LeaseNamespace != "" && witness.KubeconfigSecret == nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "failoverWitness", "leaseNamespace"),
+			witness.LeaseNamespace,
+			"leaseNamespace can only be specified together with kubeconfigSecret")}
+	}
+
+	return nil
+}
+
+// validateReadOnlyService validates the configuration of the `-ro` service
+func (r *Cluster) validateReadOnlyService() field.ErrorList {
+	configuration := r.GetReadOnlyServiceConfiguration()
+	if configuration == nil || configuration.DelayedReplicaThreshold == nil {
+		return nil
+	}
+
+	if configuration.DelayedReplicaThreshold.Sign() <= 0 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "managed", "services", "readOnly", "delayedReplicaThreshold"),
+			configuration.DelayedReplicaThreshold.String(),
+			"must be greater than zero")}
+	}
+
+	return nil
+}
+
+// validateServicesIPFamilies checks that the IP families of the services
+// are coherent with the IP family policy
+func (r *Cluster) validateServicesIPFamilies() field.ErrorList {
+	var result field.ErrorList
+
+	path := field.NewPath("spec", "managed", "services", "ipFamilies")
+	families := r.GetServicesIPFamilies()
+	for idx, family := range families {
+		if family != v1.IPv4Protocol && family != v1.IPv6Protocol {
+			result = append(result, field.NotSupported(
+				path.Index(idx),
+				family,
+				[]string{string(v1.IPv4Protocol), string(v1.IPv6Protocol)}))
+		}
+	}
+
+	if len(families) == 2 && families[0] == families[1] {
+		result = append(result, field.Duplicate(path.Index(1), families[1]))
+	}
+
+	policy := r.GetServicesIPFamilyPolicy()
+	if len(families) == 2 && (policy == nil || *policy == v1.IPFamilyPolicySingleStack) {
+		result = append(result, field.Invalid(
+			path,
+			families,
+			"two IP families require the PreferDualStack or RequireDualStack IP family policy"))
+	}
+
+	return result
+}
+
+// validateServicesIPFamiliesChange checks that the primary IP family
+// of the services is not changed, as Kubernetes doesn't allow it
+func (r *Cluster) validateServicesIPFamiliesChange(old *Cluster) field.ErrorList {
+	oldFamilies := old.GetServicesIPFamilies()
+	newFamilies := r.GetServicesIPFamilies()
+	if len(oldFamilies) == 0 || len(newFamilies) == 0 || oldFamilies[0] == newFamilies[0] {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(
+		field.NewPath("spec", "managed", "services", "ipFamilies").Index(0),
+		newFamilies[0],
+		"the primary IP family of the services cannot be changed")}
+}
+
+// validate checks that the source references at least a Kubernetes
+// resource, and that its selectors are valid
+func (source *PgHBAAddressesSource) validate(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if source.ConfigMapKeyRef == nil && !source.HasResourceReferences() {
+		result = append(result, field.Required(
+			path,
+			"at least one of configMapKeyRef, namespaceSelector, podSelector, services and networkPolicies is required"))
+	}
+
+	if source.ConfigMapKeyRef != nil && (source.ConfigMapKeyRef.Name == "" || source.ConfigMapKeyRef.Key == "") {
+		result = append(result, field.Required(
+			path.Child("configMapKeyRef"),
+			"the name and the key of the ConfigMap are required"))
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(source.NamespaceSelector); err != nil {
+		result = append(result, field.Invalid(
+			path.Child("namespaceSelector"),
+			source.NamespaceSelector,
+			err.Error()))
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(source.PodSelector); err != nil {
+		result = append(result, field.Invalid(
+			path.Child("podSelector"),
+			source.PodSelector,
+			err.Error()))
+	}
+
+	return result
+}
+
+// pgHBAOptionNameRegex matches the names of the options of the
+// authentication methods
+var pgHBAOptionNameRegex = regexp.MustCompile(`^[a-z_]+$`)
+
+// validatePgHBARules checks that every rule can be rendered into a valid
+// pg_hba.conf entry, and that it doesn't conflict with the entries
+// generated by the operator, which come first
+func (r *Cluster) validatePgHBARules() field.ErrorList {
+	var result field.ErrorList
+
+	identMaps := stringset.New()
+	for _, identMap := range r.Spec.PostgresConfiguration.PgIdent {
+		identMaps.Put(identMap.MapName)
+	}
+
+	for idx, rule := range r.Spec.PostgresConfiguration.PgHBARules {
+		path := field.NewPath("spec", "postgresql", "pg_hba_rules").Index(idx)
+
+		for listIdx, database := range rule.Databases {
+			if !isPgHBAListItem(database) {
+				result = append(result, field.Invalid(
+					path.Child("databases").Index(listIdx),
+					database,
+					"must be a single pg_hba.conf database name"))
+			}
+		}
+
+		for listIdx, user := range rule.Users {
+			if !isPgHBAListItem(user) {
+				result = append(result, field.Invalid(
+					path.Child("users").Index(listIdx),
+					user,
+					"must be a single pg_hba.conf user name"))
+				continue
+			}
+
+			// The entries generated by the operator for these users come
+			// before the ones of the user, and would take precedence
+			if user == StreamingReplicationUser || user == PGBouncerPoolerUserName {
+				result = append(result, field.Forbidden(
+					path.Child("users").Index(listIdx),
+					fmt.Sprintf("the authentication of the %s user is managed by the operator", user)))
+			}
+		}
+
+		switch {
+		case rule.Address != "" && rule.AddressesFrom != nil:
+			result = append(result, field.Invalid(
+				path,
+				rule.Address,
+				"address and addressesFrom cannot be set at the same time"))
+		case rule.Address == "" && rule.AddressesFrom == nil:
+			result = append(result, field.Required(path, "one of address and addressesFrom is required"))
+		case rule.Address != "" && !isPgHBAAddress(rule.Address):
+			result = append(result, field.Invalid(
+				path.Child("address"),
+				rule.Address,
+				"must be a CIDR, a host name or one of all, samehost and samenet"))
+		case rule.AddressesFrom != nil:
+			result = append(result, rule.AddressesFrom.validate(path.Child("addressesFrom"))...)
+		}
+
+		for name, value := range rule.Options {
+			if !pgHBAOptionNameRegex.MatchString(name) {
+				result = append(result, field.Invalid(
+					path.Child("options").Key(name),
+					name,
+					"must be the name of an option of the authentication method"))
+			}
+			if value == "" || strings.ContainsAny(value, "\"\n#") {
+				result = append(result, field.Invalid(
+					path.Child("options").Key(name),
+					value,
+					"must be a non-empty value without quotes, newlines and comments"))
+			}
+		}
+
+		if identMap, ok := rule.Options["map"]; ok && !identMaps.Has(identMap) {
+			result = append(result, field.Invalid(
+				path.Child("options").Key("map"),
+				identMap,
+				"must be a map defined in pg_ident"))
+		}
+	}
+
+	return result
+}
+
+// validatePgIdent checks that every user name map entry can be rendered
+// into a pg_ident.conf line, and doesn't change the map of the operator
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	for idx, identMap := range r.Spec.PostgresConfiguration.PgIdent {
+		path := field.NewPath("spec", "postgresql", "pg_ident").Index(idx)
+
+		if identMap.MapName == "local" {
+			result = append(result, field.Forbidden(
+				path.Child("mapName"),
+				"the local map is reserved to the operator"))
+		}
+
+		fields := []struct{ name, value string }{
+			{"mapName", identMap.MapName},
+			{"systemUsername", identMap.SystemUsername},
+			{"databaseUsername", identMap.DatabaseUsername},
+		}
+		for _, identField := range fields {
+			if identField.value == "" || strings.ContainsAny(identField.value, " \t\n\"#") {
+				result = append(result, field.Invalid(
+					path.Child(identField.name),
+					identField.value,
+					"must be a single pg_ident.conf field"))
+			}
+		}
+	}
+
+	return result
+}
+
+// isPgHBAListItem checks whether the passed value can be an item of
+// the comma separated lists of pg_hba.conf
+func isPgHBAListItem(value string) bool {
+	return value != "" && !strings.ContainsAny(value, " \t\n,\"#")
+}
+
+// isPgHBAAddress checks whether the passed value is a valid address
+// field of pg_hba.conf. A single IP address requires a separate netmask
+// field, and is not accepted
+func isPgHBAAddress(address string) bool {
+	switch address {
+	case "all", "samehost", "samenet":
+		return true
+	}
+
+	if _, _, err := net.ParseCIDR(address); err == nil {
+		return true
+	}
+
+	if net.ParseIP(address) != nil {
+		return false
+	}
+
+	// A host name starting with a dot matches the hosts ending with it
+	return len(validationutil.IsDNS1123Subdomain(strings.TrimPrefix(address, "."))) == 0
+}
+
+// validateExtensions validates the extensions delivered by an image or
+// by a volume
+func (r *Cluster) validateExtensions() field.ErrorList {
+	extensions := r.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "extensions")
+
+	// The library path set by the user is accepted only when it matches
+	// the one built from the extensions
+	value, found := r.Spec.PostgresConfiguration.Parameters["dynamic_library_path"]
+	if found && value != r.GetPostgresqlParameters()["dynamic_library_path"] {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "dynamic_library_path"),
+			value,
+			fmt.Sprintf("Can't be set together with %s", path.String())))
+	}
+
+	if _, err := r.GetPostgresqlVersion(); err != nil {
+		result = append(result, field.Invalid(
+			path,
+			r.GetImageName(),
+			"the extensions require a PostgreSQL image whose version can be detected"))
+	}
+
+	names := make(map[string]bool, len(extensions))
+	for idx, extension := range extensions {
+		if names[extension.Name] {
+			result = append(result, field.Duplicate(path.Index(idx).Child("name"), extension.Name))
+		}
+		names[extension.Name] = true
+
+		if (extension.Image == "") == (extension.VolumeSource == nil) {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				extension.Name,
+				"exactly one of image and volumeSource must be set"))
+		}
+	}
+
+	return result
+}
+
+// validateSynchronousCommit checks that every default synchronous_commit
+// setting has a target, which is not shared with another setting
+func (r *Cluster) validateSynchronousCommit() field.ErrorList {
+	syncCommit := r.Spec.PostgresConfiguration.SynchronousCommit
+	if syncCommit == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "synchronousCommit", "defaults")
+	targets := make(map[SynchronousCommitDefault]bool, len(syncCommit.Defaults))
+	for idx, setting := range syncCommit.Defaults {
+		if setting.Database == "" && setting.Role == "" {
+			result = append(result, field.Required(
+				path.Index(idx),
+				"at least one of database and role is required"))
+			continue
+		}
+
+		target := SynchronousCommitDefault{Database: setting.Database, Role: setting.Role}
+		if targets[target] {
+			result = append(result, field.Duplicate(
+				path.Index(idx),
+				fmt.Sprintf("database %q, role %q", setting.Database, setting.Role)))
+		}
+		targets[target] = true
+	}
+
+	return result
+}
+
+// validateInstanceOverrides validates the overrides of the instances
+// configuration
+func (r *Cluster) validateInstanceOverrides() field.ErrorList {
+	var result field.ErrorList
+	path := field.NewPath("spec", "instanceOverrides")
+	ordinals := make(map[int]bool)
+	for idx, override := range r.Spec.InstanceOverrides {
+		if len(override.Instances) == 0 && override.Role == "" {
+			result = append(result, field.Required(
+				path.Index(idx),
+				"at least one of instances and role is required"))
+		}
+
+		for instanceIdx, ordinal := range override.Instances {
+			instancePath := path.Index(idx).Child("instances").Index(instanceIdx)
+			if ordinal < 1 {
+				result = append(result, field.Invalid(instancePath, ordinal, "the ordinal must be greater than zero"))
+				continue
+			}
+			if ordinals[ordinal] {
+				result = append(result, field.Duplicate(instancePath, ordinal))
+			}
+			ordinals[ordinal] = true
+		}
+
+		if override.StorageSize != "" {
+			if _, err := resource.ParseQuantity(override.StorageSize); err != nil {
+				result = append(result, field.Invalid(
+					path.Index(idx).Child("storageSize"),
+					override.StorageSize,
+					"Size value isn't valid"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateDeletionPolicy validates the deletion policy, ensuring the final
+// backup can be taken
+func (r *Cluster) validateDeletionPolicy() field.ErrorList {
+	if !r.IsFinalBackupEnabled() {
+		return nil
+	}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "deletionPolicy", "finalBackup"),
+			r.Spec.DeletionPolicy.FinalBackup,
+			"the final backup requires the backup section with the barmanObjectStore configuration")}
+	}
+
+	return nil
+}
+
+// restrictedReplicasForbiddenParameters are the parameters whose value on a
+// standby cannot be lower than the one on the primary, and that are thus
+// kept equal on every instance
+var restrictedReplicasForbiddenParameters = []string{
+	"max_connections",
+	"max_prepared_transactions",
+	"max_wal_senders",
+	"max_worker_processes",
+	"max_locks_per_transaction",
+}
+
+// validateReattachStrategy checks that wal_log_hints is not disabled when
+// it is needed by pg_rewind to re-attach the former primaries
+func (r *Cluster) validateReattachStrategy() field.ErrorList {
+	value, found := r.GetPostgresqlParameters()["wal_log_hints"]
+	if !found || !r.IsWalLogHintsRequired() || isPostgresBooleanTrue(value) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "wal_log_hints"),
+			value,
+			"wal_log_hints is required by pg_rewind unless data checksums are enabled, "+
+				"use the reclone re-attach strategy to disable it"),
+	}
+}
+
+// isPostgresBooleanTrue checks whether the passed value of a boolean
+// PostgreSQL parameter enables it
+func isPostgresBooleanTrue(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return false
+	}
+
+	return value == "on" || value == "1" ||
+		strings.HasPrefix("true", value) || strings.HasPrefix("yes", value)
+}
+
+// validatePromotionPriorities checks that every instance has at most one
+// promotion priority
+func (r *Cluster) validatePromotionPriorities() field.ErrorList {
+	var result field.ErrorList
+	path := field.NewPath("spec", "promotionPriorities")
+	ordinals := make(map[int]bool)
+	for idx, item := range r.Spec.PromotionPriorities {
+		if ordinals[item.Instance] {
+			result = append(result, field.Duplicate(path.Index(idx).Child("instance"), item.Instance))
+		}
+		ordinals[item.Instance] = true
+	}
+
+	return result
+}
+
+// validateFencedInstances checks that every fenced instance is listed only
+// once, and that the wildcard is not listed together with other instances
+func (r *Cluster) validateFencedInstances() field.ErrorList {
+	if r.Spec.Managed == nil || len(r.Spec.Managed.FencedInstances) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	fencedInstances := r.Spec.Managed.FencedInstances
+	path := field.NewPath("spec", "managed", "fencedInstances")
+	names := make(map[string]bool, len(fencedInstances))
+	for idx, name := range fencedInstances {
+		switch {
+		case name == "":
+			result = append(result, field.Required(path.Index(idx), "the instance name must not be empty"))
+		case names[name]:
+			result = append(result, field.Duplicate(path.Index(idx), name))
+		}
+		names[name] = true
+	}
+
+	if names[utils.FenceAllServers] && len(fencedInstances) > 1 {
+		result = append(result, field.Invalid(
+			path,
+			fencedInstances,
+			"the wildcard must not be listed together with other instances"))
+	}
+
+	return result
+}
+
+// validateVelero checks that a replica is fenced while being backed up by
+// Velero when the volumes of an instance can't be backed up atomically
+func (r *Cluster) validateVelero() field.ErrorList {
+	if !r.IsVeleroEnabled() || r.Spec.Backup.Velero.FenceReplicas {
+		return nil
+	}
+
+	if r.Spec.WalStorage == nil && len(r.Spec.Tablespaces) == 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "backup", "velero", "fenceReplicas"),
+			r.Spec.Backup.Velero.FenceReplicas,
+			"fenceReplicas is required when the WAL or the tablespaces are stored in separate volumes, "+
+				"as Velero doesn't back up the volumes of an instance atomically"),
+	}
+}
+
+// validateMaintenanceWindows checks that the schedule and the time zone of
+// each maintenance window can be parsed, and that its duration is positive
+func (r *Cluster) validateMaintenanceWindows() field.ErrorList {
+	var result field.ErrorList
+	for idx, window := range r.Spec.MaintenanceWindows {
+		path := field.NewPath("spec", "maintenanceWindows").Index(idx)
+		if _, err := cron.ParseStandard(window.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				path.Child("schedule"),
+				window.Schedule,
+				fmt.Sprintf("invalid schedule: %v", err)))
+		}
+		if window.TimeZone != "" {
+			if _, err := time.LoadLocation(window.TimeZone); err != nil {
+				result = append(result, field.Invalid(
+					path.Child("timeZone"),
+					window.TimeZone,
+					fmt.Sprintf("invalid time zone: %v", err)))
+			}
+		}
+		if window.Duration.Duration <= 0 {
+			result = append(result, field.Invalid(
+				path.Child("duration"),
+				window.Duration.String(),
+				"the duration must be positive"))
+		}
+	}
+
+	return result
+}
+
+// validateRestrictedReplicas validates the configuration of the restricted
+// replicas, ensuring at least one instance can be promoted
+func (r *Cluster) validateRestrictedReplicas() field.ErrorList {
+	if r.Spec.RestrictedReplicas == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "restrictedReplicas")
+	ordinals := make(map[int]bool)
+	for idx, ordinal := range r.Spec.RestrictedReplicas.Instances {
+		if ordinal < 1 {
+			result = append(result, field.Invalid(
+				path.Child("instances").Index(idx), ordinal, "the ordinal must be greater than zero"))
+			continue
+		}
+		if ordinals[ordinal] {
+			result = append(result, field.Duplicate(path.Child("instances").Index(idx), ordinal))
+		}
+		ordinals[ordinal] = true
+	}
+
+	if len(ordinals) >= r.Spec.Instances {
+		result = append(result, field.Invalid(
+			path.Child("instances"),
+			r.Spec.RestrictedReplicas.Instances,
+			"at least one instance must not be restricted"))
+	}
+
+	for key, value := range r.Spec.RestrictedReplicas.Parameters {
+		if _, isFixed := postgres.FixedConfigurationParameters[key]; isFixed {
+			result = append(result, field.Invalid(
+				path.Child("parameters", key), value, "Can't set fixed configuration parameter"))
+			continue
+		}
+		if slices.Contains(restrictedReplicasForbiddenParameters, key) {
+			result = append(result, field.Invalid(
+				path.Child("parameters", key), value,
+				"this parameter must have the same value on every instance"))
+		}
+	}
+
+	return result
+}
+
+// validateLogicalDecoding checks that the logical replication slots have
+// unique names, not clashing with the ones managed for high availability
+func (r *Cluster) validateLogicalDecoding() field.ErrorList {
+	logicalDecoding := r.Spec.PostgresConfiguration.LogicalDecoding
+	if logicalDecoding == nil {
+		return nil
+	}
+
+	haSlotPrefix := DefaultReplicationSlotsHASlotPrefix
+	if r.Spec.ReplicationSlots != nil {
+		haSlotPrefix = r.Spec.ReplicationSlots.HighAvailability.GetSlotPrefix()
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "logicalDecoding", "slots")
+	names := stringset.New()
+	for idx, slot := range logicalDecoding.Slots {
+		switch {
+		case names.Has(slot.Name):
+			result = append(result, field.Duplicate(path.Index(idx).Child("name"), slot.Name))
+		case strings.HasPrefix(slot.Name, haSlotPrefix):
+			result = append(result, field.Invalid(
+				path.Index(idx).Child("name"), slot.Name,
+				fmt.Sprintf("the %s prefix is reserved to the high availability replication slots", haSlotPrefix)))
+		}
+		names.Put(slot.Name)
+	}
+
+	if logicalDecoding.FailoverSlots {
+		result = append(result, r.validateFailoverSlots()...)
+	}
+
+	return result
+}
+
+// validateFailoverSlots checks the prerequisites of the synchronization of
+// the logical replication slots to the replicas
+func (r *Cluster) validateFailoverSlots() field.ErrorList {
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "logicalDecoding", "failoverSlots")
+
+	if psqlVersion, err := r.GetPostgresqlVersion(); err == nil && psqlVersion < 170000 {
+		result = append(result, field.Invalid(
+			path, true, "the failover slots require PostgreSQL 17 or newer"))
+	}
+
+	if r.Spec.ReplicationSlots == nil ||
+		r.Spec.ReplicationSlots.HighAvailability == nil ||
+		!r.Spec.ReplicationSlots.HighAvailability.Enabled {
+		result = append(result, field.Invalid(
+			path, true, "the failover slots require the high availability replication slots"))
+	}
+
+	return result
+}
+
+// parameterNameRegex matches the names of the configuration parameters,
+// including the ones of the extensions which are prefixed by their name
+var parameterNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// validateDatabaseDefaults checks that every database is listed only once,
+// with valid parameter names and privileges matching the object types
+func (r *Cluster) validateDatabaseDefaults() field.ErrorList {
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "databaseDefaults")
+	databases := stringset.New()
+	for idx, database := range r.Spec.PostgresConfiguration.DatabaseDefaults {
+		databasePath := path.Index(idx)
+		switch {
+		case database.Name == "template0":
+			result = append(result, field.Invalid(
+				databasePath.Child("name"), database.Name, "connections to this database are not allowed"))
+		case databases.Has(database.Name):
+			result = append(result, field.Duplicate(databasePath.Child("name"), database.Name))
+		}
+		databases.Put(database.Name)
+
+		for name := range database.Parameters {
+			switch {
+			case !parameterNameRegex.MatchString(name):
+				result = append(result, field.Invalid(
+					databasePath.Child("parameters").Key(name), name, "invalid parameter name"))
+			case name == "synchronous_commit":
+				result = append(result, field.Forbidden(
+					databasePath.Child("parameters").Key(name),
+					"use the synchronousCommit section to set the default synchronous_commit of databases"))
+			}
+		}
+
+		for privilegeIdx, privilege := range database.DefaultPrivileges {
+			result = append(result, privilege.validate(databasePath.Child("defaultPrivileges").Index(privilegeIdx))...)
+		}
+	}
+
+	return result
+}
+
+// validate checks that the privileges can be granted on the object type,
+// and that schemas are not restricted to a schema
+func (privilege DefaultPrivilege) validate(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+	allowed, ok := defaultPrivilegesByObjectType[privilege.ObjectType]
+	if !ok {
+		return append(result, field.NotSupported(
+			path.Child("objectType"), privilege.ObjectType, []string{
+				string(DefaultPrivilegeObjectTypeTables),
+				string(DefaultPrivilegeObjectTypeSequences),
+				string(DefaultPrivilegeObjectTypeFunctions),
+				string(DefaultPrivilegeObjectTypeTypes),
+				string(DefaultPrivilegeObjectTypeSchemas),
+			}))
+	}
+
+	if privilege.ObjectType == DefaultPrivilegeObjectTypeSchemas && privilege.Schema != "" {
+		result = append(result, field.Forbidden(
+			path.Child("schema"), "the default privileges of schemas cannot be restricted to a schema"))
+	}
+
+	for _, name := range privilege.GetPrivileges() {
+		if !slices.Contains(allowed, name) {
+			result = append(result, field.Invalid(
+				path.Child("privileges"), name,
+				fmt.Sprintf("the privilege cannot be granted on %s", privilege.ObjectType)))
+		}
+	}
+
+	return result
+}
+
+// validateConnections validates the connections configuration, ensuring
+// the connection slots fit into the memory available to the instances
+func (r *Cluster) validateConnections() field.ErrorList {
+	connections := r.Spec.PostgresConfiguration.Connections
+	if connections == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "connections")
+
+	managedParameters := []struct {
+		name  string
+		value *int32
+		path  *field.Path
+	}{
+		{"max_connections", connections.MaxConnections, path.Child("maxConnections")},
+		{"superuser_reserved_connections", connections.SuperuserReservedConnections,
+			path.Child("superuserReservedConnections")},
+	}
+	for _, parameter := range managedParameters {
+		if _, found := r.Spec.PostgresConfiguration.Parameters[parameter.name]; found && parameter.value != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", parameter.name),
+				r.Spec.PostgresConfiguration.Parameters[parameter.name],
+				fmt.Sprintf("Can't be set together with %s", parameter.path.String())))
+		}
+	}
+
+	if connections.MaxConnections == nil {
+		return result
+	}
+
+	if connections.SuperuserReservedConnections != nil &&
+		*connections.SuperuserReservedConnections >= *connections.MaxConnections {
+		result = append(result, field.Invalid(
+			path.Child("superuserReservedConnections"),
+			*connections.SuperuserReservedConnections,
+			"must be lower than maxConnections"))
+	}
+
+	memory := r.getInstancesMinimumMemory()
+	if memory.IsZero() {
+		return result
+	}
+
+	parameters := r.GetPostgresqlParameters()
+	sharedBuffers, err := getMemoryParameter(parameters, "shared_buffers",
+		postgres.DefaultSharedBuffers, postgres.SharedBuffersUnit)
+	if err != nil {
+		return append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "shared_buffers"),
+			parameters["shared_buffers"],
+			err.Error()))
+	}
+	workMem, err := getMemoryParameter(parameters, "work_mem",
+		postgres.DefaultWorkMem, postgres.WorkMemUnit)
+	if err != nil {
+		return append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "work_mem"),
+			parameters["work_mem"],
+			err.Error()))
+	}
+
+	// Every connection may use at least work_mem in addition to the
+	// memory shared among the instance processes
+	required := sharedBuffers + int64(*connections.MaxConnections)*workMem
+	if required > memory.Value() {
+		result = append(result, field.Invalid(
+			path.Child("maxConnections"),
+			*connections.MaxConnections,
+			fmt.Sprintf("shared_buffers plus maxConnections times work_mem requires %d bytes, "+
+				"more than the %s of memory available to the instances",
+				required, memory.String())))
+	}
+
+	return result
+}
+
+// getInstancesMinimumMemory gets the lowest memory limit, or request when
+// the limit is not set, among the instances of the cluster, considering
+// the resources overriding the ones of the cluster
+func (r *Cluster) getInstancesMinimumMemory() *resource.Quantity {
+	// The instances not selected by their ordinal take the resources of
+	// their role, or the ones of the cluster
+	serials := []int{0}
+	for _, override := range r.Spec.InstanceOverrides {
+		serials = append(serials, override.Instances...)
+	}
+
+	var result *resource.Quantity
+	for _, serial := range serials {
+		for _, role := range []InstanceOverrideRole{InstanceOverrideRolePrimary, InstanceOverrideRoleReplica} {
+			resources := r.GetInstanceResources(serial, role)
+			memory := resources.Limits.Memory()
+			if memory.IsZero() {
+				memory = resources.Requests.Memory()
+			}
+			if memory.IsZero() {
+				continue
+			}
+			if result == nil || memory.Cmp(*result) < 0 {
+				result = memory
+			}
+		}
+	}
+
+	if result == nil {
+		return &resource.Quantity{}
+	}
+	return result
+}
+
+// validateRecoveryTuning validates the WAL replay settings against the
+// PostgreSQL version in use
+func (r *Cluster) validateRecoveryTuning() field.ErrorList {
+	tuning := r.Spec.PostgresConfiguration.RecoveryTuning
+	if tuning == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "recoveryTuning")
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	managedParameters := []struct {
+		name       string
+		isSet      bool
+		minVersion int
+		path       *field.Path
+	}{
+		{"recovery_prefetch", tuning.Prefetch != "", 150000, path.Child("prefetch")},
+		{"maintenance_io_concurrency", tuning.MaintenanceIOConcurrency != nil, 130000,
+			path.Child("maintenanceIOConcurrency")},
+		{"max_parallel_workers", tuning.MaxParallelWorkers != nil, 0, path.Child("maxParallelWorkers")},
+	}
+	for _, parameter := range managedParameters {
+		if !parameter.isSet {
+			continue
+		}
+
+		// The parameters defaulted by the operator are overridden
+		value, found := r.Spec.PostgresConfiguration.Parameters[parameter.name]
+		if found && value != postgres.CnpgConfigurationSettings.GlobalDefaultSettings[parameter.name] {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", parameter.name),
+				value,
+				fmt.Sprintf("Can't be set together with %s", parameter.path.String())))
+		}
+
+		if psqlVersion < parameter.minVersion {
+			result = append(result, field.Forbidden(
+				parameter.path,
+				fmt.Sprintf("%s is not supported by PostgreSQL %d", parameter.name, psqlVersion/10000)))
+		}
+	}
+
+	if tuning.MaxParallelWorkers != nil {
+		value, found := r.Spec.PostgresConfiguration.Parameters["max_worker_processes"]
+		if !found {
+			value = postgres.CnpgConfigurationSettings.GlobalDefaultSettings["max_worker_processes"]
+		}
+		maxWorkerProcesses, err := strconv.Atoi(value)
+		if err != nil {
+			return result
+		}
+		if int(*tuning.MaxParallelWorkers) > maxWorkerProcesses {
+			result = append(result, field.Invalid(
+				path.Child("maxParallelWorkers"),
+				*tuning.MaxParallelWorkers,
+				fmt.Sprintf("must not be greater than max_worker_processes (%d)", maxWorkerProcesses)))
+		}
+	}
+
+	return result
+}
+
+// getMemoryParameter returns, in bytes, the value of a PostgreSQL memory
+// parameter, using the passed default when it is not set
+func getMemoryParameter(
+	parameters map[string]string,
+	name string,
+	defaultValue string,
+	defaultUnit int64,
+) (int64, error) {
+	value, found := parameters[name]
+	if !found {
+		value = defaultValue
+	}
+	return postgres.ParseMemoryValue(value, defaultUnit)
+}
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	replicationSlots := r.Spec.ReplicationSlots
 	if replicationSlots == nil ||
@@ -1685,3 +3332,20 @@ func (gcs *GoogleCredentials) validateGCSCredentials(path *field.Path) field.Err
 
 	return allErrors
 }
+
+// validateIsolationCheck validates the configuration of the isolation check
+func (r *Cluster) validateIsolationCheck() field.ErrorList {
+	check := r.Spec.IsolationCheck
+	if check == nil || check.PeerQuorum == nil {
+		return nil
+	}
+
+	if int(*check.PeerQuorum) >= r.Spec.Instances {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "isolationCheck", "peerQuorum"),
+			*check.PeerQuorum,
+			"peerQuorum must be lower than the number of instances")}
+	}
+
+	return nil
+}
@@ -18,12 +18,16 @@ package v1
 
 import (
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -225,6 +229,33 @@ var _ = Describe("certificates options validation", func() {
 		result := cluster.validateCerts()
 		Expect(len(result)).To(Equal(1))
 	})
+	It("doesn't complain if the certificates are issued by cert-manager", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerAltDNSNames: []string{"dns-name"},
+					CertManager: &CertManagerConfiguration{
+						IssuerRef: CertManagerIssuerReference{Name: "issuer"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(BeEmpty())
+	})
+	It("complains if cert-manager is used together with user provided certificates", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerCASecret:  "test-server-ca",
+					ServerTLSSecret: "test-server-tls",
+					CertManager:     &CertManagerConfiguration{},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.certificates.certManager.issuerRef.name"))
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -301,6 +332,8 @@ var _ = Describe("initdb options validation", func() {
 
 		result := cluster.validateInitDB()
 		Expect(len(result)).To(Equal(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.postInitApplicationSQLRefs.secretRefs[0].key"))
 	})
 
 	It("complain if name is missing in the secretRefs", func() {
@@ -545,6 +578,8 @@ var _ = Describe("ImagePullPolicy validation", func() {
 
 		result := cluster.validateImagePullPolicy()
 		Expect(len(result)).To(Equal(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		Expect(result[0].Detail).To(Equal(`supported values: "Always", "Never", "IfNotPresent"`))
 	})
 	It("does not complain if the imagePullPolicy is valid", func() {
 		cluster := Cluster{
@@ -575,6 +610,22 @@ var _ = Describe("Defaulting webhook", func() {
 		Expect(cluster.Spec.ImageName).To(Equal("test:13"))
 	})
 
+	It("should derive the archive_timeout from the target RPO", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"archive_timeout": "10min"},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters["archive_timeout"]).To(Equal("10min"))
+
+		cluster.Spec.Backup = &BackupConfiguration{TargetRPO: 120}
+		cluster.Default()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters["archive_timeout"]).To(Equal("60s"))
+	})
+
 	It("should setup the application database name", func() {
 		cluster := Cluster{}
 		cluster.Default()
@@ -756,6 +807,37 @@ var _ = Describe("configuration change validation", func() {
 	})
 })
 
+var _ = Describe("configuration validation", func() {
+	It("complains when a fixed parameter is set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.1",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"data_directory": "/tmp"},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(HaveLen(1))
+	})
+
+	It("validates the parameters set through the dedicated sections", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.1",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters:  map[string]string{"shared_buffers": "1GB"},
+					Connections: &ConnectionsConfiguration{MaxConnections: pointer.Int32(200)},
+					RecoveryTuning: &RecoveryTuningConfiguration{
+						StorageProfile: RecoveryStorageProfileSSD,
+					},
+					Extensions: []ExtensionConfiguration{{Name: "pgvector", Image: "pgvector:0.3.2"}},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("validate image name change", func() {
 	It("doesn't complain with no changes", func() {
 		clusterNew := Cluster{
@@ -790,6 +872,129 @@ var _ = Describe("validate image name change", func() {
 		}
 		Expect(len(clusterNew.validateImageChange("postgres:12.1"))).To(Equal(0))
 	})
+
+	It("allows a major version upgrade when it is enabled", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName:           "postgres:13.0",
+				MajorVersionUpgrade: &MajorVersionUpgradeConfiguration{},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:12.1")).To(BeEmpty())
+	})
+
+	It("complains about a major version upgrade of a hibernated cluster", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName:           "postgres:13.0",
+				MajorVersionUpgrade: &MajorVersionUpgradeConfiguration{},
+				Hibernation:         HibernationOn,
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:12.1")).To(HaveLen(1))
+	})
+
+	It("complains about a major version downgrade even when the upgrades are enabled", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName:           "postgres:12.1",
+				MajorVersionUpgrade: &MajorVersionUpgradeConfiguration{},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:13.0")).To(HaveLen(1))
+	})
+
+	It("allows rolling back the image of a failed major version upgrade", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:12.1",
+			},
+			Status: ClusterStatus{
+				MajorVersionUpgrade: &MajorVersionUpgradeStatus{
+					SourceImage: "postgres:12.1",
+					TargetImage: "postgres:13.0",
+					Phase:       MajorVersionUpgradePhaseFailed,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:13.0")).To(BeEmpty())
+
+		clusterNew.Status.MajorVersionUpgrade.Phase = MajorVersionUpgradePhaseRunning
+		Expect(clusterNew.validateImageChange("postgres:13.0")).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("image catalog validation", func() {
+	newCatalogRef := func(kind string, major int) *ImageCatalogRef {
+		return &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				APIGroup: pointer.String(GroupVersion.Group),
+				Kind:     kind,
+				Name:     "catalog",
+			},
+			Major: major,
+		}
+	}
+
+	It("accepts a reference to an image catalog or a cluster image catalog", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ImageCatalogKind, 15)}}
+		Expect(cluster.validateImageCatalogRef()).To(BeEmpty())
+
+		cluster.Spec.ImageCatalogRef = newCatalogRef(ClusterImageCatalogKind, 15)
+		Expect(cluster.validateImageCatalogRef()).To(BeEmpty())
+	})
+
+	It("doesn't allow using the image name together with the image catalog", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName:       "postgres:15.4",
+				ImageCatalogRef: newCatalogRef(ImageCatalogKind, 15),
+			},
+		}
+		result := cluster.validateImageCatalogRef()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.imageName"))
+	})
+
+	It("complains about a reference to another kind of object", func() {
+		ref := newCatalogRef("ConfigMap", 15)
+		ref.APIGroup = nil
+		cluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: ref}}
+		Expect(cluster.validateImageCatalogRef()).To(HaveLen(2))
+	})
+
+	It("doesn't default the image name when using an image catalog", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ImageCatalogKind, 15)}}
+		cluster.Default()
+		Expect(cluster.Spec.ImageName).To(BeEmpty())
+	})
+
+	It("allows changing the catalog keeping the major version", func() {
+		oldCluster := Cluster{Spec: ClusterSpec{ImageName: "postgres:15.4"}}
+		cluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ClusterImageCatalogKind, 15)}}
+		Expect(cluster.validateImageCatalogChange(&oldCluster)).To(BeEmpty())
+		Expect(oldCluster.validateImageCatalogChange(&cluster)).To(BeEmpty())
+	})
+
+	It("complains about a major version change when the upgrades are not enabled", func() {
+		oldCluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ImageCatalogKind, 14)}}
+		cluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ImageCatalogKind, 15)}}
+		result := cluster.validateImageCatalogChange(&oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.imageCatalogRef.major"))
+	})
+
+	It("allows a major version upgrade when it is enabled", func() {
+		oldCluster := Cluster{Spec: ClusterSpec{ImageCatalogRef: newCatalogRef(ImageCatalogKind, 14)}}
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageCatalogRef:     newCatalogRef(ImageCatalogKind, 15),
+				MajorVersionUpgrade: &MajorVersionUpgradeConfiguration{},
+			},
+		}
+		Expect(cluster.validateImageCatalogChange(&oldCluster)).To(BeEmpty())
+		Expect(oldCluster.validateImageCatalogChange(&cluster)).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("recovery target", func() {
@@ -1225,6 +1430,85 @@ var _ = Describe("storage configuration validation", func() {
 
 		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
 	})
+
+	It("allows changing the VolumeAttributesClass", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					Size:                      "1G",
+					VolumeAttributesClassName: pointer.String("silver"),
+				},
+				WalStorage: &StorageConfiguration{
+					Size: "1G",
+				},
+			},
+		}
+
+		clusterNew := clusterOld.DeepCopy()
+		clusterNew.Spec.StorageConfiguration.VolumeAttributesClassName = pointer.String("gold")
+		clusterNew.Spec.WalStorage.VolumeAttributesClassName = pointer.String("gold")
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
+		Expect(clusterNew.validateWalStorageChange(&clusterOld)).To(BeEmpty())
+	})
+
+	It("complains if the VolumeAttributesClass is being removed", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					Size:                      "1G",
+					VolumeAttributesClassName: pointer.String("silver"),
+				},
+			},
+		}
+
+		clusterNew := clusterOld.DeepCopy()
+		clusterNew.Spec.StorageConfiguration.VolumeAttributesClassName = nil
+
+		result := clusterNew.validateStorageChange(&clusterOld)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.storage.volumeAttributesClassName"))
+	})
+
+	It("allows changing the VolumeAttributesClass used during the base backups", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{
+					Size:                      "1G",
+					VolumeAttributesClassName: pointer.String("silver"),
+				},
+			},
+		}
+
+		clusterNew := clusterOld.DeepCopy()
+		clusterNew.Spec.WalStorage.BackupVolumeAttributesClassName = pointer.String("gold")
+		Expect(clusterNew.validateWalStorageChange(&clusterOld)).To(BeEmpty())
+	})
+
+	It("requires a VolumeAttributesClass to restore at the end of the base backups", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					Size:                            "1G",
+					VolumeAttributesClassName:       pointer.String("silver"),
+					BackupVolumeAttributesClassName: pointer.String("gold"),
+				},
+				Tablespaces: []TablespaceConfiguration{
+					{
+						Name: "fast_disk",
+						Storage: StorageConfiguration{
+							Size:                            "1G",
+							BackupVolumeAttributesClassName: pointer.String("gold"),
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateBackupVolumeAttributesClasses()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.tablespaces[0].storage.backupVolumeAttributesClassName"))
+	})
 })
 
 var _ = Describe("Cluster name validation", func() {
@@ -1366,6 +1650,83 @@ var _ = Describe("validation of an external cluster", func() {
 		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
 		Expect(cluster.validateExternalClusters()).To(BeEmpty())
 	})
+
+	It("accepts the backups stored by a plugin declared in the cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{{Name: "pgbackrest", Image: "pgbackrest:latest"}},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                "origin",
+						PluginConfiguration: &BackupPluginConfiguration{Name: "pgbackrest"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
+		Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		cluster.Spec.ExternalClusters[0].PluginConfiguration.Name = "walg"
+		Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("plugins validation", func() {
+	It("accepts a valid list of plugins", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{
+					{Name: "pgbackrest", Image: "pgbackrest:latest"},
+					{Name: "walg", Image: "walg:latest", IsWALArchiver: true},
+				},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(BeEmpty())
+	})
+
+	It("complains about invalid and duplicate names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{
+					{Name: "pgBackRest", Image: "pgbackrest:latest"},
+					{Name: "walg", Image: "walg:latest"},
+					{Name: "walg", Image: "walg:latest"},
+					{Name: strings.Repeat("a", 57), Image: "walg:latest"},
+				},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(HaveLen(3))
+	})
+
+	It("requires the image", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{{Name: "pgbackrest"}},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(HaveLen(1))
+	})
+
+	It("allows only one WAL archiver, without barmanObjectStore", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{
+					{Name: "pgbackrest", Image: "pgbackrest:latest", IsWALArchiver: true},
+					{Name: "walg", Image: "walg:latest", IsWALArchiver: true},
+				},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(HaveLen(1))
+
+		cluster.Spec.Plugins[1].IsWALArchiver = false
+		cluster.Spec.Backup = &BackupConfiguration{
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+		}
+		Expect(cluster.validatePlugins()).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("bootstrap base backup validation", func() {
@@ -1777,7 +2138,7 @@ var _ = Describe("replica mode validation", func() {
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+		Expect(cluster.validateReplicaModeBootstrap()).ToNot(BeEmpty())
 	})
 
 	It("complains if the initdb bootstrap method is used", func() {
@@ -1797,7 +2158,7 @@ var _ = Describe("replica mode validation", func() {
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+		Expect(cluster.validateReplicaModeBootstrap()).ToNot(BeEmpty())
 	})
 
 	It("is valid when the pg_basebackup bootstrap option is used", func() {
@@ -1817,7 +2178,7 @@ var _ = Describe("replica mode validation", func() {
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
+		result := cluster.validateReplicaModeBootstrap()
 		Expect(result).To(BeEmpty())
 	})
 
@@ -1838,7 +2199,7 @@ var _ = Describe("replica mode validation", func() {
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
+		result := cluster.validateReplicaModeBootstrap()
 		Expect(result).To(BeEmpty())
 	})
 
@@ -1861,18 +2222,15 @@ var _ = Describe("replica mode validation", func() {
 		Expect(result).ToNot(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with no replica mode configured", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{},
-		}
+	It("doesn't require a bootstrap from the source when the replica mode is disabled", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
 				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
+					Enabled: false,
 					Source:  "test",
 				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					InitDB: &BootstrapInitDB{},
 				},
 				ExternalClusters: []ExternalCluster{
 					{Name: "test"},
@@ -1880,32 +2238,43 @@ var _ = Describe("replica mode validation", func() {
 			},
 		}
 		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		Expect(cluster.validateReplicaModeBootstrap()).To(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with replica mode disabled", func() {
+	It("allows the demotion of an existing cluster bootstrapped with initdb", func() {
 		oldCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: false,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					InitDB: &BootstrapInitDB{},
 				},
 				ExternalClusters: []ExternalCluster{
 					{Name: "test"},
 				},
 			},
 		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.ReplicaCluster = &ReplicaClusterConfiguration{
+			Enabled: true,
+			Source:  "test",
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.ValidateChanges(oldCluster)).To(BeEmpty())
+	})
+
+	It("validates the promotion token", func() {
+		token, err := (&postgres.DemotionToken{
+			DatabaseSystemIdentifier:   "7296712156551992651",
+			LatestCheckpointTimelineID: "1",
+			REDOLocation:               "0/5000028",
+		}).Encode()
+		Expect(err).ToNot(HaveOccurred())
+
 		cluster := &Cluster{
 			Spec: ClusterSpec{
 				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Enabled:        false,
+					Source:         "test",
+					PromotionToken: token,
 				},
 				ExternalClusters: []ExternalCluster{
 					{Name: "test"},
@@ -1913,7 +2282,13 @@ var _ = Describe("replica mode validation", func() {
 			},
 		}
 		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+
+		cluster.Spec.ReplicaCluster.PromotionToken = "wrong"
+		Expect(cluster.validateReplicaMode()).To(HaveLen(1))
+
+		cluster.Spec.ReplicaCluster.PromotionToken = token
+		cluster.Spec.ReplicaCluster.Enabled = true
+		Expect(cluster.validateReplicaMode()).To(HaveLen(1))
 	})
 })
 
@@ -1972,37 +2347,155 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(len(err)).To(Equal(2))
 	})
-})
 
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  DefaultMonitoringKey,
-			}))
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  DefaultMonitoringKey,
-			}))
-	})
-	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
-		CustomQueriesConfigMap: []ConfigMapKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			},
-		},
-		CustomQueriesSecret: []SecretKeySelector{
+	It("complain if the encrypted base backups are compressed with snappy", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{AWS: &S3Credentials{InheritFromIAMRole: true}},
+						Data:              &DataBackupConfiguration{Compression: CompressionTypeSnappy},
+						Encryption: &ClientSideEncryptionConfiguration{
+							KeysSecret: LocalObjectReference{Name: "keys"},
+							ActiveKey:  "key",
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.barmanObjectStore.data.compression"))
+
+		cluster.Spec.Backup.BarmanObjectStore.Data.Compression = CompressionTypeGzip
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+	})
+
+	It("complain if online volume snapshot backups have no WAL archive", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					VolumeSnapshot: &VolumeSnapshotConfiguration{},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.volumeSnapshot.online"))
+	})
+
+	It("doesn't complain about crash-consistent volume snapshot backups without a WAL archive", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					VolumeSnapshot: &VolumeSnapshotConfiguration{Online: pointer.Bool(false)},
+				},
+			},
+		}
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+	})
+
+	It("complain about crash-consistent volume snapshot backups of multiple volumes", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{Size: "1Gi"},
+				Backup: &BackupConfiguration{
+					VolumeSnapshot: &VolumeSnapshotConfiguration{Online: pointer.Bool(false)},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.volumeSnapshot.online"))
+
+		cluster.Spec.WalStorage = nil
+		cluster.Spec.Tablespaces = []TablespaceConfiguration{{Name: "idx"}}
+		err = cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.volumeSnapshot.online"))
+	})
+})
+
+var _ = Describe("Backup hooks validation", func() {
+	path := field.NewPath("spec", "backup", "hooks")
+
+	It("doesn't complain if no hook is defined", func() {
+		var hooks *BackupHooks
+		Expect(hooks.validate(path)).To(BeEmpty())
+	})
+
+	It("doesn't complain about valid hooks", func() {
+		hooks := &BackupHooks{
+			Pre: []BackupHook{
+				{Name: "flush", SQL: "SELECT app.flush()", Database: "app"},
+				{Name: "sync", Command: []string{"sync"}},
+			},
+			Post: []BackupHook{
+				{Name: "flush", SQL: "SELECT app.resume()"},
+			},
+		}
+		Expect(hooks.validate(path)).To(BeEmpty())
+	})
+
+	It("complains about duplicated names in the same stage", func() {
+		hooks := &BackupHooks{
+			Pre: []BackupHook{
+				{Name: "flush", SQL: "SELECT 1"},
+				{Name: "flush", SQL: "SELECT 2"},
+			},
+		}
+		Expect(hooks.validate(path)).To(HaveLen(1))
+	})
+
+	It("complains if neither or both of sql and command are specified", func() {
+		hooks := &BackupHooks{
+			Pre: []BackupHook{
+				{Name: "empty"},
+				{Name: "both", SQL: "SELECT 1", Command: []string{"sync"}},
+			},
+		}
+		Expect(hooks.validate(path)).To(HaveLen(2))
+	})
+
+	It("complains if a database is specified for a command", func() {
+		hooks := &BackupHooks{
+			Post: []BackupHook{
+				{Name: "sync", Command: []string{"sync"}, Database: "app"},
+			},
+		}
+		Expect(hooks.validate(path)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  DefaultMonitoringKey,
+			}))
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  DefaultMonitoringKey,
+			}))
+	})
+	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+		CustomQueriesConfigMap: []ConfigMapKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			},
+		},
+		CustomQueriesSecret: []SecretKeySelector{
 			{
 				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
 				Key:                  "test3",
@@ -2274,6 +2767,75 @@ var _ = Describe("validation of imports", func() {
 	})
 })
 
+var _ = Describe("initdb locale provider validation", func() {
+	It("doesn't complain when the ICU locale provider is used with PostgreSQL 15", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						ICULocale:      "en-US",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains when the ICU locale provider is used with PostgreSQL 14", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:14.6",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						ICULocale:      "en-US",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("complains when the ICU locale is missing", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("complains when the ICU locale is set without the ICU locale provider", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "libc",
+						ICULocale:      "en-US",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("validation of replication slots configuration", func() {
 	It("prevents using replication slots on PostgreSQL 10 and older", func() {
 		cluster := &Cluster{
@@ -2387,3 +2949,1031 @@ var _ = Describe("validation of replication slots configuration", func() {
 		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
 	})
 })
+
+var _ = Describe("read-only service validation", func() {
+	It("accepts a positive delayed replica threshold", func() {
+		threshold := resource.MustParse("64Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						ReadOnly: &ReadOnlyServiceConfiguration{DelayedReplicaThreshold: &threshold},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadOnlyService()).To(BeEmpty())
+	})
+
+	It("rejects a delayed replica threshold that is not positive", func() {
+		threshold := resource.MustParse("0")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						ReadOnly: &ReadOnlyServiceConfiguration{DelayedReplicaThreshold: &threshold},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadOnlyService()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("failover witness validation", func() {
+	It("accepts a witness in the namespace of the cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				FailoverWitness: &FailoverWitnessConfiguration{LeaseName: "witness"},
+			},
+		}
+		Expect(cluster.validateFailoverWitness()).To(BeEmpty())
+	})
+
+	It("accepts a lease namespace for a remote witness", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				FailoverWitness: &FailoverWitnessConfiguration{
+					LeaseName:      "witness",
+					LeaseNamespace: "arbiter",
+					KubeconfigSecret: &SecretKeySelector{
+						LocalObjectReference: LocalObjectReference{Name: "witness-kubeconfig"},
+						Key:                  "kubeconfig",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateFailoverWitness()).To(BeEmpty())
+	})
+
+	It("rejects a lease namespace for a local witness", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				FailoverWitness: &FailoverWitnessConfiguration{
+					LeaseName:      "witness",
+					LeaseNamespace: "arbiter",
+				},
+			},
+		}
+		Expect(cluster.validateFailoverWitness()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("isolation check validation", func() {
+	It("accepts a peer quorum lower than the number of instances", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances:      3,
+				IsolationCheck: &IsolationCheckConfiguration{Enabled: true, PeerQuorum: pointer.Int32(2)},
+			},
+		}
+		Expect(cluster.validateIsolationCheck()).To(BeEmpty())
+	})
+
+	It("rejects a peer quorum that cannot be reached", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances:      2,
+				IsolationCheck: &IsolationCheckConfiguration{Enabled: true, PeerQuorum: pointer.Int32(2)},
+			},
+		}
+		Expect(cluster.validateIsolationCheck()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("instance naming validation", func() {
+	It("accepts an empty prefix", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.validateInstanceNaming()).To(BeEmpty())
+	})
+
+	It("complains if the prefix is not a valid DNS label", func() {
+		cluster := &Cluster{Spec: ClusterSpec{InstanceNamePrefix: "PG_legacy"}}
+		Expect(cluster.validateInstanceNaming()).To(HaveLen(1))
+	})
+
+	It("complains if the prefix is too long", func() {
+		cluster := &Cluster{Spec: ClusterSpec{InstanceNamePrefix: strings.Repeat("a", 51)}}
+		Expect(cluster.validateInstanceNaming()).To(HaveLen(1))
+	})
+
+	It("complains if the prefix or the padding are changed", func() {
+		oldCluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.InstanceNamePrefix = "pg-legacy"
+		cluster.Spec.InstanceOrdinalPadding = 2
+		Expect(cluster.validateInstanceNamingChange(oldCluster)).To(HaveLen(2))
+	})
+
+	It("doesn't complain if the prefix is set to the default value", func() {
+		oldCluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.InstanceNamePrefix = "cluster-example"
+		Expect(cluster.validateInstanceNamingChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("services IP families validation", func() {
+	newCluster := func(policy *v1.IPFamilyPolicy, families ...v1.IPFamily) *Cluster {
+		return &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			Services: &ManagedServices{IPFamilyPolicy: policy, IPFamilies: families},
+		}}}
+	}
+	dualStack := v1.IPFamilyPolicyPreferDualStack
+	singleStack := v1.IPFamilyPolicySingleStack
+
+	It("accepts the default configuration", func() {
+		Expect((&Cluster{}).validateServicesIPFamilies()).To(BeEmpty())
+	})
+
+	It("accepts an IPv6 single-stack and a dual-stack configuration", func() {
+		Expect(newCluster(&singleStack, v1.IPv6Protocol).validateServicesIPFamilies()).To(BeEmpty())
+		Expect(newCluster(&dualStack, v1.IPv6Protocol, v1.IPv4Protocol).validateServicesIPFamilies()).To(BeEmpty())
+	})
+
+	It("complains about unknown or duplicated families", func() {
+		Expect(newCluster(&dualStack, "IPv5").validateServicesIPFamilies()).To(HaveLen(1))
+		Expect(newCluster(&dualStack, v1.IPv4Protocol, v1.IPv4Protocol).validateServicesIPFamilies()).To(HaveLen(1))
+	})
+
+	It("complains about two families with a single-stack policy", func() {
+		Expect(newCluster(&singleStack, v1.IPv6Protocol, v1.IPv4Protocol).validateServicesIPFamilies()).To(HaveLen(1))
+		Expect(newCluster(nil, v1.IPv6Protocol, v1.IPv4Protocol).validateServicesIPFamilies()).To(HaveLen(1))
+	})
+
+	It("complains if the primary family is changed", func() {
+		oldCluster := newCluster(&dualStack, v1.IPv4Protocol, v1.IPv6Protocol)
+		Expect(newCluster(&dualStack, v1.IPv6Protocol).validateServicesIPFamiliesChange(oldCluster)).To(HaveLen(1))
+		Expect(newCluster(&singleStack, v1.IPv4Protocol).validateServicesIPFamiliesChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pg_hba rules validation", func() {
+	newCluster := func(rules ...PgHBARule) *Cluster {
+		return &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			PgHBARules: rules,
+			PgIdent:    []PgIdentMap{{MapName: "certs", SystemUsername: "app.example.com", DatabaseUsername: "app"}},
+		}}}
+	}
+
+	It("accepts valid rules", func() {
+		cluster := newCluster(
+			PgHBARule{Databases: []string{"app"}, Users: []string{"app"}, Address: "10.0.0.0/8", Method: "scram-sha-256"},
+			PgHBARule{Type: "hostssl", Address: ".example.com", Method: "cert", Options: map[string]string{"map": "certs"}},
+			PgHBARule{
+				AddressesFrom: &PgHBAAddressesSource{ConfigMapKeyRef: &ConfigMapKeySelector{
+					LocalObjectReference: LocalObjectReference{Name: "office"},
+					Key:                  "cidrs",
+				}},
+				Method: "md5",
+			},
+			PgHBARule{
+				AddressesFrom: &PgHBAAddressesSource{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					Services:          []string{"consumer"},
+				},
+				Databases: []string{"app"},
+				Method:    "scram-sha-256",
+			},
+		)
+		Expect(cluster.validatePgHBARules()).To(BeEmpty())
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
+	})
+
+	It("requires exactly one of address and addressesFrom", func() {
+		Expect(newCluster(PgHBARule{Method: "md5"}).validatePgHBARules()).To(HaveLen(1))
+		Expect(newCluster(PgHBARule{
+			Address:       "all",
+			AddressesFrom: &PgHBAAddressesSource{Services: []string{"consumer"}},
+			Method:        "md5",
+		}).validatePgHBARules()).To(HaveLen(1))
+	})
+
+	It("requires the addresses to be taken from a Kubernetes resource", func() {
+		Expect(newCluster(PgHBARule{
+			AddressesFrom: &PgHBAAddressesSource{},
+			Method:        "md5",
+		}).validatePgHBARules()).To(HaveLen(1))
+		Expect(newCluster(PgHBARule{
+			AddressesFrom: &PgHBAAddressesSource{ConfigMapKeyRef: &ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: "office"},
+			}},
+			Method: "md5",
+		}).validatePgHBARules()).To(HaveLen(1))
+	})
+
+	It("complains about invalid selectors", func() {
+		Expect(newCluster(PgHBARule{
+			AddressesFrom: &PgHBAAddressesSource{PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+			}},
+			Method: "md5",
+		}).validatePgHBARules()).To(HaveLen(1))
+	})
+
+	It("complains about the fields that cannot be rendered in pg_hba.conf", func() {
+		Expect(newCluster(PgHBARule{
+			Databases: []string{"app,other"},
+			Users:     []string{"app user"},
+			Address:   "10.0.0.1",
+			Method:    "md5",
+			Options:   map[string]string{"Bad-Option": "1"},
+		}).validatePgHBARules()).To(HaveLen(4))
+	})
+
+	It("complains about rules shadowed by the ones of the operator", func() {
+		Expect(newCluster(PgHBARule{
+			Users:   []string{StreamingReplicationUser},
+			Address: "all",
+			Method:  "trust",
+		}).validatePgHBARules()).To(HaveLen(1))
+	})
+
+	It("requires the user name maps to be defined", func() {
+		Expect(newCluster(PgHBARule{
+			Type:    "hostssl",
+			Address: "all",
+			Method:  "cert",
+			Options: map[string]string{"map": "unknown"},
+		}).validatePgHBARules()).To(HaveLen(1))
+	})
+
+	It("complains about the user name maps reserved to the operator or not renderable", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			PgIdent: []PgIdentMap{
+				{MapName: "local", SystemUsername: "root", DatabaseUsername: "postgres"},
+				{MapName: "certs", SystemUsername: "", DatabaseUsername: "app"},
+			},
+		}}}
+		Expect(cluster.validatePgIdent()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("connections validation", func() {
+	newCluster := func(maxConnections int32, memory string, parameters map[string]string) *Cluster {
+		cluster := &Cluster{Spec: ClusterSpec{
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: parameters,
+				Connections: &ConnectionsConfiguration{
+					MaxConnections: pointer.Int32(maxConnections),
+				},
+			},
+		}}
+		if memory != "" {
+			cluster.Spec.Resources.Limits = v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse(memory),
+			}
+		}
+		return cluster
+	}
+
+	It("doesn't complain without a connections configuration", func() {
+		Expect((&Cluster{}).validateConnections()).To(BeEmpty())
+	})
+
+	It("accepts connection slots fitting into the available memory", func() {
+		Expect(newCluster(200, "1Gi", nil).validateConnections()).To(BeEmpty())
+		Expect(newCluster(5000, "", nil).validateConnections()).To(BeEmpty())
+	})
+
+	It("complains when the connection slots exceed the available memory", func() {
+		Expect(newCluster(500, "1Gi", nil).validateConnections()).To(HaveLen(1))
+		Expect(newCluster(200, "1Gi", map[string]string{
+			"shared_buffers": "512MB",
+			"work_mem":       "4096",
+		}).validateConnections()).To(HaveLen(1))
+	})
+
+	It("complains about invalid memory parameters", func() {
+		Expect(newCluster(200, "1Gi", map[string]string{"work_mem": "4mb"}).validateConnections()).To(HaveLen(1))
+	})
+
+	It("checks the memory of the instances with overridden resources", func() {
+		cluster := newCluster(200, "1Gi", nil)
+		cluster.Spec.InstanceOverrides = []InstanceOverride{{
+			Instances: []int{3},
+			Resources: &v1.ResourceRequirements{
+				Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		}}
+		Expect(cluster.validateConnections()).To(HaveLen(1))
+
+		cluster.Spec.InstanceOverrides[0].Resources.Limits[v1.ResourceMemory] = resource.MustParse("2Gi")
+		Expect(cluster.validateConnections()).To(BeEmpty())
+	})
+
+	It("complains when the same parameter is set twice", func() {
+		Expect(newCluster(200, "", map[string]string{"max_connections": "100"}).validateConnections()).To(HaveLen(1))
+	})
+
+	It("complains when the reserved connections exceed the maximum", func() {
+		cluster := newCluster(10, "", nil)
+		cluster.Spec.PostgresConfiguration.Connections.SuperuserReservedConnections = pointer.Int32(10)
+		Expect(cluster.validateConnections()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("recovery tuning validation", func() {
+	newCluster := func(imageName string, tuning RecoveryTuningConfiguration, parameters map[string]string) *Cluster {
+		return &Cluster{Spec: ClusterSpec{
+			ImageName: imageName,
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters:     parameters,
+				RecoveryTuning: &tuning,
+			},
+		}}
+	}
+
+	It("accepts settings supported by the PostgreSQL version", func() {
+		Expect(newCluster("postgres:15.1", RecoveryTuningConfiguration{
+			StorageProfile:           RecoveryStorageProfileSSD,
+			Prefetch:                 RecoveryPrefetchTry,
+			MaintenanceIOConcurrency: pointer.Int32(100),
+			MaxParallelWorkers:       pointer.Int32(8),
+		}, map[string]string{"max_parallel_workers": "32"}).validateRecoveryTuning()).To(BeEmpty())
+	})
+
+	It("complains about settings not supported by the PostgreSQL version", func() {
+		Expect(newCluster("postgres:14.6", RecoveryTuningConfiguration{
+			Prefetch: RecoveryPrefetchOn,
+		}, nil).validateRecoveryTuning()).To(HaveLen(1))
+		Expect(newCluster("postgres:12.13", RecoveryTuningConfiguration{
+			MaintenanceIOConcurrency: pointer.Int32(100),
+		}, nil).validateRecoveryTuning()).To(HaveLen(1))
+	})
+
+	It("complains when the same parameter is set twice", func() {
+		Expect(newCluster("postgres:15.1", RecoveryTuningConfiguration{
+			MaintenanceIOConcurrency: pointer.Int32(100),
+		}, map[string]string{"maintenance_io_concurrency": "10"}).validateRecoveryTuning()).To(HaveLen(1))
+	})
+
+	It("complains when the parallel workers exceed the worker processes", func() {
+		Expect(newCluster("postgres:15.1", RecoveryTuningConfiguration{
+			MaxParallelWorkers: pointer.Int32(64),
+		}, nil).validateRecoveryTuning()).To(HaveLen(1))
+		Expect(newCluster("postgres:15.1", RecoveryTuningConfiguration{
+			MaxParallelWorkers: pointer.Int32(64),
+		}, map[string]string{"max_worker_processes": "64"}).validateRecoveryTuning()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("extensions validation", func() {
+	newCluster := func(parameters map[string]string, extensions ...ExtensionConfiguration) *Cluster {
+		return &Cluster{Spec: ClusterSpec{
+			ImageName: "postgres:15.1",
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: parameters,
+				Extensions: extensions,
+			},
+		}}
+	}
+
+	It("accepts extensions delivered by an image or by a volume", func() {
+		Expect(newCluster(nil,
+			ExtensionConfiguration{Name: "pgvector", Image: "pgvector:0.3.2"},
+			ExtensionConfiguration{Name: "postgis", VolumeSource: &v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			}},
+		).validateExtensions()).To(BeEmpty())
+	})
+
+	It("complains when both or none of the sources are set", func() {
+		Expect(newCluster(nil,
+			ExtensionConfiguration{Name: "pgvector"},
+			ExtensionConfiguration{Name: "postgis", Image: "postgis:3", VolumeSource: &v1.VolumeSource{}},
+		).validateExtensions()).To(HaveLen(2))
+	})
+
+	It("complains about duplicate names", func() {
+		Expect(newCluster(nil,
+			ExtensionConfiguration{Name: "pgvector", Image: "pgvector:0.3.2"},
+			ExtensionConfiguration{Name: "pgvector", Image: "pgvector:0.3.1"},
+		).validateExtensions()).To(HaveLen(1))
+	})
+
+	It("complains when the library path is set in the parameters", func() {
+		Expect(newCluster(map[string]string{"dynamic_library_path": "$libdir"},
+			ExtensionConfiguration{Name: "pgvector", Image: "pgvector:0.3.2"},
+		).validateExtensions()).To(HaveLen(1))
+	})
+
+	It("accepts the library path built from the extensions", func() {
+		Expect(newCluster(map[string]string{"dynamic_library_path": "$libdir:/extensions/pgvector/lib"},
+			ExtensionConfiguration{Name: "pgvector", Image: "pgvector:0.3.2"},
+		).validateExtensions()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("expiration validation", func() {
+	It("accepts clusters without an expiration time", func() {
+		Expect((&Cluster{}).validateExpiration()).To(BeEmpty())
+	})
+
+	It("accepts expiration times in RFC3339 format", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			"cnpg.io/expiresAt": "2022-12-01T10:00:00Z",
+		}}}
+		Expect(cluster.validateExpiration()).To(BeEmpty())
+	})
+
+	It("complains about invalid expiration times", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			"cnpg.io/expiresAt": "tomorrow",
+		}}}
+		Expect(cluster.validateExpiration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("recovery anonymization validation", func() {
+	newCluster := func(anonymization RecoveryAnonymization) *Cluster {
+		return &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			Recovery: &BootstrapRecovery{Source: "origin", Anonymization: &anonymization},
+		}}}
+	}
+
+	It("ignores a disabled anonymization", func() {
+		Expect(newCluster(RecoveryAnonymization{}).validateRecoveryAnonymization()).To(BeEmpty())
+	})
+
+	It("accepts an anonymization with something to execute", func() {
+		Expect(newCluster(RecoveryAnonymization{
+			Enabled:                true,
+			UseAnonymizerExtension: true,
+		}).validateRecoveryAnonymization()).To(BeEmpty())
+		Expect(newCluster(RecoveryAnonymization{
+			Enabled: true,
+			SQLRefs: &PostInitApplicationSQLRefs{
+				SecretRefs: []SecretKeySelector{{LocalObjectReference: LocalObjectReference{Name: "mask"}, Key: "sql"}},
+			},
+		}).validateRecoveryAnonymization()).To(BeEmpty())
+	})
+
+	It("complains about an anonymization without anything to execute", func() {
+		Expect(newCluster(RecoveryAnonymization{Enabled: true}).validateRecoveryAnonymization()).To(HaveLen(1))
+	})
+
+	It("complains about incomplete SQL references", func() {
+		Expect(newCluster(RecoveryAnonymization{
+			Enabled: true,
+			SQLRefs: &PostInitApplicationSQLRefs{
+				ConfigMapRefs: []ConfigMapKeySelector{{LocalObjectReference: LocalObjectReference{Name: "mask"}}},
+			},
+		}).validateRecoveryAnonymization()).To(HaveLen(1))
+	})
+
+	It("complains about the anonymization of a replica cluster", func() {
+		cluster := newCluster(RecoveryAnonymization{Enabled: true, UseAnonymizerExtension: true})
+		cluster.Spec.ReplicaCluster = &ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+		Expect(cluster.validateRecoveryAnonymization()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("instance hooks validation", func() {
+	It("accepts a cluster without instance hooks", func() {
+		Expect((&Cluster{}).validateInstanceHooks()).To(BeEmpty())
+	})
+
+	It("complains about duplicate hook names in the same event", func() {
+		cluster := &Cluster{Spec: ClusterSpec{InstanceHooks: &InstanceHooksConfiguration{
+			PrePromote: []InstanceHook{
+				{Name: "dns", Command: []string{"/hooks/dns.sh"}},
+				{Name: "dns", Command: []string{"/hooks/cache.sh"}},
+			},
+			PostPromote: []InstanceHook{
+				{Name: "dns", Command: []string{"/hooks/dns.sh"}},
+			},
+		}}}
+		errs := cluster.validateInstanceHooks()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.instanceHooks.prePromote[1].name"))
+	})
+
+	It("complains when the pre-shutdown hooks would exceed a quarter of the stop delay", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			MaxStopDelay: 120,
+			InstanceHooks: &InstanceHooksConfiguration{
+				PreShutdown: []InstanceHook{
+					{Name: "deregister", Command: []string{"/hooks/deregister.sh"}, Timeout: 25},
+				},
+			},
+		}}
+		Expect(cluster.validateInstanceHooks()).To(BeEmpty())
+
+		cluster.Spec.InstanceHooks.PreShutdown = append(cluster.Spec.InstanceHooks.PreShutdown,
+			InstanceHook{Name: "flush", Command: []string{"/hooks/flush.sh"}})
+		errs := cluster.validateInstanceHooks()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.instanceHooks.preShutdown"))
+	})
+})
+
+var _ = Describe("synchronous_commit defaults validation", func() {
+	newCluster := func(defaults ...SynchronousCommitDefault) *Cluster {
+		return &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			SynchronousCommit: &SynchronousCommitConfiguration{Defaults: defaults},
+		}}}
+	}
+
+	It("accepts the settings of databases, roles and roles in databases", func() {
+		Expect(newCluster(
+			SynchronousCommitDefault{Database: "app", Value: "off"},
+			SynchronousCommitDefault{Role: "reporting", Value: "local"},
+			SynchronousCommitDefault{Database: "app", Role: "reporting", Value: "on"},
+		).validateSynchronousCommit()).To(BeEmpty())
+	})
+
+	It("complains about settings without a target", func() {
+		errs := newCluster(SynchronousCommitDefault{Value: "off"}).validateSynchronousCommit()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.synchronousCommit.defaults[0]"))
+	})
+
+	It("complains about settings with the same target", func() {
+		errs := newCluster(
+			SynchronousCommitDefault{Database: "app", Value: "off"},
+			SynchronousCommitDefault{Database: "app", Value: "on"},
+		).validateSynchronousCommit()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.synchronousCommit.defaults[1]"))
+	})
+})
+
+var _ = Describe("synchronous replication configuration validation", func() {
+	newCluster := func(config SynchronousReplicaConfiguration) *Cluster {
+		return &Cluster{Spec: ClusterSpec{
+			Instances:             3,
+			PostgresConfiguration: PostgresConfiguration{Synchronous: &config},
+		}}
+	}
+
+	It("accepts a required synchronous replication with external standbys", func() {
+		Expect(newCluster(SynchronousReplicaConfiguration{
+			Method:           SynchronousReplicaConfigurationMethodFirst,
+			Number:           3,
+			StandbyNamesPost: []string{"dr-site"},
+			DataDurability:   DataDurabilityLevelRequired,
+		}).validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
+	It("complains when used together with minSyncReplicas and maxSyncReplicas", func() {
+		cluster := newCluster(SynchronousReplicaConfiguration{
+			Method:         SynchronousReplicaConfigurationMethodAny,
+			Number:         1,
+			DataDurability: DataDurabilityLevelPreferred,
+		})
+		cluster.Spec.MaxSyncReplicas = 1
+		errs := cluster.validateSynchronousReplicaConfiguration()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.synchronous"))
+	})
+
+	It("accepts external standbys when the synchronous replication is preferred", func() {
+		errs := newCluster(SynchronousReplicaConfiguration{
+			Method:           SynchronousReplicaConfigurationMethodAny,
+			Number:           1,
+			StandbyNamesPre:  []string{"dr-site"},
+			StandbyNamesPost: []string{"reporting"},
+			DataDurability:   DataDurabilityLevelPreferred,
+		}).validateSynchronousReplicaConfiguration()
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("complains about standby names listed twice", func() {
+		errs := newCluster(SynchronousReplicaConfiguration{
+			Method:           SynchronousReplicaConfigurationMethodAny,
+			Number:           1,
+			StandbyNamesPre:  []string{"dr-site"},
+			StandbyNamesPost: []string{"dr-site"},
+			DataDurability:   DataDurabilityLevelRequired,
+		}).validateSynchronousReplicaConfiguration()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.synchronous.standbyNamesPost[0]"))
+	})
+
+	It("complains when the number of synchronous standbys cannot be reached", func() {
+		maxStandbyNames := 1
+		errs := newCluster(SynchronousReplicaConfiguration{
+			Method:                     SynchronousReplicaConfigurationMethodAny,
+			Number:                     2,
+			MaxStandbyNamesFromCluster: &maxStandbyNames,
+			DataDurability:             DataDurabilityLevelRequired,
+		}).validateSynchronousReplicaConfiguration()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.synchronous.number"))
+	})
+})
+
+var _ = Describe("logical decoding validation", func() {
+	newCluster := func(slots ...LogicalReplicationSlot) *Cluster {
+		return &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			LogicalDecoding: &LogicalDecodingConfiguration{Slots: slots},
+		}}}
+	}
+
+	It("accepts slots with different names", func() {
+		Expect(newCluster(
+			LogicalReplicationSlot{Name: "debezium", Database: "app"},
+			LogicalReplicationSlot{Name: "audit", Database: "app", Plugin: "wal2json"},
+		).validateLogicalDecoding()).To(BeEmpty())
+	})
+
+	It("complains about slots with the same name", func() {
+		errs := newCluster(
+			LogicalReplicationSlot{Name: "debezium", Database: "app"},
+			LogicalReplicationSlot{Name: "debezium", Database: "catalog"},
+		).validateLogicalDecoding()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.logicalDecoding.slots[1].name"))
+	})
+
+	It("complains about slots using the prefix of the high availability ones", func() {
+		cluster := newCluster(LogicalReplicationSlot{Name: "_cnpg_debezium", Database: "app"})
+		Expect(cluster.validateLogicalDecoding()).To(HaveLen(1))
+
+		cluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{SlotPrefix: "_ha_"},
+		}
+		Expect(cluster.validateLogicalDecoding()).To(BeEmpty())
+	})
+
+	It("requires PostgreSQL 17 and the high availability slots for the failover slots", func() {
+		cluster := newCluster(LogicalReplicationSlot{Name: "debezium", Database: "app"})
+		cluster.Spec.PostgresConfiguration.LogicalDecoding.FailoverSlots = true
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.2"
+		Expect(cluster.validateLogicalDecoding()).To(HaveLen(2))
+
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:17.0"
+		cluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{Enabled: true},
+		}
+		Expect(cluster.validateLogicalDecoding()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("database defaults validation", func() {
+	newCluster := func(databases ...DatabaseDefaultsConfiguration) *Cluster {
+		return &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			DatabaseDefaults: databases,
+		}}}
+	}
+
+	It("accepts parameters and default privileges", func() {
+		Expect(newCluster(DatabaseDefaultsConfiguration{
+			Name: "app",
+			Parameters: map[string]string{
+				"search_path":                         `"$user", app, public`,
+				"statement_timeout":                   "30s",
+				"pg_stat_statements.track":            "all",
+				"idle_in_transaction_session_timeout": "10min",
+			},
+			DefaultPrivileges: []DefaultPrivilege{
+				{Schema: "app", ObjectType: "tables", Privileges: []string{"select"}, Grantee: "reporting"},
+				{Role: "app", ObjectType: "sequences", Privileges: []string{"ALL"}, Grantee: "PUBLIC"},
+				{ObjectType: "schemas", Privileges: []string{"USAGE"}, Grantee: "reporting"},
+			},
+		}).validateDatabaseDefaults()).To(BeEmpty())
+	})
+
+	It("complains about databases listed twice", func() {
+		errs := newCluster(
+			DatabaseDefaultsConfiguration{Name: "app"},
+			DatabaseDefaultsConfiguration{Name: "app"},
+		).validateDatabaseDefaults()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.databaseDefaults[1].name"))
+	})
+
+	It("complains about invalid and reserved parameter names", func() {
+		errs := newCluster(DatabaseDefaultsConfiguration{
+			Name: "app",
+			Parameters: map[string]string{
+				"search_path; DROP TABLE x": "public",
+			},
+		}).validateDatabaseDefaults()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+
+		errs = newCluster(DatabaseDefaultsConfiguration{
+			Name:       "app",
+			Parameters: map[string]string{"synchronous_commit": "off"},
+		}).validateDatabaseDefaults()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	})
+
+	It("complains about privileges not matching the object type", func() {
+		errs := newCluster(DatabaseDefaultsConfiguration{
+			Name: "app",
+			DefaultPrivileges: []DefaultPrivilege{
+				{ObjectType: "functions", Privileges: []string{"SELECT"}, Grantee: "reporting"},
+			},
+		}).validateDatabaseDefaults()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.databaseDefaults[0].defaultPrivileges[0].privileges"))
+	})
+
+	It("complains about the default privileges of schemas in a schema", func() {
+		errs := newCluster(DatabaseDefaultsConfiguration{
+			Name: "app",
+			DefaultPrivileges: []DefaultPrivilege{
+				{Schema: "app", ObjectType: "schemas", Privileges: []string{"USAGE"}, Grantee: "reporting"},
+			},
+		}).validateDatabaseDefaults()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.databaseDefaults[0].defaultPrivileges[0].schema"))
+	})
+})
+
+var _ = Describe("instance overrides validation", func() {
+	newCluster := func(overrides ...InstanceOverride) *Cluster {
+		return &Cluster{Spec: ClusterSpec{InstanceOverrides: overrides}}
+	}
+
+	It("accepts overrides selecting the instances by ordinal or by role", func() {
+		Expect(newCluster(
+			InstanceOverride{Instances: []int{2, 3}, StorageSize: "1Ti"},
+			InstanceOverride{Role: InstanceOverrideRoleReplica, StorageSize: "10Gi"},
+		).validateInstanceOverrides()).To(BeEmpty())
+	})
+
+	It("complains about overrides not selecting any instance", func() {
+		errs := newCluster(InstanceOverride{StorageSize: "1Ti"}).validateInstanceOverrides()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.instanceOverrides[0]"))
+	})
+
+	It("complains about invalid or duplicated ordinals", func() {
+		errs := newCluster(
+			InstanceOverride{Instances: []int{0, 2}},
+			InstanceOverride{Instances: []int{2}},
+		).validateInstanceOverrides()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.instanceOverrides[0].instances[0]"))
+		Expect(errs[1].Field).To(Equal("spec.instanceOverrides[1].instances[0]"))
+	})
+
+	It("complains about invalid storage sizes", func() {
+		errs := newCluster(InstanceOverride{Role: InstanceOverrideRolePrimary, StorageSize: "big"}).
+			validateInstanceOverrides()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.instanceOverrides[0].storageSize"))
+	})
+})
+
+var _ = Describe("validation of the restricted replicas", func() {
+	newCluster := func(restricted *RestrictedReplicasConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Instances:          3,
+				RestrictedReplicas: restricted,
+			},
+		}
+	}
+
+	It("accepts a cluster without restricted replicas", func() {
+		Expect(newCluster(nil).validateRestrictedReplicas()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(newCluster(&RestrictedReplicasConfiguration{
+			Instances:  []int{3},
+			Parameters: map[string]string{"work_mem": "256MB", "max_parallel_workers_per_gather": "8"},
+		}).validateRestrictedReplicas()).To(BeEmpty())
+	})
+
+	It("complains about invalid or duplicated ordinals", func() {
+		errs := newCluster(&RestrictedReplicasConfiguration{Instances: []int{0, 2, 2}}).validateRestrictedReplicas()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.restrictedReplicas.instances[0]"))
+		Expect(errs[1].Field).To(Equal("spec.restrictedReplicas.instances[2]"))
+	})
+
+	It("requires at least one instance to be promotable", func() {
+		errs := newCluster(&RestrictedReplicasConfiguration{Instances: []int{1, 2, 3}}).validateRestrictedReplicas()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.restrictedReplicas.instances"))
+	})
+
+	It("complains about parameters that cannot differ between the instances", func() {
+		errs := newCluster(&RestrictedReplicasConfiguration{
+			Instances:  []int{3},
+			Parameters: map[string]string{"max_connections": "1000"},
+		}).validateRestrictedReplicas()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.restrictedReplicas.parameters.max_connections"))
+
+		errs = newCluster(&RestrictedReplicasConfiguration{
+			Instances:  []int{3},
+			Parameters: map[string]string{"archive_mode": "off"},
+		}).validateRestrictedReplicas()
+		Expect(errs).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the deletion policy", func() {
+	It("accepts a cluster without a final backup", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DeletionPolicy: &DeletionPolicyConfiguration{}}}
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+
+	It("requires the object store to take the final backup", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DeletionPolicy: &DeletionPolicyConfiguration{FinalBackup: true}}}
+		errs := cluster.validateDeletionPolicy()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.deletionPolicy.finalBackup"))
+
+		cluster.Spec.Backup = &BackupConfiguration{BarmanObjectStore: &BarmanObjectStoreConfiguration{}}
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("tablespaces validation", func() {
+	newCluster := func(tablespaces ...TablespaceConfiguration) *Cluster {
+		return &Cluster{Spec: ClusterSpec{Tablespaces: tablespaces}}
+	}
+
+	It("accepts tablespaces with a valid storage", func() {
+		cluster := newCluster(
+			TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}},
+			TablespaceConfiguration{Name: "temp", Storage: StorageConfiguration{Size: "1Gi"}, Temporary: true},
+		)
+		Expect(cluster.validateTablespaces()).To(BeEmpty())
+	})
+
+	It("rejects the reserved and the duplicated names", func() {
+		cluster := newCluster(
+			TablespaceConfiguration{Name: "pg_fast", Storage: StorageConfiguration{Size: "1Gi"}},
+			TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}},
+			TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}},
+		)
+		errs := cluster.validateTablespaces()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.tablespaces[0].name"))
+		Expect(errs[1].Field).To(Equal("spec.tablespaces[2].name"))
+	})
+
+	It("requires the size of the tablespaces", func() {
+		Expect(newCluster(TablespaceConfiguration{Name: "fast"}).validateTablespaces()).To(HaveLen(1))
+	})
+
+	It("allows the tablespaces to be resized", func() {
+		oldCluster := newCluster(TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}})
+		cluster := newCluster(TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "2Gi"}})
+		Expect(cluster.validateTablespacesChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("prevents the tablespaces from being added, removed or renamed", func() {
+		oldCluster := newCluster(TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}})
+		Expect(newCluster().validateTablespacesChange(oldCluster)).To(HaveLen(1))
+		Expect(oldCluster.validateTablespacesChange(newCluster())).To(HaveLen(1))
+
+		cluster := newCluster(TablespaceConfiguration{Name: "slow", Storage: StorageConfiguration{Size: "1Gi"}})
+		Expect(cluster.validateTablespacesChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("prevents the storage class of the tablespaces from being changed", func() {
+		storageClass := "fast"
+		oldCluster := newCluster(TablespaceConfiguration{Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}})
+		cluster := newCluster(TablespaceConfiguration{
+			Name: "fast", Storage: StorageConfiguration{Size: "1Gi", StorageClass: &storageClass},
+		})
+		errs := cluster.validateTablespacesChange(oldCluster)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.tablespaces[0].storage"))
+
+		cluster = newCluster(TablespaceConfiguration{
+			Name: "fast", Storage: StorageConfiguration{Size: "1Gi"}, Temporary: true,
+		})
+		Expect(cluster.validateTablespacesChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the promotion priorities", func() {
+	It("accepts a priority for each instance", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PromotionPriorities: []PromotionPriority{
+			{Instance: 1, Priority: 10},
+			{Instance: 2, Priority: -5},
+		}}}
+		Expect(cluster.validatePromotionPriorities()).To(BeEmpty())
+	})
+
+	It("complains about instances having more than one priority", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PromotionPriorities: []PromotionPriority{
+			{Instance: 1, Priority: 10},
+			{Instance: 1, Priority: 5},
+		}}}
+		errs := cluster.validatePromotionPriorities()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.promotionPriorities[1].instance"))
+	})
+})
+
+var _ = Describe("validation of the fenced instances", func() {
+	newCluster := func(fencedInstances ...string) *Cluster {
+		return &Cluster{Spec: ClusterSpec{
+			Managed: &ManagedConfiguration{FencedInstances: fencedInstances},
+		}}
+	}
+
+	It("accepts a list of instances or the wildcard", func() {
+		Expect(newCluster("cluster-example-1", "cluster-example-2").validateFencedInstances()).To(BeEmpty())
+		Expect(newCluster("*").validateFencedInstances()).To(BeEmpty())
+		Expect((&Cluster{}).validateFencedInstances()).To(BeEmpty())
+	})
+
+	It("complains about empty and duplicated instances", func() {
+		errs := newCluster("cluster-example-1", "", "cluster-example-1").validateFencedInstances()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.managed.fencedInstances[1]"))
+		Expect(errs[1].Field).To(Equal("spec.managed.fencedInstances[2]"))
+	})
+
+	It("complains about the wildcard listed with other instances", func() {
+		errs := newCluster("*", "cluster-example-1").validateFencedInstances()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.managed.fencedInstances"))
+	})
+})
+
+var _ = Describe("validation of the re-attach strategy", func() {
+	newCluster := func(strategy ReattachStrategy, walLogHints string) *Cluster {
+		return &Cluster{Spec: ClusterSpec{
+			ReattachStrategy: strategy,
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: map[string]string{"wal_log_hints": walLogHints},
+			},
+		}}
+	}
+
+	It("complains when wal_log_hints is disabled while needed by pg_rewind", func() {
+		errs := newCluster("", "off").validateReattachStrategy()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.parameters.wal_log_hints"))
+		Expect(newCluster(ReattachStrategyRewind, "on").validateReattachStrategy()).To(BeEmpty())
+		Expect(newCluster(ReattachStrategyRewind, "True").validateReattachStrategy()).To(BeEmpty())
+	})
+
+	It("allows wal_log_hints to be disabled when pg_rewind doesn't need it", func() {
+		Expect(newCluster(ReattachStrategyReclone, "off").validateReattachStrategy()).To(BeEmpty())
+
+		cluster := newCluster(ReattachStrategyRewind, "off")
+		cluster.Spec.Bootstrap = &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{DataChecksums: pointer.Bool(true)},
+		}
+		Expect(cluster.validateReattachStrategy()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the maintenance windows", func() {
+	It("accepts valid windows", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			MaintenanceWindows: []MaintenanceWindow{
+				{
+					Schedule: "0 2 * * 6",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+					TimeZone: "Europe/Rome",
+				},
+			},
+		}}
+		Expect(cluster.validateMaintenanceWindows()).To(BeEmpty())
+	})
+
+	It("complains about invalid schedules, time zones and durations", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			MaintenanceWindows: []MaintenanceWindow{
+				{
+					Schedule: "not a schedule",
+					TimeZone: "Nowhere/Nothing",
+				},
+			},
+		}}
+		errs := cluster.validateMaintenanceWindows()
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.maintenanceWindows[0].schedule"))
+		Expect(errs[1].Field).To(Equal("spec.maintenanceWindows[0].timeZone"))
+		Expect(errs[2].Field).To(Equal("spec.maintenanceWindows[0].duration"))
+	})
+})
+
+var _ = Describe("validation of the Velero integration", func() {
+	It("accepts running replicas when the instances have a single volume", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			Backup: &BackupConfiguration{Velero: &VeleroConfiguration{}},
+		}}
+		Expect(cluster.validateVelero()).To(BeEmpty())
+	})
+
+	It("requires fencing the replicas when the WAL has its own volume", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			Backup:     &BackupConfiguration{Velero: &VeleroConfiguration{}},
+			WalStorage: &StorageConfiguration{Size: "1Gi"},
+		}}
+		errs := cluster.validateVelero()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.velero.fenceReplicas"))
+
+		cluster.Spec.Backup.Velero.FenceReplicas = true
+		Expect(cluster.validateVelero()).To(BeEmpty())
+	})
+})
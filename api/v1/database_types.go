@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DatabaseConditionReady is the condition reporting whether the
+	// database has been applied on the primary instance
+	DatabaseConditionReady = "Ready"
+
+	// DatabaseReasonReconciled means that the database is aligned with
+	// its specification
+	DatabaseReasonReconciled = "DatabaseReconciled"
+
+	// DatabaseReasonClusterNotFound means that the referenced cluster
+	// doesn't exist
+	DatabaseReasonClusterNotFound = "ClusterNotFound"
+
+	// DatabaseReasonWaitingForPrimary means that the referenced cluster
+	// has no primary instance which can apply the database
+	DatabaseReasonWaitingForPrimary = "WaitingForPrimary"
+
+	// DatabaseReasonReconciliationFailed means that the primary instance
+	// failed applying the database
+	DatabaseReasonReconciliationFailed = "DatabaseReconciliationFailed"
+
+	// DefaultDatabaseEncoding is the encoding used when creating
+	// a database, unless specified
+	DefaultDatabaseEncoding = "UTF8"
+)
+
+// DatabaseSpec defines the desired state of Database
+type DatabaseSpec struct {
+	// The cluster hosting the database
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The name of the database inside PostgreSQL. This field cannot be changed
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The role owning the database and the schemas created in it.
+	// The role needs to exist
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// The encoding of the database. This field cannot be changed
+	// +kubebuilder:default:=UTF8
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The value of `LC_COLLATE` and `LC_CTYPE` of the database. The locale
+	// of the template database is used when not specified. This field
+	// cannot be changed
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// The extensions to be created in the database. The extensions removed
+	// from this list are not dropped
+	// +optional
+	Extensions []string `json:"extensions,omitempty"`
+
+	// The schemas to be created in the database, owned by the database
+	// owner. The schemas removed from this list are not dropped
+	// +optional
+	Schemas []string `json:"schemas,omitempty"`
+}
+
+// DatabaseStatus defines the observed state of Database
+type DatabaseStatus struct {
+	// The generation of the database specification last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the database specification has been applied
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// The conditions of the database
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired database.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec DatabaseSpec `json:"spec"`
+	// Most recently observed status of the Database. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of databases
+	Items []Database `json:"items"`
+}
+
+// GetEncoding returns the encoding of the database
+func (spec *DatabaseSpec) GetEncoding() string {
+	if spec.Encoding != "" {
+		return spec.Encoding
+	}
+
+	return DefaultDatabaseEncoding
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
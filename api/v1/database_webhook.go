@@ -0,0 +1,142 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+var (
+	// databaseLog is for logging in this package.
+	databaseLog = log.WithName("database-resource").WithValues("version", "v1")
+
+	// reservedDatabaseNames are the databases which are managed by the
+	// operator and cannot be declared
+	reservedDatabaseNames = stringset.From([]string{
+		"postgres",
+		"template0",
+		"template1",
+	})
+)
+
+// SetupWebhookWithManager setup the webhook inside the controller manager
+func (r *Database) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-database,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=databases,versions=v1,name=vdatabase.kb.io,sideEffects=None
+
+var _ webhook.Validator = &Database{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Database) ValidateCreate() error {
+	databaseLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+
+	allErrs := r.Validate()
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: DatabaseKind},
+		r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Database) ValidateUpdate(old runtime.Object) error {
+	databaseLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+
+	oldDatabase := old.(*Database)
+	allErrs := append(r.Validate(), r.validateChanges(oldDatabase)...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: DatabaseKind},
+		r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Database) ValidateDelete() error {
+	databaseLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+// Validate validates the configuration of a Database, returning
+// a list of errors
+func (r *Database) Validate() (allErrs field.ErrorList) {
+	if r.Spec.Cluster.Name == "" {
+		allErrs = append(allErrs,
+			field.Required(
+				field.NewPath("spec", "cluster", "name"),
+				"must specify a cluster name"))
+	}
+
+	if reservedDatabaseNames.Has(r.Spec.Name) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "name"),
+				r.Spec.Name, "the database is managed by the operator"))
+	}
+
+	return allErrs
+}
+
+// validateChanges checks that the fields which cannot be applied to an
+// existing database haven't been changed
+func (r *Database) validateChanges(old *Database) (allErrs field.ErrorList) {
+	if r.Spec.Cluster.Name != old.Spec.Cluster.Name {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "cluster", "name"),
+				r.Spec.Cluster.Name, "the cluster of a database cannot be changed"))
+	}
+
+	if r.Spec.Name != old.Spec.Name {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "name"),
+				r.Spec.Name, "the name of a database cannot be changed"))
+	}
+
+	if r.Spec.GetEncoding() != old.Spec.GetEncoding() {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "encoding"),
+				r.Spec.Encoding, "the encoding of a database cannot be changed"))
+	}
+
+	if r.Spec.Locale != old.Spec.Locale {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "locale"),
+				r.Spec.Locale, "the locale of a database cannot be changed"))
+	}
+
+	return allErrs
+}
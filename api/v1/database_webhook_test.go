@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database validation", func() {
+	newDatabase := func() *Database {
+		return &Database{
+			Spec: DatabaseSpec{
+				Cluster: LocalObjectReference{Name: "cluster-example"},
+				Name:    "catalog",
+				Owner:   "catalog",
+			},
+		}
+	}
+
+	It("accepts a database referencing a cluster", func() {
+		Expect(newDatabase().Validate()).To(BeEmpty())
+	})
+
+	It("requires a cluster name", func() {
+		database := newDatabase()
+		database.Spec.Cluster.Name = ""
+		result := database.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.cluster.name"))
+	})
+
+	It("doesn't allow declaring the databases managed by the operator", func() {
+		database := newDatabase()
+		database.Spec.Name = "template1"
+		result := database.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.name"))
+	})
+
+	It("allows changing the owner, the extensions and the schemas", func() {
+		database := newDatabase()
+		updated := database.DeepCopy()
+		updated.Spec.Owner = "app"
+		updated.Spec.Extensions = []string{"pg_trgm"}
+		updated.Spec.Schemas = []string{"inventory"}
+		Expect(updated.validateChanges(database)).To(BeEmpty())
+	})
+
+	It("doesn't allow changing the fields applied only at creation time", func() {
+		database := newDatabase()
+		updated := database.DeepCopy()
+		updated.Spec.Name = "orders"
+		updated.Spec.Encoding = "LATIN1"
+		updated.Spec.Locale = "C"
+		result := updated.validateChanges(database)
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.name"))
+		Expect(result[1].Field).To(Equal("spec.encoding"))
+		Expect(result[2].Field).To(Equal("spec.locale"))
+	})
+
+	It("considers the default encoding when detecting changes", func() {
+		database := newDatabase()
+		updated := database.DeepCopy()
+		updated.Spec.Encoding = DefaultDatabaseEncoding
+		Expect(updated.validateChanges(database)).To(BeEmpty())
+	})
+})
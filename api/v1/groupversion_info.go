@@ -51,6 +51,21 @@ var (
 	// PoolerKind is the kind name of Poolers
 	PoolerKind = "Pooler"
 
+	// DatabaseKind is the kind name of Databases
+	DatabaseKind = "Database"
+
+	// PublicationKind is the kind name of Publications
+	PublicationKind = "Publication"
+
+	// SubscriptionKind is the kind name of Subscriptions
+	SubscriptionKind = "Subscription"
+
+	// ImageCatalogKind is the kind name of namespaced image catalogs
+	ImageCatalogKind = "ImageCatalog"
+
+	// ClusterImageCatalogKind is the kind name of the cluster-wide image catalogs
+	ClusterImageCatalogKind = "ClusterImageCatalog"
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
 
@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ImageCatalogSpec defines the desired ImageCatalog
+type ImageCatalogSpec struct {
+	// The images available in the catalog, one for each PostgreSQL
+	// major version
+	// +kubebuilder:validation:MinItems=1
+	Images []CatalogImage `json:"images"`
+}
+
+// CatalogImage is the image to be used for a PostgreSQL major version
+type CatalogImage struct {
+	// The image reference
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// The PostgreSQL major version of the image, which must be unique
+	// within the catalog
+	// +kubebuilder:validation:Minimum=10
+	Major int `json:"major"`
+}
+
+// FindImageForMajor finds the image to be used for the passed PostgreSQL
+// major version, if the catalog contains it
+func (spec *ImageCatalogSpec) FindImageForMajor(major int) (string, bool) {
+	for _, entry := range spec.Images {
+		if entry.Major == major {
+			return entry.Image, true
+		}
+	}
+
+	return "", false
+}
+
+// validate checks that each PostgreSQL major version is listed only once,
+// and that the tag of each image, when it contains a version, matches the
+// declared major version
+func (spec *ImageCatalogSpec) validate() (allErrs field.ErrorList) {
+	majors := make(map[int]bool, len(spec.Images))
+	for idx, entry := range spec.Images {
+		path := field.NewPath("spec", "images").Index(idx)
+		if majors[entry.Major] {
+			allErrs = append(allErrs,
+				field.Duplicate(path.Child("major"), entry.Major))
+		}
+		majors[entry.Major] = true
+
+		tag := utils.GetImageTag(entry.Image)
+		tagMajor, err := postgres.GetPostgresMajorVersionFromTag(tag)
+		if err != nil {
+			// The tag doesn't contain a version, i.e. a digest
+			continue
+		}
+		if tagMajor != entry.Major {
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("image"),
+					entry.Image,
+					fmt.Sprintf("the image tag refers to PostgreSQL %d instead of %d", tagMajor, entry.Major)))
+		}
+	}
+
+	return allErrs
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ImageCatalog is the Schema for the imagecatalogs API
+type ImageCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the ImageCatalog.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ImageCatalogSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImageCatalogList contains a list of ImageCatalog
+type ImageCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of image catalogs
+	Items []ImageCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageCatalog{}, &ImageCatalogList{})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// imageCatalogLog is for logging in this package.
+var imageCatalogLog = log.WithName("imagecatalog-resource").WithValues("version", "v1")
+
+// SetupWebhookWithManager setup the webhook inside the controller manager
+func (r *ImageCatalog) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-imagecatalog,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=imagecatalogs,versions=v1,name=vimagecatalog.kb.io,sideEffects=None
+
+var _ webhook.Validator = &ImageCatalog{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ImageCatalog) ValidateCreate() error {
+	imageCatalogLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ImageCatalog) ValidateUpdate(_ runtime.Object) error {
+	imageCatalogLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ImageCatalog) ValidateDelete() error {
+	imageCatalogLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+// validate validates the images listed in the catalog
+func (r *ImageCatalog) validate() error {
+	allErrs := r.Spec.validate()
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: ImageCatalogKind},
+		r.Name, allErrs)
+}
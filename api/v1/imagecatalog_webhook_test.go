@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Image catalog validation", func() {
+	newSpec := func() *ImageCatalogSpec {
+		return &ImageCatalogSpec{
+			Images: []CatalogImage{
+				{Major: 14, Image: "ghcr.io/cloudnative-pg/postgresql:14.9"},
+				{Major: 15, Image: "ghcr.io/cloudnative-pg/postgresql:15.4"},
+			},
+		}
+	}
+
+	It("accepts a catalog with one image for each major version", func() {
+		Expect(newSpec().validate()).To(BeEmpty())
+	})
+
+	It("accepts the images without a version in the tag", func() {
+		spec := newSpec()
+		spec.Images[0].Image = "ghcr.io/cloudnative-pg/postgresql@sha256:" +
+			"3d6a1e3ee9e4bd0e2bc2e96e4e0f5ab1bbd5c26ad4e4c2bbc69c9bbd8aee5a5e"
+		Expect(spec.validate()).To(BeEmpty())
+	})
+
+	It("doesn't allow listing a major version twice", func() {
+		spec := newSpec()
+		spec.Images[1].Major = 14
+		spec.Images[1].Image = "ghcr.io/cloudnative-pg/postgresql:14.8"
+		result := spec.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.images[1].major"))
+	})
+
+	It("doesn't allow an image tag referring to another major version", func() {
+		spec := newSpec()
+		spec.Images[1].Image = "ghcr.io/cloudnative-pg/postgresql:16.0"
+		result := spec.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.images[1].image"))
+	})
+
+	It("finds the image for a major version", func() {
+		spec := newSpec()
+		image, ok := spec.FindImageForMajor(15)
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.4"))
+
+		_, ok = spec.FindImageForMajor(16)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("rejects the invalid image catalogs and cluster image catalogs", func() {
+		spec := newSpec()
+		spec.Images[1].Major = 14
+		Expect((&ImageCatalog{Spec: *spec}).ValidateCreate()).ToNot(Succeed())
+		Expect((&ClusterImageCatalog{Spec: *spec}).ValidateCreate()).ToNot(Succeed())
+		Expect((&ImageCatalog{Spec: *newSpec()}).ValidateCreate()).To(Succeed())
+		Expect((&ClusterImageCatalog{Spec: *newSpec()}).ValidateCreate()).To(Succeed())
+	})
+})
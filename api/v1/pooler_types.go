@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -35,6 +38,10 @@ const (
 
 	// DefaultPgBouncerPoolerAuthQuery is the default auth_query for PgBouncer
 	DefaultPgBouncerPoolerAuthQuery = "SELECT usename, passwd FROM user_search($1)"
+
+	// DefaultPoolerTargetClientConnections is the default number of client
+	// connections each PgBouncer instance handles when autoscaling
+	DefaultPoolerTargetClientConnections = 100
 )
 
 // PgBouncerPoolMode is the mode of PgBouncer
@@ -49,6 +56,27 @@ const (
 	PgBouncerPoolModeTransaction = PgBouncerPoolMode("transaction")
 )
 
+// PoolerLoadBalancing is how PgBouncer balances its server connections
+// across the instances of the cluster
+// +kubebuilder:validation:Enum=service;roundRobin;leastConnections
+type PoolerLoadBalancing string
+
+const (
+	// PoolerLoadBalancingService means that PgBouncer connects to the
+	// service of the cluster, leaving the balancing to Kubernetes
+	PoolerLoadBalancingService = PoolerLoadBalancing("service")
+
+	// PoolerLoadBalancingRoundRobin means that PgBouncer connects directly
+	// to the replicas selected by the `-ro` service, opening its server
+	// connections on each of them in turn
+	PoolerLoadBalancingRoundRobin = PoolerLoadBalancing("roundRobin")
+
+	// PoolerLoadBalancingLeastConnections means that PgBouncer connects
+	// directly to the replicas selected by the `-ro` service, opening its
+	// server connections on the one with the fewest client connections
+	PoolerLoadBalancingLeastConnections = PoolerLoadBalancing("leastConnections")
+)
+
 // PoolerSpec defines the desired state of Pooler
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
@@ -59,6 +87,14 @@ type PoolerSpec struct {
 	// +kubebuilder:default:=rw
 	Type PoolerType `json:"type"`
 
+	// How the server connections of a `ro` pooler are balanced across the
+	// replicas: `service` (default) connects to the `-ro` service, while
+	// `roundRobin` and `leastConnections` connect directly to every replica
+	// selected by it, respectively in turn or preferring the least loaded one
+	// +kubebuilder:default:=service
+	// +optional
+	LoadBalancing PoolerLoadBalancing `json:"loadBalancing,omitempty"`
+
 	// The number of replicas we want
 	// +kubebuilder:default:=1
 	Instances int32 `json:"instances"`
@@ -68,6 +104,78 @@ type PoolerSpec struct {
 
 	// The PgBouncer configuration
 	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
+
+	// The configuration of the monitoring infrastructure of this pooler
+	// +optional
+	Monitoring *PoolerMonitoringConfiguration `json:"monitoring,omitempty"`
+
+	// The configuration of the KEDA `ScaledObject` adjusting the number of
+	// instances of this pooler to the load of PgBouncer
+	// +optional
+	Autoscaling *PoolerAutoscalingConfiguration `json:"autoscaling,omitempty"`
+}
+
+// PoolerAutoscalingConfiguration contains the configuration of the KEDA
+// `ScaledObject` scaling a Pooler depending on the client connections
+// handled by its PgBouncer instances
+type PoolerAutoscalingConfiguration struct {
+	// Enable or disable the `ScaledObject` scaling the pooler
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The minimum number of instances of the pooler
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinInstances int32 `json:"minInstances,omitempty"`
+
+	// The maximum number of instances of the pooler
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int32 `json:"maxInstances"`
+
+	// The number of client connections, either active or waiting for a
+	// server connection, that each PgBouncer instance should handle
+	// +kubebuilder:default:=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetClientConnections int32 `json:"targetClientConnections,omitempty"`
+}
+
+// PoolerMonitoringConfiguration is the type containing all the monitoring
+// configuration for a certain Pooler
+type PoolerMonitoringConfiguration struct {
+	// Enable or disable the `PodMonitor` scraping the metrics exported
+	// by the PgBouncer instances
+	// +kubebuilder:default:=false
+	// +optional
+	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// Enable or disable the `ServiceMonitor` scraping the metrics exported
+	// by the PgBouncer instances through the `-metrics` headless service.
+	// It can be enabled instead of, or together with, the `PodMonitor`
+	// +kubebuilder:default:=false
+	// +optional
+	EnableServiceMonitor bool `json:"enableServiceMonitor,omitempty"`
+}
+
+// GetMinInstances returns the minimum number of instances of the pooler
+func (in PoolerAutoscalingConfiguration) GetMinInstances() int32 {
+	if in.MinInstances > 0 {
+		return in.MinInstances
+	}
+
+	return 1
+}
+
+// GetTargetClientConnections returns the number of client connections
+// each PgBouncer instance should handle
+func (in PoolerAutoscalingConfiguration) GetTargetClientConnections() int32 {
+	if in.TargetClientConnections > 0 {
+		return in.TargetClientConnections
+	}
+
+	return DefaultPoolerTargetClientConnections
 }
 
 // PodTemplateSpec is a structure allowing the user to set
@@ -154,6 +262,18 @@ type PoolerStatus struct {
 	Secrets *PoolerSecrets `json:"secrets,omitempty"`
 	// The number of pods trying to be scheduled
 	Instances int32 `json:"instances,omitempty"`
+	// The label selector of the pods of the pooler, in the string
+	// format used by the `scale` subresource
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// The libpq "sslmode" used by PgBouncer to connect to PostgreSQL,
+	// following the replication TLS mode of the cluster
+	// +optional
+	ServerTLSMode string `json:"serverTLSMode,omitempty"`
+	// The hosts PgBouncer is balancing its server connections across,
+	// used when the `roundRobin` load balancing is enabled
+	// +optional
+	Backends []string `json:"backends,omitempty"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -192,7 +312,7 @@ type SecretVersion struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
-// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances
+// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances,selectorpath=.status.selector
 
 // Pooler is the Schema for the poolers API
 type Pooler struct {
@@ -235,3 +355,67 @@ func (in *Pooler) GetAuthQuery() string {
 
 	return DefaultPgBouncerPoolerAuthQuery
 }
+
+// IsPodMonitorEnabled checks if the PodMonitor object needs to be created
+func (in *Pooler) IsPodMonitorEnabled() bool {
+	if in.Spec.Monitoring != nil {
+		return in.Spec.Monitoring.EnablePodMonitor
+	}
+
+	return false
+}
+
+// IsServiceMonitorEnabled checks if the ServiceMonitor object, and the
+// metrics service it scrapes, need to be created
+func (in *Pooler) IsServiceMonitorEnabled() bool {
+	if in.Spec.Monitoring != nil {
+		return in.Spec.Monitoring.EnableServiceMonitor
+	}
+
+	return false
+}
+
+// IsAutoscalingEnabled checks if the KEDA ScaledObject, and the metrics
+// service it queries, need to be created
+func (in *Pooler) IsAutoscalingEnabled() bool {
+	return in.Spec.Autoscaling != nil && in.Spec.Autoscaling.Enabled
+}
+
+// IsMetricsServiceRequired checks if the headless service exposing the
+// metrics of the PgBouncer instances needs to be created
+func (in *Pooler) IsMetricsServiceRequired() bool {
+	return in.IsServiceMonitorEnabled() || in.IsAutoscalingEnabled()
+}
+
+// GetMetricsServiceName returns the name of the headless service used to
+// scrape the metrics of the PgBouncer instances
+func (in *Pooler) GetMetricsServiceName() string {
+	return in.Name + ServiceMetricsSuffix
+}
+
+// IsDirectLoadBalancingEnabled checks if PgBouncer needs to connect
+// directly to the replicas instead of using the `-ro` service
+func (in *Pooler) IsDirectLoadBalancingEnabled() bool {
+	if in.Spec.Type != PoolerTypeRO {
+		return false
+	}
+
+	return in.Spec.LoadBalancing == PoolerLoadBalancingRoundRobin ||
+		in.IsLeastConnectionsLoadBalancingEnabled()
+}
+
+// IsLeastConnectionsLoadBalancingEnabled checks if PgBouncer needs to open
+// its server connections on the replica with the fewest client connections
+func (in *Pooler) IsLeastConnectionsLoadBalancingEnabled() bool {
+	return in.Spec.Type == PoolerTypeRO && in.Spec.LoadBalancing == PoolerLoadBalancingLeastConnections
+}
+
+// GetBackendHost returns the hosts PgBouncer connects to. The service of
+// the cluster is used until the operator publishes the list of backends
+func (in *Pooler) GetBackendHost() string {
+	if in.IsDirectLoadBalancingEnabled() && len(in.Status.Backends) > 0 {
+		return strings.Join(in.Status.Backends, ",")
+	}
+
+	return fmt.Sprintf("%s-%s", in.Spec.Cluster.Name, in.Spec.Type)
+}
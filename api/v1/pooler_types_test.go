@@ -34,4 +34,60 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+	It("creates the PodMonitor only when requested", func() {
+		pooler := Pooler{}
+		Expect(pooler.IsPodMonitorEnabled()).To(BeFalse())
+
+		pooler.Spec.Monitoring = &PoolerMonitoringConfiguration{EnablePodMonitor: true}
+		Expect(pooler.IsPodMonitorEnabled()).To(BeTrue())
+	})
+
+	It("connects to the service of the cluster by default", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Cluster: LocalObjectReference{Name: "cluster-example"},
+				Type:    PoolerTypeRO,
+			},
+			Status: PoolerStatus{Backends: []string{"cluster-example-2.cluster-example-any.default.svc"}},
+		}
+		Expect(pooler.IsDirectLoadBalancingEnabled()).To(BeFalse())
+		Expect(pooler.GetBackendHost()).To(Equal("cluster-example-ro"))
+	})
+
+	It("connects directly to the published backends with the roundRobin load balancing", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Cluster:       LocalObjectReference{Name: "cluster-example"},
+				Type:          PoolerTypeRO,
+				LoadBalancing: PoolerLoadBalancingRoundRobin,
+			},
+		}
+		Expect(pooler.IsDirectLoadBalancingEnabled()).To(BeTrue())
+		Expect(pooler.GetBackendHost()).To(Equal("cluster-example-ro"))
+
+		pooler.Status.Backends = []string{
+			"cluster-example-2.cluster-example-any.default.svc",
+			"cluster-example-3.cluster-example-any.default.svc",
+		}
+		Expect(pooler.GetBackendHost()).To(Equal(
+			"cluster-example-2.cluster-example-any.default.svc,cluster-example-3.cluster-example-any.default.svc"))
+	})
+
+	It("connects directly to the published backends with the leastConnections load balancing", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Cluster:       LocalObjectReference{Name: "cluster-example"},
+				Type:          PoolerTypeRO,
+				LoadBalancing: PoolerLoadBalancingLeastConnections,
+			},
+			Status: PoolerStatus{Backends: []string{"cluster-example-3.cluster-example-any.default.svc"}},
+		}
+		Expect(pooler.IsDirectLoadBalancingEnabled()).To(BeTrue())
+		Expect(pooler.IsLeastConnectionsLoadBalancingEnabled()).To(BeTrue())
+		Expect(pooler.GetBackendHost()).To(Equal("cluster-example-3.cluster-example-any.default.svc"))
+
+		pooler.Spec.Type = PoolerTypeRW
+		Expect(pooler.IsDirectLoadBalancingEnabled()).To(BeFalse())
+		Expect(pooler.IsLeastConnectionsLoadBalancingEnabled()).To(BeFalse())
+	})
 })
@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"sort"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -127,21 +130,21 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 	switch {
 	case r.Spec.PgBouncer == nil:
 		result = append(result,
-			field.Invalid(
+			field.Required(
 				field.NewPath("spec", "pgbouncer"),
-				"", "required pgbouncer configuration"))
+				"required pgbouncer configuration"))
 	case r.Spec.PgBouncer.AuthQuerySecret != nil && r.Spec.PgBouncer.AuthQuerySecret.Name != "" &&
 		r.Spec.PgBouncer.AuthQuery == "":
 		result = append(result,
-			field.Invalid(
+			field.Required(
 				field.NewPath("spec", "pgbouncer", "authQuery"),
-				"", "must specify an auth query when providing an auth query secret"))
+				"must specify an auth query when providing an auth query secret"))
 	case (r.Spec.PgBouncer.AuthQuerySecret == nil || r.Spec.PgBouncer.AuthQuerySecret.Name == "") &&
 		r.Spec.PgBouncer.AuthQuery != "":
 		result = append(result,
-			field.Invalid(
+			field.Required(
 				field.NewPath("spec", "pgbouncer", "authQuerySecret", "name"),
-				"", "must specify an existing auth query secret when providing an auth query secret"))
+				"must specify an existing auth query secret when providing an auth query"))
 	}
 
 	result = append(result, r.validatePgbouncerGenericParameters()...)
@@ -153,9 +156,9 @@ func (r *Pooler) validateCluster() field.ErrorList {
 	var result field.ErrorList
 	if r.Spec.Cluster.Name == "" {
 		result = append(result,
-			field.Invalid(
+			field.Required(
 				field.NewPath("spec", "cluster", "name"),
-				"", "must specify a cluster name"))
+				"must specify a cluster name"))
 	}
 	if r.Spec.Cluster.Name == r.Name {
 		result = append(result,
@@ -171,19 +174,61 @@ func (r *Pooler) validateCluster() field.ErrorList {
 func (r *Pooler) Validate() (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validatePgBouncer()...)
 	allErrs = append(allErrs, r.validateCluster()...)
+	allErrs = append(allErrs, r.validateLoadBalancing()...)
+	allErrs = append(allErrs, r.validateAutoscaling()...)
 	return allErrs
 }
 
-// validatePgbouncerGenericParameters validates pgbouncer parameters
+// validateAutoscaling checks that the range of instances of the
+// autoscaling configuration is consistent
+func (r *Pooler) validateAutoscaling() field.ErrorList {
+	var result field.ErrorList
+	if !r.IsAutoscalingEnabled() {
+		return result
+	}
+
+	autoscaling := r.Spec.Autoscaling
+	if autoscaling.MaxInstances < autoscaling.GetMinInstances() {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "autoscaling", "maxInstances"),
+				autoscaling.MaxInstances, "maxInstances must be greater than or equal to minInstances"))
+	}
+	return result
+}
+
+// validateLoadBalancing checks that the connections are balanced across
+// the replicas only by the `ro` poolers
+func (r *Pooler) validateLoadBalancing() field.ErrorList {
+	var result field.ErrorList
+	if r.Spec.LoadBalancing != "" && r.Spec.LoadBalancing != PoolerLoadBalancingService &&
+		r.Spec.Type != PoolerTypeRO {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "loadBalancing"),
+				r.Spec.LoadBalancing,
+				fmt.Sprintf("the %s load balancing is only supported by the ro poolers", r.Spec.LoadBalancing)))
+	}
+	return result
+}
+
+// validatePgbouncerGenericParameters validates pgbouncer parameters,
+// reporting the supported ones for each invalid or reserved parameter
 func (r *Pooler) validatePgbouncerGenericParameters() field.ErrorList {
 	var result field.ErrorList
 
+	params := make([]string, 0, len(r.Spec.PgBouncer.Parameters))
 	for param := range r.Spec.PgBouncer.Parameters {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
 		if !AllowedPgbouncerGenericConfigurationParameters.Has(param) {
 			result = append(result,
-				field.Invalid(
-					field.NewPath("spec", "cluster", "parameters"),
-					param, "Invalid or reserved parameter"))
+				field.NotSupported(
+					field.NewPath("spec", "pgbouncer", "parameters").Key(param),
+					param, AllowedPgbouncerGenericConfigurationParameters.ToSortedList()))
 		}
 	}
 	return result
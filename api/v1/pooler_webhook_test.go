@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -115,7 +116,11 @@ var _ = Describe("Pooler validation", func() {
 				},
 			},
 		}
-		Expect(pooler.validatePgbouncerGenericParameters()).NotTo(BeEmpty())
+		result := pooler.validatePgbouncerGenericParameters()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		Expect(result[0].Field).To(Equal("spec.pgbouncer.parameters[pool_mode]"))
+		Expect(result[0].Detail).To(ContainSubstring(`"verbose"`))
 	})
 
 	It("does not complain when given a valid parameter", func() {
@@ -128,4 +133,49 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("allows the direct load balancing only for the ro poolers", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Type:          PoolerTypeRW,
+				LoadBalancing: PoolerLoadBalancingRoundRobin,
+			},
+		}
+		result := pooler.validateLoadBalancing()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.loadBalancing"))
+
+		pooler.Spec.Type = PoolerTypeRO
+		Expect(pooler.validateLoadBalancing()).To(BeEmpty())
+
+		pooler.Spec.LoadBalancing = PoolerLoadBalancingLeastConnections
+		Expect(pooler.validateLoadBalancing()).To(BeEmpty())
+		pooler.Spec.Type = PoolerTypeRW
+		Expect(pooler.validateLoadBalancing()).To(HaveLen(1))
+
+		pooler.Spec.LoadBalancing = PoolerLoadBalancingService
+		Expect(pooler.validateLoadBalancing()).To(BeEmpty())
+	})
+
+	It("checks the range of instances of the autoscaling configuration", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Autoscaling: &PoolerAutoscalingConfiguration{
+					Enabled:      true,
+					MinInstances: 3,
+					MaxInstances: 2,
+				},
+			},
+		}
+		result := pooler.validateAutoscaling()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.autoscaling.maxInstances"))
+
+		pooler.Spec.Autoscaling.MinInstances = 0
+		Expect(pooler.validateAutoscaling()).To(BeEmpty())
+
+		pooler.Spec.Autoscaling.Enabled = false
+		pooler.Spec.Autoscaling.MaxInstances = 0
+		Expect(pooler.validateAutoscaling()).To(BeEmpty())
+	})
 })
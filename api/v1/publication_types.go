@@ -0,0 +1,151 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PublicationConditionReady is the condition reporting whether the
+	// publication has been applied on the primary instance
+	PublicationConditionReady = "Ready"
+
+	// PublicationReasonReconciled means that the publication is aligned
+	// with its specification
+	PublicationReasonReconciled = "PublicationReconciled"
+
+	// PublicationReasonReconciliationFailed means that the primary instance
+	// failed applying the publication
+	PublicationReasonReconciliationFailed = "PublicationReconciliationFailed"
+)
+
+// PublicationSpec defines the desired state of Publication
+type PublicationSpec struct {
+	// The cluster hosting the publication
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The name of the publication inside PostgreSQL. This field cannot be changed
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The database hosting the publication. This field cannot be changed
+	// +kubebuilder:validation:MinLength=1
+	DBName string `json:"dbname"`
+
+	// The tables to be published
+	Target PublicationTarget `json:"target"`
+
+	// Publication parameters, as the `publish` one, set with the `WITH`
+	// clause of `CREATE PUBLICATION`
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// PublicationTarget is the set of tables to be published
+type PublicationTarget struct {
+	// Publish all the tables of the database, including the ones created
+	// in the future. This field cannot be changed
+	// +optional
+	AllTables bool `json:"allTables,omitempty"`
+
+	// The tables to be published, when not publishing all the tables
+	// +optional
+	Tables []PublicationTable `json:"tables,omitempty"`
+}
+
+// PublicationTable is a table to be published
+type PublicationTable struct {
+	// The schema of the table, resolved through the `search_path` if empty
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The name of the table
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// PublicationStatus defines the observed state of Publication
+type PublicationStatus struct {
+	// The generation of the publication specification last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the publication specification has been applied
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// The logical replication slots in the database of the publication,
+	// which are used by the subscribers to receive the changes
+	// +optional
+	ReplicationSlots []LogicalReplicationSlotStatus `json:"replicationSlots,omitempty"`
+
+	// The conditions of the publication
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// LogicalReplicationSlotStatus is the status of a logical replication slot
+type LogicalReplicationSlotStatus struct {
+	// The name of the replication slot
+	Name string `json:"name"`
+
+	// Whether a subscriber is receiving the changes through the slot
+	Active bool `json:"active"`
+
+	// The amount of WAL, in bytes, generated after the last position
+	// confirmed by the subscriber
+	LagBytes int64 `json:"lagBytes"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+
+// Publication is the Schema for the publications API
+type Publication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired publication.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec PublicationSpec `json:"spec"`
+	// Most recently observed status of the Publication. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status PublicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PublicationList contains a list of Publication
+type PublicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of publications
+	Items []Publication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Publication{}, &PublicationList{})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Publication validation", func() {
+	newPublication := func() *Publication {
+		return &Publication{
+			Spec: PublicationSpec{
+				Cluster: LocalObjectReference{Name: "cluster-example"},
+				Name:    "pub",
+				DBName:  "app",
+				Target:  PublicationTarget{AllTables: true},
+			},
+		}
+	}
+
+	It("accepts a publication of all the tables", func() {
+		Expect(newPublication().Validate()).To(BeEmpty())
+	})
+
+	It("accepts a publication of a list of tables", func() {
+		publication := newPublication()
+		publication.Spec.Target = PublicationTarget{
+			Tables: []PublicationTable{{Schema: "public", Name: "orders"}},
+		}
+		Expect(publication.Validate()).To(BeEmpty())
+	})
+
+	It("requires a cluster name", func() {
+		publication := newPublication()
+		publication.Spec.Cluster.Name = ""
+		result := publication.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.cluster.name"))
+	})
+
+	It("requires the tables to be published", func() {
+		publication := newPublication()
+		publication.Spec.Target.AllTables = false
+		result := publication.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.target"))
+	})
+
+	It("doesn't allow listing the tables when publishing all the tables", func() {
+		publication := newPublication()
+		publication.Spec.Target.Tables = []PublicationTable{{Name: "orders"}}
+		result := publication.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.target.tables"))
+	})
+
+	It("allows changing the parameters", func() {
+		publication := newPublication()
+		updated := publication.DeepCopy()
+		updated.Spec.Parameters = map[string]string{"publish": "insert"}
+		Expect(updated.validateChanges(publication)).To(BeEmpty())
+	})
+
+	It("doesn't allow changing the fields applied only at creation time", func() {
+		publication := newPublication()
+		updated := publication.DeepCopy()
+		updated.Spec.Name = "other"
+		updated.Spec.DBName = "other"
+		updated.Spec.Target = PublicationTarget{Tables: []PublicationTable{{Name: "orders"}}}
+		result := updated.validateChanges(publication)
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.name"))
+		Expect(result[1].Field).To(Equal("spec.dbname"))
+		Expect(result[2].Field).To(Equal("spec.target.allTables"))
+	})
+})
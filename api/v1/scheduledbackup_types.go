@@ -46,6 +46,17 @@ type ScheduledBackupSpec struct {
 	// +kubebuilder:validation:Enum=none;self;cluster
 	// +kubebuilder:default:=none
 	BackupOwnerReference string `json:"backupOwnerReference,omitempty"`
+
+	// The backup method to be used, `barmanObjectStore`, `volumeSnapshot`
+	// or `plugin`. Defaults to `barmanObjectStore`
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin
+	// +kubebuilder:default:=barmanObjectStore
+	// +optional
+	Method BackupMethod `json:"method,omitempty"`
+
+	// The plugin taking the backup, required by the `plugin` method
+	// +optional
+	PluginConfiguration *BackupPluginConfiguration `json:"pluginConfiguration,omitempty"`
 }
 
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
@@ -141,7 +152,9 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Namespace: scheduledBackup.Namespace,
 		},
 		Spec: BackupSpec{
-			Cluster: scheduledBackup.Spec.Cluster,
+			Cluster:             scheduledBackup.Spec.Cluster,
+			Method:              scheduledBackup.Spec.Method,
+			PluginConfiguration: scheduledBackup.Spec.PluginConfiguration.DeepCopy(),
 		},
 	}
 	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current)
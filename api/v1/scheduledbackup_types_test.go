@@ -45,4 +45,17 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(backup.ObjectMeta.Name).To(BeEquivalentTo(backupName))
 		Expect(backup.Annotations).ToNot(BeEmpty())
 	})
+
+	It("properly creates a backup with the requested method", func() {
+		scheduledBackup.Spec.Method = BackupMethodVolumeSnapshot
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup.Spec.Method).To(Equal(BackupMethodVolumeSnapshot))
+		Expect(backup.IsVolumeSnapshot()).To(BeTrue())
+	})
+
+	It("defaults to the barmanObjectStore method", func() {
+		backup := &Backup{}
+		Expect(backup.GetMethod()).To(Equal(BackupMethodBarmanObjectStore))
+		Expect(backup.IsVolumeSnapshot()).To(BeFalse())
+	})
 })
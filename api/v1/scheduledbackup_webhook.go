@@ -58,6 +58,8 @@ func (r *ScheduledBackup) ValidateCreate() error {
 	scheduledBackupLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
 
 	allErrs = append(allErrs, r.validateSchedule()...)
+	allErrs = append(allErrs, validateBackupPluginConfiguration(
+		r.Spec.Method, r.Spec.PluginConfiguration, field.NewPath("spec", "pluginConfiguration"))...)
 
 	if len(allErrs) == 0 {
 		return nil
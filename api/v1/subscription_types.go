@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SubscriptionConditionReady is the condition reporting whether the
+	// subscription has been applied on the primary instance
+	SubscriptionConditionReady = "Ready"
+
+	// SubscriptionReasonReconciled means that the subscription is aligned
+	// with its specification
+	SubscriptionReasonReconciled = "SubscriptionReconciled"
+
+	// SubscriptionReasonReconciliationFailed means that the primary instance
+	// failed applying the subscription
+	SubscriptionReasonReconciliationFailed = "SubscriptionReconciliationFailed"
+)
+
+// SubscriptionState is the state of a subscription inside PostgreSQL
+type SubscriptionState string
+
+const (
+	// SubscriptionStateDisabled means that the subscription is disabled
+	SubscriptionStateDisabled = SubscriptionState("disabled")
+
+	// SubscriptionStateInitializing means that the initial copy of
+	// some tables is still in progress
+	SubscriptionStateInitializing = SubscriptionState("initializing")
+
+	// SubscriptionStateStreaming means that the subscription is receiving
+	// the changes from the publisher
+	SubscriptionStateStreaming = SubscriptionState("streaming")
+
+	// SubscriptionStateDown means that the subscription is enabled, but
+	// no worker is receiving the changes from the publisher
+	SubscriptionStateDown = SubscriptionState("down")
+)
+
+// SubscriptionSpec defines the desired state of Subscription
+type SubscriptionSpec struct {
+	// The cluster hosting the subscription
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The name of the subscription inside PostgreSQL. This field cannot be changed
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The database hosting the subscription. This field cannot be changed
+	// +kubebuilder:validation:MinLength=1
+	DBName string `json:"dbname"`
+
+	// The name of the publication to subscribe to
+	// +kubebuilder:validation:MinLength=1
+	PublicationName string `json:"publicationName"`
+
+	// The database of the publication, overriding the `dbname` connection
+	// parameter of the external cluster
+	// +optional
+	PublicationDBName string `json:"publicationDBName,omitempty"`
+
+	// The name of the external cluster, as defined in the `externalClusters`
+	// section of the subscriber cluster, hosting the publication
+	// +kubebuilder:validation:MinLength=1
+	ExternalClusterName string `json:"externalClusterName"`
+
+	// Subscription parameters, as the `copy_data` one, set with the `WITH`
+	// clause of `CREATE SUBSCRIPTION`. These parameters are used only when
+	// creating the subscription
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// SubscriptionStatus defines the observed state of Subscription
+type SubscriptionStatus struct {
+	// The generation of the subscription specification last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the subscription specification has been applied
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// The state of the subscription inside PostgreSQL
+	// +optional
+	State SubscriptionState `json:"state,omitempty"`
+
+	// The last WAL location received from the publisher
+	// +optional
+	ReceivedLsn string `json:"receivedLsn,omitempty"`
+
+	// The last WAL location reported to the publisher
+	// +optional
+	LatestEndLsn string `json:"latestEndLsn,omitempty"`
+
+	// The time of the last WAL location reported to the publisher
+	// +optional
+	LatestEndTime string `json:"latestEndTime,omitempty"`
+
+	// The conditions of the subscription
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+
+// Subscription is the Schema for the subscriptions API
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired subscription.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec SubscriptionSpec `json:"spec"`
+	// Most recently observed status of the Subscription. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status SubscriptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SubscriptionList contains a list of Subscription
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of subscriptions
+	Items []Subscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Subscription{}, &SubscriptionList{})
+}
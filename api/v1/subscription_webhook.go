@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// subscriptionLog is for logging in this package.
+var subscriptionLog = log.WithName("subscription-resource").WithValues("version", "v1")
+
+// SetupWebhookWithManager setup the webhook inside the controller manager
+func (r *Subscription) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-subscription,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=subscriptions,versions=v1,name=vsubscription.kb.io,sideEffects=None
+
+var _ webhook.Validator = &Subscription{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Subscription) ValidateCreate() error {
+	subscriptionLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+
+	allErrs := r.Validate()
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: SubscriptionKind},
+		r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Subscription) ValidateUpdate(old runtime.Object) error {
+	subscriptionLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+
+	oldSubscription := old.(*Subscription)
+	allErrs := append(r.Validate(), r.validateChanges(oldSubscription)...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: SubscriptionKind},
+		r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Subscription) ValidateDelete() error {
+	subscriptionLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+// Validate validates the configuration of a Subscription, returning
+// a list of errors
+func (r *Subscription) Validate() (allErrs field.ErrorList) {
+	if r.Spec.Cluster.Name == "" {
+		allErrs = append(allErrs,
+			field.Required(
+				field.NewPath("spec", "cluster", "name"),
+				"must specify a cluster name"))
+	}
+
+	return allErrs
+}
+
+// validateChanges checks that the fields which cannot be applied to an
+// existing subscription haven't been changed
+func (r *Subscription) validateChanges(old *Subscription) (allErrs field.ErrorList) {
+	if r.Spec.Cluster.Name != old.Spec.Cluster.Name {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "cluster", "name"),
+				r.Spec.Cluster.Name, "the cluster of a subscription cannot be changed"))
+	}
+
+	if r.Spec.Name != old.Spec.Name {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "name"),
+				r.Spec.Name, "the name of a subscription cannot be changed"))
+	}
+
+	if r.Spec.DBName != old.Spec.DBName {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "dbname"),
+				r.Spec.DBName, "the database of a subscription cannot be changed"))
+	}
+
+	return allErrs
+}
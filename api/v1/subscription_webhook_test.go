@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscription validation", func() {
+	newSubscription := func() *Subscription {
+		return &Subscription{
+			Spec: SubscriptionSpec{
+				Cluster:             LocalObjectReference{Name: "cluster-green"},
+				Name:                "sub",
+				DBName:              "app",
+				PublicationName:     "pub",
+				ExternalClusterName: "cluster-blue",
+			},
+		}
+	}
+
+	It("accepts a subscription referencing a cluster", func() {
+		Expect(newSubscription().Validate()).To(BeEmpty())
+	})
+
+	It("requires a cluster name", func() {
+		subscription := newSubscription()
+		subscription.Spec.Cluster.Name = ""
+		result := subscription.Validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.cluster.name"))
+	})
+
+	It("allows changing the publication and the external cluster", func() {
+		subscription := newSubscription()
+		updated := subscription.DeepCopy()
+		updated.Spec.PublicationName = "other"
+		updated.Spec.ExternalClusterName = "cluster-red"
+		Expect(updated.validateChanges(subscription)).To(BeEmpty())
+	})
+
+	It("doesn't allow changing the fields applied only at creation time", func() {
+		subscription := newSubscription()
+		updated := subscription.DeepCopy()
+		updated.Spec.Cluster.Name = "other"
+		updated.Spec.Name = "other"
+		updated.Spec.DBName = "other"
+		result := updated.validateChanges(subscription)
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.cluster.name"))
+		Expect(result[1].Field).To(Equal("spec.name"))
+		Expect(result[2].Field).To(Equal("spec.dbname"))
+	})
+})
@@ -112,7 +112,7 @@ func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -142,6 +142,26 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationConfiguration)
+		**out = **in
+	}
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		*out = new(VolumeSnapshotConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(VeleroConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -154,6 +174,78 @@ func (in *BackupConfiguration) DeepCopy() *BackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookStatus) DeepCopyInto(out *BackupHookStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookStatus.
+func (in *BackupHookStatus) DeepCopy() *BackupHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHooks) DeepCopyInto(out *BackupHooks) {
+	*out = *in
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHooks.
+func (in *BackupHooks) DeepCopy() *BackupHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -186,6 +278,43 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPluginConfiguration) DeepCopyInto(out *BackupPluginConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPluginConfiguration.
+func (in *BackupPluginConfiguration) DeepCopy() *BackupPluginConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPluginConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupSnapshotElementStatus) DeepCopyInto(out *BackupSnapshotElementStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSnapshotElementStatus.
+func (in *BackupSnapshotElementStatus) DeepCopy() *BackupSnapshotElementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupSnapshotElementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSource) DeepCopyInto(out *BackupSource) {
 	*out = *in
@@ -211,6 +340,11 @@ func (in *BackupSource) DeepCopy() *BackupSource {
 func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 	*out = *in
 	out.Cluster = in.Cluster
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(BackupPluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -223,6 +357,36 @@ func (in *BackupSpec) DeepCopy() *BackupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStatistics) DeepCopyInto(out *BackupStatistics) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DeduplicatedSize != nil {
+		in, out := &in.DeduplicatedSize, &out.DeduplicatedSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatistics.
+func (in *BackupStatistics) DeepCopy() *BackupStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 	*out = *in
@@ -232,6 +396,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(SecretKeySelector)
 		**out = **in
 	}
+	if in.ClientSideEncryption != nil {
+		in, out := &in.ClientSideEncryption, &out.ClientSideEncryption
+		*out = new(ClientSideEncryptionConfiguration)
+		**out = **in
+	}
 	if in.StartedAt != nil {
 		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
@@ -245,6 +414,45 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(InstanceID)
 		**out = **in
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]BackupHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Statistics != nil {
+		in, out := &in.Statistics, &out.Statistics
+		*out = new(BackupStatistics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]BackupSnapshotElementStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackupLabelFile != nil {
+		in, out := &in.BackupLabelFile, &out.BackupLabelFile
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.TablespaceMapFile != nil {
+		in, out := &in.TablespaceMapFile, &out.TablespaceMapFile
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.PluginMetadata != nil {
+		in, out := &in.PluginMetadata, &out.PluginMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -257,6 +465,21 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanCredentials) DeepCopyInto(out *BarmanCredentials) {
 	*out = *in
@@ -299,7 +522,7 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 	if in.Wal != nil {
 		in, out := &in.Wal, &out.Wal
 		*out = new(WalBackupConfiguration)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -320,6 +543,11 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 			(*out)[key] = val
 		}
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(ClientSideEncryptionConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarmanObjectStoreConfiguration.
@@ -455,6 +683,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.Anonymization != nil {
+		in, out := &in.Anonymization, &out.Anonymization
+		*out = new(RecoveryAnonymization)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecovery.
@@ -467,6 +700,52 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImage.
+func (in *CatalogImage) DeepCopy() *CatalogImage {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerConfiguration) DeepCopyInto(out *CertManagerConfiguration) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerConfiguration.
+func (in *CertManagerConfiguration) DeepCopy() *CertManagerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -475,6 +754,11 @@ func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
@@ -510,6 +794,22 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSideEncryptionConfiguration) DeepCopyInto(out *ClientSideEncryptionConfiguration) {
+	*out = *in
+	out.KeysSecret = in.KeysSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSideEncryptionConfiguration.
+func (in *ClientSideEncryptionConfiguration) DeepCopy() *ClientSideEncryptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSideEncryptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -537,6 +837,64 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageCatalog) DeepCopyInto(out *ClusterImageCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageCatalog.
+func (in *ClusterImageCatalog) DeepCopy() *ClusterImageCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageCatalogList) DeepCopyInto(out *ClusterImageCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterImageCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageCatalogList.
+func (in *ClusterImageCatalogList) DeepCopy() *ClusterImageCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -577,12 +935,42 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImageCatalogRef != nil {
+		in, out := &in.ImageCatalogRef, &out.ImageCatalogRef
+		*out = new(ImageCatalogRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MajorVersionUpgrade != nil {
+		in, out := &in.MajorVersionUpgrade, &out.MajorVersionUpgrade
+		*out = new(MajorVersionUpgradeConfiguration)
+		**out = **in
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationConfiguration)
+		**out = **in
+	}
+	if in.RestrictedReplicas != nil {
+		in, out := &in.RestrictedReplicas, &out.RestrictedReplicas
+		*out = new(RestrictedReplicasConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PromotionPriorities != nil {
+		in, out := &in.PromotionPriorities, &out.PromotionPriorities
+		*out = make([]PromotionPriority, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceDiscovery != nil {
+		in, out := &in.ServiceDiscovery, &out.ServiceDiscovery
+		*out = new(ServiceDiscoveryConfiguration)
+		**out = **in
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -619,18 +1007,84 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Affinity.DeepCopyInto(&out.Affinity)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Backup != nil {
+	if in.StorageBenchmark != nil {
+		in, out := &in.StorageBenchmark, &out.StorageBenchmark
+		*out = new(StorageBenchmarkConfiguration)
+		**out = **in
+	}
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]TablespaceConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionDraining != nil {
+		in, out := &in.ConnectionDraining, &out.ConnectionDraining
+		*out = new(ConnectionDrainingConfiguration)
+		**out = **in
+	}
+	if in.SwitchoverGuardrail != nil {
+		in, out := &in.SwitchoverGuardrail, &out.SwitchoverGuardrail
+		*out = new(SwitchoverGuardrailConfiguration)
+		**out = **in
+	}
+	if in.ShutdownCheckpoint != nil {
+		in, out := &in.ShutdownCheckpoint, &out.ShutdownCheckpoint
+		*out = new(ShutdownCheckpointConfiguration)
+		**out = **in
+	}
+	if in.FailoverWitness != nil {
+		in, out := &in.FailoverWitness, &out.FailoverWitness
+		*out = new(FailoverWitnessConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IsolationCheck != nil {
+		in, out := &in.IsolationCheck, &out.IsolationCheck
+		*out = new(IsolationCheckConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceHooks != nil {
+		in, out := &in.InstanceHooks, &out.InstanceHooks
+		*out = new(InstanceHooksConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Affinity.DeepCopyInto(&out.Affinity)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.InstanceOverrides != nil {
+		in, out := &in.InstanceOverrides, &out.InstanceOverrides
+		*out = make([]InstanceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]PluginConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicyConfiguration)
+		**out = **in
+	}
 	if in.NodeMaintenanceWindow != nil {
 		in, out := &in.NodeMaintenanceWindow, &out.NodeMaintenanceWindow
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -643,6 +1097,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(ManagedConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -677,9 +1136,24 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ReadOnlyServiceMembers != nil {
+		in, out := &in.ReadOnlyServiceMembers, &out.ReadOnlyServiceMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadOnlyServiceMembersByConnections != nil {
+		in, out := &in.ReadOnlyServiceMembersByConnections, &out.ReadOnlyServiceMembersByConnections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MajorVersionUpgrade != nil {
+		in, out := &in.MajorVersionUpgrade, &out.MajorVersionUpgrade
+		*out = new(MajorVersionUpgradeStatus)
+		**out = **in
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -706,9 +1180,19 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceDiscovery != nil {
+		in, out := &in.ServiceDiscovery, &out.ServiceDiscovery
+		*out = new(ServiceDiscoveryStatus)
+		**out = **in
+	}
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
+	if in.LastBackupStatistics != nil {
+		in, out := &in.LastBackupStatistics, &out.LastBackupStatistics
+		*out = new(BackupStatistics)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -726,6 +1210,35 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgHBARules != nil {
+		in, out := &in.PgHBARules, &out.PgHBARules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageBenchmarks != nil {
+		in, out := &in.StorageBenchmarks, &out.StorageBenchmarks
+		*out = make(map[string]StorageBenchmarkResult, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = new(PendingMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginStatus != nil {
+		in, out := &in.PluginStatus, &out.PluginStatus
+		*out = make([]PluginStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogicalReplicationSlots != nil {
+		in, out := &in.LogicalReplicationSlots, &out.LogicalReplicationSlots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -776,6 +1289,46 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDrainingConfiguration) DeepCopyInto(out *ConnectionDrainingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDrainingConfiguration.
+func (in *ConnectionDrainingConfiguration) DeepCopy() *ConnectionDrainingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDrainingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionsConfiguration) DeepCopyInto(out *ConnectionsConfiguration) {
+	*out = *in
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuperuserReservedConnections != nil {
+		in, out := &in.SuperuserReservedConnections, &out.SuperuserReservedConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionsConfiguration.
+func (in *ConnectionsConfiguration) DeepCopy() *ConnectionsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
@@ -797,382 +1350,1894 @@ func (in *DataBackupConfiguration) DeepCopy() *DataBackupConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
+func (in *Database) DeepCopyInto(out *Database) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedObjectMetadata.
-func (in *EmbeddedObjectMetadata) DeepCopy() *EmbeddedObjectMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
+func (in *Database) DeepCopy() *Database {
 	if in == nil {
 		return nil
 	}
-	out := new(EmbeddedObjectMetadata)
+	out := new(Database)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Database) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
+func (in *DatabaseDefaultsConfiguration) DeepCopyInto(out *DatabaseDefaultsConfiguration) {
 	*out = *in
-	if in.ConnectionParameters != nil {
-		in, out := &in.ConnectionParameters, &out.ConnectionParameters
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.SSLCert != nil {
-		in, out := &in.SSLCert, &out.SSLCert
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SSLKey != nil {
-		in, out := &in.SSLKey, &out.SSLKey
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SSLRootCert != nil {
-		in, out := &in.SSLRootCert, &out.SSLRootCert
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Password != nil {
-		in, out := &in.Password, &out.Password
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.BarmanObjectStore != nil {
-		in, out := &in.BarmanObjectStore, &out.BarmanObjectStore
-		*out = new(BarmanObjectStoreConfiguration)
-		(*in).DeepCopyInto(*out)
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DefaultPrivilege, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
-func (in *ExternalCluster) DeepCopy() *ExternalCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseDefaultsConfiguration.
+func (in *DatabaseDefaultsConfiguration) DeepCopy() *DatabaseDefaultsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalCluster)
+	out := new(DatabaseDefaultsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
 	*out = *in
-	if in.ApplicationCredentials != nil {
-		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
-		*out = new(SecretKeySelector)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Database, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
-func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseList.
+func (in *DatabaseList) DeepCopy() *DatabaseList {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleCredentials)
+	out := new(DatabaseList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Import) DeepCopyInto(out *Import) {
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 	*out = *in
-	out.Source = in.Source
-	if in.Databases != nil {
-		in, out := &in.Databases, &out.Databases
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
+	out.Cluster = in.Cluster
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.PostImportApplicationSQL != nil {
-		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
-func (in *Import) DeepCopy() *Import {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Import)
+	out := new(DatabaseSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
-func (in *ImportSource) DeepCopy() *ImportSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
+func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportSource)
+	out := new(DatabaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+func (in *DefaultPrivilege) DeepCopyInto(out *DefaultPrivilege) {
 	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
-func (in *InstanceID) DeepCopy() *InstanceID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultPrivilege.
+func (in *DefaultPrivilege) DeepCopy() *DefaultPrivilege {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceID)
+	out := new(DefaultPrivilege)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+func (in *DeletionPolicyConfiguration) DeepCopyInto(out *DeletionPolicyConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
-func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicyConfiguration.
+func (in *DeletionPolicyConfiguration) DeepCopy() *DeletionPolicyConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceReportedState)
+	out := new(DeletionPolicyConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedObjectMetadata.
+func (in *EmbeddedObjectMetadata) DeepCopy() *EmbeddedObjectMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(EmbeddedObjectMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *ExtensionConfiguration) DeepCopyInto(out *ExtensionConfiguration) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(corev1.VolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
-func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfiguration.
+func (in *ExtensionConfiguration) DeepCopy() *ExtensionConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindSearchAuth)
+	out := new(ExtensionConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
-	if in.BindAsAuth != nil {
-		in, out := &in.BindAsAuth, &out.BindAsAuth
-		*out = new(LDAPBindAsAuth)
-		**out = **in
+	if in.ConnectionParameters != nil {
+		in, out := &in.ConnectionParameters, &out.ConnectionParameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.BindSearchAuth != nil {
-		in, out := &in.BindSearchAuth, &out.BindSearchAuth
-		*out = new(LDAPBindSearchAuth)
+	if in.SSLCert != nil {
+		in, out := &in.SSLCert, &out.SSLCert
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSLKey != nil {
+		in, out := &in.SSLKey, &out.SSLKey
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSLRootCert != nil {
+		in, out := &in.SSLRootCert, &out.SSLRootCert
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BarmanObjectStore != nil {
+		in, out := &in.BarmanObjectStore, &out.BarmanObjectStore
+		*out = new(BarmanObjectStoreConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(BackupPluginConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
-func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
+func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPConfig)
+	out := new(ExternalCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+func (in *FailoverWitnessConfiguration) DeepCopyInto(out *FailoverWitnessConfiguration) {
 	*out = *in
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
-func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverWitnessConfiguration.
+func (in *FailoverWitnessConfiguration) DeepCopy() *FailoverWitnessConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalObjectReference)
+	out := new(FailoverWitnessConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
 	*out = *in
-	if in.DisableDefaultQueries != nil {
-		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
-		*out = new(bool)
+	if in.ApplicationCredentials != nil {
+		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
+		*out = new(SecretKeySelector)
 		**out = **in
 	}
-	if in.CustomQueriesConfigMap != nil {
-		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
-		*out = make([]ConfigMapKeySelector, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
+func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalog.
+func (in *ImageCatalog) DeepCopy() *ImageCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogList) DeepCopyInto(out *ImageCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogList.
+func (in *ImageCatalogList) DeepCopy() *ImageCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogRef) DeepCopyInto(out *ImageCatalogRef) {
+	*out = *in
+	in.TypedLocalObjectReference.DeepCopyInto(&out.TypedLocalObjectReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogRef.
+func (in *ImageCatalogRef) DeepCopy() *ImageCatalogRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogSpec) DeepCopyInto(out *ImageCatalogSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]CatalogImage, len(*in))
 		copy(*out, *in)
 	}
-	if in.CustomQueriesSecret != nil {
-		in, out := &in.CustomQueriesSecret, &out.CustomQueriesSecret
-		*out = make([]SecretKeySelector, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogSpec.
+func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Import) DeepCopyInto(out *Import) {
+	*out = *in
+	out.Source = in.Source
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostImportApplicationSQL != nil {
+		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
-func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
+func (in *Import) DeepCopy() *Import {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringConfiguration)
+	out := new(Import)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
+func (in *ImportSource) DeepCopyInto(out *ImportSource) {
 	*out = *in
-	if in.ReusePVC != nil {
-		in, out := &in.ReusePVC, &out.ReusePVC
-		*out = new(bool)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
+func (in *ImportSource) DeepCopy() *ImportSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceHook) DeepCopyInto(out *InstanceHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHook.
+func (in *InstanceHook) DeepCopy() *InstanceHook {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceHooksConfiguration) DeepCopyInto(out *InstanceHooksConfiguration) {
+	*out = *in
+	if in.ScriptsConfigMap != nil {
+		in, out := &in.ScriptsConfigMap, &out.ScriptsConfigMap
+		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.PrePromote != nil {
+		in, out := &in.PrePromote, &out.PrePromote
+		*out = make([]InstanceHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostPromote != nil {
+		in, out := &in.PostPromote, &out.PostPromote
+		*out = make([]InstanceHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreDemote != nil {
+		in, out := &in.PreDemote, &out.PreDemote
+		*out = make([]InstanceHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreShutdown != nil {
+		in, out := &in.PreShutdown, &out.PreShutdown
+		*out = make([]InstanceHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
-func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHooksConfiguration.
+func (in *InstanceHooksConfiguration) DeepCopy() *InstanceHooksConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMaintenanceWindow)
+	out := new(InstanceHooksConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+func (in *InstanceID) DeepCopyInto(out *InstanceID) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
+func (in *InstanceID) DeepCopy() *InstanceID {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceID)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceOverride) DeepCopyInto(out *InstanceOverride) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
-func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOverride.
+func (in *InstanceOverride) DeepCopy() *InstanceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+	*out = *in
+	if in.TimelineDivergence != nil {
+		in, out := &in.TimelineDivergence, &out.TimelineDivergence
+		*out = new(TimelineDivergence)
+		**out = **in
+	}
+	if in.LongRunningTransactions != nil {
+		in, out := &in.LongRunningTransactions, &out.LongRunningTransactions
+		*out = new(LongRunningTransactionsState)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
+func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceReportedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IsolationCheckConfiguration) DeepCopyInto(out *IsolationCheckConfiguration) {
+	*out = *in
+	if in.PeerQuorum != nil {
+		in, out := &in.PeerQuorum, &out.PeerQuorum
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IsolationCheckConfiguration.
+func (in *IsolationCheckConfiguration) DeepCopy() *IsolationCheckConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(IsolationCheckConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
+func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindSearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	if in.BindAsAuth != nil {
+		in, out := &in.BindAsAuth, &out.BindAsAuth
+		*out = new(LDAPBindAsAuth)
+		**out = **in
+	}
+	if in.BindSearchAuth != nil {
+		in, out := &in.BindSearchAuth, &out.BindSearchAuth
+		*out = new(LDAPBindSearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalDecodingConfiguration) DeepCopyInto(out *LogicalDecodingConfiguration) {
+	*out = *in
+	if in.Slots != nil {
+		in, out := &in.Slots, &out.Slots
+		*out = make([]LogicalReplicationSlot, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalDecodingConfiguration.
+func (in *LogicalDecodingConfiguration) DeepCopy() *LogicalDecodingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalDecodingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalReplicationSlot) DeepCopyInto(out *LogicalReplicationSlot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalReplicationSlot.
+func (in *LogicalReplicationSlot) DeepCopy() *LogicalReplicationSlot {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalReplicationSlot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalReplicationSlotStatus) DeepCopyInto(out *LogicalReplicationSlotStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalReplicationSlotStatus.
+func (in *LogicalReplicationSlotStatus) DeepCopy() *LogicalReplicationSlotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalReplicationSlotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LongRunningTransactionsConfiguration) DeepCopyInto(out *LongRunningTransactionsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LongRunningTransactionsConfiguration.
+func (in *LongRunningTransactionsConfiguration) DeepCopy() *LongRunningTransactionsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LongRunningTransactionsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LongRunningTransactionsState) DeepCopyInto(out *LongRunningTransactionsState) {
+	*out = *in
+	if in.OldestTransactionStart != nil {
+		in, out := &in.OldestTransactionStart, &out.OldestTransactionStart
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LongRunningTransactionsState.
+func (in *LongRunningTransactionsState) DeepCopy() *LongRunningTransactionsState {
+	if in == nil {
+		return nil
+	}
+	out := new(LongRunningTransactionsState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorVersionUpgradeConfiguration) DeepCopyInto(out *MajorVersionUpgradeConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorVersionUpgradeConfiguration.
+func (in *MajorVersionUpgradeConfiguration) DeepCopy() *MajorVersionUpgradeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorVersionUpgradeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorVersionUpgradeStatus) DeepCopyInto(out *MajorVersionUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorVersionUpgradeStatus.
+func (in *MajorVersionUpgradeStatus) DeepCopy() *MajorVersionUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorVersionUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FencedInstances != nil {
+		in, out := &in.FencedInstances, &out.FencedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(ReadOnlyServiceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
+func (in *ManagedServices) DeepCopy() *ManagedServices {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+	*out = *in
+	if in.DisableDefaultQueries != nil {
+		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomQueriesConfigMap != nil {
+		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomQueriesSecret != nil {
+		in, out := &in.CustomQueriesSecret, &out.CustomQueriesSecret
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.LongRunningTransactions != nil {
+		in, out := &in.LongRunningTransactions, &out.LongRunningTransactions
+		*out = new(LongRunningTransactionsConfiguration)
+		**out = **in
+	}
+	if in.StorageForecast != nil {
+		in, out := &in.StorageForecast, &out.StorageForecast
+		*out = new(StorageForecastConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
+func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
+	*out = *in
+	if in.ReusePVC != nil {
+		in, out := &in.ReusePVC, &out.ReusePVC
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
+func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingMaintenanceStatus) DeepCopyInto(out *PendingMaintenanceStatus) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingMaintenanceStatus.
+func (in *PendingMaintenanceStatus) DeepCopy() *PendingMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
+func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerIntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+	*out = *in
+	out.AuthQuery = in.AuthQuery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
+func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+	*out = *in
+	if in.AuthQuerySecret != nil {
+		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
+func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgHBAAddressesSource) DeepCopyInto(out *PgHBAAddressesSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgHBAAddressesSource.
+func (in *PgHBAAddressesSource) DeepCopy() *PgHBAAddressesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PgHBAAddressesSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgHBARule) DeepCopyInto(out *PgHBARule) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddressesFrom != nil {
+		in, out := &in.AddressesFrom, &out.AddressesFrom
+		*out = new(PgHBAAddressesSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgHBARule.
+func (in *PgHBARule) DeepCopy() *PgHBARule {
+	if in == nil {
+		return nil
+	}
+	out := new(PgHBARule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgIdentMap) DeepCopyInto(out *PgIdentMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgIdentMap.
+func (in *PgIdentMap) DeepCopy() *PgIdentMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PgIdentMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfiguration.
+func (in *PluginConfiguration) DeepCopy() *PluginConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginStatus) DeepCopyInto(out *PluginStatus) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginStatus.
+func (in *PluginStatus) DeepCopy() *PluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMeta) DeepCopyInto(out *PodMeta) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMeta.
+func (in *PodMeta) DeepCopy() *PodMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
+func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
+	{
+		in := &in
+		*out = make(PodTopologyLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
+func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTopologyLabels)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pooler) DeepCopyInto(out *Pooler) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pooler.
+func (in *Pooler) DeepCopy() *Pooler {
+	if in == nil {
+		return nil
+	}
+	out := new(Pooler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pooler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
+	*out = *in
+	in.PgBouncerIntegration.DeepCopyInto(&out.PgBouncerIntegration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerIntegrations.
+func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerIntegrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingConfiguration) DeepCopyInto(out *PoolerAutoscalingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingConfiguration.
+func (in *PoolerAutoscalingConfiguration) DeepCopy() *PoolerAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pooler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
+func (in *PoolerList) DeepCopy() *PoolerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
+func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
+	*out = *in
+	out.ServerTLS = in.ServerTLS
+	out.ServerCA = in.ServerCA
+	out.ClientCA = in.ClientCA
+	if in.PgBouncerSecrets != nil {
+		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
+		*out = new(PgBouncerSecrets)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
+func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PgBouncer != nil {
+		in, out := &in.PgBouncer, &out.PgBouncer
+		*out = new(PgBouncerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PoolerMonitoringConfiguration)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
+func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(PoolerSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
+func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
+func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(PostInitApplicationSQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PgHBA != nil {
+		in, out := &in.PgHBA, &out.PgHBA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgHBARules != nil {
+		in, out := &in.PgHBARules, &out.PgHBARules
+		*out = make([]PgHBARule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]PgIdentMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(ConnectionsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveryTuning != nil {
+		in, out := &in.RecoveryTuning, &out.RecoveryTuning
+		*out = new(RecoveryTuningConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(SynchronousReplicaConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SynchronousCommit != nil {
+		in, out := &in.SynchronousCommit, &out.SynchronousCommit
+		*out = new(SynchronousCommitConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseDefaults != nil {
+		in, out := &in.DatabaseDefaults, &out.DatabaseDefaults
+		*out = make([]DatabaseDefaultsConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalLibraries != nil {
+		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prewarm != nil {
+		in, out := &in.Prewarm, &out.Prewarm
+		*out = new(PrewarmConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogicalDecoding != nil {
+		in, out := &in.LogicalDecoding, &out.LogicalDecoding
+		*out = new(LogicalDecodingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
+func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrewarmConfiguration) DeepCopyInto(out *PrewarmConfiguration) {
+	*out = *in
+	if in.Relations != nil {
+		in, out := &in.Relations, &out.Relations
+		*out = make([]PrewarmRelation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrewarmConfiguration.
+func (in *PrewarmConfiguration) DeepCopy() *PrewarmConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrewarmConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrewarmRelation) DeepCopyInto(out *PrewarmRelation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrewarmRelation.
+func (in *PrewarmRelation) DeepCopy() *PrewarmRelation {
+	if in == nil {
+		return nil
+	}
+	out := new(PrewarmRelation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionPriority) DeepCopyInto(out *PromotionPriority) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionPriority.
+func (in *PromotionPriority) DeepCopy() *PromotionPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Publication) DeepCopyInto(out *Publication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Publication.
+func (in *Publication) DeepCopy() *Publication {
+	if in == nil {
+		return nil
+	}
+	out := new(Publication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Publication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationList) DeepCopyInto(out *PublicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Publication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationList.
+func (in *PublicationList) DeepCopy() *PublicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PublicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationSpec) DeepCopyInto(out *PublicationSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationSpec.
+func (in *PublicationSpec) DeepCopy() *PublicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationStatus) DeepCopyInto(out *PublicationStatus) {
+	*out = *in
+	if in.ReplicationSlots != nil {
+		in, out := &in.ReplicationSlots, &out.ReplicationSlots
+		*out = make([]LogicalReplicationSlotStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationStatus.
+func (in *PublicationStatus) DeepCopy() *PublicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationTable) DeepCopyInto(out *PublicationTable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTable.
+func (in *PublicationTable) DeepCopy() *PublicationTable {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationTarget) DeepCopyInto(out *PublicationTarget) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]PublicationTable, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTarget.
+func (in *PublicationTarget) DeepCopy() *PublicationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyServiceConfiguration) DeepCopyInto(out *ReadOnlyServiceConfiguration) {
+	*out = *in
+	if in.DelayedReplicaThreshold != nil {
+		in, out := &in.DelayedReplicaThreshold, &out.DelayedReplicaThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyServiceConfiguration.
+func (in *ReadOnlyServiceConfiguration) DeepCopy() *ReadOnlyServiceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyServiceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryAnonymization) DeepCopyInto(out *RecoveryAnonymization) {
+	*out = *in
+	if in.SQLRefs != nil {
+		in, out := &in.SQLRefs, &out.SQLRefs
+		*out = new(PostInitApplicationSQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryAnonymization.
+func (in *RecoveryAnonymization) DeepCopy() *RecoveryAnonymization {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryAnonymization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
+	*out = *in
+	if in.TargetImmediate != nil {
+		in, out := &in.TargetImmediate, &out.TargetImmediate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Exclusive != nil {
+		in, out := &in.Exclusive, &out.Exclusive
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
+func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryTuningConfiguration) DeepCopyInto(out *RecoveryTuningConfiguration) {
+	*out = *in
+	if in.MaintenanceIOConcurrency != nil {
+		in, out := &in.MaintenanceIOConcurrency, &out.MaintenanceIOConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxParallelWorkers != nil {
+		in, out := &in.MaxParallelWorkers, &out.MaxParallelWorkers
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTuningConfiguration.
+func (in *RecoveryTuningConfiguration) DeepCopy() *RecoveryTuningConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryTuningConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaClusterConfiguration.
+func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerIntegrationStatus)
+	out := new(ReplicaClusterConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+func (in *ReplicationConfiguration) DeepCopyInto(out *ReplicationConfiguration) {
 	*out = *in
-	out.AuthQuery = in.AuthQuery
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
-func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConfiguration.
+func (in *ReplicationConfiguration) DeepCopy() *ReplicationConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSecrets)
+	out := new(ReplicationConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
-	if in.AuthQuerySecret != nil {
-		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
-		*out = new(LocalObjectReference)
+	if in.HighAvailability != nil {
+		in, out := &in.HighAvailability, &out.HighAvailability
+		*out = new(ReplicationSlotsHAConfiguration)
 		**out = **in
 	}
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Paused != nil {
-		in, out := &in.Paused, &out.Paused
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsConfiguration.
+func (in *ReplicationSlotsConfiguration) DeepCopy() *ReplicationSlotsConfiguration {
+	if in == nil {
+		return nil
 	}
+	out := new(ReplicationSlotsConfiguration)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
-func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationSlotsHAConfiguration) DeepCopyInto(out *ReplicationSlotsHAConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsHAConfiguration.
+func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSpec)
+	out := new(ReplicationSlotsHAConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodMeta) DeepCopyInto(out *PodMeta) {
+func (in *RestrictedReplicasConfiguration) DeepCopyInto(out *RestrictedReplicasConfiguration) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]int, len(*in))
+		copy(*out, *in)
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
@@ -1180,56 +3245,69 @@ func (in *PodMeta) DeepCopyInto(out *PodMeta) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMeta.
-func (in *PodMeta) DeepCopy() *PodMeta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestrictedReplicasConfiguration.
+func (in *RestrictedReplicasConfiguration) DeepCopy() *RestrictedReplicasConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PodMeta)
+	out := new(RestrictedReplicasConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
-func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStatus.
+func (in *RollingUpdateStatus) DeepCopy() *RollingUpdateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTemplateSpec)
+	out := new(RollingUpdateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
-	{
-		in := &in
-		*out = make(PodTopologyLabels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+func (in *S3Credentials) DeepCopyInto(out *S3Credentials) {
+	*out = *in
+	if in.AccessKeyIDReference != nil {
+		in, out := &in.AccessKeyIDReference, &out.AccessKeyIDReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.SecretAccessKeyReference != nil {
+		in, out := &in.SecretAccessKeyReference, &out.SecretAccessKeyReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.RegionReference != nil {
+		in, out := &in.RegionReference, &out.RegionReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.SessionToken != nil {
+		in, out := &in.SessionToken, &out.SessionToken
+		*out = new(SecretKeySelector)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
-func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Credentials.
+func (in *S3Credentials) DeepCopy() *S3Credentials {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTopologyLabels)
+	out := new(S3Credentials)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pooler) DeepCopyInto(out *Pooler) {
+func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1237,18 +3315,18 @@ func (in *Pooler) DeepCopyInto(out *Pooler) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pooler.
-func (in *Pooler) DeepCopy() *Pooler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
+func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(Pooler)
+	out := new(ScheduledBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Pooler) DeepCopyObject() runtime.Object {
+func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1256,47 +3334,31 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
-	*out = *in
-	in.PgBouncerIntegration.DeepCopyInto(&out.PgBouncerIntegration)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerIntegrations.
-func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
-	if in == nil {
-		return nil
-	}
-	out := new(PoolerIntegrations)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Pooler, len(*in))
+		*out = make([]ScheduledBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
-func (in *PoolerList) DeepCopy() *PoolerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
+func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerList)
+	out := new(ScheduledBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PoolerList) DeepCopyObject() runtime.Object {
+func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1304,265 +3366,255 @@ func (in *PoolerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
+func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 	*out = *in
-	out.ServerTLS = in.ServerTLS
-	out.ServerCA = in.ServerCA
-	out.ClientCA = in.ClientCA
-	if in.PgBouncerSecrets != nil {
-		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
-		*out = new(PgBouncerSecrets)
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Immediate != nil {
+		in, out := &in.Immediate, &out.Immediate
+		*out = new(bool)
 		**out = **in
 	}
+	out.Cluster = in.Cluster
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(BackupPluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
-func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
+func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSecrets)
+	out := new(ScheduledBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
+func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 	*out = *in
-	out.Cluster = in.Cluster
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(PodTemplateSpec)
-		(*in).DeepCopyInto(*out)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	if in.PgBouncer != nil {
-		in, out := &in.PgBouncer, &out.PgBouncer
-		*out = new(PgBouncerSpec)
-		(*in).DeepCopyInto(*out)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
-func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
+func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSpec)
+	out := new(ScheduledBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = new(PoolerSecrets)
-		(*in).DeepCopyInto(*out)
-	}
+	out.LocalObjectReference = in.LocalObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
-func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerStatus)
+	out := new(SecretKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+func (in *SecretVersion) DeepCopyInto(out *SecretVersion) {
 	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
-		copy(*out, *in)
-	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
-func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersion.
+func (in *SecretVersion) DeepCopy() *SecretVersion {
 	if in == nil {
 		return nil
 	}
-	out := new(PostInitApplicationSQLRefs)
+	out := new(SecretVersion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
 	*out = *in
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.PgHBA != nil {
-		in, out := &in.PgHBA, &out.PgHBA
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
-	if in.AdditionalLibraries != nil {
-		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LDAP != nil {
-		in, out := &in.LDAP, &out.LDAP
-		*out = new(LDAPConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
-func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
+func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresConfiguration)
+	out := new(SecretsResourceVersion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
+func (in *ServiceDiscoveryConfiguration) DeepCopyInto(out *ServiceDiscoveryConfiguration) {
 	*out = *in
-	if in.TargetImmediate != nil {
-		in, out := &in.TargetImmediate, &out.TargetImmediate
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Exclusive != nil {
-		in, out := &in.Exclusive, &out.Exclusive
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
-func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDiscoveryConfiguration.
+func (in *ServiceDiscoveryConfiguration) DeepCopy() *ServiceDiscoveryConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(RecoveryTarget)
+	out := new(ServiceDiscoveryConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
+func (in *ServiceDiscoveryStatus) DeepCopyInto(out *ServiceDiscoveryStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaClusterConfiguration.
-func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDiscoveryStatus.
+func (in *ServiceDiscoveryStatus) DeepCopy() *ServiceDiscoveryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicaClusterConfiguration)
+	out := new(ServiceDiscoveryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
+func (in *ShutdownCheckpointConfiguration) DeepCopyInto(out *ShutdownCheckpointConfiguration) {
 	*out = *in
-	if in.HighAvailability != nil {
-		in, out := &in.HighAvailability, &out.HighAvailability
-		*out = new(ReplicationSlotsHAConfiguration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsConfiguration.
-func (in *ReplicationSlotsConfiguration) DeepCopy() *ReplicationSlotsConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownCheckpointConfiguration.
+func (in *ShutdownCheckpointConfiguration) DeepCopy() *ShutdownCheckpointConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicationSlotsConfiguration)
+	out := new(ShutdownCheckpointConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicationSlotsHAConfiguration) DeepCopyInto(out *ReplicationSlotsHAConfiguration) {
+func (in *StorageBenchmarkConfiguration) DeepCopyInto(out *StorageBenchmarkConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsHAConfiguration.
-func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBenchmarkConfiguration.
+func (in *StorageBenchmarkConfiguration) DeepCopy() *StorageBenchmarkConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicationSlotsHAConfiguration)
+	out := new(StorageBenchmarkConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
+func (in *StorageBenchmarkResult) DeepCopyInto(out *StorageBenchmarkResult) {
 	*out = *in
-	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.OpsPerSecond != nil {
+		in, out := &in.OpsPerSecond, &out.OpsPerSecond
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStatus.
-func (in *RollingUpdateStatus) DeepCopy() *RollingUpdateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBenchmarkResult.
+func (in *StorageBenchmarkResult) DeepCopy() *StorageBenchmarkResult {
 	if in == nil {
 		return nil
 	}
-	out := new(RollingUpdateStatus)
+	out := new(StorageBenchmarkResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3Credentials) DeepCopyInto(out *S3Credentials) {
+func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
-	if in.AccessKeyIDReference != nil {
-		in, out := &in.AccessKeyIDReference, &out.AccessKeyIDReference
-		*out = new(SecretKeySelector)
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
 		**out = **in
 	}
-	if in.SecretAccessKeyReference != nil {
-		in, out := &in.SecretAccessKeyReference, &out.SecretAccessKeyReference
-		*out = new(SecretKeySelector)
+	if in.ResizeInUseVolumes != nil {
+		in, out := &in.ResizeInUseVolumes, &out.ResizeInUseVolumes
+		*out = new(bool)
 		**out = **in
 	}
-	if in.RegionReference != nil {
-		in, out := &in.RegionReference, &out.RegionReference
-		*out = new(SecretKeySelector)
+	if in.PersistentVolumeClaimTemplate != nil {
+		in, out := &in.PersistentVolumeClaimTemplate, &out.PersistentVolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeAttributesClassName != nil {
+		in, out := &in.VolumeAttributesClassName, &out.VolumeAttributesClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.SessionToken != nil {
-		in, out := &in.SessionToken, &out.SessionToken
-		*out = new(SecretKeySelector)
+	if in.BackupVolumeAttributesClassName != nil {
+		in, out := &in.BackupVolumeAttributesClassName, &out.BackupVolumeAttributesClassName
+		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Credentials.
-func (in *S3Credentials) DeepCopy() *S3Credentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
+func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(S3Credentials)
+	out := new(StorageConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
+func (in *StorageForecastConfiguration) DeepCopyInto(out *StorageForecastConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageForecastConfiguration.
+func (in *StorageForecastConfiguration) DeepCopy() *StorageForecastConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageForecastConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1570,18 +3622,18 @@ func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
-func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackup)
+	out := new(Subscription)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
+func (in *Subscription) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1589,31 +3641,31 @@ func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
+func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScheduledBackup, len(*in))
+		*out = make([]Subscription, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
-func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionList.
+func (in *SubscriptionList) DeepCopy() *SubscriptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupList)
+	out := new(SubscriptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
+func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1621,157 +3673,177 @@ func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
+func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 	*out = *in
-	if in.Suspend != nil {
-		in, out := &in.Suspend, &out.Suspend
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Immediate != nil {
-		in, out := &in.Immediate, &out.Immediate
-		*out = new(bool)
-		**out = **in
-	}
 	out.Cluster = in.Cluster
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
-func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
+func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupSpec)
+	out := new(SubscriptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
+func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 	*out = *in
-	if in.LastCheckTime != nil {
-		in, out := &in.LastCheckTime, &out.LastCheckTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastScheduleTime != nil {
-		in, out := &in.LastScheduleTime, &out.LastScheduleTime
-		*out = (*in).DeepCopy()
-	}
-	if in.NextScheduleTime != nil {
-		in, out := &in.NextScheduleTime, &out.NextScheduleTime
-		*out = (*in).DeepCopy()
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
-func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
+func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupStatus)
+	out := new(SubscriptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+func (in *SwitchoverGuardrailConfiguration) DeepCopyInto(out *SwitchoverGuardrailConfiguration) {
 	*out = *in
-	out.LocalObjectReference = in.LocalObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
-func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverGuardrailConfiguration.
+func (in *SwitchoverGuardrailConfiguration) DeepCopy() *SwitchoverGuardrailConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeySelector)
+	out := new(SwitchoverGuardrailConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretVersion) DeepCopyInto(out *SecretVersion) {
+func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
+	if in.NodeLabelsAntiAffinity != nil {
+		in, out := &in.NodeLabelsAntiAffinity, &out.NodeLabelsAntiAffinity
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersion.
-func (in *SecretVersion) DeepCopy() *SecretVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncReplicaElectionConstraints.
+func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstraints {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretVersion)
+	out := new(SyncReplicaElectionConstraints)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
+func (in *SynchronousCommitConfiguration) DeepCopyInto(out *SynchronousCommitConfiguration) {
 	*out = *in
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make([]SynchronousCommitDefault, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
-func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousCommitConfiguration.
+func (in *SynchronousCommitConfiguration) DeepCopy() *SynchronousCommitConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsResourceVersion)
+	out := new(SynchronousCommitConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
+func (in *SynchronousCommitDefault) DeepCopyInto(out *SynchronousCommitDefault) {
 	*out = *in
-	if in.StorageClass != nil {
-		in, out := &in.StorageClass, &out.StorageClass
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousCommitDefault.
+func (in *SynchronousCommitDefault) DeepCopy() *SynchronousCommitDefault {
+	if in == nil {
+		return nil
 	}
-	if in.ResizeInUseVolumes != nil {
-		in, out := &in.ResizeInUseVolumes, &out.ResizeInUseVolumes
-		*out = new(bool)
+	out := new(SynchronousCommitDefault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynchronousReplicaConfiguration) DeepCopyInto(out *SynchronousReplicaConfiguration) {
+	*out = *in
+	if in.MaxStandbyNamesFromCluster != nil {
+		in, out := &in.MaxStandbyNamesFromCluster, &out.MaxStandbyNamesFromCluster
+		*out = new(int)
 		**out = **in
 	}
-	if in.PersistentVolumeClaimTemplate != nil {
-		in, out := &in.PersistentVolumeClaimTemplate, &out.PersistentVolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
+	if in.StandbyNamesPre != nil {
+		in, out := &in.StandbyNamesPre, &out.StandbyNamesPre
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StandbyNamesPost != nil {
+		in, out := &in.StandbyNamesPost, &out.StandbyNamesPost
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
-func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousReplicaConfiguration.
+func (in *SynchronousReplicaConfiguration) DeepCopy() *SynchronousReplicaConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfiguration)
+	out := new(SynchronousReplicaConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
+func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
-	if in.NodeLabelsAntiAffinity != nil {
-		in, out := &in.NodeLabelsAntiAffinity, &out.NodeLabelsAntiAffinity
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.Storage.DeepCopyInto(&out.Storage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceConfiguration.
+func (in *TablespaceConfiguration) DeepCopy() *TablespaceConfiguration {
+	if in == nil {
+		return nil
 	}
+	out := new(TablespaceConfiguration)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncReplicaElectionConstraints.
-func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstraints {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineDivergence) DeepCopyInto(out *TimelineDivergence) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineDivergence.
+func (in *TimelineDivergence) DeepCopy() *TimelineDivergence {
 	if in == nil {
 		return nil
 	}
-	out := new(SyncReplicaElectionConstraints)
+	out := new(TimelineDivergence)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1808,9 +3880,49 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroConfiguration) DeepCopyInto(out *VeleroConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroConfiguration.
+func (in *VeleroConfiguration) DeepCopy() *VeleroConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
+	*out = *in
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotConfiguration.
+func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(WalStreamingConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalBackupConfiguration.
@@ -1822,3 +3934,18 @@ func (in *WalBackupConfiguration) DeepCopy() *WalBackupConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalStreamingConfiguration) DeepCopyInto(out *WalStreamingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalStreamingConfiguration.
+func (in *WalStreamingConfiguration) DeepCopy() *WalStreamingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalStreamingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -27,17 +27,25 @@ import (
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/clone"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/drill"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fleet"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/hibernate"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/install"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/pgbench"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/pprof"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/psql"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/rebuild"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/recoverability"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/reload"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/upgradecheck"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 
@@ -49,11 +57,16 @@ func main() {
 	configFlags := genericclioptions.NewConfigFlags(true)
 
 	rootCmd := &cobra.Command{
-		Use:          "kubectl-cnpg",
-		Short:        "A plugin to manage your CloudNativePG clusters",
-		SilenceUsage: true,
+		Use:           "kubectl-cnpg",
+		Short:         "A plugin to manage your CloudNativePG clusters",
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			logFlags.ConfigureLogging()
+			if plugin.IsShellCompletionCommand(cmd) {
+				plugin.SetupCompletion(configFlags)
+				return nil
+			}
 			return plugin.SetupKubernetesClient(configFlags)
 		},
 	}
@@ -62,8 +75,10 @@ func main() {
 	configFlags.AddFlags(rootCmd.PersistentFlags())
 
 	rootCmd.AddCommand(certificate.NewCmd())
+	rootCmd.AddCommand(clone.NewCmd())
 	rootCmd.AddCommand(destroy.NewCmd())
 	rootCmd.AddCommand(fence.NewCmd())
+	rootCmd.AddCommand(fleet.NewCmd())
 	rootCmd.AddCommand(hibernate.NewCmd())
 	rootCmd.AddCommand(maintenance.NewCmd())
 	rootCmd.AddCommand(promote.NewCmd())
@@ -74,8 +89,15 @@ func main() {
 	rootCmd.AddCommand(versions.NewCmd())
 	rootCmd.AddCommand(pgbench.NewCmd())
 	rootCmd.AddCommand(install.NewCmd())
+	rootCmd.AddCommand(recoverability.NewCmd())
+	rootCmd.AddCommand(rebuild.NewCmd())
+	rootCmd.AddCommand(pprof.NewCmd())
+	rootCmd.AddCommand(drill.NewCmd())
+	rootCmd.AddCommand(psql.NewCmd())
+	rootCmd.AddCommand(upgradecheck.NewCmd())
 
 	if err := rootCmd.Execute(); err != nil {
+		plugin.PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -25,12 +25,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/backup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/backupencryption"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/bootstrap"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/debug"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/extension"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/velero"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/waldecrypt"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -52,11 +57,16 @@ func main() {
 	logFlags.AddFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(backup.NewCmd())
+	cmd.AddCommand(backupencryption.NewCmd())
 	cmd.AddCommand(bootstrap.NewCmd())
 	cmd.AddCommand(controller.NewCmd())
+	cmd.AddCommand(debug.NewCmd())
+	cmd.AddCommand(extension.NewCmd())
 	cmd.AddCommand(instance.NewCmd())
 	cmd.AddCommand(show.NewCmd())
+	cmd.AddCommand(velero.NewCmd())
 	cmd.AddCommand(walarchive.NewCmd())
+	cmd.AddCommand(waldecrypt.NewCmd())
 	cmd.AddCommand(walrestore.NewCmd())
 	cmd.AddCommand(versions.NewCmd())
 	cmd.AddCommand(pgbouncer.NewCmd())
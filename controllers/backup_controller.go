@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -53,8 +54,9 @@ const backupPhase = ".status.phase"
 // BackupReconciler reconciles a Backup object
 type BackupReconciler struct {
 	client.Client
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
+	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
+	Capabilities *utils.CapabilitiesRegistry
 }
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch;create;update;patch;delete
@@ -109,6 +111,13 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	contextLogger.Debug("Found cluster for backup", "cluster", clusterName)
 
+	if err := r.checkBackupMethod(&backup, &cluster); err != nil {
+		contextLogger.Info("Cannot take the backup", "method", backup.GetMethod(), "reason", err.Error())
+		backup.Status.SetAsFailed(err)
+		r.Recorder.Eventf(&backup, "Warning", "BackupMethod", "Cannot take the backup: %s", err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, &backup)
+	}
+
 	// Detect the pod where a backup will be executed
 	var pod corev1.Pod
 	err := r.Get(ctx, client.ObjectKey{
@@ -168,6 +177,24 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		r.Recorder.Eventf(&backup, "Normal", "ReStarting",
 			"Restarted backup for cluster %v on instance %v", clusterName, pod.Name)
 	} else {
+		limitReached, err := r.isBackupConcurrencyLimitReached(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if limitReached {
+			contextLogger.Info("Maximum number of concurrent backups reached, will retry in 30 seconds",
+				"backupMaxConcurrency", configuration.Current.BackupMaxConcurrency)
+			if backup.Status.Phase != apiv1.BackupPhasePending {
+				r.Recorder.Eventf(&backup, "Normal", "BackupPending",
+					"Maximum number of concurrent backups reached (%d)", configuration.Current.BackupMaxConcurrency)
+				backup.Status.Phase = apiv1.BackupPhasePending
+				if err := r.Status().Update(ctx, &backup); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
 		// We need to start a backup
 		r.Recorder.Eventf(&backup, "Normal", "Starting", "Starting backup for cluster %v", clusterName)
 	}
@@ -185,6 +212,73 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, err
 }
 
+// checkBackupMethod checks that the backup method requested by the
+// backup is configured in the cluster and supported by Kubernetes
+func (r *BackupReconciler) checkBackupMethod(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
+	if backup.IsPlugin() {
+		return checkBackupPlugin(backup, cluster)
+	}
+
+	if !backup.IsVolumeSnapshot() {
+		return nil
+	}
+
+	if !cluster.Spec.Backup.IsVolumeSnapshotBackupConfigured() {
+		return fmt.Errorf("the volumeSnapshot backup method is not configured in cluster %s", cluster.Name)
+	}
+
+	if !r.Capabilities.Get().HaveVolumeSnapshot {
+		return fmt.Errorf("the %s/v1 VolumeSnapshot API is not available in the Kubernetes cluster",
+			specs.VolumeSnapshotAPIGroup)
+	}
+
+	return nil
+}
+
+// checkBackupPlugin checks that the plugin requested by the backup runs
+// as a sidecar of the instances of the cluster
+func checkBackupPlugin(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
+	if backup.Spec.PluginConfiguration == nil || backup.Spec.PluginConfiguration.Name == "" {
+		return fmt.Errorf("the plugin taking the backup is not specified")
+	}
+
+	name := backup.Spec.PluginConfiguration.Name
+	if _, found := cluster.GetPlugin(name); !found {
+		return fmt.Errorf("the plugin %s is not declared in cluster %s", name, cluster.Name)
+	}
+
+	return nil
+}
+
+// countRunningBackups returns the number of backups in the passed list that
+// are currently being taken
+func countRunningBackups(backups []apiv1.Backup) int {
+	running := 0
+	for _, backup := range backups {
+		if backup.Status.Phase == apiv1.BackupPhaseStarted || backup.Status.Phase == apiv1.BackupPhaseRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// isBackupConcurrencyLimitReached checks if the number of base backups being
+// taken across all the clusters managed by the operator has reached the limit
+// set in the operator configuration
+func (r *BackupReconciler) isBackupConcurrencyLimitReached(ctx context.Context) (bool, error) {
+	maxConcurrency := configuration.Current.BackupMaxConcurrency
+	if maxConcurrency <= 0 {
+		return false, nil
+	}
+
+	var backups apiv1.BackupList
+	if err := r.List(ctx, &backups); err != nil {
+		return false, fmt.Errorf("while listing backups: %w", err)
+	}
+
+	return countRunningBackups(backups.Items) >= maxConcurrency, nil
+}
+
 // StartBackup request a backup in a Pod and marks the backup started
 // or failed if needed
 func StartBackup(
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup concurrency", func() {
+	It("counts only the backups being taken", func() {
+		backups := []apiv1.Backup{
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseStarted}},
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning}},
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhasePending}},
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted}},
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseFailed}},
+			{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseWalArchivingFailing}},
+			{},
+		}
+		Expect(countRunningBackups(backups)).To(Equal(2))
+	})
+})
+
+var _ = Describe("backup method", func() {
+	reconciler := &BackupReconciler{Capabilities: utils.NewCapabilitiesRegistry(nil, nil, 0)}
+	snapshotBackup := &apiv1.Backup{Spec: apiv1.BackupSpec{Method: apiv1.BackupMethodVolumeSnapshot}}
+
+	It("doesn't check the barmanObjectStore backups", func() {
+		Expect(reconciler.checkBackupMethod(&apiv1.Backup{}, &apiv1.Cluster{})).To(Succeed())
+	})
+
+	It("requires the volumeSnapshot method to be configured in the cluster", func() {
+		err := reconciler.checkBackupMethod(snapshotBackup, &apiv1.Cluster{})
+		Expect(err).To(MatchError(ContainSubstring("not configured")))
+	})
+
+	It("requires the VolumeSnapshot API", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{}},
+			},
+		}
+		err := reconciler.checkBackupMethod(snapshotBackup, cluster)
+		Expect(err).To(MatchError(ContainSubstring("VolumeSnapshot API")))
+	})
+
+	It("requires the plugin to be declared in the cluster", func() {
+		pluginBackup := &apiv1.Backup{Spec: apiv1.BackupSpec{Method: apiv1.BackupMethodPlugin}}
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Plugins: []apiv1.PluginConfiguration{{Name: "pgbackrest", Image: "pgbackrest:latest"}},
+			},
+		}
+		err := reconciler.checkBackupMethod(pluginBackup, cluster)
+		Expect(err).To(MatchError(ContainSubstring("not specified")))
+
+		pluginBackup.Spec.PluginConfiguration = &apiv1.BackupPluginConfiguration{Name: "walg"}
+		err = reconciler.checkBackupMethod(pluginBackup, cluster)
+		Expect(err).To(MatchError(ContainSubstring("not declared")))
+
+		pluginBackup.Spec.PluginConfiguration.Name = "pgbackrest"
+		Expect(reconciler.checkBackupMethod(pluginBackup, cluster)).To(Succeed())
+	})
+})
+
+var _ = Describe("scheduled backup jitter", func() {
+	var previousJitter int
+	scheduleTime := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	scheduledBackup := &apiv1.ScheduledBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+	}
+
+	BeforeEach(func() {
+		previousJitter = configuration.Current.BackupScheduleJitter
+	})
+
+	AfterEach(func() {
+		configuration.Current.BackupScheduleJitter = previousJitter
+	})
+
+	It("adds no delay when the jitter is disabled", func() {
+		configuration.Current.BackupScheduleJitter = 0
+		Expect(getScheduleJitter(scheduledBackup, scheduleTime)).To(BeZero())
+	})
+
+	It("adds a stable delay within the configured limit", func() {
+		configuration.Current.BackupScheduleJitter = 600
+		jitter := getScheduleJitter(scheduledBackup, scheduleTime)
+		Expect(jitter).To(BeNumerically(">=", 0))
+		Expect(jitter).To(BeNumerically("<", 600*time.Second))
+		Expect(getScheduleJitter(scheduledBackup, scheduleTime)).To(Equal(jitter))
+	})
+
+	It("spreads the scheduled backups sharing the same schedule", func() {
+		configuration.Current.BackupScheduleJitter = 3600
+		delays := make(map[time.Duration]bool)
+		for _, name := range []string{"first", "second", "third", "fourth"} {
+			delays[getScheduleJitter(&apiv1.ScheduledBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			}, scheduleTime)] = true
+		}
+		Expect(len(delays)).To(BeNumerically(">", 1))
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// capabilitiesGroups are the API groups whose resources are detected
+// by the capabilities registry
+var capabilitiesGroups = map[string]bool{
+	"cert-manager.io":         true,
+	"keda.sh":                 true,
+	"kyverno.io":              true,
+	"monitoring.coreos.com":   true,
+	"security.openshift.io":   true,
+	"snapshot.storage.k8s.io": true,
+	"templates.gatekeeper.sh": true,
+}
+
+// CapabilitiesReconciler triggers a new detection of the capabilities of the
+// Kubernetes cluster every time a CustomResourceDefinition of a detected
+// resource is created or deleted
+type CapabilitiesReconciler struct {
+	Capabilities *utils.CapabilitiesRegistry
+}
+
+// Reconcile triggers a new detection of the capabilities
+func (r *CapabilitiesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log.FromContext(ctx).Debug("CustomResourceDefinition changed, detecting the capabilities",
+		"name", req.Name)
+	r.Capabilities.Trigger()
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager setup this controller inside the controller manager.
+// Only the metadata of the CustomResourceDefinitions is watched
+func (r *CapabilitiesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	crdPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		// The name of a CRD is "<plural>.<group>"
+		_, group, _ := strings.Cut(object.GetName(), ".")
+		return capabilitiesGroups[group]
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("capabilities").
+		For(&apiextensionsv1.CustomResourceDefinition{},
+			builder.OnlyMetadata,
+			builder.WithPredicates(crdPredicate, predicate.Funcs{
+				// Only the creation and the deletion of a CRD
+				// change the available resources
+				UpdateFunc: func(event.UpdateEvent) bool { return false },
+			})).
+		Complete(r)
+}
+
+// capabilitiesChangesSource creates a source generating an event for
+// every object returned by the passed function when the capabilities
+// of the Kubernetes cluster change
+func capabilitiesChangesSource(
+	registry *utils.CapabilitiesRegistry,
+	listObjects func(ctx context.Context) ([]client.Object, error),
+) source.Source {
+	events := make(chan event.GenericEvent)
+	registry.AddListener(func(ctx context.Context, _, _ utils.ClusterCapabilities) {
+		objects, err := listObjects(ctx)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Cannot list the objects to be reconciled after "+
+				"a change in the capabilities")
+			return
+		}
+
+		for _, object := range objects {
+			select {
+			case events <- event.GenericEvent{Object: object}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	return &source.Channel{Source: events}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// admissionRejectedRetryInterval is the interval after which the creation
+// of a child resource rejected by the admission policies is retried
+const admissionRejectedRetryInterval = time.Minute
+
+// admissionRejectionMessages are the messages reported by the admission
+// webhooks, by the validating admission policies and by the Pod Security
+// admission when they deny the creation of a resource
+var admissionRejectionMessages = []string{
+	"denied the request",
+	"denied request",
+	"violates PodSecurity",
+}
+
+// isAdmissionRejection checks whether the passed error has been raised by
+// an admission webhook, or by a validating admission policy, denying the
+// creation of a resource. The Forbidden errors raised by the resource
+// quotas and by the RBAC authorization are not admission rejections
+func isAdmissionRejection(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if !apierrs.IsForbidden(err) && !apierrs.IsBadRequest(err) && !apierrs.IsInvalid(err) {
+		return false
+	}
+
+	for _, message := range admissionRejectionMessages {
+		if strings.Contains(err.Error(), message) {
+			return true
+		}
+	}
+	return false
+}
+
+// getAdmissionDryRunObjects returns the resources whose creation needs to be
+// dry-run to check the passed child resource. As the policy engines usually
+// validate the pods, a Job is checked together with the Pod described by its
+// template
+func getAdmissionDryRunObjects(object client.Object) []client.Object {
+	result := []client.Object{object}
+	job, ok := object.(*batchv1.Job)
+	if !ok {
+		return result
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        job.Name,
+			Namespace:   job.Namespace,
+			Labels:      job.Spec.Template.Labels,
+			Annotations: job.Spec.Template.Annotations,
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+	return append(result, pod)
+}
+
+// checkAdmissionPolicies dry-runs the creation of the passed child resource,
+// and of the Pod it describes if it is a Job, when a policy engine is installed in the Kubernetes cluster, updating
+// the AdmissionPolicies condition of the cluster. When the resource is
+// rejected, a warning event is raised and a non-empty result is returned,
+// so that the actual creation is postponed instead of being retried in a
+// tight loop
+func (r *ClusterReconciler) checkAdmissionPolicies(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	object client.Object,
+) (*ctrl.Result, error) {
+	if !r.Capabilities.Get().HavePolicyEngine() {
+		if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionAdmissionPolicies)) == nil {
+			return nil, nil
+		}
+
+		existingCluster := cluster.DeepCopy()
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionAdmissionPolicies))
+		return nil, r.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster))
+	}
+
+	var err error
+	var rejectedObject client.Object
+	for _, candidate := range getAdmissionDryRunObjects(object) {
+		dryRunObject, ok := candidate.DeepCopyObject().(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T", candidate)
+		}
+		err = r.Create(ctx, dryRunObject, client.DryRunAll)
+		if err != nil && !isAdmissionRejection(err) {
+			// We can't tell if the resource will be admitted, let the
+			// actual creation report the error
+			return nil, nil
+		}
+		if err != nil {
+			rejectedObject = candidate
+			break
+		}
+	}
+
+	if err == nil {
+		return nil, conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionAdmissionPolicies),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonResourcesAdmitted),
+			Message: "The child resources of the cluster are admitted by the admission policies",
+		})
+	}
+
+	kind := fmt.Sprintf("%T", rejectedObject)
+	if gvk, err := apiutil.GVKForObject(rejectedObject, r.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+	message := fmt.Sprintf("The creation of %s %s has been rejected: %s", kind, rejectedObject.GetName(), err.Error())
+	log.FromContext(ctx).Warning("Child resource rejected by the admission policies",
+		"kind", kind, "name", rejectedObject.GetName(), "error", err.Error())
+	if !meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionAdmissionPolicies)) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonResourceRejected), message)
+	}
+
+	if err := conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionAdmissionPolicies),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonResourceRejected),
+		Message: message,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: admissionRejectedRetryInterval}, nil
+}
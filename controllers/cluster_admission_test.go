@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admission rejection detection", func() {
+	podResource := schema.GroupResource{Resource: "pods"}
+
+	It("detects the resources forbidden by an admission policy", func() {
+		err := apierrs.NewForbidden(podResource, "cluster-example-1",
+			errors.New("admission webhook \"validate.kyverno.svc\" denied the request"))
+		Expect(isAdmissionRejection(err)).To(BeTrue())
+	})
+
+	It("detects the resources denied by a validating webhook", func() {
+		err := apierrs.NewBadRequest("admission webhook \"validation.gatekeeper.sh\" denied the request")
+		Expect(isAdmissionRejection(err)).To(BeTrue())
+	})
+
+	It("ignores the errors not related to the admission policies", func() {
+		Expect(isAdmissionRejection(nil)).To(BeFalse())
+		Expect(isAdmissionRejection(apierrs.NewAlreadyExists(podResource, "cluster-example-1"))).To(BeFalse())
+		Expect(isAdmissionRejection(apierrs.NewBadRequest("invalid request"))).To(BeFalse())
+	})
+
+	It("detects the resources denied by a validating admission policy or by the Pod Security admission", func() {
+		Expect(isAdmissionRejection(apierrs.NewInvalid(schema.GroupKind{Kind: "Pod"}, "cluster-example-1",
+			field.ErrorList{field.Forbidden(field.NewPath(""),
+				"ValidatingAdmissionPolicy 'require-labels' with binding 'require-labels' denied request")},
+		))).To(BeTrue())
+		Expect(isAdmissionRejection(apierrs.NewForbidden(podResource, "cluster-example-1",
+			errors.New("violates PodSecurity \"restricted:latest\": runAsNonRoot != true")))).To(BeTrue())
+	})
+
+	It("does not consider the resource quotas and the RBAC authorization as admission rejections", func() {
+		Expect(isAdmissionRejection(apierrs.NewForbidden(podResource, "cluster-example-1",
+			errors.New("exceeded quota: compute-resources, requested: limits.cpu=2, used: limits.cpu=4, limited: limits.cpu=4"),
+		))).To(BeFalse())
+		Expect(isAdmissionRejection(apierrs.NewForbidden(podResource, "cluster-example-1",
+			errors.New("User \"system:serviceaccount:cnpg-system:cnpg-manager\" cannot create resource \"pods\""),
+		))).To(BeFalse())
+	})
+})
+
+var _ = Describe("admission dry-run objects", func() {
+	It("checks a pod by itself", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"}}
+		Expect(getAdmissionDryRunObjects(pod)).To(Equal([]client.Object{pod}))
+	})
+
+	It("checks a job together with the pod described by its template", func() {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1-initdb", Namespace: "default"},
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "initdb"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "bootstrap-controller", Image: "postgres:16"}},
+					},
+				},
+			},
+		}
+
+		objects := getAdmissionDryRunObjects(job)
+		Expect(objects).To(HaveLen(2))
+		Expect(objects[0]).To(Equal(job))
+		pod, ok := objects[1].(*corev1.Pod)
+		Expect(ok).To(BeTrue())
+		Expect(pod.Name).To(Equal("cluster-example-1-initdb"))
+		Expect(pod.Namespace).To(Equal("default"))
+		Expect(pod.Labels).To(HaveKeyWithValue("app", "initdb"))
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+	})
+})
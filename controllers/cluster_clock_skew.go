@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// estimateClockSkew estimates the difference between the clock of an
+// instance and the one of the operator, assuming that the heartbeat has been
+// taken halfway through the status request
+func estimateClockSkew(sentAt, receivedAt, heartbeat time.Time) time.Duration {
+	return heartbeat.Sub(sentAt.Add(receivedAt.Sub(sentAt) / 2))
+}
+
+// markClockSkewedInstances flags the instances whose clock skew is above
+// the passed maximum. The instances not reporting a heartbeat, such as the
+// ones running an older instance manager, are never flagged
+func markClockSkewedInstances(status postgres.PostgresqlStatusList, maxClockSkew time.Duration) {
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		item.IsClockSkewed = item.Error == nil && item.Heartbeat != nil &&
+			(item.ClockSkew > maxClockSkew || item.ClockSkew < -maxClockSkew)
+	}
+}
+
+// buildClockSkewCondition creates the ClockSynchronized condition given
+// the status of the instances. The message doesn't contain the measured
+// skews, as they change at every status request
+func buildClockSkewCondition(
+	instancesStatus postgres.PostgresqlStatusList,
+	maxClockSkew time.Duration,
+) *metav1.Condition {
+	var skewed []string
+	for _, item := range instancesStatus.Items {
+		if item.IsClockSkewed {
+			skewed = append(skewed, item.Pod.Name)
+		}
+	}
+	sort.Strings(skewed)
+
+	if len(skewed) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionClockSynchronized),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonClockSynchronized),
+			Message: "The clocks of every instance are synchronized",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionClockSynchronized),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonClockSkewDetected),
+		Message: fmt.Sprintf("Instances whose clock is skewed by more than %v: %s",
+			maxClockSkew, strings.Join(skewed, ", ")),
+	}
+}
+
+// hasHeartbeats checks if at least one instance reported its heartbeat
+func hasHeartbeats(instancesStatus postgres.PostgresqlStatusList) bool {
+	for _, item := range instancesStatus.Items {
+		if item.Error == nil && item.Heartbeat != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileClockSkew sets the ClockSynchronized condition using the
+// heartbeats reported by the instances, raising an event when a clock skew
+// is detected
+func (r *ClusterReconciler) reconcileClockSkew(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	if !hasHeartbeats(instancesStatus) {
+		return nil
+	}
+
+	condition := buildClockSkewCondition(instancesStatus, cluster.GetMaxClockSkew())
+	if condition.Status == metav1.ConditionFalse &&
+		!meta.IsStatusConditionFalse(cluster.Status.Conditions, condition.Type) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonClockSkewDetected), condition.Message)
+	}
+
+	return conditions.Update(ctx, r.Client, cluster, condition)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clock skew", func() {
+	heartbeat := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newStatus := func(podName string, clockSkew time.Duration) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			Heartbeat: &heartbeat,
+			ClockSkew: clockSkew,
+		}
+	}
+
+	It("estimates the skew from the middle of the request", func() {
+		sentAt := heartbeat.Add(-10 * time.Second)
+		Expect(estimateClockSkew(sentAt, sentAt.Add(2*time.Second), heartbeat)).To(Equal(9 * time.Second))
+		Expect(estimateClockSkew(heartbeat, heartbeat.Add(2*time.Second), heartbeat)).To(Equal(-time.Second))
+	})
+
+	It("flags the instances whose clock is skewed above the maximum", func() {
+		instancesStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", time.Second),
+			newStatus("cluster-example-2", -10*time.Second),
+			newStatus("cluster-example-3", 10*time.Second),
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}}, ClockSkew: time.Hour},
+		}}
+		markClockSkewedInstances(instancesStatus, 5*time.Second)
+		Expect(instancesStatus.Items[0].IsClockSkewed).To(BeFalse())
+		Expect(instancesStatus.Items[1].IsClockSkewed).To(BeTrue())
+		Expect(instancesStatus.Items[2].IsClockSkewed).To(BeTrue())
+		Expect(instancesStatus.Items[3].IsClockSkewed).To(BeFalse())
+
+		condition := buildClockSkewCondition(instancesStatus, 5*time.Second)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(Equal(
+			"Instances whose clock is skewed by more than 5s: cluster-example-2, cluster-example-3"))
+	})
+
+	It("sets the condition and raises an event when a skew is detected", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: recorder,
+		}
+
+		By("ignoring the instances not reporting a heartbeat", func() {
+			Expect(r.reconcileClockSkew(context.Background(), cluster, postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{{}},
+			})).To(Succeed())
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+
+		synchronized := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", 0),
+		}}
+		Expect(r.reconcileClockSkew(context.Background(), cluster, synchronized)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			string(apiv1.ConditionClockSynchronized))).To(BeTrue())
+
+		skewed := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", time.Minute),
+		}}
+		markClockSkewedInstances(skewed, cluster.GetMaxClockSkew())
+		Expect(r.reconcileClockSkew(context.Background(), cluster, skewed)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionClockSynchronized))).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		// The event is raised only when the condition changes
+		Expect(r.reconcileClockSkew(context.Background(), cluster, skewed)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+})
@@ -35,11 +35,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -50,7 +50,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/witness"
 )
 
 const (
@@ -67,15 +69,26 @@ var apiGVString = apiv1.GroupVersion.String()
 type ClusterReconciler struct {
 	client.Client
 
-	DiscoveryClient *discovery.DiscoveryClient
-	Scheme          *runtime.Scheme
-	Recorder        record.EventRecorder
+	Capabilities *utils.CapabilitiesRegistry
+	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
+
+	// WebhookSecretName is the name of the secret, in the namespace of
+	// the operator, containing the certificate of the webhooks when the
+	// operator manages it
+	WebhookSecretName string
 
 	timeoutHTTPClient *http.Client
+
+	witnessClientProvider witness.ClientProvider
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
-func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.DiscoveryClient) *ClusterReconciler {
+func NewClusterReconciler(
+	mgr manager.Manager,
+	capabilities *utils.CapabilitiesRegistry,
+	webhookSecretName string,
+) *ClusterReconciler {
 	const connectionTimeout = 2 * time.Second
 	const requestTimeout = 30 * time.Second
 
@@ -93,10 +106,11 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 	return &ClusterReconciler{
 		timeoutHTTPClient: timeoutClient,
 
-		DiscoveryClient: discoveryClient,
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg"),
+		Capabilities:      capabilities,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Recorder:          mgr.GetEventRecorderFor("cloudnative-pg"),
+		WebhookSecretName: webhookSecretName,
 	}
 }
 
@@ -107,25 +121,37 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // Alphabetical order to not repeat or miss permissions
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;update;list;patch
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list;patch
-// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=patch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=subscriptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=subscriptions/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=configmaps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
-// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
@@ -154,6 +180,14 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				"namespace", req.Namespace,
 			)
 		}
+		if err := r.deleteEndpointsRegistryEntry(ctx, req.Namespace, req.Name); err != nil {
+			contextLogger.Error(
+				err,
+				"error while removing the cluster from the endpoints registry",
+				"configMapName", apiv1.EndpointsRegistryConfigMapName,
+				"namespace", req.Namespace,
+			)
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -175,6 +209,21 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, nil
 	}
 
+	if deleted, err := r.deleteExpiredCluster(ctx, cluster); err != nil || deleted {
+		return ctrl.Result{}, err
+	}
+
+	// The only work to be done on a deleted cluster is the final backup,
+	// while Kubernetes waits for the finalizer to be removed
+	if !cluster.DeletionTimestamp.IsZero() &&
+		controllerutil.ContainsFinalizer(cluster, utils.FinalBackupFinalizerName) {
+		return r.reconcileFinalBackup(ctx, cluster)
+	}
+
+	if err := r.reconcileFinalBackupFinalizer(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the final backup finalizer: %w", err)
+	}
+
 	// IMPORTANT: the following call will delete conditions using
 	// invalid condition reasons.
 	//
@@ -195,16 +244,40 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	// The image of the instances can be taken from an image catalog,
+	// and nothing can be done until it is resolved
+	if result, err := r.reconcileImage(ctx, cluster); result != nil || err != nil {
+		if result == nil {
+			return ctrl.Result{}, fmt.Errorf("cannot resolve the image from the catalog: %w", err)
+		}
+		return *result, err
+	}
+
 	// Ensure we reconcile the orphan resources if present when we reconcile for the first time a cluster
 	if err := r.reconcileRestoredCluster(ctx, cluster); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot reconcile restored Cluster: %w", err)
 	}
 
+	if err := r.liftExpiredVeleroFencing(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot lift the expired Velero fence: %w", err)
+	}
+
 	// Ensure we have the required global objects
 	if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
 	}
 
+	if err := r.reconcilePgHBARules(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling the pg_hba rules", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the pg_hba rules: %w", err)
+	}
+
 	// Update the status of this resource
 	resources, err := r.getManagedResources(ctx, cluster)
 	if err != nil {
@@ -224,6 +297,16 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
 	}
 
+	// A split-brain requires a manual intervention, and no automated
+	// action should be taken until the user acknowledges it
+	if paused, err := r.isSplitBrainPaused(ctx, cluster); err != nil || paused {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePoolersDuringSwitchover(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if cluster.Status.CurrentPrimary != "" &&
 		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
 		contextLogger.Info("There is a switchover or a failover "+
@@ -234,8 +317,27 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// A major version upgrade replaces the instances with a job working
+	// on the volumes of the primary, and nothing else should be done
+	// until it is finished
+	if result, err := r.reconcileMajorUpgrade(ctx, cluster, resources); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return result, err
+	}
+
+	// A hibernated cluster has no instances, and all the following
+	// steps would recreate them
+	if result, err := r.reconcileHibernation(ctx, cluster, resources); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return result, err
+	}
+
 	// Get the replication status
-	instancesStatus := r.getStatusFromInstances(ctx, resources.instances)
+	instancesStatus := r.getStatusFromInstances(ctx, cluster, resources.instances)
 
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
@@ -247,6 +349,20 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
 	}
 
+	if err := r.reconcileStreamingConditions(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the streaming condition of the instances: %w", err)
+	}
+
+	if splitBrain, err := r.reconcileSplitBrain(ctx, cluster, instancesStatus); err != nil || splitBrain {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Verify the architecture of all the instances and update the OnlineUpdateEnabled
 	// field in the status
 	onlineUpdateEnabled := configuration.Current.EnableInstanceManagerInplaceUpdates
@@ -256,6 +372,34 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		onlineUpdateEnabled = false
 	}
 
+	if err := r.reconcileReplicationEncryption(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the replication encryption condition: %w", err)
+	}
+
+	if err := r.reconcileClockSkew(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the clock synchronization condition: %w", err)
+	}
+
+	if err := r.reconcileRewindPrerequisites(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the rewind availability condition: %w", err)
+	}
+
+	if err := r.reconcileStorageCapacity(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the storage capacity condition: %w", err)
+	}
+
 	// The instance list is sorted and will present the primary as the first
 	// element, followed by the replicas, the most updated coming first.
 	// Pods that are not responding will be at the end of the list. We use
@@ -353,6 +497,10 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if err == ErrFailoverBlockedByWitness {
+			contextLogger.Info("Waiting for the failover witness lease to expire to elect a new primary")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"cluster", cluster.Name)
@@ -431,6 +579,21 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, fmt.Errorf("cannot update role labels on pods: %w", err)
 	}
 
+	// Apply the routing policy of the -ro service
+	if err := r.reconcileReadOnlyService(ctx, cluster, resources.instances, instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the read-only service: %w", err)
+	}
+
+	// Publish the read-only endpoints for the postgres_fdw servers of the other clusters
+	if err := r.reconcileEndpointsRegistry(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the endpoints registry: %w", err)
+	}
+
+	// Publish the service discovery records for the applications
+	if err := r.reconcileServiceDiscoveryConfigMap(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the service discovery configmap: %w", err)
+	}
+
 	// updated any labels that are coming from the operator
 	if err := r.updateOperatorLabelsOnInstances(ctx, resources.instances); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot update instance labels on pods: %w", err)
@@ -461,6 +624,15 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, fmt.Errorf("cannot update annotations on pvcs: %w", err)
 	}
 
+	// The volumes serving a base backup are changed while the new replicas
+	// are being cloned too, so this can't wait for the jobs to complete
+	if err := r.reconcilePVCsVolumeAttributesClass(ctx, cluster, resources); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Act on Pods and PVCs only if there is nothing that is currently being created or deleted
 	if runningJobs := resources.countRunningJobs(); runningJobs > 0 {
 		contextLogger.Debug("A job is currently running. Waiting", "count", runningJobs)
@@ -497,23 +669,39 @@ func (r *ClusterReconciler) reconcileResources(
 	}
 
 	if len(resources.instances.Items) > 0 && resources.noInstanceIsAlive() {
-		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.RegisterPhase(ctx, cluster, apiv1.PhaseUnrecoverable,
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.RegisterPhaseWithReasonCode(ctx, cluster,
+			apiv1.PhaseUnrecoverable, apiv1.ConditionReasonNoActiveInstances,
 			"No pods are active, the cluster needs manual intervention ")
 	}
 
+	if podName, message := resources.getImagePullFailure(); podName != "" {
+		contextLogger.Warning("Cannot pull the image of an instance", "podName", podName, "message", message)
+		if err := r.RegisterPhaseWithReasonCode(ctx, cluster, cluster.Status.Phase,
+			apiv1.ConditionReasonInvalidImage,
+			fmt.Sprintf("Cannot pull the image of instance %s: %s", podName, message)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// If we still need more instances, we need to wait before setting healthy status
 	if instancesStatus.InstancesReportingStatus() != cluster.Spec.Instances {
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
 	// When everything is reconciled, update the status
-	if err = r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, ""); err != nil {
+	if err = r.registerHealthyPhase(ctx, cluster); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	r.cleanupCompletedJobs(ctx, resources.jobs)
 
-	return ctrl.Result{}, nil
+	var finalResult ctrl.Result
+	if hasPgHBAResourceReferences(cluster) {
+		finalResult.RequeueAfter = pgHBARulesRefreshInterval
+	}
+
+	finalResult = requeueBeforeVeleroFenceExpiration(cluster, finalResult)
+	return requeueBeforeExpiration(cluster, finalResult), nil
 }
 
 // deleteEvictedPods will delete the Pods that the Kubelet has evicted
@@ -549,6 +737,57 @@ func (r *ClusterReconciler) deleteEvictedPods(ctx context.Context, cluster *apiv
 	return nil, nil
 }
 
+// newLongRunningTransactionsState returns the state, stored in the status of
+// the cluster, of the long-running transactions and of the lock chains
+// detected in an instance, or nil if there are none. The start time of
+// the oldest transaction is stored with the precision of the status, so
+// that it doesn't change until the transaction ends
+func newLongRunningTransactionsState(
+	transactions *postgres.LongRunningTransactions,
+) *apiv1.LongRunningTransactionsState {
+	if transactions.IsEmpty() {
+		return nil
+	}
+
+	state := &apiv1.LongRunningTransactionsState{
+		Transactions:    transactions.Transactions + transactions.PreparedTransactions,
+		BlockedSessions: transactions.BlockedSessions,
+	}
+	if transactions.OldestTransactionStart != nil {
+		start := metav1.NewTime(transactions.OldestTransactionStart.Truncate(time.Second).Local())
+		state.OldestTransactionStart = &start
+	}
+
+	return state
+}
+
+// reportLongRunningTransactions raises a warning event when an instance
+// starts reporting long-running transactions, prepared transactions left
+// open or sessions blocked by locks, and a normal event when it stops, if
+// requested by the user. The messages don't change with the transactions,
+// which are described by the status of the cluster, so that the events
+// of an instance are aggregated
+func (r *ClusterReconciler) reportLongRunningTransactions(
+	cluster *apiv1.Cluster,
+	podName string,
+	previous, current *apiv1.LongRunningTransactionsState,
+) {
+	if !cluster.AreLongRunningTransactionsEventsEnabled() {
+		return
+	}
+
+	switch {
+	case previous == nil && current != nil:
+		r.Recorder.Eventf(cluster, "Warning", "LongRunningTransactions",
+			"Instance %s has long-running transactions, prepared transactions or sessions blocked by locks",
+			podName)
+	case previous != nil && current == nil:
+		r.Recorder.Eventf(cluster, "Normal", "LongRunningTransactionsResolved",
+			"Instance %s has no more long-running transactions, prepared transactions or sessions blocked by locks",
+			podName)
+	}
+}
+
 // checkPodsArchitecture checks whether the architecture of the instances is consistent with the runtime one
 func (r *ClusterReconciler) checkPodsArchitecture(ctx context.Context, status *postgres.PostgresqlStatusList) bool {
 	contextLogger := log.FromContext(ctx)
@@ -591,16 +830,18 @@ func (r *ClusterReconciler) ReconcilePVCs(ctx context.Context, cluster *apiv1.Cl
 		return nil
 	}
 
-	// Size is empty would due to size is defined through request and not changed yet
-	if cluster.Spec.StorageConfiguration.Size == "" {
-		return nil
-	}
-	quantity, err := resource.ParseQuantity(cluster.Spec.StorageConfiguration.Size)
-	if err != nil {
-		return fmt.Errorf("while parsing PVC size %v: %w", cluster.Spec.StorageConfiguration.Size, err)
-	}
-
 	for idx := range resources.pvcs.Items {
+		size := getExpectedPVCSize(cluster, &resources.pvcs.Items[idx])
+
+		// Size is empty would due to size is defined through request and not changed yet
+		if size == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(size)
+		if err != nil {
+			return fmt.Errorf("while parsing PVC size %v: %w", size, err)
+		}
+
 		oldPVC := resources.pvcs.Items[idx].DeepCopy()
 		oldQuantity, ok := resources.pvcs.Items[idx].Spec.Resources.Requests["storage"]
 
@@ -636,6 +877,151 @@ func (r *ClusterReconciler) ReconcilePVCs(ctx context.Context, cluster *apiv1.Cl
 	return nil
 }
 
+// reconcilePVCsVolumeAttributesClass applies in place the VolumeAttributesClass
+// requested in the storage configuration to the PVCs of the cluster. The
+// PVCs of the instances serving a base backup use the class requested for
+// the backups, if any
+func (r *ClusterReconciler) reconcilePVCsVolumeAttributesClass(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	backupSources, err := r.getBaseBackupSources(ctx, cluster, resources)
+	if err != nil {
+		return err
+	}
+
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		servingBaseBackup := backupSources.Has(pvc.Labels[utils.InstanceNameLabelName])
+		className := getExpectedPVCVolumeAttributesClass(cluster, pvc, servingBaseBackup)
+		if className == nil || pvc.Annotations[specs.PVCVolumeAttributesClassAnnotationName] == *className {
+			continue
+		}
+
+		contextLogger.Info("Changing the VolumeAttributesClass of the PVC",
+			"pvcName", pvc.Name,
+			"from", pvc.Annotations[specs.PVCVolumeAttributesClassAnnotationName],
+			"to", *className)
+		if err := r.Patch(ctx, pvc, specs.VolumeAttributesClassPatch(*className)); err != nil {
+			return fmt.Errorf("while changing the VolumeAttributesClass of PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getBaseBackupSources gets the names of the instances a base backup is
+// being taken from, either by a Backup or by a new replica being cloned.
+// The backups are not listed when no class is requested for them
+func (r *ClusterReconciler) getBaseBackupSources(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*stringset.Data, error) {
+	result := stringset.New()
+	if !cluster.HasBackupVolumeAttributesClass() {
+		return result, nil
+	}
+
+	// The new replicas are cloned from the current primary
+	for _, job := range resources.jobs.Items {
+		if job.Labels[utils.JobRoleLabelName] == "join" && !utils.IsJobComplete(job) {
+			result.Put(cluster.Status.CurrentPrimary)
+		}
+	}
+
+	var backups apiv1.BackupList
+	if err := r.List(ctx, &backups, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing the backups: %w", err)
+	}
+	for idx := range backups.Items {
+		if podName, ok := getBaseBackupSource(cluster, &backups.Items[idx]); ok {
+			result.Put(podName)
+		}
+	}
+
+	return result, nil
+}
+
+// getBaseBackupSource gets the name of the instance the passed backup of
+// the cluster is copying the data files from, if it is running. The volume
+// snapshots don't read the data through the volumes
+func getBaseBackupSource(cluster *apiv1.Cluster, backup *apiv1.Backup) (string, bool) {
+	if backup.Spec.Cluster.Name != cluster.Name || backup.IsVolumeSnapshot() || backup.Status.InstanceID == nil {
+		return "", false
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseStarted && backup.Status.Phase != apiv1.BackupPhaseRunning {
+		return "", false
+	}
+
+	return backup.Status.InstanceID.PodName, true
+}
+
+// getPVCStorageConfiguration gets the storage configuration the passed
+// PVC has been created from, if any
+func getPVCStorageConfiguration(
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+) *apiv1.StorageConfiguration {
+	switch pvc.Labels[utils.PvcRoleLabelName] {
+	case string(utils.PVCRolePgWal):
+		return cluster.Spec.WalStorage
+	case string(utils.PVCRolePgTablespace):
+		if tablespace := cluster.GetTablespace(pvc.Labels[utils.TablespaceNameLabelName]); tablespace != nil {
+			return &tablespace.Storage
+		}
+		return nil
+	default:
+		return &cluster.Spec.StorageConfiguration
+	}
+}
+
+// getExpectedPVCVolumeAttributesClass gets the VolumeAttributesClass the
+// passed PVC should use, if any, depending on its instance serving a base
+// backup
+func getExpectedPVCVolumeAttributesClass(
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+	servingBaseBackup bool,
+) *string {
+	storage := getPVCStorageConfiguration(cluster, pvc)
+	if storage == nil {
+		return nil
+	}
+
+	if servingBaseBackup && storage.BackupVolumeAttributesClassName != nil {
+		return storage.BackupVolumeAttributesClassName
+	}
+	return storage.VolumeAttributesClassName
+}
+
+// getExpectedPVCSize gets the size the passed PVC should have, applying
+// the instance overrides to the PGDATA volumes
+func getExpectedPVCSize(cluster *apiv1.Cluster, pvc *corev1.PersistentVolumeClaim) string {
+	switch pvc.Labels[utils.PvcRoleLabelName] {
+	case string(utils.PVCRolePgData):
+	case string(utils.PVCRolePgTablespace):
+		if tablespace := cluster.GetTablespace(pvc.Labels[utils.TablespaceNameLabelName]); tablespace != nil {
+			return tablespace.Storage.Size
+		}
+		return ""
+	default:
+		return cluster.Spec.StorageConfiguration.Size
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+	if err != nil {
+		return cluster.Spec.StorageConfiguration.Size
+	}
+
+	overrideRole := apiv1.InstanceOverrideRole(pvc.Annotations[specs.InstanceOverrideRoleAnnotationName])
+	return cluster.GetInstanceStorageConfiguration(nodeSerial, overrideRole).Size
+}
+
 // ReconcilePods decides when to create, scale up/down or wait for pods
 func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cluster,
 	resources *managedResources, instancesStatus postgres.PostgresqlStatusList,
@@ -757,18 +1143,26 @@ func (r *ClusterReconciler) handleRollingUpdate(
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
-	// If we need to roll out a restart of any instance, this is the right moment
-	// Do I have to roll out a new image?
-	done, err := r.rolloutDueToCondition(ctx, cluster, &instancesStatus, IsPodNeedingRollout)
+	// The non-urgent restarts are deferred until the next maintenance window
+	untilNextWindow, err := r.deferRolloutToMaintenanceWindow(ctx, cluster, &instancesStatus)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if done {
-		// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
-		return ctrl.Result{}, ErrNextLoop
+
+	// If we need to roll out a restart of any instance, this is the right moment
+	// Do I have to roll out a new image?
+	if untilNextWindow == 0 {
+		done, err := r.rolloutDueToCondition(ctx, cluster, &instancesStatus, IsPodNeedingRollout)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if done {
+			// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
+			return ctrl.Result{}, ErrNextLoop
+		}
 	}
 
-	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
+	if untilNextWindow == 0 && instancesStatus.ArePodsWaitingForDecreasedSettings() {
 		// requeue and wait for the pods to be ready to be restarted,
 		// which will be handled by rolloutDueToCondition
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
@@ -792,7 +1186,7 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: untilNextWindow}, nil
 }
 
 // SetupWithManager creates a ClusterReconciler
@@ -802,7 +1196,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
 		Owns(&corev1.Pod{}).
 		Owns(&batchv1.Job{}).
@@ -824,11 +1218,58 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters(ctx)),
 		).
 		Watches(
+			&source.Kind{Type: &apiv1.Backup{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapBackupsToClusters()),
+			builder.WithPredicates(backupsPredicate),
+		).
+		Watches(
+			&source.Kind{Type: &apiv1.ImageCatalog{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapImageCatalogsToClusters(ctx)),
+		)
+
+	// The ClusterImageCatalogs are cluster-scoped, and cannot be watched
+	// when the operator is installed with namespaced RBAC
+	if r.Capabilities.Get().HaveClusterImageCatalogsAccess {
+		controllerBuilder = controllerBuilder.Watches(
+			&source.Kind{Type: &apiv1.ClusterImageCatalog{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapImageCatalogsToClusters(ctx)),
+		)
+	}
+
+	// Without the permission to watch the Nodes, the operator cannot
+	// react to a node being drained
+	if r.Capabilities.Get().HaveNodesAccess {
+		controllerBuilder = controllerBuilder.Watches(
 			&source.Kind{Type: &corev1.Node{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters(ctx)),
 			builder.WithPredicates(nodesPredicate),
-		).
-		Complete(r)
+		)
+	}
+
+	// The PodMonitors and the ServiceMonitors are created as soon as the
+	// Prometheus Operator is installed
+	if r.Capabilities != nil {
+		controllerBuilder = controllerBuilder.Watches(
+			capabilitiesChangesSource(r.Capabilities, r.listClusters),
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// listClusters lists every cluster managed by the operator
+func (r *ClusterReconciler) listClusters(ctx context.Context) ([]client.Object, error) {
+	var clusters apiv1.ClusterList
+	if err := r.List(ctx, &clusters); err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, len(clusters.Items))
+	for idx := range clusters.Items {
+		result[idx] = &clusters.Items[idx]
+	}
+	return result, nil
 }
 
 // createFieldIndexes creates the indexes needed by this controller
@@ -1024,6 +1465,19 @@ func (r *ClusterReconciler) mapPoolersToClusters(ctx context.Context) handler.Ma
 	}
 }
 
+// mapBackupsToClusters returns a function mapping backup events watched to cluster reconcile requests
+func (r *ClusterReconciler) mapBackupsToClusters() handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		backup, ok := obj.(*apiv1.Backup)
+		if !ok || backup.Spec.Cluster.Name == "" {
+			return nil
+		}
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: backup.Namespace, Name: backup.Spec.Cluster.Name},
+		}}
+	}
+}
+
 // mapNodeToClusters returns a function mapping cluster events watched to cluster reconcile requests
 func (r *ClusterReconciler) mapConfigMapsToClusters(ctx context.Context) handler.MapFunc {
 	return func(obj client.Object) []reconcile.Request {
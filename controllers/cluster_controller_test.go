@@ -17,9 +17,25 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -56,3 +72,202 @@ var _ = Describe("Filtering cluster", func() {
 		Expect(req).ToNot(BeNil())
 	})
 })
+
+var _ = Describe("expected PVC size", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{Name: "fast_disk", Storage: apiv1.StorageConfiguration{Size: "5Gi"}},
+			},
+		},
+	}
+
+	It("uses the size of the tablespace for its volumes", func() {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					utils.PvcRoleLabelName:        string(utils.PVCRolePgTablespace),
+					utils.TablespaceNameLabelName: "fast_disk",
+				},
+			},
+		}
+		Expect(getExpectedPVCSize(cluster, pvc)).To(Equal("5Gi"))
+
+		pvc.Labels[utils.TablespaceNameLabelName] = "unknown"
+		Expect(getExpectedPVCSize(cluster, pvc)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("expected PVC VolumeAttributesClass", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{
+				Size:                      "1Gi",
+				VolumeAttributesClassName: pointer.String("gold"),
+			},
+			WalStorage: &apiv1.StorageConfiguration{Size: "1Gi"},
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{
+					Name: "fast_disk",
+					Storage: apiv1.StorageConfiguration{
+						Size:                      "5Gi",
+						VolumeAttributesClassName: pointer.String("platinum"),
+					},
+				},
+			},
+		},
+	}
+
+	newPVC := func(role utils.PVCRole) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					utils.PvcRoleLabelName:        string(role),
+					utils.TablespaceNameLabelName: "fast_disk",
+				},
+			},
+		}
+	}
+
+	It("uses the VolumeAttributesClass of the storage of each volume", func() {
+		Expect(getExpectedPVCVolumeAttributesClass(cluster, newPVC(utils.PVCRolePgData), false)).
+			To(HaveValue(Equal("gold")))
+		Expect(getExpectedPVCVolumeAttributesClass(cluster, newPVC(utils.PVCRolePgWal), false)).
+			To(BeNil())
+		Expect(getExpectedPVCVolumeAttributesClass(cluster, newPVC(utils.PVCRolePgTablespace), false)).
+			To(HaveValue(Equal("platinum")))
+	})
+
+	It("uses the VolumeAttributesClass requested for the base backups when serving one", func() {
+		clusterWithBackupClass := cluster.DeepCopy()
+		clusterWithBackupClass.Spec.StorageConfiguration.BackupVolumeAttributesClassName = pointer.String("diamond")
+
+		Expect(getExpectedPVCVolumeAttributesClass(clusterWithBackupClass, newPVC(utils.PVCRolePgData), true)).
+			To(HaveValue(Equal("diamond")))
+		Expect(getExpectedPVCVolumeAttributesClass(clusterWithBackupClass, newPVC(utils.PVCRolePgData), false)).
+			To(HaveValue(Equal("gold")))
+		Expect(getExpectedPVCVolumeAttributesClass(clusterWithBackupClass, newPVC(utils.PVCRolePgTablespace), true)).
+			To(HaveValue(Equal("platinum")))
+	})
+})
+
+var _ = Describe("base backup source", func() {
+	cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+
+	newBackup := func(method apiv1.BackupMethod, phase apiv1.BackupPhase) *apiv1.Backup {
+		return &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Method:  method,
+			},
+			Status: apiv1.BackupStatus{
+				Phase:      phase,
+				InstanceID: &apiv1.InstanceID{PodName: "cluster-example-2"},
+			},
+		}
+	}
+
+	It("reports the instance a running backup is taken from", func() {
+		podName, ok := getBaseBackupSource(cluster, newBackup(apiv1.BackupMethodBarmanObjectStore, apiv1.BackupPhaseRunning))
+		Expect(ok).To(BeTrue())
+		Expect(podName).To(Equal("cluster-example-2"))
+	})
+
+	It("ignores the completed backups, the volume snapshots and the other clusters", func() {
+		_, ok := getBaseBackupSource(cluster, newBackup(apiv1.BackupMethodBarmanObjectStore, apiv1.BackupPhaseCompleted))
+		Expect(ok).To(BeFalse())
+
+		_, ok = getBaseBackupSource(cluster, newBackup(apiv1.BackupMethodVolumeSnapshot, apiv1.BackupPhaseRunning))
+		Expect(ok).To(BeFalse())
+
+		otherBackup := newBackup(apiv1.BackupMethodPlugin, apiv1.BackupPhaseStarted)
+		otherBackup.Spec.Cluster.Name = "other"
+		_, ok = getBaseBackupSource(cluster, otherBackup)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("base backup VolumeAttributesClass", func() {
+	newBackup := func(phase apiv1.BackupPhase) *apiv1.Backup {
+		return &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: "default"},
+			Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+			Status:     apiv1.BackupStatus{Phase: phase},
+		}
+	}
+
+	It("raises the class of the primary volume while a replica is being cloned", func() {
+		ctx := log.IntoContext(context.Background(), log.GetLogger())
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					VolumeAttributesClassName:       pointer.String("gold"),
+					BackupVolumeAttributesClassName: pointer.String("diamond"),
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ClusterLabelName:      cluster.Name,
+					utils.InstanceNameLabelName: "cluster-example-1",
+					utils.PvcRoleLabelName:      string(utils.PVCRolePgData),
+				},
+				Annotations: map[string]string{specs.PVCVolumeAttributesClassAnnotationName: "gold"},
+			},
+		}
+		job := batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-2-join",
+				Namespace: "default",
+				Labels:    map[string]string{utils.JobRoleLabelName: "join"},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, &pvc).
+			Build()
+		r := &ClusterReconciler{
+			Client:   fakeClient,
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		resources := &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{pvc}},
+			jobs: batchv1.JobList{Items: []batchv1.Job{job}},
+		}
+
+		result, err := r.reconcileResources(ctx, cluster, resources, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{RequeueAfter: 1 * time.Second}))
+
+		var updatedPVC corev1.PersistentVolumeClaim
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: pvc.Name}, &updatedPVC)).
+			To(Succeed())
+		Expect(updatedPVC.Annotations).To(HaveKeyWithValue(specs.PVCVolumeAttributesClassAnnotationName, "diamond"))
+	})
+
+	It("enqueues the cluster of a backup", func() {
+		r := &ClusterReconciler{}
+		requests := r.mapBackupsToClusters()(newBackup(apiv1.BackupPhaseRunning))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].NamespacedName).
+			To(Equal(types.NamespacedName{Namespace: "default", Name: "cluster-example"}))
+	})
+
+	It("reacts only to the changes of the backup phase", func() {
+		Expect(backupsPredicate.Update(event.UpdateEvent{
+			ObjectOld: newBackup(apiv1.BackupPhaseStarted),
+			ObjectNew: newBackup(apiv1.BackupPhaseRunning),
+		})).To(BeTrue())
+		Expect(backupsPredicate.Update(event.UpdateEvent{
+			ObjectOld: newBackup(apiv1.BackupPhaseRunning),
+			ObjectNew: newBackup(apiv1.BackupPhaseRunning),
+		})).To(BeFalse())
+	})
+})
@@ -93,6 +93,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.createOrPatchServiceMonitor(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -270,42 +275,94 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
-	anyService := specs.CreateClusterAnyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&anyService.ObjectMeta, cluster)
+	services := []*corev1.Service{
+		specs.CreateClusterAnyService(*cluster),
+		specs.CreateClusterReadService(*cluster),
+		specs.CreateClusterReadOnlyService(*cluster),
+		specs.CreateClusterReadWriteService(*cluster),
+	}
+	if cluster.Spec.RestrictedReplicas != nil {
+		services = append(services, specs.CreateClusterRestrictedService(*cluster))
+	} else if err := r.deleteService(ctx, cluster.Namespace, cluster.GetServiceRestrictedName()); err != nil {
+		return err
+	}
+	if cluster.IsServiceMonitorEnabled() {
+		services = append(services, specs.CreateClusterMetricsService(*cluster))
+	} else if err := r.deleteService(ctx, cluster.Namespace, cluster.GetServiceMetricsName()); err != nil {
+		return err
+	}
 
-	if err := r.Create(ctx, anyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+	for _, service := range services {
+		SetClusterOwnerAnnotationsAndLabels(&service.ObjectMeta, cluster)
+		if err := r.createOrPatchServiceIPFamilies(ctx, cluster, service); err != nil {
 			return err
 		}
 	}
 
-	readService := specs.CreateClusterReadService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readService.ObjectMeta, cluster)
+	return nil
+}
 
-	if err := r.Create(ctx, readService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+// deleteService deletes a service which is not required anymore, such as
+// the one of the restricted replicas or the metrics one, if it exists
+func (r *ClusterReconciler) deleteService(ctx context.Context, namespace, name string) error {
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if err := r.Delete(ctx, &service); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting the %s service: %w", name, err)
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readOnlyService.ObjectMeta, cluster)
+	return nil
+}
 
-	if err := r.Create(ctx, readOnlyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+// createOrPatchServiceIPFamilies creates the passed service or, when it
+// already exists, aligns its IP family configuration with the one of the
+// cluster. Kubernetes rejects some of these changes, i.e. the change of the
+// primary IP family, and in that case the service is left untouched
+func (r *ClusterReconciler) createOrPatchServiceIPFamilies(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	service *corev1.Service,
+) error {
+	err := r.Create(ctx, service)
+	if err == nil || !apierrs.IsAlreadyExists(err) {
+		return err
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readWriteService.ObjectMeta, cluster)
+	var existingService corev1.Service
+	if err := r.Get(ctx, client.ObjectKeyFromObject(service), &existingService); err != nil {
+		return err
+	}
 
-	if err := r.Create(ctx, readWriteService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
+	patchedService := existingService.DeepCopy()
+	if service.Spec.IPFamilyPolicy != nil {
+		patchedService.Spec.IPFamilyPolicy = service.Spec.IPFamilyPolicy
+	}
+	if len(service.Spec.IPFamilies) > 0 {
+		patchedService.Spec.IPFamilies = service.Spec.IPFamilies
+	}
+	if reflect.DeepEqual(patchedService.Spec, existingService.Spec) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, patchedService, client.MergeFrom(&existingService)); err != nil {
+		if !apierrs.IsInvalid(err) {
+			return fmt.Errorf("while patching the IP families of service %s: %w", service.Name, err)
 		}
+
+		log.FromContext(ctx).Warning("Cannot change the IP families of the service",
+			"service", service.Name,
+			"error", err)
+		r.Recorder.Event(cluster, "Warning", "InvalidServiceIPFamilies",
+			fmt.Sprintf("Cannot change the IP families of service %s: %v", service.Name, err))
+		return nil
 	}
 
+	r.Recorder.Event(cluster, "Normal", "UpdatingServiceIPFamilies",
+		fmt.Sprintf("Updated the IP families of service %s", service.Name))
 	return nil
 }
 
@@ -766,14 +823,15 @@ func (r *ClusterReconciler) createOrPatchDefaultMetrics(ctx context.Context, clu
 }
 
 // createOrPatchPodMonitor
+//
+//nolint:dupl
 func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster *apiv1.Cluster) error {
 	contextLogger := log.FromContext(ctx)
 
 	// Checking for the PodMonitor resource in the cluster
-	havePodMonitor, err := utils.PodMonitorExist(r.DiscoveryClient)
-	if err != nil || !havePodMonitor {
-		contextLogger.Debug("Kind PodMonitor not detected", "err", err)
-		return err
+	if !r.Capabilities.Get().HavePodMonitor {
+		contextLogger.Debug("Kind PodMonitor not detected")
+		return nil
 	}
 
 	// We get the current pod monitor
@@ -792,6 +850,11 @@ func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster
 		podMonitor = nil
 	}
 
+	// A PodMonitor managed by the user is never overwritten
+	if podMonitor != nil && !isPodMonitorManageable(ctx, r.Recorder, cluster, cluster.IsPodMonitorEnabled(), podMonitor) {
+		return nil
+	}
+
 	switch {
 	// Pod monitor disabled and no pod monitor - nothing to do
 	case !cluster.IsPodMonitorEnabled() && podMonitor == nil:
@@ -827,6 +890,68 @@ func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster
 	}
 }
 
+// createOrPatchServiceMonitor creates, patches or deletes the
+// ServiceMonitor scraping the instances through the metrics service
+//
+//nolint:dupl
+func (r *ClusterReconciler) createOrPatchServiceMonitor(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// Checking for the ServiceMonitor resource in the cluster
+	if !r.Capabilities.Get().HaveServiceMonitor {
+		contextLogger.Debug("Kind ServiceMonitor not detected")
+		return nil
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		serviceMonitor,
+	); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the servicemonitor: %w", err)
+		}
+		serviceMonitor = nil
+	}
+
+	// A ServiceMonitor managed by the user is never overwritten
+	if serviceMonitor != nil &&
+		!isServiceMonitorManageable(ctx, r.Recorder, cluster, cluster.IsServiceMonitorEnabled(), serviceMonitor) {
+		return nil
+	}
+
+	switch {
+	case !cluster.IsServiceMonitorEnabled() && serviceMonitor == nil:
+		return nil
+	case !cluster.IsServiceMonitorEnabled() && serviceMonitor != nil:
+		contextLogger.Info("Deleting ServiceMonitor")
+		if err := r.Delete(ctx, serviceMonitor); err != nil {
+			if !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	case cluster.IsServiceMonitorEnabled() && serviceMonitor == nil:
+		contextLogger.Debug("Creating ServiceMonitor")
+		newServiceMonitor := specs.CreateServiceMonitor(cluster)
+		SetClusterOwnerAnnotationsAndLabels(&newServiceMonitor.ObjectMeta, cluster)
+		return r.Create(ctx, newServiceMonitor)
+	default:
+		origServiceMonitor := serviceMonitor.DeepCopy()
+		serviceMonitor.Spec = specs.CreateServiceMonitor(cluster).Spec
+		if reflect.DeepEqual(origServiceMonitor, serviceMonitor) {
+			return nil
+		}
+
+		contextLogger.Debug("Patching ServiceMonitor")
+		return r.Patch(ctx, serviceMonitor, client.MergeFrom(origServiceMonitor))
+	}
+}
+
 // createRole creates the role
 func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
 	role := specs.CreateRole(*cluster, backupOrigin)
@@ -891,6 +1016,27 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	// The backup to recover from is needed before creating the PVCs, as they
+	// are provisioned from the snapshots of a volumeSnapshot backup
+	backup, err := r.getOriginBackup(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil &&
+		cluster.Spec.Bootstrap.Recovery.Backup != nil && backup == nil {
+		contextLogger.Info("Missing backup object, can't continue full recovery",
+			"backup", cluster.Spec.Bootstrap.Recovery.Backup)
+		return ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: time.Minute,
+		}, nil
+	}
+
+	dataSource, walSource, tablespaceSources, err := r.getVolumeSnapshotSources(ctx, cluster, backup)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
 	// Generate a new node serial
 	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
 	if err != nil {
@@ -900,9 +1046,11 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	if err := r.createPVC(
 		ctx,
 		cluster,
-		cluster.Spec.StorageConfiguration,
+		cluster.GetInstanceStorageConfiguration(nodeSerial, apiv1.InstanceOverrideRolePrimary),
 		nodeSerial,
 		utils.PVCRolePgData,
+		apiv1.InstanceOverrideRolePrimary,
+		dataSource,
 	); err != nil {
 		return ctrl.Result{RequeueAfter: time.Minute}, err
 	}
@@ -914,32 +1062,28 @@ func (r *ClusterReconciler) createPrimaryInstance(
 			*cluster.Spec.WalStorage,
 			nodeSerial,
 			utils.PVCRolePgWal,
+			apiv1.InstanceOverrideRolePrimary,
+			walSource,
 		); err != nil {
 			return ctrl.Result{RequeueAfter: time.Minute}, err
 		}
 	}
 
+	if err := r.createTablespacePVCs(
+		ctx,
+		cluster,
+		nodeSerial,
+		apiv1.InstanceOverrideRolePrimary,
+		tablespaceSources,
+	); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
 	// We are bootstrapping a cluster and in need to create the first node
 	var job *batchv1.Job
 
 	switch {
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil:
-		var backup *apiv1.Backup
-		if cluster.Spec.Bootstrap.Recovery.Backup != nil {
-			backup, err = r.getOriginBackup(ctx, cluster)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-			if backup == nil {
-				contextLogger.Info("Missing backup object, can't continue full recovery",
-					"backup", cluster.Spec.Bootstrap.Recovery.Backup)
-				return ctrl.Result{
-					Requeue:      true,
-					RequeueAfter: time.Minute,
-				}, nil
-			}
-		}
-
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from backup)")
 		job = specs.CreatePrimaryJobViaRecovery(*cluster, nodeSerial, backup)
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil:
@@ -955,7 +1099,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, err
 	}
 
-	podName := fmt.Sprintf("%v-%v", cluster.Name, nodeSerial)
+	podName := cluster.GetInstanceName(nodeSerial)
 	if err = r.setPrimaryInstance(ctx, cluster, podName); err != nil {
 		contextLogger.Error(err, "Unable to set the primary instance name")
 		return ctrl.Result{}, err
@@ -981,6 +1125,14 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 
+	result, err := r.checkAdmissionPolicies(ctx, cluster, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+
 	if err = r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
 			// This Job was already created, maybe the cache is stale.
@@ -1023,6 +1175,68 @@ func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.
 	return &backup, nil
 }
 
+// getVolumeSnapshotSources returns the data sources of the PGDATA, of
+// the WAL and of the tablespaces volumes when recovering from a backup
+// taken with the volumeSnapshot method
+func (r *ClusterReconciler) getVolumeSnapshotSources(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) (
+	dataSource, walSource *corev1.TypedLocalObjectReference,
+	tablespaceSources map[string]*corev1.TypedLocalObjectReference,
+	err error,
+) {
+	if backup == nil || !backup.IsVolumeSnapshot() {
+		return nil, nil, nil, nil
+	}
+
+	message := getVolumeSnapshotSourcesError(cluster, backup)
+	if message != "" {
+		log.FromContext(ctx).Info("Cannot recover from the volume snapshots", "reason", message)
+		if err := r.RegisterPhaseWithReasonCode(ctx, cluster, cluster.Status.Phase,
+			apiv1.ConditionReasonPVCProvisioningFailed, message); err != nil {
+			return nil, nil, nil, err
+		}
+		return nil, nil, nil, ErrNextLoop
+	}
+
+	dataSource = specs.GetVolumeSnapshotDataSource(backup.Status.GetSnapshotName(string(utils.PVCRolePgData)))
+	if walSnapshot := backup.Status.GetSnapshotName(string(utils.PVCRolePgWal)); walSnapshot != "" {
+		walSource = specs.GetVolumeSnapshotDataSource(walSnapshot)
+	}
+	tablespaceSources = make(map[string]*corev1.TypedLocalObjectReference, len(cluster.Spec.Tablespaces))
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		tablespaceSources[tablespace.Name] = specs.GetVolumeSnapshotDataSource(
+			backup.Status.GetTablespaceSnapshotName(tablespace.Name))
+	}
+	return dataSource, walSource, tablespaceSources, nil
+}
+
+// getVolumeSnapshotSourcesError returns the reason why the volumes of the
+// cluster cannot be provisioned from the snapshots of the passed backup,
+// or an empty string if they can
+func getVolumeSnapshotSourcesError(cluster *apiv1.Cluster, backup *apiv1.Backup) string {
+	switch {
+	case backup.Status.Phase != apiv1.BackupPhaseCompleted:
+		return fmt.Sprintf("Backup %s is not completed", backup.Name)
+	case backup.Status.GetSnapshotName(string(utils.PVCRolePgData)) == "":
+		return fmt.Sprintf("Backup %s has no snapshot of the PGDATA volume", backup.Name)
+	case backup.Status.GetSnapshotName(string(utils.PVCRolePgWal)) != "" && !cluster.ShouldCreateWalArchiveVolume():
+		return fmt.Sprintf("Backup %s has a snapshot of the WAL volume, "+
+			"and the cluster requires walStorage to be restored", backup.Name)
+	}
+
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		if backup.Status.GetTablespaceSnapshotName(tablespace.Name) == "" {
+			return fmt.Sprintf("Backup %s has no snapshot of the volume of the %s tablespace",
+				backup.Name, tablespace.Name)
+		}
+	}
+
+	return ""
+}
+
 func (r *ClusterReconciler) joinReplicaInstance(
 	ctx context.Context,
 	nodeSerial int,
@@ -1063,6 +1277,14 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 
+	result, err := r.checkAdmissionPolicies(ctx, cluster, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+
 	if err = r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
 			// This Job was already created, maybe the cache is stale.
@@ -1077,9 +1299,11 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	if err := r.createPVC(
 		ctx,
 		cluster,
-		cluster.Spec.StorageConfiguration,
+		cluster.GetInstanceStorageConfiguration(nodeSerial, apiv1.InstanceOverrideRoleReplica),
 		nodeSerial,
 		utils.PVCRolePgData,
+		apiv1.InstanceOverrideRoleReplica,
+		nil,
 	); err != nil {
 		return ctrl.Result{RequeueAfter: time.Minute}, err
 	}
@@ -1091,11 +1315,23 @@ func (r *ClusterReconciler) joinReplicaInstance(
 			*cluster.Spec.WalStorage,
 			nodeSerial,
 			utils.PVCRolePgWal,
+			apiv1.InstanceOverrideRoleReplica,
+			nil,
 		); err != nil {
 			return ctrl.Result{RequeueAfter: time.Minute}, err
 		}
 	}
 
+	if err := r.createTablespacePVCs(
+		ctx,
+		cluster,
+		nodeSerial,
+		apiv1.InstanceOverrideRoleReplica,
+		nil,
+	); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
 }
 
@@ -1195,6 +1431,14 @@ func (r *ClusterReconciler) reconcilePVCs(
 	utils.InheritLabels(&pod.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 
+	result, err := r.checkAdmissionPolicies(ctx, cluster, pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+
 	if err := r.Create(ctx, pod); err != nil {
 		if apierrs.IsAlreadyExists(err) {
 			// This Pod was already created, maybe the cache is stale.
@@ -1257,32 +1501,135 @@ func (r *ClusterReconciler) removeDanglingPVCs(ctx context.Context, cluster *api
 	return nil
 }
 
+// ErrInstanceNameCollision is raised when a PVC to be created for an instance
+// already exists and belongs to a different cluster
+var ErrInstanceNameCollision = fmt.Errorf("instance name collides with another cluster")
+
+// ensurePVCNotOwnedByAnotherCluster checks that an existing PVC is not
+// controlled by a different cluster, which happens when the instance names of
+// two clusters in the same namespace collide
+func (r *ClusterReconciler) ensurePVCNotOwnedByAnotherCluster(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvcName string,
+) error {
+	var existingPVC corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: pvcName}, &existingPVC); err != nil {
+		return err
+	}
+
+	if owner, isOwned := IsOwnedByCluster(&existingPVC); isOwned && owner != cluster.Name {
+		return fmt.Errorf("%w: PVC %s belongs to cluster %s", ErrInstanceNameCollision, pvcName, owner)
+	}
+
+	return nil
+}
+
 func (r *ClusterReconciler) createPVC(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	storageConfiguration apiv1.StorageConfiguration,
 	nodeSerial int,
 	role utils.PVCRole,
+	overrideRole apiv1.InstanceOverrideRole,
+	dataSource *corev1.TypedLocalObjectReference,
 ) error {
-	contextLogger := log.FromContext(ctx)
-
 	pvc, err := specs.CreatePVC(storageConfiguration, *cluster, nodeSerial, role)
 	if err != nil {
-		if err == specs.ErrorInvalidSize {
-			// This error should have been caught by the validating
-			// webhook, but since we are here the user must have disabled server-side
-			// validation, and we must react.
-			contextLogger.Info("The size specified for the cluster is not valid",
-				"size",
-				storageConfiguration.Size)
-			return ErrNextLoop
+		return r.handlePVCSpecError(ctx, cluster, storageConfiguration, nodeSerial, err)
+	}
+
+	return r.createPVCFromSpec(ctx, cluster, pvc, nodeSerial, overrideRole, dataSource)
+}
+
+// createTablespacePVCs creates the PVCs storing the tablespaces of the
+// instance, provisioning them from the passed data sources when available
+func (r *ClusterReconciler) createTablespacePVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+	overrideRole apiv1.InstanceOverrideRole,
+	dataSources map[string]*corev1.TypedLocalObjectReference,
+) error {
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		pvc, err := specs.CreateTablespacePVC(*cluster, nodeSerial, tablespace)
+		if err != nil {
+			return r.handlePVCSpecError(ctx, cluster, tablespace.Storage, nodeSerial, err)
+		}
+
+		if err := r.createPVCFromSpec(
+			ctx,
+			cluster,
+			pvc,
+			nodeSerial,
+			overrideRole,
+			dataSources[tablespace.Name],
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handlePVCSpecError reacts to an error raised while building the
+// spec of a PVC
+func (r *ClusterReconciler) handlePVCSpecError(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	storageConfiguration apiv1.StorageConfiguration,
+	nodeSerial int,
+	err error,
+) error {
+	if err == specs.ErrorInvalidSize {
+		// This error should have been caught by the validating
+		// webhook, but since we are here the user must have disabled server-side
+		// validation, and we must react.
+		log.FromContext(ctx).Info("The size specified for the cluster is not valid",
+			"size",
+			storageConfiguration.Size)
+		if err := r.RegisterPhaseWithReasonCode(ctx, cluster, cluster.Status.Phase,
+			apiv1.ConditionReasonPVCProvisioningFailed,
+			fmt.Sprintf("The storage size %q is not valid", storageConfiguration.Size)); err != nil {
+			return err
 		}
-		return fmt.Errorf("unable to create a PVC spec for node with serial %v: %w", nodeSerial, err)
+		return ErrNextLoop
 	}
+	return fmt.Errorf("unable to create a PVC spec for node with serial %v: %w", nodeSerial, err)
+}
 
+// createPVCFromSpec creates the passed PVC of an instance
+func (r *ClusterReconciler) createPVCFromSpec(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+	nodeSerial int,
+	overrideRole apiv1.InstanceOverrideRole,
+	dataSource *corev1.TypedLocalObjectReference,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	pvc.Annotations[specs.InstanceOverrideRoleAnnotationName] = string(overrideRole)
+	if dataSource != nil {
+		pvc.Spec.DataSource = dataSource
+	}
 	SetClusterOwnerAnnotationsAndLabels(&pvc.ObjectMeta, cluster)
 
-	if err = r.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
+	pvcObject, err := specs.PVCWithVolumeAttributesClass(pvc)
+	if err != nil {
+		return fmt.Errorf("while setting the VolumeAttributesClass of PVC %s: %w", pvc.Name, err)
+	}
+
+	err = r.Create(ctx, pvcObject)
+	if apierrs.IsAlreadyExists(err) {
+		err = r.ensurePVCNotOwnedByAnotherCluster(ctx, cluster, pvc.Name)
+	}
+	if err != nil {
+		if registerErr := r.RegisterPhaseWithReasonCode(ctx, cluster, cluster.Status.Phase,
+			apiv1.ConditionReasonPVCProvisioningFailed,
+			fmt.Sprintf("Cannot create PVC %s: %v", pvc.Name, err)); registerErr != nil {
+			contextLogger.Error(registerErr, "Cannot register the PVC provisioning failure")
+		}
 		return fmt.Errorf("unable to create a PVC: %s for this node (nodeSerial: %d): %w",
 			pvc.Name,
 			nodeSerial,
@@ -18,13 +18,21 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -236,3 +244,124 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("instance name collisions", func() {
+	newPVC := func(name string, owner string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		if owner != "" {
+			pvc.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+				Name:       owner,
+				Controller: pointer.Bool(true),
+			}}
+		}
+		return pvc
+	}
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec:       apiv1.ClusterSpec{InstanceNamePrefix: "pg"},
+	}
+
+	newReconciler := func(pvcs ...*corev1.PersistentVolumeClaim) *ClusterReconciler {
+		builder := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme())
+		for _, pvc := range pvcs {
+			builder = builder.WithObjects(pvc)
+		}
+		return &ClusterReconciler{Client: builder.Build()}
+	}
+
+	It("accepts a PVC owned by the same cluster or not owned at all", func() {
+		reconciler := newReconciler(newPVC("pg-1", "cluster-example"), newPVC("pg-2", ""))
+		Expect(reconciler.ensurePVCNotOwnedByAnotherCluster(context.TODO(), cluster, "pg-1")).To(Succeed())
+		Expect(reconciler.ensurePVCNotOwnedByAnotherCluster(context.TODO(), cluster, "pg-2")).To(Succeed())
+	})
+
+	It("refuses a PVC owned by a different cluster", func() {
+		reconciler := newReconciler(newPVC("pg-1", "pg"))
+		err := reconciler.ensurePVCNotOwnedByAnotherCluster(context.TODO(), cluster, "pg-1")
+		Expect(errors.Is(err, ErrInstanceNameCollision)).To(BeTrue())
+	})
+})
+
+var _ = Describe("services IP families", func() {
+	It("aligns the IP families of an existing service", func() {
+		dualStack := corev1.IPFamilyPolicyPreferDualStack
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{Managed: &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{IPFamilyPolicy: &dualStack},
+			}},
+		}
+		existingService := specs.CreateClusterReadWriteService(apiv1.Cluster{ObjectMeta: cluster.ObjectMeta})
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(existingService).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		service := specs.CreateClusterReadWriteService(*cluster)
+		Expect(reconciler.createOrPatchServiceIPFamilies(context.TODO(), cluster, service)).To(Succeed())
+
+		var patchedService corev1.Service
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(service), &patchedService)).To(Succeed())
+		Expect(patchedService.Spec.IPFamilyPolicy).To(Equal(&dualStack))
+	})
+})
+
+var _ = Describe("tablespaces volumes", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{Name: "fast_disk", Storage: apiv1.StorageConfiguration{Size: "1Gi"}},
+			},
+		},
+	}
+
+	It("creates the PVCs of the tablespaces from their data sources", func() {
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		dataSource := specs.GetVolumeSnapshotDataSource("backup-example-tbs-fast-disk")
+		Expect(reconciler.createTablespacePVCs(context.TODO(), cluster, 1, apiv1.InstanceOverrideRolePrimary,
+			map[string]*corev1.TypedLocalObjectReference{"fast_disk": dataSource})).To(Succeed())
+
+		var pvc corev1.PersistentVolumeClaim
+		Expect(reconciler.Get(context.TODO(),
+			types.NamespacedName{Namespace: "default", Name: "cluster-example-1-tbs-fast-disk"}, &pvc)).
+			To(Succeed())
+		Expect(pvc.Spec.DataSource).To(Equal(dataSource))
+		Expect(pvc.Annotations).To(HaveKeyWithValue(specs.InstanceOverrideRoleAnnotationName,
+			string(apiv1.InstanceOverrideRolePrimary)))
+	})
+
+	It("requires a snapshot of every tablespace to recover from a volume snapshot backup", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example"},
+			Status: apiv1.BackupStatus{
+				Phase: apiv1.BackupPhaseCompleted,
+				Snapshots: []apiv1.BackupSnapshotElementStatus{
+					{Name: "backup-example", Type: string(utils.PVCRolePgData)},
+				},
+			},
+		}
+		Expect(getVolumeSnapshotSourcesError(cluster, backup)).To(ContainSubstring("fast_disk"))
+
+		backup.Status.Snapshots = append(backup.Status.Snapshots, apiv1.BackupSnapshotElementStatus{
+			Name:           "backup-example-tbs-fast-disk",
+			Type:           string(utils.PVCRolePgTablespace),
+			TablespaceName: "fast_disk",
+		})
+		Expect(getVolumeSnapshotSourcesError(cluster, backup)).To(BeEmpty())
+	})
+})
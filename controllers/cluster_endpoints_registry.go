@@ -0,0 +1,147 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// buildEndpointsRegistryEntry builds the connection parameters of the
+// replicas currently selected by the `-ro` service, in the libpq format
+// accepted by the options of a `postgres_fdw` server. The `-ro` service
+// is used when no replica is selected, as it implements the fallback policy
+func buildEndpointsRegistryEntry(cluster *apiv1.Cluster, members map[string]bool) string {
+	hosts := make([]string, 0, len(members))
+	for podName, isMember := range members {
+		if isMember {
+			hosts = append(hosts, fmt.Sprintf("%s.%s.%s.svc", podName, cluster.GetServiceAnyName(), cluster.Namespace))
+		}
+	}
+	sort.Strings(hosts)
+
+	if len(hosts) == 0 {
+		hosts = append(hosts, fmt.Sprintf("%s.%s.svc", cluster.GetServiceReadOnlyName(), cluster.Namespace))
+	}
+
+	return fmt.Sprintf("host=%s port=%d", strings.Join(hosts, ","), postgres.ServerPort)
+}
+
+// reconcileEndpointsRegistry publishes the read-only endpoints of the
+// cluster in the registry of the namespace, or removes them when the
+// cluster is not publishing its endpoints anymore
+func (r *ClusterReconciler) reconcileEndpointsRegistry(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	if !cluster.Spec.PublishEndpoints {
+		return r.deleteEndpointsRegistryEntry(ctx, cluster.Namespace, cluster.Name)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	entry := buildEndpointsRegistryEntry(cluster, getReadOnlyServiceMembers(cluster, instancesStatus))
+
+	var registry corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: apiv1.EndpointsRegistryConfigMapName},
+		&registry)
+	if apierrs.IsNotFound(err) {
+		registry = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      apiv1.EndpointsRegistryConfigMapName,
+				Namespace: cluster.Namespace,
+			},
+			Data: map[string]string{
+				cluster.Name: entry,
+			},
+		}
+		utils.SetOperatorVersion(&registry.ObjectMeta, versions.Version)
+		contextLogger.Info("Creating the endpoints registry", "entry", entry)
+		return r.Create(ctx, &registry)
+	}
+	if err != nil {
+		return err
+	}
+
+	// we check that we own the existing configmap
+	if _, ok := registry.Annotations[utils.OperatorVersionAnnotationName]; !ok {
+		contextLogger.Warning("A configmap with the same name as the endpoints registry already exists, "+
+			"without the required annotation",
+			"configmap", registry.Name, "annotation", utils.OperatorVersionAnnotationName)
+		return nil
+	}
+
+	if registry.Data[cluster.Name] == entry {
+		return nil
+	}
+
+	// The merge patch only contains the entry of this cluster, so the
+	// entries of the other clusters are preserved
+	contextLogger.Info("Updating the endpoints registry", "entry", entry)
+	patch := client.MergeFrom(registry.DeepCopy())
+	if registry.Data == nil {
+		registry.Data = make(map[string]string)
+	}
+	registry.Data[cluster.Name] = entry
+	return r.Patch(ctx, &registry, patch)
+}
+
+// deleteEndpointsRegistryEntry removes the entry of the passed cluster from
+// the registry of the namespace, deleting the registry when it is empty
+func (r *ClusterReconciler) deleteEndpointsRegistryEntry(ctx context.Context, namespace, clusterName string) error {
+	var registry corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: apiv1.EndpointsRegistryConfigMapName}, &registry)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, ok := registry.Annotations[utils.OperatorVersionAnnotationName]; !ok {
+		return nil
+	}
+
+	if _, ok := registry.Data[clusterName]; !ok {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Removing the cluster from the endpoints registry", "clusterName", clusterName)
+	if len(registry.Data) == 1 {
+		if err := r.Delete(ctx, &registry); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	patch := client.MergeFrom(registry.DeepCopy())
+	delete(registry.Data, clusterName)
+	return r.Patch(ctx, &registry, patch)
+}
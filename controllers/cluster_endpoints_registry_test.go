@@ -0,0 +1,149 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("endpoints registry", func() {
+	newCluster := func(name string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{PublishEndpoints: true},
+		}
+	}
+
+	newStatus := func(podName string, isPrimary, isPodReady bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			IsPrimary:  isPrimary,
+			IsPodReady: isPodReady,
+		}
+	}
+
+	getRegistry := func(ctx context.Context, reconciler *ClusterReconciler) (*corev1.ConfigMap, error) {
+		var registry corev1.ConfigMap
+		err := reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: apiv1.EndpointsRegistryConfigMapName},
+			&registry)
+		return &registry, err
+	}
+
+	It("lists the replicas selected by the read-only service", func() {
+		cluster := newCluster("cluster-example")
+		Expect(buildEndpointsRegistryEntry(cluster, map[string]bool{
+			"cluster-example-3": true,
+			"cluster-example-2": true,
+			"cluster-example-4": false,
+		})).To(Equal("host=cluster-example-2.cluster-example-any.default.svc," +
+			"cluster-example-3.cluster-example-any.default.svc port=5432"))
+	})
+
+	It("falls back to the read-only service when no replica is selected", func() {
+		cluster := newCluster("cluster-example")
+		Expect(buildEndpointsRegistryEntry(cluster, map[string]bool{"cluster-example-2": false})).
+			To(Equal("host=cluster-example-ro.default.svc port=5432"))
+	})
+
+	It("keeps the entries of every cluster publishing its endpoints", func() {
+		ctx := context.Background()
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		first := newCluster("first")
+		firstStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("first-1", true, true),
+			newStatus("first-2", false, true),
+		}}
+		second := newCluster("second")
+		secondStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("second-1", true, true),
+		}}
+
+		Expect(reconciler.reconcileEndpointsRegistry(ctx, first, firstStatus)).To(Succeed())
+		Expect(reconciler.reconcileEndpointsRegistry(ctx, second, secondStatus)).To(Succeed())
+		registry, err := getRegistry(ctx, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry.Data).To(Equal(map[string]string{
+			"first":  "host=first-2.first-any.default.svc port=5432",
+			"second": "host=second-ro.default.svc port=5432",
+		}))
+
+		By("following a switchover", func() {
+			firstStatus.Items[0].IsPrimary = false
+			firstStatus.Items[1].IsPrimary = true
+			Expect(reconciler.reconcileEndpointsRegistry(ctx, first, firstStatus)).To(Succeed())
+			registry, err := getRegistry(ctx, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(registry.Data).To(HaveKeyWithValue("first", "host=first-1.first-any.default.svc port=5432"))
+		})
+
+		By("removing the clusters not publishing their endpoints anymore", func() {
+			first.Spec.PublishEndpoints = false
+			Expect(reconciler.reconcileEndpointsRegistry(ctx, first, firstStatus)).To(Succeed())
+			registry, err := getRegistry(ctx, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(registry.Data).To(HaveLen(1))
+			Expect(registry.Data).To(HaveKey("second"))
+		})
+
+		By("deleting the registry when it is empty", func() {
+			Expect(reconciler.deleteEndpointsRegistryEntry(ctx, "default", "second")).To(Succeed())
+			_, err := getRegistry(ctx, reconciler)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("doesn't touch a configmap not created by the operator", func() {
+		ctx := context.Background()
+		registry := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: apiv1.EndpointsRegistryConfigMapName, Namespace: "default"},
+			Data:       map[string]string{"key": "value"},
+		}
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(registry).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		Expect(reconciler.reconcileEndpointsRegistry(ctx, newCluster("cluster-example"),
+			postgres.PostgresqlStatusList{})).To(Succeed())
+		current, err := getRegistry(ctx, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(current.Data).To(Equal(map[string]string{"key": "value"}))
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// getClusterExpiration returns the time after which the cluster is
+// deleted, or nil when the cluster doesn't expire
+func getClusterExpiration(cluster *apiv1.Cluster) (*time.Time, error) {
+	value, ok := cluster.Annotations[utils.ClusterExpirationAnnotationName]
+	if !ok {
+		return nil, nil
+	}
+
+	expiration, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &expiration, nil
+}
+
+// deleteExpiredCluster deletes the cluster when its expiration time is
+// passed, returning true if the cluster has been deleted
+func (r *ClusterReconciler) deleteExpiredCluster(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	expiration, err := getClusterExpiration(cluster)
+	if err != nil {
+		contextLogger.Warning("Ignoring invalid expiration time",
+			"annotation", utils.ClusterExpirationAnnotationName,
+			"error", err)
+		return false, nil
+	}
+	if expiration == nil || time.Now().Before(*expiration) || !cluster.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+
+	contextLogger.Info("Deleting expired cluster", "expiration", expiration)
+	r.Recorder.Eventf(cluster, "Normal", "ClusterExpired",
+		"Deleting the cluster, which expired at %s", expiration.Format(time.RFC3339))
+	if err := r.Delete(ctx, cluster); err != nil && !apierrs.IsNotFound(err) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// requeueBeforeExpiration ensures the cluster is reconciled again when its
+// expiration time is reached
+func requeueBeforeExpiration(cluster *apiv1.Cluster, result ctrl.Result) ctrl.Result {
+	expiration, err := getClusterExpiration(cluster)
+	if err != nil || expiration == nil {
+		return result
+	}
+
+	return requeueBefore(*expiration, result)
+}
+
+// requeueBefore ensures the passed result requeues the reconciliation
+// not later than the passed time
+func requeueBefore(deadline time.Time, result ctrl.Result) ctrl.Result {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		remaining = time.Second
+	}
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
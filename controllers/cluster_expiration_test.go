@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster expiration", func() {
+	newCluster := func(expiration string) *apiv1.Cluster {
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-clone", Namespace: "default"}}
+		if expiration != "" {
+			cluster.Annotations = map[string]string{utils.ClusterExpirationAnnotationName: expiration}
+		}
+		return cluster
+	}
+
+	newReconciler := func(cluster *apiv1.Cluster) *ClusterReconciler {
+		return &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("deletes the clusters whose expiration time is passed", func() {
+		ctx := context.Background()
+		cluster := newCluster(time.Now().Add(-time.Minute).Format(time.RFC3339))
+		reconciler := newReconciler(cluster)
+
+		deleted, err := reconciler.deleteExpiredCluster(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &apiv1.Cluster{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("keeps the clusters not yet expired or without a valid expiration time", func() {
+		ctx := context.Background()
+		for _, expiration := range []string{"", "tomorrow", time.Now().Add(time.Hour).Format(time.RFC3339)} {
+			cluster := newCluster(expiration)
+			deleted, err := newReconciler(cluster).deleteExpiredCluster(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+		}
+	})
+
+	It("requeues the reconciliation when the cluster expires", func() {
+		cluster := newCluster(time.Now().Add(time.Minute).Format(time.RFC3339))
+		result := requeueBeforeExpiration(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, 2*time.Second))
+
+		result = requeueBeforeExpiration(cluster, ctrl.Result{RequeueAfter: 30 * time.Second})
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+		result = requeueBeforeExpiration(newCluster(""), ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+})
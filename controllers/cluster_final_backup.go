@@ -0,0 +1,171 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// finalBackupPollingInterval is the interval between two checks of the
+// status of the final backup
+const finalBackupPollingInterval = 10 * time.Second
+
+// errFinalBackupFailed is returned when the final backup failed
+var errFinalBackupFailed = errors.New("the final backup failed")
+
+// verifyFinalBackup checks whether the final backup is completed and can be
+// used to recover the cluster. It returns false when the backup is still
+// in progress
+func verifyFinalBackup(backup *apiv1.Backup) (bool, error) {
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseCompleted:
+	case apiv1.BackupPhaseFailed, apiv1.BackupPhaseWalArchivingFailing:
+		return false, fmt.Errorf("%w: %s", errFinalBackupFailed, backup.Status.Error)
+	default:
+		return false, nil
+	}
+
+	if backup.Status.BackupID == "" || backup.Status.BeginWal == "" || backup.Status.EndWal == "" {
+		return false, fmt.Errorf("%w: the backup status doesn't contain the backup ID and the WAL range",
+			errFinalBackupFailed)
+	}
+
+	return true, nil
+}
+
+// reconcileFinalBackupFinalizer adds the finalizer protecting the cluster
+// resources when the final backup is enabled, and removes it otherwise
+func (r *ClusterReconciler) reconcileFinalBackupFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	hasFinalizer := controllerutil.ContainsFinalizer(cluster, utils.FinalBackupFinalizerName)
+	if cluster.IsFinalBackupEnabled() == hasFinalizer {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if hasFinalizer {
+		controllerutil.RemoveFinalizer(cluster, utils.FinalBackupFinalizerName)
+	} else {
+		controllerutil.AddFinalizer(cluster, utils.FinalBackupFinalizerName)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// reconcileFinalBackup takes the final backup of a deleted cluster, releasing
+// its resources when the backup is completed and verified, when the user
+// requested to skip it, or when the timeout expires
+func (r *ClusterReconciler) reconcileFinalBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithValues("backup", cluster.GetFinalBackupName())
+
+	if cluster.Annotations[utils.SkipFinalBackupAnnotationName] == "true" {
+		contextLogger.Warning("Skipping the final backup as requested by the user")
+		r.Recorder.Event(cluster, "Warning", "FinalBackupSkipped",
+			"The final backup has been skipped as requested by the user")
+		return ctrl.Result{}, r.removeFinalBackupFinalizer(ctx, cluster)
+	}
+
+	if time.Since(cluster.DeletionTimestamp.Time) > cluster.GetFinalBackupTimeout() {
+		contextLogger.Warning("The final backup didn't complete in time, releasing the cluster resources",
+			"timeout", cluster.GetFinalBackupTimeout())
+		r.Recorder.Eventf(cluster, "Warning", "FinalBackupTimeout",
+			"The final backup %s didn't complete in %v, releasing the cluster resources",
+			cluster.GetFinalBackupName(), cluster.GetFinalBackupTimeout())
+		return ctrl.Result{}, r.removeFinalBackupFinalizer(ctx, cluster)
+	}
+
+	var backup apiv1.Backup
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetFinalBackupName()}, &backup)
+	if apierrs.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: finalBackupPollingInterval}, r.createFinalBackup(ctx, cluster)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	verified, err := verifyFinalBackup(&backup)
+	if err != nil {
+		contextLogger.Warning("The final backup failed, waiting for the timeout or for the user to skip it",
+			"error", err)
+		r.Recorder.Eventf(cluster, "Warning", "FinalBackupFailed",
+			"The final backup %s failed, set the %s annotation to release the cluster resources: %v",
+			backup.Name, utils.SkipFinalBackupAnnotationName, err)
+		return ctrl.Result{RequeueAfter: finalBackupPollingInterval}, nil
+	}
+	if !verified {
+		contextLogger.Info("Waiting for the final backup to be completed", "phase", backup.Status.Phase)
+		return ctrl.Result{RequeueAfter: finalBackupPollingInterval}, nil
+	}
+
+	contextLogger.Info("The final backup has been completed, releasing the cluster resources",
+		"backupID", backup.Status.BackupID)
+	r.Recorder.Eventf(cluster, "Normal", "FinalBackupCompleted",
+		"The final backup %s has been completed and verified", backup.Name)
+	return ctrl.Result{}, r.removeFinalBackupFinalizer(ctx, cluster)
+}
+
+// createFinalBackup creates the final backup of the passed cluster. The
+// backup is not owned by the cluster, as it must survive it
+func (r *ClusterReconciler) createFinalBackup(ctx context.Context, cluster *apiv1.Cluster) error {
+	backup := apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetFinalBackupName(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+			},
+		},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+		},
+	}
+
+	log.FromContext(ctx).Info("Taking the final backup of the deleted cluster", "backup", backup.Name)
+	r.Recorder.Eventf(cluster, "Normal", "FinalBackupStarted", "Taking the final backup %s", backup.Name)
+	if err := r.Create(ctx, &backup); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating the final backup: %w", err)
+	}
+
+	return nil
+}
+
+// removeFinalBackupFinalizer removes the finalizer from the passed cluster,
+// letting Kubernetes release its resources
+func (r *ClusterReconciler) removeFinalBackupFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, utils.FinalBackupFinalizerName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("final backup", func() {
+	newCluster := func(deleted bool) *apiv1.Cluster {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "cluster-example",
+				Namespace:  "default",
+				Finalizers: []string{utils.FinalBackupFinalizerName},
+			},
+			Spec: apiv1.ClusterSpec{
+				DeletionPolicy: &apiv1.DeletionPolicyConfiguration{FinalBackup: true, FinalBackupTimeout: 3600},
+			},
+		}
+		if deleted {
+			deletion := metav1.NewTime(time.Now().Add(-time.Minute))
+			cluster.DeletionTimestamp = &deletion
+		}
+		return cluster
+	}
+
+	newReconciler := func(objects ...client.Object) *ClusterReconciler {
+		return &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("verifies the final backup", func() {
+		backup := &apiv1.Backup{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning}}
+		verified, err := verifyFinalBackup(backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(verified).To(BeFalse())
+
+		backup.Status.Phase = apiv1.BackupPhaseCompleted
+		_, err = verifyFinalBackup(backup)
+		Expect(err).To(MatchError(errFinalBackupFailed))
+
+		backup.Status.BackupID = "20260101T000000"
+		backup.Status.BeginWal = "000000010000000000000002"
+		backup.Status.EndWal = "000000010000000000000003"
+		verified, err = verifyFinalBackup(backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(verified).To(BeTrue())
+
+		backup.Status.Phase = apiv1.BackupPhaseFailed
+		_, err = verifyFinalBackup(backup)
+		Expect(err).To(MatchError(errFinalBackupFailed))
+	})
+
+	It("adds and removes the finalizer following the deletion policy", func() {
+		ctx := context.Background()
+		cluster := newCluster(false)
+		cluster.Finalizers = nil
+		reconciler := newReconciler(cluster)
+
+		Expect(reconciler.reconcileFinalBackupFinalizer(ctx, cluster)).To(Succeed())
+		Expect(cluster.Finalizers).To(ConsistOf(utils.FinalBackupFinalizerName))
+
+		cluster.Spec.DeletionPolicy = nil
+		Expect(reconciler.reconcileFinalBackupFinalizer(ctx, cluster)).To(Succeed())
+		Expect(cluster.Finalizers).To(BeEmpty())
+	})
+
+	It("takes the final backup before releasing the cluster resources", func() {
+		ctx := context.Background()
+		cluster := newCluster(true)
+		reconciler := newReconciler(cluster)
+
+		result, err := reconciler.reconcileFinalBackup(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(finalBackupPollingInterval))
+		Expect(cluster.Finalizers).ToNot(BeEmpty())
+
+		var backup apiv1.Backup
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: cluster.GetFinalBackupName()},
+			&backup)).To(Succeed())
+		Expect(backup.Spec.Cluster.Name).To(Equal(cluster.Name))
+		Expect(backup.OwnerReferences).To(BeEmpty())
+
+		backup.Status = apiv1.BackupStatus{
+			Phase:    apiv1.BackupPhaseCompleted,
+			BackupID: "20260101T000000",
+			BeginWal: "000000010000000000000002",
+			EndWal:   "000000010000000000000003",
+		}
+		Expect(reconciler.Update(ctx, &backup)).To(Succeed())
+
+		result, err = reconciler.reconcileFinalBackup(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(cluster.Finalizers).To(BeEmpty())
+	})
+
+	It("releases the cluster resources when requested or when the timeout expires", func() {
+		ctx := context.Background()
+
+		skipped := newCluster(true)
+		skipped.Annotations = map[string]string{utils.SkipFinalBackupAnnotationName: "true"}
+		_, err := newReconciler(skipped).reconcileFinalBackup(ctx, skipped)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(skipped.Finalizers).To(BeEmpty())
+
+		expired := newCluster(true)
+		expired.Spec.DeletionPolicy.FinalBackupTimeout = 10
+		_, err = newReconciler(expired).reconcileFinalBackup(ctx, expired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired.Finalizers).To(BeEmpty())
+	})
+})
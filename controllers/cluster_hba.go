@@ -0,0 +1,378 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// pgHBARulesRefreshInterval is how often the addresses of the Pods,
+// Services and NetworkPolicies referenced by the `pg_hba_rules` are
+// refreshed, as the operator isn't notified when they change
+const pgHBARulesRefreshInterval = 30 * time.Second
+
+// errInvalidPgHBARule is raised when the addresses of a `pg_hba_rules`
+// rule cannot be read from the referenced resources
+var errInvalidPgHBARule = errors.New("invalid pg_hba rule")
+
+// reconcilePgHBARules renders the `pg_hba_rules` into pg_hba.conf entries,
+// reading the addresses from the referenced Kubernetes resources, and
+// stores them in the cluster status where the instance manager reads them.
+// When the addresses of a rule cannot be read, the previously rendered
+// entries are kept and the PgHBARules condition reports the problem, as
+// dropping the rule could grant the access to a wider set of addresses
+func (r *ClusterReconciler) reconcilePgHBARules(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	var rules []string
+	for _, rule := range cluster.Spec.PostgresConfiguration.PgHBARules {
+		addresses, rejectedAddresses, err := r.getPgHBARuleAddresses(ctx, cluster.Namespace, rule)
+		if errors.Is(err, errInvalidPgHBARule) {
+			contextLogger.Warning("Keeping the previous pg_hba.conf entries of the pg_hba rules", "error", err)
+			return conditions.Update(ctx, r.Client, cluster, buildPgHBARulesCondition(err))
+		}
+		if err != nil {
+			return err
+		}
+
+		// The excluded CIDRs of the NetworkPolicies come first, as PostgreSQL
+		// uses the first entry matching the connection
+		rejectRule := rule
+		rejectRule.Method = "reject"
+		rejectRule.Options = nil
+		for _, address := range rejectedAddresses {
+			rules = append(rules, buildPgHBARuleEntry(rejectRule, address))
+		}
+		for _, address := range addresses {
+			rules = append(rules, buildPgHBARuleEntry(rule, address))
+		}
+	}
+
+	if !reflect.DeepEqual(rules, cluster.Status.PgHBARules) {
+		contextLogger.Info("Updating the pg_hba.conf entries of the pg_hba rules", "rules", len(rules))
+		cluster.Status.PgHBARules = rules
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
+	if len(cluster.Spec.PostgresConfiguration.PgHBARules) == 0 &&
+		meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPgHBARules)) == nil {
+		return nil
+	}
+	return conditions.Update(ctx, r.Client, cluster, buildPgHBARulesCondition(nil))
+}
+
+// hasPgHBAResourceReferences checks whether any `pg_hba_rules` rule takes
+// its addresses from Pods, Services or NetworkPolicies, which need to be
+// refreshed periodically
+func hasPgHBAResourceReferences(cluster *apiv1.Cluster) bool {
+	for _, rule := range cluster.Spec.PostgresConfiguration.PgHBARules {
+		if rule.AddressesFrom != nil && rule.AddressesFrom.HasResourceReferences() {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPgHBARulesCondition builds the condition reporting whether the
+// `pg_hba_rules` have been rendered
+func buildPgHBARulesCondition(err error) *metav1.Condition {
+	if err != nil {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionPgHBARules),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonPgHBARulesInvalid),
+			Message: err.Error(),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionPgHBARules),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonPgHBARulesApplied),
+		Message: "The pg_hba rules have been rendered",
+	}
+}
+
+// getPgHBARuleAddresses gets the addresses matched by a `pg_hba_rules`
+// rule, reading them from the referenced resources if needed, together
+// with the addresses excluded by the referenced NetworkPolicies. The
+// addresses of the Pods and of the Services are converted to single host
+// CIDRs
+func (r *ClusterReconciler) getPgHBARuleAddresses(
+	ctx context.Context,
+	namespace string,
+	rule apiv1.PgHBARule,
+) (addresses []string, rejectedAddresses []string, err error) {
+	source := rule.AddressesFrom
+	if source == nil {
+		return []string{rule.Address}, nil, nil
+	}
+
+	if source.ConfigMapKeyRef != nil {
+		configMapAddresses, err := r.getPgHBAConfigMapAddresses(ctx, namespace, *source.ConfigMapKeyRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		addresses = append(addresses, configMapAddresses...)
+	}
+
+	podAddresses, err := r.getPgHBAPodAddresses(ctx, namespace, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	addresses = append(addresses, podAddresses...)
+
+	for _, serviceName := range source.Services {
+		var endpoints corev1.Endpoints
+		err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: serviceName}, &endpoints)
+		if apierrs.IsNotFound(err) {
+			log.FromContext(ctx).Warning("Service referenced by pg_hba_rules not found", "service", serviceName)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("while getting the endpoints of service %s: %w", serviceName, err)
+		}
+		addresses = append(addresses, getEndpointsAddresses(endpoints)...)
+	}
+
+	for _, policyName := range source.NetworkPolicies {
+		var policy networkingv1.NetworkPolicy
+		err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: policyName}, &policy)
+		if apierrs.IsNotFound(err) {
+			log.FromContext(ctx).Warning("NetworkPolicy referenced by pg_hba_rules not found",
+				"networkPolicy", policyName)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("while getting network policy %s: %w", policyName, err)
+		}
+		allowed, rejected := getNetworkPolicyCIDRs(policy)
+		addresses = append(addresses, allowed...)
+		rejectedAddresses = append(rejectedAddresses, rejected...)
+	}
+
+	return sortedUniqueAddresses(addresses), sortedUniqueAddresses(rejectedAddresses), nil
+}
+
+// getPgHBAConfigMapAddresses reads the CIDRs contained in the key of a
+// ConfigMap. A missing ConfigMap, or key, and an invalid CIDR are reported
+// as errInvalidPgHBARule, as they would prevent PostgreSQL from loading
+// the file
+func (r *ClusterReconciler) getPgHBAConfigMapAddresses(
+	ctx context.Context,
+	namespace string,
+	selector apiv1.ConfigMapKeySelector,
+) ([]string, error) {
+	var configMap corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, &configMap)
+	if apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: configmap %s not found", errInvalidPgHBARule, selector.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting configmap %s: %w", selector.Name, err)
+	}
+
+	data, ok := configMap.Data[selector.Key]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s not found in configmap %s",
+			errInvalidPgHBARule, selector.Key, selector.Name)
+	}
+
+	var addresses []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(line); err != nil {
+			return nil, fmt.Errorf("%w: invalid CIDR %q in key %s of configmap %s",
+				errInvalidPgHBARule, line, selector.Key, selector.Name)
+		}
+		addresses = append(addresses, line)
+	}
+
+	return addresses, nil
+}
+
+// getPgHBAPodAddresses returns the host CIDRs of the running Pods selected
+// by the source of the addresses of a `pg_hba_rules` rule. The namespaces
+// can only be selected when the operator is allowed to list them, which
+// is reported as errInvalidPgHBARule
+func (r *ClusterReconciler) getPgHBAPodAddresses(
+	ctx context.Context,
+	namespace string,
+	source *apiv1.PgHBAAddressesSource,
+) ([]string, error) {
+	if !source.HasPodReferences() {
+		return nil, nil
+	}
+
+	namespaces := []string{namespace}
+	if source.NamespaceSelector != nil {
+		if !r.Capabilities.Get().HaveNamespacesAccess {
+			return nil, fmt.Errorf("%w: the operator is not allowed to list the namespaces", errInvalidPgHBARule)
+		}
+
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(source.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		var namespaceList corev1.NamespaceList
+		if err := r.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, fmt.Errorf("while listing the namespaces: %w", err)
+		}
+
+		namespaces = make([]string, 0, len(namespaceList.Items))
+		for _, item := range namespaceList.Items {
+			namespaces = append(namespaces, item.Name)
+		}
+	}
+
+	podSelector := labels.Everything()
+	if source.PodSelector != nil {
+		var err error
+		if podSelector, err = metav1.LabelSelectorAsSelector(source.PodSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	var addresses []string
+	for _, podNamespace := range namespaces {
+		var podList corev1.PodList
+		if err := r.List(ctx, &podList,
+			client.InNamespace(podNamespace),
+			client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+			return nil, fmt.Errorf("while listing the pods in namespace %s: %w", podNamespace, err)
+		}
+
+		for _, pod := range podList.Items {
+			if !utils.IsPodActive(pod) {
+				continue
+			}
+			for _, podIP := range pod.Status.PodIPs {
+				addresses = append(addresses, getHostCIDR(podIP.IP))
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+// getEndpointsAddresses returns the host CIDRs of the ready addresses of
+// the passed endpoints
+func getEndpointsAddresses(endpoints corev1.Endpoints) []string {
+	var addresses []string
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			addresses = append(addresses, getHostCIDR(address.IP))
+		}
+	}
+	return addresses
+}
+
+// getNetworkPolicyCIDRs returns the CIDRs allowed by the ingress rules of
+// the passed network policy, together with the excluded ones
+func getNetworkPolicyCIDRs(policy networkingv1.NetworkPolicy) (allowed []string, rejected []string) {
+	for _, ingress := range policy.Spec.Ingress {
+		for _, peer := range ingress.From {
+			if peer.IPBlock == nil {
+				continue
+			}
+			allowed = append(allowed, peer.IPBlock.CIDR)
+			rejected = append(rejected, peer.IPBlock.Except...)
+		}
+	}
+	return allowed, rejected
+}
+
+// getHostCIDR converts an IP address to the CIDR matching only that address
+func getHostCIDR(address string) string {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return address
+	case ip.To4() != nil:
+		return address + "/32"
+	default:
+		return address + "/128"
+	}
+}
+
+// buildPgHBARuleEntry builds the pg_hba.conf entry of a `pg_hba_rules`
+// rule for an address. The options are sorted, to get a stable result
+func buildPgHBARuleEntry(rule apiv1.PgHBARule, address string) string {
+	databases := strings.Join(rule.Databases, ",")
+	users := strings.Join(rule.Users, ",")
+	entry := fmt.Sprintf("%s %s %s %s %s",
+		defaultString(rule.Type, "host"),
+		defaultString(databases, "all"),
+		defaultString(users, "all"),
+		address,
+		rule.Method)
+
+	options := make([]string, 0, len(rule.Options))
+	for name := range rule.Options {
+		options = append(options, name)
+	}
+	sort.Strings(options)
+	for _, name := range options {
+		value := rule.Options[name]
+		if strings.ContainsAny(value, " \t") {
+			value = `"` + value + `"`
+		}
+		entry += fmt.Sprintf(" %s=%s", name, value)
+	}
+
+	return entry
+}
+
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func sortedUniqueAddresses(addresses []string) []string {
+	result := stringset.From(addresses).ToList()
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,273 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_hba rules referencing Kubernetes resources", func() {
+	newPod := func(namespace, name, app string, ips ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		for _, ip := range ips {
+			pod.Status.PodIPs = append(pod.Status.PodIPs, corev1.PodIP{IP: ip})
+		}
+		return pod
+	}
+
+	newCapabilities := func(ctx context.Context, haveNamespacesAccess bool) *utils.CapabilitiesRegistry {
+		overrides := utils.CapabilitiesOverrides{}
+		for _, name := range []string{
+			"haveSCC", "haveSeccompSupport", "havePodMonitor", "haveServiceMonitor",
+			"haveVolumeSnapshot", "haveCertManager", "haveKyverno", "haveGatekeeper",
+			"haveKEDA",
+		} {
+			overrides[name] = false
+		}
+		overrides["haveNodesAccess"] = true
+		overrides["haveClusterImageCatalogsAccess"] = true
+		overrides["haveNamespacesAccess"] = haveNamespacesAccess
+
+		capabilities := utils.NewCapabilitiesRegistry(nil, nil, 0)
+		capabilities.SetOverrides(overrides)
+		Expect(capabilities.Detect(ctx)).To(Succeed())
+		return capabilities
+	}
+
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func(ctx SpecContext) {
+		cluster = &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}}
+		objects := []client.Object{
+			cluster,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+			newPod("team-a", "app-1", "web", "10.0.0.1", "fd00::1"),
+			newPod("team-a", "app-2", "batch", "10.0.0.2"),
+			newPod("team-b", "app-3", "web", "10.0.1.1"),
+			newPod("default", "app-4", "web", "10.0.2.1"),
+			&corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "external-consumer"},
+				Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "192.168.1.10"}}}},
+			},
+			&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "office"},
+				Spec: networkingv1.NetworkPolicySpec{Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{
+						CIDR:   "172.16.0.0/16",
+						Except: []string{"172.16.1.0/24"},
+					}}},
+				}}},
+			},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Capabilities: newCapabilities(ctx, true),
+		}
+	})
+
+	renderRule := func(rule apiv1.PgHBARule) []string {
+		cluster.Spec.PostgresConfiguration.PgHBARules = []apiv1.PgHBARule{rule}
+		Expect(reconciler.reconcilePgHBARules(context.TODO(), cluster)).To(Succeed())
+		return cluster.Status.PgHBARules
+	}
+
+	It("renders the addresses of the Pods in the selected namespaces", func() {
+		Expect(renderRule(apiv1.PgHBARule{
+			Databases: []string{"app"},
+			Users:     []string{"app"},
+			Method:    "scram-sha-256",
+			AddressesFrom: &apiv1.PgHBAAddressesSource{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+		})).To(Equal([]string{
+			"host app app 10.0.0.1/32 scram-sha-256",
+			"host app app 10.0.0.2/32 scram-sha-256",
+			"host app app fd00::1/128 scram-sha-256",
+		}))
+	})
+
+	It("keeps the previous rules when the operator cannot list the namespaces", func(ctx SpecContext) {
+		reconciler.Capabilities = newCapabilities(ctx, false)
+		Expect(renderRule(apiv1.PgHBARule{
+			Method: "scram-sha-256",
+			AddressesFrom: &apiv1.PgHBAAddressesSource{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+		})).To(BeEmpty())
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPgHBARules))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPgHBARulesInvalid)))
+	})
+
+	It("restricts the Pods to the cluster namespace when only the Pod selector is set", func() {
+		Expect(renderRule(apiv1.PgHBARule{
+			Method: "scram-sha-256",
+			AddressesFrom: &apiv1.PgHBAAddressesSource{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		})).To(Equal([]string{"host all all 10.0.2.1/32 scram-sha-256"}))
+	})
+
+	It("renders the Service endpoints and the NetworkPolicy CIDRs", func() {
+		Expect(renderRule(apiv1.PgHBARule{
+			Type:    "hostssl",
+			Method:  "cert",
+			Options: map[string]string{"map": "certs"},
+			AddressesFrom: &apiv1.PgHBAAddressesSource{
+				Services:        []string{"external-consumer", "missing"},
+				NetworkPolicies: []string{"office"},
+			},
+		})).To(Equal([]string{
+			"hostssl all all 172.16.1.0/24 reject",
+			"hostssl all all 172.16.0.0/16 cert map=certs",
+			"hostssl all all 192.168.1.10/32 cert map=certs",
+		}))
+	})
+
+	It("asks for the periodic refresh only when resources are referenced", func() {
+		cluster.Spec.PostgresConfiguration.PgHBARules = []apiv1.PgHBARule{{
+			Method: "md5",
+			AddressesFrom: &apiv1.PgHBAAddressesSource{
+				ConfigMapKeyRef: &apiv1.ConfigMapKeySelector{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "office"},
+					Key:                  "cidrs",
+				},
+			},
+		}}
+		Expect(hasPgHBAResourceReferences(cluster)).To(BeFalse())
+
+		cluster.Spec.PostgresConfiguration.PgHBARules[0].AddressesFrom.Services = []string{"external-consumer"}
+		Expect(hasPgHBAResourceReferences(cluster)).To(BeTrue())
+	})
+})
+
+var _ = Describe("pg_hba rules", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(
+					cluster,
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "office"},
+						Data:       map[string]string{"cidrs": "# Offices\n10.1.0.0/16\n\n  fd00::/64\n"},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "partners"},
+						Data:       map[string]string{"cidrs": "192.168.0.0/16\nnot-a-cidr\n"},
+					},
+				).
+				Build(),
+		}
+	})
+
+	It("renders the rules with their options", func() {
+		Expect(buildPgHBARuleEntry(apiv1.PgHBARule{
+			Type:      "hostssl",
+			Databases: []string{"app", "reports"},
+			Users:     []string{"app"},
+			Method:    "cert",
+			Options:   map[string]string{"map": "certs", "clientname": "DN"},
+		}, "all")).To(Equal("hostssl app,reports app all cert clientname=DN map=certs"))
+
+		Expect(buildPgHBARuleEntry(apiv1.PgHBARule{
+			Method:  "ldap",
+			Options: map[string]string{"ldapprefix": "cn=", "ldapsuffix": ", dc=example, dc=com"},
+		}, "10.0.0.0/8")).To(Equal(`host all all 10.0.0.0/8 ldap ldapprefix=cn= ldapsuffix=", dc=example, dc=com"`))
+	})
+
+	It("stores the rendered rules in the cluster status, reading the CIDRs from the ConfigMaps", func() {
+		cluster.Spec.PostgresConfiguration.PgHBARules = []apiv1.PgHBARule{
+			{Address: "samenet", Method: "scram-sha-256"},
+			{
+				AddressesFrom: &apiv1.PgHBAAddressesSource{
+					ConfigMapKeyRef: &apiv1.ConfigMapKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "office"},
+						Key:                  "cidrs",
+					},
+				},
+				Method: "md5",
+			},
+		}
+		Expect(reconciler.reconcilePgHBARules(context.TODO(), cluster)).To(Succeed())
+		Expect(cluster.Status.PgHBARules).To(Equal([]string{
+			"host all all samenet scram-sha-256",
+			"host all all 10.1.0.0/16 md5",
+			"host all all fd00::/64 md5",
+		}))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionPgHBARules))).To(BeTrue())
+	})
+
+	DescribeTable("keeps the previous rules when the addresses cannot be read",
+		func(configMapName, key string) {
+			previousRules := []string{"host all all 10.1.0.0/16 md5"}
+			cluster.Status.PgHBARules = previousRules
+			Expect(reconciler.Status().Update(context.TODO(), cluster)).To(Succeed())
+			cluster.Spec.PostgresConfiguration.PgHBARules = []apiv1.PgHBARule{
+				{Address: "samenet", Method: "scram-sha-256"},
+				{
+					AddressesFrom: &apiv1.PgHBAAddressesSource{
+						ConfigMapKeyRef: &apiv1.ConfigMapKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: configMapName},
+							Key:                  key,
+						},
+					},
+					Method: "md5",
+				},
+			}
+			Expect(reconciler.reconcilePgHBARules(context.TODO(), cluster)).To(Succeed())
+			Expect(cluster.Status.PgHBARules).To(Equal(previousRules))
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPgHBARules))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPgHBARulesInvalid)))
+			Expect(condition.Message).To(ContainSubstring(configMapName))
+		},
+		Entry("with a missing ConfigMap", "missing", "cidrs"),
+		Entry("with a missing key", "office", "missing"),
+		Entry("with an invalid CIDR", "partners", "cidrs"),
+	)
+})
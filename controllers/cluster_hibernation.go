@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileHibernation shuts down the instances of a cluster whose
+// hibernation has been requested, retaining the PVCs. While the cluster
+// is hibernating or hibernated, ErrNextLoop is returned as no other action
+// should be taken. When the hibernation is lifted, the Pods are recreated
+// by the usual reattachment of the dangling PVCs, starting from the
+// target primary
+func (r *ClusterReconciler) reconcileHibernation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	if !cluster.IsHibernationRequested() {
+		return ctrl.Result{}, r.resumeFromHibernation(ctx, cluster)
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	if len(resources.instances.Items) == 0 {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionHibernated)) {
+			r.Recorder.Event(cluster, "Normal", string(apiv1.ConditionReasonHibernated),
+				"Every instance has been shut down")
+		}
+		if err := conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionHibernated),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonHibernated),
+			Message: "Every instance has been shut down",
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseHibernated, ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, ErrNextLoop
+	}
+
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionHibernated)) == nil {
+		r.Recorder.Event(cluster, "Normal", string(apiv1.ConditionReasonHibernationInProgress),
+			"Shutting down every instance")
+	}
+	if err := conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionHibernated),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonHibernationInProgress),
+		Message: "The instances are being shut down",
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseHibernating, ""); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, pod := range getPodsToDeleteForHibernation(cluster, resources.instances.Items) {
+		pod := pod
+		contextLogger.Info("Deleting the instance Pod for hibernation", "pod", pod.Name)
+		if err := r.Delete(ctx, &pod); err != nil && !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("while deleting Pod %s for hibernation: %w", pod.Name, err)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// getPodsToDeleteForHibernation returns the Pods to be deleted in the
+// current step of the hibernation. The primary is shut down first, and
+// the replicas only when it is gone, letting them receive every WAL
+// record written by the primary, including the shutdown checkpoint.
+// Pods which are already terminating are skipped
+func getPodsToDeleteForHibernation(cluster *apiv1.Cluster, pods []corev1.Pod) []corev1.Pod {
+	for _, pod := range pods {
+		if pod.Name == cluster.Status.CurrentPrimary {
+			if !pod.DeletionTimestamp.IsZero() {
+				return nil
+			}
+			return []corev1.Pod{pod}
+		}
+	}
+
+	result := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp.IsZero() {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// resumeFromHibernation removes the Hibernated condition from a cluster
+// whose hibernation has been lifted
+func (r *ClusterReconciler) resumeFromHibernation(ctx context.Context, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionHibernated)) == nil {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Resuming the cluster from hibernation")
+	r.Recorder.Event(cluster, "Normal", "HibernationLifted", "Recreating the instances")
+
+	existingCluster := cluster.DeepCopy()
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionHibernated))
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster)); err != nil {
+		return err
+	}
+
+	return r.RegisterPhase(ctx, cluster, apiv1.PhaseResumingFromHibernation, "")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster hibernation", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+	var pods []corev1.Pod
+
+	newPod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+			Status:     apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		pods = []corev1.Pod{
+			newPod("cluster-example-1"),
+			newPod("cluster-example-2"),
+			newPod("cluster-example-3"),
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, &pods[0], &pods[1], &pods[2]).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("shuts down the primary before the replicas", func() {
+		toDelete := getPodsToDeleteForHibernation(cluster, pods)
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].Name).To(Equal("cluster-example-1"))
+
+		toDelete = getPodsToDeleteForHibernation(cluster, pods[1:])
+		Expect(toDelete).To(HaveLen(2))
+	})
+
+	It("waits for the primary to be terminated", func() {
+		now := metav1.Now()
+		pods[0].DeletionTimestamp = &now
+		Expect(getPodsToDeleteForHibernation(cluster, pods)).To(BeEmpty())
+	})
+
+	It("does nothing when the hibernation is not requested", func() {
+		_, err := reconciler.reconcileHibernation(context.TODO(), cluster,
+			&managedResources{instances: corev1.PodList{Items: pods}})
+		Expect(err).ToNot(HaveOccurred())
+
+		var pod corev1.Pod
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+	})
+
+	It("deletes the instances and marks the cluster as hibernated", func() {
+		cluster.Spec.Hibernation = apiv1.HibernationOn
+		Expect(reconciler.Update(context.TODO(), cluster)).To(Succeed())
+		_, err := reconciler.reconcileHibernation(context.TODO(), cluster,
+			&managedResources{instances: corev1.PodList{Items: pods}})
+		Expect(errors.Is(err, ErrNextLoop)).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHibernating))
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionHibernated))).To(BeTrue())
+
+		var pod corev1.Pod
+		err = reconciler.Get(context.TODO(), client.ObjectKeyFromObject(&pods[0]), &pod)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(&pods[1]), &pod)).To(Succeed())
+
+		_, err = reconciler.reconcileHibernation(context.TODO(), cluster, &managedResources{})
+		Expect(errors.Is(err, ErrNextLoop)).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHibernated))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionHibernated))).To(BeTrue())
+	})
+
+	It("removes the hibernation condition when the cluster is resumed", func() {
+		cluster.Spec.Hibernation = apiv1.HibernationOn
+		Expect(reconciler.Update(context.TODO(), cluster)).To(Succeed())
+		_, err := reconciler.reconcileHibernation(context.TODO(), cluster, &managedResources{})
+		Expect(errors.Is(err, ErrNextLoop)).To(BeTrue())
+
+		cluster.Spec.Hibernation = apiv1.HibernationOff
+		Expect(reconciler.Update(context.TODO(), cluster)).To(Succeed())
+		_, err = reconciler.reconcileHibernation(context.TODO(), cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseResumingFromHibernation))
+
+		var updated apiv1.Cluster
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, string(apiv1.ConditionHibernated))).To(BeNil())
+	})
+})
@@ -0,0 +1,161 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// reconcileImage resolves the image of the instances from the image
+// catalog referenced by the cluster, storing it in the status
+func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.Cluster) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	image, err := r.getImageFromCatalog(ctx, cluster)
+	if err != nil {
+		contextLogger.Info("Cannot retrieve the image from the catalog", "reason", err.Error())
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseImageCatalogError, err.Error()); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{}, ErrNextLoop
+	}
+
+	if image == cluster.Status.Image {
+		return nil, nil
+	}
+
+	contextLogger.Info("Updating the image from the catalog",
+		"previousImage", cluster.Status.Image,
+		"image", image)
+	origCluster := cluster.DeepCopy()
+	cluster.Status.Image = image
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// getImageFromCatalog gets the image to be used for the major version
+// requested by the cluster, returning an empty string when the cluster
+// is not using an image catalog
+func (r *ClusterReconciler) getImageFromCatalog(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	ref := cluster.Spec.ImageCatalogRef
+	if ref == nil {
+		return "", nil
+	}
+
+	var spec *apiv1.ImageCatalogSpec
+	switch ref.Kind {
+	case apiv1.ImageCatalogKind:
+		var catalog apiv1.ImageCatalog
+		err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: ref.Name}, &catalog)
+		if apierrs.IsNotFound(err) {
+			return "", fmt.Errorf("%s %s not found", ref.Kind, ref.Name)
+		}
+		if err != nil {
+			return "", err
+		}
+		spec = &catalog.Spec
+	case apiv1.ClusterImageCatalogKind:
+		// Reading a ClusterImageCatalog through the cache would start an
+		// informer that can never be synced without the permission to watch them
+		if !r.Capabilities.Get().HaveClusterImageCatalogsAccess {
+			return "", fmt.Errorf("the operator is not allowed to read the %s resources", ref.Kind)
+		}
+
+		var catalog apiv1.ClusterImageCatalog
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, &catalog)
+		if apierrs.IsNotFound(err) {
+			return "", fmt.Errorf("%s %s not found", ref.Kind, ref.Name)
+		}
+		if err != nil {
+			return "", err
+		}
+		spec = &catalog.Spec
+	default:
+		return "", fmt.Errorf("unsupported image catalog kind: %s", ref.Kind)
+	}
+
+	image, ok := spec.FindImageForMajor(ref.Major)
+	if !ok {
+		return "", fmt.Errorf("no image for major version %d in %s %s", ref.Major, ref.Kind, ref.Name)
+	}
+
+	return image, nil
+}
+
+// mapImageCatalogsToClusters returns a function mapping the events of the
+// image catalogs to the reconcile requests of the clusters using them
+func (r *ClusterReconciler) mapImageCatalogsToClusters(ctx context.Context) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var kind string
+		var listOptions []client.ListOption
+		switch obj.(type) {
+		case *apiv1.ImageCatalog:
+			kind = apiv1.ImageCatalogKind
+			listOptions = append(listOptions, client.InNamespace(obj.GetNamespace()))
+		case *apiv1.ClusterImageCatalog:
+			kind = apiv1.ClusterImageCatalogKind
+		default:
+			return nil
+		}
+
+		var clusters apiv1.ClusterList
+		if err := r.List(ctx, &clusters, listOptions...); err != nil {
+			log.FromContext(ctx).Error(err, "while getting cluster list", "catalog", obj.GetName())
+			return nil
+		}
+
+		return filterClustersUsingImageCatalog(clusters, kind, obj.GetName())
+	}
+}
+
+// filterClustersUsingImageCatalog returns a list of reconcile.Request for
+// the clusters that reference the image catalog
+func filterClustersUsingImageCatalog(
+	clusters apiv1.ClusterList,
+	kind string,
+	name string,
+) (requests []reconcile.Request) {
+	for _, cluster := range clusters.Items {
+		ref := cluster.Spec.ImageCatalogRef
+		if ref == nil || ref.Kind != kind || ref.Name != name {
+			continue
+		}
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      cluster.Name,
+					Namespace: cluster.Namespace,
+				},
+			},
+		)
+	}
+	return requests
+}
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("image catalogs", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+
+	newCatalogRef := func(kind string, major int) *apiv1.ImageCatalogRef {
+		return &apiv1.ImageCatalogRef{
+			TypedLocalObjectReference: corev1.TypedLocalObjectReference{
+				APIGroup: pointer.String(apiv1.GroupVersion.Group),
+				Kind:     kind,
+				Name:     "catalog",
+			},
+			Major: major,
+		}
+	}
+
+	getCluster := func() *apiv1.Cluster {
+		var updated apiv1.Cluster
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return &updated
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances:       1,
+				ImageCatalogRef: newCatalogRef(apiv1.ImageCatalogKind, 15),
+			},
+		}
+		catalogSpec := apiv1.ImageCatalogSpec{
+			Images: []apiv1.CatalogImage{
+				{Major: 14, Image: "postgres:14.9"},
+				{Major: 15, Image: "postgres:15.4"},
+			},
+		}
+		catalog := &apiv1.ImageCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"},
+			Spec:       catalogSpec,
+		}
+		clusterCatalog := &apiv1.ClusterImageCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: "catalog"},
+			Spec:       *catalogSpec.DeepCopy(),
+		}
+		clusterCatalog.Spec.Images[1].Image = "postgres:15.3"
+
+		scheme := schemeBuilder.BuildWithAllKnownScheme()
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, catalog, clusterCatalog).
+				Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("resolves the image from the image catalog", func() {
+		result, err := reconciler.reconcileImage(context.TODO(), cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(getCluster().Status.Image).To(Equal("postgres:15.4"))
+		Expect(cluster.GetImageName()).To(Equal("postgres:15.4"))
+	})
+
+	It("resolves the image from the cluster image catalog", func() {
+		cluster.Spec.ImageCatalogRef = newCatalogRef(apiv1.ClusterImageCatalogKind, 15)
+		result, err := reconciler.reconcileImage(context.TODO(), cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(getCluster().Status.Image).To(Equal("postgres:15.3"))
+	})
+
+	It("doesn't read the cluster image catalogs without the permission to watch them", func(ctx SpecContext) {
+		overrides := utils.CapabilitiesOverrides{}
+		for _, name := range []string{
+			"haveSCC", "haveSeccompSupport", "havePodMonitor", "haveServiceMonitor",
+			"haveVolumeSnapshot", "haveCertManager", "haveKyverno", "haveGatekeeper",
+			"haveKEDA", "haveNodesAccess", "haveNamespacesAccess", "haveClusterImageCatalogsAccess",
+		} {
+			overrides[name] = false
+		}
+		reconciler.Capabilities = utils.NewCapabilitiesRegistry(nil, nil, 0)
+		reconciler.Capabilities.SetOverrides(overrides)
+		Expect(reconciler.Capabilities.Detect(ctx)).To(Succeed())
+
+		cluster.Spec.ImageCatalogRef = newCatalogRef(apiv1.ClusterImageCatalogKind, 15)
+		result, err := reconciler.reconcileImage(ctx, cluster)
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(result).ToNot(BeNil())
+		Expect(getCluster().Status.Phase).To(Equal(apiv1.PhaseImageCatalogError))
+		Expect(getCluster().Status.Image).To(BeEmpty())
+	})
+
+	It("stops the reconciliation when the major version is not in the catalog", func() {
+		cluster.Spec.ImageCatalogRef.Major = 16
+		result, err := reconciler.reconcileImage(context.TODO(), cluster)
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(result).ToNot(BeNil())
+		Expect(getCluster().Status.Phase).To(Equal(apiv1.PhaseImageCatalogError))
+	})
+
+	It("stops the reconciliation when the catalog doesn't exist", func() {
+		cluster.Spec.ImageCatalogRef.Name = "missing"
+		result, err := reconciler.reconcileImage(context.TODO(), cluster)
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(result).ToNot(BeNil())
+	})
+
+	It("does nothing when the cluster is not using an image catalog", func() {
+		cluster.Spec.ImageCatalogRef = nil
+		result, err := reconciler.reconcileImage(context.TODO(), cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(getCluster().Status.Image).To(BeEmpty())
+	})
+
+	It("maps the image catalogs to the clusters using them", func() {
+		other := cluster.DeepCopy()
+		other.Name = "cluster-other"
+		other.Spec.ImageCatalogRef = newCatalogRef(apiv1.ClusterImageCatalogKind, 15)
+		clusters := apiv1.ClusterList{Items: []apiv1.Cluster{*cluster, *other}}
+
+		requests := filterClustersUsingImageCatalog(clusters, apiv1.ImageCatalogKind, "catalog")
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal("cluster-example"))
+
+		requests = filterClustersUsingImageCatalog(clusters, apiv1.ClusterImageCatalogKind, "catalog")
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal("cluster-other"))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// deferRolloutToMaintenanceWindow checks whether the rollout of the
+// instances needs to wait for the next maintenance window of the cluster,
+// recording the deferred operations in the status. When the rollout is
+// deferred, the time until the beginning of the next window is returned
+func (r *ClusterReconciler) deferRolloutToMaintenanceWindow(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus *postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var pendingMaintenance *apiv1.PendingMaintenanceStatus
+	inWindow, nextWindow, err := cluster.IsInMaintenanceWindow(time.Now())
+	if err != nil {
+		// The webhook prevents this from happening, we don't want to
+		// block the rollout forever because of an invalid window
+		contextLogger.Error(err, "Invalid maintenance window, ignoring it")
+		inWindow = true
+	}
+
+	if !inWindow {
+		if operations := getPendingRollouts(cluster, instancesStatus); len(operations) > 0 {
+			pendingMaintenance = &apiv1.PendingMaintenanceStatus{
+				Operations: operations,
+				NextWindow: nextWindow.Format(time.RFC3339),
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(cluster.Status.PendingMaintenance, pendingMaintenance) {
+		if pendingMaintenance != nil {
+			contextLogger.Info("Deferring the rollout of the instances to the next maintenance window",
+				"nextWindow", pendingMaintenance.NextWindow,
+				"operations", pendingMaintenance.Operations)
+			r.Recorder.Eventf(cluster, "Normal", "RolloutDeferred",
+				"Rollout deferred to the maintenance window starting at %s", pendingMaintenance.NextWindow)
+		}
+
+		existingCluster := cluster.DeepCopy()
+		cluster.Status.PendingMaintenance = pendingMaintenance
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster)); err != nil {
+			return 0, err
+		}
+	}
+
+	if pendingMaintenance == nil {
+		return 0, nil
+	}
+
+	return time.Until(nextWindow), nil
+}
+
+// getPendingRollouts describes the instances needing to be restarted,
+// following the same rules used by rolloutDueToCondition
+func getPendingRollouts(cluster *apiv1.Cluster, instancesStatus *postgres.PostgresqlStatusList) []string {
+	var operations []string
+	for _, status := range instancesStatus.Items {
+		shouldRestart, _, reason := IsPodNeedingRollout(status, cluster)
+		if !shouldRestart {
+			continue
+		}
+
+		isPrimary := cluster.Status.CurrentPrimary == status.Pod.Name
+		if isPrimary && status.PendingRestartForDecrease {
+			continue
+		}
+
+		if reason == "" {
+			reason = "the instance manager needs to be upgraded"
+		}
+		operation := fmt.Sprintf("restart of %s: %s", status.Pod.Name, reason)
+		if isPrimary && cluster.Status.Instances > 1 &&
+			cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodSwitchover {
+			operation = fmt.Sprintf("switchover from %s: %s", status.Pod.Name, reason)
+		}
+		operations = append(operations, operation)
+	}
+
+	return operations
+}
@@ -0,0 +1,482 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// reconcileMajorUpgrade detects a change of the image to a new PostgreSQL
+// major version and drives the upgrade of the data. While the upgrade is
+// running, ErrNextLoop is returned as no other action should be taken
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+	upgrade := cluster.Status.MajorVersionUpgrade
+	requestedImage := cluster.GetRequestedImageName()
+
+	switch {
+	case upgrade == nil:
+		return r.startMajorUpgrade(ctx, cluster, resources)
+
+	case upgrade.Phase == apiv1.MajorVersionUpgradePhaseRunning:
+		return r.runMajorUpgrade(ctx, cluster, resources)
+
+	case requestedImage == upgrade.TargetImage:
+		// The upgrade failed, and the instances are running the source
+		// image until the user changes the image again
+		return ctrl.Result{}, nil
+	}
+
+	isMajorUpgrade, err := postgres.IsMajorVersionUpgrade(upgrade.SourceImage, requestedImage)
+	if err != nil || !isMajorUpgrade {
+		contextLogger.Info("The failed major version upgrade has been rolled back by the user",
+			"sourceImage", upgrade.SourceImage, "requestedImage", requestedImage)
+		cluster.Status.MajorVersionUpgrade = nil
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(cluster, "Normal", "MajorVersionUpgradeRolledBack",
+			"The major version upgrade to %s has been rolled back", upgrade.TargetImage)
+		return ctrl.Result{}, nil
+	}
+
+	contextLogger.Info("Retrying the failed major version upgrade with a new image",
+		"sourceImage", upgrade.SourceImage, "targetImage", requestedImage)
+	upgrade.TargetImage = requestedImage
+	upgrade.Phase = apiv1.MajorVersionUpgradePhaseRunning
+	upgrade.Message = ""
+	return r.registerMajorUpgradeStarted(ctx, cluster)
+}
+
+// startMajorUpgrade starts a major version upgrade if the image requested
+// for the cluster contains a PostgreSQL major version newer than the one
+// of the current primary instance
+func (r *ClusterReconciler) startMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	if !cluster.IsMajorVersionUpgradeEnabled() {
+		return ctrl.Result{}, nil
+	}
+
+	var primary *corev1.Pod
+	for idx := range resources.instances.Items {
+		if resources.instances.Items[idx].Name == cluster.Status.CurrentPrimary {
+			primary = &resources.instances.Items[idx]
+		}
+	}
+	if primary == nil {
+		return ctrl.Result{}, nil
+	}
+
+	sourceImage, err := specs.GetPostgresImageName(*primary)
+	if err != nil {
+		return ctrl.Result{}, nil
+	}
+
+	requestedImage := cluster.GetRequestedImageName()
+	isMajorUpgrade, err := postgres.IsMajorVersionUpgrade(sourceImage, requestedImage)
+	if err != nil || !isMajorUpgrade {
+		return ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("Starting the major version upgrade",
+		"sourceImage", sourceImage, "targetImage", requestedImage, "primary", primary.Name)
+	cluster.Status.MajorVersionUpgrade = &apiv1.MajorVersionUpgradeStatus{
+		SourceImage: sourceImage,
+		TargetImage: requestedImage,
+		Primary:     primary.Name,
+		Phase:       apiv1.MajorVersionUpgradePhaseRunning,
+	}
+	return r.registerMajorUpgradeStarted(ctx, cluster)
+}
+
+func (r *ClusterReconciler) registerMajorUpgradeStarted(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	upgrade := cluster.Status.MajorVersionUpgrade
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+		fmt.Sprintf("Upgrading from %s to %s", upgrade.SourceImage, upgrade.TargetImage)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "MajorVersionUpgradeStarted",
+		"Upgrading the data of %s from %s to %s", upgrade.Primary, upgrade.SourceImage, upgrade.TargetImage)
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// runMajorUpgrade stops the instances and runs the upgrade job against
+// the volumes of the primary instance. When the job succeeds, the volumes
+// of the replicas are removed, so that they are cloned again from the
+// upgraded primary. When the job fails, the instances are restarted with
+// the source image
+func (r *ClusterReconciler) runMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+	upgrade := cluster.Status.MajorVersionUpgrade
+
+	// pg_upgrade needs every instance to be shut down
+	if len(resources.instances.Items) > 0 {
+		for idx := range resources.instances.Items {
+			pod := &resources.instances.Items[idx]
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			contextLogger.Info("Deleting the instance before the major version upgrade", "pod", pod.Name)
+			if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+	}
+
+	if recoveryJob := findMajorUpgradeJob(
+		resources.jobs.Items, upgrade.Primary, specs.MajorUpgradeRecoveryJobRole,
+	); recoveryJob != nil {
+		return r.reconcileMajorUpgradeRecovery(ctx, cluster, resources, recoveryJob)
+	}
+
+	job := findMajorUpgradeJob(resources.jobs.Items, upgrade.Primary, specs.MajorUpgradeJobRole)
+	switch {
+	case job == nil:
+		return r.createMajorUpgradeJob(ctx, cluster, resources)
+
+	case utils.IsJobComplete(*job):
+		if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.completeMajorUpgrade(ctx, cluster, resources)
+
+	case utils.IsJobFailed(*job):
+		return r.recoverMajorUpgrade(ctx, cluster, resources, job)
+	}
+
+	contextLogger.Debug("Waiting for the major version upgrade job to finish", "job", job.Name)
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// findMajorUpgradeJob finds the job with the passed role working on the
+// data of the passed instance
+func findMajorUpgradeJob(jobs []batchv1.Job, instanceName string, role string) *batchv1.Job {
+	for idx := range jobs {
+		if jobs[idx].Labels[utils.JobRoleLabelName] == role &&
+			jobs[idx].Labels[utils.InstanceNameLabelName] == instanceName {
+			return &jobs[idx]
+		}
+	}
+
+	return nil
+}
+
+// getPrimaryNodeSerial gets the serial of the primary instance being
+// upgraded from its PVC, or -1 if it cannot be found
+func getPrimaryNodeSerial(upgrade *apiv1.MajorVersionUpgradeStatus, resources *managedResources) (int, error) {
+	for idx := range resources.pvcs.Items {
+		if resources.pvcs.Items[idx].Name != upgrade.Primary {
+			continue
+		}
+		return specs.GetNodeSerial(resources.pvcs.Items[idx].ObjectMeta)
+	}
+
+	return -1, nil
+}
+
+func (r *ClusterReconciler) createMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	upgrade := cluster.Status.MajorVersionUpgrade
+
+	nodeSerial, err := getPrimaryNodeSerial(upgrade, resources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if nodeSerial < 0 {
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.registerMajorUpgradeFailure(ctx, cluster,
+			fmt.Sprintf("cannot find the PVC of the primary instance %s", upgrade.Primary))
+	}
+
+	return r.createMajorUpgradeJobObject(ctx, cluster,
+		specs.CreateMajorUpgradeJob(*cluster, nodeSerial, upgrade.SourceImage))
+}
+
+// createMajorUpgradeJobObject creates a job working on the data of the
+// primary instance being upgraded
+func (r *ClusterReconciler) createMajorUpgradeJobObject(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to set the owner reference for the major upgrade job: %w", err)
+	}
+
+	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
+	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+
+	result, err := r.checkAdmissionPolicies(ctx, cluster, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+
+	log.FromContext(ctx).Info("Creating the major version upgrade job", "job", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		if apierrs.IsAlreadyExists(err) {
+			// The cache is stale, let's reconcile another time
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to create the job %s: %w", job.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// completeMajorUpgrade removes the volumes of the replicas, which are
+// not upgraded, and lets the primary instance start with the new image
+func (r *ClusterReconciler) completeMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+	upgrade := cluster.Status.MajorVersionUpgrade
+
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		if specs.DoesPVCBelongToInstance(cluster, upgrade.Primary, pvc.Name) {
+			continue
+		}
+		contextLogger.Info("Deleting the PVC of a replica to clone it from the upgraded primary", "pvc", pvc.Name)
+		if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	cluster.Status.MajorVersionUpgrade = nil
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "MajorVersionUpgradeCompleted",
+		"The data of %s has been upgraded to %s", upgrade.Primary, upgrade.TargetImage)
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// recoverMajorUpgrade records why the upgrade job failed and replaces it
+// with a job bringing the data directory of the primary back to a
+// consistent state, as the upgrade job may have been killed before being
+// able to roll back, or while replacing the old data directory
+func (r *ClusterReconciler) recoverMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	upgrade := cluster.Status.MajorVersionUpgrade
+
+	nodeSerial, err := getPrimaryNodeSerial(upgrade, resources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if nodeSerial < 0 {
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.registerMajorUpgradeFailure(ctx, cluster,
+			fmt.Sprintf("cannot find the PVC of the primary instance %s", upgrade.Primary))
+	}
+
+	message := fmt.Sprintf("the major version upgrade job %s failed", job.Name)
+	if terminationMessage := r.getJobTerminationMessage(ctx, job); terminationMessage != "" {
+		message = fmt.Sprintf("%s: %s", message, terminationMessage)
+	}
+	if upgrade.Message != message {
+		upgrade.Message = message
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.createMajorUpgradeJobObject(ctx, cluster, specs.CreateMajorUpgradeRecoveryJob(*cluster, nodeSerial))
+	if !errors.Is(err, ErrNextLoop) {
+		return result, err
+	}
+
+	if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return result, ErrNextLoop
+}
+
+// reconcileMajorUpgradeRecovery waits for the recovery job to finish and
+// completes the upgrade or restarts the instances with the source image,
+// depending on the state in which the data directory has been found
+func (r *ClusterReconciler) reconcileMajorUpgradeRecovery(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	upgrade := cluster.Status.MajorVersionUpgrade
+
+	switch {
+	case utils.IsJobComplete(*job):
+		recoveryResult := r.getJobResult(ctx, job)
+		if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		if recoveryResult == string(postgresManagement.UpgradeRecoveryResultCompleted) {
+			log.FromContext(ctx).Info("The data directory had already been upgraded by the failed job",
+				"primary", upgrade.Primary)
+			return r.completeMajorUpgrade(ctx, cluster, resources)
+		}
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.registerMajorUpgradeFailure(ctx, cluster, upgrade.Message)
+
+	case utils.IsJobFailed(*job):
+		message := fmt.Sprintf("%s, and the recovery job %s failed: the data directory of %s "+
+			"needs a manual intervention", upgrade.Message, job.Name, upgrade.Primary)
+		if terminationMessage := r.getJobTerminationMessage(ctx, job); terminationMessage != "" {
+			message = fmt.Sprintf("%s: %s", message, terminationMessage)
+		}
+		if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, r.registerMajorUpgradeFailure(ctx, cluster, message)
+	}
+
+	log.FromContext(ctx).Debug("Waiting for the major version upgrade recovery job to finish", "job", job.Name)
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+}
+
+// deleteMajorUpgradeJob removes a finished job, so that it's not found
+// by the next upgrade
+func (r *ClusterReconciler) deleteMajorUpgradeJob(ctx context.Context, job *batchv1.Job) error {
+	foreground := metav1.DeletePropagationForeground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{
+		PropagationPolicy: &foreground,
+	}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClusterReconciler) registerMajorUpgradeFailure(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	message string,
+) error {
+	upgrade := cluster.Status.MajorVersionUpgrade
+	log.FromContext(ctx).Warning("The major version upgrade failed, restarting the instances with the source image",
+		"sourceImage", upgrade.SourceImage, "targetImage", upgrade.TargetImage, "message", message)
+
+	upgrade.Phase = apiv1.MajorVersionUpgradePhaseFailed
+	upgrade.Message = message
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(cluster, "Warning", "MajorVersionUpgradeFailed",
+		"Restarting the instances with %s: %s", upgrade.SourceImage, message)
+	return ErrNextLoop
+}
+
+// getJobTerminationMessage gets the termination message of the failed
+// container of a job, if any
+func (r *ClusterReconciler) getJobTerminationMessage(ctx context.Context, job *batchv1.Job) string {
+	return r.findJobTerminationMessage(ctx, job, func(status corev1.ContainerStatus) bool {
+		return status.State.Terminated.ExitCode != 0
+	})
+}
+
+// getJobResult gets the termination message of the succeeded container
+// of a job, if any
+func (r *ClusterReconciler) getJobResult(ctx context.Context, job *batchv1.Job) string {
+	return r.findJobTerminationMessage(ctx, job, func(status corev1.ContainerStatus) bool {
+		return status.Name == job.Spec.Template.Spec.Containers[0].Name && status.State.Terminated.ExitCode == 0
+	})
+}
+
+// findJobTerminationMessage gets the termination message of the first
+// terminated container of a job matching the passed filter
+func (r *ClusterReconciler) findJobTerminationMessage(
+	ctx context.Context,
+	job *batchv1.Job,
+	filter func(corev1.ContainerStatus) bool,
+) string {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		log.FromContext(ctx).Warning("Cannot list the pods of the job", "job", job.Name, "error", err)
+		return ""
+	}
+
+	for _, pod := range pods.Items {
+		for _, statuses := range [][]corev1.ContainerStatus{
+			pod.Status.InitContainerStatuses,
+			pod.Status.ContainerStatuses,
+		} {
+			for _, containerStatus := range statuses {
+				if containerStatus.State.Terminated != nil && filter(containerStatus) {
+					return strings.TrimSpace(containerStatus.State.Terminated.Message)
+				}
+			}
+		}
+	}
+
+	return ""
+}
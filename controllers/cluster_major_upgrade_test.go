@@ -0,0 +1,302 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major version upgrade", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+	var resources *managedResources
+
+	newPod := func(name, image string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: specs.PostgresContainerName, Image: image}},
+			},
+		}
+	}
+
+	newPVC := func(name, serial string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{specs.ClusterSerialAnnotationName: serial},
+			},
+		}
+	}
+
+	getCluster := func() *apiv1.Cluster {
+		var updated apiv1.Cluster
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return &updated
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances:           2,
+				ImageName:           "postgres:15.1",
+				MajorVersionUpgrade: &apiv1.MajorVersionUpgradeConfiguration{},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		resources = &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{
+				newPod("cluster-example-1", "postgres:14.5"),
+				newPod("cluster-example-2", "postgres:14.5"),
+			}},
+			pvcs: corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{
+				newPVC("cluster-example-1", "1"),
+				newPVC("cluster-example-2", "2"),
+			}},
+		}
+
+		objects := []client.Object{cluster}
+		for idx := range resources.instances.Items {
+			objects = append(objects, &resources.instances.Items[idx])
+		}
+		for idx := range resources.pvcs.Items {
+			objects = append(objects, &resources.pvcs.Items[idx])
+		}
+		scheme := schemeBuilder.BuildWithAllKnownScheme()
+		reconciler = &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("does nothing when the major version upgrades are not enabled", func() {
+		cluster.Spec.MajorVersionUpgrade = nil
+		_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getCluster().Status.MajorVersionUpgrade).To(BeNil())
+	})
+
+	It("does nothing when the primary is already running the requested major version", func() {
+		cluster.Spec.ImageName = "postgres:14.6"
+		_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getCluster().Status.MajorVersionUpgrade).To(BeNil())
+	})
+
+	It("starts the upgrade when the image contains a new major version", func() {
+		_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+		Expect(err).To(MatchError(ErrNextLoop))
+
+		updated := getCluster()
+		Expect(updated.Status.Phase).To(Equal(apiv1.PhaseMajorUpgrade))
+		Expect(updated.Status.MajorVersionUpgrade).To(Equal(&apiv1.MajorVersionUpgradeStatus{
+			SourceImage: "postgres:14.5",
+			TargetImage: "postgres:15.1",
+			Primary:     "cluster-example-1",
+			Phase:       apiv1.MajorVersionUpgradePhaseRunning,
+		}))
+	})
+
+	Context("when the upgrade is running", func() {
+		BeforeEach(func() {
+			cluster.Status.MajorVersionUpgrade = &apiv1.MajorVersionUpgradeStatus{
+				SourceImage: "postgres:14.5",
+				TargetImage: "postgres:15.1",
+				Primary:     "cluster-example-1",
+				Phase:       apiv1.MajorVersionUpgradePhaseRunning,
+			}
+		})
+
+		It("shuts down every instance before creating the upgrade job", func() {
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+
+			var pods corev1.PodList
+			Expect(reconciler.List(context.TODO(), &pods)).To(Succeed())
+			Expect(pods.Items).To(BeEmpty())
+
+			resources.instances.Items = nil
+			_, err = reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+
+			var job batchv1.Job
+			Expect(reconciler.Get(context.TODO(),
+				client.ObjectKey{Namespace: "default", Name: "cluster-example-1-major-upgrade"}, &job)).To(Succeed())
+			Expect(job.Spec.Template.Spec.InitContainers[len(job.Spec.Template.Spec.InitContainers)-1].Image).
+				To(Equal("postgres:14.5"))
+			Expect(job.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("removes the volumes of the replicas when the upgrade succeeds", func() {
+			resources.instances.Items = nil
+			job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:14.5")
+			job.Status.Succeeded = 1
+			resources.jobs.Items = []batchv1.Job{*job}
+
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+
+			var pvc corev1.PersistentVolumeClaim
+			Expect(reconciler.Get(context.TODO(),
+				client.ObjectKey{Namespace: "default", Name: "cluster-example-1"}, &pvc)).To(Succeed())
+			err = reconciler.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pvc)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+			updated := getCluster()
+			Expect(updated.Status.MajorVersionUpgrade).To(BeNil())
+			Expect(updated.GetImageName()).To(Equal("postgres:15.1"))
+		})
+
+		It("recovers the data directory when the upgrade fails", func() {
+			resources.instances.Items = nil
+			job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:14.5")
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(reconciler.Create(context.TODO(), job)).To(Succeed())
+			resources.jobs.Items = []batchv1.Job{*job}
+
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+
+			err = reconciler.Get(context.TODO(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+			var recoveryJob batchv1.Job
+			Expect(reconciler.Get(context.TODO(), client.ObjectKey{
+				Namespace: "default", Name: "cluster-example-1-major-upgrade-recovery",
+			}, &recoveryJob)).To(Succeed())
+			Expect(recoveryJob.Spec.Template.Spec.Containers[0].Image).To(Equal("postgres:15.1"))
+
+			// The instances are not restarted until the recovery is finished
+			updated := getCluster()
+			Expect(updated.Status.MajorVersionUpgrade.Phase).To(Equal(apiv1.MajorVersionUpgradePhaseRunning))
+			Expect(updated.Status.MajorVersionUpgrade.Message).To(ContainSubstring(job.Name))
+		})
+
+		completeRecovery := func(result string) {
+			resources.instances.Items = nil
+			cluster.Status.MajorVersionUpgrade.Message = "the major version upgrade job failed"
+			Expect(reconciler.Status().Update(context.TODO(), cluster)).To(Succeed())
+
+			job := specs.CreateMajorUpgradeRecoveryJob(*cluster, 1)
+			job.Status.Succeeded = 1
+			Expect(reconciler.Create(context.TODO(), job)).To(Succeed())
+			resources.jobs.Items = []batchv1.Job{*job}
+			Expect(reconciler.Create(context.TODO(), &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: job.Name + "-abcde", Namespace: "default",
+					Labels: map[string]string{"job-name": job.Name},
+				},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name: specs.MajorUpgradeRecoveryJobRole,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Message: result},
+					},
+				}}},
+			})).To(Succeed())
+
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+			err = reconciler.Get(context.TODO(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		}
+
+		It("restarts the instances with the source image when the upgrade is rolled back", func() {
+			completeRecovery("rolledBack")
+
+			updated := getCluster()
+			Expect(updated.Status.MajorVersionUpgrade.Phase).To(Equal(apiv1.MajorVersionUpgradePhaseFailed))
+			Expect(updated.Status.MajorVersionUpgrade.Message).To(Equal("the major version upgrade job failed"))
+			Expect(updated.GetImageName()).To(Equal("postgres:14.5"))
+		})
+
+		It("completes the upgrade when the failed job had already upgraded the data", func() {
+			completeRecovery("completed")
+
+			updated := getCluster()
+			Expect(updated.Status.MajorVersionUpgrade).To(BeNil())
+			Expect(updated.GetImageName()).To(Equal("postgres:15.1"))
+		})
+	})
+
+	Context("when the upgrade failed", func() {
+		BeforeEach(func() {
+			cluster.Status.MajorVersionUpgrade = &apiv1.MajorVersionUpgradeStatus{
+				SourceImage: "postgres:14.5",
+				TargetImage: "postgres:15.1",
+				Primary:     "cluster-example-1",
+				Phase:       apiv1.MajorVersionUpgradePhaseFailed,
+			}
+		})
+
+		It("keeps the instances running the source image", func() {
+			result, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.IsZero()).To(BeTrue())
+		})
+
+		It("forgets the upgrade when the user restores the source image", func() {
+			cluster.Spec.ImageName = "postgres:14.5"
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCluster().Status.MajorVersionUpgrade).To(BeNil())
+		})
+
+		It("retries the upgrade when the user requests another image", func() {
+			cluster.Spec.ImageName = "postgres:15.2"
+			_, err := reconciler.reconcileMajorUpgrade(context.TODO(), cluster, resources)
+			Expect(err).To(MatchError(ErrNextLoop))
+
+			upgrade := getCluster().Status.MajorVersionUpgrade
+			Expect(upgrade.Phase).To(Equal(apiv1.MajorVersionUpgradePhaseRunning))
+			Expect(upgrade.TargetImage).To(Equal("postgres:15.2"))
+		})
+	})
+
+	It("finds the upgrade job of an instance", func() {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:14.5")
+		joinJob := specs.JoinReplicaInstance(*cluster, 2)
+		recoveryJob := specs.CreateMajorUpgradeRecoveryJob(*cluster, 1)
+		jobs := []batchv1.Job{*joinJob, *recoveryJob, *job}
+		Expect(findMajorUpgradeJob(jobs, "cluster-example-1", specs.MajorUpgradeJobRole).Name).To(Equal(job.Name))
+		Expect(findMajorUpgradeJob(jobs, "cluster-example-1", specs.MajorUpgradeRecoveryJobRole).Name).
+			To(Equal(recoveryJob.Name))
+		Expect(findMajorUpgradeJob(jobs, "cluster-example-2", specs.MajorUpgradeJobRole)).To(BeNil())
+		Expect(job.Labels[utils.JobRoleLabelName]).To(Equal(specs.MajorUpgradeJobRole))
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +35,10 @@ import (
 // setupPostgresPKI create all the PKI infrastructure that PostgreSQL need to work
 // if using ssl=on
 func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.IsCertManagerEnabled() {
+		return r.setupCertManagerPKI(ctx, cluster)
+	}
+
 	// This is the CA of cluster
 	serverCaSecret, err := r.ensureServerCASecret(ctx, cluster)
 	if err != nil {
@@ -44,6 +49,10 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 	}
 
 	// This is the certificate for the server
+	serverAltDNSNames, err := r.getServerAltDNSNames(ctx, cluster)
+	if err != nil {
+		return err
+	}
 	serverCertificateName := client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerTLSSecretName()}
 	opts := x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
 	err = r.ensureServerLeafCertificate(
@@ -53,7 +62,7 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 		cluster.GetServiceReadWriteName(),
 		serverCaSecret,
 		certs.CertTypeServer,
-		cluster.GetClusterAltDNSNames(),
+		serverAltDNSNames,
 		&opts)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -92,6 +101,43 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 	return nil
 }
 
+// getServerAltDNSNames returns the names to be included in the server
+// certificate. Other than the names of the services of the cluster and the
+// ones requested by the user, the certificate contains the names of the
+// services of the poolers pointing to the cluster, as PgBouncer presents
+// the same certificate to its clients
+func (r *ClusterReconciler) getServerAltDNSNames(ctx context.Context, cluster *apiv1.Cluster) ([]string, error) {
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{poolerClusterKey: cluster.Name},
+	); err != nil {
+		return nil, fmt.Errorf("while getting poolers for cluster %s: %w", cluster.Name, err)
+	}
+
+	return append(cluster.GetClusterAltDNSNames(), buildPoolersAltDNSNames(poolers.Items)...), nil
+}
+
+// buildPoolersAltDNSNames returns the names of the services of the passed
+// poolers, sorted to keep the certificate stable
+func buildPoolersAltDNSNames(poolers []apiv1.Pooler) []string {
+	sortedPoolers := make([]apiv1.Pooler, len(poolers))
+	copy(sortedPoolers, poolers)
+	sort.Slice(sortedPoolers, func(i, j int) bool {
+		return sortedPoolers[i].Name < sortedPoolers[j].Name
+	})
+
+	var result []string
+	for _, pooler := range sortedPoolers {
+		result = append(result,
+			pooler.Name,
+			fmt.Sprintf("%v.%v", pooler.Name, pooler.Namespace),
+			fmt.Sprintf("%v.%v.svc", pooler.Name, pooler.Namespace),
+		)
+	}
+	return result
+}
+
 // ensureClientCASecret ensure that the cluster CA really exist and is valid
 func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *apiv1.Cluster) (*v1.Secret, error) {
 	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ClientCASecret == "" {
@@ -300,6 +346,11 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	var secret v1.Secret
 	err := r.Get(ctx, secretName, &secret)
 	if err == nil {
+		regenerated, err := r.regenerateCertificateOnAltNamesChange(
+			ctx, cluster, caSecret, &secret, commonName, usage, altDNSNames)
+		if err != nil || regenerated {
+			return err
+		}
 		return r.renewAndUpdateCertificate(ctx, caSecret, &secret)
 	}
 
@@ -318,6 +369,49 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	return r.Create(ctx, serverSecret)
 }
 
+// regenerateCertificateOnAltNamesChange replaces the certificate with a new
+// one when its subject alternative names don't match the expected ones,
+// i.e. when services or DNS names have been added or removed.
+// Returns true if the certificate has been regenerated
+func (r *ClusterReconciler) regenerateCertificateOnAltNamesChange(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+	secret *v1.Secret,
+	commonName string,
+	usage certs.CertType,
+	altDNSNames []string,
+) (bool, error) {
+	pair, err := certs.ParseServerSecret(secret)
+	if err != nil {
+		return false, err
+	}
+
+	matching, err := pair.HasSubjectAltNames(commonName, usage, altDNSNames)
+	if err != nil || matching {
+		return false, err
+	}
+
+	newSecret, err := generateCertificateFromCA(caSecret, commonName, usage, altDNSNames,
+		client.ObjectKeyFromObject(secret))
+	if err != nil {
+		return false, err
+	}
+
+	log.FromContext(ctx).Info("Regenerating the certificate as its alternative names changed",
+		"secret", secret.Name, "altDNSNames", altDNSNames)
+	origSecret := secret.DeepCopy()
+	secret.Data[certs.TLSCertKey] = newSecret.Data[certs.TLSCertKey]
+	secret.Data[certs.TLSPrivateKeyKey] = newSecret.Data[certs.TLSPrivateKeyKey]
+	if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+		return false, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "CertificateRegenerated",
+		"Regenerated the certificate in secret %s as its alternative names changed", secret.Name)
+	return true, nil
+}
+
 // generateCertificateFromCA create a certificate secret using the provided CA secret
 func generateCertificateFromCA(
 	caSecret *v1.Secret,
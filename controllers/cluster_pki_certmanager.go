@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// setupCertManagerPKI delegates the issuance of the server and of the
+// replication certificates to cert-manager. The secrets written by
+// cert-manager are labelled to be watched, and their rotation is propagated
+// to the instances like the one of the user provided certificates
+func (r *ClusterReconciler) setupCertManagerPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !r.Capabilities.Get().HaveCertManager {
+		r.Recorder.Event(cluster, "Warning", "CertManagerNotFound",
+			"The certificates should be issued by cert-manager, but cert-manager is not installed")
+		return fmt.Errorf("the cert-manager.io/v1 Certificate resource is not available")
+	}
+
+	serverAltDNSNames, err := r.getServerAltDNSNames(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileCertManagerCertificate(
+		ctx,
+		specs.CreateCertManagerServerCertificate(*cluster, serverAltDNSNames),
+	); err != nil {
+		return fmt.Errorf("while reconciling the server certificate: %w", err)
+	}
+	if err := r.reconcileCertManagerCertificate(
+		ctx,
+		specs.CreateCertManagerReplicationCertificate(*cluster),
+	); err != nil {
+		return fmt.Errorf("while reconciling the replication certificate: %w", err)
+	}
+
+	if err := r.verifyCertManagerSecret(
+		ctx,
+		cluster,
+		cluster.GetServerTLSSecretName(),
+		x509.ExtKeyUsageServerAuth,
+	); err != nil {
+		return err
+	}
+
+	return r.verifyCertManagerSecret(
+		ctx,
+		cluster,
+		cluster.GetReplicationSecretName(),
+		x509.ExtKeyUsageClientAuth,
+	)
+}
+
+// reconcileCertManagerCertificate creates the passed Certificate, or updates
+// its spec if it changed, such as when a pooler is added
+func (r *ClusterReconciler) reconcileCertManagerCertificate(
+	ctx context.Context,
+	expected *unstructured.Unstructured,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var current unstructured.Unstructured
+	current.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+	err := r.Get(ctx, client.ObjectKeyFromObject(expected), &current)
+	if apierrors.IsNotFound(err) {
+		contextLogger.Info("Creating the cert-manager certificate", "certificate", expected.GetName())
+		return r.Create(ctx, expected)
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(current.Object["spec"], expected.Object["spec"]) {
+		return nil
+	}
+
+	contextLogger.Info("Updating the cert-manager certificate", "certificate", expected.GetName())
+	patch := client.MergeFrom(current.DeepCopy())
+	current.Object["spec"] = expected.Object["spec"]
+	return r.Patch(ctx, &current, patch)
+}
+
+// verifyCertManagerSecret checks that the secret written by cert-manager
+// contains a certificate signed by the included CA, and valid for the
+// passed usage
+func (r *ClusterReconciler) verifyCertManagerSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secretName string,
+	usage x509.ExtKeyUsage,
+) error {
+	var secret v1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("waiting for cert-manager to issue the certificate in the secret %s", secretName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.verifyCAValidity(secret, cluster); err != nil {
+		return fmt.Errorf("while verifying the CA in the secret %s: %w", secretName, err)
+	}
+
+	opts := x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{usage}}
+	if err := validateLeafCertificate(&secret, &secret, &opts); err != nil {
+		return fmt.Errorf("while verifying the certificate in the secret %s: %w", secretName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager PKI", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Certificates: &apiv1.CertificatesConfiguration{
+				CertManager: &apiv1.CertManagerConfiguration{
+					IssuerRef: apiv1.CertManagerIssuerReference{Name: "issuer"},
+				},
+			},
+		},
+	}
+
+	// newIssuedSecret builds a secret as written by a cert-manager CA issuer
+	newIssuedSecret := func(name string, usage certs.CertType) *corev1.Secret {
+		caPair, err := certs.CreateRootCA("issuer", "default")
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := caPair.CreateAndSignPair("cluster-example-rw", usage, nil)
+		Expect(err).ToNot(HaveOccurred())
+		secret := pair.GenerateCertificateSecret("default", name)
+		secret.Data[certs.CACertKey] = caPair.Certificate
+		return secret
+	}
+
+	newReconciler := func(objects ...client.Object) *ClusterReconciler {
+		return &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("creates and updates the certificates", func() {
+		ctx := context.Background()
+		reconciler := newReconciler()
+
+		Expect(reconciler.reconcileCertManagerCertificate(ctx,
+			specs.CreateCertManagerServerCertificate(*cluster, []string{"cluster-example-rw"}))).To(Succeed())
+		Expect(reconciler.reconcileCertManagerCertificate(ctx,
+			specs.CreateCertManagerServerCertificate(*cluster, []string{"cluster-example-rw", "pooler"}))).
+			To(Succeed())
+
+		var certificate unstructured.Unstructured
+		certificate.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-server"},
+			&certificate)).To(Succeed())
+		dnsNames, _, err := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dnsNames).To(Equal([]string{"cluster-example-rw", "pooler"}))
+	})
+
+	It("waits for cert-manager to issue the certificates", func() {
+		reconciler := newReconciler()
+		err := reconciler.verifyCertManagerSecret(context.Background(), cluster, "cluster-example-server",
+			x509.ExtKeyUsageServerAuth)
+		Expect(err).To(MatchError(ContainSubstring("waiting for cert-manager")))
+	})
+
+	It("accepts the certificates signed by the included CA", func() {
+		reconciler := newReconciler(
+			newIssuedSecret("cluster-example-server", certs.CertTypeServer),
+			newIssuedSecret("cluster-example-replication", certs.CertTypeClient),
+		)
+		Expect(reconciler.verifyCertManagerSecret(context.Background(), cluster, "cluster-example-server",
+			x509.ExtKeyUsageServerAuth)).To(Succeed())
+		Expect(reconciler.verifyCertManagerSecret(context.Background(), cluster, "cluster-example-replication",
+			x509.ExtKeyUsageClientAuth)).To(Succeed())
+	})
+})
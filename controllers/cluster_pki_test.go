@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("server certificate alternative names", func() {
+	It("includes the services of the poolers", func() {
+		poolers := []apiv1.Pooler{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pooler-rw", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pooler-ro", Namespace: "default"}},
+		}
+		Expect(buildPoolersAltDNSNames(poolers)).To(Equal([]string{
+			"pooler-ro", "pooler-ro.default", "pooler-ro.default.svc",
+			"pooler-rw", "pooler-rw.default", "pooler-rw.default.svc",
+		}))
+		Expect(buildPoolersAltDNSNames(nil)).To(BeEmpty())
+	})
+
+	It("regenerates the certificate when the alternative names change", func() {
+		ctx := context.Background()
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}}
+		rootCA, err := certs.CreateRootCA("cluster-example", "default")
+		Expect(err).ToNot(HaveOccurred())
+		caSecret := rootCA.GenerateCASecret("default", "cluster-example-ca")
+
+		altDNSNames := cluster.GetClusterAltDNSNames()
+		serverSecret, err := generateCertificateFromCA(caSecret, "cluster-example-rw", certs.CertTypeServer,
+			altDNSNames, client.ObjectKey{Namespace: "default", Name: "cluster-example-server"})
+		Expect(err).ToNot(HaveOccurred())
+
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, serverSecret).
+				Build(),
+			Recorder: recorder,
+		}
+
+		regenerated, err := r.regenerateCertificateOnAltNamesChange(ctx, cluster, caSecret, serverSecret,
+			"cluster-example-rw", certs.CertTypeServer, altDNSNames)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(regenerated).To(BeFalse())
+
+		altDNSNames = append(altDNSNames, "pooler-rw")
+		regenerated, err = r.regenerateCertificateOnAltNamesChange(ctx, cluster, caSecret, serverSecret,
+			"cluster-example-rw", certs.CertTypeServer, altDNSNames)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(regenerated).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		var updatedSecret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(serverSecret), &updatedSecret)).To(Succeed())
+		pair, err := certs.ParseServerSecret(&updatedSecret)
+		Expect(err).ToNot(HaveOccurred())
+		cert, err := pair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cert.VerifyHostname("pooler-rw")).To(Succeed())
+	})
+})
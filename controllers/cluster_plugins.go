@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// aggregatePluginStatus computes the status of the plugins of the cluster
+// from the ones reported by the instances. A plugin is ready when every
+// instance reporting its status finds it ready
+func aggregatePluginStatus(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) []apiv1.PluginStatus {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil
+	}
+
+	result := make([]apiv1.PluginStatus, 0, len(cluster.Spec.Plugins))
+	for _, plugin := range cluster.Spec.Plugins {
+		pluginStatus := apiv1.PluginStatus{Name: plugin.Name, Ready: true}
+		var messages []string
+		reported := 0
+
+		for _, item := range statuses.Items {
+			if item.Error != nil {
+				continue
+			}
+
+			instancePluginStatus, found := findInstancePluginStatus(item.PluginStatus, plugin.Name)
+			if !found {
+				pluginStatus.Ready = false
+				messages = append(messages, fmt.Sprintf("%s: not reported", item.Pod.Name))
+				continue
+			}
+
+			reported++
+			if pluginStatus.Version == "" {
+				pluginStatus.Version = instancePluginStatus.Version
+				pluginStatus.Capabilities = instancePluginStatus.Capabilities
+			}
+			if !instancePluginStatus.Ready {
+				pluginStatus.Ready = false
+				messages = append(messages, fmt.Sprintf("%s: %s", item.Pod.Name, instancePluginStatus.Message))
+			}
+		}
+
+		if reported == 0 {
+			pluginStatus.Ready = false
+		}
+		pluginStatus.Message = strings.Join(messages, "; ")
+		result = append(result, pluginStatus)
+	}
+
+	return result
+}
+
+// findInstancePluginStatus finds the status of a plugin among the ones
+// reported by an instance
+func findInstancePluginStatus(
+	pluginStatuses []postgres.PluginStatus,
+	name string,
+) (postgres.PluginStatus, bool) {
+	for _, pluginStatus := range pluginStatuses {
+		if pluginStatus.Name == name {
+			return pluginStatus, true
+		}
+	}
+	return postgres.PluginStatus{}, false
+}
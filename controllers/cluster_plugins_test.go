@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("plugins status", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Plugins: []apiv1.PluginConfiguration{
+				{Name: "pgbackrest", Image: "pgbackrest:latest"},
+			},
+		},
+	}
+
+	instanceStatus := func(podName string, pluginStatus ...postgres.PluginStatus) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			PluginStatus: pluginStatus,
+		}
+	}
+
+	readyPlugin := postgres.PluginStatus{
+		Name:         "pgbackrest",
+		Version:      "2.50",
+		Capabilities: []string{"BACKUP"},
+		Ready:        true,
+	}
+
+	It("is empty when there are no plugins", func() {
+		statuses := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{instanceStatus("cluster-1")}}
+		Expect(aggregatePluginStatus(&apiv1.Cluster{}, statuses)).To(BeNil())
+	})
+
+	It("is ready when every instance finds the plugin ready", func() {
+		failedStatus := instanceStatus("cluster-3")
+		failedStatus.Error = errors.New("connection refused")
+		statuses := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus("cluster-1", readyPlugin),
+			instanceStatus("cluster-2", readyPlugin),
+			failedStatus,
+		}}
+		Expect(aggregatePluginStatus(cluster, statuses)).To(Equal([]apiv1.PluginStatus{
+			{Name: "pgbackrest", Version: "2.50", Capabilities: []string{"BACKUP"}, Ready: true},
+		}))
+	})
+
+	It("reports the instances where the plugin isn't ready", func() {
+		notReadyPlugin := postgres.PluginStatus{Name: "pgbackrest", Message: "repository not initialized"}
+		statuses := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus("cluster-1", readyPlugin),
+			instanceStatus("cluster-2", notReadyPlugin),
+			instanceStatus("cluster-3"),
+		}}
+		result := aggregatePluginStatus(cluster, statuses)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Ready).To(BeFalse())
+		Expect(result[0].Version).To(Equal("2.50"))
+		Expect(result[0].Message).To(Equal("cluster-2: repository not initialized; cluster-3: not reported"))
+	})
+
+	It("is not ready when no instance reported it", func() {
+		result := aggregatePluginStatus(cluster, postgres.PostgresqlStatusList{})
+		Expect(result).To(Equal([]apiv1.PluginStatus{{Name: "pgbackrest"}}))
+	})
+})
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 )
 
@@ -70,6 +71,25 @@ var (
 		},
 	}
 
+	// The clusters change the volumes of the instances serving a base
+	// backup when it starts and when it ends
+	backupsPredicate = predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldBackup, oldOk := e.ObjectOld.(*apiv1.Backup)
+			newBackup, newOk := e.ObjectNew.(*apiv1.Backup)
+			return oldOk && newOk && oldBackup.Status.Phase != newBackup.Status.Phase
+		},
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return true
+		},
+		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(genericEvent event.GenericEvent) bool {
+			return false
+		},
+	}
+
 	nodesPredicate = predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			oldNode, oldOk := e.ObjectOld.(*corev1.Node)
@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const (
+	// streamingReasonPrimary is the reason of the streaming condition of
+	// the primary instance, or of the designated primary of a replica cluster
+	streamingReasonPrimary = "Primary"
+
+	// streamingReasonStreaming is the reason of the streaming condition
+	// of a replica whose WAL receiver is active
+	streamingReasonStreaming = "Streaming"
+
+	// streamingReasonNotStreaming is the reason of the streaming condition
+	// of a replica whose WAL receiver is not active
+	streamingReasonNotStreaming = "NotStreaming"
+)
+
+// buildStreamingCondition builds the streaming condition of an instance
+// from its status
+func buildStreamingCondition(cluster *apiv1.Cluster, status postgres.PostgresqlStatus) corev1.PodCondition {
+	condition := corev1.PodCondition{
+		Type:   specs.StreamingConditionType,
+		Status: corev1.ConditionFalse,
+		Reason: streamingReasonNotStreaming,
+	}
+
+	switch {
+	case status.IsPrimary || status.Pod.Name == cluster.Status.CurrentPrimary:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = streamingReasonPrimary
+	case status.IsWalReceiverActive:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = streamingReasonStreaming
+	}
+
+	return condition
+}
+
+// reconcileStreamingConditions sets the streaming condition of the instance
+// Pods, used by the readiness gate and by the tools waiting for a replica
+// to catch up. The instances not reporting their status are skipped, as
+// their condition would be unreliable
+func (r *ClusterReconciler) reconcileStreamingConditions(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range instancesStatus.Items {
+		status := &instancesStatus.Items[idx]
+		if status.Error != nil {
+			continue
+		}
+
+		condition := buildStreamingCondition(cluster, *status)
+		pod := status.Pod.DeepCopy()
+		if !setPodCondition(pod, condition) {
+			continue
+		}
+
+		contextLogger.Info("Updating the streaming condition of the instance",
+			"pod", pod.Name, "status", condition.Status, "reason", condition.Reason)
+		if err := r.Status().Patch(ctx, pod, client.StrategicMergeFrom(&status.Pod)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setPodCondition sets the passed condition into the Pod status,
+// returning true when it has changed
+func setPodCondition(pod *corev1.Pod, condition corev1.PodCondition) bool {
+	for idx := range pod.Status.Conditions {
+		existing := &pod.Status.Conditions[idx]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status && existing.Reason == condition.Reason {
+			return false
+		}
+		if existing.Status != condition.Status {
+			existing.LastTransitionTime = metav1.Now()
+		}
+		existing.Status = condition.Status
+		existing.Reason = condition.Reason
+		return true
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	pod.Status.Conditions = append(pod.Status.Conditions, condition)
+	return true
+}
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("streaming condition", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Status:     apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+	}
+
+	newStatus := func(name string, isPrimary, isStreaming bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+			IsPrimary:           isPrimary,
+			IsWalReceiverActive: isStreaming,
+		}
+	}
+
+	It("is true for the primary and the streaming replicas", func() {
+		Expect(buildStreamingCondition(cluster, newStatus("cluster-example-1", true, false)).Reason).
+			To(Equal(streamingReasonPrimary))
+		Expect(buildStreamingCondition(cluster, newStatus("cluster-example-2", false, true)).Status).
+			To(Equal(corev1.ConditionTrue))
+
+		condition := buildStreamingCondition(cluster, newStatus("cluster-example-3", false, false))
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(streamingReasonNotStreaming))
+	})
+
+	It("is true for the designated primary of a replica cluster", func() {
+		Expect(buildStreamingCondition(cluster, newStatus("cluster-example-1", false, false)).Status).
+			To(Equal(corev1.ConditionTrue))
+	})
+
+	It("only changes the transition time when the status changes", func() {
+		pod := &corev1.Pod{}
+		Expect(setPodCondition(pod, buildStreamingCondition(cluster, newStatus("cluster-example-2", false, false)))).
+			To(BeTrue())
+		Expect(setPodCondition(pod, buildStreamingCondition(cluster, newStatus("cluster-example-2", false, false)))).
+			To(BeFalse())
+		Expect(setPodCondition(pod, buildStreamingCondition(cluster, newStatus("cluster-example-2", false, true)))).
+			To(BeTrue())
+		Expect(pod.Status.Conditions).To(HaveLen(1))
+		Expect(pod.Status.Conditions[0].Reason).To(Equal(streamingReasonStreaming))
+	})
+
+	It("is set on the Pods of the instances reporting their status", func() {
+		primary := newStatus("cluster-example-1", true, false)
+		replica := newStatus("cluster-example-2", false, true)
+		failing := newStatus("cluster-example-3", false, false)
+		failing.Error = errors.New("unreachable")
+
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(&primary.Pod, &replica.Pod, &failing.Pod).
+				Build(),
+		}
+		Expect(reconciler.reconcileStreamingConditions(context.TODO(), cluster,
+			postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{primary, replica, failing}})).
+			To(Succeed())
+
+		for name, expectedConditions := range map[string]int{
+			"cluster-example-1": 1,
+			"cluster-example-2": 1,
+			"cluster-example-3": 0,
+		} {
+			var pod corev1.Pod
+			Expect(reconciler.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, &pod)).
+				To(Succeed())
+			Expect(pod.Status.Conditions).To(HaveLen(expectedConditions))
+			if expectedConditions > 0 {
+				Expect(pod.Status.Conditions[0].Type).To(Equal(specs.StreamingConditionType))
+				Expect(pod.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+			}
+		}
+	})
+})
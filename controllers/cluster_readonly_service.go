@@ -0,0 +1,221 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// isDelayedReplica checks if the replay position of a replica is behind the
+// current position of the primary by more than the passed threshold. A
+// replica whose replay position is unknown is considered delayed
+func isDelayedReplica(primaryLSN, replayLSN postgres.LSN, threshold int64) bool {
+	primaryPosition, err := primaryLSN.Parse()
+	if err != nil {
+		return false
+	}
+
+	replayPosition, err := replayLSN.Parse()
+	if err != nil {
+		return true
+	}
+
+	return primaryPosition-replayPosition > threshold
+}
+
+// getReadOnlyServiceMembers returns, for every replica in the passed status
+// list, whether it can be selected by the `-ro` service. The restricted
+// replicas are never selected
+func getReadOnlyServiceMembers(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) map[string]bool {
+	var primaryLSN postgres.LSN
+	for _, status := range instancesStatus.Items {
+		if status.IsPrimary {
+			primaryLSN = status.CurrentLsn
+		}
+	}
+
+	threshold := cluster.GetDelayedReplicaThreshold()
+	members := make(map[string]bool, len(instancesStatus.Items))
+	for _, status := range instancesStatus.Items {
+		if status.IsPrimary || cluster.IsRestrictedReplica(status.Pod.Name) {
+			continue
+		}
+
+		members[status.Pod.Name] = status.IsPodReady &&
+			(!cluster.ShouldExcludeDelayedReplicas() || !isDelayedReplica(primaryLSN, status.ReplayLsn, threshold))
+	}
+
+	return members
+}
+
+// listReadOnlyServiceMembers returns the sorted names of the replicas
+// selected by the `-ro` service, as published in the cluster status
+func listReadOnlyServiceMembers(members map[string]bool) []string {
+	var result []string
+	for podName, isMember := range members {
+		if isMember {
+			result = append(result, podName)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// listReadOnlyServiceMembersByConnections returns the names of the replicas
+// selected by the `-ro` service, sorted by ascending number of client
+// connections. This is the order in which the poolers using the
+// leastConnections load balancing open their server connections
+func listReadOnlyServiceMembersByConnections(
+	members map[string]bool,
+	instancesStatus postgres.PostgresqlStatusList,
+) []string {
+	connections := make(map[string]int, len(instancesStatus.Items))
+	for _, status := range instancesStatus.Items {
+		connections[status.Pod.Name] = status.ClientConnections
+	}
+
+	result := listReadOnlyServiceMembers(members)
+	sort.SliceStable(result, func(i, j int) bool {
+		return connections[result[i]] < connections[result[j]]
+	})
+	return result
+}
+
+// reconcileReadOnlyService applies the routing policy of the `-ro` service,
+// labelling the replicas that can be selected and updating the service
+// selector when the primary needs to be used as a fallback
+func (r *ClusterReconciler) reconcileReadOnlyService(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pods corev1.PodList,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	members := getReadOnlyServiceMembers(cluster, instancesStatus)
+	healthyReplicas := 0
+	for _, isMember := range members {
+		if isMember {
+			healthyReplicas++
+		}
+	}
+
+	if err := r.updateReadOnlyServiceMemberLabels(ctx, cluster, pods, members); err != nil {
+		return err
+	}
+
+	var service corev1.Service
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServiceReadOnlyName()},
+		&service); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	expectedService := specs.CreateClusterReadOnlyService(*cluster)
+	expectedService.Spec.Selector = specs.BuildReadOnlyServiceSelector(*cluster, healthyReplicas == 0)
+	if expectedService.Spec.SessionAffinity == "" {
+		expectedService.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+
+	if reflect.DeepEqual(service.Spec.Selector, expectedService.Spec.Selector) &&
+		service.Spec.SessionAffinity == expectedService.Spec.SessionAffinity {
+		return nil
+	}
+
+	contextLogger.Info("Updating the read-only service selector",
+		"selectorPolicy", cluster.GetReadOnlyServiceSelectorPolicy(),
+		"healthyReplicas", healthyReplicas,
+		"selector", expectedService.Spec.Selector)
+
+	patch := client.MergeFrom(service.DeepCopy())
+	service.Spec.Selector = expectedService.Spec.Selector
+	service.Spec.SessionAffinity = expectedService.Spec.SessionAffinity
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityNone {
+		service.Spec.SessionAffinityConfig = nil
+	}
+
+	return r.Patch(ctx, &service, patch)
+}
+
+// updateReadOnlyServiceMemberLabels sets the label used by the `-ro` service
+// to select the replicas that are not delayed, removing it when the delayed
+// replicas are not excluded
+func (r *ClusterReconciler) updateReadOnlyServiceMemberLabels(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pods corev1.PodList,
+	members map[string]bool,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		currentValue, hasLabel := pod.Labels[utils.ReadOnlyServiceMemberLabelName]
+
+		if !cluster.ShouldExcludeDelayedReplicas() {
+			if !hasLabel {
+				continue
+			}
+			patch := client.MergeFrom(pod.DeepCopy())
+			delete(pod.Labels, utils.ReadOnlyServiceMemberLabelName)
+			if err := r.Patch(ctx, pod, patch); err != nil {
+				return err
+			}
+			continue
+		}
+
+		isMember, isReplica := members[pod.Name]
+		if !isReplica {
+			continue
+		}
+
+		expectedValue := strconv.FormatBool(isMember)
+		if hasLabel && currentValue == expectedValue {
+			continue
+		}
+
+		contextLogger.Info("Updating read-only service membership", "pod", pod.Name, "member", isMember)
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels[utils.ReadOnlyServiceMemberLabelName] = expectedValue
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
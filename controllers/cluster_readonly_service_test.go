@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("read-only service routing", func() {
+	newStatus := func(name string, isPrimary, isReady bool, lsn postgres.LSN) postgres.PostgresqlStatus {
+		status := postgres.PostgresqlStatus{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:  isPrimary,
+			IsPodReady: isReady,
+		}
+		if isPrimary {
+			status.CurrentLsn = lsn
+		} else {
+			status.ReplayLsn = lsn
+		}
+		return status
+	}
+
+	instancesStatus := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true, "0/20000000"),
+			newStatus("cluster-example-2", false, true, "0/1FFFF000"),
+			newStatus("cluster-example-3", false, true, "0/10000000"),
+			newStatus("cluster-example-4", false, false, "0/20000000"),
+		},
+	}
+
+	It("detects the delayed replicas", func() {
+		Expect(isDelayedReplica("0/20000000", "0/1FFFF000", 1024*1024)).To(BeFalse())
+		Expect(isDelayedReplica("0/20000000", "0/10000000", 1024*1024)).To(BeTrue())
+		Expect(isDelayedReplica("0/20000000", "", 1024*1024)).To(BeTrue())
+		Expect(isDelayedReplica("", "0/10000000", 1024*1024)).To(BeFalse())
+	})
+
+	It("selects every ready replica by default", func() {
+		members := getReadOnlyServiceMembers(&apiv1.Cluster{}, instancesStatus)
+		Expect(members).To(Equal(map[string]bool{
+			"cluster-example-2": true,
+			"cluster-example-3": true,
+			"cluster-example-4": false,
+		}))
+	})
+
+	It("lists the selected replicas in a stable order", func() {
+		Expect(listReadOnlyServiceMembers(map[string]bool{
+			"cluster-example-3": true,
+			"cluster-example-2": true,
+			"cluster-example-4": false,
+		})).To(Equal([]string{"cluster-example-2", "cluster-example-3"}))
+		Expect(listReadOnlyServiceMembers(map[string]bool{"cluster-example-4": false})).To(BeNil())
+	})
+
+	It("sorts the selected replicas by number of client connections", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}, ClientConnections: 10},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}, ClientConnections: 3},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}}, ClientConnections: 0},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-5"}}, ClientConnections: 3},
+			},
+		}
+		Expect(listReadOnlyServiceMembersByConnections(map[string]bool{
+			"cluster-example-2": true,
+			"cluster-example-3": true,
+			"cluster-example-4": false,
+			"cluster-example-5": true,
+		}, statuses)).To(Equal([]string{"cluster-example-3", "cluster-example-5", "cluster-example-2"}))
+	})
+
+	It("never selects the restricted replicas", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				RestrictedReplicas: &apiv1.RestrictedReplicasConfiguration{Instances: []int{3}},
+			},
+		}
+		members := getReadOnlyServiceMembers(cluster, instancesStatus)
+		Expect(members).To(Equal(map[string]bool{
+			"cluster-example-2": true,
+			"cluster-example-4": false,
+		}))
+	})
+
+	It("excludes the delayed replicas when requested", func() {
+		threshold := resource.MustParse("1Mi")
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{
+						ReadOnly: &apiv1.ReadOnlyServiceConfiguration{
+							ExcludeDelayedReplicas:  true,
+							DelayedReplicaThreshold: &threshold,
+						},
+					},
+				},
+			},
+		}
+		members := getReadOnlyServiceMembers(cluster, instancesStatus)
+		Expect(members).To(Equal(map[string]bool{
+			"cluster-example-2": true,
+			"cluster-example-3": false,
+			"cluster-example-4": false,
+		}))
+	})
+})
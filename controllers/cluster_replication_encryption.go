@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// getUnencryptedReplicationConnections returns the sorted application names
+// of the streaming replication connections that are known not to be encrypted
+func getUnencryptedReplicationConnections(replicationInfo postgres.PgStatReplicationList) []string {
+	var result []string
+	for _, connection := range replicationInfo {
+		if connection.Encrypted != nil && !*connection.Encrypted {
+			result = append(result, connection.ApplicationName)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// buildReplicationEncryptionCondition creates the ReplicationEncrypted
+// condition given the replication connections reported by the primary
+func buildReplicationEncryptionCondition(replicationInfo postgres.PgStatReplicationList) *metav1.Condition {
+	unencrypted := getUnencryptedReplicationConnections(replicationInfo)
+	if len(unencrypted) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionReplicationEncrypted),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonReplicationEncrypted),
+			Message: "Every streaming replication connection is encrypted",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionReplicationEncrypted),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonReplicationUnencrypted),
+		Message: fmt.Sprintf("Unencrypted streaming replication connections: %s",
+			strings.Join(unencrypted, ", ")),
+	}
+}
+
+// reconcileReplicationEncryption sets the ReplicationEncrypted condition
+// using the streaming replication connections reported by the primary
+// instance, raising an event when an unencrypted connection is detected
+func (r *ClusterReconciler) reconcileReplicationEncryption(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || !status.IsPrimary {
+			continue
+		}
+
+		condition := buildReplicationEncryptionCondition(status.ReplicationInfo)
+		if condition.Status == metav1.ConditionFalse &&
+			!meta.IsStatusConditionFalse(cluster.Status.Conditions, condition.Type) {
+			r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonReplicationUnencrypted),
+				condition.Message)
+		}
+
+		return conditions.Update(ctx, r.Client, cluster, condition)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication encryption", func() {
+	replicationInfo := postgres.PgStatReplicationList{
+		{ApplicationName: "cluster-example-3", Encrypted: pointer.Bool(false)},
+		{ApplicationName: "cluster-example-2", Encrypted: pointer.Bool(true)},
+		{ApplicationName: "cluster-example-4"},
+		{ApplicationName: "cluster-example-5", Encrypted: pointer.Bool(false)},
+	}
+
+	It("ignores the connections not reporting the encryption", func() {
+		Expect(getUnencryptedReplicationConnections(replicationInfo)).To(
+			Equal([]string{"cluster-example-3", "cluster-example-5"}))
+	})
+
+	It("builds the condition", func() {
+		condition := buildReplicationEncryptionCondition(replicationInfo[1:3])
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonReplicationEncrypted)))
+
+		condition = buildReplicationEncryptionCondition(replicationInfo)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonReplicationUnencrypted)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-3, cluster-example-5"))
+	})
+
+	It("sets the condition using the status of the primary", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: recorder,
+		}
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{IsPrimary: false},
+				{IsPrimary: true, ReplicationInfo: replicationInfo},
+			},
+		}
+
+		Expect(r.reconcileReplicationEncryption(context.Background(), cluster, instancesStatus)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionReplicationEncrypted))).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		// The event is raised only when the condition changes
+		Expect(r.reconcileReplicationEncryption(context.Background(), cluster, instancesStatus)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+})
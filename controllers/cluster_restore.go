@@ -20,6 +20,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -60,13 +61,33 @@ func (r *ClusterReconciler) reconcileRestoredCluster(ctx context.Context, cluste
 		primarySerial = highestSerial
 	}
 
+	// The volumes of the instance fenced by the Velero pre-backup hook are
+	// the only ones backed up while PostgreSQL was shut down
+	if fencedSerial := getVeleroFencedSerial(cluster, pvcs); fencedSerial != 0 {
+		contextLogger.Info("electing as primary the instance fenced while being backed up by Velero",
+			"instance", cluster.GetInstanceName(fencedSerial))
+		primarySerial = fencedSerial
+		if pvcs, err = deleteUnfencedPVCs(ctx, r.Client, pvcs, fencedSerial); err != nil {
+			return err
+		}
+	}
+
 	contextLogger.Debug("proceeding to restore the cluster status")
 	if err := restoreClusterStatus(ctx, r.Client, cluster, highestSerial, primarySerial); err != nil {
 		return err
 	}
 
 	contextLogger.Debug("restored the cluster status, proceeding to restore the orphan PVCS")
-	return restoreOrphanPVCs(ctx, r.Client, cluster, pvcs)
+	if err := restoreOrphanPVCs(ctx, r.Client, cluster, pvcs); err != nil {
+		return err
+	}
+
+	contextLogger.Debug("restored the orphan PVCs, proceeding to delete the orphan pods")
+	if err := deleteOrphanPods(ctx, r.Client, cluster); err != nil {
+		return err
+	}
+
+	return liftVeleroFencing(ctx, r.Client, cluster)
 }
 
 // restoreClusterStatus bootstraps the status needed to make the restored cluster work
@@ -79,7 +100,7 @@ func restoreClusterStatus(
 ) error {
 	clusterOrig := cluster.DeepCopy()
 	cluster.Status.LatestGeneratedNode = latestNodeSerial
-	cluster.Status.TargetPrimary = specs.GetInstanceName(cluster.Name, targetPrimaryNodeSerial)
+	cluster.Status.TargetPrimary = cluster.GetInstanceName(targetPrimaryNodeSerial)
 	return c.Status().Patch(ctx, cluster, client.MergeFrom(clusterOrig))
 }
 
@@ -168,3 +189,38 @@ func restoreOrphanPVCs(
 
 	return nil
 }
+
+// deleteOrphanPods deletes the pods belonging to this cluster but not owned
+// by it, i.e. the ones restored by Velero together with the PVCs. The
+// operator recreates them on top of the restored PVCs
+func deleteOrphanPods(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var podList corev1.PodList
+	if err := c.List(
+		ctx,
+		&podList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if len(pod.OwnerReferences) != 0 {
+			continue
+		}
+
+		contextLogger.Info("deleting orphan pod", "podName", pod.Name)
+		if err := c.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
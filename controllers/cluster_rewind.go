@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// buildRewindCondition creates the RewindAvailable condition given the
+// settings reported by the instances. It returns nil when no instance
+// reported whether pg_rewind can be used, as it happens with the older
+// instance managers
+func buildRewindCondition(instancesStatus postgres.PostgresqlStatusList) *metav1.Condition {
+	var reported bool
+	var unsupported []string
+	for _, item := range instancesStatus.Items {
+		if item.Error != nil || item.IsRewindSupported == nil {
+			continue
+		}
+		reported = true
+		if !*item.IsRewindSupported {
+			unsupported = append(unsupported, item.Pod.Name)
+		}
+	}
+	if !reported {
+		return nil
+	}
+	sort.Strings(unsupported)
+
+	if len(unsupported) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionRewindAvailable),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonRewindPrerequisitesMet),
+			Message: "The settings of every instance allow pg_rewind to be used",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionRewindAvailable),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonRewindNotPossible),
+		Message: fmt.Sprintf("Instances having neither data checksums nor wal_log_hints enabled, "+
+			"or having full_page_writes disabled: %s", strings.Join(unsupported, ", ")),
+	}
+}
+
+// reconcileRewindPrerequisites sets the RewindAvailable condition when the
+// former primaries are re-attached using pg_rewind, raising an event when
+// the settings of the instances make it impossible. The condition is
+// removed when the former primaries are cloned again
+func (r *ClusterReconciler) reconcileRewindPrerequisites(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	if cluster.GetReattachStrategy() != apiv1.ReattachStrategyRewind {
+		if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionRewindAvailable)) == nil {
+			return nil
+		}
+
+		existingCluster := cluster.DeepCopy()
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionRewindAvailable))
+		return r.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster))
+	}
+
+	condition := buildRewindCondition(instancesStatus)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionFalse &&
+		!meta.IsStatusConditionFalse(cluster.Status.Conditions, condition.Type) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonRewindNotPossible), condition.Message)
+	}
+
+	return conditions.Update(ctx, r.Client, cluster, condition)
+}
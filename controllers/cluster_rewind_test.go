@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewind prerequisites", func() {
+	newStatus := func(podName string, isRewindSupported *bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:               corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			IsRewindSupported: isRewindSupported,
+		}
+	}
+
+	It("lists the instances where pg_rewind cannot be used", func() {
+		Expect(buildRewindCondition(postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", nil),
+		}})).To(BeNil())
+
+		condition := buildRewindCondition(postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-3", pointer.Bool(false)),
+			newStatus("cluster-example-1", pointer.Bool(true)),
+			newStatus("cluster-example-2", pointer.Bool(false)),
+			newStatus("cluster-example-4", nil),
+		}})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(HaveSuffix(": cluster-example-2, cluster-example-3"))
+	})
+
+	It("sets the condition only when the former primaries are rewound", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: recorder,
+		}
+		unsupported := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", pointer.Bool(false)),
+		}}
+
+		Expect(r.reconcileRewindPrerequisites(context.Background(), cluster, unsupported)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionRewindAvailable))).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		// The event is raised only when the condition changes
+		Expect(r.reconcileRewindPrerequisites(context.Background(), cluster, unsupported)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		cluster.Spec.ReattachStrategy = apiv1.ReattachStrategyReclone
+		Expect(r.Update(context.Background(), cluster)).To(Succeed())
+		Expect(r.reconcileRewindPrerequisites(context.Background(), cluster, unsupported)).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+			string(apiv1.ConditionRewindAvailable))).To(BeNil())
+	})
+})
@@ -52,6 +52,19 @@ func (r *ClusterReconciler) scaleDownCluster(
 		return nil
 	}
 
+	if requiredSyncReplicas := cluster.GetRequiredLocalSyncReplicas(); cluster.Spec.Instances < requiredSyncReplicas+1 {
+		cluster.Spec.Instances = cluster.Status.Instances
+		if err := r.Update(ctx, cluster); err != nil {
+			return err
+		}
+
+		r.Recorder.Eventf(cluster, "Warning", "NoScaleDown",
+			"Can't scale down lower than the required synchronous replicas, going back to %v",
+			cluster.Spec.Instances)
+
+		return nil
+	}
+
 	// Is there one pod to be deleted?
 	sacrificialInstance := getSacrificialInstance(resources.instances.Items)
 	if sacrificialInstance == nil {
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// buildServiceDiscoveryData builds the content of the service discovery
+// ConfigMap from the records published in the cluster status
+func buildServiceDiscoveryData(status *apiv1.ServiceDiscoveryStatus) map[string]string {
+	data := map[string]string{
+		"rw":   status.ReadWrite,
+		"ro":   status.ReadOnly,
+		"r":    status.Read,
+		"port": strconv.Itoa(int(status.Port)),
+	}
+	if status.CurrentPrimary != "" {
+		data["primary"] = status.CurrentPrimary
+	}
+	return data
+}
+
+// reconcileServiceDiscoveryConfigMap publishes the service discovery records
+// of the cluster in its ConfigMap, or removes the ConfigMap when the records
+// are not published anymore
+func (r *ClusterReconciler) reconcileServiceDiscoveryConfigMap(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	var configMap corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServiceDiscoveryConfigMapName()},
+		&configMap)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if found {
+		// we check that we own the existing configmap
+		if _, ok := configMap.Annotations[utils.OperatorVersionAnnotationName]; !ok {
+			contextLogger.Warning("A configmap with the same name as the service discovery configmap "+
+				"already exists, without the required annotation",
+				"configmap", configMap.Name, "annotation", utils.OperatorVersionAnnotationName)
+			return nil
+		}
+	}
+
+	if !cluster.IsServiceDiscoveryConfigMapPublished() {
+		if !found {
+			return nil
+		}
+		contextLogger.Info("Deleting the service discovery configmap", "configmap", configMap.Name)
+		if err := r.Delete(ctx, &configMap); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	data := buildServiceDiscoveryData(cluster.BuildServiceDiscoveryStatus())
+	if !found {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetServiceDiscoveryConfigMapName(),
+				Namespace: cluster.Namespace,
+			},
+			Data: data,
+		}
+		SetClusterOwnerAnnotationsAndLabels(&configMap.ObjectMeta, cluster)
+		contextLogger.Info("Creating the service discovery configmap", "configmap", configMap.Name)
+		return r.Create(ctx, &configMap)
+	}
+
+	if reflect.DeepEqual(configMap.Data, data) {
+		return nil
+	}
+
+	contextLogger.Info("Updating the service discovery configmap", "configmap", configMap.Name)
+	patch := client.MergeFrom(configMap.DeepCopy())
+	configMap.Data = data
+	return r.Patch(ctx, &configMap, patch)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("service discovery configmap", func() {
+	newCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				ServiceDiscovery: &apiv1.ServiceDiscoveryConfiguration{PublishConfigMap: true},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+	}
+
+	getConfigMap := func(ctx context.Context, reconciler *ClusterReconciler) (*corev1.ConfigMap, error) {
+		var configMap corev1.ConfigMap
+		err := reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-discovery"},
+			&configMap)
+		return &configMap, err
+	}
+
+	newReconciler := func(objects ...client.Object) *ClusterReconciler {
+		return &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("publishes and updates the records of the cluster", func() {
+		ctx := context.Background()
+		reconciler := newReconciler()
+		cluster := newCluster()
+
+		Expect(reconciler.reconcileServiceDiscoveryConfigMap(ctx, cluster)).To(Succeed())
+		configMap, err := getConfigMap(ctx, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"rw":      "cluster-example-rw.default.svc",
+			"ro":      "cluster-example-ro.default.svc",
+			"r":       "cluster-example-r.default.svc",
+			"port":    "5432",
+			"primary": "cluster-example-1.cluster-example-any.default.svc",
+		}))
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+
+		By("following a switchover", func() {
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			Expect(reconciler.reconcileServiceDiscoveryConfigMap(ctx, cluster)).To(Succeed())
+			configMap, err := getConfigMap(ctx, reconciler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Data).To(HaveKeyWithValue("primary",
+				"cluster-example-2.cluster-example-any.default.svc"))
+		})
+
+		By("deleting the configmap when the records are not published anymore", func() {
+			cluster.Spec.ServiceDiscovery = nil
+			Expect(reconciler.reconcileServiceDiscoveryConfigMap(ctx, cluster)).To(Succeed())
+			_, err := getConfigMap(ctx, reconciler)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("doesn't touch a configmap not created by the operator", func() {
+		ctx := context.Background()
+		reconciler := newReconciler(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-discovery", Namespace: "default"},
+			Data:       map[string]string{"key": "value"},
+		})
+
+		Expect(reconciler.reconcileServiceDiscoveryConfigMap(ctx, newCluster())).To(Succeed())
+		configMap, err := getConfigMap(ctx, reconciler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Data).To(Equal(map[string]string{"key": "value"}))
+	})
+})
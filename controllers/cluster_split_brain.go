@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// getRunningPrimaries returns the status of the instances that are running
+// as a primary and are not fenced, sorted by name
+func getRunningPrimaries(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) []postgres.PostgresqlStatus {
+	var primaries []postgres.PostgresqlStatus
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || !status.IsPrimary || cluster.IsInstanceFenced(status.Pod.Name) {
+			continue
+		}
+		primaries = append(primaries, status)
+	}
+
+	sort.Slice(primaries, func(i, j int) bool {
+		return primaries[i].Pod.Name < primaries[j].Pod.Name
+	})
+
+	return primaries
+}
+
+// isSplitBrainPaused checks if the reconciliation loop has been paused by
+// a split-brain. When the user acknowledged the split-brain, the pause is
+// lifted and the acknowledgement annotation removed
+func (r *ClusterReconciler) isSplitBrainPaused(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSplitBrain)) {
+		return false, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	if _, acknowledged := cluster.Annotations[utils.SplitBrainAcknowledgedAnnotationName]; !acknowledged {
+		contextLogger.Warning("Reconciliation paused after a split-brain, waiting for the user acknowledgement",
+			"annotation", utils.SplitBrainAcknowledgedAnnotationName)
+		return true, nil
+	}
+
+	contextLogger.Info("Split-brain acknowledged, resuming the reconciliation loop")
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.SplitBrainAcknowledgedAnnotationName)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return true, err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "SplitBrainAcknowledged",
+		"Split-brain acknowledged, resuming the reconciliation loop")
+
+	return false, conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionSplitBrain),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonSplitBrainAcknowledged),
+		Message: "The split-brain has been acknowledged by the user",
+	})
+}
+
+// reconcileSplitBrain detects more than one instance running as a primary.
+// The involved instances are fenced, and the reconciliation loop is paused
+// until the user acknowledges the split-brain. Returns true when a
+// split-brain has been detected
+func (r *ClusterReconciler) reconcileSplitBrain(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (bool, error) {
+	primaries := getRunningPrimaries(cluster, instancesStatus)
+	if len(primaries) < 2 {
+		return false, nil
+	}
+
+	descriptions := make([]string, len(primaries))
+	for idx, status := range primaries {
+		descriptions[idx] = fmt.Sprintf("%s (timeline %d)", status.Pod.Name, status.TimeLineID)
+	}
+	message := fmt.Sprintf("Instances running as primary: %s", strings.Join(descriptions, ", "))
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Warning("Split-brain detected, fencing the primary instances and "+
+		"pausing the reconciliation loop",
+		"primaries", descriptions)
+	r.Recorder.Eventf(cluster, "Warning", "SplitBrain", "Split-brain detected. %s", message)
+
+	// The condition is set first, as it's what keeps the reconciliation
+	// loop paused once the primaries are fenced
+	if err := conditions.Update(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionSplitBrain),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonSplitBrainDetected),
+		Message: message,
+	}); err != nil {
+		return true, err
+	}
+
+	origCluster := cluster.DeepCopy()
+	for _, status := range primaries {
+		if err := utils.AddFencedInstance(status.Pod.Name, &cluster.ObjectMeta); err != nil {
+			return true, err
+		}
+	}
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return true, err
+	}
+
+	return true, r.RegisterPhaseWithReasonCode(ctx, cluster, apiv1.PhaseSplitBrain,
+		apiv1.ConditionReasonSplitBrainDetected, message)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("split-brain detection", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+
+	newStatus := func(name string, isPrimary bool, timeline int) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:  isPrimary,
+			TimeLineID: timeline,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("ignores the primaries that are fenced or not reporting their status", func() {
+		cluster.Annotations = map[string]string{utils.FencedInstanceAnnotation: `["cluster-example-2"]`}
+		failing := newStatus("cluster-example-3", true, 2)
+		failing.Error = errors.New("unreachable")
+		primaries := getRunningPrimaries(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, 1),
+			newStatus("cluster-example-2", true, 2),
+			failing,
+		}})
+		Expect(primaries).To(HaveLen(1))
+		Expect(primaries[0].Pod.Name).To(Equal("cluster-example-1"))
+	})
+
+	It("does nothing when there is only one primary", func() {
+		detected, err := reconciler.reconcileSplitBrain(context.TODO(), cluster,
+			postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true, 1),
+				newStatus("cluster-example-2", false, 1),
+			}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(detected).To(BeFalse())
+	})
+
+	It("fences every primary and pauses the reconciliation until acknowledged", func() {
+		detected, err := reconciler.reconcileSplitBrain(context.TODO(), cluster,
+			postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true, 1),
+				newStatus("cluster-example-2", true, 2),
+			}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(detected).To(BeTrue())
+
+		var updated apiv1.Cluster
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		Expect(updated.IsInstanceFenced("cluster-example-1")).To(BeTrue())
+		Expect(updated.IsInstanceFenced("cluster-example-2")).To(BeTrue())
+		Expect(updated.Status.Phase).To(Equal(apiv1.PhaseSplitBrain))
+		Expect(updated.Status.PhaseReason).To(ContainSubstring("cluster-example-2 (timeline 2)"))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, string(apiv1.ConditionSplitBrain))).To(BeTrue())
+
+		paused, err := reconciler.isSplitBrainPaused(context.TODO(), &updated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paused).To(BeTrue())
+
+		updated.Annotations[utils.SplitBrainAcknowledgedAnnotationName] = "true"
+		Expect(reconciler.Update(context.TODO(), &updated)).To(Succeed())
+		paused, err = reconciler.isSplitBrainPaused(context.TODO(), &updated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paused).To(BeFalse())
+
+		Expect(reconciler.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		Expect(updated.Annotations).ToNot(HaveKey(utils.SplitBrainAcknowledgedAnnotationName))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, string(apiv1.ConditionSplitBrain))).To(BeFalse())
+	})
+})
@@ -96,6 +96,34 @@ func (resources *managedResources) noInstanceIsAlive() bool {
 	return true
 }
 
+// imagePullFailureReasons are the reasons used by the kubelet when the
+// image of a container cannot be pulled
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// getImagePullFailure returns the name of the first instance Pod having a
+// container whose image cannot be pulled, together with the kubelet message.
+// An empty name is returned when every image has been pulled
+func (resources *managedResources) getImagePullFailure() (podName string, message string) {
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		statuses := make([]corev1.ContainerStatus, 0,
+			len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting != nil && imagePullFailureReasons[status.State.Waiting.Reason] {
+				return pod.Name, status.State.Waiting.Message
+			}
+		}
+	}
+	return "", ""
+}
+
 // Retrieve a PVC by name
 func (resources *managedResources) getPVC(name string) *corev1.PersistentVolumeClaim {
 	for _, pvc := range resources.pvcs.Items {
@@ -152,6 +180,12 @@ func (r *ClusterReconciler) getManagedResources(
 }
 
 func (r *ClusterReconciler) getNodes(ctx context.Context) (map[string]corev1.Node, error) {
+	// The operator may not be allowed to read the Nodes when installed
+	// with namespaced RBAC, and the features depending on them are disabled
+	if !r.Capabilities.Get().HaveNodesAccess {
+		return map[string]corev1.Node{}, nil
+	}
+
 	var nodes corev1.NodeList
 	if err := r.List(ctx, &nodes); err != nil {
 		return nil, err
@@ -300,6 +334,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	// Services
 	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
 	cluster.Status.ReadService = cluster.GetServiceReadName()
+	cluster.Status.ServiceDiscovery = cluster.BuildServiceDiscoveryStatus()
 
 	// If we are switching, check if the target primary is still active
 	// Ignore this check if current primary is empty (it happens during the bootstrap)
@@ -737,6 +772,20 @@ func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
 	cluster *apiv1.Cluster,
 	phase string,
 	reason string,
+) error {
+	return r.RegisterPhaseWithReasonCode(ctx, cluster, phase, apiv1.ClusterIsNotReady, reason)
+}
+
+// RegisterPhaseWithReasonCode update phase in the status cluster with the
+// proper reason. The passed machine-readable code is used as the reason of
+// the Ready condition, that is false even when the cluster is healthy,
+// letting automation react to the failure without parsing the
+// human-readable message
+func (r *ClusterReconciler) RegisterPhaseWithReasonCode(ctx context.Context,
+	cluster *apiv1.Cluster,
+	phase string,
+	code apiv1.ConditionReason,
+	reason string,
 ) error {
 	// we ensure that the cluster conditions aren't nil before operating
 	if cluster.Status.Conditions == nil {
@@ -754,7 +803,12 @@ func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
 		Message: "Cluster Is Not Ready",
 	}
 
-	if cluster.Status.Phase == apiv1.PhaseHealthy {
+	if code != apiv1.ClusterIsNotReady {
+		condition.Reason = string(code)
+		condition.Message = reason
+	}
+
+	if cluster.Status.Phase == apiv1.PhaseHealthy && code == apiv1.ClusterIsNotReady {
 		condition = metav1.Condition{
 			Type:    string(apiv1.ConditionClusterReady),
 			Status:  metav1.ConditionTrue,
@@ -774,6 +828,51 @@ func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
 	return nil
 }
 
+// registerHealthyPhase registers the cluster as healthy. The Ready
+// condition is false when the certificate of the operator webhooks is
+// missing, as the changes to the cluster cannot be validated
+func (r *ClusterReconciler) registerHealthyPhase(ctx context.Context, cluster *apiv1.Cluster) error {
+	missing, err := r.isWebhookCertificateMissing(ctx)
+	if err != nil {
+		return err
+	}
+
+	if missing {
+		log.FromContext(ctx).Warning("The certificate of the operator webhooks is missing",
+			"secretName", r.WebhookSecretName, "namespace", configuration.Current.OperatorNamespace)
+		return r.RegisterPhaseWithReasonCode(ctx, cluster, apiv1.PhaseHealthy,
+			apiv1.ConditionReasonWebhookCertMissing,
+			fmt.Sprintf("The secret %s containing the certificate of the operator webhooks is missing",
+				r.WebhookSecretName))
+	}
+
+	return r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, "")
+}
+
+// isWebhookCertificateMissing checks if the secret containing the
+// certificate of the operator webhooks is missing. The certificates
+// provided by OLM are not checked. The secret is read from the cache of
+// the manager, which already holds the secrets watched by the reconciler,
+// as this is done every time a cluster is reconciled
+func (r *ClusterReconciler) isWebhookCertificateMissing(ctx context.Context) (bool, error) {
+	if r.WebhookSecretName == "" ||
+		configuration.Current.OperatorNamespace == "" ||
+		configuration.Current.WebhookCertDir != "" {
+		return false, nil
+	}
+
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: configuration.Current.OperatorNamespace,
+		Name:      r.WebhookSecretName,
+	}, &secret)
+	if apierrs.IsNotFound(err) {
+		return true, nil
+	}
+
+	return false, err
+}
+
 // updateClusterStatusThatRequiresInstancesState updates all the cluster status fields that require the instances status
 func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	ctx context.Context,
@@ -784,13 +883,51 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
-	for _, item := range statuses.Items {
-		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
+	primary := getReportingPrimary(statuses)
+	for idx := range statuses.Items {
+		item := &statuses.Items[idx]
+		reportedState := apiv1.InstanceReportedState{
 			IsPrimary:  item.IsPrimary,
 			TimeLineID: item.TimeLineID,
 		}
+		previousState := existingClusterStatus.InstancesReportedState[apiv1.PodName(item.Pod.Name)]
+
+		// an instance that can't be reached keeps the last state reported
+		reportedState.LongRunningTransactions = previousState.LongRunningTransactions
+		if item.Error == nil {
+			reportedState.LongRunningTransactions = newLongRunningTransactionsState(item.LongRunningTransactions)
+			r.reportLongRunningTransactions(cluster, item.Pod.Name,
+				previousState.LongRunningTransactions, reportedState.LongRunningTransactions)
+		}
+
+		if primary != nil && item.Error == nil && !item.IsPrimary {
+			reportedState.TimelineDivergence = detectTimelineDivergence(primary, item)
+			if reportedState.TimelineDivergence != nil && previousState.TimelineDivergence == nil {
+				log.FromContext(ctx).Warning("Timeline divergence detected",
+					"instance", item.Pod.Name,
+					"remediation", reportedState.TimelineDivergence.Remediation,
+					"message", reportedState.TimelineDivergence.Message)
+				r.Recorder.Eventf(cluster, "Warning", "TimelineDivergence",
+					"Instance %s cannot follow the primary, suggested remediation is %s: %s",
+					item.Pod.Name,
+					reportedState.TimelineDivergence.Remediation,
+					reportedState.TimelineDivergence.Message)
+			}
+		}
+
+		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = reportedState
 	}
 
+	// the poolers balancing their connections across the replicas
+	// follow the members of the `-ro` service
+	readOnlyServiceMembers := getReadOnlyServiceMembers(cluster, statuses)
+	cluster.Status.ReadOnlyServiceMembers = listReadOnlyServiceMembers(readOnlyServiceMembers)
+	cluster.Status.ReadOnlyServiceMembersByConnections = listReadOnlyServiceMembersByConnections(
+		readOnlyServiceMembers, statuses)
+
+	// the plugins are probed by every instance they run in
+	cluster.Status.PluginStatus = aggregatePluginStatus(cluster, statuses)
+
 	// we update any relevant cluster status that depends on the primary instance
 	for _, item := range statuses.Items {
 		// we refresh the last known timeline on the status root.
@@ -875,6 +1012,7 @@ func rawInstanceStatusRequest(
 		return result
 	}
 
+	sentAt := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
 		result.Error = err
@@ -905,6 +1043,10 @@ func rawInstanceStatusRequest(
 		return result
 	}
 
+	if result.Heartbeat != nil {
+		result.ClockSkew = estimateClockSkew(sentAt, time.Now(), *result.Heartbeat)
+	}
+
 	return result
 }
 
@@ -21,11 +21,16 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -162,3 +167,113 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("cluster failure reason codes", func() {
+	var reconciler *ClusterReconciler
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			WebhookSecretName: "cnpg-webhook-cert",
+		}
+	})
+
+	It("uses the generic reason when no code is passed", func() {
+		Expect(reconciler.RegisterPhase(context.TODO(), cluster, v1.PhaseCreatingReplica, "creating")).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(v1.ClusterIsNotReady)))
+	})
+
+	It("uses the passed code as the reason of the Ready condition", func() {
+		Expect(reconciler.RegisterPhaseWithReasonCode(context.TODO(), cluster, v1.PhaseUnrecoverable,
+			v1.ConditionReasonNoActiveInstances, "No pods are active")).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonNoActiveInstances)))
+		Expect(condition.Message).To(Equal("No pods are active"))
+		Expect(cluster.Status.Phase).To(Equal(v1.PhaseUnrecoverable))
+	})
+
+	It("reports the Ready condition when the cluster is healthy", func() {
+		Expect(reconciler.RegisterPhase(context.TODO(), cluster, v1.PhaseHealthy, "")).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ClusterReady)))
+	})
+
+	It("keeps the passed code when the cluster is healthy", func() {
+		Expect(reconciler.RegisterPhaseWithReasonCode(context.TODO(), cluster, v1.PhaseHealthy,
+			v1.ConditionReasonInvalidImage, "Cannot pull the image")).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonInvalidImage)))
+		Expect(condition.Message).To(Equal("Cannot pull the image"))
+		Expect(cluster.Status.Phase).To(Equal(v1.PhaseHealthy))
+	})
+
+	When("the operator manages the certificate of the webhooks", func() {
+		BeforeEach(func() {
+			previousNamespace := configuration.Current.OperatorNamespace
+			configuration.Current.OperatorNamespace = "cnpg-system"
+			DeferCleanup(func() {
+				configuration.Current.OperatorNamespace = previousNamespace
+			})
+		})
+
+		It("reports the missing certificate of the webhooks", func() {
+			Expect(reconciler.registerHealthyPhase(context.TODO(), cluster)).To(Succeed())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.ConditionReasonWebhookCertMissing)))
+			Expect(cluster.Status.Phase).To(Equal(v1.PhaseHealthy))
+		})
+
+		It("reports the cluster as ready when the certificate exists", func() {
+			Expect(reconciler.Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "cnpg-webhook-cert", Namespace: "cnpg-system"},
+			})).To(Succeed())
+			Expect(reconciler.registerHealthyPhase(context.TODO(), cluster)).To(Succeed())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionClusterReady))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	It("detects the instances whose image cannot be pulled", func() {
+		resources := &managedResources{instances: corev1.PodList{Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}},
+			},
+		}}}
+		podName, _ := resources.getImagePullFailure()
+		Expect(podName).To(BeEmpty())
+
+		resources.instances.Items = append(resources.instances.Items, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"},
+			Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image",
+				}}},
+			}},
+		})
+		podName, message := resources.getImagePullFailure()
+		Expect(podName).To(Equal("cluster-example-2"))
+		Expect(message).To(Equal("Back-off pulling image"))
+	})
+})
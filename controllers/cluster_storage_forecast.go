@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// buildStorageCapacityCondition creates the StorageCapacity condition
+// given the storage forecasts reported by the instances. It returns nil
+// when no instance reported them, as it happens with the older instance
+// managers
+func buildStorageCapacityCondition(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) *metav1.Condition {
+	warningDays := cluster.Spec.Monitoring.GetStorageForecastWarningDays()
+
+	var reported bool
+	var fillingUp []string
+	for _, item := range instancesStatus.Items {
+		if item.Error != nil || len(item.StorageForecasts) == 0 {
+			continue
+		}
+		reported = true
+		for _, forecast := range item.StorageForecasts {
+			if forecast.DaysUntilFull == nil || *forecast.DaysUntilFull >= float64(warningDays) {
+				continue
+			}
+			// The days are rounded down to avoid updating the condition
+			// every time the instances report a slightly different forecast
+			fillingUp = append(fillingUp, fmt.Sprintf("%s/%s (%d days)",
+				item.Pod.Name, forecast.Volume, int(math.Floor(*forecast.DaysUntilFull))))
+		}
+	}
+	if !reported {
+		return nil
+	}
+	sort.Strings(fillingUp)
+
+	if len(fillingUp) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionStorageCapacity),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonStorageCapacitySufficient),
+			Message: fmt.Sprintf("No volume is expected to be full within %d days", warningDays),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionStorageCapacity),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonVolumesFillingUp),
+		Message: fmt.Sprintf("Volumes expected to be full within %d days at their current growth rate: %s",
+			warningDays, strings.Join(fillingUp, ", ")),
+	}
+}
+
+// reconcileStorageCapacity sets the StorageCapacity condition from the
+// storage forecasts reported by the instances, raising an event when some
+// volumes are expected to be full soon
+func (r *ClusterReconciler) reconcileStorageCapacity(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	condition := buildStorageCapacityCondition(cluster, instancesStatus)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionFalse &&
+		!meta.IsStatusConditionFalse(cluster.Status.Conditions, condition.Type) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonVolumesFillingUp), condition.Message)
+	}
+
+	return conditions.Update(ctx, r.Client, cluster, condition)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage capacity", func() {
+	newStatus := func(podName string, daysUntilFull ...float64) postgres.PostgresqlStatus {
+		status := postgres.PostgresqlStatus{
+			Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+		}
+		for i := range daysUntilFull {
+			status.StorageForecasts = append(status.StorageForecasts, postgres.StorageForecast{
+				Volume:        []string{"pgdata", "wal"}[i],
+				DaysUntilFull: &daysUntilFull[i],
+			})
+		}
+		return status
+	}
+
+	It("lists the volumes expected to be full soon", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(buildStorageCapacityCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1")},
+		})).To(BeNil())
+
+		condition := buildStorageCapacityCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-2", 30, 2.7),
+				newStatus("cluster-example-1", 6.2, 10),
+				newStatus("cluster-example-3"),
+			},
+		})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(HaveSuffix(
+			": cluster-example-1/pgdata (6 days), cluster-example-2/wal (2 days)"))
+	})
+
+	It("uses the configured number of days", func() {
+		cluster := &apiv1.Cluster{Spec: apiv1.ClusterSpec{Monitoring: &apiv1.MonitoringConfiguration{
+			StorageForecast: &apiv1.StorageForecastConfiguration{WarningDays: 3},
+		}}}
+		condition := buildStorageCapacityCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1", 6.2)},
+		})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("No volume is expected to be full within 3 days"))
+	})
+
+	It("raises an event when the volumes start filling up", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: recorder,
+		}
+		fillingUp := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", 1),
+		}}
+
+		Expect(r.reconcileStorageCapacity(context.Background(), cluster, fillingUp)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionStorageCapacity))).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		// The event is raised only when the condition changes
+		Expect(r.reconcileStorageCapacity(context.Background(), cluster, fillingUp)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+})
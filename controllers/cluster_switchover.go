@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// poolerSwitchoverAction is the action to be applied to a pooler
+// depending on the switchover status of the referenced cluster
+type poolerSwitchoverAction string
+
+const (
+	poolerSwitchoverActionNone   poolerSwitchoverAction = "none"
+	poolerSwitchoverActionPause  poolerSwitchoverAction = "pause"
+	poolerSwitchoverActionResume poolerSwitchoverAction = "resume"
+)
+
+// isSwitchoverInProgress checks if the cluster is changing its primary
+// instance because of a switchover
+func isSwitchoverInProgress(cluster *apiv1.Cluster) bool {
+	return cluster.Status.Phase == apiv1.PhaseSwitchover &&
+		cluster.Status.CurrentPrimary != "" &&
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary &&
+		cluster.Status.TargetPrimary != apiv1.PendingFailoverMarker
+}
+
+// getPoolerSwitchoverAction returns how a pooler should be handled given
+// the switchover status of the cluster it points to
+func getPoolerSwitchoverAction(cluster *apiv1.Cluster, pooler *apiv1.Pooler) poolerSwitchoverAction {
+	if pooler.Spec.PgBouncer == nil {
+		return poolerSwitchoverActionNone
+	}
+
+	_, pausedDuringSwitchover := pooler.Annotations[utils.PausedDuringSwitchoverAnnotationName]
+	switch {
+	case isSwitchoverInProgress(cluster) &&
+		cluster.ShouldPausePoolersDuringSwitchover() &&
+		!pooler.Spec.PgBouncer.IsPaused():
+		return poolerSwitchoverActionPause
+
+	case pausedDuringSwitchover &&
+		cluster.Status.CurrentPrimary == cluster.Status.TargetPrimary:
+		return poolerSwitchoverActionResume
+
+	default:
+		return poolerSwitchoverActionNone
+	}
+}
+
+// reconcilePoolersDuringSwitchover pauses the PgBouncer poolers pointing to
+// the cluster while a switchover is in progress, if requested, and resumes
+// them once the new primary is in place
+func (r *ClusterReconciler) reconcilePoolersDuringSwitchover(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{poolerClusterKey: cluster.Name},
+	); err != nil {
+		return fmt.Errorf("while getting poolers for cluster %s: %w", cluster.Name, err)
+	}
+
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		origPooler := pooler.DeepCopy()
+
+		switch getPoolerSwitchoverAction(cluster, pooler) {
+		case poolerSwitchoverActionPause:
+			contextLogger.Info("Pausing pooler while the switchover is in progress", "pooler", pooler.Name)
+			if pooler.Annotations == nil {
+				pooler.Annotations = make(map[string]string)
+			}
+			pooler.Annotations[utils.PausedDuringSwitchoverAnnotationName] = cluster.Status.TargetPrimary
+			pooler.Spec.PgBouncer.Paused = pointer.Bool(true)
+
+		case poolerSwitchoverActionResume:
+			contextLogger.Info("Resuming pooler after the switchover", "pooler", pooler.Name)
+			delete(pooler.Annotations, utils.PausedDuringSwitchoverAnnotationName)
+			pooler.Spec.PgBouncer.Paused = pointer.Bool(false)
+
+		default:
+			continue
+		}
+
+		if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return fmt.Errorf("while updating pooler %s: %w", pooler.Name, err)
+		}
+	}
+
+	return nil
+}
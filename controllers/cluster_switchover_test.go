@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pooler handling during a switchover", func() {
+	var cluster *apiv1.Cluster
+	var pooler *apiv1.Pooler
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ConnectionDraining: &apiv1.ConnectionDrainingConfiguration{
+					Enabled:      true,
+					PausePoolers: true,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseSwitchover,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-2",
+			},
+		}
+		pooler = &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{},
+			},
+		}
+	})
+
+	It("pauses the poolers while the switchover is in progress", func() {
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionPause))
+	})
+
+	It("doesn't pause the poolers when not requested", func() {
+		cluster.Spec.ConnectionDraining.PausePoolers = false
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionNone))
+	})
+
+	It("doesn't pause the poolers during a failover", func() {
+		cluster.Status.Phase = apiv1.PhaseFailOver
+		cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionNone))
+	})
+
+	It("doesn't touch the poolers which are already paused", func() {
+		pooler.Spec.PgBouncer.Paused = pointer.Bool(true)
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionNone))
+	})
+
+	It("resumes the poolers paused by the operator once the switchover is completed", func() {
+		pooler.ObjectMeta = metav1.ObjectMeta{
+			Annotations: map[string]string{
+				utils.PausedDuringSwitchoverAnnotationName: "cluster-example-2",
+			},
+		}
+		pooler.Spec.PgBouncer.Paused = pointer.Bool(true)
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionResume))
+	})
+
+	It("doesn't resume the poolers paused by the user", func() {
+		pooler.Spec.PgBouncer.Paused = pointer.Bool(true)
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		Expect(getPoolerSwitchoverAction(cluster, pooler)).To(Equal(poolerSwitchoverActionNone))
+	})
+})
@@ -0,0 +1,100 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// getReportingPrimary returns the status of the first instance that
+// reported to be a primary, if any
+func getReportingPrimary(statuses postgres.PostgresqlStatusList) *postgres.PostgresqlStatus {
+	for idx := range statuses.Items {
+		if statuses.Items[idx].Error == nil && statuses.Items[idx].IsPrimary {
+			return &statuses.Items[idx]
+		}
+	}
+
+	return nil
+}
+
+// detectTimelineDivergence checks if the passed replica cannot follow the
+// primary because their histories diverged. A replica that is on a
+// timeline abandoned by the primary can follow it only if it didn't go
+// past the switch point. When the timeline of the replica is not part of the
+// history of the primary, the divergence point can't be determined and
+// the replica needs to be cloned again
+func detectTimelineDivergence(
+	primary *postgres.PostgresqlStatus,
+	replica *postgres.PostgresqlStatus,
+) *apiv1.TimelineDivergence {
+	if primary.TimeLineID == 0 || replica.TimeLineID == 0 {
+		return nil
+	}
+
+	replicaLSN := replica.ReceivedLsn
+	if replicaLSN.Less(replica.ReplayLsn) {
+		replicaLSN = replica.ReplayLsn
+	}
+
+	divergence := &apiv1.TimelineDivergence{
+		InstanceTimeline: replica.TimeLineID,
+		PrimaryTimeline:  primary.TimeLineID,
+		InstanceLSN:      string(replicaLSN),
+	}
+
+	if primary.SystemID != "" && replica.SystemID != "" && primary.SystemID != replica.SystemID {
+		divergence.Remediation = apiv1.TimelineDivergenceRemediationReclone
+		divergence.Message = fmt.Sprintf("the system identifier of the replica (%s) "+
+			"is different from the one of the primary (%s)", replica.SystemID, primary.SystemID)
+		return divergence
+	}
+
+	if replica.TimeLineID == primary.TimeLineID {
+		return nil
+	}
+
+	for _, entry := range primary.TimelineHistory {
+		if entry.TimelineID != replica.TimeLineID {
+			continue
+		}
+
+		// We can't tell the position reached by the replica, so we wait
+		// for it to be reported before raising a divergence
+		if _, err := replicaLSN.Parse(); err != nil {
+			return nil
+		}
+
+		if !entry.SwitchPoint.Less(replicaLSN) {
+			return nil
+		}
+
+		divergence.DivergencePoint = string(entry.SwitchPoint)
+		divergence.Remediation = apiv1.TimelineDivergenceRemediationRewind
+		divergence.Message = fmt.Sprintf("the primary switched from timeline %d to a new one at %s, "+
+			"while the replica went on up to %s", entry.TimelineID, entry.SwitchPoint, replicaLSN)
+		return divergence
+	}
+
+	divergence.Remediation = apiv1.TimelineDivergenceRemediationReclone
+	divergence.Message = fmt.Sprintf("the timeline %d of the replica is not part of the history "+
+		"of the primary timeline %d", replica.TimeLineID, primary.TimeLineID)
+	return divergence
+}
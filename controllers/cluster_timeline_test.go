@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("timeline divergence", func() {
+	primary := &postgres.PostgresqlStatus{
+		SystemID:   "7166091376653282666",
+		IsPrimary:  true,
+		TimeLineID: 3,
+		TimelineHistory: []postgres.TimelineHistoryEntry{
+			{TimelineID: 1, SwitchPoint: "0/3000060"},
+			{TimelineID: 2, SwitchPoint: "0/5000148"},
+		},
+	}
+
+	It("doesn't report replicas on the timeline of the primary", func() {
+		replica := &postgres.PostgresqlStatus{SystemID: primary.SystemID, TimeLineID: 3, ReplayLsn: "0/6000000"}
+		Expect(detectTimelineDivergence(primary, replica)).To(BeNil())
+	})
+
+	It("doesn't report replicas that can still follow the timeline switch", func() {
+		replica := &postgres.PostgresqlStatus{SystemID: primary.SystemID, TimeLineID: 2, ReplayLsn: "0/5000100"}
+		Expect(detectTimelineDivergence(primary, replica)).To(BeNil())
+	})
+
+	It("suggests a rewind for replicas that went past the switch point", func() {
+		replica := &postgres.PostgresqlStatus{
+			SystemID:    primary.SystemID,
+			TimeLineID:  2,
+			ReceivedLsn: "0/5000200",
+			ReplayLsn:   "0/5000180",
+		}
+		divergence := detectTimelineDivergence(primary, replica)
+		Expect(divergence).ToNot(BeNil())
+		Expect(divergence.Remediation).To(Equal(apiv1.TimelineDivergenceRemediationRewind))
+		Expect(divergence.DivergencePoint).To(Equal("0/5000148"))
+		Expect(divergence.InstanceLSN).To(Equal("0/5000200"))
+		Expect(divergence.InstanceTimeline).To(Equal(2))
+		Expect(divergence.PrimaryTimeline).To(Equal(3))
+	})
+
+	It("suggests a re-clone for replicas on a timeline unknown to the primary", func() {
+		replica := &postgres.PostgresqlStatus{SystemID: primary.SystemID, TimeLineID: 4, ReplayLsn: "0/5000200"}
+		divergence := detectTimelineDivergence(primary, replica)
+		Expect(divergence).ToNot(BeNil())
+		Expect(divergence.Remediation).To(Equal(apiv1.TimelineDivergenceRemediationReclone))
+		Expect(divergence.DivergencePoint).To(BeEmpty())
+	})
+
+	It("suggests a re-clone for replicas of a different system", func() {
+		replica := &postgres.PostgresqlStatus{SystemID: "7166091376653282667", TimeLineID: 3}
+		divergence := detectTimelineDivergence(primary, replica)
+		Expect(divergence).ToNot(BeNil())
+		Expect(divergence.Remediation).To(Equal(apiv1.TimelineDivergenceRemediationReclone))
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("long-running transactions state", func() {
+	It("is empty without long-running transactions or locks", func() {
+		Expect(newLongRunningTransactionsState(nil)).To(BeNil())
+		Expect(newLongRunningTransactionsState(&postgres.LongRunningTransactions{OldestTransactionAge: 3})).
+			To(BeNil())
+	})
+
+	It("stores the start time of the oldest transaction with the precision of the status", func() {
+		start := time.Date(2026, 10, 15, 10, 30, 12, 345678, time.UTC)
+		state := newLongRunningTransactionsState(&postgres.LongRunningTransactions{
+			Transactions:           1,
+			PreparedTransactions:   2,
+			BlockedSessions:        3,
+			OldestTransactionStart: &start,
+		})
+		Expect(state.Transactions).To(Equal(3))
+		Expect(state.BlockedSessions).To(Equal(3))
+		Expect(state.OldestTransactionStart.Time.Equal(start.Truncate(time.Second))).To(BeTrue())
+
+		// the state decoded from the status of the cluster is the same
+		content, err := state.OldestTransactionStart.MarshalJSON()
+		Expect(err).ToNot(HaveOccurred())
+		var decoded metav1.Time
+		Expect(decoded.UnmarshalJSON(content)).To(Succeed())
+		Expect(decoded).To(Equal(*state.OldestTransactionStart))
+	})
+})
+
+var _ = Describe("long-running transactions events", func() {
+	var recorder *record.FakeRecorder
+	var reconciler *ClusterReconciler
+
+	state := &apiv1.LongRunningTransactionsState{Transactions: 1}
+	cluster := &apiv1.Cluster{Spec: apiv1.ClusterSpec{Monitoring: &apiv1.MonitoringConfiguration{
+		LongRunningTransactions: &apiv1.LongRunningTransactionsConfiguration{EmitEvents: true},
+	}}}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ClusterReconciler{Recorder: recorder}
+	})
+
+	It("doesn't raise events unless requested", func() {
+		reconciler.reportLongRunningTransactions(&apiv1.Cluster{}, "cluster-example-1", nil, state)
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("raises an event when an instance starts reporting long-running transactions", func() {
+		reconciler.reportLongRunningTransactions(cluster, "cluster-example-1", nil, state)
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal("Warning LongRunningTransactions Instance cluster-example-1 has " +
+			"long-running transactions, prepared transactions or sessions blocked by locks"))
+	})
+
+	It("doesn't raise events while the long-running transactions are reported", func() {
+		reconciler.reportLongRunningTransactions(cluster, "cluster-example-1", state,
+			&apiv1.LongRunningTransactionsState{Transactions: 2, BlockedSessions: 1})
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("raises an event when an instance stops reporting long-running transactions", func() {
+		reconciler.reportLongRunningTransactions(cluster, "cluster-example-1", state, nil)
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal("Normal LongRunningTransactionsResolved Instance cluster-example-1 " +
+			"has no more long-running transactions, prepared transactions or sessions blocked by locks"))
+	})
+})
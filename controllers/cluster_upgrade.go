@@ -150,17 +150,13 @@ func (r *ClusterReconciler) updatePrimaryPod(
 
 	// if the cluster has more than one instance, we should trigger a switchover before upgrading
 	if cluster.Status.Instances > 1 && len(podList.Items) > 1 {
-		// If this is not a replica cluster, podList.Items[1] is the first replica,
-		// as the pod list is sorted in the same order we use for switchover / failover.
-		// This may not be true for replica clusters, where every instance is a replica
-		// from the PostgreSQL point-of-view.
-		targetPrimary := podList.Items[1].Pod.Name
-
-		// If this is a replica cluster, the target primary we chose may be
-		// the one we're trying to upgrade, as the list isn't sorted. In
-		// this case, we promote the first instance of the list
-		if targetPrimary == primaryPod.Name {
-			targetPrimary = podList.Items[0].Pod.Name
+		targetPrimary := getSwitchoverTarget(cluster, podList, primaryPod.Name)
+		if targetPrimary == "" {
+			contextLogger.Info("The primary needs to be restarted, but there are no instances that can be promoted",
+				"reason", reason,
+				"currentPrimary", primaryPod.Name,
+				"podList", podList)
+			return true, nil
 		}
 
 		contextLogger.Info("The primary needs to be restarted, we'll trigger a switchover to do that",
@@ -184,6 +180,29 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod)
 }
 
+// getSwitchoverTarget gets the instance to be promoted in place of the passed
+// primary, skipping the restricted replicas. An empty string is returned when
+// no instance can be promoted
+func getSwitchoverTarget(cluster *apiv1.Cluster, podList *postgres.PostgresqlStatusList, primaryName string) string {
+	// If this is not a replica cluster, podList.Items[1] is the first replica,
+	// as the pod list is sorted in the same order we use for switchover / failover.
+	// This may not be true for replica clusters, where every instance is a replica
+	// from the PostgreSQL point-of-view, and the target primary we chose may be
+	// the one we're trying to upgrade, as the list isn't sorted. In this case,
+	// we promote the first instance of the list
+	candidates := make([]postgres.PostgresqlStatus, 0, len(podList.Items))
+	candidates = append(candidates, podList.Items[1], podList.Items[0])
+	candidates = append(candidates, podList.Items[2:]...)
+
+	for _, candidate := range candidates {
+		if candidate.Pod.Name != primaryName && !cluster.IsRestrictedReplica(candidate.Pod.Name) {
+			return candidate.Pod.Name
+		}
+	}
+
+	return ""
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -270,7 +289,26 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		}
 	}
 
+	// check if the pod has been created with a different extensions configuration
+	if extensionsHash, err := specs.GetExtensionsHash(*cluster); err == nil &&
+		status.Pod.Annotations[specs.ExtensionsHashAnnotationName] != extensionsHash {
+		return true, false, "the extensions configuration changed"
+	}
+
+	// check if the pod has been created with a different plugins configuration
+	if pluginsHash, err := specs.GetPluginsHash(*cluster); err == nil &&
+		status.Pod.Annotations[specs.PluginsHashAnnotationName] != pluginsHash {
+		return true, false, "the plugins configuration changed"
+	}
+
+	// check if the scripts of the instance hooks are taken from a different ConfigMap
+	if oldConfigMap, newConfigMap := getInstanceHooksConfigMaps(cluster, status.Pod); oldConfigMap != newConfigMap {
+		return true, false, fmt.Sprintf("the instance hooks ConfigMap changed: %q -> %q",
+			oldConfigMap, newConfigMap)
+	}
+
 	// Detect changes in the postgres container configuration
+	resources := getInstanceResources(cluster, status.Pod)
 	for _, container := range status.Pod.Spec.Containers {
 		// we go to the next array element if it isn't the postgres container
 		if container.Name != specs.PostgresContainerName {
@@ -278,9 +316,9 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		}
 
 		// Check if there is a change in the resource requirements
-		if !utils.IsResourceSubset(container.Resources, cluster.Spec.Resources) {
+		if !utils.IsResourceSubset(container.Resources, resources) {
 			return true, false, fmt.Sprintf("resources changed, old: %+v, new: %+v",
-				cluster.Spec.Resources,
+				resources,
 				container.Resources)
 		}
 	}
@@ -290,6 +328,36 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		true, "configuration needs a restart to apply some configuration changes"
 }
 
+// getInstanceResources returns the resources requirements expected for the
+// passed Pod, using the role it had when it was created to select the
+// instance overrides. This prevents a switchover from requiring a rollout
+func getInstanceResources(cluster *apiv1.Cluster, pod v1.Pod) v1.ResourceRequirements {
+	nodeSerial, err := specs.GetNodeSerial(pod.ObjectMeta)
+	if err != nil {
+		return cluster.Spec.Resources
+	}
+
+	overrideRole := apiv1.InstanceOverrideRole(pod.Annotations[specs.InstanceOverrideRoleAnnotationName])
+	return cluster.GetInstanceResources(nodeSerial, overrideRole)
+}
+
+// getInstanceHooksConfigMaps returns the name of the ConfigMap
+// containing the scripts of the instance hooks mounted in the Pod and the
+// one required by the cluster, empty when there is none
+func getInstanceHooksConfigMaps(cluster *apiv1.Cluster, pod v1.Pod) (oldConfigMap, newConfigMap string) {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == specs.InstanceHooksVolumeName && volume.ConfigMap != nil {
+			oldConfigMap = volume.ConfigMap.Name
+		}
+	}
+
+	if hooks := cluster.Spec.InstanceHooks; hooks != nil && hooks.ScriptsConfigMap != nil {
+		newConfigMap = hooks.ScriptsConfigMap.Name
+	}
+
+	return oldConfigMap, newConfigMap
+}
+
 // isPodNeedingUpgradedImage checks whether an image in a pod has to be changed
 func isPodNeedingUpgradedImage(
 	cluster *apiv1.Cluster,
@@ -328,9 +396,9 @@ func isPodNeedingUpgradedInitContainerImage(
 		return "", "", err
 	}
 
-	if opCurrentImageName != configuration.Current.OperatorImageName {
+	if opCurrentImageName != configuration.Current.GetOperatorImageName() {
 		// We need to apply a different version of the instance manager
-		return opCurrentImageName, configuration.Current.OperatorImageName, nil
+		return opCurrentImageName, configuration.Current.GetOperatorImageName(), nil
 	}
 
 	return "", "", nil
@@ -17,6 +17,9 @@ limitations under the License.
 package controllers
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -90,4 +93,68 @@ var _ = Describe("Pod upgrade", func() {
 		Expect(inplacePossible).To(BeTrue())
 		Expect(reason).To(BeEquivalentTo("configuration needs a restart to apply some configuration changes"))
 	})
+
+	It("requires a rollout when the instance hooks ConfigMap changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		clusterWithHooks := cluster.DeepCopy()
+		clusterWithHooks.Spec.InstanceHooks = &apiv1.InstanceHooksConfiguration{
+			ScriptsConfigMap: &apiv1.LocalObjectReference{Name: "hooks"},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, clusterWithHooks)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("instance hooks ConfigMap"))
+
+		status.Pod = *specs.PodWithExistingStorage(*clusterWithHooks, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, clusterWithHooks)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the plugins change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		clusterWithPlugins := cluster.DeepCopy()
+		clusterWithPlugins.Spec.Plugins = []apiv1.PluginConfiguration{
+			{Name: "pgbackrest", Image: "pgbackrest:latest"},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, clusterWithPlugins)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the plugins configuration changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*clusterWithPlugins, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, clusterWithPlugins)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("uses the role of the instance at the Pod creation to select the resources overrides", func() {
+		clusterWithOverrides := cluster.DeepCopy()
+		clusterWithOverrides.Status.TargetPrimary = clusterWithOverrides.GetInstanceName(1)
+		clusterWithOverrides.Spec.InstanceOverrides = []apiv1.InstanceOverride{
+			{
+				Role: apiv1.InstanceOverrideRoleReplica,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+		}
+
+		pod := specs.PodWithExistingStorage(*clusterWithOverrides, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, clusterWithOverrides)
+		Expect(needRollout).To(BeFalse())
+
+		// A switchover doesn't require the Pod to be recreated
+		clusterWithOverrides.Status.TargetPrimary = clusterWithOverrides.GetInstanceName(2)
+		needRollout, _, _ = IsPodNeedingRollout(status, clusterWithOverrides)
+		Expect(needRollout).To(BeFalse())
+
+		clusterWithOverrides.Spec.InstanceOverrides[0].Role = apiv1.InstanceOverrideRolePrimary
+		needRollout, _, reason := IsPodNeedingRollout(status, clusterWithOverrides)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(ContainSubstring("resources changed"))
+	})
 })
@@ -0,0 +1,153 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// getVeleroFencedSerial gets the serial of the instance that was fenced
+// by the Velero pre-backup hook when the cluster has been backed up,
+// returning zero when no restored PVC belongs to it
+func getVeleroFencedSerial(cluster *apiv1.Cluster, pvcs []corev1.PersistentVolumeClaim) int {
+	fencedInstance, ok := cluster.Annotations[utils.VeleroFencedInstanceAnnotationName]
+	if !ok {
+		return 0
+	}
+
+	for _, pvc := range pvcs {
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err == nil && cluster.GetInstanceName(serial) == fencedInstance {
+			return serial
+		}
+	}
+
+	return 0
+}
+
+// deleteUnfencedPVCs deletes the PVCs of the instances that were running
+// while being backed up by Velero. Their volumes are not consistent, as
+// the volumes of an instance are not backed up atomically, and these
+// instances are recreated cloning the fenced one. The PVCs of the fenced
+// instance are returned
+func deleteUnfencedPVCs(
+	ctx context.Context,
+	c client.Client,
+	pvcs []corev1.PersistentVolumeClaim,
+	fencedSerial int,
+) ([]corev1.PersistentVolumeClaim, error) {
+	contextLogger := log.FromContext(ctx)
+
+	fencedPVCs := make([]corev1.PersistentVolumeClaim, 0, len(pvcs))
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err != nil {
+			return nil, err
+		}
+		if serial == fencedSerial {
+			fencedPVCs = append(fencedPVCs, *pvc)
+			continue
+		}
+
+		contextLogger.Info("deleting the pvc of an instance backed up by Velero while running",
+			"pvcName", pvc.Name)
+		if err := c.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return fencedPVCs, nil
+}
+
+// liftVeleroFencing unfences the instance fenced by the Velero pre-backup
+// hook, as the cluster could have been backed up while the fence was set
+func liftVeleroFencing(ctx context.Context, c client.Client, cluster *apiv1.Cluster) error {
+	fencedInstance, ok := cluster.Annotations[utils.VeleroFencedInstanceAnnotationName]
+	if !ok {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("lifting the fence set by the Velero pre-backup hook",
+		"instance", fencedInstance)
+
+	clusterOrig := cluster.DeepCopy()
+	err := utils.RemoveFencedInstance(fencedInstance, &cluster.ObjectMeta)
+	if err != nil && !errors.Is(err, utils.ErrorServerAlreadyUnfenced) {
+		return err
+	}
+	delete(cluster.Annotations, utils.VeleroFencedInstanceAnnotationName)
+	delete(cluster.Annotations, utils.VeleroFenceExpirationAnnotationName)
+	return c.Patch(ctx, cluster, client.MergeFrom(clusterOrig))
+}
+
+// getVeleroFenceExpiration returns the time after which the fence set by
+// the Velero pre-backup hook is lifted, or nil when no fence is set
+func getVeleroFenceExpiration(cluster *apiv1.Cluster) (*time.Time, error) {
+	value, ok := cluster.Annotations[utils.VeleroFenceExpirationAnnotationName]
+	if !ok {
+		return nil, nil
+	}
+
+	expiration, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &expiration, nil
+}
+
+// liftExpiredVeleroFencing lifts the fence set by the Velero pre-backup
+// hook when it expires, as the post-backup hook is not run when the
+// backup fails
+func (r *ClusterReconciler) liftExpiredVeleroFencing(ctx context.Context, cluster *apiv1.Cluster) error {
+	expiration, err := getVeleroFenceExpiration(cluster)
+	if err != nil {
+		log.FromContext(ctx).Warning("Lifting the Velero fence having an invalid expiration time",
+			"annotation", utils.VeleroFenceExpirationAnnotationName,
+			"error", err)
+	} else if expiration == nil || time.Now().Before(*expiration) {
+		return nil
+	}
+
+	r.Recorder.Eventf(cluster, "Warning", "VeleroFenceExpired",
+		"Lifting the fence of instance %s, as the Velero post-backup hook has not been run in time",
+		cluster.Annotations[utils.VeleroFencedInstanceAnnotationName])
+	return liftVeleroFencing(ctx, r.Client, cluster)
+}
+
+// requeueBeforeVeleroFenceExpiration ensures the cluster is reconciled
+// again when the fence set by the Velero pre-backup hook expires
+func requeueBeforeVeleroFenceExpiration(cluster *apiv1.Cluster, result ctrl.Result) ctrl.Result {
+	expiration, err := getVeleroFenceExpiration(cluster)
+	if err != nil || expiration == nil {
+		return result
+	}
+
+	return requeueBefore(*expiration, result)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Velero integration", func() {
+	newCluster := func(expiration time.Time) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation:            `["cluster-example-2"]`,
+					utils.VeleroFencedInstanceAnnotationName:  "cluster-example-2",
+					utils.VeleroFenceExpirationAnnotationName: expiration.Format(time.RFC3339),
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{Velero: &apiv1.VeleroConfiguration{FenceReplicas: true}},
+			},
+		}
+	}
+
+	newPVC := func(serial int, role string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("cluster-example-%d", serial),
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ClusterLabelName:     "cluster-example",
+					specs.ClusterRoleLabelName: role,
+				},
+				Annotations: map[string]string{
+					specs.ClusterSerialAnnotationName: strconv.Itoa(serial),
+				},
+			},
+		}
+	}
+
+	newReconciler := func(objects ...client.Object) *ClusterReconciler {
+		return &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("lifts the fence when it expires", func() {
+		ctx := context.Background()
+		cluster := newCluster(time.Now().Add(-time.Minute))
+		reconciler := newReconciler(cluster)
+
+		Expect(reconciler.liftExpiredVeleroFencing(ctx, cluster)).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.IsInstanceFenced("cluster-example-2")).To(BeFalse())
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.VeleroFencedInstanceAnnotationName))
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.VeleroFenceExpirationAnnotationName))
+	})
+
+	It("keeps the fence until it expires, requeueing the reconciliation", func() {
+		ctx := context.Background()
+		cluster := newCluster(time.Now().Add(time.Hour))
+		reconciler := newReconciler(cluster)
+
+		Expect(reconciler.liftExpiredVeleroFencing(ctx, cluster)).To(Succeed())
+		Expect(cluster.IsInstanceFenced("cluster-example-2")).To(BeTrue())
+
+		result := requeueBeforeVeleroFenceExpiration(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	It("restores the cluster from the instance fenced while being backed up", func() {
+		ctx := context.Background()
+		cluster := newCluster(time.Now().Add(time.Hour))
+		primaryPVC := newPVC(1, specs.ClusterRoleLabelPrimary)
+		fencedPVC := newPVC(2, specs.ClusterRoleLabelReplica)
+		reconciler := newReconciler(cluster, primaryPVC, fencedPVC)
+
+		Expect(reconciler.reconcileRestoredCluster(ctx, cluster)).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+		Expect(updatedCluster.Status.LatestGeneratedNode).To(Equal(2))
+		Expect(updatedCluster.IsInstanceFenced("cluster-example-2")).To(BeFalse())
+
+		var pvcs corev1.PersistentVolumeClaimList
+		Expect(reconciler.List(ctx, &pvcs)).To(Succeed())
+		Expect(pvcs.Items).To(HaveLen(1))
+		Expect(pvcs.Items[0].Name).To(Equal(fencedPVC.Name))
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/witness"
+)
+
+// ErrFailoverBlockedByWitness is raised when a new primary server can't be
+// elected because the current one is still renewing the failover witness lease
+var ErrFailoverBlockedByWitness = fmt.Errorf("the failover witness lease is still held by the current primary")
+
+// isFailoverAllowedByWitness checks if the failover witness allows the
+// promotion of a replica. The failover is blocked while the current primary
+// keeps renewing its lease, and when the witness cannot be reached, as the
+// operator cannot tell whether the primary is still running
+func (r *ClusterReconciler) isFailoverAllowedByWitness(ctx context.Context, cluster *apiv1.Cluster) bool {
+	if cluster.Spec.FailoverWitness == nil {
+		return true
+	}
+
+	contextLogger := log.FromContext(ctx)
+	leaseKey := witness.GetLeaseKey(cluster)
+
+	witnessClient, err := r.witnessClientProvider.GetClient(ctx, r.Client, cluster)
+	if err != nil {
+		contextLogger.Warning("Cannot reach the failover witness, blocking the failover",
+			"lease", leaseKey, "error", err)
+		return false
+	}
+
+	isPrimaryAlive, err := witness.IsPrimaryAlive(ctx, witnessClient, leaseKey,
+		cluster.Status.CurrentPrimary, time.Now())
+	if err != nil {
+		contextLogger.Warning("Cannot read the failover witness lease, blocking the failover",
+			"lease", leaseKey, "error", err)
+		return false
+	}
+
+	return !isPrimaryAlive
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/witness"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover witness", func() {
+	var reconciler *ClusterReconciler
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				FailoverWitness: &apiv1.FailoverWitnessConfiguration{LeaseName: "witness", LeaseDuration: 30},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build(),
+		}
+	})
+
+	It("allows the failover when no witness is configured", func() {
+		cluster.Spec.FailoverWitness = nil
+		Expect(reconciler.isFailoverAllowedByWitness(context.TODO(), cluster)).To(BeTrue())
+	})
+
+	It("allows the failover when the primary never reached the witness", func() {
+		Expect(reconciler.isFailoverAllowedByWitness(context.TODO(), cluster)).To(BeTrue())
+	})
+
+	It("blocks the failover while the current primary holds the lease", func() {
+		Expect(witness.Renew(context.TODO(), reconciler.Client, witness.GetLeaseKey(cluster),
+			"cluster-example-1", 30*time.Second, time.Now())).To(Succeed())
+		Expect(reconciler.isFailoverAllowedByWitness(context.TODO(), cluster)).To(BeFalse())
+	})
+
+	It("allows the failover when the lease of the current primary expired", func() {
+		Expect(witness.Renew(context.TODO(), reconciler.Client, witness.GetLeaseKey(cluster),
+			"cluster-example-1", 30*time.Second, time.Now().Add(-time.Minute))).To(Succeed())
+		Expect(reconciler.isFailoverAllowedByWitness(context.TODO(), cluster)).To(BeTrue())
+	})
+
+	It("blocks the failover when the witness kubeconfig cannot be read", func() {
+		cluster.Spec.FailoverWitness.KubeconfigSecret = &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "witness-kubeconfig"},
+			Key:                  "kubeconfig",
+		}
+		Expect(reconciler.isFailoverAllowedByWitness(context.TODO(), cluster)).To(BeFalse())
+	})
+})
@@ -0,0 +1,244 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// databaseRetryDelay is the delay before applying again a database
+	// which couldn't be applied
+	databaseRetryDelay = 30 * time.Second
+
+	// databaseWaitingDelay is the delay before checking again whether
+	// the cluster of a database has a primary instance
+	databaseWaitingDelay = 10 * time.Second
+)
+
+// DatabaseReconciler reconciles the Database objects, applying them
+// through the instance manager of the primary instance
+type DatabaseReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	httpClient *http.Client
+}
+
+// NewDatabaseReconciler creates a new DatabaseReconciler
+func NewDatabaseReconciler(mgr manager.Manager) *DatabaseReconciler {
+	const connectionTimeout = 2 * time.Second
+	const requestTimeout = 5 * time.Minute
+
+	// Creating a database can take long, as it copies the template
+	// database, but we don't want to wait for lost SYN packets
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: connectionTimeout,
+			}).DialContext,
+		},
+		Timeout: requestTimeout,
+	}
+
+	return &DatabaseReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("cloudnative-pg-database"),
+		httpClient: httpClient,
+	}
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases/status,verbs=get;update;patch
+
+// Reconcile applies the database specification on the primary instance
+// of the referenced cluster
+func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var database apiv1.Database
+	if err := r.Get(ctx, req.NamespacedName, &database); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The PostgreSQL database is kept when the Database object is deleted
+	if !database.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var cluster apiv1.Cluster
+	err := r.Get(ctx, types.NamespacedName{Namespace: database.Namespace, Name: database.Spec.Cluster.Name}, &cluster)
+	if apierrs.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: databaseRetryDelay}, r.updateReadyCondition(
+			ctx, &database, metav1.ConditionFalse, apiv1.DatabaseReasonClusterNotFound,
+			fmt.Sprintf("Unknown cluster %s", database.Spec.Cluster.Name))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	primary, err := r.getPrimaryPod(ctx, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if primary == nil {
+		return ctrl.Result{RequeueAfter: databaseWaitingDelay}, r.updateReadyCondition(
+			ctx, &database, metav1.ConditionFalse, apiv1.DatabaseReasonWaitingForPrimary,
+			fmt.Sprintf("Waiting for a primary instance in cluster %s", cluster.Name))
+	}
+
+	if err := r.applyDatabase(ctx, primary, database.Spec); err != nil {
+		contextLogger.Warning("Cannot apply the database", "pod", primary.Name, "error", err.Error())
+		r.Recorder.Eventf(&database, "Warning", apiv1.DatabaseReasonReconciliationFailed,
+			"Cannot apply the database on %s: %v", primary.Name, err)
+		return ctrl.Result{RequeueAfter: databaseRetryDelay}, r.updateReadyCondition(
+			ctx, &database, metav1.ConditionFalse, apiv1.DatabaseReasonReconciliationFailed, err.Error())
+	}
+
+	return ctrl.Result{}, r.updateReadyCondition(
+		ctx, &database, metav1.ConditionTrue, apiv1.DatabaseReasonReconciled,
+		fmt.Sprintf("Database applied on %s", primary.Name))
+}
+
+// getPrimaryPod returns the Pod of the primary instance of the cluster,
+// or nil when there is no primary instance which can apply a database
+func (r *DatabaseReconciler) getPrimaryPod(ctx context.Context, cluster *apiv1.Cluster) (*corev1.Pod, error) {
+	// During a switchover or a failover, the current primary
+	// could be demoted at any time
+	if cluster.Status.CurrentPrimary == "" || cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return nil, nil
+	}
+
+	var pod corev1.Pod
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Status.CurrentPrimary}, &pod)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.IsPodReady(pod) || pod.Status.PodIP == "" {
+		return nil, nil
+	}
+
+	return &pod, nil
+}
+
+// applyDatabase sends the database specification to the instance manager
+// running in the passed Pod
+func (r *DatabaseReconciler) applyDatabase(ctx context.Context, pod *corev1.Pod, spec apiv1.DatabaseSpec) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	databaseURL := url.Build(pod.Status.PodIP, url.PathPgDatabase, url.StatusPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, databaseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	message, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s", bytes.TrimSpace(message))
+}
+
+// updateReadyCondition sets the Ready condition of the database, patching
+// the status only when it changes
+func (r *DatabaseReconciler) updateReadyCondition(
+	ctx context.Context,
+	database *apiv1.Database,
+	status metav1.ConditionStatus,
+	reason, message string,
+) error {
+	existingDatabase := database.DeepCopy()
+	setDatabaseReadyCondition(database, status, reason, message)
+	if reflect.DeepEqual(existingDatabase.Status, database.Status) {
+		return nil
+	}
+
+	return r.Status().Patch(ctx, database, client.MergeFrom(existingDatabase))
+}
+
+// setDatabaseReadyCondition updates the status of the database with the
+// passed Ready condition
+func setDatabaseReadyCondition(
+	database *apiv1.Database,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	database.Status.Ready = status == metav1.ConditionTrue
+	if database.Status.Ready {
+		database.Status.ObservedGeneration = database.Generation
+	}
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:               apiv1.DatabaseConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: database.Generation,
+	})
+}
+
+// SetupWithManager setup this controller inside the controller manager
+func (r *DatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Database{}).
+		Complete(r)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative databases", func() {
+	newDatabase := func() *apiv1.Database {
+		return &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default", Generation: 2},
+			Spec: apiv1.DatabaseSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Name:    "catalog",
+				Owner:   "catalog",
+			},
+		}
+	}
+
+	newCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+	}
+
+	newPod := func(ready bool) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: readyStatus}},
+			},
+		}
+	}
+
+	newReconciler := func(objects ...client.Object) *DatabaseReconciler {
+		return &DatabaseReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("records the applied generation only when the database is ready", func() {
+		database := newDatabase()
+		setDatabaseReadyCondition(database, metav1.ConditionFalse, apiv1.DatabaseReasonWaitingForPrimary, "")
+		Expect(database.Status.Ready).To(BeFalse())
+		Expect(database.Status.ObservedGeneration).To(BeZero())
+
+		setDatabaseReadyCondition(database, metav1.ConditionTrue, apiv1.DatabaseReasonReconciled, "")
+		Expect(database.Status.Ready).To(BeTrue())
+		Expect(database.Status.ObservedGeneration).To(BeEquivalentTo(2))
+		Expect(meta.IsStatusConditionTrue(database.Status.Conditions, apiv1.DatabaseConditionReady)).To(BeTrue())
+	})
+
+	It("uses only a ready current primary", func() {
+		ctx := context.Background()
+
+		pod, err := newReconciler(newPod(true)).getPrimaryPod(ctx, newCluster())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-1"))
+
+		pod, err = newReconciler(newPod(false)).getPrimaryPod(ctx, newCluster())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+
+		switchingCluster := newCluster()
+		switchingCluster.Status.TargetPrimary = "cluster-example-2"
+		pod, err = newReconciler(newPod(true)).getPrimaryPod(ctx, switchingCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+	})
+
+	It("waits for the primary instance before applying the database", func() {
+		ctx := context.Background()
+		database := newDatabase()
+		reconciler := newReconciler(database, newCluster())
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "catalog"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(databaseWaitingDelay))
+
+		var updated apiv1.Database
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(database), &updated)).To(Succeed())
+		condition := meta.FindStatusCondition(updated.Status.Conditions, apiv1.DatabaseConditionReady)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(apiv1.DatabaseReasonWaitingForPrimary))
+	})
+
+	It("reports the clusters which don't exist", func() {
+		ctx := context.Background()
+		database := newDatabase()
+		reconciler := newReconciler(database)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "catalog"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(databaseRetryDelay))
+
+		var updated apiv1.Database
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(database), &updated)).To(Succeed())
+		Expect(updated.Status.Ready).To(BeFalse())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, apiv1.DatabaseConditionReady).Reason).
+			To(Equal(apiv1.DatabaseReasonClusterNotFound))
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// isPodMonitorManageable checks whether the operator is allowed to update or
+// delete the passed PodMonitor on behalf of its owner. PodMonitors not
+// controlled by the owner have been created by the user and are never
+// touched: when the owner requires a PodMonitor, the conflict is reported
+// with an event. PodMonitors with the reconciliation loop disabled are
+// left to the user too
+func isPodMonitorManageable(
+	ctx context.Context,
+	recorder record.EventRecorder,
+	owner monitorOwner,
+	podMonitorEnabled bool,
+	podMonitor *monitoringv1.PodMonitor,
+) bool {
+	return isMonitorManageable(ctx, recorder, owner, "PodMonitor", podMonitorEnabled, &podMonitor.ObjectMeta)
+}
+
+// isServiceMonitorManageable checks whether the operator is allowed to
+// update or delete the passed ServiceMonitor on behalf of its owner,
+// following the same rules of the PodMonitors
+func isServiceMonitorManageable(
+	ctx context.Context,
+	recorder record.EventRecorder,
+	owner monitorOwner,
+	serviceMonitorEnabled bool,
+	serviceMonitor *monitoringv1.ServiceMonitor,
+) bool {
+	return isMonitorManageable(ctx, recorder, owner, "ServiceMonitor", serviceMonitorEnabled, &serviceMonitor.ObjectMeta)
+}
+
+// monitorOwner is the object owning a PodMonitor or a ServiceMonitor
+type monitorOwner interface {
+	metav1.Object
+	runtime.Object
+}
+
+// isMonitorManageable checks whether the operator is allowed to update or
+// delete the passed monitor, whose kind is used in the logs and events
+func isMonitorManageable(
+	ctx context.Context,
+	recorder record.EventRecorder,
+	owner monitorOwner,
+	kind string,
+	monitorEnabled bool,
+	monitor *metav1.ObjectMeta,
+) bool {
+	contextLogger := log.FromContext(ctx)
+
+	if !metav1.IsControlledBy(monitor, owner) {
+		if !monitorEnabled {
+			return false
+		}
+		contextLogger.Warning("A "+kind+" with the same name was not created by the operator, "+
+			"leaving it untouched",
+			"name", monitor.Name)
+		recorder.Eventf(owner, "Warning", kind+"Conflict",
+			"%s %s was not created by the operator and will not be managed", kind, monitor.Name)
+		return false
+	}
+
+	if utils.IsReconciliationDisabled(monitor) {
+		contextLogger.Debug("Reconciliation loop disabled for the "+kind+", leaving it untouched",
+			"name", monitor.Name)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PodMonitor ownership", func() {
+	var (
+		ctx      context.Context
+		cluster  *apiv1.Cluster
+		recorder *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{Kind: apiv1.ClusterKind, APIVersion: apiv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-uid",
+			},
+		}
+	})
+
+	newPodMonitor := func() *monitoringv1.PodMonitor {
+		return &monitoringv1.PodMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+	}
+
+	It("manages the PodMonitors controlled by the cluster", func() {
+		podMonitor := newPodMonitor()
+		SetClusterOwnerAnnotationsAndLabels(&podMonitor.ObjectMeta, cluster)
+		Expect(isPodMonitorManageable(ctx, recorder, cluster, true, podMonitor)).To(BeTrue())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("reports a conflict with a PodMonitor created by the user", func() {
+		Expect(isPodMonitorManageable(ctx, recorder, cluster, true, newPodMonitor())).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring("PodMonitorConflict")))
+	})
+
+	It("silently ignores a PodMonitor created by the user when monitoring is disabled", func() {
+		Expect(isPodMonitorManageable(ctx, recorder, cluster, false, newPodMonitor())).To(BeFalse())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("leaves alone the PodMonitors with the reconciliation loop disabled", func() {
+		podMonitor := newPodMonitor()
+		SetClusterOwnerAnnotationsAndLabels(&podMonitor.ObjectMeta, cluster)
+		podMonitor.Annotations = map[string]string{
+			utils.ReconciliationLoopAnnotationName: "disabled",
+		}
+		Expect(isPodMonitorManageable(ctx, recorder, cluster, true, podMonitor)).To(BeFalse())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("applies the same rules to the ServiceMonitors", func() {
+		serviceMonitor := &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		Expect(isServiceMonitorManageable(ctx, recorder, cluster, true, serviceMonitor)).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ServiceMonitorConflict")))
+
+		SetClusterOwnerAnnotationsAndLabels(&serviceMonitor.ObjectMeta, cluster)
+		Expect(isServiceMonitorManageable(ctx, recorder, cluster, true, serviceMonitor)).To(BeTrue())
+	})
+})
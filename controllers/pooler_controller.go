@@ -38,13 +38,15 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // PoolerReconciler reconciles a Pooler object
 type PoolerReconciler struct {
 	client.Client
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
+	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
+	Capabilities *utils.CapabilitiesRegistry
 }
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=poolers,verbs=get;list;watch;create;update;patch;delete
@@ -54,6 +56,9 @@ type PoolerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=secrets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;create;delete;update;patch;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;create;list;watch;delete;patch
 
 // Reconcile implements the main reconciliation loop for pooler objects
 func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -121,7 +126,7 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 // SetupWithManager setup this controller inside the controller manager
 func (r *PoolerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Pooler{}).
 		Owns(&v1.Deployment{}).
 		Owns(&corev1.Service{}).
@@ -133,7 +138,36 @@ func (r *PoolerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler(ctx)),
 			builder.WithPredicates(secretsPoolerPredicate),
 		).
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &apiv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToPooler(ctx)),
+			builder.WithPredicates(readOnlyServiceMembersPredicate),
+		)
+
+	// The PodMonitors, the ServiceMonitors and the ScaledObjects are
+	// created as soon as the Prometheus Operator or KEDA are installed
+	if r.Capabilities != nil {
+		controllerBuilder = controllerBuilder.Watches(
+			capabilitiesChangesSource(r.Capabilities, r.listPoolers),
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// listPoolers lists every pooler managed by the operator
+func (r *PoolerReconciler) listPoolers(ctx context.Context) ([]client.Object, error) {
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers); err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, len(poolers.Items))
+	for idx := range poolers.Items {
+		result[idx] = &poolers.Items[idx]
+	}
+	return result, nil
 }
 
 // isOwnedByPooler checks that an object is owned by a pooler and returns
@@ -201,3 +235,50 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 	}
 	return requests
 }
+
+// mapClusterToPooler returns a function mapping cluster events to the
+// poolers balancing their connections across its replicas
+func (r *PoolerReconciler) mapClusterToPooler(ctx context.Context) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		cluster, ok := obj.(*apiv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		var poolers apiv1.PoolerList
+		if err := r.List(ctx, &poolers,
+			client.InNamespace(cluster.Namespace),
+		); err != nil {
+			log.FromContext(ctx).Error(err, "while getting pooler list for cluster",
+				"namespace", cluster.Namespace, "cluster", cluster.Name)
+			return nil
+		}
+
+		filteredPoolersList := getPoolersBalancedAcrossCluster(poolers, cluster)
+		result := make([]reconcile.Request, len(filteredPoolersList))
+		for idx, value := range filteredPoolersList {
+			result[idx] = reconcile.Request{NamespacedName: value}
+		}
+
+		return result
+	}
+}
+
+// getPoolersBalancedAcrossCluster get a list of poolers which are balancing
+// their connections across the replicas of the passed cluster
+func getPoolersBalancedAcrossCluster(
+	poolers apiv1.PoolerList,
+	cluster *apiv1.Cluster,
+) (requests []types.NamespacedName) {
+	for _, pooler := range poolers.Items {
+		if pooler.Spec.Cluster.Name == cluster.Name && pooler.IsDirectLoadBalancingEnabled() {
+			requests = append(requests,
+				types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			)
+		}
+	}
+	return requests
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // secretsPoolerPredicate contains the set of predicate functions of the pooler secrets
@@ -39,8 +43,31 @@ var (
 			return isUsefulPoolerSecret(e.ObjectNew)
 		},
 	}
+
+	// readOnlyServiceMembersPredicate filters the cluster changes affecting
+	// the backends of the poolers
+	readOnlyServiceMembersPredicate = predicate.Funcs{
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isReadOnlyServiceMembersChanged(e.ObjectOld, e.ObjectNew)
+		},
+	}
 )
 
+func isReadOnlyServiceMembersChanged(oldObject, newObject client.Object) bool {
+	oldCluster, oldOk := oldObject.(*apiv1.Cluster)
+	newCluster, newOk := newObject.(*apiv1.Cluster)
+	if !oldOk || !newOk {
+		return false
+	}
+
+	return !reflect.DeepEqual(oldCluster.Status.ReadOnlyServiceMembers, newCluster.Status.ReadOnlyServiceMembers) ||
+		!reflect.DeepEqual(oldCluster.Status.ReadOnlyServiceMembersByConnections,
+			newCluster.Status.ReadOnlyServiceMembersByConnections)
+}
+
 func isOwnedByPoolerOrSatisfiesPredicate(
 	object client.Object,
 	predicate func(client.Object) bool,
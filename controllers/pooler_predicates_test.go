@@ -63,6 +63,20 @@ var _ = Describe("pooler_predicates unit tests", func() {
 		})
 	})
 
+	It("detects the changes of the replicas selected by the -ro service", func() {
+		namespace := newFakeNamespace()
+		oldCluster := newFakeCNPGCluster(namespace)
+		newCluster := oldCluster.DeepCopy()
+		Expect(isReadOnlyServiceMembersChanged(oldCluster, newCluster)).To(BeFalse())
+
+		newCluster.Status.ReadOnlyServiceMembers = []string{oldCluster.Name + "-2"}
+		Expect(isReadOnlyServiceMembersChanged(oldCluster, newCluster)).To(BeTrue())
+
+		newCluster = oldCluster.DeepCopy()
+		newCluster.Status.ReadOnlyServiceMembersByConnections = []string{oldCluster.Name + "-2"}
+		Expect(isReadOnlyServiceMembersChanged(oldCluster, newCluster)).To(BeTrue())
+	})
+
 	It("makes sure isOwnedByPoolerOrSatisfiesPredicate works correctly", func() {
 		namespace := newFakeNamespace()
 		cluster := newFakeCNPGCluster(namespace)
@@ -20,6 +20,8 @@ import (
 	"context"
 	"reflect"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
@@ -59,10 +61,22 @@ func (r *PoolerReconciler) updatePoolerStatus(
 			Name:    cluster.GetClientCASecretName(),
 			Version: cluster.Status.SecretsResourceVersion.ClientCASecretVersion,
 		}
+		updatedStatus.ServerTLSMode = cluster.GetReplicationSSLMode()
+
+		updatedStatus.Backends = nil
+		if pooler.IsDirectLoadBalancingEnabled() {
+			updatedStatus.Backends = buildPoolerBackends(pooler, cluster)
+		}
 	}
 
 	if resources.Deployment != nil {
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
+
+		selector, err := metav1.LabelSelectorAsSelector(resources.Deployment.Spec.Selector)
+		if err != nil {
+			return err
+		}
+		updatedStatus.Selector = selector.String()
 	}
 
 	// then update the status if anything changed
@@ -73,3 +87,25 @@ func (r *PoolerReconciler) updatePoolerStatus(
 
 	return nil
 }
+
+// buildPoolerBackends returns the host names of the replicas selected by
+// the `-ro` service, which PgBouncer balances its server connections
+// across. With the leastConnections load balancing, the replicas are
+// sorted by ascending number of client connections, as PgBouncer
+// prefers the first one. No backend is returned when no replica is
+// selected, letting PgBouncer use the `-ro` service and its fallback policy
+func buildPoolerBackends(pooler *apiv1.Pooler, cluster *apiv1.Cluster) []string {
+	members := cluster.Status.ReadOnlyServiceMembers
+	if pooler.IsLeastConnectionsLoadBalancingEnabled() && len(cluster.Status.ReadOnlyServiceMembersByConnections) > 0 {
+		members = cluster.Status.ReadOnlyServiceMembersByConnections
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	backends := make([]string, len(members))
+	for idx, podName := range members {
+		backends[idx] = cluster.GetInstanceHostName(podName)
+	}
+	return backends
+}
@@ -35,6 +35,7 @@ var _ = Describe("pooler_status unit tests", func() {
 		Expect(pooler.Status.Secrets.ServerCA.Name).To(Equal(cluster.GetServerCASecretName()))
 		Expect(pooler.Status.Secrets.ServerTLS.Name).To(Equal(cluster.GetServerTLSSecretName()))
 		Expect(pooler.Status.Secrets.ClientCA.Name).To(Equal(cluster.GetClientCASecretName()))
+		Expect(pooler.Status.ServerTLSMode).To(Equal(cluster.GetReplicationSSLMode()))
 	}
 	assertAuthUserStatus := func(pooler *v1.Pooler, authUserSecret *corev1.Secret) {
 		Expect(pooler.Status.Secrets.PgBouncerSecrets.AuthQuery.Name).To(Equal(authUserSecret.Name))
@@ -85,6 +86,44 @@ var _ = Describe("pooler_status unit tests", func() {
 		err = poolerReconciler.updatePoolerStatus(ctx, pooler, res)
 		Expect(err).To(BeNil())
 		Expect(pooler.Status.Instances).To(Equal(dep.Status.Replicas))
+		Expect(pooler.Status.Selector).To(Equal(pgbouncer.PgbouncerNameLabel + "=" + pooler.Name))
+	})
+
+	It("should publish the backends of the roundRobin load balancing", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Status.ReadOnlyServiceMembers = []string{cluster.Name + "-2", cluster.Name + "-3"}
+		pooler := newFakePooler(cluster)
+		res := &poolerManagedResources{Cluster: cluster}
+
+		err := poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).To(BeNil())
+		Expect(pooler.Status.Backends).To(BeEmpty())
+
+		pooler.Spec.Type = v1.PoolerTypeRO
+		pooler.Spec.LoadBalancing = v1.PoolerLoadBalancingRoundRobin
+		err = poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).To(BeNil())
+		Expect(pooler.Status.Backends).To(Equal([]string{
+			cluster.GetInstanceHostName(cluster.Name + "-2"),
+			cluster.GetInstanceHostName(cluster.Name + "-3"),
+		}))
+
+		cluster.Status.ReadOnlyServiceMembersByConnections = []string{cluster.Name + "-3", cluster.Name + "-2"}
+		pooler.Spec.LoadBalancing = v1.PoolerLoadBalancingLeastConnections
+		err = poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).To(BeNil())
+		Expect(pooler.Status.Backends).To(Equal([]string{
+			cluster.GetInstanceHostName(cluster.Name + "-3"),
+			cluster.GetInstanceHostName(cluster.Name + "-2"),
+		}))
+
+		cluster.Status.ReadOnlyServiceMembers = nil
+		cluster.Status.ReadOnlyServiceMembersByConnections = nil
+		err = poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).To(BeNil())
+		Expect(pooler.Status.Backends).To(BeEmpty())
 	})
 
 	It("should correctly interact with the api server", func() {
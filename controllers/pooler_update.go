@@ -21,9 +21,11 @@ import (
 	"fmt"
 	"reflect"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -52,7 +54,23 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
-	return r.updateService(ctx, pooler, resources)
+	if err := r.updateService(ctx, pooler, resources); err != nil {
+		return err
+	}
+
+	if err := r.updateMetricsService(ctx, pooler); err != nil {
+		return err
+	}
+
+	if err := r.updatePodMonitor(ctx, pooler); err != nil {
+		return err
+	}
+
+	if err := r.updateServiceMonitor(ctx, pooler); err != nil {
+		return err
+	}
+
+	return r.updateScaledObject(ctx, pooler)
 }
 
 // updateDeployment update the deployment or create it when needed
@@ -145,6 +163,244 @@ func (r *PoolerReconciler) updateService(
 	return nil
 }
 
+// updateMetricsService create or delete the headless service exposing
+// the metrics of the pgbouncer instances, depending on the monitoring
+// configuration of the pooler
+func (r *PoolerReconciler) updateMetricsService(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	service, err := getServiceOrNil(ctx, r.Client,
+		client.ObjectKey{Name: pooler.GetMetricsServiceName(), Namespace: pooler.Namespace})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case pooler.IsMetricsServiceRequired() && service == nil:
+		service = pgbouncer.MetricsService(pooler)
+		if err := ctrl.SetControllerReference(pooler, service, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating metrics service")
+		if err := r.Create(ctx, service); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+
+	case !pooler.IsMetricsServiceRequired() && service != nil && metav1.IsControlledBy(service, pooler):
+		contextLog.Info("Deleting metrics service")
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// updatePodMonitor create, patch or delete the PodMonitor of the pooler,
+// depending on its monitoring configuration
+//
+//nolint:dupl
+func (r *PoolerReconciler) updatePodMonitor(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	// Checking for the PodMonitor resource in the cluster
+	if !r.Capabilities.Get().HavePodMonitor {
+		contextLog.Debug("Kind PodMonitor not detected")
+		return nil
+	}
+
+	podMonitor := &monitoringv1.PodMonitor{}
+	if err := r.Get(ctx, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace}, podMonitor); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the podmonitor: %w", err)
+		}
+		podMonitor = nil
+	}
+
+	// A PodMonitor managed by the user is never overwritten
+	if podMonitor != nil && !isPodMonitorManageable(ctx, r.Recorder, pooler, pooler.IsPodMonitorEnabled(), podMonitor) {
+		return nil
+	}
+
+	switch {
+	case !pooler.IsPodMonitorEnabled() && podMonitor == nil:
+		return nil
+
+	case !pooler.IsPodMonitorEnabled() && podMonitor != nil:
+		contextLog.Info("Deleting PodMonitor")
+		if err := r.Delete(ctx, podMonitor); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+
+	case podMonitor == nil:
+		newPodMonitor := pgbouncer.PodMonitor(pooler)
+		if err := ctrl.SetControllerReference(pooler, newPodMonitor, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating PodMonitor")
+		if err := r.Create(ctx, newPodMonitor); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+
+	default:
+		origPodMonitor := podMonitor.DeepCopy()
+		podMonitor.Spec = pgbouncer.PodMonitor(pooler).Spec
+		if reflect.DeepEqual(origPodMonitor, podMonitor) {
+			return nil
+		}
+
+		contextLog.Info("Patching PodMonitor")
+		return r.Patch(ctx, podMonitor, client.MergeFrom(origPodMonitor))
+	}
+}
+
+// updateServiceMonitor create, patch or delete the ServiceMonitor of the
+// pooler, depending on its monitoring configuration
+//
+//nolint:dupl
+func (r *PoolerReconciler) updateServiceMonitor(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	// Checking for the ServiceMonitor resource in the cluster
+	if !r.Capabilities.Get().HaveServiceMonitor {
+		contextLog.Debug("Kind ServiceMonitor not detected")
+		return nil
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	if err := r.Get(ctx, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace}, serviceMonitor); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the servicemonitor: %w", err)
+		}
+		serviceMonitor = nil
+	}
+
+	// A ServiceMonitor managed by the user is never overwritten
+	if serviceMonitor != nil &&
+		!isServiceMonitorManageable(ctx, r.Recorder, pooler, pooler.IsServiceMonitorEnabled(), serviceMonitor) {
+		return nil
+	}
+
+	switch {
+	case !pooler.IsServiceMonitorEnabled() && serviceMonitor == nil:
+		return nil
+
+	case !pooler.IsServiceMonitorEnabled() && serviceMonitor != nil:
+		contextLog.Info("Deleting ServiceMonitor")
+		if err := r.Delete(ctx, serviceMonitor); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+
+	case serviceMonitor == nil:
+		newServiceMonitor := pgbouncer.ServiceMonitor(pooler)
+		if err := ctrl.SetControllerReference(pooler, newServiceMonitor, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating ServiceMonitor")
+		if err := r.Create(ctx, newServiceMonitor); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+
+	default:
+		origServiceMonitor := serviceMonitor.DeepCopy()
+		serviceMonitor.Spec = pgbouncer.ServiceMonitor(pooler).Spec
+		if reflect.DeepEqual(origServiceMonitor, serviceMonitor) {
+			return nil
+		}
+
+		contextLog.Info("Patching ServiceMonitor")
+		return r.Patch(ctx, serviceMonitor, client.MergeFrom(origServiceMonitor))
+	}
+}
+
+// updateScaledObject create, patch or delete the KEDA ScaledObject of the
+// pooler, depending on its autoscaling configuration
+func (r *PoolerReconciler) updateScaledObject(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	// Checking for the ScaledObject resource in the cluster
+	if !r.Capabilities.Get().HaveKEDA {
+		if pooler.IsAutoscalingEnabled() {
+			contextLog.Warning("Autoscaling is enabled but the KEDA ScaledObject kind has not been detected")
+		}
+		return nil
+	}
+
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(pgbouncer.ScaledObjectGVK)
+	if err := r.Get(ctx, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace}, scaledObject); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the scaledobject: %w", err)
+		}
+		scaledObject = nil
+	}
+
+	// A ScaledObject managed by the user is never overwritten
+	if scaledObject != nil && !isMonitorManageable(ctx, r.Recorder, pooler, "ScaledObject",
+		pooler.IsAutoscalingEnabled(), &metav1.ObjectMeta{
+			Name:            scaledObject.GetName(),
+			Annotations:     scaledObject.GetAnnotations(),
+			OwnerReferences: scaledObject.GetOwnerReferences(),
+		}) {
+		return nil
+	}
+
+	switch {
+	case !pooler.IsAutoscalingEnabled() && scaledObject == nil:
+		return nil
+
+	case !pooler.IsAutoscalingEnabled() && scaledObject != nil:
+		contextLog.Info("Deleting ScaledObject")
+		if err := r.Delete(ctx, scaledObject); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+
+	case scaledObject == nil:
+		newScaledObject := pgbouncer.ScaledObject(pooler)
+		if err := ctrl.SetControllerReference(pooler, newScaledObject, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating ScaledObject")
+		if err := r.Create(ctx, newScaledObject); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+
+	default:
+		origScaledObject := scaledObject.DeepCopy()
+		scaledObject.Object["spec"] = pgbouncer.ScaledObject(pooler).Object["spec"]
+		if reflect.DeepEqual(origScaledObject.Object["spec"], scaledObject.Object["spec"]) {
+			return nil
+		}
+
+		contextLog.Info("Patching ScaledObject")
+		return r.Patch(ctx, scaledObject, client.MergeFrom(origScaledObject))
+	}
+}
+
 // updateRBAC update or create the pgbouncer RBAC
 func (r *PoolerReconciler) updateRBAC(
 	ctx context.Context,
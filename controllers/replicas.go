@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,6 +30,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -99,12 +101,28 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", nil
 	}
 
+	// The restricted replicas can never be promoted
+	candidates := getPromotionCandidates(cluster, status)
+	if len(candidates.Items) == 0 {
+		contextLogger.Info("Current primary isn't healthy, but there are no instances that can be promoted")
+		status.LogStatus(ctx)
+		return "", nil
+	}
+	newPrimary := candidates.Items[0].Pod.Name
+
 	// The current primary is not correctly working, and we need to elect a new one
 	// but before doing that we need to wait for all the WAL receivers to be
 	// terminated. To make sure they eventually terminate we signal the old primary
 	// (if is still alive) to shut down by setting the apiv1.PendingFailoverMarker as
 	// target primary.
 	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if !r.isFailoverAllowedByWitness(ctx, cluster) {
+			r.Recorder.Eventf(cluster, "Warning", "FailoverBlocked",
+				"Current primary isn't healthy, but %v still holds the failover witness lease",
+				cluster.Status.CurrentPrimary)
+			return "", ErrFailoverBlockedByWitness
+		}
+
 		contextLogger.Info("Current primary isn't healthy, initiating a failover")
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
@@ -129,32 +147,45 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name)
+		// The witness is checked again as the old primary could have
+		// renewed its lease while the WAL receivers were shutting down
+		if !r.isFailoverAllowedByWitness(ctx, cluster) {
+			return "", ErrFailoverBlockedByWitness
+		}
+
+		if candidates.Items[0].IsClockSkewed {
+			r.Recorder.Eventf(cluster, "Warning", string(apiv1.ConditionReasonClockSkewDetected),
+				"Promoting %v even if its clock is skewed by %v, as no other replica is equally up to date",
+				newPrimary, candidates.Items[0].ClockSkew)
+		}
+
+		contextLogger.Info("Failing over", "newPrimary", newPrimary)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
 			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)
+			cluster.Status.CurrentPrimary, newPrimary)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
-			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)); err != nil {
+			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, newPrimary)); err != nil {
 			return "", err
 		}
 	} else {
 		contextLogger.Info("Target primary isn't healthy, switching target",
-			"newPrimary", status.Items[0].Pod.Name)
+			"newPrimary", newPrimary)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+			cluster.Status.TargetPrimary, newPrimary)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
-			fmt.Sprintf("Switching over to %v", status.Items[0].Pod.Name)); err != nil {
+			fmt.Sprintf("Switching over to %v", newPrimary)); err != nil {
 			return "", err
 		}
 	}
 
 	// Set the first pod in the sorted list as the new targetPrimary
-	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
+	r.Recorder.Event(cluster, "Normal", "PromotionCandidateElected", describePromotionElection(candidates))
+	return newPrimary, r.setPrimaryInstance(ctx, cluster, newPrimary)
 }
 
 // isNodeUnschedulable checks whether a node is set to unschedulable
@@ -207,6 +238,11 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 
 	// Start looking for the next primary among the pods
 	for _, candidate := range podsOnOtherNodes.Items {
+		// The restricted replicas can never be promoted
+		if cluster.IsRestrictedReplica(candidate.Pod.Name) {
+			continue
+		}
+
 		// If candidate on an unschedulable node too, skip it
 		if unschedulable, _ := r.isNodeUnschedulable(ctx, candidate.Node); unschedulable {
 			continue
@@ -272,19 +308,100 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		return "", ErrWalReceiversRunning
 	}
 
+	// The restricted replicas can never be promoted
+	candidates := getPromotionCandidates(cluster, status)
+	if len(candidates.Items) == 0 {
+		contextLogger.Info("Current target primary isn't healthy, but there are no instances that can be promoted")
+		status.LogStatus(ctx)
+		return "", nil
+	}
+	newPrimary := candidates.Items[0].Pod.Name
+
 	contextLogger.Info("Current target primary isn't healthy, failing over",
-		"newPrimary", status.Items[0].Pod.Name)
+		"newPrimary", newPrimary)
 	status.LogStatus(ctx)
 	contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 	r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 		"Current target primary isn't healthy, failing over from %v to %v",
-		cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+		cluster.Status.TargetPrimary, newPrimary)
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
-		fmt.Sprintf("Failing over to %v", status.Items[0].Pod.Name)); err != nil {
+		fmt.Sprintf("Failing over to %v", newPrimary)); err != nil {
 		return "", err
 	}
 
-	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
+	r.Recorder.Event(cluster, "Normal", "PromotionCandidateElected", describePromotionElection(candidates))
+	return newPrimary, r.setPrimaryInstance(ctx, cluster, newPrimary)
+}
+
+// getPromotionCandidates filters out the restricted replicas from the passed
+// list, preserving the election order
+func getPromotionCandidates(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) postgres.PostgresqlStatusList {
+	candidates := postgres.PostgresqlStatusList{}
+	for _, item := range status.Items {
+		if !cluster.IsRestrictedReplica(item.Pod.Name) {
+			candidates.Items = append(candidates.Items, item)
+		}
+	}
+	return candidates
+}
+
+// markPromotionPriorities sets the promotion priority of every instance
+// as declared in the cluster specification, so that it can be considered
+// while sorting the instances in their election order
+func markPromotionPriorities(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) {
+	for idx := range status.Items {
+		status.Items[idx].PromotionPriority = cluster.GetPromotionPriority(status.Items[idx].Pod.Name)
+	}
+}
+
+// markFailoverSlotsReadiness marks the replicas where every declared
+// logical replication slot has been synchronized, so that they are
+// preferred while electing a new primary
+func markFailoverSlotsReadiness(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) {
+	logicalDecoding := cluster.Spec.PostgresConfiguration.LogicalDecoding
+	if !logicalDecoding.AreFailoverSlotsEnabled() {
+		return
+	}
+
+	for idx := range status.Items {
+		synchronized := stringset.From(status.Items[idx].SynchronizedLogicalSlots)
+		hasFailoverSlots := true
+		for _, slot := range logicalDecoding.Slots {
+			if !synchronized.Has(slot.Name) {
+				hasFailoverSlots = false
+				break
+			}
+		}
+		status.Items[idx].HasFailoverSlots = hasFailoverSlots
+	}
+}
+
+// describePromotionElection explains why the first of the passed candidates
+// has been elected as the new primary, listing the LSNs and the priorities
+// of the candidates in their election order
+func describePromotionElection(candidates postgres.PostgresqlStatusList) string {
+	if len(candidates.Items) == 0 {
+		return "No candidate for the promotion"
+	}
+
+	describe := func(item postgres.PostgresqlStatus) string {
+		return fmt.Sprintf("%v (received LSN: %v, replayed LSN: %v, priority: %v)",
+			item.Pod.Name, item.ReceivedLsn, item.ReplayLsn, item.PromotionPriority)
+	}
+
+	message := fmt.Sprintf("Elected %v as the most advanced candidate", describe(candidates.Items[0]))
+	if len(candidates.Items) == 1 {
+		return message + ", no other candidate was available"
+	}
+
+	others := make([]string, 0, len(candidates.Items)-1)
+	for _, item := range candidates.Items[1:] {
+		others = append(others, describe(item))
+	}
+	return message + ", other candidates: " + strings.Join(others, ", ")
 }
 
 // GetPodsNotOnPrimaryNode filters out only pods that are not on the same node as the primary one
@@ -309,6 +426,7 @@ func GetPodsNotOnPrimaryNode(
 // and the other instances in their election order
 func (r *ClusterReconciler) getStatusFromInstances(
 	ctx context.Context,
+	cluster *apiv1.Cluster,
 	pods corev1.PodList,
 ) postgres.PostgresqlStatusList {
 	// Only work on Pods which can still become active in the future
@@ -319,6 +437,9 @@ func (r *ClusterReconciler) getStatusFromInstances(
 	}
 
 	status := r.extractInstancesStatus(ctx, filteredPods)
+	markClockSkewedInstances(status, cluster.GetMaxClockSkew())
+	markPromotionPriorities(cluster, status)
+	markFailoverSlotsReadiness(cluster, status)
 	sort.Sort(&status)
 	for idx := range status.Items {
 		if status.Items[idx].Error != nil {
@@ -533,6 +654,16 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 				}
 			}
 
+		case cluster.IsRestrictedReplica(pod.Name):
+			if !hasRole || podRole != specs.ClusterRoleLabelRestricted {
+				contextLogger.Info("Setting restricted replica label", "pod", pod.Name)
+				patch := client.MergeFrom(pod.DeepCopy())
+				pod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelRestricted
+				if err := r.Patch(ctx, pod, patch); err != nil {
+					return err
+				}
+			}
+
 		default:
 			if !hasRole || podRole != specs.ClusterRoleLabelReplica {
 				contextLogger.Info("Setting replica label", "pod", pod.Name)
@@ -17,9 +17,12 @@ limitations under the License.
 package controllers
 
 import (
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 
@@ -150,3 +153,136 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Promotion candidates", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			RestrictedReplicas: &apiv1.RestrictedReplicasConfiguration{Instances: []int{2}},
+		},
+		Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+	}
+	newStatus := func(name string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+	statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+		newStatus("cluster-example-2"),
+		newStatus("cluster-example-3"),
+		newStatus("cluster-example-1"),
+	}}
+
+	It("excludes the restricted replicas preserving the election order", func() {
+		candidates := getPromotionCandidates(cluster, statusList)
+		Expect(candidates.Items).To(HaveLen(2))
+		Expect(candidates.Items[0].Pod.Name).To(Equal("cluster-example-3"))
+		Expect(candidates.Items[1].Pod.Name).To(Equal("cluster-example-1"))
+	})
+
+	It("never chooses a restricted replica as the switchover target", func() {
+		Expect(getSwitchoverTarget(cluster, &statusList, "cluster-example-1")).To(Equal("cluster-example-3"))
+		Expect(getSwitchoverTarget(cluster, &postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1"),
+			newStatus("cluster-example-2"),
+		}}, "cluster-example-1")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Promotion priorities", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			PromotionPriorities: []apiv1.PromotionPriority{{Instance: 3, Priority: 10}},
+		},
+	}
+	newStatus := func(name string, lsn postgres.LSN) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReceivedLsn: lsn,
+			ReplayLsn:   lsn,
+		}
+	}
+
+	It("elects the replica with the highest priority between the equally up-to-date ones", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", "0/5000000"),
+			newStatus("cluster-example-3", "0/5000000"),
+		}}
+		markPromotionPriorities(cluster, statusList)
+		sort.Sort(&statusList)
+		Expect(statusList.Items[0].Pod.Name).To(Equal("cluster-example-3"))
+		Expect(statusList.Items[0].PromotionPriority).To(BeEquivalentTo(10))
+		Expect(statusList.Items[1].PromotionPriority).To(BeZero())
+	})
+
+	It("never prefers a replica with a higher priority but less WAL", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", "0/6000000"),
+			newStatus("cluster-example-3", "0/5000000"),
+		}}
+		markPromotionPriorities(cluster, statusList)
+		sort.Sort(&statusList)
+		Expect(statusList.Items[0].Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("describes the election rationale", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-3", "0/5000000"),
+			newStatus("cluster-example-2", "0/5000000"),
+		}}
+		markPromotionPriorities(cluster, statusList)
+		Expect(describePromotionElection(statusList)).To(Equal(
+			"Elected cluster-example-3 (received LSN: 0/5000000, replayed LSN: 0/5000000, priority: 10) " +
+				"as the most advanced candidate, other candidates: " +
+				"cluster-example-2 (received LSN: 0/5000000, replayed LSN: 0/5000000, priority: 0)"))
+		Expect(describePromotionElection(postgres.PostgresqlStatusList{
+			Items: statusList.Items[:1],
+		})).To(HaveSuffix("no other candidate was available"))
+	})
+})
+
+var _ = Describe("Failover slots readiness", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				LogicalDecoding: &apiv1.LogicalDecodingConfiguration{
+					FailoverSlots: true,
+					Slots: []apiv1.LogicalReplicationSlot{
+						{Name: "debezium", Database: "app"},
+						{Name: "audit", Database: "app"},
+					},
+				},
+			},
+		},
+	}
+	newStatus := func(name string, lsn postgres.LSN, slots ...string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                      corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReceivedLsn:              lsn,
+			ReplayLsn:                lsn,
+			SynchronizedLogicalSlots: slots,
+		}
+	}
+
+	It("prefers the equally up-to-date replicas where every slot has been synchronized", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", "0/5000000", "debezium"),
+			newStatus("cluster-example-3", "0/5000000", "audit", "debezium"),
+		}}
+		markFailoverSlotsReadiness(cluster, statusList)
+		sort.Sort(&statusList)
+		Expect(statusList.Items[0].Pod.Name).To(Equal("cluster-example-3"))
+		Expect(statusList.Items[0].HasFailoverSlots).To(BeTrue())
+		Expect(statusList.Items[1].HasFailoverSlots).To(BeFalse())
+	})
+
+	It("never prefers a replica with the synchronized slots but less WAL", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", "0/6000000"),
+			newStatus("cluster-example-3", "0/5000000", "audit", "debezium"),
+		}}
+		markFailoverSlotsReadiness(cluster, statusList)
+		sort.Sort(&statusList)
+		Expect(statusList.Items[0].Pod.Name).To(Equal("cluster-example-2"))
+	})
+})
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"strconv"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -154,18 +156,20 @@ func ReconcileScheduledBackup(
 		}
 
 		nextTime := schedule.Next(now)
-		contextLogger.Info("Next backup schedule", "next", nextTime)
-		event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Scheduled first backup by %v", nextTime)
-		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+		startTime := nextTime.Add(getScheduleJitter(scheduledBackup, nextTime))
+		contextLogger.Info("Next backup schedule", "next", startTime)
+		event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Scheduled first backup by %v", startTime)
+		return ctrl.Result{RequeueAfter: startTime.Sub(now)}, nil
 	}
 
 	// Let's check if we are supposed to start a new backup.
 	nextTime := schedule.Next(scheduledBackup.GetStatus().LastCheckTime.Time)
-	contextLogger.Info("Next backup schedule", "next", nextTime)
+	startTime := nextTime.Add(getScheduleJitter(scheduledBackup, nextTime))
+	contextLogger.Info("Next backup schedule", "next", startTime)
 
-	if now.Before(nextTime) {
+	if now.Before(startTime) {
 		// No need to schedule a new backup, let's wait a bit
-		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+		return ctrl.Result{RequeueAfter: startTime.Sub(now)}, nil
 	}
 
 	return createBackup(ctx, event, client, scheduledBackup, nextTime, now, schedule, false)
@@ -237,7 +241,8 @@ func createBackup(
 	scheduledBackup.GetStatus().LastScheduleTime = &metav1.Time{
 		Time: backupTime,
 	}
-	nextBackupTime := schedule.Next(now)
+	nextScheduleTime := schedule.Next(now)
+	nextBackupTime := nextScheduleTime.Add(getScheduleJitter(scheduledBackup, nextScheduleTime))
 	scheduledBackup.GetStatus().NextScheduleTime = &metav1.Time{
 		Time: nextBackupTime,
 	}
@@ -256,6 +261,22 @@ func createBackup(
 	return ctrl.Result{RequeueAfter: nextBackupTime.Sub(now)}, nil
 }
 
+// getScheduleJitter returns the delay to be added to the passed schedule time
+// before starting the backup, within the limit set in the operator
+// configuration. The delay is derived from the identity of the scheduled backup
+// and from the schedule time, so that it's stable across reconciliation loops
+// while being spread among the scheduled backups sharing the same schedule
+func getScheduleJitter(scheduledBackup *apiv1.ScheduledBackup, scheduleTime time.Time) time.Duration {
+	maxJitter := configuration.Current.BackupScheduleJitter
+	if maxJitter <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%s/%s/%d", scheduledBackup.Namespace, scheduledBackup.Name, scheduleTime.Unix())
+	return time.Duration(hash.Sum64()%uint64(maxJitter)) * time.Second
+}
+
 // GetChildBackups gets all the backups scheduled by a certain scheduler
 func (r *ScheduledBackupReconciler) GetChildBackups(
 	ctx context.Context,
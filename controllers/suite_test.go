@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -100,6 +101,8 @@ var _ = BeforeSuite(func() {
 		Client:   k8sClient,
 		Scheme:   scheme,
 		Recorder: record.NewFakeRecorder(120),
+		Capabilities: utils.NewCapabilitiesRegistry(
+			discovery.NewDiscoveryClientForConfigOrDie(cfg), k8sClient, 0),
 	}
 })
 
@@ -325,6 +328,8 @@ func createManagerWithReconcilers(ctx context.Context) (*ClusterReconciler, *Poo
 		Client:   mgr.GetClient(),
 		Scheme:   scheme,
 		Recorder: record.NewFakeRecorder(120),
+		Capabilities: utils.NewCapabilitiesRegistry(
+			discovery.NewDiscoveryClientForConfigOrDie(cfg), mgr.GetClient(), 0),
 	}
 
 	err = poolerRec.SetupWithManager(ctx, mgr)
@@ -0,0 +1,124 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupencryption implement the "backup-encryption" command,
+// encrypting the base backups uploaded by barman-cloud-backup and
+// decrypting the ones downloaded by barman-cloud-restore
+package backupencryption
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the "backup-encryption" subcommand
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "backup-encryption",
+		Short: "Encrypt and decrypt the base backups stored in the object store",
+	}
+
+	cmd.AddCommand(newEncryptPartCmd())
+	cmd.AddCommand(newDecryptCmd())
+
+	return &cmd
+}
+
+// newEncryptPartCmd creates the command encrypting a part of a file of
+// a base backup, read from the standard input
+func newEncryptPartCmd() *cobra.Command {
+	var partNumber uint32
+
+	cmd := cobra.Command{
+		Use:           "encrypt-part",
+		Short:         "Encrypt a part of a base backup read from the standard input",
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			keyring, err := loadKeyring()
+			if err != nil {
+				return err
+			}
+
+			plaintext, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				log.Error(err, "while reading the base backup part")
+				return err
+			}
+
+			encrypted, err := keyring.EncryptPart(plaintext, partNumber)
+			if err != nil {
+				log.Error(err, "while encrypting the base backup part", "part", partNumber)
+				return err
+			}
+
+			_, err = os.Stdout.Write(encrypted)
+			return err
+		},
+	}
+
+	cmd.Flags().Uint32Var(&partNumber, "part", 1, "The number of the part, starting from 1")
+
+	return &cmd
+}
+
+// newDecryptCmd creates the command decrypting a file of a base backup,
+// read from the standard input
+func newDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:           "decrypt",
+		Short:         "Decrypt a file of a base backup read from the standard input",
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			keyring, err := loadKeyring()
+			if err != nil {
+				return err
+			}
+
+			output := bufio.NewWriter(os.Stdout)
+			if err := keyring.DecryptStream(output, os.Stdin); err != nil {
+				log.Error(err, "while decrypting the base backup")
+				return err
+			}
+
+			return output.Flush()
+		},
+	}
+}
+
+// loadKeyring loads the encryption keys from the environment
+func loadKeyring() (*encryption.Keyring, error) {
+	keyring, err := encryption.FromEnv(os.Environ())
+	if err != nil {
+		log.Error(err, "while loading the encryption keys")
+		return nil, err
+	}
+	if keyring == nil {
+		err := fmt.Errorf("no encryption key has been configured")
+		log.Error(err, "while loading the encryption keys")
+		return nil, err
+	}
+
+	return keyring, nil
+}
@@ -22,23 +22,33 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/http/pprof"
 	"time"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/fleetapi"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/multicache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 	// +kubebuilder:scaffold:imports
@@ -49,6 +59,32 @@ var (
 	setupLog = log.WithName("setup")
 )
 
+// capabilitiesDetectionBackoff is used to retry the detection of the
+// capabilities of the Kubernetes cluster at startup, before assuming
+// the ones that couldn't be detected are missing
+var capabilitiesDetectionBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// managedCustomResourceDefinitions are the CRDs whose stored objects are
+// migrated to the storage version when the operator starts. Keep the list
+// in the "Migration of the stored objects" section of
+// docs/src/installation_upgrade.md in sync
+var managedCustomResourceDefinitions = []string{
+	"backups.postgresql.cnpg.io",
+	"clusterimagecatalogs.postgresql.cnpg.io",
+	"clusters.postgresql.cnpg.io",
+	"databases.postgresql.cnpg.io",
+	"imagecatalogs.postgresql.cnpg.io",
+	"poolers.postgresql.cnpg.io",
+	"publications.postgresql.cnpg.io",
+	"scheduledbackups.postgresql.cnpg.io",
+	"subscriptions.postgresql.cnpg.io",
+}
+
 const (
 	// WebhookSecretName is the name of the secret where the certificates
 	// for the webhook server are stored
@@ -126,6 +162,15 @@ func RunController(
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		LeaderElectionReleaseOnCancel: true,
+		// The failover witness Leases are read only while deciding whether
+		// a failover can proceed, and they must never be stale. Endpoints and
+		// NetworkPolicies are read only when referenced by the pg_hba rules,
+		// and caching them would require watching the whole Kubernetes cluster
+		ClientDisableCacheFor: []client.Object{
+			&coordinationv1.Lease{},
+			&corev1.Endpoints{},
+			&networkingv1.NetworkPolicy{},
+		},
 	}
 
 	if configuration.Current.WatchNamespace != "" {
@@ -181,42 +226,81 @@ func RunController(
 		return err
 	}
 
-	// Detect if we are running under a system that implements OpenShift Security Context Constraints
-	if err = utils.DetectSecurityContextConstraints(discoveryClient); err != nil {
-		setupLog.Error(err, "unable to detect OpenShift Security Context Constraints presence")
+	// Retrieve the Kubernetes cluster system UID. The kube-system namespace
+	// may not be readable with namespaced RBAC, and the UID is only informative
+	if err = utils.DetectKubeSystemUID(ctx, kubeClient); err != nil {
+		if !apierrs.IsForbidden(err) {
+			setupLog.Error(err, "unable to retrieve the Kubernetes cluster system UID")
+			return err
+		}
+		setupLog.Info("Not allowed to retrieve the Kubernetes cluster system UID", "err", err.Error())
+	}
+
+	// The capabilities detected at startup are refreshed periodically and
+	// when the CRDs of the detected resources are created or deleted
+	capabilities := utils.NewCapabilitiesRegistry(
+		discoveryClient,
+		kubeClient,
+		time.Duration(configuration.Current.CapabilitiesDetectionInterval)*time.Second)
+	capabilities.SetOverrides(utils.ParseCapabilitiesOverrides(configuration.Current.CapabilityOverrides))
+	capabilities.DetectWithBackoff(ctx, capabilitiesDetectionBackoff)
+	if err = metrics.Registry.Register(newCapabilitiesDegradedGauge(capabilities)); err != nil {
+		setupLog.Error(err, "unable to register the capabilities detection metric")
+		return err
+	}
+	if err = mgr.Add(capabilities); err != nil {
+		setupLog.Error(err, "unable to add the capabilities registry")
 		return err
 	}
 
-	// Detect if we support SeccompProfile
-	if err = utils.DetectSeccompSupport(discoveryClient); err != nil {
-		setupLog.Error(err, "unable to detect SeccompProfile support")
+	setupLog.Info("Kubernetes system metadata",
+		"systemUID", utils.GetKubeSystemUID(),
+		"capabilities", capabilities.Get())
+
+	if err := ensurePKI(ctx, kubeClient, mgr.GetWebhookServer().CertDir); err != nil {
 		return err
 	}
 
-	// Retrieve the Kubernetes cluster system UID
-	if err = utils.DetectKubeSystemUID(ctx, kubeClient); err != nil {
-		setupLog.Error(err, "unable to retrieve the Kubernetes cluster system UID")
+	// The stored objects are migrated by the leader only, as soon as the
+	// manager is started. The operator can work even if the migration fails,
+	// i.e. when the CRDs cannot be updated with the configured RBAC
+	if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := utils.MigrateStoredVersions(ctx, kubeClient, managedCustomResourceDefinitions); err != nil {
+			setupLog.Error(err, "unable to migrate the stored versions of the CustomResourceDefinitions")
+		}
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add the stored versions migration")
 		return err
 	}
 
-	setupLog.Info("Kubernetes system metadata",
-		"systemUID", utils.GetKubeSystemUID(),
-		"haveSCC", utils.HaveSecurityContextConstraints(),
-		"haveSeccompProfile", utils.HaveSeccompSupport())
+	if err := ensureOperatorServiceMonitor(ctx, kubeClient, capabilities.Get().HaveServiceMonitor); err != nil {
+		// The operator works correctly even if its metrics are not scraped
+		setupLog.Error(err, "unable to reconcile the operator ServiceMonitor")
+	}
+	capabilities.AddListener(func(ctx context.Context, old, new utils.ClusterCapabilities) {
+		if old.HaveServiceMonitor || !new.HaveServiceMonitor {
+			return
+		}
+		if err := ensureOperatorServiceMonitor(ctx, kubeClient, true); err != nil {
+			setupLog.Error(err, "unable to reconcile the operator ServiceMonitor")
+		}
+	})
 
-	if err := ensurePKI(ctx, kubeClient, mgr.GetWebhookServer().CertDir); err != nil {
+	if err = setupCapabilitiesReconciler(ctx, mgr, kubeClient, capabilities); err != nil {
 		return err
 	}
 
-	if err = controllers.NewClusterReconciler(mgr, discoveryClient).SetupWithManager(ctx, mgr); err != nil {
+	if err = controllers.NewClusterReconciler(mgr, capabilities, WebhookSecretName).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		return err
 	}
 
 	if err = (&controllers.BackupReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-backup"),
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("cloudnative-pg-backup"),
+		Capabilities: capabilities,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Backup")
 		return err
@@ -232,14 +316,20 @@ func RunController(
 	}
 
 	if err = (&controllers.PoolerReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		Capabilities: capabilities,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pooler")
 		return err
 	}
 
+	if err = controllers.NewDatabaseReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Database")
+		return err
+	}
+
 	if err = (&apiv1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Cluster", "version", "v1")
 		return err
@@ -260,6 +350,31 @@ func RunController(
 		return err
 	}
 
+	if err = (&apiv1.Database{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Database", "version", "v1")
+		return err
+	}
+
+	if err = (&apiv1.Publication{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Publication", "version", "v1")
+		return err
+	}
+
+	if err = (&apiv1.Subscription{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Subscription", "version", "v1")
+		return err
+	}
+
+	if err = (&apiv1.ImageCatalog{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ImageCatalog", "version", "v1")
+		return err
+	}
+
+	if err = (&apiv1.ClusterImageCatalog{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterImageCatalog", "version", "v1")
+		return err
+	}
+
 	// Setup the handler used by the readiness and liveliness probe.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
@@ -273,6 +388,11 @@ func RunController(
 	//    deleted. In that case we could get a "Connection refused" error message.
 	mgr.GetWebhookServer().WebhookMux.HandleFunc("/readyz", readinessProbeHandler)
 
+	if configuration.Current.EnableFleetAPI {
+		setupLog.Info("Enabling the fleet API", "path", fleetapi.ClustersPath)
+		mgr.GetWebhookServer().WebhookMux.Handle(fleetapi.ClustersPath, fleetapi.NewHandler(mgr.GetClient()))
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -370,6 +490,128 @@ func ensurePKI(
 	return err
 }
 
+// setupCapabilitiesReconciler starts the controller detecting the capabilities
+// of the Kubernetes cluster when a CRD is created or deleted. Without the
+// permission to watch the CRDs, the capabilities are only detected periodically
+func setupCapabilitiesReconciler(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	kubeClient client.Client,
+	capabilities *utils.CapabilitiesRegistry,
+) error {
+	canWatchCRDs, err := utils.CanWatchCustomResourceDefinitions(ctx, kubeClient)
+	if err != nil {
+		setupLog.Error(err, "unable to detect the access to the CustomResourceDefinitions")
+		return err
+	}
+	if !canWatchCRDs {
+		setupLog.Info("Not allowed to watch the CustomResourceDefinitions, " +
+			"the capabilities of the Kubernetes cluster will only be detected periodically")
+		return nil
+	}
+
+	if err = (&controllers.CapabilitiesReconciler{
+		Capabilities: capabilities,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Capabilities")
+		return err
+	}
+
+	return nil
+}
+
+// newCapabilitiesDegradedGauge creates the metric reporting whether the
+// capabilities of the Kubernetes cluster couldn't be detected, and the
+// missing ones are being re-detected in background
+func newCapabilitiesDegradedGauge(capabilities *utils.CapabilitiesRegistry) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "capabilities_detection_degraded",
+		Help: "1 if the capabilities of the Kubernetes cluster couldn't be detected at startup " +
+			"and the operator is assuming the missing ones are not available, 0 otherwise",
+	}, func() float64 {
+		if capabilities.IsDegraded() {
+			return 1
+		}
+		return 0
+	})
+}
+
+// ensureOperatorServiceMonitor creates or patches the ServiceMonitor scraping
+// the metrics of the operator, together with the Service it uses, when
+// requested, and removes them otherwise
+func ensureOperatorServiceMonitor(
+	ctx context.Context,
+	kubeClient client.Client,
+	haveServiceMonitor bool,
+) error {
+	if configuration.Current.OperatorNamespace == "" || !haveServiceMonitor {
+		// We are not getting started via a k8s deployment, or
+		// the Prometheus Operator is not installed
+		return nil
+	}
+
+	expectedService := specs.CreateOperatorMetricsService(configuration.Current.OperatorNamespace)
+	service := &corev1.Service{}
+	err := ensureOperatorMetricsObject(ctx, kubeClient, "Service", expectedService, service, func() {
+		service.Spec.Ports = expectedService.Spec.Ports
+		service.Spec.Selector = expectedService.Spec.Selector
+	})
+	if err != nil {
+		return err
+	}
+
+	expectedServiceMonitor := specs.CreateOperatorServiceMonitor(configuration.Current.OperatorNamespace)
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	return ensureOperatorMetricsObject(ctx, kubeClient, "ServiceMonitor", expectedServiceMonitor, serviceMonitor, func() {
+		serviceMonitor.Spec = expectedServiceMonitor.Spec
+	})
+}
+
+// ensureOperatorMetricsObject creates or patches, using the passed function
+// to update the existing object, one of the objects used to scrape the
+// metrics of the operator, and removes it when the ServiceMonitor of the
+// operator is not enabled. The kind is only used in the logs
+func ensureOperatorMetricsObject(
+	ctx context.Context,
+	kubeClient client.Client,
+	kind string,
+	expected client.Object,
+	current client.Object,
+	update func(),
+) error {
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(expected), current)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if found && utils.IsReconciliationDisabled(&metav1.ObjectMeta{Annotations: current.GetAnnotations()}) {
+		setupLog.Info("Reconciliation loop disabled for the operator metrics object, leaving it untouched",
+			"kind", kind, "name", current.GetName())
+		return nil
+	}
+
+	switch {
+	case !configuration.Current.EnableOperatorServiceMonitor && !found:
+		return nil
+
+	case !configuration.Current.EnableOperatorServiceMonitor:
+		setupLog.Info("Deleting the operator metrics object", "kind", kind, "name", current.GetName())
+		return client.IgnoreNotFound(kubeClient.Delete(ctx, current))
+
+	case !found:
+		setupLog.Info("Creating the operator metrics object", "kind", kind, "name", expected.GetName())
+		return kubeClient.Create(ctx, expected)
+
+	default:
+		origObject := current.DeepCopyObject().(client.Object)
+		update()
+		return kubeClient.Patch(ctx, current, client.MergeFrom(origObject))
+	}
+}
+
 // readConfigMap reads the configMap and returns its content as map
 func readConfigMap(
 	ctx context.Context,
@@ -437,17 +679,12 @@ func readSecret(
 	return data, nil
 }
 
-// startPprofDebugServer exposes pprof debug server if POD_DEBUG env variable is set to 1
+// startPprofDebugServer exposes the pprof debug server on localhost
 func startPprofDebugServer(ctx context.Context) {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/debug/pprof/", pprof.Index)
-	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux := webserver.NewPprofServeMux()
 
 	pprofServer := http.Server{
-		Addr:              "0.0.0.0:6060",
+		Addr:              fmt.Sprintf("localhost:%d", url.OperatorPprofPort),
 		Handler:           mux,
 		ReadTimeout:       webserver.DefaultReadTimeout,
 		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debug implement the debug command subfeatures
+package debug
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:           "debug [cmd]",
+		Short:         "Runtime diagnostics subfeature",
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(newPprofCmd())
+
+	return &cmd
+}
+
+func newPprofCmd() *cobra.Command {
+	var port int
+	var seconds int
+
+	cmd := cobra.Command{
+		Use:   "pprof [profile]",
+		Short: "Writes a pprof profile or an execution trace of the running manager to the standard output",
+		Long: "Writes to the standard output the passed profile, like \"heap\", \"goroutine\", " +
+			"\"allocs\", \"profile\" (CPU) or \"trace\", reading it from the pprof endpoints " +
+			"of the manager running in this container",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return writeProfile(os.Stdout, port, args[0], seconds)
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", url.LocalPort, "The port of the pprof endpoints: "+
+		fmt.Sprintf("%d for the instance manager, %d for the operator", url.LocalPort, url.OperatorPprofPort))
+	cmd.Flags().IntVar(&seconds, "seconds", 0, "The duration of the CPU profile or of the execution "+
+		"trace. Defaults to the one of the endpoint")
+
+	return &cmd
+}
+
+// writeProfile downloads the passed profile, writing it to the passed writer
+func writeProfile(out io.Writer, port int, profile string, seconds int) error {
+	profileURL := url.Local(url.PathPprof+profile, port)
+	if seconds > 0 {
+		profileURL = fmt.Sprintf("%s?seconds=%d", profileURL, seconds)
+	}
+
+	// CPU profiles last 30 seconds unless specified otherwise
+	httpClient := &http.Client{Timeout: time.Duration(seconds)*time.Second + time.Minute}
+	resp, err := httpClient.Get(profileURL)
+	if err != nil {
+		return fmt.Errorf("while reading the %s profile: %w", profile, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("while reading the %s profile: %s: %s", profile, resp.Status, body)
+	}
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
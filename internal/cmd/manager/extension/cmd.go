@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package extension implements the "extension" command, used by the init
+// containers delivering the extensions to the PostgreSQL Pods
+package extension
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the "extension" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extension",
+		Short: "Extension management commands",
+	}
+
+	cmd.AddCommand(newCopyCmd())
+
+	return cmd
+}
+
+// newCopyCmd creates the "extension copy" command
+func newCopyCmd() *cobra.Command {
+	var sources []string
+	var extensions []string
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "copy --source [directory] --target [directory]",
+		Short: "Copies the content of the source directories into the target one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, source := range sources {
+				if err := copyDirectory(source, target, ""); err != nil {
+					log.Error(err, "Error while copying the extension files",
+						"source", source,
+						"target", target)
+					return err
+				}
+			}
+
+			for _, extension := range extensions {
+				source := filepath.Join(extension, "share")
+				if err := copyDirectory(source, target, filepath.Join(extension, "lib")); err != nil {
+					log.Error(err, "Error while copying the extension files",
+						"source", source,
+						"target", target)
+					return err
+				}
+			}
+
+			log.Info("Extension files copied", "sources", sources, "extensions", extensions, "target", target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&sources, "source", nil,
+		"The directory whose content is copied. Can be repeated, and the "+
+			"files of the following directories replace the ones of the previous ones")
+	cmd.Flags().StringArrayVar(&extensions, "extension", nil,
+		"The directory of an extension, whose share directory is copied after the sources, "+
+			"pointing the references to $libdir to its lib directory. Can be repeated")
+	cmd.Flags().StringVar(&target, "target", "", "The directory where the files are copied")
+	_ = cmd.MarkFlagRequired("target")
+
+	return cmd
+}
+
+// copyDirectory copies the content of the source directory into the target
+// one, preserving the symbolic links. A missing source directory is skipped,
+// as an extension may come without SQL files or without libraries. When a
+// library directory is passed, the references to $libdir of the control and
+// SQL files are rewritten to point to it
+func copyDirectory(source, target, libraryDirectory string) error {
+	if _, err := os.Stat(source); errors.Is(err, os.ErrNotExist) {
+		log.Info("Skipping missing source directory", "source", source)
+		return nil
+	}
+
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(target, relativePath)
+
+		switch {
+		case entry.IsDir():
+			return fileutils.EnsureDirectoryExist(destination)
+
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := fileutils.RemoveFile(destination); err != nil {
+				return err
+			}
+			return os.Symlink(link, destination)
+
+		case libraryDirectory != "" && isExtensionScript(path):
+			return copyExtensionScript(path, destination, libraryDirectory)
+
+		default:
+			return fileutils.CopyFile(path, destination)
+		}
+	})
+}
+
+// isExtensionScript checks whether the passed file is a control or an SQL
+// file of an extension
+func isExtensionScript(fileName string) bool {
+	extension := filepath.Ext(fileName)
+	return extension == ".control" || extension == ".sql"
+}
+
+// copyExtensionScript copies a control or an SQL file of an extension,
+// rewriting its references to $libdir. PostgreSQL expands $libdir to the
+// library directory of the installation, without looking into the
+// dynamic_library_path, which is where the libraries of the extensions
+// delivered by images and volumes are
+func copyExtensionScript(source, destination, libraryDirectory string) error {
+	content, err := fileutils.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(destination, rewriteLibdir(content, libraryDirectory), 0o644)
+	return err
+}
+
+// rewriteLibdir replaces the references to $libdir, like the one of the
+// module_pathname of the control files, with the passed directory
+func rewriteLibdir(content []byte, libraryDirectory string) []byte {
+	return bytes.ReplaceAll(content, []byte("$libdir/"), []byte(strings.TrimSuffix(libraryDirectory, "/")+"/"))
+}
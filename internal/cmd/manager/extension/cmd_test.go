@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extension files copy", func() {
+	var source, target string
+
+	BeforeEach(func() {
+		source = GinkgoT().TempDir()
+		target = GinkgoT().TempDir()
+
+		Expect(os.MkdirAll(filepath.Join(source, "lib"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(source, "lib", "vector.so.1"), []byte("library"), 0o600)).To(Succeed())
+		Expect(os.Symlink("vector.so.1", filepath.Join(source, "lib", "vector.so"))).To(Succeed())
+	})
+
+	It("copies the files and preserves the symbolic links", func() {
+		Expect(copyDirectory(source, target, "")).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(target, "lib", "vector.so.1"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("library"))
+
+		link, err := os.Readlink(filepath.Join(target, "lib", "vector.so"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(link).To(Equal("vector.so.1"))
+
+		// Copying again replaces the existing files
+		Expect(copyDirectory(source, target, "")).To(Succeed())
+	})
+
+	It("skips the missing source directories", func() {
+		Expect(copyDirectory(filepath.Join(source, "share"), target, "")).To(Succeed())
+		Expect(os.ReadDir(target)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Extension share directory copy", func() {
+	var extension, target string
+
+	BeforeEach(func() {
+		extension = GinkgoT().TempDir()
+		target = GinkgoT().TempDir()
+
+		// The layout of the share directory of pgvector
+		share := filepath.Join(extension, "share")
+		Expect(os.MkdirAll(share, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(share, "vector.control"), []byte(
+			"comment = 'vector data type and ivfflat and hnsw access methods'\n"+
+				"default_version = '0.7.0'\n"+
+				"module_pathname = '$libdir/vector'\n"+
+				"relocatable = true\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(share, "vector--0.7.0.sql"), []byte(
+			"CREATE FUNCTION vector_in(cstring, oid, integer) RETURNS vector\n"+
+				"\tAS 'MODULE_PATHNAME' LANGUAGE C IMMUTABLE STRICT PARALLEL SAFE;\n"+
+				"CREATE FUNCTION l2_distance(vector, vector) RETURNS float8\n"+
+				"\tAS '$libdir/vector' LANGUAGE C IMMUTABLE STRICT PARALLEL SAFE;\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(share, "README.md"), []byte("See $libdir/vector"), 0o600)).To(Succeed())
+	})
+
+	It("points the module_pathname of the control files to the library directory of the extension", func() {
+		libraryDirectory := filepath.Join(extension, "lib")
+		Expect(copyDirectory(filepath.Join(extension, "share"), target, libraryDirectory)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(target, "vector.control"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("module_pathname = '" + libraryDirectory + "/vector'\n"))
+		Expect(string(content)).To(ContainSubstring("default_version = '0.7.0'\n"))
+
+		content, err = os.ReadFile(filepath.Join(target, "vector--0.7.0.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("AS 'MODULE_PATHNAME'"))
+		Expect(string(content)).To(ContainSubstring("AS '" + libraryDirectory + "/vector'"))
+		Expect(string(content)).ToNot(ContainSubstring("$libdir"))
+
+		// Only the control and SQL files are rewritten
+		content, err = os.ReadFile(filepath.Join(target, "README.md"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("See $libdir/vector"))
+	})
+
+	It("keeps the references to $libdir of the files of the PostgreSQL installation", func() {
+		Expect(copyDirectory(filepath.Join(extension, "share"), target, "")).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(target, "vector.control"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("module_pathname = '$libdir/vector'"))
+	})
+})
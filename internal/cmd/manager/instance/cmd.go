@@ -28,6 +28,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/upgrade"
 )
 
 // NewCmd creates the "instance" command
@@ -46,6 +47,7 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(status.NewCmd())
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
+	cmd.AddCommand(upgrade.NewCmd())
 
 	return cmd
 }
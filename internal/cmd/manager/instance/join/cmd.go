@@ -55,10 +55,12 @@ func NewCmd() *cobra.Command {
 			instance.ClusterName = clusterName
 
 			info := postgres.InitInfo{
-				PgData:     pgData,
-				PgWal:      pgWal,
-				ParentNode: parentNode,
-				PodName:    podName,
+				ClusterName: clusterName,
+				Namespace:   namespace,
+				PgData:      pgData,
+				PgWal:       pgWal,
+				ParentNode:  parentNode,
+				PodName:     podName,
 			}
 
 			return joinSubCommand(ctx, instance, info)
@@ -113,6 +115,11 @@ func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postg
 
 	reconciler.RefreshSecrets(ctx, &cluster)
 
+	if err := info.RunStorageBenchmark(ctx, client, &cluster); err != nil {
+		log.Error(err, "Error while checking the storage")
+		return err
+	}
+
 	err = info.Join(&cluster)
 	if err != nil {
 		log.Error(err, "Error joining node")
@@ -45,6 +45,7 @@ func NewCmd() *cobra.Command {
 	var namespace string
 	var pgData string
 	var pgWal string
+	var podName string
 
 	cmd := &cobra.Command{
 		Use: "pgbasebackup",
@@ -60,6 +61,7 @@ func NewCmd() *cobra.Command {
 					Namespace:   namespace,
 					PgData:      pgData,
 					PgWal:       pgWal,
+					PodName:     podName,
 				},
 				client: client,
 			}
@@ -79,6 +81,8 @@ func NewCmd() *cobra.Command {
 		"the cluster and of the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
 	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the instance "+
+		"being cloned")
 
 	return cmd
 }
@@ -115,6 +119,11 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 			return err
 		}
 	}
+
+	if err := env.info.RunStorageBenchmark(ctx, env.client, &cluster); err != nil {
+		return err
+	}
+
 	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
 	if err != nil {
 		return err
@@ -33,6 +33,8 @@ func NewCmd() *cobra.Command {
 	var namespace string
 	var pgData string
 	var pgWal string
+	var podName string
+	var anonymizationSQLRefsFolder string
 
 	cmd := &cobra.Command{
 		Use:           "restore [flags]",
@@ -44,6 +46,10 @@ func NewCmd() *cobra.Command {
 				ClusterName: clusterName,
 				Namespace:   namespace,
 				PgData:      pgData,
+				PodName:     podName,
+				// if the value is empty, the anonymization scripts
+				// stored in Secrets and ConfigMaps are not executed
+				AnonymizationSQLRefsFolder: anonymizationSQLRefsFolder,
 			}
 
 			return restoreSubCommand(ctx, info)
@@ -56,17 +62,17 @@ func NewCmd() *cobra.Command {
 		"the cluster and the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
 	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the instance "+
+		"being restored")
+	cmd.Flags().StringVar(&anonymizationSQLRefsFolder, "anonymization-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"to anonymize the recovered data")
 
 	return cmd
 }
 
 func restoreSubCommand(ctx context.Context, info postgres.InitInfo) error {
-	err := info.VerifyPGData()
-	if err != nil {
-		return err
-	}
-
-	err = info.Restore(ctx)
+	err := info.Restore(ctx)
 	if err != nil {
 		log.Error(err, "Error while restoring a backup")
 		return err
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,7 +37,12 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/backupverifier"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/isolation"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/pluginstatus"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/storageforecast"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/walstreamer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -45,6 +51,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
 	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/witness"
 )
 
 var scheme = runtime.NewScheme()
@@ -112,6 +119,7 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		ClientDisableCacheFor: []client.Object{
 			&corev1.Secret{},
 			&corev1.ConfigMap{},
+			&coordinationv1.Lease{},
 		},
 		MetricsBindAddress: "0", // TODO: merge metrics to the manager one
 	})
@@ -138,6 +146,16 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 	}
 	postgresStartConditions = append(postgresStartConditions, reconciler.GetExecutedCondition())
 
+	if err = controller.NewPublicationReconciler(instance, mgr.GetClient()).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Publication")
+		return err
+	}
+
+	if err = controller.NewSubscriptionReconciler(instance, mgr.GetClient()).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Subscription")
+		return err
+	}
+
 	// postgres CSV logs handler (PGAudit too)
 	postgresLogPipe := logpipe.NewLogPipe()
 	if err := mgr.Add(postgresLogPipe); err != nil {
@@ -185,6 +203,36 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(witness.NewRenewer(instance, mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create failover witness renewer")
+		return err
+	}
+
+	if err = mgr.Add(isolation.NewChecker(instance, mgr.GetClient(), mgr.GetAPIReader())); err != nil {
+		setupLog.Error(err, "unable to create isolation checker")
+		return err
+	}
+
+	if err = mgr.Add(walstreamer.NewStreamer(instance, mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create WAL streamer")
+		return err
+	}
+
+	if err = mgr.Add(backupverifier.NewVerifier(instance, mgr.GetClient(), metricsServer.GetExporter())); err != nil {
+		setupLog.Error(err, "unable to create backup verifier")
+		return err
+	}
+
+	if err = mgr.Add(storageforecast.NewSampler(instance, mgr.GetClient(), metricsServer.GetExporter())); err != nil {
+		setupLog.Error(err, "unable to create storage sampler")
+		return err
+	}
+
+	if err = mgr.Add(pluginstatus.NewProber(instance)); err != nil {
+		setupLog.Error(err, "unable to create plugin status prober")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
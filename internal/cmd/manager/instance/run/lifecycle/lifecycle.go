@@ -24,7 +24,9 @@ import (
 	"os/exec"
 	"os/signal"
 	"syscall"
+	"time"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -126,9 +128,16 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				// resulting in a data corruption.
 				//
 				// This is why we are trying a smart shutdown for half-time
-				// of our stop delay, and then we proceed.
+				// of our stop delay, and then we proceed. The checkpoint
+				// requested in advance, when enabled, gets a quarter of it.
+				// The time spent by the pre-shutdown hooks is taken from both.
 				log.Info("Received termination signal", "signal", sig)
-				if err := tryShuttingDownSmartFast(i.instance.MaxStopDelay/2, i.instance); err != nil {
+				signalTime := time.Now()
+				i.instance.RunInstanceHooks(ctx, apiv1.InstanceHookEventPreShutdown)
+				hooksDuration := time.Since(signalTime)
+				requestShutdownCheckpoint(ctx, getRemainingStopDelay(i.instance.MaxStopDelay/4, hooksDuration), i.instance)
+				smartShutdownTimeout := getRemainingStopDelay(i.instance.MaxStopDelay/2, hooksDuration)
+				if err := tryShuttingDownSmartFast(smartShutdownTimeout, i.instance); err != nil {
 					log.Error(err, "error while shutting down instance, proceeding")
 				}
 				return nil
@@ -139,7 +148,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				log.Info("Received request for postgres", "req", req)
 
 				// We execute the requested operation
-				restartNeeded, err := i.handleInstanceCommandRequests(req)
+				restartNeeded, err := i.handleInstanceCommandRequests(ctx, req)
 				if err != nil {
 					log.Error(err, "while handling instance command request")
 				}
@@ -159,6 +168,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 // handleInstanceCommandRequests execute a command requested by the reconciliation
 // loop.
 func (i *PostgresLifecycle) handleInstanceCommandRequests(
+	ctx context.Context,
 	req postgres.InstanceCommand,
 ) (restartNeeded bool, err error) {
 	if i.instance.IsFenced() {
@@ -182,6 +192,7 @@ func (i *PostgresLifecycle) handleInstanceCommandRequests(
 		}
 		return false, err
 	case postgres.RestartSmartFast:
+		requestShutdownCheckpoint(ctx, i.instance.MaxStopDelay, i.instance)
 		return true, tryShuttingDownSmartFast(i.instance.MaxStopDelay, i.instance)
 	case postgres.ShutDownFastImmediate:
 		if err := tryShuttingDownFastImmediate(i.instance.MaxSwitchoverDelay, i.instance); err != nil {
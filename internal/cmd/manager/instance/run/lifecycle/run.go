@@ -31,7 +31,10 @@ import (
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 )
 
-var identifierStreamingReplicationUser = pgx.Identifier{apiv1.StreamingReplicationUser}.Sanitize()
+var (
+	identifierStreamingReplicationUser = pgx.Identifier{apiv1.StreamingReplicationUser}.Sanitize()
+	identifierMonitoringRole           = pgx.Identifier{apiv1.MonitoringRoleName}.Sanitize()
+)
 
 // runPostgresAndWait runs a goroutine which will run, configure and run Postgres itself,
 // returning any error via the returned channel
@@ -140,6 +143,12 @@ func configureInstancePermissions(instance *postgres.Instance) error {
 		return err
 	}
 
+	err = configureMonitoringRole(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -174,6 +183,28 @@ func configureStreamingReplicaUser(tx *sql.Tx) (bool, error) {
 	return hasSuperuser, nil
 }
 
+// configureMonitoringRole makes sure the role used to execute the monitoring
+// queries exists and is a member of pg_monitor
+func configureMonitoringRole(tx *sql.Tx) error {
+	var exists bool
+	row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = $1", apiv1.MonitoringRoleName)
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("while checking the monitoring role: %w", err)
+	}
+
+	if !exists {
+		if _, err := tx.Exec(fmt.Sprintf("CREATE ROLE %v NOLOGIN", identifierMonitoringRole)); err != nil {
+			return fmt.Errorf("CREATE ROLE %v error: %w", apiv1.MonitoringRoleName, err)
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("GRANT pg_monitor TO %v", identifierMonitoringRole)); err != nil {
+		return fmt.Errorf("GRANT pg_monitor TO %v error: %w", apiv1.MonitoringRoleName, err)
+	}
+
+	return nil
+}
+
 // configurePgRewindPrivileges ensures that the StreamingReplicationUser has enough rights to execute pg_rewind
 func configurePgRewindPrivileges(majorVersion int, hasSuperuser bool, tx *sql.Tx) error {
 	// We need the superuser bit for the streaming-replication user since pg_rewind in PostgreSQL <= 10
@@ -255,5 +286,11 @@ func verifyPgDataCoherence(ctx context.Context, instance *postgres.Instance) err
 		return err
 	}
 
+	// PostgreSQL 12 and newer refuse to start with a recovery.conf file,
+	// which may be found in an imported data directory
+	if _, err := postgres.MigrateLegacyRecoveryConfiguration(instance.PgData); err != nil {
+		return err
+	}
+
 	return nil
 }
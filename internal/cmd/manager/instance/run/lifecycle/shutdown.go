@@ -17,8 +17,10 @@ limitations under the License.
 package lifecycle
 
 import (
+	"context"
 	"errors"
 	"os/exec"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -72,3 +74,32 @@ func tryShuttingDownSmartFast(timeout int32, instance *postgres.Instance) error
 	}
 	return err
 }
+
+// getRemainingStopDelay gets what is left of the passed share of the stop
+// delay, in seconds, after the time already elapsed since the termination
+// signal. At least one second is always given to the shutdown
+func getRemainingStopDelay(share int32, elapsed time.Duration) int32 {
+	remaining := share - int32(elapsed/time.Second)
+	if remaining < 1 {
+		return 1
+	}
+	return remaining
+}
+
+// requestShutdownCheckpoint requests a checkpoint before a planned shutdown
+// of the instance, when enabled in the cluster, waiting for it up to the
+// given timeout. Errors are logged, as the shutdown can go on anyway
+func requestShutdownCheckpoint(ctx context.Context, timeout int32, instance *postgres.Instance) {
+	if !instance.ShutdownCheckpointEnabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	log.Info("Requesting a checkpoint before shutting down the PostgreSQL instance")
+	if err := instance.CheckpointBeforeShutdown(ctx); err != nil {
+		log.Warning("Error while requesting the checkpoint before the shutdown, proceeding",
+			"err", err)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements the "instance upgrade" subcommand of the operator
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+)
+
+// NewCmd creates the "upgrade" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the data directory to a new PostgreSQL major version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("missing subcommand")
+		},
+	}
+
+	cmd.AddCommand(newPrepareCmd())
+	cmd.AddCommand(newExecuteCmd())
+	cmd.AddCommand(newRecoverCmd())
+
+	return cmd
+}
+
+// newPrepareCmd creates the "upgrade prepare" command, running in the
+// image of the previous major version
+func newPrepareCmd() *cobra.Command {
+	var destination string
+
+	cmd := &cobra.Command{
+		Use:   "prepare [options]",
+		Short: "Copy the PostgreSQL installation to be upgraded",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := postgres.CopyBinariesForUpgrade(destination); err != nil {
+				log.Error(err, "Error while copying the PostgreSQL installation")
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&destination, "destination", postgres.OldBinariesDirectory,
+		"The directory where the PostgreSQL installation is copied")
+
+	return cmd
+}
+
+// newExecuteCmd creates the "upgrade execute" command, running in the
+// image of the new major version
+func newExecuteCmd() *cobra.Command {
+	var pgData string
+	var pgWal string
+	var method string
+	var initDBFlagsString string
+	var podName string
+	var clusterName string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "execute [options]",
+		Short: "Upgrade the data directory with pg_upgrade",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			initDBFlags, err := shellquote.Split(initDBFlagsString)
+			if err != nil {
+				log.Error(err, "Error while parsing initdb flags")
+				return err
+			}
+
+			instance := postgres.NewInstance()
+			instance.Namespace = namespace
+			instance.PodName = podName
+			instance.ClusterName = clusterName
+
+			info := postgres.UpgradeInfo{
+				PgData:        pgData,
+				PgWal:         pgWal,
+				Method:        apiv1.PgUpgradeMethod(method),
+				InitDBOptions: initDBFlags,
+			}
+
+			return upgradeSubCommand(ctx, instance, info)
+		},
+	}
+
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be upgraded")
+	cmd.Flags().StringVar(&method, "method", string(apiv1.PgUpgradeMethodLink),
+		"How pg_upgrade transfers the data files, one of link or copy")
+	cmd.Flags().StringVar(&initDBFlagsString, "initdb-flags", "", "The list of flags to be passed "+
+		"to initdb while creating the upgraded data directory")
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of this pod, to "+
+		"be checked against the cluster state")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and of the Pod in k8s")
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of "+
+		"the current cluster in k8s, used to download TLS certificates")
+
+	return cmd
+}
+
+// newRecoverCmd creates the "upgrade recover" command, running in the
+// image of the new major version when the upgrade job failed
+func newRecoverCmd() *cobra.Command {
+	var pgData string
+	var pgWal string
+	var terminationLog string
+
+	cmd := &cobra.Command{
+		Use:   "recover [options]",
+		Short: "Complete or roll back an interrupted upgrade of the data directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := postgres.UpgradeInfo{
+				PgData: pgData,
+				PgWal:  pgWal,
+			}
+
+			result, err := info.Recover()
+			if err != nil {
+				log.Error(err, "Error while recovering the interrupted upgrade")
+				return err
+			}
+
+			// The operator reads the outcome from the termination message
+			log.Info("Recovered the interrupted upgrade", "result", result)
+			return os.WriteFile(terminationLog, []byte(result), 0o600)
+		},
+	}
+
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA being upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL being upgraded")
+	cmd.Flags().StringVar(&terminationLog, "termination-log", corev1.TerminationMessagePathDefault,
+		"The file where the outcome of the recovery is written")
+
+	return cmd
+}
+
+func upgradeSubCommand(ctx context.Context, instance *postgres.Instance, info postgres.UpgradeInfo) error {
+	client, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		log.Error(err, "Error creating Kubernetes client")
+		return err
+	}
+
+	metricServer, err := metricserver.New(instance)
+	if err != nil {
+		return err
+	}
+
+	// The configuration of the old cluster refers to the crypto
+	// material, which is needed by pg_upgrade to start it
+	reconciler := controller.NewInstanceReconciler(instance, client, metricServer)
+
+	var cluster apiv1.Cluster
+	err = reconciler.GetClient().Get(ctx,
+		ctrl.ObjectKey{Namespace: instance.Namespace, Name: instance.ClusterName},
+		&cluster)
+	if err != nil {
+		log.Error(err, "Error while getting cluster")
+		return err
+	}
+
+	reconciler.RefreshSecrets(ctx, &cluster)
+
+	if err := info.Upgrade(); err != nil {
+		log.Error(err, "Error while upgrading the data directory")
+		return err
+	}
+
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -95,7 +96,7 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 		"version", versions.Version,
 		"build", versions.Info)
 
-	if err = startWebServer(); err != nil {
+	if err = startWebServer(poolerNamespacedName); err != nil {
 		return fmt.Errorf("while starting the web server: %w", err)
 	}
 
@@ -173,8 +174,10 @@ func registerSignalHandler(reconciler *controller.PgBouncerReconciler, command *
 
 // startWebServer start the web server for handling probes given
 // a certain PostgreSQL instance
-func startWebServer() error {
-	if err := metricsserver.Setup(); err != nil {
+func startWebServer(poolerNamespacedName types.NamespacedName) error {
+	metricsServiceHost := fmt.Sprintf("%s%s.%s.svc",
+		poolerNamespacedName.Name, apiv1.ServiceMetricsSuffix, poolerNamespacedName.Namespace)
+	if err := metricsserver.Setup(metricsServiceHost); err != nil {
 		return err
 	}
 
@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupcatalog implement the backup-catalog command
+package backupcatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var podName string
+
+	cmd := cobra.Command{
+		Use:           "backup-catalog",
+		Short:         "Prints the catalog of the backups in the object store, in JSON format",
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return run(podName)
+		},
+	}
+
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the "+
+		"current pod in k8s")
+
+	return &cmd
+}
+
+func run(podName string) error {
+	cluster, err := cacheClient.GetCluster()
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	serverName, caEnv, barmanConfiguration, err := walrestore.GetRecoverConfiguration(cluster, podName)
+	if err != nil {
+		return fmt.Errorf("while getting the object store configuration: %w", err)
+	}
+	if barmanConfiguration.ServerName != "" {
+		serverName = barmanConfiguration.ServerName
+	}
+
+	env, err := cacheClient.GetEnv(cache.WALRestoreKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+
+	// The last value of a duplicated variable is the one being used
+	backupList, err := barman.GetBackupList(barmanConfiguration, serverName, append(env, caEnv...))
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(backupList)
+}
@@ -20,6 +20,8 @@ package show
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show/backupcatalog"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show/missingwals"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show/walarchivequeue"
 )
 
@@ -32,6 +34,8 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(walarchivequeue.NewCmd())
+	cmd.AddCommand(backupcatalog.NewCmd())
+	cmd.AddCommand(missingwals.NewCmd())
 
 	return &cmd
 }
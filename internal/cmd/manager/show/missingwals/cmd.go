@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package missingwals implement the missing-wals command
+package missingwals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/backupverifier"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// pgControldataWALSegmentSize is the key of the pg_controldata output
+// containing the size of the WAL segments
+const pgControldataWALSegmentSize = "Bytes per WAL segment"
+
+// fetchDirectory is where the WAL files are fetched while being checked
+var fetchDirectory = path.Join(postgresutils.ScratchDataDirectory, "missing-wals")
+
+// Result is the list of the WAL files that cannot be fetched from the archive
+type Result struct {
+	// The WAL files that are not in the archive
+	Missing []string `json:"missing,omitempty"`
+
+	// The WAL files that are in the archive, but cannot be fetched
+	// or whose content is not valid
+	Corrupted []string `json:"corrupted,omitempty"`
+}
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var podName, pgData, from, to string
+	var maxParallel int
+
+	cmd := cobra.Command{
+		Use: "missing-wals --from [WAL] --to [WAL]",
+		Short: "Prints the WAL files between the passed ones, both included, that cannot be " +
+			"fetched from the object store, in JSON format",
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return run(cobraCmd.Context(), podName, pgData, from, to, maxParallel)
+		},
+	}
+
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the "+
+		"current pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA of the instance, "+
+		"used to detect the size of the WAL segments")
+	cmd.Flags().StringVar(&from, "from", "", "The first WAL file to be checked")
+	cmd.Flags().StringVar(&to, "to", "", "The last WAL file to be checked")
+	cmd.Flags().IntVar(&maxParallel, "max-parallel", 4, "The maximum number of WAL files "+
+		"fetched at the same time")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return &cmd
+}
+
+func run(ctx context.Context, podName, pgData, from, to string, maxParallel int) error {
+	cluster, err := cacheClient.GetCluster()
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	serverName, caEnv, barmanConfiguration, err := walrestore.GetRecoverConfiguration(cluster, podName)
+	if err != nil {
+		return fmt.Errorf("while getting the object store configuration: %w", err)
+	}
+	if barmanConfiguration.ServerName != "" {
+		serverName = barmanConfiguration.ServerName
+	}
+
+	env, err := cacheClient.GetEnv(cache.WALRestoreKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+	// The last value of a duplicated variable is the one being used
+	env = append(env, caEnv...)
+
+	segmentSize := getWALSegmentSize(pgData)
+	walNames, err := backupverifier.GetWALRange(from, to, segmentSize)
+	if err != nil {
+		return fmt.Errorf("while computing the WAL files to be checked: %w", err)
+	}
+
+	options, err := walrestore.BarmanCloudWalRestoreOptions(barmanConfiguration, serverName)
+	if err != nil {
+		return fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
+	}
+
+	if err := os.MkdirAll(fetchDirectory, 0o700); err != nil {
+		return fmt.Errorf("while creating the directory of the fetched WAL files: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(fetchDirectory)
+	}()
+
+	walRestorer, err := restorer.New(ctx, cluster, env, fetchDirectory)
+	if err != nil {
+		return err
+	}
+
+	var result Result
+	result.Missing, result.Corrupted = backupverifier.FetchWALs(
+		ctx, walRestorer, fetchDirectory, walNames, options, segmentSize, maxParallel)
+
+	return json.NewEncoder(os.Stdout).Encode(result)
+}
+
+// getWALSegmentSize gets the size of the WAL segments of the instance,
+// falling back to the default one when it cannot be detected
+func getWALSegmentSize(pgData string) int64 {
+	instance := postgres.NewInstance()
+	instance.PgData = pgData
+	controlData, err := instance.GetPgControldata()
+	if err != nil {
+		return postgresutils.DefaultWALSegmentSize
+	}
+
+	segmentSize, err := strconv.ParseInt(controlData[pgControldataWALSegmentSize], 10, 64)
+	if err != nil || segmentSize <= 0 {
+		return postgresutils.DefaultWALSegmentSize
+	}
+
+	return segmentSize
+}
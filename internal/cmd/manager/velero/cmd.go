@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package velero implement the "controller velero" command, run by the
+// Velero backup hooks
+package velero
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd create a new cobra command
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "velero",
+		Short: "Hooks run by Velero while backing up the instance",
+	}
+
+	cmd.AddCommand(newHookCmd("pre-backup", url.PathVeleroPreBackup))
+	cmd.AddCommand(newHookCmd("post-backup", url.PathVeleroPostBackup))
+
+	return &cmd
+}
+
+// newHookCmd creates the command invoking the given hook of the
+// instance manager
+func newHookCmd(name, path string) *cobra.Command {
+	return &cobra.Command{
+		Use:  name,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hookURL := url.Local(path, url.LocalPort)
+			resp, err := http.Get(hookURL) //nolint:gosec
+			if err != nil {
+				log.Error(err, "Error while running the Velero hook", "hookURL", hookURL)
+				return err
+			}
+
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					log.Error(err, "Can't close the connection",
+						"hookURL", hookURL,
+						"statusCode", resp.StatusCode,
+					)
+				}
+			}()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				log.Error(err, "Error while reading the Velero hook response body",
+					"hookURL", hookURL,
+					"statusCode", resp.StatusCode,
+				)
+				return err
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				log.Info(
+					"Error while running the Velero hook",
+					"hookURL", hookURL,
+					"statusCode", resp.StatusCode,
+					"body", string(body),
+				)
+				return fmt.Errorf("invalid status code: %v", resp.StatusCode)
+			}
+
+			_, err = os.Stderr.Write(body)
+			return err
+		},
+	}
+}
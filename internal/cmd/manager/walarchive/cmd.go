@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
@@ -39,8 +40,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/bucket"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -51,6 +55,10 @@ const (
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-archive-spool"
 )
 
+// ErrWalArchivingPaused is returned to PostgreSQL when the WAL archiving
+// has been paused by the user, that will make it retry later
+var ErrWalArchivingPaused = errors.New("WAL archiving has been paused by the user")
+
 // NewCmd creates the new cobra command
 func NewCmd() *cobra.Command {
 	var podName string
@@ -79,6 +87,11 @@ func NewCmd() *cobra.Command {
 			}
 
 			err = run(ctx, podName, pgData, args, typedClient)
+			if errors.Is(err, ErrWalArchivingPaused) {
+				contextLog.Info("WAL archiving paused, the WAL file will be archived later",
+					"walName", args[0])
+				return err
+			}
 			if err != nil {
 				contextLog.Error(err, logErrorMessage)
 				return err
@@ -106,7 +119,8 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	pluginConfiguration := cluster.GetWALArchiverPlugin()
+	if pluginConfiguration == nil && (cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil) {
 		// Backup not configured, skipping WAL
 		contextLog.Info("Backup not configured, skip WAL archiving",
 			"walName", walName,
@@ -129,6 +143,16 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		}
 	}
 
+	if utils.IsWalArchivingPaused(&cluster.ObjectMeta) {
+		if err := checkWalArchivingPause(ctx, cluster, client, pgData, walName); err != nil {
+			return err
+		}
+	}
+
+	if pluginConfiguration != nil {
+		return archiveWithPlugin(ctx, cluster, client, pluginConfiguration.Name, podName, pgData, walName)
+	}
+
 	maxParallel := 1
 	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
 		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
@@ -164,14 +188,18 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 	// Step 3: gather the WAL files names to archive
 	walFilesList := gatherWALFilesToArchive(ctx, walName, maxParallel)
 
-	// Step 4: Check if the archive location is safe to perform archiving
+	// Step 4: Create the bucket if requested, and check if the archive
+	// location is safe to perform archiving
+	if err := createBucket(ctx, cluster, walArchiver, client, env, pgData); err != nil {
+		return err
+	}
 	if utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
 		if err := checkWalArchive(ctx, cluster, walArchiver, client, pgData); err != nil {
 			return err
 		}
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
+	options, err := BarmanCloudWalArchiveOptions(cluster, cluster.Name)
 	if err != nil {
 		log.Error(err, "while getting barman-cloud-wal-archive options")
 		condition := metav1.Condition{
@@ -205,6 +233,14 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
 		Message: "Continuous archiving is working",
 	}
+	if walStatus[0].Err != nil {
+		condition = metav1.Condition{
+			Type:    string(apiv1.ConditionContinuousArchiving),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(archiveFailureReason(walStatus[0].Err)),
+			Message: walStatus[0].Err.Error(),
+		}
+	}
 	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
 		log.Error(errCond, "Error while updating wal archiving condition (wal archiving succeeded)")
 	}
@@ -216,6 +252,147 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 	return walStatus[0].Err
 }
 
+// archiveWithPlugin archives the WAL file requested by PostgreSQL with the
+// plugin declared as the WAL archiver of the cluster
+func archiveWithPlugin(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.WithWatch,
+	pluginName string,
+	podName string,
+	pgData string,
+	walName string,
+) error {
+	contextLog := log.FromContext(ctx)
+	startTime := time.Now()
+
+	walPath := walName
+	if !filepath.IsAbs(walPath) {
+		walPath = path.Join(pgData, walName)
+	}
+
+	err := func() error {
+		pluginClient, err := plugin.Connect(ctx, pluginName)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = pluginClient.Close()
+		}()
+
+		_, err = pluginClient.WAL.Archive(ctx, &pluginapi.ArchiveWALRequest{
+			Cluster:        plugin.NewClusterInfo(cluster.Namespace, cluster.Name, podName),
+			Parameters:     cluster.GetPluginParameters(pluginName, nil),
+			SourceFileName: walPath,
+			ServerName:     cluster.Name,
+		})
+		return err
+	}()
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		Message: "Continuous archiving is working",
+	}
+	if err != nil {
+		err = fmt.Errorf("while archiving the WAL file with the plugin %s: %w", pluginName, err)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonContinuousArchivingFailing)
+		condition.Message = err.Error()
+	}
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		log.Error(errCond, "Error while updating wal archiving condition")
+	}
+	if err != nil {
+		return err
+	}
+
+	contextLog.Info("Archived WAL file",
+		"walName", walName,
+		"plugin", pluginName,
+		"startTime", startTime,
+		"totalTime", time.Since(startTime))
+	return nil
+}
+
+// checkWalArchivingPause returns ErrWalArchivingPaused when the WAL
+// archiving has been paused by the user, unless the size of the WAL files
+// waiting to be archived reached the allowed maximum. In that case the
+// archiving is resumed, to not fill the volume storing the WAL files
+func checkWalArchivingPause(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.WithWatch,
+	pgData string,
+	walName string,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingPaused),
+		Message: "WAL archiving has been paused by the user",
+	}
+
+	maxSize, err := cluster.GetWalArchivingPauseMaxSize()
+	if err != nil {
+		condition.Reason = string(apiv1.ConditionReasonContinuousArchivingFailing)
+		condition.Message = err.Error()
+		if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+			log.Error(errCond, "Error updating wal archiving condition (wal archiving paused)")
+		}
+		return err
+	}
+
+	if maxSize != nil {
+		pendingSize, err := getPendingWALSize(pgData, walName)
+		if err != nil {
+			return fmt.Errorf("while computing the size of the WAL files waiting to be archived: %w", err)
+		}
+		if pendingSize >= maxSize.Value() {
+			contextLog.Warning("The WAL files waiting to be archived reached the maximum size "+
+				"allowed while the WAL archiving is paused, resuming it",
+				"pendingSize", pendingSize,
+				"maxSize", maxSize.String())
+			return nil
+		}
+	}
+
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		log.Error(errCond, "Error updating wal archiving condition (wal archiving paused)")
+	}
+	return ErrWalArchivingPaused
+}
+
+// getPendingWALSize estimates the size of the WAL files waiting to be
+// archived, given the size of the one requested by PostgreSQL, as each
+// WAL segment has the same size
+func getPendingWALSize(pgData string, walName string) (int64, error) {
+	entries, err := os.ReadDir(path.Join(pgData, "pg_wal", "archive_status"))
+	if err != nil {
+		return 0, err
+	}
+	var ready int64
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".ready") {
+			ready++
+		}
+	}
+
+	walPath := walName
+	if !filepath.IsAbs(walPath) {
+		walPath = path.Join(pgData, walName)
+	}
+	info, err := os.Stat(walPath)
+	if err != nil {
+		return 0, err
+	}
+
+	return ready * info.Size(), nil
+}
+
 // gatherWALFilesToArchive reads from the archived status the list of WAL files
 // that can be archived in parallel way.
 // `requestedWALFile` is the name of the file whose archiving was requested by
@@ -289,7 +466,9 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 	return walList
 }
 
-func barmanCloudWalArchiveOptions(
+// BarmanCloudWalArchiveOptions builds the options to be passed to
+// barman-cloud-wal-archive to archive a WAL file of the passed cluster
+func BarmanCloudWalArchiveOptions(
 	cluster *apiv1.Cluster,
 	clusterName string,
 ) ([]string, error) {
@@ -383,6 +562,38 @@ func checkWalArchive(ctx context.Context,
 	if err := walArchiver.CheckWalArchiveDestination(ctx, checkWalOptions); err != nil {
 		log.Error(err, "while barman-cloud-check-wal-archive")
 		// Update the condition if needed.
+		condition := metav1.Condition{
+			Type:    string(apiv1.ConditionContinuousArchiving),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(archiveFailureReason(err)),
+			Message: err.Error(),
+		}
+		if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+			log.Error(errCond, "Error changing wal archiving condition (wal archiving failed)")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// createBucket creates the bucket of the object store, when requested by
+// the user, before the first WAL file is archived
+func createBucket(ctx context.Context,
+	cluster *apiv1.Cluster,
+	walArchiver *archiver.WALArchiver,
+	client client.WithWatch,
+	env []string,
+	pgData string,
+) error {
+	if !cluster.Spec.Backup.BarmanObjectStore.CreateBucket ||
+		!walArchiver.IsCheckWalArchiveFlagFilePresent(ctx, pgData) {
+		return nil
+	}
+
+	err := bucket.Ensure(ctx, cluster.Spec.Backup.BarmanObjectStore, cluster.Spec.Backup.RetentionPolicy, env)
+	if err != nil {
+		log.Error(err, "while creating the bucket")
 		condition := metav1.Condition{
 			Type:    string(apiv1.ConditionContinuousArchiving),
 			Status:  metav1.ConditionFalse,
@@ -397,3 +608,18 @@ func checkWalArchive(ctx context.Context,
 
 	return nil
 }
+
+// barmanConnectivityErrorExitCode is the exit code used by the barman-cloud
+// commands when the connection to the cloud provider failed
+const barmanConnectivityErrorExitCode = 2
+
+// archiveFailureReason returns the reason to be used in the continuous
+// archiving condition for the passed barman-cloud error
+func archiveFailureReason(err error) apiv1.ConditionReason {
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() == barmanConnectivityErrorExitCode {
+		return apiv1.ConditionReasonArchiveUnreachable
+	}
+
+	return apiv1.ConditionReasonContinuousArchivingFailing
+}
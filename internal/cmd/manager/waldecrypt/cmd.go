@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package waldecrypt implement the "wal-decrypt" command, decrypting the
+// WAL files restored by barman-cloud-wal-restore during the recovery
+// bootstrap
+package waldecrypt
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the "wal-decrypt" subcommand
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:           "wal-decrypt [path]",
+		Short:         "Decrypt in place a WAL file restored from the object store",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			keyring, err := encryption.FromEnv(os.Environ())
+			if err != nil {
+				log.Error(err, "while loading the WAL encryption keys")
+				return err
+			}
+
+			if err := keyring.DecryptFile(args[0]); err != nil {
+				log.Error(err, "while decrypting the WAL file", "path", args[0])
+				_ = os.Remove(args[0])
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return &cmd
+}
@@ -53,11 +53,17 @@ const (
 	// SpoolDirectory is the directory where we spool the WAL files that
 	// were pre-archived in parallel
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-restore-spool"
+
+	// partialWALSuffix is the suffix of the partial WAL segments uploaded
+	// by the WAL streamer
+	partialWALSuffix = ".partial"
 )
 
 // NewCmd creates a new cobra command
 func NewCmd() *cobra.Command {
 	var podName string
+	var pluginName string
+	var serverName string
 
 	cmd := cobra.Command{
 		Use:           "wal-restore [name]",
@@ -66,7 +72,12 @@ func NewCmd() *cobra.Command {
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			contextLog := log.WithName("wal-restore")
 			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
-			err := run(ctx, podName, args)
+			var err error
+			if pluginName != "" {
+				err = runWithPlugin(ctx, podName, pluginName, serverName, args)
+			} else {
+				err = run(ctx, podName, args)
+			}
 			if err == nil {
 				return nil
 			}
@@ -93,6 +104,10 @@ func NewCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the "+
 		"current pod in k8s")
+	cmd.Flags().StringVar(&pluginName, "plugin", "", "The name of the plugin restoring "+
+		"the WAL files during the recovery of the cluster")
+	cmd.Flags().StringVar(&serverName, "server-name", "", "The name of the server whose "+
+		"WAL files are restored by the plugin")
 
 	return &cmd
 }
@@ -111,6 +126,10 @@ func run(ctx context.Context, podName string, args []string) error {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
+	if pluginConfiguration := getPluginRecoverConfiguration(cluster, podName); pluginConfiguration != nil {
+		return restoreWithPlugin(ctx, cluster, podName, pluginConfiguration, walName, destinationPath)
+	}
+
 	recoverClusterName, recoverEnv, barmanConfiguration, err := GetRecoverConfiguration(cluster, podName)
 	if errors.Is(err, ErrNoBackupConfigured) {
 		// Backup not configured, skipping WAL
@@ -125,7 +144,7 @@ func run(ctx context.Context, podName string, args []string) error {
 		return fmt.Errorf("while getting recover configuration: %w", err)
 	}
 
-	options, err := barmanCloudWalRestoreOptions(
+	options, err := BarmanCloudWalRestoreOptions(
 		barmanConfiguration, recoverClusterName)
 	if err != nil {
 		return fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
@@ -185,6 +204,20 @@ func run(ctx context.Context, podName string, args []string) error {
 	downloadStartTime := time.Now()
 	walStatus := walRestorer.RestoreList(ctx, walFilesList, destinationPath, options)
 
+	// When the requested WAL file has not been archived yet, an instance
+	// that cannot stream from a primary can use the partial WAL segment,
+	// if the object store is receiving it from the WAL streamer
+	if errors.Is(walStatus[0].Err, restorer.ErrWALNotFound) &&
+		isPartialWALRestoreAllowed(barmanConfiguration, cluster, podName, walName) {
+		if err := walRestorer.Restore(walName+partialWALSuffix, destinationPath, options); err == nil {
+			contextLog.Info("Restored partial WAL file",
+				"walName", walName,
+				"currentPrimary", cluster.Status.CurrentPrimary,
+				"targetPrimary", cluster.Status.TargetPrimary)
+			return nil
+		}
+	}
+
 	// We return immediately if the first WAL has errors, because the first WAL
 	// is the one that PostgreSQL has requested to restore.
 	// The failure has already been logged in walRestorer.RestoreList method
@@ -341,7 +374,9 @@ func gatherWALFilesToRestore(walName string, parallel int) (walList []string, er
 	return walList, err
 }
 
-func barmanCloudWalRestoreOptions(
+// BarmanCloudWalRestoreOptions builds the options to be passed to
+// barman-cloud-wal-restore to fetch a WAL file of the passed server
+func BarmanCloudWalRestoreOptions(
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
@@ -396,3 +431,29 @@ func isStreamingAvailable(cluster *apiv1.Cluster, podName string) bool {
 	// Primary, we do not replicate from nobody
 	return false
 }
+
+// isPartialWALRestoreAllowed checks if the partial segment can be restored
+// in place of the requested WAL file. This is allowed only when the object
+// store is receiving the streamed WAL and this instance cannot stream it
+// from a primary, as a replica could otherwise diverge after a failover.
+// The partial segments of the timelines preceding the current one are the
+// tail of a history abandoned by a promotion, and are never restored
+func isPartialWALRestoreAllowed(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	cluster *apiv1.Cluster,
+	podName string,
+	walName string,
+) bool {
+	if !configuration.IsWalStreamingEnabled() ||
+		!postgres.IsWALFile(walName) ||
+		isStreamingAvailable(cluster, podName) {
+		return false
+	}
+
+	segment, err := postgres.SegmentFromName(walName)
+	if err != nil {
+		return false
+	}
+
+	return int(segment.Tli) >= cluster.Status.TimelineID
+}
@@ -108,3 +108,107 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function isPartialWALRestoreAllowed", func() {
+	streamingConfiguration := &apiv1.BarmanObjectStoreConfiguration{
+		Wal: &apiv1.WalBackupConfiguration{
+			Streaming: &apiv1.WalStreamingConfiguration{Enabled: true},
+		},
+	}
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "primaryPod",
+		},
+	}
+
+	It("allows the primary to restore the partial WAL segment", func() {
+		Expect(isPartialWALRestoreAllowed(
+			streamingConfiguration, cluster, "primaryPod", "000000010000000000000003")).To(BeTrue())
+	})
+
+	It("doesn't allow a replica to restore the partial WAL segment", func() {
+		Expect(isPartialWALRestoreAllowed(
+			streamingConfiguration, cluster, "replicaPod", "000000010000000000000003")).To(BeFalse())
+	})
+
+	It("doesn't restore the partial segment of files that are not WAL segments", func() {
+		Expect(isPartialWALRestoreAllowed(
+			streamingConfiguration, cluster, "primaryPod", "00000002.history")).To(BeFalse())
+	})
+
+	It("doesn't restore the partial segment of a timeline preceding the current one", func() {
+		promotedCluster := cluster.DeepCopy()
+		promotedCluster.Status.TimelineID = 2
+		Expect(isPartialWALRestoreAllowed(
+			streamingConfiguration, promotedCluster, "primaryPod", "000000010000000000000003")).To(BeFalse())
+		Expect(isPartialWALRestoreAllowed(
+			streamingConfiguration, promotedCluster, "primaryPod", "000000020000000000000003")).To(BeTrue())
+	})
+
+	It("doesn't restore the partial segment when the WAL streaming is not enabled", func() {
+		Expect(isPartialWALRestoreAllowed(
+			&apiv1.BarmanObjectStoreConfiguration{}, cluster, "primaryPod", "000000010000000000000003")).To(BeFalse())
+	})
+})
+
+var _ = Describe("Function getPluginRecoverConfiguration", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "primaryPod",
+			},
+			Spec: apiv1.ClusterSpec{
+				Plugins: []apiv1.PluginConfiguration{
+					{
+						Name:          "pgbackrest",
+						Image:         "pgbackrest:latest",
+						IsWALArchiver: true,
+						Parameters:    map[string]string{"repository": "repo1"},
+					},
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "clusterSource",
+						PluginConfiguration: &apiv1.BackupPluginConfiguration{
+							Name:       "pgbackrest",
+							Parameters: map[string]string{"repository": "repo2"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("uses the WAL archiver of the cluster", func() {
+		configuration := getPluginRecoverConfiguration(cluster, "replicaPod")
+		Expect(configuration).ToNot(BeNil())
+		Expect(configuration.pluginName).To(Equal("pgbackrest"))
+		Expect(configuration.serverName).To(Equal(cluster.Name))
+		Expect(configuration.parameters).To(HaveKeyWithValue("repository", "repo1"))
+	})
+
+	It("uses the source of the designated primary of a replica cluster", func() {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
+			Enabled: true,
+			Source:  "clusterSource",
+		}
+		configuration := getPluginRecoverConfiguration(cluster, "primaryPod")
+		Expect(configuration).ToNot(BeNil())
+		Expect(configuration.serverName).To(Equal("clusterSource"))
+		Expect(configuration.parameters).To(HaveKeyWithValue("repository", "repo2"))
+	})
+
+	It("is nil when the WAL files are not restored by a plugin", func() {
+		cluster.Spec.Plugins[0].IsWALArchiver = false
+		Expect(getPluginRecoverConfiguration(cluster, "replicaPod")).To(BeNil())
+
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
+			Enabled: true,
+			Source:  "clusterSource",
+		}
+		cluster.Spec.ExternalClusters[0].PluginConfiguration = nil
+		Expect(getPluginRecoverConfiguration(cluster, "primaryPod")).To(BeNil())
+	})
+})
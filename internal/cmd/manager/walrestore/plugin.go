@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+)
+
+// pluginRecoverConfiguration is the plugin restoring the WAL files
+// for an instance, with the parameters it is called with
+type pluginRecoverConfiguration struct {
+	pluginName string
+	serverName string
+	parameters map[string]string
+}
+
+// getPluginRecoverConfiguration gets the plugin restoring the WAL files
+// for the passed instance, or nil when the WAL files are not restored by
+// a plugin. The designated primary of a replica cluster restores them
+// from its source, every other instance from the archive of the cluster
+func getPluginRecoverConfiguration(cluster *apiv1.Cluster, podName string) *pluginRecoverConfiguration {
+	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
+		externalCluster, found := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+		if !found || externalCluster.PluginConfiguration == nil {
+			return nil
+		}
+
+		pluginName := externalCluster.PluginConfiguration.Name
+		return &pluginRecoverConfiguration{
+			pluginName: pluginName,
+			serverName: externalCluster.GetServerName(),
+			parameters: cluster.GetPluginParameters(pluginName, externalCluster.PluginConfiguration.Parameters),
+		}
+	}
+
+	if walArchiver := cluster.GetWALArchiverPlugin(); walArchiver != nil {
+		return &pluginRecoverConfiguration{
+			pluginName: walArchiver.Name,
+			serverName: cluster.Name,
+			parameters: cluster.GetPluginParameters(walArchiver.Name, nil),
+		}
+	}
+
+	return nil
+}
+
+// runWithPlugin restores a WAL file with the passed plugin during the
+// recovery of a cluster. The recovery job has no instance manager to get
+// the cluster from, so it is loaded from the API server
+func runWithPlugin(ctx context.Context, podName, pluginName, serverName string, args []string) error {
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	var cluster apiv1.Cluster
+	if err := typedClient.Get(ctx, client.ObjectKey{
+		Namespace: os.Getenv("NAMESPACE"),
+		Name:      os.Getenv("CLUSTER_NAME"),
+	}, &cluster); err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	// The parameters of the recovery source take precedence over
+	// the ones of the plugin
+	var overrides map[string]string
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil {
+		externalCluster, found := cluster.ExternalCluster(cluster.Spec.Bootstrap.Recovery.Source)
+		if found && externalCluster.PluginConfiguration != nil &&
+			externalCluster.PluginConfiguration.Name == pluginName {
+			overrides = externalCluster.PluginConfiguration.Parameters
+		}
+	}
+
+	return restoreWithPlugin(ctx, &cluster, podName, &pluginRecoverConfiguration{
+		pluginName: pluginName,
+		serverName: serverName,
+		parameters: cluster.GetPluginParameters(pluginName, overrides),
+	}, args[0], args[1])
+}
+
+// restoreWithPlugin asks the plugin to restore the requested WAL file,
+// returning restorer.ErrWALNotFound when it is not in the archive
+func restoreWithPlugin(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podName string,
+	configuration *pluginRecoverConfiguration,
+	walName string,
+	destinationPath string,
+) error {
+	contextLog := log.FromContext(ctx)
+	startTime := time.Now()
+
+	pluginClient, err := plugin.Connect(ctx, configuration.pluginName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	_, err = pluginClient.WAL.Restore(ctx, &pluginapi.RestoreWALRequest{
+		Cluster:             plugin.NewClusterInfo(cluster.Namespace, cluster.Name, podName),
+		Parameters:          configuration.parameters,
+		SourceWalName:       walName,
+		DestinationFileName: destinationPath,
+		ServerName:          configuration.serverName,
+	})
+	if plugin.IsNotFound(err) {
+		contextLog.Info("WAL file not found in the archive of the plugin",
+			"walName", walName,
+			"plugin", configuration.pluginName)
+		return restorer.ErrWALNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("while restoring the WAL file with the plugin %s: %w", configuration.pluginName, err)
+	}
+
+	contextLog.Info("Restored WAL file",
+		"walName", walName,
+		"plugin", configuration.pluginName,
+		"startTime", startTime,
+		"totalTime", time.Since(startTime))
+	return nil
+}
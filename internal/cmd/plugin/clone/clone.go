@@ -0,0 +1,183 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+type cloneCommand struct {
+	sourceName string
+	cloneName  string
+	backupName string
+	ttl        time.Duration
+	instances  int
+	dryRun     bool
+
+	anonymizerExtension bool
+	anonymizationSQL    []apiv1.ConfigMapKeySelector
+}
+
+func (cmd *cloneCommand) execute(ctx context.Context) error {
+	var source apiv1.Cluster
+	err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.sourceName},
+		&source)
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+
+	backup, err := cmd.getBackup(ctx)
+	if err != nil {
+		return err
+	}
+
+	clone := cmd.buildCluster(source, backup, time.Now())
+
+	if cmd.dryRun {
+		return plugin.Print(clone, plugin.OutputFormatYAML, os.Stdout)
+	}
+
+	if err := plugin.Client.Create(ctx, clone); err != nil {
+		return err
+	}
+
+	fmt.Printf("cluster/%v created from backup/%v\n", clone.Name, backup.Name)
+	return nil
+}
+
+// getBackup gets the backup to recover, which is the passed one or the
+// latest completed backup of the source cluster
+func (cmd *cloneCommand) getBackup(ctx context.Context) (*apiv1.Backup, error) {
+	if cmd.backupName != "" {
+		var backup apiv1.Backup
+		err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.backupName},
+			&backup)
+		if err != nil {
+			return nil, fmt.Errorf("could not get backup: %w", err)
+		}
+		if backup.Spec.Cluster.Name != cmd.sourceName {
+			return nil, fmt.Errorf("backup %v belongs to cluster %v", backup.Name, backup.Spec.Cluster.Name)
+		}
+		if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+			return nil, fmt.Errorf("backup %v is not completed", backup.Name)
+		}
+		return &backup, nil
+	}
+
+	var backupList apiv1.BackupList
+	if err := plugin.Client.List(ctx, &backupList, client.InNamespace(plugin.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not list backups: %w", err)
+	}
+
+	latestBackup := getLatestCompletedBackup(backupList.Items, cmd.sourceName)
+	if latestBackup == nil {
+		return nil, fmt.Errorf("cluster %v has no completed backup", cmd.sourceName)
+	}
+	return latestBackup, nil
+}
+
+// getLatestCompletedBackup returns the completed backup of the passed
+// cluster which stopped last, or nil if there is no such backup
+func getLatestCompletedBackup(backups []apiv1.Backup, clusterName string) *apiv1.Backup {
+	var latestBackup *apiv1.Backup
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.Spec.Cluster.Name != clusterName ||
+			backup.Status.Phase != apiv1.BackupPhaseCompleted ||
+			backup.Status.StoppedAt == nil {
+			continue
+		}
+		if latestBackup == nil || backup.Status.StoppedAt.After(latestBackup.Status.StoppedAt.Time) {
+			latestBackup = backup
+		}
+	}
+	return latestBackup
+}
+
+// buildCluster creates the definition of the copy of the source cluster,
+// which doesn't inherit its backup configuration to avoid writing into
+// the same object store
+func (cmd *cloneCommand) buildCluster(source apiv1.Cluster, backup *apiv1.Backup, now time.Time) *apiv1.Cluster {
+	clone := &apiv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cmd.cloneName,
+			Namespace: source.Namespace,
+		},
+		Spec: apiv1.ClusterSpec{
+			Instances:             cmd.instances,
+			ImageName:             source.Spec.ImageName,
+			PostgresConfiguration: *source.Spec.PostgresConfiguration.DeepCopy(),
+			StorageConfiguration:  *source.Spec.StorageConfiguration.DeepCopy(),
+			WalStorage:            source.Spec.WalStorage.DeepCopy(),
+			Resources:             *source.Spec.Resources.DeepCopy(),
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				Recovery: &apiv1.BootstrapRecovery{
+					Backup: &apiv1.BackupSource{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: backup.Name},
+					},
+				},
+			},
+		},
+	}
+
+	// The backup can only be restored with the tablespaces of the source
+	for _, tablespace := range source.Spec.Tablespaces {
+		clone.Spec.Tablespaces = append(clone.Spec.Tablespaces, *tablespace.DeepCopy())
+	}
+
+	if source.Spec.Backup.IsBarmanEndpointCASet() {
+		clone.Spec.Bootstrap.Recovery.Backup.EndpointCA = source.Spec.Backup.BarmanObjectStore.EndpointCA.DeepCopy()
+	}
+
+	if cmd.anonymizerExtension || len(cmd.anonymizationSQL) > 0 {
+		clone.Spec.Bootstrap.Recovery.Anonymization = &apiv1.RecoveryAnonymization{
+			Enabled:                true,
+			UseAnonymizerExtension: cmd.anonymizerExtension,
+		}
+		if len(cmd.anonymizationSQL) > 0 {
+			clone.Spec.Bootstrap.Recovery.Anonymization.SQLRefs = &apiv1.PostInitApplicationSQLRefs{
+				ConfigMapRefs: cmd.anonymizationSQL,
+			}
+		}
+	}
+
+	if cmd.ttl > 0 {
+		clone.Annotations = map[string]string{
+			utils.ClusterExpirationAnnotationName: now.Add(cmd.ttl).UTC().Format(time.RFC3339),
+		}
+	}
+
+	return clone
+}
@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+var cloneExample = `
+  # Create a single instance copy of "cluster-example", deleted after one day
+  kubectl-cnpg clone cluster-example cluster-example-ci
+
+  # Create a copy from a given backup, deleted after two hours
+  kubectl-cnpg clone cluster-example cluster-example-ci --backup backup-example --ttl 2h
+
+  # Create a copy anonymized with postgresql_anonymizer and the "mask.sql" key of the "masking" ConfigMap
+  kubectl-cnpg clone cluster-example cluster-example-ci --anonymizer-extension --anonymization-sql masking:mask.sql
+
+  # Print the manifest of a copy which is never deleted
+  kubectl-cnpg clone cluster-example cluster-example-ci --ttl 0 --dry-run`
+
+// NewCmd initializes the clone command
+func NewCmd() *cobra.Command {
+	var backupName string
+	var ttl time.Duration
+	var instances int
+	var anonymizerExtension bool
+	var anonymizationSQL []string
+	var dryRun bool
+
+	cloneCmd := &cobra.Command{
+		Use:               "clone [SOURCE_CLUSTER] [CLONE_CLUSTER]",
+		Short:             "Creates a short-lived copy of a cluster from a backup",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusters,
+		Example:           cloneExample,
+		Long: `Creates a new cluster recovering the latest completed backup of the source cluster,
+or the passed one. The new cluster is deleted by the operator when its time to live expires,
+and doesn't archive its WALs, making it fit for CI pipelines and analytics.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			command := &cloneCommand{
+				sourceName: args[0],
+				cloneName:  args[1],
+				backupName: backupName,
+				ttl:        ttl,
+				instances:  instances,
+				dryRun:     dryRun,
+
+				anonymizerExtension: anonymizerExtension,
+			}
+			if err := command.setAnonymizationSQL(anonymizationSQL); err != nil {
+				return err
+			}
+			return command.execute(ctx)
+		},
+	}
+
+	cloneCmd.Flags().StringVar(
+		&backupName,
+		"backup",
+		"",
+		"The name of the backup to recover. Defaults to the latest completed backup of the source cluster",
+	)
+	cloneCmd.Flags().DurationVar(
+		&ttl,
+		"ttl",
+		24*time.Hour,
+		"The time after which the copy is deleted. Use 0 to keep the copy until it's manually deleted",
+	)
+	cloneCmd.Flags().IntVar(
+		&instances,
+		"instances",
+		1,
+		"The number of instances of the copy",
+	)
+	cloneCmd.Flags().BoolVar(
+		&anonymizerExtension,
+		"anonymizer-extension",
+		false,
+		"Anonymize the copy with the static masking of the postgresql_anonymizer extension",
+	)
+	cloneCmd.Flags().StringSliceVar(
+		&anonymizationSQL,
+		"anonymization-sql",
+		nil,
+		"The SQL scripts anonymizing the copy, as CONFIGMAP_NAME:KEY references executed in order",
+	)
+	cloneCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"When true prints the cluster manifest instead of creating it",
+	)
+
+	_ = cloneCmd.RegisterFlagCompletionFunc("backup", plugin.CompleteBackups)
+
+	return cloneCmd
+}
+
+// setAnonymizationSQL parses the references to the anonymization SQL
+// scripts, in the CONFIGMAP_NAME:KEY format
+func (cmd *cloneCommand) setAnonymizationSQL(references []string) error {
+	for _, reference := range references {
+		name, key, found := strings.Cut(reference, ":")
+		if !found || name == "" || key == "" {
+			return fmt.Errorf("invalid anonymization SQL reference %q, expected CONFIGMAP_NAME:KEY", reference)
+		}
+		cmd.anonymizationSQL = append(cmd.anonymizationSQL, apiv1.ConfigMapKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: name},
+			Key:                  key,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// completionConfigFlags are the flags used to create the Kubernetes client
+// while completing a command line. They are parsed after the root command
+// is invoked, and the client is created only when needed
+var completionConfigFlags *genericclioptions.ConfigFlags
+
+// IsShellCompletionCommand checks if the passed command generates the shell
+// completion scripts or completes a command line. Those commands must work
+// without a connection to the Kubernetes API server
+func IsShellCompletionCommand(cmd *cobra.Command) bool {
+	switch {
+	case cmd.Name() == cobra.ShellCompRequestCmd, cmd.Name() == cobra.ShellCompNoDescRequestCmd:
+		return true
+	case cmd.HasParent() && cmd.Parent().Name() == "completion":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetupCompletion stores the flags used to create the Kubernetes client
+// when completing a command line
+func SetupCompletion(configFlags *genericclioptions.ConfigFlags) {
+	completionConfigFlags = configFlags
+}
+
+// setupCompletionClient creates the Kubernetes client honouring the flags
+// passed in the command line being completed
+func setupCompletionClient() error {
+	if completionConfigFlags == nil {
+		return nil
+	}
+	return SetupKubernetesClient(completionConfigFlags)
+}
+
+// CompleteClusters completes the first argument of a command with the
+// names of the clusters in the namespace
+func CompleteClusters(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeClusterNames(toComplete)
+}
+
+// CompleteClusterInstances completes the first argument of a command with
+// the names of the clusters in the namespace, and the second one with the
+// names of the instances of the chosen cluster
+func CompleteClusterInstances(
+	_ *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	return completeClusterInstances(args, toComplete, false)
+}
+
+// CompleteClusterInstanceIDs completes the first argument of a command with
+// the names of the clusters in the namespace, and the second one with the
+// serial numbers of the instances of the chosen cluster
+func CompleteClusterInstanceIDs(
+	_ *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	return completeClusterInstances(args, toComplete, true)
+}
+
+func completeClusterInstances(args []string, toComplete string, onlyIDs bool) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeClusterNames(toComplete)
+
+	case 1:
+		if err := setupCompletionClient(); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var cluster apiv1.Cluster
+		err := Client.Get(context.Background(), client.ObjectKey{Namespace: Namespace, Name: args[0]}, &cluster)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		names := cluster.Status.InstanceNames
+		if onlyIDs {
+			names = getInstanceIDs(&cluster)
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// getInstanceIDs gets the serial numbers of the instances of a cluster,
+// as they appear in the names of the instances
+func getInstanceIDs(cluster *apiv1.Cluster) []string {
+	ids := make([]string, 0, len(cluster.Status.InstanceNames))
+	for _, instanceName := range cluster.Status.InstanceNames {
+		id := strings.TrimPrefix(instanceName, cluster.GetInstanceNamePrefix()+"-")
+		if serial, err := strconv.Atoi(id); err == nil && cluster.GetInstanceName(serial) == instanceName {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// CompleteBackups completes a flag with the names of the backups in the
+// namespace. When the cluster name is the first argument, only its backups
+// are returned
+func CompleteBackups(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := setupCompletionClient(); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var backups apiv1.BackupList
+	if err := Client.List(context.Background(), &backups, client.InNamespace(Namespace)); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(backups.Items))
+	for _, backup := range backups.Items {
+		if len(args) > 0 && backup.Spec.Cluster.Name != args[0] {
+			continue
+		}
+		names = append(names, backup.Name)
+	}
+
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeClusterNames(toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := setupCompletionClient(); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names, err := getClusterNames(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// getClusterNames gets the sorted names of the clusters in the namespace
+func getClusterNames(ctx context.Context) ([]string, error) {
+	var clusters apiv1.ClusterList
+	if err := Client.List(ctx, &clusters, client.InNamespace(Namespace)); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// filterCompletions returns the names starting with the passed prefix
+func filterCompletions(names []string, toComplete string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
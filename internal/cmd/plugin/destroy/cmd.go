@@ -20,14 +20,17 @@ import (
 	"context"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd create the new "promote" subcommand
 func NewCmd() *cobra.Command {
 	promoteCmd := &cobra.Command{
-		Use:   "destroy [CLUSTER_NAME] [INSTANCE_ID]",
-		Short: "Destroy the instance named [CLUSTER_NAME] and [INSTANCE_ID] with the associated PVC",
-		Args:  cobra.ExactArgs(2),
+		Use:               "destroy [CLUSTER_NAME] [INSTANCE_ID]",
+		Short:             "Destroy the instance named [CLUSTER_NAME] and [INSTANCE_ID] with the associated PVC",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			clusterName := args[0]
@@ -35,7 +35,10 @@ import (
 
 // Destroy implements the destroy subcommand
 func Destroy(ctx context.Context, clusterName, instanceID string, keepPVC bool) error {
-	instanceName := fmt.Sprintf("%s-%s", clusterName, instanceID)
+	instanceName, err := plugin.GetInstanceName(ctx, clusterName, instanceID)
+	if err != nil {
+		return err
+	}
 
 	if err := ensurePodIsDeleted(ctx, instanceName, clusterName); err != nil {
 		return fmt.Errorf("error deleting instance %s: %v", instanceName, err)
@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var failoverExample = `
+  # Switch over "cluster-example" to its first healthy replica, measuring the downtime
+  kubectl-cnpg drill failover cluster-example
+
+  # Switch over to "cluster-example-3", measuring the downtime through the "pooler-example-rw" pooler too
+  kubectl-cnpg drill failover cluster-example --target cluster-example-3 --pooler pooler-example-rw`
+
+// NewCmd creates the new "drill" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drill [failover]",
+		Short: "Game day drills, measuring the behavior of a cluster during a failure",
+	}
+
+	cmd.AddCommand(newFailoverCmd())
+
+	return cmd
+}
+
+func newFailoverCmd() *cobra.Command {
+	options := failoverOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "failover [cluster]",
+		Short: "Runs a controlled switchover of the cluster, measuring the downtime seen by the clients",
+		Long: "Starts a Job connecting to the read-write service of the cluster in a loop, triggers a " +
+			"switchover, and prints the time spent to detect it, to promote the new primary, to " +
+			"repoint the read-write service and to resume the pooler, together with the downtime " +
+			"seen by the clients",
+		Example:           failoverExample,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName, err := plugin.GetClusterName(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			options.clusterName = clusterName
+			options.format = plugin.OutputFormat(output)
+			return runFailoverDrill(ctx, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.target, "target", "",
+		"The instance to be promoted. Defaults to the first healthy replica")
+	cmd.Flags().StringVar(&options.poolerName, "pooler", "",
+		"The name of a pooler of the cluster to be probed too")
+	cmd.Flags().StringVar(&options.dbName, "db-name", "app",
+		"The database used by the probes")
+	cmd.Flags().DurationVar(&options.probeInterval, "probe-interval", 200*time.Millisecond,
+		"The time between two connection attempts of the probes")
+	cmd.Flags().DurationVar(&options.timeout, "timeout", 5*time.Minute,
+		"The maximum time to wait for the cluster to be healthy again")
+	cmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json")
+
+	return cmd
+}
@@ -0,0 +1,410 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drill implements the kubectl-cnpg drill command
+package drill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// pollInterval is the time between two checks of the status of the cluster
+// during the drill
+const pollInterval = 250 * time.Millisecond
+
+// errDrillTimeout is returned when the cluster is not healthy again
+// before the timeout of the drill
+var errDrillTimeout = errors.New("the cluster didn't complete the switchover before the timeout")
+
+// failoverOptions are the options of the failover drill
+type failoverOptions struct {
+	clusterName   string
+	target        string
+	poolerName    string
+	dbName        string
+	probeInterval time.Duration
+	timeout       time.Duration
+	format        plugin.OutputFormat
+}
+
+// Report is the result of the failover drill. Every duration is measured
+// from the moment the switchover was requested
+type Report struct {
+	// ClusterName is the name of the cluster
+	ClusterName string `json:"clusterName"`
+
+	// OldPrimary is the instance that was the primary before the drill
+	OldPrimary string `json:"oldPrimary"`
+
+	// NewPrimary is the instance that has been promoted
+	NewPrimary string `json:"newPrimary"`
+
+	// RequestedAt is the time when the switchover was requested
+	RequestedAt time.Time `json:"requestedAt"`
+
+	// Detection is the time spent before the old primary was
+	// removed from the read-write service
+	Detection *metav1.Duration `json:"detection,omitempty"`
+
+	// Promotion is the time spent before the new primary was promoted
+	Promotion *metav1.Duration `json:"promotion,omitempty"`
+
+	// ServiceRepoint is the time spent before the read-write service
+	// pointed to the new primary
+	ServiceRepoint *metav1.Duration `json:"serviceRepoint,omitempty"`
+
+	// PoolerResume is the time spent before the pooler accepted
+	// connections again
+	PoolerResume *metav1.Duration `json:"poolerResume,omitempty"`
+
+	// Healthy is the time spent before the cluster was healthy again
+	Healthy *metav1.Duration `json:"healthy,omitempty"`
+
+	// ClientDowntime is the total time the clients of the read-write
+	// service were unable to connect
+	ClientDowntime metav1.Duration `json:"clientDowntime"`
+
+	// ClientFailedAttempts is the number of failed connections to the
+	// read-write service
+	ClientFailedAttempts int `json:"clientFailedAttempts"`
+
+	// PoolerDowntime is the total time the clients of the pooler
+	// were unable to connect
+	PoolerDowntime *metav1.Duration `json:"poolerDowntime,omitempty"`
+
+	// PoolerFailedAttempts is the number of failed connections to the pooler
+	PoolerFailedAttempts *int `json:"poolerFailedAttempts,omitempty"`
+}
+
+// runFailoverDrill implements the "drill failover" subcommand
+func runFailoverDrill(ctx context.Context, options failoverOptions) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: options.clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", options.clusterName, plugin.Namespace)
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseHealthy || cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("cluster %s is not healthy, refusing to run the drill (phase: %s)",
+			cluster.Name, cluster.Status.Phase)
+	}
+
+	target, err := getDrillTarget(&cluster, options.target)
+	if err != nil {
+		return err
+	}
+
+	if options.poolerName != "" {
+		if err := checkPooler(ctx, &cluster, options.poolerName); err != nil {
+			return err
+		}
+	}
+
+	job := buildProbeJob(&cluster, options)
+	if err := plugin.Client.Create(ctx, job); err != nil {
+		return fmt.Errorf("while creating the probe job: %w", err)
+	}
+	defer func() {
+		if err := plugin.Client.Delete(
+			context.Background(), job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!apierrs.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "cannot delete the probe job %s: %v\n", job.Name, err)
+		}
+	}()
+
+	probePod, err := waitForProbes(ctx, job, options)
+	if err != nil {
+		return err
+	}
+
+	report := &Report{
+		ClusterName: cluster.Name,
+		OldPrimary:  cluster.Status.CurrentPrimary,
+		NewPrimary:  target,
+		RequestedAt: time.Now(),
+	}
+
+	cluster.Status.TargetPrimary = target
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.Phase = apiv1.PhaseSwitchover
+	cluster.Status.PhaseReason = fmt.Sprintf("Switching over to %v", target)
+	if err := plugin.Client.Status().Update(ctx, &cluster); err != nil {
+		return fmt.Errorf("while requesting the switchover: %w", err)
+	}
+
+	timeoutErr := watchSwitchover(ctx, report, options.timeout)
+
+	// Leave the probes the time to see the services accepting
+	// connections again
+	time.Sleep(2 * time.Second)
+
+	if err := collectDowntime(ctx, report, probePod, options); err != nil {
+		return err
+	}
+
+	if err := plugin.Print(report, options.format, os.Stdout); err != nil {
+		return err
+	}
+
+	if options.format == plugin.OutputFormatText {
+		report.print()
+	}
+
+	return timeoutErr
+}
+
+// getDrillTarget gets the instance that will be promoted, checking
+// that it can be
+func getDrillTarget(cluster *apiv1.Cluster, target string) (string, error) {
+	healthy := cluster.Status.InstancesStatus[utils.PodHealthy]
+
+	if target != "" {
+		switch {
+		case target == cluster.Status.CurrentPrimary:
+			return "", fmt.Errorf("%s is already the primary instance", target)
+		case cluster.IsRestrictedReplica(target):
+			return "", fmt.Errorf("%s is a restricted replica and cannot be promoted", target)
+		case !stringSliceContains(healthy, target):
+			return "", fmt.Errorf("%s is not a healthy instance of cluster %s", target, cluster.Name)
+		}
+		return target, nil
+	}
+
+	for _, instance := range healthy {
+		if instance != cluster.Status.CurrentPrimary && !cluster.IsRestrictedReplica(instance) {
+			return instance, nil
+		}
+	}
+
+	return "", fmt.Errorf("cluster %s has no healthy replica that can be promoted", cluster.Name)
+}
+
+// checkPooler checks that the passed pooler exists and belongs to the cluster
+func checkPooler(ctx context.Context, cluster *apiv1.Cluster, poolerName string) error {
+	var pooler apiv1.Pooler
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: poolerName}, &pooler)
+	if err != nil {
+		return fmt.Errorf("pooler %s not found in namespace %s", poolerName, cluster.Namespace)
+	}
+
+	if pooler.Spec.Cluster.Name != cluster.Name {
+		return fmt.Errorf("pooler %s doesn't belong to cluster %s", poolerName, cluster.Name)
+	}
+
+	return nil
+}
+
+// waitForProbes waits for the probes to be connected to the cluster,
+// returning the Pod running them
+func waitForProbes(ctx context.Context, job *batchv1.Job, options failoverOptions) (*corev1.Pod, error) {
+	fmt.Fprintf(os.Stderr, "Waiting for the probes of job %s to connect\n", job.Name)
+
+	deadline := time.Now().Add(options.timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(time.Second)
+
+		pod, err := getProbePod(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		if pod == nil {
+			continue
+		}
+
+		containers := []string{probeClusterContainerName}
+		if options.poolerName != "" {
+			containers = append(containers, probePoolerContainerName)
+		}
+		if probesConnected(ctx, pod, containers) {
+			return pod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the probes of job %s didn't connect before the timeout", job.Name)
+}
+
+// probesConnected checks if every probe connected successfully at least once
+func probesConnected(ctx context.Context, pod *corev1.Pod, containers []string) bool {
+	for _, container := range containers {
+		samples, err := readProbeSamples(ctx, pod, container)
+		if err != nil {
+			return false
+		}
+
+		connected := false
+		for _, sample := range samples {
+			if sample.ok {
+				connected = true
+				break
+			}
+		}
+		if !connected {
+			return false
+		}
+	}
+
+	return true
+}
+
+// watchSwitchover follows the switchover, recording when every
+// step is complete
+func watchSwitchover(ctx context.Context, report *Report, timeout time.Duration) error {
+	elapsed := func() *metav1.Duration {
+		return &metav1.Duration{Duration: time.Since(report.RequestedAt).Round(time.Millisecond)}
+	}
+
+	deadline := report.RequestedAt.Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+
+		var cluster apiv1.Cluster
+		if err := plugin.Client.Get(
+			ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: report.ClusterName}, &cluster); err != nil {
+			continue
+		}
+
+		var endpoints corev1.Endpoints
+		if err := plugin.Client.Get(
+			ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.GetServiceReadWriteName()},
+			&endpoints); err != nil {
+			continue
+		}
+
+		if report.Detection == nil && !endpointsContain(&endpoints, report.OldPrimary) {
+			report.Detection = elapsed()
+		}
+		if report.Promotion == nil && cluster.Status.CurrentPrimary == report.NewPrimary {
+			report.Promotion = elapsed()
+		}
+		if report.ServiceRepoint == nil && endpointsContain(&endpoints, report.NewPrimary) &&
+			!endpointsContain(&endpoints, report.OldPrimary) {
+			report.ServiceRepoint = elapsed()
+		}
+		if report.Promotion != nil && report.Healthy == nil && cluster.Status.Phase == apiv1.PhaseHealthy {
+			report.Healthy = elapsed()
+		}
+
+		if report.ServiceRepoint != nil && report.Healthy != nil {
+			// The old primary may have been removed from the service
+			// together with the new one being added
+			if report.Detection == nil {
+				report.Detection = report.ServiceRepoint
+			}
+			return nil
+		}
+	}
+
+	return errDrillTimeout
+}
+
+// collectDowntime reads the results of the probes, computing the downtime
+// seen by the clients
+func collectDowntime(ctx context.Context, report *Report, probePod *corev1.Pod, options failoverOptions) error {
+	samples, err := readProbeSamples(ctx, probePod, probeClusterContainerName)
+	if err != nil {
+		return err
+	}
+	clusterDowntime := computeDowntime(samples, report.RequestedAt)
+	report.ClientDowntime = metav1.Duration{Duration: clusterDowntime.downtime}
+	report.ClientFailedAttempts = clusterDowntime.failures
+
+	if options.poolerName == "" {
+		return nil
+	}
+
+	samples, err = readProbeSamples(ctx, probePod, probePoolerContainerName)
+	if err != nil {
+		return err
+	}
+	poolerDowntime := computeDowntime(samples, report.RequestedAt)
+	report.PoolerDowntime = &metav1.Duration{Duration: poolerDowntime.downtime}
+	report.PoolerFailedAttempts = &poolerDowntime.failures
+	if poolerDowntime.recovery != nil {
+		report.PoolerResume = &metav1.Duration{
+			Duration: poolerDowntime.recovery.Sub(report.RequestedAt).Round(time.Millisecond),
+		}
+	}
+
+	return nil
+}
+
+// endpointsContain checks if the passed Pod is one of the ready
+// addresses of the endpoints
+func endpointsContain(endpoints *corev1.Endpoints, podName string) bool {
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.TargetRef != nil && address.TargetRef.Name == podName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func stringSliceContains(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// print prints the report in a human-readable format
+func (report *Report) print() {
+	printDuration := func(description string, duration *metav1.Duration) {
+		if duration == nil {
+			fmt.Printf("%-20s not completed\n", description+":")
+			return
+		}
+		fmt.Printf("%-20s %s\n", description+":", duration.Duration)
+	}
+
+	fmt.Printf("Switchover of cluster %s from %s to %s, requested at %s\n",
+		report.ClusterName, report.OldPrimary, report.NewPrimary, report.RequestedAt.Format(time.RFC3339))
+	fmt.Println()
+	fmt.Println("Time elapsed since the request:")
+	printDuration("Detection", report.Detection)
+	printDuration("Promotion", report.Promotion)
+	printDuration("Service repoint", report.ServiceRepoint)
+	if report.PoolerDowntime != nil {
+		printDuration("Pooler resume", report.PoolerResume)
+	}
+	printDuration("Cluster healthy", report.Healthy)
+	fmt.Println()
+	fmt.Printf("Client downtime: %s (%d failed connections)\n",
+		report.ClientDowntime.Duration, report.ClientFailedAttempts)
+	if report.PoolerDowntime != nil {
+		fmt.Printf("Pooler client downtime: %s (%d failed connections)\n",
+			report.PoolerDowntime.Duration, *report.PoolerFailedAttempts)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Failover drill", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			RestrictedReplicas: &apiv1.RestrictedReplicasConfiguration{Instances: []int{2}},
+		},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			InstancesStatus: map[utils.PodStatus][]string{
+				utils.PodHealthy: {"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+				utils.PodFailed:  {"cluster-example-4"},
+			},
+		},
+	}
+
+	ginkgo.It("promotes the first healthy replica that is not restricted", func() {
+		gomega.Expect(getDrillTarget(cluster, "")).To(gomega.Equal("cluster-example-3"))
+	})
+
+	ginkgo.DescribeTable("validates the requested target",
+		func(target string, valid bool) {
+			result, err := getDrillTarget(cluster, target)
+			if !valid {
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(result).To(gomega.Equal(target))
+		},
+		ginkgo.Entry("healthy replica", "cluster-example-3", true),
+		ginkgo.Entry("current primary", "cluster-example-1", false),
+		ginkgo.Entry("restricted replica", "cluster-example-2", false),
+		ginkgo.Entry("failed instance", "cluster-example-4", false),
+	)
+
+	ginkgo.It("checks the instances pointed by the endpoints", func() {
+		endpoints := &corev1.Endpoints{
+			Subsets: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{
+						{IP: "10.0.0.1", TargetRef: &corev1.ObjectReference{Name: "cluster-example-1"}},
+						{IP: "10.0.0.2"},
+					},
+				},
+			},
+		}
+		gomega.Expect(endpointsContain(endpoints, "cluster-example-1")).To(gomega.BeTrue())
+		gomega.Expect(endpointsContain(endpoints, "cluster-example-2")).To(gomega.BeFalse())
+	})
+})
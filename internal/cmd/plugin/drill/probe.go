@@ -0,0 +1,255 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+const (
+	// probeClusterContainerName is the name of the container probing
+	// the read-write service of the cluster
+	probeClusterContainerName = "probe-cluster"
+
+	// probePoolerContainerName is the name of the container probing
+	// the service of the pooler
+	probePoolerContainerName = "probe-pooler"
+
+	// probeOK is printed by the probes after a successful connection
+	probeOK = "ok"
+
+	// probeFail is printed by the probes after a failed connection
+	probeFail = "fail"
+)
+
+// probeSample is the result of a connection attempt of a probe
+type probeSample struct {
+	time time.Time
+	ok   bool
+}
+
+// probeTimeout is the maximum time, in seconds, a connection attempt of
+// the probes can take, including the write
+const probeTimeout = 2
+
+// probeQuery is the write run by the probes, which fails when the service
+// points to a replica and hangs when the commit cannot be replicated to
+// the synchronous standbys. The temporary table only needs the TEMPORARY
+// privilege, that every user has by default
+const probeQuery = "CREATE TEMPORARY TABLE cnpg_drill_probe (t timestamptz) ON COMMIT DROP; " +
+	"INSERT INTO cnpg_drill_probe VALUES (now())"
+
+// probeScript is the shell loop running the probes. It prints the time, in
+// milliseconds since the epoch, and the result of each connection attempt
+const probeScript = `while true; do
+  if timeout %d psql -Atqc '%s' >/dev/null 2>&1; then result=` + probeOK + `; else result=` + probeFail + `; fi
+  echo "$(date +%%s%%3N) $result"
+  sleep %s
+done`
+
+// buildProbeJob creates the Job running the probes against the read-write
+// service of the cluster and, optionally, against the pooler service
+func buildProbeJob(cluster *apiv1.Cluster, options failoverOptions) *batchv1.Job {
+	script := fmt.Sprintf(probeScript,
+		probeTimeout, probeQuery, strconv.FormatFloat(options.probeInterval.Seconds(), 'f', 3, 64))
+	probeContainer := func(name, host string) corev1.Container {
+		return corev1.Container{
+			Name:    name,
+			Image:   cluster.GetImageName(),
+			Env:     buildProbeEnv(cluster, host, options.dbName),
+			Command: []string{"sh", "-c", script},
+		}
+	}
+
+	containers := []corev1.Container{
+		probeContainer(probeClusterContainerName, cluster.GetServiceReadWriteName()),
+	}
+	if options.poolerName != "" {
+		containers = append(containers, probeContainer(probePoolerContainerName, options.poolerName))
+	}
+
+	labels := map[string]string{
+		"cnpg.io/drill": cluster.Name,
+	}
+	deadline := int64((options.timeout + 5*time.Minute).Seconds())
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-drill-failover", cluster.Name),
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds:   &deadline,
+			BackoffLimit:            pointer.Int32(0),
+			TTLSecondsAfterFinished: pointer.Int32(600),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    containers,
+				},
+			},
+		},
+	}
+}
+
+func buildProbeEnv(cluster *apiv1.Cluster, host, dbName string) []corev1.EnvVar {
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cluster.GetApplicationSecretName(),
+				},
+				Key: key,
+			},
+		}
+	}
+
+	return []corev1.EnvVar{
+		{Name: "PGHOST", Value: host},
+		{Name: "PGDATABASE", Value: dbName},
+		{Name: "PGPORT", Value: "5432"},
+		{Name: "PGCONNECT_TIMEOUT", Value: strconv.Itoa(probeTimeout)},
+		{Name: "PGUSER", ValueFrom: secretKey("username")},
+		{Name: "PGPASSWORD", ValueFrom: secretKey("password")},
+	}
+}
+
+// getProbePod gets the Pod running the probes of the passed Job
+func getProbePod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx, &pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	for idx := range pods.Items {
+		if pods.Items[idx].Status.Phase == corev1.PodRunning {
+			return &pods.Items[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// readProbeSamples reads the results of the connection attempts of the
+// passed container of the probe Pod
+func readProbeSamples(ctx context.Context, pod *corev1.Pod, containerName string) ([]probeSample, error) {
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	logs, err := clientInterface.CoreV1().Pods(pod.Namespace).GetLogs(
+		pod.Name, &corev1.PodLogOptions{Container: containerName}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the logs of the %s probe: %w", containerName, err)
+	}
+
+	return parseProbeSamples(string(logs)), nil
+}
+
+// parseProbeSamples parses the output of a probe, skipping the
+// malformed lines
+func parseProbeSamples(logs string) []probeSample {
+	var result []probeSample
+
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		millis, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, probeSample{
+			time: time.UnixMilli(millis),
+			ok:   fields[1] == probeOK,
+		})
+	}
+
+	return result
+}
+
+// probeDowntime is the downtime measured by a probe
+type probeDowntime struct {
+	// firstFailure is the time of the first failed connection attempt
+	firstFailure *time.Time
+
+	// recovery is the time of the first successful connection attempt
+	// after the last failure
+	recovery *time.Time
+
+	// downtime is the total time spent without being able to connect
+	downtime time.Duration
+
+	// failures is the number of failed connection attempts
+	failures int
+}
+
+// computeDowntime computes the time the probe was unable to connect,
+// considering only the connection attempts after the passed time.
+// A downtime window starts with a failed attempt and ends with the
+// following successful one
+func computeDowntime(samples []probeSample, since time.Time) probeDowntime {
+	var result probeDowntime
+	var windowStart *time.Time
+
+	for idx := range samples {
+		sample := samples[idx]
+		if sample.time.Before(since) {
+			continue
+		}
+
+		switch {
+		case !sample.ok:
+			result.failures++
+			if windowStart == nil {
+				windowStart = &samples[idx].time
+			}
+			if result.firstFailure == nil {
+				result.firstFailure = &samples[idx].time
+			}
+
+		case windowStart != nil:
+			result.downtime += sample.time.Sub(*windowStart)
+			result.recovery = &samples[idx].time
+			windowStart = nil
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Probes", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+	}
+
+	ginkgo.It("probes the services with a write", func() {
+		job := buildProbeJob(cluster, failoverOptions{
+			poolerName:    "pooler-example-rw",
+			dbName:        "app",
+			probeInterval: 200 * time.Millisecond,
+			timeout:       time.Minute,
+		})
+
+		containers := job.Spec.Template.Spec.Containers
+		gomega.Expect(containers).To(gomega.HaveLen(2))
+		gomega.Expect(containers[0].Name).To(gomega.Equal(probeClusterContainerName))
+		gomega.Expect(containers[1].Name).To(gomega.Equal(probePoolerContainerName))
+
+		script := containers[0].Command[2]
+		gomega.Expect(script).To(gomega.ContainSubstring("timeout 2 psql -Atqc '" + probeQuery + "'"))
+		gomega.Expect(script).To(gomega.ContainSubstring("INSERT INTO cnpg_drill_probe"))
+		gomega.Expect(script).To(gomega.ContainSubstring("sleep 0.200"))
+		gomega.Expect(script).To(gomega.ContainSubstring("date +%s%3N"))
+
+		gomega.Expect(containers[0].Env).To(gomega.ContainElement(
+			gomega.HaveField("Value", "cluster-example-rw")))
+		gomega.Expect(containers[1].Env).To(gomega.ContainElement(
+			gomega.HaveField("Value", "pooler-example-rw")))
+	})
+
+	ginkgo.It("parses the output of the probes", func() {
+		samples := parseProbeSamples("1666267951000 ok\n1666267951200 fail\nmalformed\nabc ok\n")
+		gomega.Expect(samples).To(gomega.Equal([]probeSample{
+			{time: time.UnixMilli(1666267951000), ok: true},
+			{time: time.UnixMilli(1666267951200), ok: false},
+		}))
+	})
+
+	ginkgo.It("computes the downtime from the failed attempts", func() {
+		start := time.UnixMilli(1666267951000)
+		at := func(millis int64) time.Time {
+			return start.Add(time.Duration(millis) * time.Millisecond)
+		}
+		samples := []probeSample{
+			{time: at(-400), ok: false},
+			{time: at(0), ok: true},
+			{time: at(200), ok: false},
+			{time: at(400), ok: false},
+			{time: at(600), ok: true},
+			{time: at(800), ok: false},
+			{time: at(1000), ok: true},
+		}
+
+		downtime := computeDowntime(samples, start)
+		gomega.Expect(downtime.failures).To(gomega.Equal(3))
+		gomega.Expect(downtime.downtime).To(gomega.Equal(600 * time.Millisecond))
+		gomega.Expect(*downtime.firstFailure).To(gomega.Equal(at(200)))
+		gomega.Expect(*downtime.recovery).To(gomega.Equal(at(1000)))
+	})
+
+	ginkgo.It("doesn't report a recovery without failures", func() {
+		downtime := computeDowntime([]probeSample{{time: time.Now(), ok: true}}, time.Time{})
+		gomega.Expect(downtime.failures).To(gomega.BeZero())
+		gomega.Expect(downtime.recovery).To(gomega.BeNil())
+	})
+})
@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PrintError prints the passed error. When the Kubernetes API server
+// rejected an object, like the validating webhooks of the operator do,
+// every cause is printed on its own line, starting with the path of the
+// field it refers to
+func PrintError(w io.Writer, err error) {
+	var apiStatus apierrs.APIStatus
+	if !errors.As(err, &apiStatus) {
+		_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	status := apiStatus.Status()
+	if status.Reason != metav1.StatusReasonInvalid || status.Details == nil || len(status.Details.Causes) == 0 {
+		_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Error: %s %q is invalid\n", status.Details.Kind, status.Details.Name)
+	for _, cause := range status.Details.Causes {
+		if cause.Field == "" {
+			_, _ = fmt.Fprintf(w, "  - %s\n", cause.Message)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  - %s: %s\n", cause.Field, cause.Message)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("error printing", func() {
+	It("prints the causes of the rejected objects", func() {
+		err := apierrs.NewInvalid(
+			schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Cluster"},
+			"cluster-example",
+			field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "imagePullPolicy"), "wrong", []string{"Always", "Never"}),
+				field.Required(field.NewPath("spec", "storage", "size"), "the size is required"),
+			})
+
+		var out bytes.Buffer
+		PrintError(&out, fmt.Errorf("while creating the cluster: %w", err))
+		Expect(out.String()).To(Equal(`Error: Cluster "cluster-example" is invalid
+  - spec.imagePullPolicy: Unsupported value: "wrong": supported values: "Always", "Never"
+  - spec.storage.size: Required value: the size is required
+`))
+	})
+
+	It("prints the other errors as they are", func() {
+		var out bytes.Buffer
+		PrintError(&out, errors.New("cluster not found"))
+		Expect(out.String()).To(Equal("Error: cluster not found\n"))
+	})
+})
@@ -17,22 +17,22 @@ limitations under the License.
 package fence
 
 import (
-	"fmt"
-	"strconv"
-
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 var (
 	fenceOnCmd = &cobra.Command{
-		Use:   "on [cluster] [node]",
-		Short: `Fence an instance named [cluster]-[node] or [node]`,
-		Args:  cobra.ExactArgs(2),
+		Use:               "on [cluster] [node]",
+		Short:             `Fence an instance named [cluster]-[node] or [node]`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstances,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
-			node := args[1]
-			if _, err := strconv.Atoi(args[1]); err == nil {
-				node = fmt.Sprintf("%s-%s", clusterName, node)
+			node, err := plugin.GetInstanceName(cmd.Context(), clusterName, args[1])
+			if err != nil {
+				return err
 			}
 
 			return fencingOn(cmd.Context(), clusterName, node)
@@ -40,14 +40,15 @@ var (
 	}
 
 	fenceOffCmd = &cobra.Command{
-		Use:   "off [cluster] [node]",
-		Short: `Remove fence for an instance named [cluster]-[node] or [node]`,
-		Args:  cobra.ExactArgs(2),
+		Use:               "off [cluster] [node]",
+		Short:             `Remove fence for an instance named [cluster]-[node] or [node]`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstances,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
-			node := args[1]
-			if _, err := strconv.Atoi(args[1]); err == nil {
-				node = fmt.Sprintf("%s-%s", clusterName, node)
+			node, err := plugin.GetInstanceName(cmd.Context(), clusterName, args[1])
+			if err != nil {
+				return err
 			}
 			return fencingOff(cmd.Context(), clusterName, node)
 		},
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var statusExample = `
+  # Summarize the clusters in the current namespace
+  kubectl-cnpg fleet status
+
+  # Summarize the clusters in every namespace, as CSV
+  kubectl-cnpg fleet status -A -o csv`
+
+// NewCmd creates the new 'fleet' command
+func NewCmd() *cobra.Command {
+	var allNamespaces bool
+	var output string
+
+	fleetCmd := &cobra.Command{
+		Use:   "fleet [status]",
+		Short: "Summarizes the state of many clusters at once",
+	}
+
+	fleetCmd.AddCommand(&cobra.Command{
+		Use:     "status",
+		Short:   "Summarizes the state of the clusters",
+		Example: statusExample,
+		Long: "This command will summarize the version, the instances, the zone of the primary, " +
+			"the last backup and the health of the WAL archiving of the clusters in the current " +
+			"namespace, or in every namespace if not specified differently through flags",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatCSV,
+				plugin.OutputFormatJSON, plugin.OutputFormatOpenMetrics:
+			default:
+				return fmt.Errorf("unsupported output format: %s", output)
+			}
+
+			return Status(context.Background(), allNamespaces, format)
+		},
+	})
+
+	fleetCmd.PersistentFlags().BoolVarP(&allNamespaces,
+		"all-namespaces", "A", false, "Summarize the clusters in all namespaces")
+	fleetCmd.PersistentFlags().StringVarP(&output,
+		"output", "o", "text", "Output format. One of text|csv|json|openmetrics")
+
+	return fleetCmd
+}
@@ -0,0 +1,332 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/fleetapi"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// archivingOK means that the WAL archiving is working
+	archivingOK = "OK"
+
+	// archivingFailing means that the WAL archiving is failing
+	archivingFailing = "Failing"
+
+	// archivingNotConfigured means that the cluster has no object store
+	// where the WAL files are archived
+	archivingNotConfigured = "Not configured"
+
+	// archivingUnknown means that the operator has not yet reported
+	// the state of the WAL archiving
+	archivingUnknown = "Unknown"
+)
+
+// clusterStatus is the summary of the state of a cluster
+// printed by the fleet status command
+type clusterStatus struct {
+	fleetapi.ClusterSummary
+
+	// PrimaryZone is the zone of the node running the primary instance,
+	// empty if it cannot be detected
+	PrimaryZone string `json:"primaryZone,omitempty"`
+
+	// Archiving is the health of the WAL archiving
+	Archiving string `json:"archiving"`
+}
+
+// Status prints the summary of the clusters in the current
+// namespace, or in every namespace
+func Status(ctx context.Context, allNamespaces bool, format plugin.OutputFormat) error {
+	var opts []client.ListOption
+	if !allNamespaces {
+		opts = append(opts, client.InNamespace(plugin.Namespace))
+	}
+
+	var clusters apiv1.ClusterList
+	if err := plugin.Client.List(ctx, &clusters, opts...); err != nil {
+		return err
+	}
+
+	var backups apiv1.BackupList
+	if err := plugin.Client.List(ctx, &backups, opts...); err != nil {
+		return err
+	}
+
+	statuses := newClusterStatuses(clusters.Items, backups.Items, getPrimaryZones(ctx, opts))
+
+	switch format {
+	case plugin.OutputFormatJSON:
+		return plugin.Print(statuses, format, os.Stdout)
+	case plugin.OutputFormatCSV:
+		return printCSV(statuses, os.Stdout)
+	case plugin.OutputFormatOpenMetrics:
+		return printOpenMetrics(statuses, os.Stdout)
+	default:
+		printTable(statuses)
+		return nil
+	}
+}
+
+// getPrimaryZones gets the zone of the node running every primary instance,
+// indexed by the namespace and the name of the cluster. As the nodes are
+// not readable by every user, failing to read them is not an error
+func getPrimaryZones(ctx context.Context, opts []client.ListOption) map[string]string {
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx,
+		&pods,
+		append(opts, client.MatchingLabels{specs.ClusterRoleLabelName: specs.ClusterRoleLabelPrimary})...,
+	); err != nil {
+		return nil
+	}
+
+	var nodes corev1.NodeList
+	if err := plugin.Client.List(ctx, &nodes); err != nil {
+		return nil
+	}
+
+	nodeZones := make(map[string]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeZones[node.Name] = node.Labels[corev1.LabelTopologyZone]
+	}
+
+	result := make(map[string]string, len(pods.Items))
+	for _, pod := range pods.Items {
+		clusterName := pod.Labels[utils.ClusterLabelName]
+		if clusterName == "" {
+			continue
+		}
+		result[pod.Namespace+"/"+clusterName] = nodeZones[pod.Spec.NodeName]
+	}
+
+	return result
+}
+
+// newClusterStatuses creates the summary of the passed clusters
+func newClusterStatuses(
+	clusters []apiv1.Cluster,
+	backups []apiv1.Backup,
+	primaryZones map[string]string,
+) []clusterStatus {
+	clustersByKey := make(map[string]*apiv1.Cluster, len(clusters))
+	for i := range clusters {
+		clustersByKey[clusters[i].Namespace+"/"+clusters[i].Name] = &clusters[i]
+	}
+
+	summary := fleetapi.NewFleetSummary(clusters, backups)
+	result := make([]clusterStatus, 0, len(summary.Clusters))
+	for _, clusterSummary := range summary.Clusters {
+		key := clusterSummary.Namespace + "/" + clusterSummary.Name
+		result = append(result, clusterStatus{
+			ClusterSummary: clusterSummary,
+			PrimaryZone:    primaryZones[key],
+			Archiving:      getArchivingHealth(clustersByKey[key]),
+		})
+	}
+
+	return result
+}
+
+// getArchivingHealth gets the health of the WAL archiving of a cluster
+func getArchivingHealth(cluster *apiv1.Cluster) string {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return archivingNotConfigured
+	}
+
+	condition := meta.FindStatusCondition(
+		cluster.Status.Conditions,
+		string(apiv1.ConditionContinuousArchiving))
+	switch {
+	case condition == nil:
+		return archivingUnknown
+	case condition.Status == metav1.ConditionTrue:
+		return archivingOK
+	case condition.Status == metav1.ConditionFalse:
+		return archivingFailing
+	default:
+		return archivingUnknown
+	}
+}
+
+// formatPostgresVersion formats a PostgreSQL version number
+// in the way it is used in the image tags
+func formatPostgresVersion(version int) string {
+	if version == 0 {
+		return ""
+	}
+
+	major := version / 10000
+	if major >= 10 {
+		return fmt.Sprintf("%d.%d", major, version%100)
+	}
+
+	return fmt.Sprintf("%d.%d.%d", major, version/100%100, version%100)
+}
+
+// formatLastBackup formats the time of the last successful backup
+func formatLastBackup(status clusterStatus) string {
+	if status.Backup.LastSuccessfulBackup == nil {
+		return ""
+	}
+
+	return status.Backup.LastSuccessfulBackup.UTC().Format(time.RFC3339)
+}
+
+// getStatusRecords gets the header and the rows of the summary
+func getStatusRecords(statuses []clusterStatus) [][]string {
+	records := [][]string{
+		{"Namespace", "Name", "Version", "Instances", "Ready", "Primary", "Primary zone", "Last backup", "Archiving"},
+	}
+	for _, status := range statuses {
+		records = append(records, []string{
+			status.Namespace,
+			status.Name,
+			formatPostgresVersion(status.PostgresVersion),
+			strconv.Itoa(status.Instances),
+			strconv.Itoa(status.ReadyInstances),
+			status.CurrentPrimary,
+			status.PrimaryZone,
+			formatLastBackup(status),
+			status.Archiving,
+		})
+	}
+
+	return records
+}
+
+// printTable prints the summary as a table
+func printTable(statuses []clusterStatus) {
+	records := getStatusRecords(statuses)
+
+	table := tabby.New()
+	header := make([]interface{}, 0, len(records[0]))
+	for _, column := range records[0] {
+		header = append(header, column)
+	}
+	table.AddHeader(header...)
+
+	for _, record := range records[1:] {
+		line := make([]interface{}, 0, len(record))
+		for _, value := range record {
+			line = append(line, value)
+		}
+		table.AddLine(line...)
+	}
+
+	table.Print()
+}
+
+// printCSV prints the summary in the CSV format
+func printCSV(statuses []clusterStatus, writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.WriteAll(getStatusRecords(statuses)); err != nil {
+		return err
+	}
+
+	return csvWriter.Error()
+}
+
+// openMetricsLabelEscaper escapes the values of the OpenMetrics labels
+var openMetricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// printOpenMetrics prints the summary in the OpenMetrics text
+// exposition format, to be imported in a metrics backend
+func printOpenMetrics(statuses []clusterStatus, writer io.Writer) error {
+	var builder strings.Builder
+
+	writeFamily := func(name, help string, value func(status clusterStatus) (float64, bool)) {
+		_, _ = fmt.Fprintf(&builder, "# TYPE %s gauge\n# HELP %s %s\n", name, name, help)
+		for _, status := range statuses {
+			if metricValue, ok := value(status); ok {
+				_, _ = fmt.Fprintf(&builder, "%s{namespace=\"%s\",name=\"%s\"} %s\n",
+					name,
+					openMetricsLabelEscaper.Replace(status.Namespace),
+					openMetricsLabelEscaper.Replace(status.Name),
+					strconv.FormatFloat(metricValue, 'f', -1, 64))
+			}
+		}
+	}
+
+	_, _ = fmt.Fprint(&builder,
+		"# TYPE cnpg_fleet_cluster_info gauge\n"+
+			"# HELP cnpg_fleet_cluster_info Information about the cluster, always 1\n")
+	for _, status := range statuses {
+		_, _ = fmt.Fprintf(&builder,
+			"cnpg_fleet_cluster_info{namespace=\"%s\",name=\"%s\",version=\"%s\",primary=\"%s\","+
+				"primary_zone=\"%s\",archiving=\"%s\"} 1\n",
+			openMetricsLabelEscaper.Replace(status.Namespace),
+			openMetricsLabelEscaper.Replace(status.Name),
+			formatPostgresVersion(status.PostgresVersion),
+			openMetricsLabelEscaper.Replace(status.CurrentPrimary),
+			openMetricsLabelEscaper.Replace(status.PrimaryZone),
+			status.Archiving)
+	}
+
+	writeFamily("cnpg_fleet_cluster_instances", "Number of desired instances",
+		func(status clusterStatus) (float64, bool) {
+			return float64(status.Instances), true
+		})
+	writeFamily("cnpg_fleet_cluster_ready_instances", "Number of ready instances",
+		func(status clusterStatus) (float64, bool) {
+			return float64(status.ReadyInstances), true
+		})
+	writeFamily("cnpg_fleet_cluster_last_backup_timestamp_seconds",
+		"Time when the last successful backup stopped",
+		func(status clusterStatus) (float64, bool) {
+			if status.Backup.LastSuccessfulBackup == nil {
+				return 0, false
+			}
+			return float64(status.Backup.LastSuccessfulBackup.Unix()), true
+		})
+	writeFamily("cnpg_fleet_cluster_archiving_healthy",
+		"1 if the WAL archiving is working, 0 if it is failing",
+		func(status clusterStatus) (float64, bool) {
+			switch status.Archiving {
+			case archivingOK:
+				return 1, true
+			case archivingFailing:
+				return 0, true
+			default:
+				return 0, false
+			}
+		})
+
+	builder.WriteString("# EOF\n")
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
@@ -0,0 +1,196 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"bytes"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fleet status", func() {
+	lastBackup := metav1.NewTime(time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC))
+
+	newCluster := func(namespace, name string, archiving *metav1.ConditionStatus) apiv1.Cluster {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			},
+			Status: apiv1.ClusterStatus{
+				ReadyInstances: 2,
+				CurrentPrimary: name + "-1",
+			},
+		}
+		if archiving != nil {
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+			}
+			cluster.Status.Conditions = []metav1.Condition{{
+				Type:   string(apiv1.ConditionContinuousArchiving),
+				Status: *archiving,
+			}}
+		}
+		return cluster
+	}
+
+	conditionStatus := func(status metav1.ConditionStatus) *metav1.ConditionStatus {
+		return &status
+	}
+
+	Context("newClusterStatuses", func() {
+		It("returns an empty summary for an empty fleet", func() {
+			statuses := newClusterStatuses(nil, nil, nil)
+			Expect(statuses).ToNot(BeNil())
+			Expect(statuses).To(BeEmpty())
+		})
+
+		It("sorts the clusters and reports the primary zone and the archiving health", func() {
+			clusters := []apiv1.Cluster{
+				newCluster("team-b", "orders", conditionStatus(metav1.ConditionFalse)),
+				newCluster("team-a", "users", conditionStatus(metav1.ConditionTrue)),
+				newCluster("team-a", "billing", nil),
+				newCluster("team-c", "events", conditionStatus(metav1.ConditionUnknown)),
+			}
+			backups := []apiv1.Backup{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "users-backup"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "users"}},
+				Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted, StoppedAt: &lastBackup},
+			}}
+
+			statuses := newClusterStatuses(clusters, backups, map[string]string{"team-a/users": "eu-west-1a"})
+			Expect(statuses).To(HaveLen(4))
+
+			Expect(statuses[0].Namespace).To(Equal("team-a"))
+			Expect(statuses[0].Name).To(Equal("billing"))
+			Expect(statuses[0].PrimaryZone).To(BeEmpty())
+			Expect(statuses[0].Archiving).To(Equal(archivingNotConfigured))
+
+			Expect(statuses[1].Name).To(Equal("users"))
+			Expect(statuses[1].PrimaryZone).To(Equal("eu-west-1a"))
+			Expect(statuses[1].Archiving).To(Equal(archivingOK))
+			Expect(statuses[1].Backup.LastSuccessfulBackup).To(Equal(&lastBackup))
+
+			Expect(statuses[2].Name).To(Equal("orders"))
+			Expect(statuses[2].Archiving).To(Equal(archivingFailing))
+
+			Expect(statuses[3].Name).To(Equal("events"))
+			Expect(statuses[3].Archiving).To(Equal(archivingUnknown))
+		})
+
+		It("reports an unknown archiving health until the condition is set", func() {
+			cluster := newCluster("default", "example", conditionStatus(metav1.ConditionTrue))
+			cluster.Status.Conditions = nil
+			statuses := newClusterStatuses([]apiv1.Cluster{cluster}, nil, nil)
+			Expect(statuses[0].Archiving).To(Equal(archivingUnknown))
+		})
+	})
+
+	Context("printCSV", func() {
+		It("prints only the header for an empty fleet", func() {
+			var output bytes.Buffer
+			Expect(printCSV(nil, &output)).To(Succeed())
+			Expect(output.String()).To(Equal(
+				"Namespace,Name,Version,Instances,Ready,Primary,Primary zone,Last backup,Archiving\n"))
+		})
+
+		It("prints a row for every cluster, quoting the values when needed", func() {
+			statuses := newClusterStatuses(
+				[]apiv1.Cluster{newCluster("default", "example", conditionStatus(metav1.ConditionTrue))},
+				[]apiv1.Backup{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example-backup"},
+					Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "example"}},
+					Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted, StoppedAt: &lastBackup},
+				}},
+				map[string]string{"default/example": `zone "a",b`})
+
+			var output bytes.Buffer
+			Expect(printCSV(statuses, &output)).To(Succeed())
+			Expect(output.String()).To(Equal(
+				"Namespace,Name,Version,Instances,Ready,Primary,Primary zone,Last backup,Archiving\n" +
+					`default,example,16.2,3,2,example-1,"zone ""a"",b",2026-10-15T10:30:00Z,OK` + "\n"))
+		})
+	})
+
+	Context("printOpenMetrics", func() {
+		It("prints the metric families without samples for an empty fleet", func() {
+			var output bytes.Buffer
+			Expect(printOpenMetrics(nil, &output)).To(Succeed())
+			Expect(output.String()).To(Equal(
+				"# TYPE cnpg_fleet_cluster_info gauge\n" +
+					"# HELP cnpg_fleet_cluster_info Information about the cluster, always 1\n" +
+					"# TYPE cnpg_fleet_cluster_instances gauge\n" +
+					"# HELP cnpg_fleet_cluster_instances Number of desired instances\n" +
+					"# TYPE cnpg_fleet_cluster_ready_instances gauge\n" +
+					"# HELP cnpg_fleet_cluster_ready_instances Number of ready instances\n" +
+					"# TYPE cnpg_fleet_cluster_last_backup_timestamp_seconds gauge\n" +
+					"# HELP cnpg_fleet_cluster_last_backup_timestamp_seconds " +
+					"Time when the last successful backup stopped\n" +
+					"# TYPE cnpg_fleet_cluster_archiving_healthy gauge\n" +
+					"# HELP cnpg_fleet_cluster_archiving_healthy " +
+					"1 if the WAL archiving is working, 0 if it is failing\n" +
+					"# EOF\n"))
+		})
+
+		It("escapes the values of the labels", func() {
+			statuses := newClusterStatuses(
+				[]apiv1.Cluster{newCluster("default", "example", conditionStatus(metav1.ConditionFalse))},
+				nil,
+				map[string]string{"default/example": "zone \"a\"\\b\nc"})
+
+			var output bytes.Buffer
+			Expect(printOpenMetrics(statuses, &output)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(
+				`cnpg_fleet_cluster_info{namespace="default",name="example",version="16.2",primary="example-1",` +
+					`primary_zone="zone \"a\"\\b\nc",archiving="Failing"} 1` + "\n"))
+		})
+
+		It("only prints the samples that are known", func() {
+			statuses := newClusterStatuses(
+				[]apiv1.Cluster{
+					newCluster("default", "archived", conditionStatus(metav1.ConditionTrue)),
+					newCluster("default", "unarchived", nil),
+				},
+				[]apiv1.Backup{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "archived-backup"},
+					Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "archived"}},
+					Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted, StoppedAt: &lastBackup},
+				}},
+				nil)
+
+			var output bytes.Buffer
+			Expect(printOpenMetrics(statuses, &output)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(
+				`cnpg_fleet_cluster_ready_instances{namespace="default",name="unarchived"} 2` + "\n"))
+			Expect(output.String()).To(ContainSubstring(
+				`cnpg_fleet_cluster_last_backup_timestamp_seconds{namespace="default",name="archived"} 1792060200` + "\n"))
+			Expect(output.String()).ToNot(ContainSubstring(
+				`cnpg_fleet_cluster_last_backup_timestamp_seconds{namespace="default",name="unarchived"}`))
+			Expect(output.String()).To(ContainSubstring(
+				`cnpg_fleet_cluster_archiving_healthy{namespace="default",name="archived"} 1` + "\n"))
+			Expect(output.String()).ToNot(ContainSubstring(
+				`cnpg_fleet_cluster_archiving_healthy{namespace="default",name="unarchived"}`))
+		})
+	})
+})
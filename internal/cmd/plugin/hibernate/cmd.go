@@ -26,11 +26,15 @@ import (
 
 var (
 	hibernateOnCmd = &cobra.Command{
-		Use:   "on [cluster]",
-		Short: "Hibernates the cluster named [cluster]",
-		Args:  cobra.ExactArgs(1),
+		Use:               "on [cluster]",
+		Short:             "Hibernates the cluster named [cluster]",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
 			force, err := cmd.Flags().GetBool("force")
 			if err != nil {
 				return err
@@ -46,22 +50,30 @@ var (
 	}
 
 	hibernateOffCmd = &cobra.Command{
-		Use:   "off [cluster]",
-		Short: "Bring the cluster named [cluster] back from hibernation",
-		Args:  cobra.ExactArgs(1),
+		Use:               "off [cluster]",
+		Short:             "Bring the cluster named [cluster] back from hibernation",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
 			off := newOffCommand(cmd.Context(), clusterName)
 			return off.execute()
 		},
 	}
 
 	hibernateStatusCmd = &cobra.Command{
-		Use:   "status [cluster]",
-		Short: "Prints the hibernation status for the [cluster]",
-		Args:  cobra.ExactArgs(1),
+		Use:               "status [cluster]",
+		Short:             "Prints the hibernation status for the [cluster]",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
 			rawOutput, err := cmd.Flags().GetString("output")
 			if err != nil {
 				return err
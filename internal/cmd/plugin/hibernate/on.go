@@ -157,7 +157,7 @@ func (on *onCommand) checkPreconditionsStep() error {
 	contextLogger := log.FromContext(on.ctx)
 
 	// We should refuse to hibernate a cluster that was fenced already
-	fencedInstances, err := utils.GetFencedInstances(on.cluster.Annotations)
+	fencedInstances, err := on.cluster.GetFencedInstances()
 	if err != nil {
 		return fmt.Errorf("could not check if cluster is fenced: %v", err)
 	}
@@ -278,6 +278,18 @@ func (on *onCommand) deleteResourcesStep() error {
 	}
 	on.printAdvancement("primary instance destroy completed")
 
+	// The instances are shut down, and the final backup cannot be taken
+	if on.cluster.IsFinalBackupEnabled() {
+		origCluster := on.cluster.DeepCopy()
+		if on.cluster.Annotations == nil {
+			on.cluster.Annotations = make(map[string]string)
+		}
+		on.cluster.Annotations[utils.SkipFinalBackupAnnotationName] = "true"
+		if err := plugin.Client.Patch(on.ctx, on.cluster, client.MergeFrom(origCluster)); err != nil {
+			return fmt.Errorf("error while disabling the final backup: %w", err)
+		}
+	}
+
 	on.printAdvancement("deleting the cluster resource")
 	if err := plugin.Client.Delete(on.ctx, on.cluster); err != nil {
 		return fmt.Errorf("error while deleting cluster resource: %w", err)
@@ -53,15 +53,21 @@ type generateExecutor struct {
 	replicas             int32
 	userRequestedVersion string
 	postgresImage        string
+	namespacedRBAC       bool
 }
 
 func newGenerateCmd() *cobra.Command {
 	var version, watchNamespaces, postgresImage string
 	var replicas int32
+	var namespacedRBAC bool
 	cmd := &cobra.Command{
 		Use:   "generate",
 		Short: "generates the YAML manifests needed to install the CloudNativePG operator",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if namespacedRBAC && watchNamespaces == "" {
+				return fmt.Errorf("--namespaced-rbac requires the list of the namespaces to watch")
+			}
+
 			// we consider the namespace only if explicitly passed for this command
 			namespace := ""
 			if plugin.NamespaceExplicitlyPassed {
@@ -75,6 +81,7 @@ func newGenerateCmd() *cobra.Command {
 				replicas:             replicas,
 				userRequestedVersion: version,
 				postgresImage:        postgresImage,
+				namespacedRBAC:       namespacedRBAC,
 			}
 			return command.execute()
 		},
@@ -110,6 +117,15 @@ func newGenerateCmd() *cobra.Command {
 		"Optional flag to specify a PostgreSQL image to use. If not specified, the default image is used",
 	)
 
+	cmd.Flags().BoolVar(
+		&namespacedRBAC,
+		"namespaced-rbac",
+		false,
+		"Grant the operator the permissions on the namespaced resources with a Role in every watched namespace, "+
+			"instead of a ClusterRole. Requires --watch-namespace. The features requiring access to the Nodes "+
+			"and to the Namespaces are disabled",
+	)
+
 	return cmd
 }
 
@@ -130,6 +146,12 @@ func (cmd *generateExecutor) execute() error {
 		return err
 	}
 
+	if cmd.namespacedRBAC {
+		if irs, err = cmd.reconcileNamespacedRBAC(irs); err != nil {
+			return err
+		}
+	}
+
 	return cmd.printResources(irs)
 }
 
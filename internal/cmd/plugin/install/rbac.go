@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// clusterScopedResources contains the cluster-scoped resources the operator
+// has permissions on, in the "group/resource" format. The value is true when
+// the permission is required by the operator, and false when the operator
+// can work without it, disabling the features depending on it
+var clusterScopedResources = map[string]bool{
+	"admissionregistration.k8s.io/mutatingwebhookconfigurations":   true,
+	"admissionregistration.k8s.io/validatingwebhookconfigurations": true,
+	"apiextensions.k8s.io/customresourcedefinitions":               true,
+	"authentication.k8s.io/tokenreviews":                           true,
+	"authorization.k8s.io/subjectaccessreviews":                    true,
+	"postgresql.cnpg.io/clusterimagecatalogs":                      false,
+	"/namespaces": false,
+	"/nodes":      false,
+}
+
+// namedOnlyVerbs are the verbs which can be restricted to a list
+// of resource names
+var namedOnlyVerbs = map[string]bool{
+	"get":    true,
+	"update": true,
+	"patch":  true,
+	"delete": true,
+}
+
+// isClusterScopedResource checks if a resource, or one of its
+// subresources, is cluster-scoped, and if it is required
+func isClusterScopedResource(group, resource string) (clusterScoped bool, required bool) {
+	resource, _, _ = strings.Cut(resource, "/")
+	required, clusterScoped = clusterScopedResources[group+"/"+resource]
+	return clusterScoped, required
+}
+
+// reconcileNamespacedRBAC replaces the permissions granted to the operator
+// by the ClusterRole with a Role and a RoleBinding in every watched namespace
+// and in the namespace of the operator. The ClusterRole only keeps the
+// required permissions on the cluster-scoped resources, restricted to the
+// ones created by the installation manifest
+func (cmd *generateExecutor) reconcileNamespacedRBAC(irs []installationResource) ([]installationResource, error) {
+	var clusterRole *rbacv1.ClusterRole
+	var clusterRoleBinding *rbacv1.ClusterRoleBinding
+	resourceNames := make(map[string][]string)
+	for _, ir := range irs {
+		switch obj := ir.obj.(type) {
+		case *rbacv1.ClusterRole:
+			clusterRole = obj
+		case *rbacv1.ClusterRoleBinding:
+			clusterRoleBinding = obj
+		case *apiextensionsv1.CustomResourceDefinition:
+			key := "apiextensions.k8s.io/customresourcedefinitions"
+			resourceNames[key] = append(resourceNames[key], obj.Name)
+		case *admissionregistrationv1.MutatingWebhookConfiguration:
+			key := "admissionregistration.k8s.io/mutatingwebhookconfigurations"
+			resourceNames[key] = append(resourceNames[key], obj.Name)
+		case *admissionregistrationv1.ValidatingWebhookConfiguration:
+			key := "admissionregistration.k8s.io/validatingwebhookconfigurations"
+			resourceNames[key] = append(resourceNames[key], obj.Name)
+		}
+	}
+	if clusterRole == nil || clusterRoleBinding == nil || len(clusterRoleBinding.Subjects) == 0 {
+		return nil, fmt.Errorf("cannot find the operator ClusterRole and ClusterRoleBinding in the manifest")
+	}
+
+	clusterRules, namespacedRules := splitPolicyRules(clusterRole.Rules, resourceNames)
+	clusterRole.Rules = clusterRules
+
+	namespaces := (&configuration.Data{WatchNamespace: cmd.watchNamespace}).WatchedNamespaces()
+	operatorNamespace := clusterRoleBinding.Subjects[0].Namespace
+	if !stringset.From(namespaces).Has(operatorNamespace) {
+		namespaces = append(namespaces, operatorNamespace)
+	}
+
+	for _, namespace := range namespaces {
+		irs = append(irs,
+			installationResource{obj: &rbacv1.Role{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "Role",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterRole.Name,
+					Namespace: namespace,
+					Labels:    clusterRole.Labels,
+				},
+				Rules: namespacedRules,
+			}},
+			installationResource{obj: &rbacv1.RoleBinding{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "RoleBinding",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterRoleBinding.Name,
+					Namespace: namespace,
+					Labels:    clusterRoleBinding.Labels,
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "Role",
+					Name:     clusterRole.Name,
+				},
+				Subjects: clusterRoleBinding.Subjects,
+			}},
+		)
+	}
+
+	return irs, nil
+}
+
+// splitPolicyRules splits the passed rules between the ones on the required
+// cluster-scoped resources, restricted to the passed resource names, and the
+// ones on the namespaced resources. The rules on the cluster-scoped resources
+// which are not required are dropped
+func splitPolicyRules(
+	rules []rbacv1.PolicyRule,
+	resourceNames map[string][]string,
+) (clusterRules []rbacv1.PolicyRule, namespacedRules []rbacv1.PolicyRule) {
+	for _, rule := range rules {
+		if len(rule.NonResourceURLs) > 0 {
+			continue
+		}
+
+		for _, group := range rule.APIGroups {
+			var namespacedResources []string
+			for _, resource := range rule.Resources {
+				clusterScoped, required := isClusterScopedResource(group, resource)
+				switch {
+				case !clusterScoped:
+					namespacedResources = append(namespacedResources, resource)
+				case required:
+					clusterRules = append(clusterRules,
+						restrictPolicyRule(group, resource, rule.Verbs, resourceNames[group+"/"+resource]))
+				}
+			}
+
+			if len(namespacedResources) > 0 {
+				namespacedRules = append(namespacedRules, rbacv1.PolicyRule{
+					APIGroups:     []string{group},
+					Resources:     namespacedResources,
+					Verbs:         rule.Verbs,
+					ResourceNames: rule.ResourceNames,
+				})
+			}
+		}
+	}
+
+	return clusterRules, namespacedRules
+}
+
+// restrictPolicyRule creates a rule on a cluster-scoped resource restricted
+// to the passed resource names. When there are no resource names, the rule
+// is left unrestricted, as it is for resources like the TokenReviews which
+// are only created
+func restrictPolicyRule(group, resource string, verbs []string, resourceNames []string) rbacv1.PolicyRule {
+	if len(resourceNames) == 0 {
+		return rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: []string{resource},
+			Verbs:     verbs,
+		}
+	}
+
+	var namedVerbs []string
+	for _, verb := range verbs {
+		if namedOnlyVerbs[verb] {
+			namedVerbs = append(namedVerbs, verb)
+		}
+	}
+
+	return rbacv1.PolicyRule{
+		APIGroups:     []string{group},
+		Resources:     []string{resource},
+		Verbs:         namedVerbs,
+		ResourceNames: resourceNames,
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd creates the new 'maintenance' command
@@ -38,7 +40,8 @@ func NewCmd() *cobra.Command {
 		Short: "Sets maintenance mode",
 		Long: "This command will set maintenance mode on a single cluster or on all clusters " +
 			"in the current namespace if not specified differently through flags",
-		Args: cobra.MaximumNArgs(1),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var clusterName string
 			if len(args) > 0 {
@@ -56,7 +59,8 @@ func NewCmd() *cobra.Command {
 		Short: "Removes maintenance mode",
 		Long: "This command will unset maintenance mode on a single cluster or on all clusters " +
 			"in the current namespace if not specified differently through flags",
-		Args: cobra.MaximumNArgs(1),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var clusterName string
 			if len(args) > 0 {
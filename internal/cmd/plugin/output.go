@@ -28,4 +28,10 @@ const (
 
 	// OutputFormatYAML means use machine-readable JSON output
 	OutputFormatYAML = "yaml"
+
+	// OutputFormatCSV means use machine-readable CSV output
+	OutputFormatCSV = "csv"
+
+	// OutputFormatOpenMetrics means use the OpenMetrics text exposition format
+	OutputFormatOpenMetrics = "openmetrics"
 )
@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd initializes the pgBench command
@@ -29,11 +31,12 @@ func NewCmd() *cobra.Command {
 	var dryRun bool
 
 	pgBenchCmd := &cobra.Command{
-		Use:     "pgbench [cluster] [-- pgBenchCommandArgs...]",
-		Short:   "Creates a pgbench job",
-		Args:    validateCommandArgs,
-		Long:    `Creates a pgbench job that will be executed on the specified Postgres Cluster.`,
-		Example: jobExample,
+		Use:               "pgbench [cluster] [-- pgBenchCommandArgs...]",
+		Short:             "Creates a pgbench job",
+		Args:              validateCommandArgs,
+		ValidArgsFunction: plugin.CompleteClusters,
+		Long:              `Creates a pgbench job that will be executed on the specified Postgres Cluster.`,
+		Example:           jobExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			clusterName := args[0]
@@ -0,0 +1,129 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrClusterNameRequired is raised when the cluster name is not passed and
+// cannot be asked interactively
+var ErrClusterNameRequired = errors.New("the cluster name is required")
+
+// GetClusterName returns the cluster name passed as first argument. When it
+// is omitted and the command runs in a terminal, the user is asked to pick
+// one of the clusters in the namespace
+func GetClusterName(ctx context.Context, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	if !isTerminal(os.Stdin) {
+		return "", ErrClusterNameRequired
+	}
+
+	names, err := getClusterNames(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return pickCluster(os.Stdin, os.Stdout, names)
+}
+
+// pickCluster asks the user to choose one of the passed clusters, either by
+// its number or by a part of its name. The list is narrowed down at each
+// answer until a single cluster matches
+func pickCluster(in io.Reader, out io.Writer, names []string) (string, error) {
+	if len(names) == 0 {
+		return "", fmt.Errorf("no cluster found in namespace %s", Namespace)
+	}
+
+	reader := bufio.NewReader(in)
+	candidates := names
+	for {
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+
+		for idx, name := range candidates {
+			_, _ = fmt.Fprintf(out, "%3d) %s\n", idx+1, name)
+		}
+		_, _ = fmt.Fprint(out, "Pick a cluster (number or part of the name): ")
+
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if err != nil && (err != io.EOF || answer == "") {
+			return "", ErrClusterNameRequired
+		}
+
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(candidates) {
+			return candidates[number-1], nil
+		}
+
+		matches := fuzzyFilter(candidates, answer)
+		if len(matches) == 0 {
+			_, _ = fmt.Fprintf(out, "No cluster matches %q\n", answer)
+			continue
+		}
+		candidates = matches
+	}
+}
+
+// fuzzyFilter returns the names containing every character of the pattern,
+// in the same order
+func fuzzyFilter(names []string, pattern string) []string {
+	pattern = strings.ToLower(pattern)
+
+	var result []string
+	for _, name := range names {
+		if isSubsequence(pattern, strings.ToLower(name)) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func isSubsequence(pattern, value string) bool {
+	valueRunes := []rune(value)
+	position := 0
+	for _, char := range pattern {
+		for position < len(valueRunes) && valueRunes[position] != char {
+			position++
+		}
+		if position == len(valueRunes) {
+			return false
+		}
+		position++
+	}
+	return true
+}
+
+// isTerminal checks if the passed file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
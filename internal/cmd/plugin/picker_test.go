@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster picker", func() {
+	names := []string{"cluster-example", "cluster-prod", "pg-analytics"}
+
+	It("fuzzy matches the cluster names", func() {
+		Expect(fuzzyFilter(names, "cex")).To(Equal([]string{"cluster-example"}))
+		Expect(fuzzyFilter(names, "CLU")).To(Equal([]string{"cluster-example", "cluster-prod"}))
+		Expect(fuzzyFilter(names, "xyz")).To(BeEmpty())
+		Expect(fuzzyFilter(names, "")).To(Equal(names))
+	})
+
+	It("picks a cluster by number", func() {
+		var out bytes.Buffer
+		name, err := pickCluster(strings.NewReader("2\n"), &out, names)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("cluster-prod"))
+		Expect(out.String()).To(ContainSubstring("  3) pg-analytics"))
+	})
+
+	It("narrows down the clusters until one matches", func() {
+		var out bytes.Buffer
+		name, err := pickCluster(strings.NewReader("clu\nunknown\nprod\n"), &out, names)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("cluster-prod"))
+		Expect(out.String()).To(ContainSubstring(`No cluster matches "unknown"`))
+	})
+
+	It("fails when no answer is given", func() {
+		_, err := pickCluster(strings.NewReader(""), &bytes.Buffer{}, names)
+		Expect(err).To(MatchError(ErrClusterNameRequired))
+	})
+
+	It("picks the only cluster without asking", func() {
+		var out bytes.Buffer
+		name, err := pickCluster(strings.NewReader(""), &out, []string{"cluster-example"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("cluster-example"))
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("fails when there are no clusters", func() {
+		_, err := pickCluster(strings.NewReader(""), &bytes.Buffer{}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("shell completion", func() {
+	It("filters the completions by prefix", func() {
+		Expect(filterCompletions([]string{"cluster-example-1", "cluster-example-2", "other"}, "cluster")).
+			To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+		Expect(filterCompletions(nil, "")).To(BeEmpty())
+	})
+
+	It("completes the serial numbers of the instances", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status: apiv1.ClusterStatus{
+				InstanceNames: []string{"cluster-example-1", "cluster-example-2"},
+			},
+		}
+		Expect(getInstanceIDs(cluster)).To(Equal([]string{"1", "2"}))
+
+		cluster.Spec.InstanceNamePrefix = "pg"
+		cluster.Spec.InstanceOrdinalPadding = 3
+		cluster.Status.InstanceNames = []string{"pg-001", "pg-002", "cluster-example-3"}
+		Expect(getInstanceIDs(cluster)).To(Equal([]string{"001", "002"}))
+	})
+})
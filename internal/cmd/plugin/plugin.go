@@ -18,6 +18,10 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -39,6 +43,10 @@ var (
 
 	// Client is the controller-runtime client
 	Client client.Client
+
+	// KubectlFlags are the flags selecting the Kubernetes cluster, to be
+	// passed to the kubectl commands run by the plugin
+	KubectlFlags []string
 )
 
 // SetupKubernetesClient creates a k8s client to be used inside the kubectl-cnpg
@@ -63,9 +71,41 @@ func SetupKubernetesClient(configFlags *genericclioptions.ConfigFlags) error {
 		return err
 	}
 
+	KubectlFlags = getKubectlFlags(configFlags)
+
 	return nil
 }
 
+// getKubectlFlags gets the flags selecting the kubeconfig file and the
+// context that were passed to the plugin
+func getKubectlFlags(configFlags *genericclioptions.ConfigFlags) []string {
+	var flags []string
+	if configFlags.KubeConfig != nil && *configFlags.KubeConfig != "" {
+		flags = append(flags, "--kubeconfig", *configFlags.KubeConfig)
+	}
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		flags = append(flags, "--context", *configFlags.Context)
+	}
+	return flags
+}
+
+// GetInstanceName gets the name of an instance of a cluster, given its
+// name or its serial number, honoring the instance name prefix and the
+// ordinal padding of the cluster
+func GetInstanceName(ctx context.Context, clusterName, node string) (string, error) {
+	serial, err := strconv.Atoi(node)
+	if err != nil {
+		return node, nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := Client.Get(ctx, client.ObjectKey{Namespace: Namespace, Name: clusterName}, &cluster); err != nil {
+		return "", fmt.Errorf("cluster %s not found in namespace %s: %w", clusterName, Namespace, err)
+	}
+
+	return cluster.GetInstanceName(serial), nil
+}
+
 func createClient(cfg *rest.Config) error {
 	var err error
 	scheme := runtime.NewScheme()
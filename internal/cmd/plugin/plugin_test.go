@@ -17,6 +17,15 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -28,3 +37,52 @@ var _ = Describe("create client", func() {
 		Expect(Client).NotTo(BeNil())
 	})
 })
+
+var _ = Describe("kubectl flags", func() {
+	It("forwards the kubeconfig and the context", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		Expect(getKubectlFlags(configFlags)).To(BeEmpty())
+
+		kubeconfig, context := "/tmp/kubeconfig", "staging"
+		configFlags.KubeConfig = &kubeconfig
+		configFlags.Context = &context
+		Expect(getKubectlFlags(configFlags)).To(Equal([]string{
+			"--kubeconfig", "/tmp/kubeconfig", "--context", "staging",
+		}))
+	})
+})
+
+var _ = Describe("instance name", func() {
+	BeforeEach(func() {
+		previousClient, previousNamespace := Client, Namespace
+		DeferCleanup(func() {
+			Client, Namespace = previousClient, previousNamespace
+		})
+
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				InstanceNamePrefix:     "pg",
+				InstanceOrdinalPadding: 3,
+			},
+		}
+		Client = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		Namespace = "default"
+	})
+
+	It("builds the name of the instance from its serial number", func() {
+		Expect(GetInstanceName(context.Background(), "cluster-example", "2")).To(Equal("pg-002"))
+	})
+
+	It("keeps the names of the instances", func() {
+		Expect(GetInstanceName(context.Background(), "cluster-example", "pg-002")).To(Equal("pg-002"))
+	})
+
+	It("fails when the cluster doesn't exist", func() {
+		_, err := GetInstanceName(context.Background(), "missing", "2")
+		Expect(err).To(HaveOccurred())
+	})
+})
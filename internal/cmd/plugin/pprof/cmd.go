@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pprof
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var pprofExample = `
+  # Capture the heap profile of the instance manager of "cluster-example-1"
+  kubectl-cnpg pprof instance cluster-example 1 --profile heap
+
+  # Capture a CPU profile of the operator lasting one minute
+  kubectl-cnpg pprof operator -n cnpg-system --profile profile --seconds 60 --file operator.pprof`
+
+// NewCmd creates the new "pprof" command
+func NewCmd() *cobra.Command {
+	var profile string
+	var seconds int
+	var file string
+
+	cmd := &cobra.Command{
+		Use:     "pprof [instance/operator]",
+		Short:   "Captures a pprof profile of an instance manager or of the operator",
+		Example: pprofExample,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:               "instance [cluster] [node]",
+		Short:             "Captures a profile of the instance manager running in [cluster]-[node] or [node]",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstances,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			node, err := plugin.GetInstanceName(ctx, clusterName, args[1])
+			if err != nil {
+				return err
+			}
+			return captureInstanceProfile(ctx, clusterName, node, profile, seconds, file)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "operator",
+		Short: "Captures a profile of the operator running in the namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return captureOperatorProfile(context.Background(), profile, seconds, file)
+		},
+	})
+
+	cmd.PersistentFlags().StringVar(&profile, "profile", "heap",
+		"The profile to capture, like heap, goroutine, allocs, profile (CPU) or trace")
+	cmd.PersistentFlags().IntVar(&seconds, "seconds", 0,
+		"The duration of the CPU profile or of the execution trace. Defaults to the one of the endpoint")
+	cmd.PersistentFlags().StringVarP(&file, "file", "f", "",
+		"The file where the profile is written. Defaults to <pod>-<profile>.pprof")
+
+	return cmd
+}
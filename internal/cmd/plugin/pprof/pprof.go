@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pprof implements the kubectl-cnpg pprof command
+package pprof
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// operatorContainerName is the name of the container running the operator
+	operatorContainerName = "manager"
+
+	// operatorPodLabelValue is the value of the "app.kubernetes.io/name"
+	// label of the operator pods
+	operatorPodLabelValue = "cloudnative-pg"
+)
+
+// captureInstanceProfile captures a profile of the instance manager running
+// in the passed pod
+func captureInstanceProfile(
+	ctx context.Context,
+	clusterName, podName string,
+	profile string,
+	seconds int,
+	file string,
+) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s: %w", clusterName, plugin.Namespace, err)
+	}
+	if !utils.IsInstancePprofEnabled(&cluster.ObjectMeta) {
+		return fmt.Errorf("pprof is disabled, annotate the cluster with %s=enabled",
+			utils.InstancePprofAnnotationName)
+	}
+
+	var pod corev1.Pod
+	if err := plugin.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: plugin.Namespace, Name: podName}, &pod); err != nil {
+		return fmt.Errorf("instance %s not found in namespace %s: %w", podName, plugin.Namespace, err)
+	}
+
+	return captureProfile(ctx, pod, specs.PostgresContainerName,
+		[]string{"/controller/manager", "debug", "pprof", "--port", strconv.Itoa(url.LocalPort)},
+		profile, seconds, file)
+}
+
+// captureOperatorProfile captures a profile of the operator running in the
+// current namespace. The operator must have been started with the
+// pprof server enabled
+func captureOperatorProfile(ctx context.Context, profile string, seconds int, file string) error {
+	var podList corev1.PodList
+	if err := plugin.Client.List(
+		ctx, &podList,
+		ctrlclient.MatchingLabels{"app.kubernetes.io/name": operatorPodLabelValue},
+		ctrlclient.InNamespace(plugin.Namespace)); err != nil {
+		return err
+	}
+
+	for _, pod := range podList.Items {
+		if utils.IsPodActive(pod) && utils.IsPodReady(pod) {
+			return captureProfile(ctx, pod, operatorContainerName,
+				[]string{"/manager", "debug", "pprof", "--port", strconv.Itoa(url.OperatorPprofPort)},
+				profile, seconds, file)
+		}
+	}
+
+	return fmt.Errorf("no ready operator pod found in namespace %s", plugin.Namespace)
+}
+
+// captureProfile runs the passed command in the pod, writing the profile
+// it outputs into the passed file
+func captureProfile(
+	ctx context.Context,
+	pod corev1.Pod,
+	containerName string,
+	command []string,
+	profile string,
+	seconds int,
+	file string,
+) error {
+	command = append(command, "--seconds", strconv.Itoa(seconds), profile)
+	if file == "" {
+		file = fmt.Sprintf("%s-%s.pprof", pod.Name, profile)
+	}
+
+	timeout := time.Duration(seconds)*time.Second + 2*time.Minute
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		pod,
+		containerName,
+		&timeout,
+		command...)
+	if err != nil {
+		return fmt.Errorf("while capturing the %s profile of %s: %w (%s)", profile, pod.Name, err, stderr)
+	}
+
+	if err := os.WriteFile(file, []byte(stdout), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("The %s profile of %s has been written to %s\n", profile, pod.Name, file)
+	return nil
+}
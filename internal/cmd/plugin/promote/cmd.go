@@ -18,24 +18,25 @@ package promote
 
 import (
 	"context"
-	"fmt"
-	"strconv"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd create the new "promote" subcommand
 func NewCmd() *cobra.Command {
 	promoteCmd := &cobra.Command{
-		Use:   "promote [cluster] [node]",
-		Short: "Promote the pod named [cluster]-[node] or [node] to primary",
-		Args:  cobra.ExactArgs(2),
+		Use:               "promote [cluster] [node]",
+		Short:             "Promote the pod named [cluster]-[node] or [node] to primary",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstances,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			clusterName := args[0]
-			node := args[1]
-			if _, err := strconv.Atoi(args[1]); err == nil {
-				node = fmt.Sprintf("%s-%s", clusterName, node)
+			node, err := plugin.GetInstanceName(ctx, clusterName, args[1])
+			if err != nil {
+				return err
 			}
 			return Promote(ctx, clusterName, node)
 		},
@@ -46,6 +46,11 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return nil
 	}
 
+	// The restricted replicas can never be promoted
+	if cluster.IsRestrictedReplica(serverName) {
+		return fmt.Errorf("%s is a restricted replica and cannot be promoted", serverName)
+	}
+
 	// Check if the Pod exist
 	var pod v1.Pod
 	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: serverName}, &pod)
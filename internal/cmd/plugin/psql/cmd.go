@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psql
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var psqlExample = `
+  # Open an interactive psql session on the primary of "cluster-example"
+  kubectl-cnpg psql cluster-example
+
+  # Open an interactive psql session on a replica, connecting to the "app" database
+  kubectl-cnpg psql cluster-example --replica -- -d app
+
+  # Run a query without an interactive session
+  kubectl-cnpg psql cluster-example --command "SELECT pg_is_in_recovery()"
+
+  # Run a query in the "app" database as the "app" user, from a script
+  kubectl-cnpg psql exec cluster-example "SELECT count(*) FROM pg_stat_activity" -- -d app -U app`
+
+// NewCmd creates the new "psql" command
+func NewCmd() *cobra.Command {
+	var replica bool
+	var command string
+
+	cmd := &cobra.Command{
+		Use:   "psql [cluster] [-- psql options]",
+		Short: "Starts a psql session on an instance of the cluster",
+		Long: "Starts a psql session on the primary instance of the cluster, or on a replica " +
+			"with the --replica option. The options following \"--\" are passed to psql",
+		Example:           psqlExample,
+		ValidArgsFunction: plugin.CompleteClusters,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterArgs, psqlArgs := args, []string(nil)
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				clusterArgs, psqlArgs = args[:dash], args[dash:]
+			}
+			if len(clusterArgs) > 1 {
+				return cmd.Help()
+			}
+
+			clusterName, err := plugin.GetClusterName(ctx, clusterArgs)
+			if err != nil {
+				return err
+			}
+
+			options := psqlOptions{
+				clusterName: clusterName,
+				replica:     replica,
+				command:     command,
+				args:        psqlArgs,
+			}
+			if command != "" {
+				return runCommand(ctx, options)
+			}
+			return startSession(ctx, options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&replica, "replica", false,
+		"Connect to a replica instead of the primary")
+	cmd.Flags().StringVarP(&command, "command", "c", "",
+		"Run the passed command and print its result instead of starting an interactive session")
+
+	cmd.AddCommand(newExecCmd())
+
+	return cmd
+}
+
+// newExecCmd creates the "psql exec" subcommand, running a command
+// without an interactive session
+func newExecCmd() *cobra.Command {
+	var replica bool
+
+	cmd := &cobra.Command{
+		Use:   "exec cluster command [-- psql options]",
+		Short: "Runs a command through psql on an instance of the cluster",
+		Long: "Runs a command through psql on the primary instance of the cluster, or on a replica " +
+			"with the --replica option, printing its result. The options following \"--\" are passed to psql",
+		ValidArgsFunction: plugin.CompleteClusters,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			execArgs, psqlArgs := args, []string(nil)
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				execArgs, psqlArgs = args[:dash], args[dash:]
+			}
+			if len(execArgs) != 2 {
+				return cmd.Help()
+			}
+
+			return runCommand(cmd.Context(), psqlOptions{
+				clusterName: execArgs[0],
+				replica:     replica,
+				command:     execArgs[1],
+				args:        psqlArgs,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&replica, "replica", false,
+		"Run the command on a replica instead of the primary")
+
+	return cmd
+}
@@ -0,0 +1,178 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package psql implements the kubectl-cnpg psql command
+package psql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// commandTimeout is the maximum time a non-interactive command can run
+const commandTimeout = 5 * time.Minute
+
+// psqlOptions are the options of the psql command
+type psqlOptions struct {
+	// The name of the cluster
+	clusterName string
+
+	// Whether to connect to a replica instead of the primary
+	replica bool
+
+	// The command to be run in non-interactive mode, if any
+	command string
+
+	// The options passed to psql
+	args []string
+}
+
+// startSession runs kubectl, attaching the terminal to a psql session
+// running in the target instance
+func startSession(ctx context.Context, options psqlOptions) error {
+	cluster, pod, err := getTarget(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubectl is needed to start an interactive session: %w", err)
+	}
+
+	// #nosec G204
+	session := exec.CommandContext(ctx, kubectl, buildKubectlArgs(cluster, pod, options)...)
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+	return session.Run()
+}
+
+// runCommand runs the passed command in the target instance, printing
+// its output
+func runCommand(ctx context.Context, options psqlOptions) error {
+	cluster, pod, err := getTarget(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	timeout := commandTimeout
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		*pod,
+		specs.PostgresContainerName,
+		&timeout,
+		buildPsqlCommand(cluster, pod, options)...)
+	fmt.Print(stdout)
+	if err != nil {
+		return fmt.Errorf("while running the command on %s: %w (%s)", pod.Name, err, stderr)
+	}
+
+	return nil
+}
+
+// buildKubectlArgs creates the arguments of the kubectl command starting
+// the interactive session, selecting the same Kubernetes cluster the
+// plugin is connected to
+func buildKubectlArgs(cluster *apiv1.Cluster, pod *corev1.Pod, options psqlOptions) []string {
+	kubectlArgs := make([]string, 0, len(plugin.KubectlFlags)+8)
+	kubectlArgs = append(kubectlArgs, plugin.KubectlFlags...)
+	kubectlArgs = append(kubectlArgs,
+		"exec", "-it", "-n", pod.Namespace, pod.Name, "-c", specs.PostgresContainerName, "--")
+	return append(kubectlArgs, buildPsqlCommand(cluster, pod, options)...)
+}
+
+// buildPsqlCommand creates the psql command line. The session connects
+// through TLS to the instance, verifying it with the server CA, and
+// authenticates with the client certificate of the streaming_replica user
+// that the operator manages. The options passed to psql take precedence
+// over the connection parameters set in the environment
+func buildPsqlCommand(cluster *apiv1.Cluster, pod *corev1.Pod, options psqlOptions) []string {
+	command := []string{
+		"env",
+		"PGHOST=" + cluster.GetInstanceHostName(pod.Name),
+		fmt.Sprintf("PGPORT=%d", postgres.ServerPort),
+		"PGUSER=" + apiv1.StreamingReplicationUser,
+		"PGDATABASE=postgres",
+		"PGSSLMODE=verify-full",
+		"PGSSLROOTCERT=" + postgres.ServerCACertificateLocation,
+		"PGSSLCERT=" + postgres.StreamingReplicaCertificateLocation,
+		"PGSSLKEY=" + postgres.StreamingReplicaKeyLocation,
+		"psql",
+	}
+	if options.command != "" {
+		command = append(command, "--no-psqlrc", "--command", options.command)
+	}
+	return append(command, options.args...)
+}
+
+// getTarget gets the cluster and the Pod the session is started in
+func getTarget(ctx context.Context, options psqlOptions) (*apiv1.Cluster, *corev1.Pod, error) {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx,
+		ctrlclient.ObjectKey{Namespace: plugin.Namespace, Name: options.clusterName}, &cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cluster %s not found in namespace %s: %w",
+			options.clusterName, plugin.Namespace, err)
+	}
+
+	podName, err := getTargetInstance(&cluster, options.replica)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pod corev1.Pod
+	if err := plugin.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: plugin.Namespace, Name: podName}, &pod); err != nil {
+		return nil, nil, fmt.Errorf("instance %s not found in namespace %s: %w", podName, plugin.Namespace, err)
+	}
+
+	return &cluster, &pod, nil
+}
+
+// getTargetInstance gets the name of the instance the session is started
+// in: the current primary or, when requested, the first healthy replica
+func getTargetInstance(cluster *apiv1.Cluster, replica bool) (string, error) {
+	if !replica {
+		if cluster.Status.CurrentPrimary == "" {
+			return "", fmt.Errorf("cluster %s has no primary instance", cluster.Name)
+		}
+		return cluster.Status.CurrentPrimary, nil
+	}
+
+	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
+		if instance != cluster.Status.CurrentPrimary {
+			return instance, nil
+		}
+	}
+
+	return "", fmt.Errorf("cluster %s has no healthy replica", cluster.Name)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psql
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("psql command", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			InstancesStatus: map[utils.PodStatus][]string{
+				utils.PodHealthy: {"cluster-example-1", "cluster-example-2"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"},
+	}
+
+	It("connects through TLS with the certificates of the operator", func() {
+		command := buildPsqlCommand(cluster, pod, psqlOptions{args: []string{"-d", "app"}})
+		Expect(command).To(Equal([]string{
+			"env",
+			"PGHOST=cluster-example-1.cluster-example-any.default.svc",
+			"PGPORT=5432",
+			"PGUSER=streaming_replica",
+			"PGDATABASE=postgres",
+			"PGSSLMODE=verify-full",
+			"PGSSLROOTCERT=/controller/certificates/server-ca.crt",
+			"PGSSLCERT=/controller/certificates/streaming_replica.crt",
+			"PGSSLKEY=/controller/certificates/streaming_replica.key",
+			"psql",
+			"-d", "app",
+		}))
+	})
+
+	It("runs the passed command without reading the psqlrc file", func() {
+		command := buildPsqlCommand(cluster, pod, psqlOptions{command: "SELECT 1"})
+		Expect(command[len(command)-4:]).To(Equal([]string{"psql", "--no-psqlrc", "--command", "SELECT 1"}))
+	})
+
+	It("forwards the kubeconfig and the context to kubectl", func() {
+		DeferCleanup(func(flags []string) { plugin.KubectlFlags = flags }, plugin.KubectlFlags)
+		plugin.KubectlFlags = []string{"--kubeconfig", "/tmp/kubeconfig", "--context", "staging"}
+
+		kubectlArgs := buildKubectlArgs(cluster, pod, psqlOptions{})
+		Expect(kubectlArgs[:11]).To(Equal([]string{
+			"--kubeconfig", "/tmp/kubeconfig", "--context", "staging",
+			"exec", "-it", "-n", "default", "cluster-example-1", "-c", "postgres",
+		}))
+		Expect(kubectlArgs[11:13]).To(Equal([]string{"--", "env"}))
+		Expect(kubectlArgs[len(kubectlArgs)-1]).To(Equal("psql"))
+	})
+
+	It("chooses the primary or a healthy replica", func() {
+		Expect(getTargetInstance(cluster, false)).To(Equal("cluster-example-1"))
+		Expect(getTargetInstance(cluster, true)).To(Equal("cluster-example-2"))
+
+		_, err := getTargetInstance(&apiv1.Cluster{}, false)
+		Expect(err).To(HaveOccurred())
+		_, err = getTargetInstance(&apiv1.Cluster{Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			InstancesStatus: map[utils.PodStatus][]string{
+				utils.PodHealthy: {"cluster-example-1"},
+			},
+		}}, true)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("adds the exec subcommand", func() {
+		cmd := NewCmd()
+		execCmd, _, err := cmd.Find([]string{"exec"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(execCmd.Name()).To(Equal("exec"))
+		Expect(execCmd.Flags().Lookup("replica")).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rebuild
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "rebuild" subcommand
+func NewCmd() *cobra.Command {
+	var dryRun bool
+	var drainTimeout time.Duration
+
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild [CLUSTER_NAME] [INSTANCE_ID]",
+		Short: "Destroy the replica named [CLUSTER_NAME]-[INSTANCE_ID] and clone a new one from the primary",
+		Long: "Diagnose the replica named [CLUSTER_NAME]-[INSTANCE_ID] and rebuild it: the instance is fenced, " +
+			"waiting for it to be removed from the services, and then destroyed with its PVCs, " +
+			"letting the operator clone a new replica from the primary",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: plugin.CompleteClusterInstanceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			instanceID := args[1]
+			return Rebuild(ctx, clusterName, instanceID, drainTimeout, dryRun)
+		},
+	}
+
+	rebuildCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Only print the diagnostics of the replica, without rebuilding it")
+	rebuildCmd.Flags().DurationVar(&drainTimeout, "drain-timeout", 2*time.Minute,
+		"The time to wait for the replica to be removed from the services")
+
+	return rebuildCmd
+}
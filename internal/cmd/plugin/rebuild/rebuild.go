@@ -0,0 +1,243 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rebuild implements a command to destroy a replica and clone it
+// again from the primary
+package rebuild
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// drainCheckInterval is the interval between two checks of the
+// readiness of the instance being drained
+const drainCheckInterval = 2 * time.Second
+
+// Rebuild implements the rebuild subcommand
+func Rebuild(
+	ctx context.Context,
+	clusterName, instanceID string,
+	drainTimeout time.Duration,
+	dryRun bool,
+) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+
+	instanceName := instanceID
+	if serial, err := strconv.Atoi(instanceID); err == nil {
+		instanceName = cluster.GetInstanceName(serial)
+	}
+
+	if err := checkPreconditions(&cluster, instanceName); err != nil {
+		return err
+	}
+
+	pods, primaryPod, err := resources.GetInstancePods(ctx, clusterName)
+	if err != nil {
+		return fmt.Errorf("could not get cluster pods: %w", err)
+	}
+
+	var instancePods []corev1.Pod
+	for idx := range pods {
+		if pods[idx].Name == instanceName || pods[idx].Name == primaryPod.Name {
+			instancePods = append(instancePods, pods[idx])
+		}
+	}
+	statusList := resources.ExtractInstancesStatus(ctx, plugin.Config, instancePods, specs.PostgresContainerName)
+
+	var instanceStatus, primaryStatus *postgres.PostgresqlStatus
+	for idx := range statusList.Items {
+		switch statusList.Items[idx].Pod.Name {
+		case instanceName:
+			instanceStatus = &statusList.Items[idx]
+		case primaryPod.Name:
+			primaryStatus = &statusList.Items[idx]
+		}
+	}
+
+	fmt.Printf("Diagnostics of %s:\n", instanceName)
+	for _, line := range diagnose(instanceStatus, primaryStatus) {
+		fmt.Printf("  - %s\n", line)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if instanceStatus != nil {
+		fmt.Printf("fencing %s to remove it from the services\n", instanceName)
+		if err := fence.ApplyFenceFunc(
+			ctx, plugin.Client, clusterName, plugin.Namespace, instanceName, utils.AddFencedInstance,
+		); err != nil {
+			return fmt.Errorf("could not fence the instance: %w", err)
+		}
+
+		if err := waitForDrain(ctx, instanceName, drainTimeout); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("destroying %s and its PVCs\n", instanceName)
+	if err := destroy.Destroy(ctx, clusterName, instanceID, false); err != nil {
+		return err
+	}
+
+	// The fenced instance doesn't exist anymore, and the annotation
+	// must not refer to it
+	if err := removeFencedInstance(ctx, clusterName, instanceName); err != nil {
+		return fmt.Errorf("could not remove %s from the fenced instances: %w", instanceName, err)
+	}
+
+	fmt.Printf("%s destroyed, the operator will clone a new replica from the primary\n", instanceName)
+	return nil
+}
+
+// checkPreconditions refuses to rebuild an instance that is not a replica
+func checkPreconditions(cluster *apiv1.Cluster, instanceName string) error {
+	if !slices.Contains(cluster.Status.InstanceNames, instanceName) {
+		return fmt.Errorf("instance %s is not part of cluster %s", instanceName, cluster.Name)
+	}
+
+	if instanceName == cluster.Status.CurrentPrimary || instanceName == cluster.Status.TargetPrimary {
+		return fmt.Errorf("instance %s is the primary and cannot be rebuilt, promote another instance first",
+			instanceName)
+	}
+
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return fmt.Errorf("a switchover or a failover is in progress, cannot rebuild %s", instanceName)
+	}
+
+	return nil
+}
+
+// diagnose describes the problems detected on the replica, comparing its
+// status with the one of the primary
+func diagnose(instance, primary *postgres.PostgresqlStatus) []string {
+	if instance == nil {
+		return []string{"the instance Pod doesn't exist"}
+	}
+	if instance.Error != nil {
+		return []string{fmt.Sprintf("the instance status is not available: %v", instance.Error)}
+	}
+
+	var result []string
+	if instance.IsPrimary {
+		result = append(result, "the standby.signal file is missing: the instance is running as a primary")
+	} else if !instance.IsWalReceiverActive {
+		result = append(result, "the WAL receiver is not running")
+	}
+
+	if primary != nil && primary.Error == nil && primary.SystemID != instance.SystemID {
+		result = append(result, fmt.Sprintf("the system identifier %s differs from the one of the primary (%s)",
+			instance.SystemID, primary.SystemID))
+	}
+
+	if primary != nil && primary.Error == nil && primary.TimeLineID != instance.TimeLineID {
+		result = append(result, fmt.Sprintf("the instance is on timeline %d, while the primary is on timeline %d",
+			instance.TimeLineID, primary.TimeLineID))
+	}
+
+	if instance.ReplayPaused {
+		result = append(result, "the WAL replay is paused")
+	}
+
+	if instance.IsPgRewindRunning {
+		result = append(result, "pg_rewind is running")
+	}
+
+	if !utils.IsPodReady(instance.Pod) {
+		result = append(result, "the instance Pod is not ready")
+	}
+
+	if len(result) == 0 {
+		result = append(result, "no problem detected")
+	}
+
+	return result
+}
+
+// waitForDrain waits for the fenced instance to stop being ready, being
+// removed from the endpoints of the services
+func waitForDrain(ctx context.Context, instanceName string, timeout time.Duration) error {
+	err := wait.PollImmediate(drainCheckInterval, timeout, func() (bool, error) {
+		var pod corev1.Pod
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: instanceName},
+			&pod,
+		); err != nil {
+			return false, err
+		}
+		return !utils.IsPodReady(pod), nil
+	})
+	if err != nil {
+		return fmt.Errorf("instance %s has not been drained: %w", instanceName, err)
+	}
+
+	return nil
+}
+
+// removeFencedInstance removes the passed instance from the fencing
+// annotation of the cluster, without checking the Pod existence
+func removeFencedInstance(ctx context.Context, clusterName, instanceName string) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return err
+	}
+	if !fencedInstances.Has(instanceName) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if err := utils.RemoveFencedInstance(instanceName, &cluster.ObjectMeta); err != nil {
+		return err
+	}
+	cluster.ManagedFields = nil
+
+	return plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster))
+}
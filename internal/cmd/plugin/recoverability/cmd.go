@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recoverability
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "verify-recoverability" subcommand
+func NewCmd() *cobra.Command {
+	var targetTime string
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify-recoverability [cluster]",
+		Short: "Check whether the archive allows the cluster to be recovered to a point in time",
+		Long: "Check, without restoring, whether the object store contains a base backup and " +
+			"the WAL files needed to recover the cluster to the target time, reporting the gaps",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName, err := plugin.GetClusterName(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+
+			return Verify(ctx, clusterName, targetTime, plugin.OutputFormat(output))
+		},
+	}
+
+	verifyCmd.Flags().StringVar(
+		&targetTime, "target-time", "", "The point in time to be checked, in RFC3339 format")
+	_ = verifyCmd.MarkFlagRequired("target-time")
+	verifyCmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json")
+
+	return verifyCmd
+}
@@ -0,0 +1,267 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recoverability implements the kubectl-cnpg verify-recoverability command
+package recoverability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// errNotRecoverable is returned when the archive doesn't allow
+// recovering the cluster to the target time
+var errNotRecoverable = errors.New("the target time is not recoverable")
+
+// Report is the result of the recoverability check
+type Report struct {
+	// TargetTime is the point in time that has been checked
+	TargetTime time.Time `json:"targetTime"`
+
+	// BackupID is the ID of the base backup that would be used
+	// by the recovery, if any
+	BackupID string `json:"backupID,omitempty"`
+
+	// BackupEndTime is the time when the base backup ended
+	BackupEndTime *time.Time `json:"backupEndTime,omitempty"`
+
+	// FirstRequiredWAL is the first WAL file needed by the recovery
+	FirstRequiredWAL string `json:"firstRequiredWAL,omitempty"`
+
+	// LastArchivedWAL is the last WAL file archived by the primary
+	LastArchivedWAL string `json:"lastArchivedWAL,omitempty"`
+
+	// LastArchivedWALTime is the time when the last WAL file was archived
+	LastArchivedWALTime *time.Time `json:"lastArchivedWALTime,omitempty"`
+
+	// MissingWALs is the list of the WAL files, between the first required
+	// one and the last archived one, which are not in the archive
+	MissingWALs []string `json:"missingWALs,omitempty"`
+
+	// CorruptedWALs is the list of the WAL files, between the first required
+	// one and the last archived one, which cannot be fetched from the archive
+	// or whose content is not valid
+	CorruptedWALs []string `json:"corruptedWALs,omitempty"`
+
+	// Gaps is the list of the problems preventing the recovery
+	Gaps []string `json:"gaps,omitempty"`
+}
+
+// walArchiveCheck is the output of the "show missing-wals" command
+// of the instance manager
+type walArchiveCheck struct {
+	Missing   []string `json:"missing,omitempty"`
+	Corrupted []string `json:"corrupted,omitempty"`
+}
+
+// Verify implements the "verify-recoverability" subcommand
+func Verify(ctx context.Context, clusterName string, targetTime string, format plugin.OutputFormat) error {
+	parsedTargetTime, err := utils.ParseTargetTime(nil, targetTime)
+	if err != nil {
+		return fmt.Errorf("while parsing the target time: %w", err)
+	}
+
+	_, primaryPod, err := resources.GetInstancePods(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if primaryPod.Name == "" {
+		return fmt.Errorf("no primary instance found for cluster %s", clusterName)
+	}
+
+	instancesStatus := resources.ExtractInstancesStatus(
+		ctx,
+		plugin.Config,
+		[]corev1.Pod{primaryPod},
+		specs.PostgresContainerName)
+	if len(instancesStatus.Items) != 1 || instancesStatus.Items[0].Error != nil {
+		return fmt.Errorf("cannot get the status of the primary instance %s", primaryPod.Name)
+	}
+
+	stdout, err := execManagerCommand(ctx, primaryPod, time.Minute, "show", "backup-catalog")
+	if err != nil {
+		return fmt.Errorf("while reading the backup catalog: %w", err)
+	}
+
+	var backupList catalog.Catalog
+	if err := json.Unmarshal([]byte(stdout), &backupList); err != nil {
+		return fmt.Errorf("while parsing the backup catalog: %w", err)
+	}
+
+	report := checkRecoverability(&backupList, &instancesStatus.Items[0], parsedTargetTime, time.Now())
+	if report.FirstRequiredWAL != "" && report.LastArchivedWAL != "" {
+		// Every WAL file is fetched from the object store, which can take a while
+		stdout, err := execManagerCommand(ctx, primaryPod, 30*time.Minute,
+			"show", "missing-wals", "--from", report.FirstRequiredWAL, "--to", report.LastArchivedWAL)
+		if err != nil {
+			return fmt.Errorf("while checking the WAL files in the archive: %w", err)
+		}
+
+		var archiveCheck walArchiveCheck
+		if err := json.Unmarshal([]byte(stdout), &archiveCheck); err != nil {
+			return fmt.Errorf("while parsing the WAL files check: %w", err)
+		}
+		report.addWALArchiveGaps(&archiveCheck)
+	}
+
+	if err := plugin.Print(report, format, os.Stdout); err != nil {
+		return err
+	}
+
+	if format == plugin.OutputFormatText {
+		report.print()
+	}
+
+	if len(report.Gaps) > 0 {
+		return errNotRecoverable
+	}
+	return nil
+}
+
+// execManagerCommand runs a command of the instance manager in the
+// PostgreSQL container of the passed pod, returning its standard output
+func execManagerCommand(ctx context.Context, pod corev1.Pod, timeout time.Duration, args ...string) (string, error) {
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		append([]string{"/controller/manager"}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("%w (%s)", err, stderr)
+	}
+
+	return stdout, nil
+}
+
+// checkRecoverability checks whether the catalog of the backups and the
+// status of the WAL archiving of the primary allow a recovery to the target time
+func checkRecoverability(
+	backupList *catalog.Catalog,
+	primary *postgres.PostgresqlStatus,
+	targetTime time.Time,
+	now time.Time,
+) *Report {
+	report := &Report{TargetTime: targetTime}
+
+	if targetTime.After(now) {
+		report.Gaps = append(report.Gaps, "the target time is in the future")
+	}
+
+	backup, err := backupList.FindBackupInfo(&apiv1.RecoveryTarget{TargetTime: targetTime.Format(time.RFC3339Nano)})
+	switch {
+	case err != nil:
+		report.Gaps = append(report.Gaps, fmt.Sprintf("cannot search the backup catalog: %v", err))
+	case backup == nil && backupList.FirstRecoverabilityPoint() == nil:
+		report.Gaps = append(report.Gaps, "the archive contains no completed base backup")
+	case backup == nil:
+		report.Gaps = append(report.Gaps, fmt.Sprintf(
+			"no base backup completed before the target time, the first one ended at %s",
+			backupList.FirstRecoverabilityPoint().Format(time.RFC3339)))
+	default:
+		endTime := backup.EndTime
+		report.BackupID = backup.ID
+		report.BackupEndTime = &endTime
+		report.FirstRequiredWAL = backup.BeginWal
+	}
+
+	report.LastArchivedWAL = primary.LastArchivedWAL
+	lastArchivedTime, err := utils.ParseTargetTime(nil, primary.LastArchivedWALTime)
+	if primary.LastArchivedWAL == "" || err != nil {
+		report.Gaps = append(report.Gaps, "no WAL file has been archived by the primary instance")
+		return report
+	}
+	report.LastArchivedWALTime = &lastArchivedTime
+
+	if !lastArchivedTime.Before(targetTime) {
+		return report
+	}
+
+	// The WAL files are archived in order, so the changes made after the last
+	// archived WAL file are not in the archive
+	gap := fmt.Sprintf(
+		"the WAL files written after %s, archived at %s, are not in the archive yet",
+		primary.LastArchivedWAL,
+		lastArchivedTime.Format(time.RFC3339))
+	if !primary.IsArchivingWAL && primary.LastFailedWAL != "" {
+		gap += fmt.Sprintf(": the archiving of %s is failing since %s",
+			primary.LastFailedWAL, primary.LastFailedWALTime)
+	}
+	report.Gaps = append(report.Gaps, gap)
+
+	return report
+}
+
+// addWALArchiveGaps adds to the report the WAL files, between the first
+// required one and the last archived one, which cannot be fetched from
+// the archive. A missing WAL file breaks the chain needed by the recovery
+func (report *Report) addWALArchiveGaps(archiveCheck *walArchiveCheck) {
+	report.MissingWALs = archiveCheck.Missing
+	report.CorruptedWALs = archiveCheck.Corrupted
+
+	if len(archiveCheck.Missing) > 0 {
+		report.Gaps = append(report.Gaps, fmt.Sprintf(
+			"the WAL files %s are missing from the archive",
+			strings.Join(archiveCheck.Missing, ", ")))
+	}
+	if len(archiveCheck.Corrupted) > 0 {
+		report.Gaps = append(report.Gaps, fmt.Sprintf(
+			"the WAL files %s cannot be fetched from the archive or are not valid",
+			strings.Join(archiveCheck.Corrupted, ", ")))
+	}
+}
+
+// print prints the report in a human-readable format
+func (report *Report) print() {
+	fmt.Printf("Target time: %s\n", report.TargetTime.Format(time.RFC3339))
+	if report.BackupID != "" {
+		fmt.Printf("Base backup: %s (ended at %s)\n", report.BackupID, report.BackupEndTime.Format(time.RFC3339))
+		fmt.Printf("First required WAL: %s\n", report.FirstRequiredWAL)
+	}
+	if report.LastArchivedWALTime != nil {
+		fmt.Printf("Last archived WAL: %s (archived at %s)\n",
+			report.LastArchivedWAL, report.LastArchivedWALTime.Format(time.RFC3339))
+	}
+
+	if len(report.Gaps) == 0 {
+		fmt.Println("The target time is recoverable")
+		return
+	}
+
+	fmt.Println("The target time is not recoverable:")
+	for _, gap := range report.Gaps {
+		fmt.Printf("- %s\n", gap)
+	}
+}
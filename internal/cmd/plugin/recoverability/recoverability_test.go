@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recoverability
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Recoverability check", func() {
+	now := time.Date(2022, 10, 20, 12, 0, 0, 0, time.UTC)
+	backupList := &catalog.Catalog{
+		List: []catalog.BarmanBackup{
+			{
+				ID:        "20221020T093000",
+				BeginTime: time.Date(2022, 10, 20, 9, 30, 0, 0, time.UTC),
+				EndTime:   time.Date(2022, 10, 20, 9, 31, 12, 0, time.UTC),
+				BeginWal:  "000000010000000000000006",
+				EndWal:    "000000010000000000000007",
+				TimeLine:  1,
+			},
+		},
+	}
+	primary := &postgres.PostgresqlStatus{
+		IsArchivingWAL:      true,
+		LastArchivedWAL:     "00000001000000000000000C",
+		LastArchivedWALTime: "2022-10-20T11:55:02Z",
+	}
+
+	ginkgo.It("reports a recoverable target time", func() {
+		report := checkRecoverability(backupList, primary, time.Date(2022, 10, 20, 11, 52, 31, 0, time.UTC), now)
+		gomega.Expect(report.Gaps).To(gomega.BeEmpty())
+		gomega.Expect(report.BackupID).To(gomega.Equal("20221020T093000"))
+		gomega.Expect(report.FirstRequiredWAL).To(gomega.Equal("000000010000000000000006"))
+		gomega.Expect(report.LastArchivedWAL).To(gomega.Equal("00000001000000000000000C"))
+	})
+
+	ginkgo.It("reports the target times in the future", func() {
+		report := checkRecoverability(backupList, primary, now.Add(time.Hour), now)
+		gomega.Expect(report.Gaps).To(gomega.ContainElement("the target time is in the future"))
+	})
+
+	ginkgo.It("reports the target times preceding the first base backup", func() {
+		report := checkRecoverability(backupList, primary, time.Date(2022, 10, 20, 9, 0, 0, 0, time.UTC), now)
+		gomega.Expect(report.BackupID).To(gomega.BeEmpty())
+		gomega.Expect(report.Gaps).To(gomega.ConsistOf(
+			gomega.ContainSubstring("no base backup completed before the target time")))
+	})
+
+	ginkgo.It("reports an empty archive", func() {
+		report := checkRecoverability(&catalog.Catalog{}, primary, time.Date(2022, 10, 20, 11, 0, 0, 0, time.UTC), now)
+		gomega.Expect(report.Gaps).To(gomega.ConsistOf("the archive contains no completed base backup"))
+	})
+
+	ginkgo.It("reports the WAL files not archived yet, together with the archiving failure", func() {
+		failingPrimary := &postgres.PostgresqlStatus{
+			IsArchivingWAL:      false,
+			LastArchivedWAL:     "00000001000000000000000C",
+			LastArchivedWALTime: "2022-10-20T11:55:02Z",
+			LastFailedWAL:       "00000001000000000000000D",
+			LastFailedWALTime:   "2022-10-20T11:58:00Z",
+		}
+		report := checkRecoverability(backupList, failingPrimary, time.Date(2022, 10, 20, 11, 59, 0, 0, time.UTC), now)
+		gomega.Expect(report.Gaps).To(gomega.HaveLen(1))
+		gomega.Expect(report.Gaps[0]).To(gomega.ContainSubstring("written after 00000001000000000000000C"))
+		gomega.Expect(report.Gaps[0]).To(gomega.ContainSubstring("the archiving of 00000001000000000000000D is failing"))
+	})
+
+	ginkgo.It("reports a primary that never archived a WAL file", func() {
+		report := checkRecoverability(backupList, &postgres.PostgresqlStatus{},
+			time.Date(2022, 10, 20, 11, 0, 0, 0, time.UTC), now)
+		gomega.Expect(report.Gaps).To(gomega.ConsistOf("no WAL file has been archived by the primary instance"))
+	})
+
+	ginkgo.It("reports the gaps in the WAL archive", func() {
+		report := checkRecoverability(backupList, primary, time.Date(2022, 10, 20, 11, 52, 31, 0, time.UTC), now)
+		report.addWALArchiveGaps(&walArchiveCheck{})
+		gomega.Expect(report.Gaps).To(gomega.BeEmpty())
+
+		report.addWALArchiveGaps(&walArchiveCheck{
+			Missing:   []string{"000000010000000000000008", "000000010000000000000009"},
+			Corrupted: []string{"00000001000000000000000B"},
+		})
+		gomega.Expect(report.MissingWALs).To(gomega.Equal([]string{"000000010000000000000008", "000000010000000000000009"}))
+		gomega.Expect(report.CorruptedWALs).To(gomega.Equal([]string{"00000001000000000000000B"}))
+		gomega.Expect(report.Gaps).To(gomega.ConsistOf(
+			"the WAL files 000000010000000000000008, 000000010000000000000009 are missing from the archive",
+			"the WAL files 00000001000000000000000B cannot be fetched from the archive or are not valid",
+		))
+	})
+})
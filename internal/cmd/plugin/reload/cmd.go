@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd creates the new "reset" command
@@ -27,11 +29,16 @@ func NewCmd() *cobra.Command {
 	restartCmd := &cobra.Command{
 		Use:   "reload [clusterName]",
 		Short: `Reload the cluster`,
-		Long:  `Triggers a reconciliation loop for all the cluster's instances, rolling out new configurations if present.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Triggers a reconciliation loop for all the cluster's instances, ` +
+			`rolling out new configurations if present.`,
+		ValidArgsFunction: plugin.CompleteClusters,
+		Args:              cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(ctx, args)
+			if err != nil {
+				return err
+			}
 			return Reload(ctx, clusterName)
 		},
 	}
@@ -33,12 +33,16 @@ func clusterCmd() *cobra.Command {
 	const filePlaceholder = "report_cluster_<name>_<timestamp>.zip"
 
 	cmd := &cobra.Command{
-		Use:   "cluster <clusterName>",
-		Short: "Report cluster resources, pods, events, logs (opt-in)",
-		Long:  "Collects combined information on the cluster in a Zip file",
-		Args:  cobra.ExactArgs(1),
+		Use:               "cluster [clusterName]",
+		Short:             "Report cluster resources, pods, events, logs (opt-in)",
+		Long:              "Collects combined information on the cluster in a Zip file",
+		ValidArgsFunction: plugin.CompleteClusters,
+		Args:              cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
 			now := time.Now().UTC()
 			if file == filePlaceholder {
 				file = reportName("cluster", now, clusterName) + ".zip"
@@ -23,12 +23,13 @@ import (
 // NewCmd creates the new "report" command
 func NewCmd() *cobra.Command {
 	reportCmd := &cobra.Command{
-		Use:   "report operator/cluster",
+		Use:   "report operator/cluster/diagnostics",
 		Short: "Report on the operator",
 	}
 
 	reportCmd.AddCommand(operatorCmd())
 	reportCmd.AddCommand(clusterCmd())
+	reportCmd.AddCommand(diagnosticsCmd())
 
 	return reportCmd
 }
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+func diagnosticsCmd() *cobra.Command {
+	var (
+		file, output      string
+		operatorNamespace string
+		redactSecrets     bool
+		logLines          int64
+	)
+
+	const filePlaceholder = "report_diagnostics_<timestamp>.zip"
+
+	cmd := &cobra.Command{
+		Use:   "diagnostics [clusterName]",
+		Short: "Report operator and cluster diagnostics for support tickets",
+		Long: "Collects the operator logs, the Cluster, Backup and Pooler manifests, the events, " +
+			"the instance status, the pg_controldata output and the recent PostgreSQL logs " +
+			"in a Zip file. When no cluster is passed, every cluster managed by the operator " +
+			"is included",
+		ValidArgsFunction: plugin.CompleteClusters,
+		Args:              cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now().UTC()
+			if file == filePlaceholder {
+				file = reportName("diagnostics", now) + ".zip"
+			}
+
+			var clusterName string
+			if len(args) > 0 {
+				clusterName = args[0]
+			}
+
+			return diagnostics(cmd.Context(), diagnosticsOptions{
+				clusterName:       clusterName,
+				operatorNamespace: operatorNamespace,
+				format:            plugin.OutputFormat(output),
+				file:              file,
+				stopRedaction:     !redactSecrets,
+				logLines:          logLines,
+			}, now)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", filePlaceholder,
+		"Output file")
+	cmd.Flags().StringVarP(&output, "output", "o", "yaml",
+		"Output format (yaml or json)")
+	cmd.Flags().StringVar(&operatorNamespace, "operator-namespace", "cnpg-system",
+		"The namespace where the operator is installed")
+	cmd.Flags().BoolVar(&redactSecrets, "redact-secrets", true,
+		"Redact the content of the secrets and configmaps")
+	cmd.Flags().Int64Var(&logLines, "log-lines", 1000,
+		"Number of the most recent log lines to collect from each Pod")
+
+	return cmd
+}
@@ -0,0 +1,299 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// diagnosticsOptions contains the parameters of the `report diagnostics` plugin
+type diagnosticsOptions struct {
+	// clusterName is the cluster to be reported. When empty, every
+	// cluster in every namespace is included
+	clusterName       string
+	operatorNamespace string
+	format            plugin.OutputFormat
+	file              string
+	stopRedaction     bool
+	logLines          int64
+}
+
+// diagnosticsClusterReport contains the data collected for each cluster
+// by the `report diagnostics` plugin
+type diagnosticsClusterReport struct {
+	cluster        cnpgv1.Cluster
+	backups        []cnpgv1.Backup
+	poolers        []cnpgv1.Pooler
+	clusterPods    corev1.PodList
+	events         corev1.EventList
+	instancePods   []corev1.Pod
+	instanceStatus postgres.PostgresqlStatusList
+}
+
+// writeToZip makes a new section in the ZIP file, and adds in it the
+// manifests of the cluster and of the objects referring to it
+func (dr diagnosticsClusterReport) writeToZip(zipper *zip.Writer, format plugin.OutputFormat, folder string) error {
+	objects := []struct {
+		content interface{}
+		name    string
+	}{
+		{content: dr.cluster, name: "cluster"},
+		{content: dr.backups, name: "backups"},
+		{content: dr.poolers, name: "poolers"},
+		{content: dr.clusterPods, name: "cluster-pods"},
+		{content: dr.events, name: "events"},
+		{content: dr.instanceStatus, name: "instance-status"},
+	}
+
+	newFolder := filepath.Join(folder, "manifests")
+	_, err := zipper.Create(newFolder + "/")
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		err := addContentToZip(object.content, object.name, newFolder, format, zipper)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diagnostics implements the "report diagnostics" subcommand
+// Produces a zip file containing
+//   - the operator report, as in `report operator`
+//   - the most recent logs of the operator pods
+//   - for each cluster in scope, the Cluster, Backup and Pooler manifests,
+//     the cluster pods, the events and the status of the instances
+//   - for each instance, the pg_controldata output and the most recent logs
+func diagnostics(ctx context.Context, options diagnosticsOptions, now time.Time) error {
+	if options.stopRedaction {
+		fmt.Println("WARNING: secret Redaction is OFF. Use it with caution")
+	}
+
+	operatorRep, err := getOperatorReport(ctx, options.operatorNamespace, options.stopRedaction)
+	if err != nil {
+		return fmt.Errorf("could not collect the operator report: %w", err)
+	}
+
+	clusters, err := getDiagnosticsClusters(ctx, options.clusterName)
+	if err != nil {
+		return err
+	}
+
+	logOptions := &corev1.PodLogOptions{
+		Timestamps: true,
+		TailLines:  &options.logLines,
+	}
+
+	sections := []zipFileWriter{
+		func(zipper *zip.Writer, dirname string) error {
+			operatorDir := filepath.Join(dirname, "operator")
+			if _, err := zipper.Create(operatorDir + "/"); err != nil {
+				return fmt.Errorf("could not add '%s' to zip: %w", operatorDir, err)
+			}
+			if err := operatorRep.writeToZip(zipper, options.format, operatorDir); err != nil {
+				return err
+			}
+			return streamPodLogsToZip(ctx, operatorRep.operatorPods, operatorDir, "logs", zipper, logOptions)
+		},
+	}
+
+	for idx := range clusters {
+		cluster := clusters[idx]
+		sections = append(sections, func(zipper *zip.Writer, dirname string) error {
+			return writeClusterDiagnosticsToZip(ctx, cluster, options.format, logOptions, zipper, dirname)
+		})
+	}
+
+	err = writeZippedReport(sections, options.file, reportName("diagnostics", now))
+	if err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+
+	fmt.Printf("Successfully written report to \"%s\" (format: \"%s\")\n", options.file, options.format)
+
+	return nil
+}
+
+// getDiagnosticsClusters returns the clusters to be included in the
+// diagnostics bundle: the passed one, or every cluster if the name is empty
+func getDiagnosticsClusters(ctx context.Context, clusterName string) ([]cnpgv1.Cluster, error) {
+	if clusterName != "" {
+		var cluster cnpgv1.Cluster
+		err := plugin.Client.Get(ctx,
+			types.NamespacedName{Namespace: plugin.Namespace, Name: clusterName},
+			&cluster)
+		if err != nil {
+			return nil, fmt.Errorf("could not get cluster: %w", err)
+		}
+		return []cnpgv1.Cluster{cluster}, nil
+	}
+
+	var clusterList cnpgv1.ClusterList
+	if err := plugin.Client.List(ctx, &clusterList); err != nil {
+		return nil, fmt.Errorf("could not list clusters: %w", err)
+	}
+	return clusterList.Items, nil
+}
+
+// writeClusterDiagnosticsToZip collects the diagnostics of a cluster
+// and writes them in the clusters/<namespace>/<name> folder
+func writeClusterDiagnosticsToZip(
+	ctx context.Context,
+	cluster cnpgv1.Cluster,
+	format plugin.OutputFormat,
+	logOptions *corev1.PodLogOptions,
+	zipper *zip.Writer,
+	dirname string,
+) error {
+	clusterDir := filepath.Join(dirname, "clusters", cluster.Namespace, cluster.Name)
+	if _, err := zipper.Create(clusterDir + "/"); err != nil {
+		return fmt.Errorf("could not add '%s' to zip: %w", clusterDir, err)
+	}
+
+	rep, err := getDiagnosticsClusterReport(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := rep.writeToZip(zipper, format, clusterDir); err != nil {
+		return err
+	}
+
+	if err := writeControlDataToZip(ctx, rep.instancePods, zipper, clusterDir); err != nil {
+		return err
+	}
+
+	return streamPodLogsToZip(ctx, rep.clusterPods.Items, clusterDir, "logs", zipper, logOptions)
+}
+
+// getDiagnosticsClusterReport collects the objects related to the passed cluster
+func getDiagnosticsClusterReport(ctx context.Context, cluster cnpgv1.Cluster) (diagnosticsClusterReport, error) {
+	var events corev1.EventList
+	err := plugin.Client.List(ctx, &events, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return diagnosticsClusterReport{}, fmt.Errorf("could not get events: %w", err)
+	}
+
+	var pods corev1.PodList
+	err = plugin.Client.List(ctx, &pods,
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return diagnosticsClusterReport{}, fmt.Errorf("could not get cluster pods: %w", err)
+	}
+
+	var backupList cnpgv1.BackupList
+	err = plugin.Client.List(ctx, &backupList, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return diagnosticsClusterReport{}, fmt.Errorf("could not get backups: %w", err)
+	}
+	backups := make([]cnpgv1.Backup, 0, len(backupList.Items))
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name == cluster.Name {
+			backups = append(backups, backup)
+		}
+	}
+
+	var poolerList cnpgv1.PoolerList
+	err = plugin.Client.List(ctx, &poolerList, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return diagnosticsClusterReport{}, fmt.Errorf("could not get poolers: %w", err)
+	}
+	poolers := make([]cnpgv1.Pooler, 0, len(poolerList.Items))
+	for _, pooler := range poolerList.Items {
+		if pooler.Spec.Cluster.Name == cluster.Name {
+			poolers = append(poolers, pooler)
+		}
+	}
+
+	instancePods := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if utils.IsPodActive(pod) && (specs.IsPodPrimary(pod) || specs.IsPodStandby(pod)) {
+			instancePods = append(instancePods, pod)
+		}
+	}
+
+	return diagnosticsClusterReport{
+		cluster:      cluster,
+		backups:      backups,
+		poolers:      poolers,
+		clusterPods:  pods,
+		events:       events,
+		instancePods: instancePods,
+		instanceStatus: resources.ExtractInstancesStatus(
+			ctx, plugin.Config, instancePods, specs.PostgresContainerName),
+	}, nil
+}
+
+// writeControlDataToZip writes the pg_controldata output of each passed
+// instance in the pg_controldata folder. Instances where the command
+// cannot be executed get the error in place of the output
+func writeControlDataToZip(ctx context.Context, pods []corev1.Pod, zipper *zip.Writer, dirname string) error {
+	controlDataDir := filepath.Join(dirname, "pg_controldata")
+	if _, err := zipper.Create(controlDataDir + "/"); err != nil {
+		return fmt.Errorf("could not add '%s' to zip: %w", controlDataDir, err)
+	}
+
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	timeout := time.Second * 10
+	for idx := range pods {
+		pod := pods[idx]
+		path := filepath.Join(controlDataDir, pod.Name) + ".txt"
+		writer, err := zipper.Create(path)
+		if err != nil {
+			return fmt.Errorf("could not add '%s' to zip: %w", path, err)
+		}
+
+		stdout, _, err := utils.ExecCommand(
+			ctx,
+			clientInterface,
+			plugin.Config,
+			pod,
+			specs.PostgresContainerName,
+			&timeout,
+			"pg_controldata")
+		if err != nil {
+			stdout = fmt.Sprintf("could not execute pg_controldata: %v\n", err)
+		}
+
+		if _, err := writer.Write([]byte(stdout)); err != nil {
+			return fmt.Errorf("could not write '%s': %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -39,7 +39,7 @@ var podLogOptions = &corev1.PodLogOptions{
 
 // streamPodLogsToZip streams the pod logs to a new section in the ZIP
 func streamPodLogsToZip(ctx context.Context, pods []corev1.Pod,
-	dirname, name string, zipper *zip.Writer,
+	dirname, name string, zipper *zip.Writer, options *corev1.PodLogOptions,
 ) error {
 	logsdir := filepath.Join(dirname, name)
 	if _, err := zipper.Create(logsdir + "/"); err != nil {
@@ -53,7 +53,7 @@ func streamPodLogsToZip(ctx context.Context, pods []corev1.Pod,
 		if zipperErr != nil {
 			return fmt.Errorf("could not add '%s' to zip: %w", path, zipperErr)
 		}
-		if err := logs.StreamPodLogs(ctx, pod, writer, podLogOptions); err != nil {
+		if err := logs.StreamPodLogs(ctx, pod, writer, options); err != nil {
 			return err
 		}
 	}
@@ -43,29 +43,30 @@ const (
 
 var errNoOperatorDeployment = fmt.Errorf("no deployment found")
 
-// getOperatorDeployment returns the operator Deployment if there is a single one running, error otherwise
-func getOperatorDeployment(ctx context.Context) (appsv1.Deployment, error) {
+// getOperatorDeployment returns the operator Deployment if there is a single one running
+// in the passed namespace, error otherwise
+func getOperatorDeployment(ctx context.Context, namespace string) (appsv1.Deployment, error) {
 	deployment, err := tryGetOperatorDeployment(ctx,
 		ctrlclient.MatchingLabels{labelOperatorNameKey: labelOperatorName},
-		ctrlclient.InNamespace(plugin.Namespace))
+		ctrlclient.InNamespace(namespace))
 	if err != errNoOperatorDeployment {
 		return deployment, err
 	}
 
 	deployment, err = tryGetOperatorDeployment(ctx,
 		ctrlclient.HasLabels{labelOperatorKeyPrefix + "openshift-operators"},
-		ctrlclient.InNamespace(plugin.Namespace))
+		ctrlclient.InNamespace(namespace))
 	if err != errNoOperatorDeployment {
 		return deployment, err
 	}
 
 	deployment, err = tryGetOperatorDeployment(ctx,
-		ctrlclient.HasLabels{labelOperatorKeyPrefix + plugin.Namespace},
-		ctrlclient.InNamespace(plugin.Namespace))
+		ctrlclient.HasLabels{labelOperatorKeyPrefix + namespace},
+		ctrlclient.InNamespace(namespace))
 	if err == errNoOperatorDeployment {
 		return appsv1.Deployment{},
 			fmt.Errorf("could not get operator in namespace '%s': %w",
-				plugin.Namespace, err)
+				namespace, err)
 	}
 	if err != nil {
 		return appsv1.Deployment{}, err
@@ -95,8 +96,9 @@ func tryGetOperatorDeployment(ctx context.Context, options ...ctrlclient.ListOpt
 	return appsv1.Deployment{}, errNoOperatorDeployment
 }
 
-// getOperatorPods returns the operator pods if found, error otherwise
-func getOperatorPods(ctx context.Context) ([]corev1.Pod, error) {
+// getOperatorPods returns the operator pods running in the passed namespace
+// if found, error otherwise
+func getOperatorPods(ctx context.Context, namespace string) ([]corev1.Pod, error) {
 	podList := &corev1.PodList{}
 
 	// This will work for newer version of the operator, which are using
@@ -104,7 +106,7 @@ func getOperatorPods(ctx context.Context) ([]corev1.Pod, error) {
 	if err := plugin.Client.List(
 		ctx, podList,
 		ctrlclient.MatchingLabels{"app.kubernetes.io/name": labelOperatorName},
-		ctrlclient.InNamespace(plugin.Namespace)); err != nil {
+		ctrlclient.InNamespace(namespace)); err != nil {
 		return nil, err
 	}
 
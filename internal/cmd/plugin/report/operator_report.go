@@ -94,32 +94,67 @@ func (or operatorReport) writeToZip(zipper *zip.Writer, format plugin.OutputForm
 func operator(ctx context.Context, format plugin.OutputFormat,
 	file string, stopRedaction, includeLogs bool, now time.Time,
 ) error {
+	if stopRedaction {
+		fmt.Println("WARNING: secret Redaction is OFF. Use it with caution")
+	}
+
+	rep, err := getOperatorReport(ctx, plugin.Namespace, stopRedaction)
+	if err != nil {
+		return err
+	}
+
+	reportZipper := func(zipper *zip.Writer, dirname string) error {
+		return rep.writeToZip(zipper, format, dirname)
+	}
+
+	sections := []zipFileWriter{reportZipper}
+
+	if includeLogs {
+		logZipper := func(zipper *zip.Writer, dirname string) error {
+			return streamPodLogsToZip(ctx, rep.operatorPods, dirname, "operator-logs", zipper, podLogOptions)
+		}
+		sections = append(sections, logZipper)
+	}
+
+	err = writeZippedReport(sections, file, reportName("operator", now))
+	if err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+
+	fmt.Printf("Successfully written report to \"%s\" (format: \"%s\")\n", file, format)
+
+	return nil
+}
+
+// getOperatorReport collects the objects of the operator running in the
+// passed namespace, redacting the secrets and the configmaps unless
+// stopRedaction is true
+func getOperatorReport(ctx context.Context, namespace string, stopRedaction bool) (operatorReport, error) {
 	secretRedactor := redactSecret
 	configMapRedactor := redactConfigMap
 	if stopRedaction {
 		secretRedactor = passSecret
 		configMapRedactor = passConfigMap
-		fmt.Println("WARNING: secret Redaction is OFF. Use it with caution")
 	}
 
-	operatorDeployment, err := getOperatorDeployment(ctx)
+	operatorDeployment, err := getOperatorDeployment(ctx, namespace)
 	if errors.Is(err, errNoOperatorDeployment) {
 		// Try to be helpful to the user
-		return fmt.Errorf("%w\n"+
+		return operatorReport{}, fmt.Errorf("%w\n"+
 			"HINT: Operator might be installed in another namespace."+
 			"Specify a namespace using the '-n' option", err)
 	} else if err != nil {
-		return fmt.Errorf("could not get operator deployment: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get operator deployment: %w", err)
 	}
 
-	operatorPods, err := getOperatorPods(ctx)
+	operatorPods, err := getOperatorPods(ctx, namespace)
 	if err != nil {
-		return fmt.Errorf("could not get operator pod: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get operator pod: %w", err)
 	}
 
 	operatorSecrets, err := getOperatorSecrets(ctx, operatorDeployment)
 	if err != nil {
-		return fmt.Errorf("could not get operator secrets: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get operator secrets: %w", err)
 	}
 	secrets := make([]namedObject, 0, len(operatorSecrets))
 	for _, ss := range operatorSecrets {
@@ -128,7 +163,7 @@ func operator(ctx context.Context, format plugin.OutputFormat,
 
 	operatorConfigMaps, err := getOperatorConfigMaps(ctx, operatorDeployment)
 	if err != nil {
-		return fmt.Errorf("could not get operator configmap: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get operator configmap: %w", err)
 	}
 	configs := make([]namedObject, 0, len(operatorConfigMaps))
 	for _, cm := range operatorConfigMaps {
@@ -138,20 +173,20 @@ func operator(ctx context.Context, format plugin.OutputFormat,
 	var events corev1.EventList
 	err = plugin.Client.List(ctx, &events, client.InNamespace(operatorPods[0].Namespace))
 	if err != nil {
-		return fmt.Errorf("could not get events: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get events: %w", err)
 	}
 
 	mutatingWebhook, validatingWebhook, err := getWebhooks(ctx, stopRedaction)
 	if err != nil {
-		return fmt.Errorf("could not get webhooks: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get webhooks: %w", err)
 	}
 
 	webhookService, err := getWebhookService(ctx, mutatingWebhook.Items[0].Webhooks[0].ClientConfig)
 	if err != nil {
-		return fmt.Errorf("could not get webhook service: %w", err)
+		return operatorReport{}, fmt.Errorf("could not get webhook service: %w", err)
 	}
 
-	rep := operatorReport{
+	return operatorReport{
 		deployment:              operatorDeployment,
 		operatorPods:            operatorPods,
 		secrets:                 secrets,
@@ -160,27 +195,5 @@ func operator(ctx context.Context, format plugin.OutputFormat,
 		mutatingWebhookConfig:   mutatingWebhook,
 		validatingWebhookConfig: validatingWebhook,
 		webhookService:          webhookService,
-	}
-
-	reportZipper := func(zipper *zip.Writer, dirname string) error {
-		return rep.writeToZip(zipper, format, dirname)
-	}
-
-	sections := []zipFileWriter{reportZipper}
-
-	if includeLogs {
-		logZipper := func(zipper *zip.Writer, dirname string) error {
-			return streamPodLogsToZip(ctx, operatorPods, dirname, "operator-logs", zipper)
-		}
-		sections = append(sections, logZipper)
-	}
-
-	err = writeZippedReport(sections, file, reportName("operator", now))
-	if err != nil {
-		return fmt.Errorf("could not write report: %w", err)
-	}
-
-	fmt.Printf("Successfully written report to \"%s\" (format: \"%s\")\n", file, format)
-
-	return nil
+	}, nil
 }
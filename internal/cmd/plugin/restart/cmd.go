@@ -17,10 +17,9 @@ limitations under the License.
 package restart
 
 import (
-	"fmt"
-	"strconv"
-
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 )
 
 // NewCmd creates the new "reset" command
@@ -32,16 +31,17 @@ func NewCmd() *cobra.Command {
 rolling out new configurations if present.
 If a specific instance is specified, only that instance will be restarted, 
 in-place if it is a primary, deleting the pod if it is a replica.`,
-		Args: cobra.RangeArgs(1, 2),
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: plugin.CompleteClusterInstances,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			clusterName := args[0]
 			if len(args) == 1 {
 				return restart(ctx, clusterName)
 			}
-			node := args[1]
-			if _, err := strconv.Atoi(args[1]); err == nil {
-				node = fmt.Sprintf("%s-%s", clusterName, node)
+			node, err := plugin.GetInstanceName(ctx, clusterName, args[1])
+			if err != nil {
+				return err
 			}
 			return instanceRestart(ctx, clusterName, node)
 		},
@@ -29,10 +29,16 @@ func NewCmd() *cobra.Command {
 	statusCmd := &cobra.Command{
 		Use:   "status [cluster]",
 		Short: "Get the status of a PostgreSQL cluster",
-		Args:  cobra.ExactArgs(1),
+		Long: "Get the status of a PostgreSQL cluster. When the cluster is omitted, " +
+			"it can be picked interactively from the ones in the namespace",
+		ValidArgsFunction: plugin.CompleteClusters,
+		Args:              cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			clusterName := args[0]
+			clusterName, err := plugin.GetClusterName(ctx, args)
+			if err != nil {
+				return err
+			}
 
 			verbose, _ := cmd.Flags().GetBool("verbose")
 			output, _ := cmd.Flags().GetString("output")
@@ -175,7 +175,7 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 			cluster.Status.CurrentPrimary, cluster.Status.TargetPrimary)
 	}
 
-	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	fencedInstances, err := cluster.GetFencedInstances()
 	if err != nil {
 		fmt.Printf("could not check if cluster is fenced: %v", err)
 	}
@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// catalogInspector runs the queries checking the catalog in the primary
+// instance, through psql
+type catalogInspector struct {
+	ctx context.Context
+	pod corev1.Pod
+}
+
+// query runs a query in the passed database, returning the rows as
+// lists of columns
+func (inspector *catalogInspector) query(database, query string) ([][]string, error) {
+	stdout, stderr, err := inspector.exec(
+		"psql", "--no-psqlrc", "--no-align", "--tuples-only", "--quiet",
+		"--field-separator", "\t", "--dbname", database, "--command", query)
+	if err != nil {
+		return nil, fmt.Errorf("while running %q: %w (%s)", query, err, stderr)
+	}
+
+	var rows [][]string
+	for _, line := range strings.Split(stdout, "\n") {
+		if line == "" {
+			continue
+		}
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	return rows, nil
+}
+
+// listSpecExtensions lists the control files and the libraries of the
+// extensions mounted in the primary instance through the cluster spec
+func (inspector *catalogInspector) listSpecExtensions() (*specExtensions, error) {
+	stdout, stderr, err := inspector.exec(
+		"find", "-L", postgres.ExtensionsBaseDirectory, "-name", "*.control", "-o", "-name", "*.so")
+	if err != nil {
+		return nil, fmt.Errorf("while listing the extensions in %s: %w (%s)",
+			postgres.ExtensionsBaseDirectory, err, stderr)
+	}
+
+	return parseSpecExtensions(stdout), nil
+}
+
+// exec runs a command in the PostgreSQL container of the primary instance
+func (inspector *catalogInspector) exec(command ...string) (string, string, error) {
+	timeout := time.Minute
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	return utils.ExecCommand(
+		inspector.ctx,
+		clientInterface,
+		plugin.Config,
+		inspector.pod,
+		specs.PostgresContainerName,
+		&timeout,
+		command...)
+}
+
+// specExtensions are the extensions and the libraries provided by the
+// extensions declared in the cluster spec, indexed by their name and
+// pointing to the name of the spec extension providing them
+type specExtensions struct {
+	extensions map[string]string
+	libraries  map[string]string
+}
+
+// parseSpecExtensions parses the list of the control files and of the
+// libraries found in the directories of the spec extensions, like
+// "/extensions/pgvector/share/extension/vector.control"
+func parseSpecExtensions(output string) *specExtensions {
+	result := &specExtensions{
+		extensions: make(map[string]string),
+		libraries:  make(map[string]string),
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		relativePath := strings.TrimPrefix(strings.TrimSpace(line), postgres.ExtensionsBaseDirectory+"/")
+		fragments := strings.Split(relativePath, "/")
+		if len(fragments) < 3 {
+			continue
+		}
+
+		specName, fileName := fragments[0], fragments[len(fragments)-1]
+		switch {
+		case fragments[1] == "share" && strings.HasSuffix(fileName, ".control"):
+			result.extensions[strings.TrimSuffix(fileName, ".control")] = specName
+		case fragments[1] == "lib" && strings.HasSuffix(fileName, ".so"):
+			result.libraries[strings.TrimSuffix(fileName, ".so")] = specName
+		}
+	}
+
+	return result
+}
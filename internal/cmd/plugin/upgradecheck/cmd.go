@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var upgradeCheckExample = `
+  # Check whether "cluster-example" can be upgraded to PostgreSQL 17
+  kubectl-cnpg upgrade-check cluster-example --to 17
+
+  # Check the upgrade against a custom image
+  kubectl-cnpg upgrade-check cluster-example --to 17 --image registry.example.com/postgresql:17.2-custom`
+
+// NewCmd creates the new "upgrade-check" command
+func NewCmd() *cobra.Command {
+	options := checkOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade-check [cluster] --to [major]",
+		Short: "Check whether a cluster can be upgraded to a new PostgreSQL major version",
+		Long: "Runs a Job with the image of the new major version to list its extensions and " +
+			"libraries, and checks the catalog of the primary instance for the known pg_upgrade " +
+			"blockers, reporting the problems to be fixed before changing the image of the cluster",
+		Example:           upgradeCheckExample,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: plugin.CompleteClusters,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName, err := plugin.GetClusterName(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			options.clusterName = clusterName
+			options.format = plugin.OutputFormat(output)
+			return Check(ctx, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.targetMajor, "to", 0,
+		"The PostgreSQL major version to upgrade to")
+	_ = cmd.MarkFlagRequired("to")
+	cmd.Flags().StringVar(&options.targetImage, "image", "",
+		"The image of the new major version. Defaults to the image of the cluster tagged with the new major version")
+	cmd.Flags().DurationVar(&options.timeout, "timeout", 5*time.Minute,
+		"The maximum time to wait for the Job inspecting the image of the new major version")
+	cmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json")
+
+	return cmd
+}
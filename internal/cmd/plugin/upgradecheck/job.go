@@ -0,0 +1,220 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// inventoryContainerName is the name of the container listing the
+// content of the image of the new major version
+const inventoryContainerName = "inventory"
+
+// inventoryScript prints the version of PostgreSQL, the extensions with
+// their default version and the libraries available in the image
+const inventoryScript = `set -e
+echo "version $(postgres --version)"
+for f in "$(pg_config --sharedir)"/extension/*.control; do
+  [ -e "$f" ] || continue
+  echo "extension $(basename "$f" .control) $(sed -n "s/^default_version *= *'\(.*\)'.*/\1/p" "$f")"
+done
+for f in "$(pg_config --pkglibdir)"/*.so; do
+  [ -e "$f" ] || continue
+  echo "library $(basename "$f" .so)"
+done`
+
+// imageInventory is the content of the image of the new major version
+type imageInventory struct {
+	// version is the output of "postgres --version"
+	version string
+
+	// extensions maps the name of the available extensions to their
+	// default version
+	extensions map[string]string
+
+	// libraries is the set of the available libraries
+	libraries map[string]bool
+}
+
+// buildInventoryJob creates the Job listing the content of the image of
+// the new major version. It doesn't mount any volume of the cluster, and
+// runs with the same security context of the instances
+func buildInventoryJob(cluster *apiv1.Cluster, image string, timeout time.Duration) *batchv1.Job {
+	labels := map[string]string{
+		"cnpg.io/upgradeCheck": cluster.Name,
+	}
+
+	var pullSecrets []corev1.LocalObjectReference
+	for _, secret := range cluster.Spec.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret.Name})
+	}
+
+	deadline := int64(timeout.Seconds())
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-upgrade-check", cluster.Name),
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds:   &deadline,
+			BackoffLimit:            pointer.Int32(0),
+			TTLSecondsAfterFinished: pointer.Int32(600),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: pullSecrets,
+					SecurityContext: specs.CreatePodSecurityContext(
+						cluster.GetPostgresUID(),
+						cluster.GetPostgresGID(),
+					),
+					Containers: []corev1.Container{
+						{
+							Name:            inventoryContainerName,
+							Image:           image,
+							Command:         []string{"sh", "-c", inventoryScript},
+							SecurityContext: specs.CreateContainerSecurityContext(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getImageInventory runs the inventory Job and parses its output, deleting
+// the Job when done
+func getImageInventory(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	image string,
+	timeout time.Duration,
+) (*imageInventory, error) {
+	job := buildInventoryJob(cluster, image, timeout)
+	if err := plugin.Client.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("while creating the inventory job: %w", err)
+	}
+	defer func() {
+		if err := plugin.Client.Delete(
+			context.Background(), job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot delete the inventory job %s: %v\n", job.Name, err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Waiting for job %s to inspect image %s\n", job.Name, image)
+	deadline := time.Now().Add(timeout)
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the inventory job %s didn't complete before the timeout", job.Name)
+		}
+		time.Sleep(time.Second)
+
+		if err := plugin.Client.Get(ctx, client.ObjectKeyFromObject(job), job); err != nil {
+			return nil, err
+		}
+		if utils.IsJobFailed(*job) {
+			return nil, fmt.Errorf("the inventory job %s failed, check that image %s exists "+
+				"and contains PostgreSQL", job.Name, image)
+		}
+		if utils.IsJobComplete(*job) {
+			break
+		}
+	}
+
+	logs, err := readInventoryLogs(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseImageInventory(logs), nil
+}
+
+// readInventoryLogs reads the output of the Pod of the inventory Job
+func readInventoryLogs(ctx context.Context, job *batchv1.Job) (string, error) {
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx, &pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+
+	for idx := range pods.Items {
+		if pods.Items[idx].Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+		logs, err := clientInterface.CoreV1().Pods(job.Namespace).GetLogs(
+			pods.Items[idx].Name, &corev1.PodLogOptions{Container: inventoryContainerName}).DoRaw(ctx)
+		if err != nil {
+			return "", fmt.Errorf("while reading the logs of the inventory job: %w", err)
+		}
+		return string(logs), nil
+	}
+
+	return "", fmt.Errorf("no completed Pod found for the inventory job %s", job.Name)
+}
+
+// parseImageInventory parses the output of the inventory script, skipping
+// the malformed lines
+func parseImageInventory(logs string) *imageInventory {
+	result := &imageInventory{
+		extensions: make(map[string]string),
+		libraries:  make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		kind, value, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+
+		switch kind {
+		case "version":
+			result.version = value
+		case "extension":
+			name, version, _ := strings.Cut(value, " ")
+			result.extensions[name] = version
+		case "library":
+			result.libraries[value] = true
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,430 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgradecheck implements the kubectl-cnpg upgrade-check command
+package upgradecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// errUpgradeBlocked is returned when the checks found problems that
+// would make the major version upgrade fail
+var errUpgradeBlocked = errors.New("the major version upgrade is blocked")
+
+// checkOptions are the options of the upgrade check
+type checkOptions struct {
+	clusterName string
+	targetMajor int
+	targetImage string
+	timeout     time.Duration
+	format      plugin.OutputFormat
+}
+
+// Report is the result of the upgrade check
+type Report struct {
+	// ClusterName is the name of the checked cluster
+	ClusterName string `json:"clusterName"`
+
+	// SourceImage is the image currently used by the cluster
+	SourceImage string `json:"sourceImage"`
+
+	// SourceVersion is the version of PostgreSQL running on the primary
+	SourceVersion string `json:"sourceVersion"`
+
+	// TargetImage is the image of the new major version
+	TargetImage string `json:"targetImage"`
+
+	// TargetVersion is the version of PostgreSQL contained in the
+	// image of the new major version
+	TargetVersion string `json:"targetVersion"`
+
+	// Blockers are the problems to be fixed before the upgrade
+	Blockers []string `json:"blockers,omitempty"`
+
+	// Warnings are the problems that don't prevent the upgrade, but
+	// require an action after it
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Check implements the "upgrade-check" subcommand
+func Check(ctx context.Context, options checkOptions) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: options.clusterName},
+		&cluster); err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", options.clusterName, plugin.Namespace)
+	}
+
+	sourceImage := cluster.GetImageName()
+	sourceMajor, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(sourceImage))
+	if err != nil {
+		return fmt.Errorf("cannot detect the PostgreSQL major version of image %s: %w", sourceImage, err)
+	}
+	if options.targetMajor <= sourceMajor {
+		return fmt.Errorf("the cluster is running PostgreSQL %d, the target major version must be newer",
+			sourceMajor)
+	}
+
+	targetImage := options.targetImage
+	if targetImage == "" {
+		targetImage = getTargetImage(sourceImage, options.targetMajor)
+	}
+
+	_, primaryPod, err := resources.GetInstancePods(ctx, options.clusterName)
+	if err != nil {
+		return err
+	}
+	if primaryPod.Name == "" {
+		return fmt.Errorf("no primary instance found for cluster %s", options.clusterName)
+	}
+
+	inventory, err := getImageInventory(ctx, &cluster, targetImage, options.timeout)
+	if err != nil {
+		return err
+	}
+
+	report := &Report{
+		ClusterName:   cluster.Name,
+		SourceImage:   sourceImage,
+		TargetImage:   targetImage,
+		TargetVersion: inventory.version,
+	}
+	if !cluster.IsMajorVersionUpgradeEnabled() {
+		report.Warnings = append(report.Warnings,
+			"the major version upgrades are not enabled in the cluster: set .spec.majorVersionUpgrade "+
+				"before changing the image, or the new image will be refused")
+	}
+	checkTargetVersion(report, inventory, options.targetMajor)
+
+	inspector := &catalogInspector{ctx: ctx, pod: primaryPod}
+	provided := &specExtensions{}
+	if len(cluster.Spec.PostgresConfiguration.Extensions) > 0 {
+		if provided, err = inspector.listSpecExtensions(); err != nil {
+			return err
+		}
+	}
+
+	if err := checkCatalog(report, inspector, inventory, provided, sourceMajor); err != nil {
+		return err
+	}
+
+	if err := plugin.Print(report, options.format, os.Stdout); err != nil {
+		return err
+	}
+
+	if options.format == plugin.OutputFormatText {
+		report.print()
+	}
+
+	if len(report.Blockers) > 0 {
+		return errUpgradeBlocked
+	}
+	return nil
+}
+
+// getTargetImage gets the image of the new major version, replacing the
+// tag of the current image with the new major version
+func getTargetImage(sourceImage string, targetMajor int) string {
+	reference := utils.NewReference(sourceImage)
+	reference.Tag = strconv.Itoa(targetMajor)
+	reference.Digest = ""
+	return reference.GetNormalizedName()
+}
+
+// checkTargetVersion checks that the image of the new major version
+// contains the requested version of PostgreSQL
+func checkTargetVersion(report *Report, inventory *imageInventory, targetMajor int) {
+	// The output of "postgres --version" is "postgres (PostgreSQL) 17.2 ..."
+	fields := strings.Fields(inventory.version)
+	if len(fields) < 3 {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"cannot detect the version of PostgreSQL in image %s", report.TargetImage))
+		return
+	}
+
+	major, err := postgres.GetPostgresMajorVersionFromTag(fields[2])
+	if err != nil || major != targetMajor {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"image %s contains %s, not PostgreSQL %d", report.TargetImage, inventory.version, targetMajor))
+	}
+}
+
+// checkCatalog checks the catalog of the primary instance for the objects
+// that pg_upgrade cannot upgrade, and for the extensions and libraries
+// missing in the image of the new major version and not provided by the
+// extensions declared in the cluster spec
+func checkCatalog(
+	report *Report,
+	inspector *catalogInspector,
+	inventory *imageInventory,
+	provided *specExtensions,
+	sourceMajor int,
+) error {
+	rows, err := inspector.query("postgres", "SHOW server_version")
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		report.SourceVersion = rows[0][0]
+	}
+
+	if err := checkGlobalObjects(report, inspector, inventory, provided, sourceMajor); err != nil {
+		return err
+	}
+
+	databases, err := inspector.query("postgres",
+		"SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range databases {
+		if err := checkDatabase(report, inspector, inventory, provided, row[0], sourceMajor); err != nil {
+			return fmt.Errorf("while checking database %s: %w", row[0], err)
+		}
+	}
+
+	return nil
+}
+
+// checkGlobalObjects checks the objects shared by every database
+func checkGlobalObjects(
+	report *Report,
+	inspector *catalogInspector,
+	inventory *imageInventory,
+	provided *specExtensions,
+	sourceMajor int,
+) error {
+	rows, err := inspector.query("postgres", "SELECT gid FROM pg_catalog.pg_prepared_xacts ORDER BY gid")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"the prepared transaction %s must be committed or rolled back", row[0]))
+	}
+
+	// pg_upgrade migrates the logical replication slots only from PostgreSQL 17
+	if sourceMajor < 17 {
+		rows, err := inspector.query("postgres",
+			"SELECT slot_name FROM pg_catalog.pg_replication_slots WHERE slot_type = 'logical' ORDER BY slot_name")
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"the logical replication slot %s is not migrated and must be created again after the upgrade",
+				row[0]))
+		}
+	}
+
+	rows, err = inspector.query("postgres", "SHOW shared_preload_libraries")
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		for _, library := range parseLibraryList(rows[0][0]) {
+			checkLibrary(report, inventory, provided, library)
+		}
+	}
+
+	return nil
+}
+
+// checkLibrary checks that a library in shared_preload_libraries is
+// available after the upgrade. The libraries provided by the extensions
+// declared in the cluster spec are not in the new image, and are
+// reported as warnings as they must be built for the new major version
+func checkLibrary(report *Report, inventory *imageInventory, provided *specExtensions, library string) {
+	if inventory.libraries[library] {
+		return
+	}
+
+	if specName, ok := provided.libraries[library]; ok {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"the %s library in shared_preload_libraries is provided by the %s extension in the cluster spec: "+
+				"make sure it is built for the PostgreSQL version in image %s",
+			library, specName, report.TargetImage))
+		return
+	}
+
+	report.Blockers = append(report.Blockers, fmt.Sprintf(
+		"the %s library in shared_preload_libraries is not available in image %s",
+		library, report.TargetImage))
+}
+
+// checkExtension checks that an extension installed in a database is
+// available after the upgrade, like checkLibrary does for the libraries
+func checkExtension(
+	report *Report,
+	inventory *imageInventory,
+	provided *specExtensions,
+	database, name, version string,
+) {
+	targetVersion, available := inventory.extensions[name]
+	specName, isProvided := provided.extensions[name]
+	switch {
+	case !available && isProvided:
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"database %s: the %s extension is provided by the %s extension in the cluster spec: "+
+				"make sure it is built for the PostgreSQL version in image %s",
+			database, name, specName, report.TargetImage))
+	case !available:
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"database %s: the %s extension is not available in image %s",
+			database, name, report.TargetImage))
+	case targetVersion != "" && targetVersion != version:
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"database %s: the %s extension is installed in version %s, run "+
+				"\"ALTER EXTENSION %s UPDATE\" after the upgrade to get version %s",
+			database, name, version, name, targetVersion))
+	}
+}
+
+// unsupportedRegTypes are the reg* data types whose values contain OIDs
+// that are not preserved by pg_upgrade
+var unsupportedRegTypes = []string{
+	"regcollation", "regconfig", "regdictionary", "regnamespace",
+	"regoper", "regoperator", "regproc", "regprocedure",
+}
+
+// checkDatabase checks the objects of a database
+func checkDatabase(
+	report *Report,
+	inspector *catalogInspector,
+	inventory *imageInventory,
+	provided *specExtensions,
+	database string,
+	sourceMajor int,
+) error {
+	rows, err := inspector.query(database,
+		"SELECT extname, extversion FROM pg_catalog.pg_extension ORDER BY extname")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		checkExtension(report, inventory, provided, database, row[0], row[1])
+	}
+
+	quotedTypes := make([]string, len(unsupportedRegTypes))
+	for idx, typeName := range unsupportedRegTypes {
+		quotedTypes[idx] = "'" + typeName + "'"
+	}
+	rows, err = inspector.query(database,
+		"SELECT n.nspname || '.' || c.relname || '.' || a.attname, a.atttypid::pg_catalog.regtype "+
+			"FROM pg_catalog.pg_class c "+
+			"JOIN pg_catalog.pg_namespace n ON c.relnamespace = n.oid "+
+			"JOIN pg_catalog.pg_attribute a ON c.oid = a.attrelid "+
+			"WHERE NOT a.attisdropped AND c.relkind IN ('r', 'm', 'i') "+
+			"AND n.nspname NOT IN ('pg_catalog', 'information_schema') "+
+			"AND a.atttypid::pg_catalog.regtype::text IN ("+strings.Join(quotedTypes, ", ")+") "+
+			"ORDER BY 1")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"database %s: the %s column uses the %s data type, which pg_upgrade doesn't support",
+			database, row[0], row[1]))
+	}
+
+	if sourceMajor >= 14 {
+		return nil
+	}
+
+	// PostgreSQL 14 removed the postfix operators and changed the
+	// signature of the encoding conversion functions
+	rows, err = inspector.query(database,
+		"SELECT oid::pg_catalog.regoperator FROM pg_catalog.pg_operator "+
+			"WHERE oprright = 0 AND oid >= 16384 ORDER BY 1")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"database %s: the postfix operator %s is not supported from PostgreSQL 14", database, row[0]))
+	}
+
+	rows, err = inspector.query(database,
+		"SELECT conname FROM pg_catalog.pg_conversion WHERE oid >= 16384 ORDER BY 1")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		report.Blockers = append(report.Blockers, fmt.Sprintf(
+			"database %s: the user-defined encoding conversion %s must be dropped and created again "+
+				"after the upgrade", database, row[0]))
+	}
+
+	return nil
+}
+
+// parseLibraryList parses the value of shared_preload_libraries into the
+// names of the libraries
+func parseLibraryList(value string) []string {
+	var result []string
+	for _, library := range strings.Split(value, ",") {
+		library = strings.Trim(strings.TrimSpace(library), `"`)
+		library = strings.TrimPrefix(library, "$libdir/")
+		library = strings.TrimSuffix(library, ".so")
+		if library != "" {
+			result = append(result, library)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// print prints the report in a human-readable format
+func (report *Report) print() {
+	fmt.Printf("Cluster: %s\n", report.ClusterName)
+	fmt.Printf("Source: %s (PostgreSQL %s)\n", report.SourceImage, report.SourceVersion)
+	fmt.Printf("Target: %s (%s)\n", report.TargetImage, report.TargetVersion)
+
+	if len(report.Warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, warning := range report.Warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+	}
+
+	if len(report.Blockers) == 0 {
+		fmt.Println("No blocker found for the major version upgrade")
+		return
+	}
+
+	fmt.Println("Blockers:")
+	for _, blocker := range report.Blockers {
+		fmt.Printf("  - %s\n", blocker)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Image inventory", func() {
+	ginkgo.It("parses the output of the inventory script", func() {
+		inventory := parseImageInventory(
+			"version postgres (PostgreSQL) 17.2 (Debian 17.2-1.pgdg110+1)\n" +
+				"extension pg_stat_statements 1.11\n" +
+				"extension plpgsql 1.0\n" +
+				"extension nodefault \n" +
+				"library pg_stat_statements\n" +
+				"library auto_explain\n" +
+				"malformed\n")
+		gomega.Expect(inventory.version).To(gomega.Equal("postgres (PostgreSQL) 17.2 (Debian 17.2-1.pgdg110+1)"))
+		gomega.Expect(inventory.extensions).To(gomega.Equal(map[string]string{
+			"pg_stat_statements": "1.11",
+			"plpgsql":            "1.0",
+			"nodefault":          "",
+		}))
+		gomega.Expect(inventory.libraries).To(gomega.Equal(map[string]bool{
+			"pg_stat_statements": true,
+			"auto_explain":       true,
+		}))
+	})
+
+	ginkgo.It("runs the inventory job with the security context of the instances", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				PostgresUID: 26,
+				PostgresGID: 26,
+			},
+		}
+		job := buildInventoryJob(cluster, "ghcr.io/cloudnative-pg/postgresql:17", time.Minute)
+		podSpec := job.Spec.Template.Spec
+		gomega.Expect(podSpec.SecurityContext).ToNot(gomega.BeNil())
+		gomega.Expect(podSpec.SecurityContext.RunAsNonRoot).To(gomega.Equal(pointer.Bool(true)))
+		gomega.Expect(podSpec.SecurityContext.RunAsUser).To(gomega.Equal(pointer.Int64(26)))
+		gomega.Expect(podSpec.Containers).To(gomega.HaveLen(1))
+		containerSecurityContext := podSpec.Containers[0].SecurityContext
+		gomega.Expect(containerSecurityContext).ToNot(gomega.BeNil())
+		gomega.Expect(containerSecurityContext.AllowPrivilegeEscalation).To(gomega.Equal(pointer.Bool(false)))
+	})
+})
+
+var _ = ginkgo.Describe("Spec extensions", func() {
+	ginkgo.It("parses the files found in the directories of the extensions", func() {
+		provided := parseSpecExtensions(
+			"/extensions/pgvector/share/extension/vector.control\n" +
+				"/extensions/pgvector/lib/vector.so\n" +
+				"/extensions/timescaledb/lib/timescaledb-2.17.2.so\n" +
+				"/extensions/timescaledb/share/extension/timescaledb.control\n" +
+				"/extensions/other/bin/tool.so\n" +
+				"/extensions/invalid.control\n")
+		gomega.Expect(provided.extensions).To(gomega.Equal(map[string]string{
+			"vector":      "pgvector",
+			"timescaledb": "timescaledb",
+		}))
+		gomega.Expect(provided.libraries).To(gomega.Equal(map[string]string{
+			"vector":             "pgvector",
+			"timescaledb-2.17.2": "timescaledb",
+		}))
+	})
+})
+
+var _ = ginkgo.Describe("Upgrade checks", func() {
+	inventory := &imageInventory{
+		version:    "postgres (PostgreSQL) 17.2",
+		extensions: map[string]string{"pg_stat_statements": "1.11"},
+		libraries:  map[string]bool{"pg_stat_statements": true},
+	}
+	provided := &specExtensions{
+		extensions: map[string]string{"vector": "pgvector"},
+		libraries:  map[string]string{"vector": "pgvector"},
+	}
+
+	ginkgo.It("parses the value of shared_preload_libraries", func() {
+		gomega.Expect(parseLibraryList(`pg_stat_statements, "$libdir/auto_explain.so",,timescaledb`)).
+			To(gomega.Equal([]string{"auto_explain", "pg_stat_statements", "timescaledb"}))
+		gomega.Expect(parseLibraryList("")).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("gets the target image from the source one", func() {
+		gomega.Expect(getTargetImage("ghcr.io/cloudnative-pg/postgresql:16.4", 17)).
+			To(gomega.Equal("ghcr.io/cloudnative-pg/postgresql:17"))
+		gomega.Expect(getTargetImage(
+			"ghcr.io/cloudnative-pg/postgresql:16.4@sha256:"+
+				"0000000000000000000000000000000000000000000000000000000000000000", 17)).
+			To(gomega.Equal("ghcr.io/cloudnative-pg/postgresql:17"))
+	})
+
+	ginkgo.DescribeTable("checks the version of the target image",
+		func(version string, expectedBlockers int) {
+			report := &Report{TargetImage: "postgresql:17"}
+			checkTargetVersion(report, &imageInventory{version: version}, 17)
+			gomega.Expect(report.Blockers).To(gomega.HaveLen(expectedBlockers))
+		},
+		ginkgo.Entry("matching version", "postgres (PostgreSQL) 17.2 (Debian 17.2-1.pgdg110+1)", 0),
+		ginkgo.Entry("different major version", "postgres (PostgreSQL) 16.4", 1),
+		ginkgo.Entry("unknown output", "postgres", 1),
+	)
+
+	ginkgo.It("reports the extensions of the cluster spec as warnings", func() {
+		report := &Report{TargetImage: "postgresql:17"}
+		checkExtension(report, inventory, provided, "app", "vector", "0.8.0")
+		gomega.Expect(report.Blockers).To(gomega.BeEmpty())
+		gomega.Expect(report.Warnings).To(gomega.ConsistOf(gomega.ContainSubstring(
+			"the vector extension is provided by the pgvector extension in the cluster spec")))
+	})
+
+	ginkgo.It("reports the missing extensions as blockers", func() {
+		report := &Report{TargetImage: "postgresql:17"}
+		checkExtension(report, inventory, &specExtensions{}, "app", "vector", "0.8.0")
+		gomega.Expect(report.Warnings).To(gomega.BeEmpty())
+		gomega.Expect(report.Blockers).To(gomega.ConsistOf(gomega.ContainSubstring(
+			"the vector extension is not available in image postgresql:17")))
+	})
+
+	ginkgo.It("reports the extensions with a different default version as warnings", func() {
+		report := &Report{TargetImage: "postgresql:17"}
+		checkExtension(report, inventory, provided, "app", "pg_stat_statements", "1.10")
+		gomega.Expect(report.Blockers).To(gomega.BeEmpty())
+		gomega.Expect(report.Warnings).To(gomega.ConsistOf(gomega.ContainSubstring(
+			"ALTER EXTENSION pg_stat_statements UPDATE")))
+	})
+
+	ginkgo.It("checks the libraries in shared_preload_libraries", func() {
+		report := &Report{TargetImage: "postgresql:17"}
+		for _, library := range []string{"pg_stat_statements", "vector", "pg_cron"} {
+			checkLibrary(report, inventory, provided, library)
+		}
+		gomega.Expect(report.Warnings).To(gomega.ConsistOf(gomega.ContainSubstring(
+			"the vector library in shared_preload_libraries is provided by the pgvector extension")))
+		gomega.Expect(report.Blockers).To(gomega.ConsistOf(gomega.ContainSubstring(
+			"the pg_cron library in shared_preload_libraries is not available")))
+	})
+})
@@ -22,6 +22,8 @@ import (
 	"path"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -32,6 +34,10 @@ var configurationLog = log.WithName("configuration")
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+// DefaultCapabilitiesDetectionInterval is the default time, in seconds,
+// between two detections of the capabilities of the Kubernetes cluster
+const DefaultCapabilitiesDetectionInterval = 300
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -84,6 +90,48 @@ type Data struct {
 	// MonitoringQueriesSecret is the name of the secret in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesSecret string `json:"monitoringQueriesSecret" env:"MONITORING_QUERIES_SECRET"`
+
+	// BackupMaxConcurrency is the maximum number of base backups that can be
+	// running at the same time across all the clusters managed by the operator.
+	// Zero means no limit
+	BackupMaxConcurrency int `json:"backupMaxConcurrency" env:"BACKUP_MAX_CONCURRENCY"`
+
+	// BackupScheduleJitter is the maximum delay, in seconds, randomly added to
+	// the time a scheduled backup is started, to avoid starting all the
+	// backups sharing the same schedule at the same time. Zero disables it
+	BackupScheduleJitter int `json:"backupScheduleJitter" env:"BACKUP_SCHEDULE_JITTER"`
+
+	// EnableFleetAPI enables the read-only HTTP API summarizing the state
+	// of the managed clusters, served by the webhook server
+	EnableFleetAPI bool `json:"enableFleetAPI" env:"ENABLE_FLEET_API"`
+
+	// ImageRegistryMirrors is a list of `prefix=replacement` rules rewriting
+	// the names of the images used in the generated Pods, so that they are
+	// pulled from a mirror. The longest matching prefix wins
+	ImageRegistryMirrors []string `json:"imageRegistryMirrors" env:"IMAGE_REGISTRY_MIRRORS"`
+
+	// ImagePullPolicy is the pull policy of the images used in the Pods
+	// of the clusters not specifying one
+	ImagePullPolicy string `json:"imagePullPolicy" env:"IMAGE_PULL_POLICY"`
+
+	// EnableOperatorServiceMonitor enables the creation of a ServiceMonitor,
+	// and of the Service it uses, scraping the metrics of the operator itself
+	EnableOperatorServiceMonitor bool `json:"enableOperatorServiceMonitor" env:"ENABLE_OPERATOR_SERVICE_MONITOR"`
+
+	// CapabilitiesDetectionInterval is the time, in seconds, between two
+	// detections of the capabilities of the Kubernetes cluster, like the
+	// presence of the Prometheus Operator. Zero disables the periodic detection
+	CapabilitiesDetectionInterval int `json:"capabilitiesDetectionInterval" env:"CAPABILITIES_DETECTION_INTERVAL"`
+
+	// CapabilityOverrides is a list of `name=value` rules forcing the value
+	// of some capabilities of the Kubernetes cluster, whose detection is
+	// skipped, i.e. `havePodMonitor=false`
+	CapabilityOverrides []string `json:"capabilityOverrides" env:"CAPABILITY_OVERRIDES"`
+
+	// DefaultArchiveTimeout is the value of the archive_timeout parameter
+	// applied to the clusters not specifying one. When empty, the
+	// default of the operator is used
+	DefaultArchiveTimeout string `json:"defaultArchiveTimeout" env:"DEFAULT_ARCHIVE_TIMEOUT"`
 }
 
 // Current is the configuration used by the operator
@@ -95,6 +143,8 @@ func newDefaultConfig() *Data {
 		OperatorPullSecretName: DefaultOperatorPullSecretName,
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
+
+		CapabilitiesDetectionInterval: DefaultCapabilitiesDetectionInterval,
 	}
 }
 
@@ -130,6 +180,52 @@ func (config *Data) WatchedNamespaces() []string {
 	return cleanNamespaceList(config.WatchNamespace)
 }
 
+// RewriteImageName replaces the prefix of the passed image name
+// according to the longest matching registry mirror rule. A prefix
+// matches only whole path components of the image name
+func (config *Data) RewriteImageName(imageName string) string {
+	var bestPrefix, bestReplacement string
+	for _, rule := range config.ImageRegistryMirrors {
+		prefix, replacement, found := strings.Cut(rule, "=")
+		prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "/")
+		replacement = strings.TrimSuffix(strings.TrimSpace(replacement), "/")
+		if !found || prefix == "" || replacement == "" {
+			configurationLog.Info("Skipping invalid image registry mirror rule", "rule", rule)
+			continue
+		}
+
+		if !strings.HasPrefix(imageName, prefix+"/") || len(prefix) <= len(bestPrefix) {
+			continue
+		}
+		bestPrefix, bestReplacement = prefix, replacement
+	}
+
+	if bestPrefix == "" {
+		return imageName
+	}
+	return bestReplacement + strings.TrimPrefix(imageName, bestPrefix)
+}
+
+// GetImagePullPolicy gets the pull policy of the images used in the Pods
+// of the clusters not specifying one. An invalid value is ignored, leaving
+// the choice to Kubernetes
+func (config *Data) GetImagePullPolicy() corev1.PullPolicy {
+	policy := corev1.PullPolicy(config.ImagePullPolicy)
+	switch policy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return policy
+	}
+
+	configurationLog.Info("Skipping invalid image pull policy", "imagePullPolicy", config.ImagePullPolicy)
+	return ""
+}
+
+// GetOperatorImageName gets the name of the image of the operator used
+// to bootstrap Pods, applying the registry mirror rules
+func (config *Data) GetOperatorImageName() string {
+	return config.RewriteImageName(config.OperatorImageName)
+}
+
 func cleanNamespaceList(namespaces string) (result []string) {
 	unfilteredList := strings.Split(namespaces, ",")
 	result = make([]string, 0, len(unfilteredList))
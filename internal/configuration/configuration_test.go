@@ -17,6 +17,8 @@ limitations under the License.
 package configuration
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -127,4 +129,62 @@ var _ = Describe("Annotation and label inheritance", func() {
 			}))
 		})
 	})
+
+	Describe("image registry mirrors", func() {
+		It("leaves the image name untouched without rules", func() {
+			config := Data{}
+			Expect(config.RewriteImageName("ghcr.io/cloudnative-pg/postgresql:15")).
+				To(Equal("ghcr.io/cloudnative-pg/postgresql:15"))
+		})
+
+		It("applies the longest matching prefix", func() {
+			config := Data{
+				ImageRegistryMirrors: []string{
+					"ghcr.io=mirror.example.com/ghcr",
+					"ghcr.io/cloudnative-pg=mirror.example.com/cnpg/",
+				},
+			}
+			Expect(config.RewriteImageName("ghcr.io/cloudnative-pg/postgresql:15")).
+				To(Equal("mirror.example.com/cnpg/postgresql:15"))
+			Expect(config.RewriteImageName("ghcr.io/other/image:1")).
+				To(Equal("mirror.example.com/ghcr/other/image:1"))
+		})
+
+		It("matches only whole path components", func() {
+			config := Data{
+				ImageRegistryMirrors: []string{"ghcr.io/cloudnative=mirror.example.com"},
+			}
+			Expect(config.RewriteImageName("ghcr.io/cloudnative-pg/postgresql:15")).
+				To(Equal("ghcr.io/cloudnative-pg/postgresql:15"))
+		})
+
+		It("skips the invalid rules", func() {
+			config := Data{
+				ImageRegistryMirrors: []string{"ghcr.io", "=mirror.example.com", "ghcr.io="},
+			}
+			Expect(config.RewriteImageName("ghcr.io/cloudnative-pg/postgresql:15")).
+				To(Equal("ghcr.io/cloudnative-pg/postgresql:15"))
+		})
+
+		It("rewrites the operator image", func() {
+			config := Data{
+				OperatorImageName:    "ghcr.io/cloudnative-pg/cloudnative-pg:1.18.0",
+				ImageRegistryMirrors: []string{"ghcr.io=mirror.example.com"},
+			}
+			Expect(config.GetOperatorImageName()).To(Equal("mirror.example.com/cloudnative-pg/cloudnative-pg:1.18.0"))
+		})
+	})
+
+	Describe("image pull policy", func() {
+		It("accepts the Kubernetes pull policies", func() {
+			Expect((&Data{}).GetImagePullPolicy()).To(BeEmpty())
+			Expect((&Data{ImagePullPolicy: "Always"}).GetImagePullPolicy()).To(Equal(corev1.PullAlways))
+			Expect((&Data{ImagePullPolicy: "IfNotPresent"}).GetImagePullPolicy()).To(Equal(corev1.PullIfNotPresent))
+			Expect((&Data{ImagePullPolicy: "Never"}).GetImagePullPolicy()).To(Equal(corev1.PullNever))
+		})
+
+		It("skips an invalid pull policy", func() {
+			Expect((&Data{ImagePullPolicy: "always"}).GetImagePullPolicy()).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ClustersPath is the path where the summary of the clusters is served.
+// Being a non-resource URL, the permission to read it is granted by
+// a ClusterRole
+const ClustersPath = "/fleet/v1/clusters"
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// authorizer checks if the owner of a bearer token is allowed
+// to read the fleet API
+type authorizer interface {
+	isAllowed(ctx context.Context, token string) (bool, error)
+}
+
+// Handler serves the summary of the clusters managed by the operator
+type Handler struct {
+	client     client.Client
+	authorizer authorizer
+}
+
+// NewHandler creates a new fleet API handler, reading the clusters with
+// the passed client and delegating the authentication and the authorization
+// of the requests to the Kubernetes API server
+func NewHandler(cli client.Client) *Handler {
+	return &Handler{
+		client:     cli,
+		authorizer: &kubernetesAuthorizer{client: cli},
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := getBearerToken(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	allowed, err := h.authorizer.isAllowed(r.Context(), token)
+	if err != nil {
+		log.Error(err, "Cannot authorize the fleet API request")
+		http.Error(w, "cannot authorize the request", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	summary, err := h.getFleetSummary(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		log.Error(err, "Cannot get the fleet summary")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	js, err := json.Marshal(summary)
+	if err != nil {
+		log.Error(err, "Internal error marshalling the fleet summary")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(js)
+}
+
+// getFleetSummary creates the summary of the clusters in the passed
+// namespace, or in every watched namespace when it is empty
+func (h *Handler) getFleetSummary(ctx context.Context, namespace string) (FleetSummary, error) {
+	var clusters apiv1.ClusterList
+	if err := h.client.List(ctx, &clusters, client.InNamespace(namespace)); err != nil {
+		return FleetSummary{}, err
+	}
+
+	var backups apiv1.BackupList
+	if err := h.client.List(ctx, &backups, client.InNamespace(namespace)); err != nil {
+		return FleetSummary{}, err
+	}
+
+	return NewFleetSummary(clusters.Items, backups.Items), nil
+}
+
+// getBearerToken extracts the bearer token from the authorization
+// header of the passed request
+func getBearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAuthorizer allows only the requests using a known token
+type fakeAuthorizer struct {
+	allowedToken string
+}
+
+func (a fakeAuthorizer) isAllowed(_ context.Context, token string) (bool, error) {
+	return token == a.allowedToken, nil
+}
+
+var _ = Describe("Fleet API handler", func() {
+	var handler *Handler
+
+	BeforeEach(func() {
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(
+				&apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}},
+				&apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "another"}},
+			).
+			Build()
+		handler = &Handler{client: cli, authorizer: fakeAuthorizer{allowedToken: "secret"}}
+	})
+
+	serve := func(method, target, authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	It("requires a bearer token", func() {
+		Expect(serve(http.MethodGet, ClustersPath, "").Code).To(Equal(http.StatusUnauthorized))
+		Expect(serve(http.MethodGet, ClustersPath, "Basic secret").Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("rejects the tokens which are not allowed", func() {
+		Expect(serve(http.MethodGet, ClustersPath, "Bearer wrong").Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("is read-only", func() {
+		Expect(serve(http.MethodPost, ClustersPath, "Bearer secret").Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("serves the summary of the clusters", func() {
+		recorder := serve(http.MethodGet, ClustersPath, "Bearer secret")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var summary FleetSummary
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &summary)).To(Succeed())
+		Expect(summary.Clusters).To(HaveLen(2))
+	})
+
+	It("filters the clusters by namespace", func() {
+		recorder := serve(http.MethodGet, ClustersPath+"?namespace=another", "Bearer secret")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var summary FleetSummary
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &summary)).To(Succeed())
+		Expect(summary.Clusters).To(HaveLen(1))
+		Expect(summary.Clusters[0].Namespace).To(Equal("another"))
+	})
+})
@@ -0,0 +1,196 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetapi
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// FleetSummary is the document returned by the fleet API
+type FleetSummary struct {
+	// Clusters is the list of the summaries of the clusters visible
+	// to the operator, sorted by namespace and name
+	Clusters []ClusterSummary `json:"clusters"`
+}
+
+// ClusterSummary is the summary of the state of a cluster
+type ClusterSummary struct {
+	// Namespace is the namespace of the cluster
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the cluster
+	Name string `json:"name"`
+
+	// Phase is the current phase of the cluster
+	Phase string `json:"phase,omitempty"`
+
+	// Instances is the number of desired instances
+	Instances int `json:"instances"`
+
+	// ReadyInstances is the number of ready instances
+	ReadyInstances int `json:"readyInstances"`
+
+	// CurrentPrimary is the name of the current primary instance
+	CurrentPrimary string `json:"currentPrimary,omitempty"`
+
+	// ImageName is the PostgreSQL image used by the cluster
+	ImageName string `json:"imageName"`
+
+	// PostgresVersion is the PostgreSQL version detected from the image tag,
+	// in the same format of "server_version_num", if it can be detected
+	PostgresVersion int `json:"postgresVersion,omitempty"`
+
+	// Backup is the summary of the backups of the cluster
+	Backup BackupSummary `json:"backup"`
+
+	// Alerts is the list of the conditions of the cluster
+	// reporting a problem
+	Alerts []Alert `json:"alerts,omitempty"`
+}
+
+// BackupSummary is the summary of the backups of a cluster
+type BackupSummary struct {
+	// Configured is true when the cluster has a backup configuration
+	Configured bool `json:"configured"`
+
+	// LastSuccessfulBackup is the time when the last completed backup stopped
+	LastSuccessfulBackup *metav1.Time `json:"lastSuccessfulBackup,omitempty"`
+
+	// FirstRecoverabilityPoint is the first point in time to which the
+	// cluster can be restored
+	FirstRecoverabilityPoint string `json:"firstRecoverabilityPoint,omitempty"`
+}
+
+// Alert is a condition of a cluster reporting a problem
+type Alert struct {
+	// Type is the type of the condition
+	Type string `json:"type"`
+
+	// Reason is the reason of the last transition of the condition
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human readable description of the problem
+	Message string `json:"message,omitempty"`
+
+	// Since is the last time the condition transitioned
+	Since metav1.Time `json:"since"`
+}
+
+// healthyConditionStatus contains, for every condition type reported
+// as an alert, the status of the condition when everything is working
+var healthyConditionStatus = map[apiv1.ClusterConditionType]metav1.ConditionStatus{
+	apiv1.ConditionClusterReady:         metav1.ConditionTrue,
+	apiv1.ConditionContinuousArchiving:  metav1.ConditionTrue,
+	apiv1.ConditionBackup:               metav1.ConditionTrue,
+	apiv1.ConditionAnonymized:           metav1.ConditionTrue,
+	apiv1.ConditionOptimizerStatistics:  metav1.ConditionTrue,
+	apiv1.ConditionMonitoringQueries:    metav1.ConditionTrue,
+	apiv1.ConditionReplicationEncrypted: metav1.ConditionTrue,
+	apiv1.ConditionBackupConsistency:    metav1.ConditionTrue,
+	apiv1.ConditionClockSynchronized:    metav1.ConditionTrue,
+	apiv1.ConditionSplitBrain:           metav1.ConditionFalse,
+}
+
+// NewFleetSummary creates the summary of the passed clusters, using
+// the passed list of backups to detect the last successful one
+func NewFleetSummary(clusters []apiv1.Cluster, backups []apiv1.Backup) FleetSummary {
+	backupsByCluster := make(map[string][]apiv1.Backup)
+	for _, backup := range backups {
+		key := backup.Namespace + "/" + backup.Spec.Cluster.Name
+		backupsByCluster[key] = append(backupsByCluster[key], backup)
+	}
+
+	summary := FleetSummary{Clusters: make([]ClusterSummary, 0, len(clusters))}
+	for i := range clusters {
+		cluster := &clusters[i]
+		summary.Clusters = append(
+			summary.Clusters,
+			newClusterSummary(cluster, backupsByCluster[cluster.Namespace+"/"+cluster.Name]))
+	}
+
+	sort.Slice(summary.Clusters, func(i, j int) bool {
+		if summary.Clusters[i].Namespace != summary.Clusters[j].Namespace {
+			return summary.Clusters[i].Namespace < summary.Clusters[j].Namespace
+		}
+		return summary.Clusters[i].Name < summary.Clusters[j].Name
+	})
+
+	return summary
+}
+
+// newClusterSummary creates the summary of a cluster, given the
+// list of its backups
+func newClusterSummary(cluster *apiv1.Cluster, backups []apiv1.Backup) ClusterSummary {
+	summary := ClusterSummary{
+		Namespace:      cluster.Namespace,
+		Name:           cluster.Name,
+		Phase:          cluster.Status.Phase,
+		Instances:      cluster.Spec.Instances,
+		ReadyInstances: cluster.Status.ReadyInstances,
+		CurrentPrimary: cluster.Status.CurrentPrimary,
+		ImageName:      cluster.GetImageName(),
+		Backup: BackupSummary{
+			Configured:               cluster.Spec.Backup != nil,
+			LastSuccessfulBackup:     getLastSuccessfulBackupTime(backups),
+			FirstRecoverabilityPoint: cluster.Status.FirstRecoverabilityPoint,
+		},
+	}
+
+	if version, err := cluster.GetPostgresqlVersion(); err == nil {
+		summary.PostgresVersion = version
+	}
+
+	for conditionType, healthyStatus := range healthyConditionStatus {
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(conditionType))
+		if condition == nil || condition.Status == healthyStatus || condition.Status == metav1.ConditionUnknown {
+			continue
+		}
+
+		summary.Alerts = append(summary.Alerts, Alert{
+			Type:    condition.Type,
+			Reason:  condition.Reason,
+			Message: condition.Message,
+			Since:   condition.LastTransitionTime,
+		})
+	}
+	sort.Slice(summary.Alerts, func(i, j int) bool {
+		return summary.Alerts[i].Type < summary.Alerts[j].Type
+	})
+
+	return summary
+}
+
+// getLastSuccessfulBackupTime gets the time when the most recent
+// completed backup stopped, or nil if there is none
+func getLastSuccessfulBackupTime(backups []apiv1.Backup) *metav1.Time {
+	var result *metav1.Time
+	for _, backup := range backups {
+		if backup.Status.Phase != apiv1.BackupPhaseCompleted || backup.Status.StoppedAt == nil {
+			continue
+		}
+		if result == nil || result.Before(backup.Status.StoppedAt) {
+			result = backup.Status.StoppedAt
+		}
+	}
+
+	return result
+}
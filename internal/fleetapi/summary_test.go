@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetapi
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fleet summary", func() {
+	now := time.Now()
+
+	newBackup := func(name, clusterName, phase string, stoppedAt time.Time) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: clusterName}},
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhase(phase),
+				StoppedAt: &metav1.Time{Time: stoppedAt},
+			},
+		}
+	}
+
+	It("summarizes the state of a cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				Backup:    &apiv1.BackupConfiguration{},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:                    apiv1.PhaseHealthy,
+				ReadyInstances:           2,
+				CurrentPrimary:           "cluster-example-1",
+				FirstRecoverabilityPoint: "2022-11-01T00:00:00Z",
+			},
+		}
+
+		summary := newClusterSummary(cluster, []apiv1.Backup{
+			newBackup("first", "cluster-example", apiv1.BackupPhaseCompleted, now.Add(-2*time.Hour)),
+			newBackup("second", "cluster-example", apiv1.BackupPhaseCompleted, now.Add(-time.Hour)),
+			newBackup("third", "cluster-example", apiv1.BackupPhaseFailed, now),
+		})
+		Expect(summary.Namespace).To(Equal("default"))
+		Expect(summary.Name).To(Equal("cluster-example"))
+		Expect(summary.Phase).To(Equal(apiv1.PhaseHealthy))
+		Expect(summary.Instances).To(Equal(3))
+		Expect(summary.ReadyInstances).To(Equal(2))
+		Expect(summary.CurrentPrimary).To(Equal("cluster-example-1"))
+		Expect(summary.PostgresVersion).To(Equal(150001))
+		Expect(summary.Backup.Configured).To(BeTrue())
+		Expect(summary.Backup.FirstRecoverabilityPoint).To(Equal("2022-11-01T00:00:00Z"))
+		Expect(summary.Backup.LastSuccessfulBackup.Time).To(BeTemporally("~", now.Add(-time.Hour), time.Second))
+		Expect(summary.Alerts).To(BeEmpty())
+	})
+
+	It("reports the conditions describing a problem as alerts", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    string(apiv1.ConditionContinuousArchiving),
+						Status:  metav1.ConditionFalse,
+						Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
+						Message: "unexpected failure invoking barman-cloud-wal-archive",
+					},
+					{
+						Type:   string(apiv1.ConditionClusterReady),
+						Status: metav1.ConditionTrue,
+					},
+					{
+						Type:   string(apiv1.ConditionSplitBrain),
+						Status: metav1.ConditionTrue,
+					},
+					{
+						Type:   string(apiv1.ConditionBackup),
+						Status: metav1.ConditionUnknown,
+					},
+				},
+			},
+		}
+
+		summary := newClusterSummary(cluster, nil)
+		Expect(summary.Backup.Configured).To(BeFalse())
+		Expect(summary.Backup.LastSuccessfulBackup).To(BeNil())
+		Expect(summary.Alerts).To(HaveLen(2))
+		Expect(summary.Alerts[0].Type).To(Equal(string(apiv1.ConditionContinuousArchiving)))
+		Expect(summary.Alerts[0].Message).To(Equal("unexpected failure invoking barman-cloud-wal-archive"))
+		Expect(summary.Alerts[1].Type).To(Equal(string(apiv1.ConditionSplitBrain)))
+	})
+
+	It("sorts the clusters and matches them with their backups", func() {
+		clusters := []apiv1.Cluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-b", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "another"}},
+		}
+		backups := []apiv1.Backup{
+			newBackup("backup", "cluster-b", apiv1.BackupPhaseCompleted, now),
+		}
+
+		summary := NewFleetSummary(clusters, backups)
+		Expect(summary.Clusters).To(HaveLen(3))
+		Expect(summary.Clusters[0].Namespace).To(Equal("another"))
+		Expect(summary.Clusters[1].Name).To(Equal("cluster-a"))
+		Expect(summary.Clusters[1].Backup.LastSuccessfulBackup).To(BeNil())
+		Expect(summary.Clusters[2].Name).To(Equal("cluster-b"))
+		Expect(summary.Clusters[2].Backup.LastSuccessfulBackup).ToNot(BeNil())
+	})
+})
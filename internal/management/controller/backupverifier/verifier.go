@@ -0,0 +1,471 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupverifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// verifierIdleInterval is the interval between two checks of the
+	// cluster configuration
+	verifierIdleInterval = time.Minute
+
+	// verificationDirectory is where the WAL files are fetched while
+	// being verified
+	verificationDirectory = postgresutils.ScratchDataDirectory + "/backup-verification"
+
+	// maxReportedNames is the maximum number of backups and WAL files
+	// listed in the message of the condition
+	maxReportedNames = 10
+)
+
+// A Verifier is a runner that, while the local instance is the primary,
+// periodically verifies the backups and the WAL archive in the object store
+type Verifier struct {
+	instance *postgres.Instance
+	client   client.Client
+	exporter *metricserver.Exporter
+
+	// lastVerification is the time when the last verification ended
+	lastVerification time.Time
+}
+
+// report contains the problems found by a verification
+type report struct {
+	// The IDs of the backups that are failed or not completed
+	incompleteBackups []string
+
+	// The WAL files that are not in the archive
+	missingWALs []string
+
+	// The WAL files that are in the archive, but cannot be fetched
+	corruptedWALs []string
+}
+
+// NewVerifier creates a new backup Verifier
+func NewVerifier(instance *postgres.Instance, cli client.Client, exporter *metricserver.Exporter) *Verifier {
+	return &Verifier{
+		instance: instance,
+		client:   cli,
+		exporter: exporter,
+	}
+}
+
+// Start starts running the backup Verifier
+func (v *Verifier) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("backup_verifier")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	go func() {
+		ticker := time.NewTicker(verifierIdleInterval)
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated backup Verifier loop")
+		}()
+
+		for {
+			if err := v.tick(ctx); err != nil {
+				contextLog.Warning("verifying the backups", "err", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// tick runs the verification when it is enabled, the local instance is
+// the primary and the configured interval elapsed since the last one
+func (v *Verifier) tick(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := v.client.Get(ctx, types.NamespacedName{
+		Namespace: v.instance.Namespace,
+		Name:      v.instance.ClusterName,
+	}, &cluster); err != nil {
+		return err
+	}
+
+	if !cluster.Spec.Backup.IsVerificationEnabled() {
+		return nil
+	}
+
+	// The verification is executed only by the primary of a cluster that
+	// is not a replica, as that's the only instance archiving the WAL
+	if cluster.IsReplica() || cluster.Status.CurrentPrimary != v.instance.PodName {
+		return nil
+	}
+
+	if time.Since(v.lastVerification) < cluster.Spec.Backup.Verification.GetInterval() {
+		return nil
+	}
+
+	isPrimary, err := v.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Verifying the backups and the WAL archive")
+	result, err := v.verify(ctx, &cluster)
+	v.lastVerification = time.Now()
+	if err != nil {
+		if condErr := conditions.Update(ctx, v.client, &cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionBackupConsistency),
+			Status:  metav1.ConditionUnknown,
+			Reason:  string(apiv1.ConditionReasonBackupVerificationFailed),
+			Message: err.Error(),
+		}); condErr != nil {
+			log.FromContext(ctx).Error(condErr, "Error while updating the backup consistency condition")
+		}
+		return err
+	}
+
+	v.exporter.Metrics.BackupVerification.IncompleteBackups.Set(float64(len(result.incompleteBackups)))
+	v.exporter.Metrics.BackupVerification.MissingWALs.Set(float64(len(result.missingWALs)))
+	v.exporter.Metrics.BackupVerification.CorruptedWALs.Set(float64(len(result.corruptedWALs)))
+	v.exporter.Metrics.BackupVerification.LastVerification.Set(float64(v.lastVerification.Unix()))
+
+	log.FromContext(ctx).Info("Verified the backups and the WAL archive",
+		"incompleteBackups", result.incompleteBackups,
+		"missingWALs", result.missingWALs,
+		"corruptedWALs", result.corruptedWALs)
+	return conditions.Update(ctx, v.client, &cluster, result.buildCondition())
+}
+
+// verify reads the backup catalog from the object store and fetches
+// every WAL file needed to recover from the backups
+func (v *Verifier) verify(ctx context.Context, cluster *apiv1.Cluster) (*report, error) {
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the environment for the object store: %w", err)
+	}
+
+	objectStore := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if objectStore.ServerName != "" {
+		serverName = objectStore.ServerName
+	}
+
+	backupList, err := barman.GetBackupList(objectStore, serverName, env)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the backup catalog: %w", err)
+	}
+
+	lastArchivedWAL, segmentSize, err := v.getArchiveStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &report{incompleteBackups: getIncompleteBackups(backupList)}
+	walNames, err := getRequiredWALs(backupList, lastArchivedWAL, segmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := walrestore.BarmanCloudWalRestoreOptions(objectStore, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
+	}
+
+	if err := os.MkdirAll(verificationDirectory, 0o700); err != nil {
+		return nil, fmt.Errorf("while creating the backup verification directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(verificationDirectory); err != nil {
+			log.FromContext(ctx).Warning("Cannot remove the backup verification directory", "err", err)
+		}
+	}()
+
+	walRestorer, err := restorer.New(ctx, cluster, env, verificationDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	result.missingWALs, result.corruptedWALs = FetchWALs(
+		ctx,
+		walRestorer,
+		verificationDirectory,
+		walNames,
+		options,
+		segmentSize,
+		cluster.Spec.Backup.Verification.GetMaxParallel())
+
+	return result, nil
+}
+
+// getArchiveStatus returns the last archived WAL file and the size
+// of the WAL segments of the instance
+func (v *Verifier) getArchiveStatus() (string, int64, error) {
+	db, err := v.instance.GetSuperUserDB()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var lastArchivedWAL string
+	var segmentSize int64
+	if err := db.QueryRow(
+		`SELECT coalesce(last_archived_wal, ''),
+			(SELECT setting::bigint FROM pg_catalog.pg_settings WHERE name = 'wal_segment_size')
+		FROM pg_catalog.pg_stat_archiver`,
+	).Scan(&lastArchivedWAL, &segmentSize); err != nil {
+		return "", 0, fmt.Errorf("while getting the archive status: %w", err)
+	}
+
+	return lastArchivedWAL, segmentSize, nil
+}
+
+// FetchWALs fetches the passed WAL files into the passed directory, at
+// most maxParallel at a time, returning the ones that are not in the
+// archive and the ones that cannot be fetched or whose content is not
+// valid. The fetched files are removed once validated
+func FetchWALs(
+	ctx context.Context,
+	walRestorer *restorer.WALRestorer,
+	directory string,
+	walNames []string,
+	options []string,
+	segmentSize int64,
+	maxParallel int,
+) (
+	missing []string,
+	corrupted []string,
+) {
+	for start := 0; start < len(walNames); start += maxParallel {
+		end := start + maxParallel
+		if end > len(walNames) {
+			end = len(walNames)
+		}
+
+		batchMissing, batchCorrupted := fetchWALs(ctx, walRestorer, directory, walNames[start:end], options, segmentSize)
+		missing = append(missing, batchMissing...)
+		corrupted = append(corrupted, batchCorrupted...)
+	}
+
+	return missing, corrupted
+}
+
+// fetchWALs fetches in parallel the passed WAL files, returning the ones
+// that are not in the archive and the ones that cannot be fetched or
+// whose content is not valid
+func fetchWALs(
+	ctx context.Context,
+	walRestorer *restorer.WALRestorer,
+	directory string,
+	walNames []string,
+	options []string,
+	segmentSize int64,
+) (
+	missing []string,
+	corrupted []string,
+) {
+	errs := make([]error, len(walNames))
+	done := make(chan struct{})
+	for idx := range walNames {
+		go func(idx int) {
+			destinationPath := filepath.Join(directory, walNames[idx])
+			errs[idx] = walRestorer.Restore(walNames[idx], destinationPath, options)
+			if errs[idx] == nil {
+				errs[idx] = validateWALFile(destinationPath, walNames[idx], segmentSize)
+			}
+			_ = os.Remove(destinationPath)
+			done <- struct{}{}
+		}(idx)
+	}
+	for range walNames {
+		<-done
+	}
+
+	for idx, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, restorer.ErrWALNotFound):
+			missing = append(missing, walNames[idx])
+		default:
+			log.FromContext(ctx).Warning("Invalid WAL file in the archive", "walName", walNames[idx], "err", err)
+			corrupted = append(corrupted, walNames[idx])
+		}
+	}
+
+	return missing, corrupted
+}
+
+// getIncompleteBackups returns the IDs of the backups in the catalog
+// that failed or have not been completed
+func getIncompleteBackups(backupList *catalog.Catalog) []string {
+	var result []string
+	for _, backup := range backupList.List {
+		if backup.Error != "" || backup.BeginTime.IsZero() || backup.EndTime.IsZero() {
+			result = append(result, backup.ID)
+		}
+	}
+
+	return result
+}
+
+// getRequiredWALs returns the sorted WAL files needed to recover from the
+// completed backups in the catalog up to the last archived WAL file: the
+// ones from the beginning of every backup to the beginning of the
+// following one, and the ones archived since the beginning of the latest
+// backup. Timeline switches are not followed: only the WAL files generated
+// while a backup was taken are required when the following one belongs to
+// a different timeline
+func getRequiredWALs(backupList *catalog.Catalog, lastArchivedWAL string, segmentSize int64) ([]string, error) {
+	required := make(map[string]bool)
+	addRange := func(begin, end string) error {
+		walNames, err := GetWALRange(begin, end, segmentSize)
+		if err != nil {
+			return err
+		}
+		for _, name := range walNames {
+			required[name] = true
+		}
+		return nil
+	}
+
+	var completedBackups []*catalog.BarmanBackup
+	for idx := range backupList.List {
+		backup := &backupList.List[idx]
+		if backup.Error != "" || backup.BeginTime.IsZero() || backup.EndTime.IsZero() {
+			continue
+		}
+		completedBackups = append(completedBackups, backup)
+	}
+
+	for idx, backup := range completedBackups {
+		if err := addRange(backup.BeginWal, backup.EndWal); err != nil {
+			return nil, fmt.Errorf("while verifying backup %s: %w", backup.ID, err)
+		}
+
+		end := lastArchivedWAL
+		if idx+1 < len(completedBackups) {
+			end = completedBackups[idx+1].BeginWal
+		}
+		if !postgresutils.IsWALFile(end) {
+			continue
+		}
+		if err := addRange(backup.BeginWal, end); err != nil {
+			return nil, fmt.Errorf("while verifying backup %s: %w", backup.ID, err)
+		}
+	}
+
+	result := make([]string, 0, len(required))
+	for name := range required {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetWALRange returns the names of the WAL files between the passed ones,
+// both included. Nothing is returned when the WAL files belong to
+// different timelines or when the end precedes the beginning
+func GetWALRange(begin, end string, segmentSize int64) ([]string, error) {
+	beginSegment, err := postgresutils.SegmentFromName(begin)
+	if err != nil {
+		return nil, err
+	}
+	endSegment, err := postgresutils.SegmentFromName(end)
+	if err != nil {
+		return nil, err
+	}
+
+	if beginSegment.Tli != endSegment.Tli ||
+		beginSegment.Log > endSegment.Log ||
+		(beginSegment.Log == endSegment.Log && beginSegment.Seg > endSegment.Seg) {
+		return nil, nil
+	}
+
+	result := []string{beginSegment.Name()}
+	for current := beginSegment; current != endSegment; {
+		current = current.NextSegments(2, nil, &segmentSize)[1]
+		result = append(result, current.Name())
+	}
+
+	return result, nil
+}
+
+// buildCondition creates the BackupConsistency condition from the report
+func (r *report) buildCondition() *metav1.Condition {
+	var problems []string
+	if len(r.incompleteBackups) > 0 {
+		problems = append(problems,
+			fmt.Sprintf("incomplete backups: %s", joinNames(r.incompleteBackups)))
+	}
+	if len(r.missingWALs) > 0 {
+		problems = append(problems,
+			fmt.Sprintf("missing WAL files: %s", joinNames(r.missingWALs)))
+	}
+	if len(r.corruptedWALs) > 0 {
+		problems = append(problems,
+			fmt.Sprintf("unreadable WAL files: %s", joinNames(r.corruptedWALs)))
+	}
+
+	if len(problems) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionBackupConsistency),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonBackupsConsistent),
+			Message: "Every backup and the WAL files needed to recover from it are available",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionBackupConsistency),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonBackupsInconsistent),
+		Message: strings.Join(problems, "; "),
+	}
+}
+
+// joinNames joins the passed names, truncating the list
+// to maxReportedNames elements
+func joinNames(names []string) string {
+	if len(names) <= maxReportedNames {
+		return strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more",
+		strings.Join(names[:maxReportedNames], ", "), len(names)-maxReportedNames)
+}
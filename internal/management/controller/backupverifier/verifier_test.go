@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupverifier
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification", func() {
+	const segmentSize = int64(1 << 24)
+	now := time.Now()
+
+	backupList := &catalog.Catalog{List: []catalog.BarmanBackup{
+		{
+			ID: "first", BeginTime: now.Add(-2 * time.Hour), EndTime: now.Add(-2 * time.Hour),
+			BeginWal: "0000000100000000000000FE", EndWal: "000000010000000100000000",
+		},
+		{ID: "failed", BeginTime: now.Add(-time.Hour), Error: "failure", BeginWal: "000000010000000100000003"},
+		{
+			ID: "second", BeginTime: now.Add(-time.Hour), EndTime: now.Add(-time.Hour),
+			BeginWal: "000000010000000100000005", EndWal: "000000010000000100000006",
+		},
+	}}
+
+	It("computes the WAL files in a range", func() {
+		Expect(GetWALRange("0000000100000000000000FE", "000000010000000100000001", segmentSize)).To(Equal([]string{
+			"0000000100000000000000FE",
+			"0000000100000000000000FF",
+			"000000010000000100000000",
+			"000000010000000100000001",
+		}))
+		Expect(GetWALRange("000000010000000100000001", "000000010000000100000001", segmentSize)).To(
+			Equal([]string{"000000010000000100000001"}))
+	})
+
+	It("doesn't follow timeline switches", func() {
+		Expect(GetWALRange("000000010000000100000001", "000000020000000100000003", segmentSize)).To(BeEmpty())
+		Expect(GetWALRange("000000010000000100000003", "000000010000000100000001", segmentSize)).To(BeEmpty())
+	})
+
+	It("detects the incomplete backups", func() {
+		Expect(getIncompleteBackups(backupList)).To(Equal([]string{"failed"}))
+	})
+
+	It("requires every WAL file from the first completed backup to the last archived one", func() {
+		Expect(getRequiredWALs(backupList, "000000010000000100000008", segmentSize)).To(Equal([]string{
+			"0000000100000000000000FE",
+			"0000000100000000000000FF",
+			"000000010000000100000000",
+			"000000010000000100000001",
+			"000000010000000100000002",
+			"000000010000000100000003",
+			"000000010000000100000004",
+			"000000010000000100000005",
+			"000000010000000100000006",
+			"000000010000000100000007",
+			"000000010000000100000008",
+		}))
+
+		// Without archived WAL files, up to the end of the latest backup
+		Expect(getRequiredWALs(backupList, "", segmentSize)).To(HaveLen(9))
+	})
+
+	It("requires only the WAL files of a backup followed by one on another timeline", func() {
+		switchedList := &catalog.Catalog{List: []catalog.BarmanBackup{
+			backupList.List[0],
+			{
+				ID: "promoted", BeginTime: now.Add(-time.Hour), EndTime: now.Add(-time.Hour),
+				BeginWal: "000000020000000100000005", EndWal: "000000020000000100000005",
+			},
+		}}
+		Expect(getRequiredWALs(switchedList, "000000020000000100000006", segmentSize)).To(Equal([]string{
+			"0000000100000000000000FE",
+			"0000000100000000000000FF",
+			"000000010000000100000000",
+			"000000020000000100000005",
+			"000000020000000100000006",
+		}))
+	})
+
+	It("builds the condition from the report", func() {
+		condition := (&report{}).buildCondition()
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBackupsConsistent)))
+
+		condition = (&report{
+			incompleteBackups: []string{"failed"},
+			missingWALs:       []string{"000000010000000100000007"},
+		}).buildCondition()
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBackupsInconsistent)))
+		Expect(condition.Message).To(Equal(
+			"incomplete backups: failed; missing WAL files: 000000010000000100000007"))
+	})
+
+	It("truncates the list of names in the condition", func() {
+		names := make([]string, maxReportedNames+2)
+		for idx := range names {
+			names[idx] = "wal"
+		}
+		Expect(joinNames(names)).To(HaveSuffix("and 2 more"))
+	})
+})
@@ -0,0 +1,174 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupverifier
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// walPageMagicOffset, and the following ones, are the offsets of
+	// the fields of the header of a WAL page (XLogPageHeaderData)
+	walPageMagicOffset     = 0
+	walPageInfoOffset      = 2
+	walPageTLIOffset       = 4
+	walPageAddressOffset   = 8
+	walPageRemLenOffset    = 16
+	walPageSegSizeOffset   = 32
+	walPageBlockSizeOffset = 36
+
+	// walShortPageHeaderSize is the size of the header of every WAL page
+	// but the first one of a segment, which uses walLongPageHeaderSize
+	walShortPageHeaderSize = 24
+	walLongPageHeaderSize  = 40
+
+	// walRecordHeaderSize is the size of the header of a WAL record
+	// (XLogRecord), whose checksum is stored at walRecordCRCOffset
+	walRecordHeaderSize = 24
+	walRecordCRCOffset  = 20
+
+	// walPageFirstIsContRecord is the flag of a WAL page starting with
+	// the continuation of a record
+	walPageFirstIsContRecord = 0x0001
+
+	// walRecordAlignment is the alignment of the WAL records
+	walRecordAlignment = 8
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// validateWALFile checks the content of a WAL segment fetched from the
+// archive: the size of the file, the header of every page, and the
+// checksum of every record starting and ending in the segment
+func validateWALFile(path string, walName string, segmentSize int64) error {
+	content, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	return validateWALSegment(content, walName, segmentSize)
+}
+
+// validateWALSegment checks the content of the passed WAL segment
+func validateWALSegment(content []byte, walName string, segmentSize int64) error {
+	if int64(len(content)) != segmentSize {
+		return fmt.Errorf("the size of the segment is %d bytes instead of %d", len(content), segmentSize)
+	}
+	if len(content) < walLongPageHeaderSize {
+		return fmt.Errorf("the segment is too short")
+	}
+
+	segment, err := postgresutils.SegmentFromName(walName)
+	if err != nil {
+		return err
+	}
+
+	if size := binary.LittleEndian.Uint32(content[walPageSegSizeOffset:]); int64(size) != segmentSize {
+		return fmt.Errorf("the segment size in the first page header is %d instead of %d", size, segmentSize)
+	}
+	pageSize := int(binary.LittleEndian.Uint32(content[walPageBlockSizeOffset:]))
+	if pageSize < walLongPageHeaderSize || len(content)%pageSize != 0 {
+		return fmt.Errorf("invalid WAL page size %d", pageSize)
+	}
+
+	// The records are validated on the payload of the pages, without
+	// their headers, up to the first page that has never been written
+	segmentAddress := uint64(segment.Log)<<32 + uint64(segment.Seg)*uint64(segmentSize)
+	magic := binary.LittleEndian.Uint16(content[walPageMagicOffset:])
+	payload := make([]byte, 0, len(content))
+	for offset := 0; offset < len(content); offset += pageSize {
+		page := content[offset : offset+pageSize]
+		headerSize := walShortPageHeaderSize
+		if offset == 0 {
+			headerSize = walLongPageHeaderSize
+		}
+
+		if binary.LittleEndian.Uint16(page[walPageMagicOffset:]) == 0 && isZero(page[:headerSize]) {
+			break
+		}
+		if pageMagic := binary.LittleEndian.Uint16(page[walPageMagicOffset:]); pageMagic != magic {
+			return fmt.Errorf("the page at offset %d has magic %#x instead of %#x", offset, pageMagic, magic)
+		}
+		if tli := binary.LittleEndian.Uint32(page[walPageTLIOffset:]); tli > uint32(segment.Tli) {
+			return fmt.Errorf("the page at offset %d belongs to timeline %d", offset, tli)
+		}
+		expectedAddress := segmentAddress + uint64(offset)
+		if address := binary.LittleEndian.Uint64(page[walPageAddressOffset:]); address != expectedAddress {
+			return fmt.Errorf("the page at offset %d has address %X instead of %X", offset, address, expectedAddress)
+		}
+
+		payload = append(payload, page[headerSize:]...)
+	}
+
+	return validateWALRecords(content, payload)
+}
+
+// validateWALRecords checks the checksums of the records contained in
+// the passed payload, skipping the continuation of the record started
+// in the previous segment and the record continuing in the next one
+func validateWALRecords(content []byte, payload []byte) error {
+	offset := 0
+	if binary.LittleEndian.Uint16(content[walPageInfoOffset:])&walPageFirstIsContRecord != 0 {
+		offset = alignWALRecord(int(binary.LittleEndian.Uint32(content[walPageRemLenOffset:])))
+	}
+
+	for offset+walRecordHeaderSize <= len(payload) {
+		totalLength := int(binary.LittleEndian.Uint32(payload[offset:]))
+		if totalLength == 0 {
+			// The rest of the segment has not been used, i.e. after a switch
+			return nil
+		}
+		if totalLength < walRecordHeaderSize {
+			return fmt.Errorf("the record at payload offset %d has an invalid length %d", offset, totalLength)
+		}
+		if offset+totalLength > len(payload) {
+			return nil
+		}
+
+		record := payload[offset : offset+totalLength]
+		checksum := crc32.Checksum(record[walRecordHeaderSize:], castagnoliTable)
+		checksum = crc32.Update(checksum, castagnoliTable, record[:walRecordCRCOffset])
+		if expected := binary.LittleEndian.Uint32(record[walRecordCRCOffset:]); checksum != expected {
+			return fmt.Errorf("the record at payload offset %d has checksum %#x instead of %#x",
+				offset, checksum, expected)
+		}
+
+		offset = alignWALRecord(offset + totalLength)
+	}
+
+	return nil
+}
+
+// alignWALRecord aligns the passed offset to the beginning of a record
+func alignWALRecord(offset int) int {
+	return (offset + walRecordAlignment - 1) &^ (walRecordAlignment - 1)
+}
+
+// isZero checks if every passed byte is zero
+func isZero(content []byte) bool {
+	for _, b := range content {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupverifier
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL file validation", func() {
+	const (
+		segmentSize = 1 << 20
+		pageSize    = 8192
+		walName     = "000000010000000000000003"
+	)
+
+	// newRecord creates a WAL record with the passed total length
+	newRecord := func(totalLength int) []byte {
+		record := make([]byte, alignWALRecord(totalLength))
+		binary.LittleEndian.PutUint32(record, uint32(totalLength))
+		for idx := walRecordHeaderSize; idx < totalLength; idx++ {
+			record[idx] = byte(idx)
+		}
+		checksum := crc32.Checksum(record[walRecordHeaderSize:totalLength], castagnoliTable)
+		checksum = crc32.Update(checksum, castagnoliTable, record[:walRecordCRCOffset])
+		binary.LittleEndian.PutUint32(record[walRecordCRCOffset:], checksum)
+		return record
+	}
+
+	// newSegment lays out the passed payload into the pages of a segment
+	newSegment := func(payload []byte, continuationLength int) []byte {
+		content := make([]byte, segmentSize)
+		for offset := 0; offset < segmentSize; offset += pageSize {
+			page := content[offset : offset+pageSize]
+			headerSize := walShortPageHeaderSize
+			binary.LittleEndian.PutUint16(page[walPageMagicOffset:], 0xD116)
+			binary.LittleEndian.PutUint32(page[walPageTLIOffset:], 1)
+			binary.LittleEndian.PutUint64(page[walPageAddressOffset:], uint64(3*segmentSize+offset))
+			if offset == 0 {
+				headerSize = walLongPageHeaderSize
+				info := uint16(0x0002)
+				if continuationLength > 0 {
+					info |= walPageFirstIsContRecord
+					binary.LittleEndian.PutUint32(page[walPageRemLenOffset:], uint32(continuationLength))
+				}
+				binary.LittleEndian.PutUint16(page[walPageInfoOffset:], info)
+				binary.LittleEndian.PutUint32(page[walPageSegSizeOffset:], segmentSize)
+				binary.LittleEndian.PutUint32(page[walPageBlockSizeOffset:], pageSize)
+			}
+			n := copy(page[headerSize:], payload)
+			payload = payload[n:]
+		}
+		return content
+	}
+
+	newPayload := func(records ...[]byte) []byte {
+		var payload []byte
+		for _, record := range records {
+			payload = append(payload, record...)
+		}
+		return payload
+	}
+
+	It("accepts a segment with valid pages and records", func() {
+		payload := newPayload(newRecord(100), newRecord(pageSize*2), newRecord(30))
+		Expect(validateWALSegment(newSegment(payload, 0), walName, segmentSize)).To(Succeed())
+	})
+
+	It("skips the continuation of the previous record and the one continuing in the next segment", func() {
+		// The last record is truncated by the end of the segment
+		payloadSize := segmentSize - walLongPageHeaderSize - (segmentSize/pageSize-1)*walShortPageHeaderSize
+		payload := newPayload(make([]byte, 16), newRecord(100), newRecord(payloadSize))
+		Expect(validateWALSegment(newSegment(payload, 10), walName, segmentSize)).To(Succeed())
+
+		// The continuation is not mistaken for a record
+		payload = newPayload([]byte{0xFF, 0xFF, 0xFF, 0xFF}, make([]byte, 12), newRecord(100))
+		Expect(validateWALSegment(newSegment(payload, 10), walName, segmentSize)).To(Succeed())
+	})
+
+	It("detects a corrupted record", func() {
+		payload := newPayload(newRecord(100), newRecord(pageSize*2))
+		segment := newSegment(payload, 0)
+		segment[walLongPageHeaderSize+150]++
+		Expect(validateWALSegment(segment, walName, segmentSize)).To(MatchError(ContainSubstring("checksum")))
+	})
+
+	It("detects a page with the wrong address", func() {
+		segment := newSegment(newPayload(newRecord(100)), 0)
+		binary.LittleEndian.PutUint64(segment[pageSize+walPageAddressOffset:], 0)
+		Expect(validateWALSegment(segment, walName, segmentSize)).To(MatchError(ContainSubstring("address")))
+	})
+
+	It("detects a truncated segment", func() {
+		segment := newSegment(newPayload(newRecord(100)), 0)
+		Expect(validateWALSegment(segment[:segmentSize/2], walName, segmentSize)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// pendingConfigurationFile is the name of the file, in the PGDATA,
+	// recording a configuration change waiting for the instance to be
+	// restarted. Being in the PGDATA, it survives the recreation of the Pod
+	pendingConfigurationFile = ".pending-configuration"
+
+	// configurationPropagationReload is the path used for the configuration
+	// changes made live by reloading the instance
+	configurationPropagationReload = "reload"
+
+	// configurationPropagationRestart is the path used for the configuration
+	// changes made live by restarting the instance
+	configurationPropagationRestart = "restart"
+)
+
+// pendingConfiguration is a configuration change waiting for the instance
+// to be restarted
+type pendingConfiguration struct {
+	// Generation is the generation of the cluster containing the change
+	Generation int64 `json:"generation"`
+
+	// AcceptedAt is the time when the change has been accepted in the
+	// cluster specification
+	AcceptedAt time.Time `json:"acceptedAt"`
+}
+
+// readPendingConfiguration reads the configuration change waiting for the
+// instance to be restarted, returning nil if there is none
+func readPendingConfiguration(fileName string) (*pendingConfiguration, error) {
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil || content == nil {
+		return nil, err
+	}
+
+	var result pendingConfiguration
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// writePendingConfiguration records a configuration change waiting for the
+// instance to be restarted
+func writePendingConfiguration(fileName string, pending pendingConfiguration) error {
+	content, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(fileName, content, 0o600)
+	return err
+}
+
+// reconcileConfigurationPropagation measures the time needed for a change
+// of the PostgreSQL configuration in the cluster specification to be live
+// on this instance, either by reloading it or, when a restart is pending,
+// when the instance is started again. Failures are not fatal, as this is
+// only used to expose metrics
+func (r *InstanceReconciler) reconcileConfigurationPropagation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	configurationChanged bool,
+	restarted bool,
+) {
+	contextLogger := log.FromContext(ctx)
+	fileName := filepath.Join(r.instance.PgData, pendingConfigurationFile)
+
+	// The configuration changes not coming from a new generation of the
+	// cluster, like the ones following a failover, are not measured. The
+	// first generation seen by this instance manager is not measured either
+	isNewGeneration := configurationChanged && r.configurationGeneration != 0 &&
+		cluster.Generation > r.configurationGeneration
+	r.configurationGeneration = cluster.Generation
+
+	pending, err := readPendingConfiguration(fileName)
+	if err != nil {
+		contextLogger.Warning("Cannot read the pending configuration change, ignoring it", "error", err)
+		_ = fileutils.RemoveFile(fileName)
+		pending = nil
+	}
+
+	if !isNewGeneration && pending == nil {
+		return
+	}
+
+	status, err := r.instance.GetStatus()
+	if err != nil {
+		contextLogger.Warning("Cannot check if the configuration change is live", "error", err)
+		return
+	}
+
+	if status.PendingRestart {
+		if pending != nil {
+			return
+		}
+		if err := writePendingConfiguration(fileName, pendingConfiguration{
+			Generation: cluster.Generation,
+			AcceptedAt: cluster.GetSpecChangeTimestamp(),
+		}); err != nil {
+			contextLogger.Warning("Cannot record the pending configuration change", "error", err)
+		}
+		return
+	}
+
+	path := configurationPropagationReload
+	acceptedAt := cluster.GetSpecChangeTimestamp()
+	if restarted {
+		path = configurationPropagationRestart
+	}
+	if pending != nil {
+		path = configurationPropagationRestart
+		acceptedAt = pending.AcceptedAt
+		if err := fileutils.RemoveFile(fileName); err != nil {
+			contextLogger.Warning("Cannot remove the pending configuration change", "error", err)
+		}
+	}
+
+	latency := time.Since(acceptedAt)
+	contextLogger.Info("The configuration change is live on the instance",
+		"path", path,
+		"latency", latency.String())
+	exporter := r.metricsServerExporter
+	exporter.Metrics.ConfigurationPropagation.Latency.WithLabelValues(path).Set(latency.Seconds())
+	exporter.Metrics.ConfigurationPropagation.Propagations.WithLabelValues(path).Inc()
+}
@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pending configuration changes", func() {
+	var fileName string
+
+	BeforeEach(func() {
+		fileName = filepath.Join(GinkgoT().TempDir(), pendingConfigurationFile)
+	})
+
+	It("returns nil when no change is pending", func() {
+		pending, err := readPendingConfiguration(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeNil())
+	})
+
+	It("records the pending changes", func() {
+		acceptedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		Expect(writePendingConfiguration(fileName, pendingConfiguration{
+			Generation: 4,
+			AcceptedAt: acceptedAt,
+		})).To(Succeed())
+
+		pending, err := readPendingConfiguration(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending.Generation).To(BeEquivalentTo(4))
+		Expect(pending.AcceptedAt.Equal(acceptedAt)).To(BeTrue())
+	})
+
+	It("reports a corrupted file", func() {
+		Expect(os.WriteFile(fileName, []byte("not json"), 0o600)).To(Succeed())
+		_, err := readPendingConfiguration(fileName)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,322 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// listParameters are the configuration parameters accepting a list of
+// values, which need to be passed as separate literals to `ALTER DATABASE`
+var listParameters = stringset.From([]string{
+	"search_path",
+	"temp_tablespaces",
+	"local_preload_libraries",
+	"session_preload_libraries",
+})
+
+// defaultPrivilegeObjectTypes maps the object types to the values of
+// pg_default_acl.defaclobjtype
+var defaultPrivilegeObjectTypes = map[apiv1.DefaultPrivilegeObjectType]string{
+	apiv1.DefaultPrivilegeObjectTypeTables:    "r",
+	apiv1.DefaultPrivilegeObjectTypeSequences: "S",
+	apiv1.DefaultPrivilegeObjectTypeFunctions: "f",
+	apiv1.DefaultPrivilegeObjectTypeTypes:     "T",
+	apiv1.DefaultPrivilegeObjectTypeSchemas:   "n",
+}
+
+// splitListParameter returns the elements of the value of a list
+// parameter, without the surrounding spaces and double quotes
+func splitListParameter(value string) []string {
+	var result []string
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		if len(element) >= 2 && strings.HasPrefix(element, `"`) && strings.HasSuffix(element, `"`) {
+			element = element[1 : len(element)-1]
+		}
+		if element != "" {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// normalizeParameterValue returns the value of a parameter in a form
+// which can be compared with the one stored by PostgreSQL
+func normalizeParameterValue(name, value string) string {
+	if !listParameters.Has(name) {
+		return value
+	}
+	return strings.Join(splitListParameter(value), ", ")
+}
+
+// quoteParameterValue returns the value of a parameter as required by
+// `ALTER DATABASE ... SET`
+func quoteParameterValue(name, value string) string {
+	if !listParameters.Has(name) {
+		return pq.QuoteLiteral(value)
+	}
+
+	elements := splitListParameter(value)
+	if len(elements) == 0 {
+		return "''"
+	}
+	for idx := range elements {
+		elements[idx] = pq.QuoteLiteral(elements[idx])
+	}
+	return strings.Join(elements, ", ")
+}
+
+// getDatabaseParameters reads the configuration parameters set on the
+// passed database, excluding the ones set for a role in the database
+func getDatabaseParameters(ctx context.Context, db *sql.DB, database string) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT cfg
+		FROM pg_db_role_setting s
+		JOIN pg_database d ON d.oid = s.setdatabase,
+		LATERAL unnest(s.setconfig) AS cfg
+		WHERE d.datname = $1 AND s.setrole = 0`,
+		database)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var setting string
+		if err := rows.Scan(&setting); err != nil {
+			return nil, err
+		}
+		name, value, _ := strings.Cut(setting, "=")
+		result[name] = value
+	}
+
+	return result, rows.Err()
+}
+
+// buildDatabaseParametersStatements returns the statements setting the
+// desired parameters of a database and resetting the ones that are not
+// desired anymore, in a stable order. The synchronous_commit parameter is
+// managed by reconcileSynchronousCommit
+func buildDatabaseParametersStatements(database string, current, desired map[string]string) []string {
+	alterCommand := fmt.Sprintf("ALTER DATABASE %s", pgx.Identifier{database}.Sanitize())
+
+	var statements []string
+	for name, value := range desired {
+		currentValue, found := current[name]
+		if !found || normalizeParameterValue(name, currentValue) != normalizeParameterValue(name, value) {
+			statements = append(statements,
+				fmt.Sprintf("%s SET %s TO %s", alterCommand, name, quoteParameterValue(name, value)))
+		}
+	}
+	for name := range current {
+		if _, found := desired[name]; !found && name != "synchronous_commit" {
+			statements = append(statements, fmt.Sprintf("%s RESET %s", alterCommand, name))
+		}
+	}
+
+	sort.Strings(statements)
+	return statements
+}
+
+// getGrantedDefaultPrivileges reads the privileges the grantee of the passed
+// default privilege has already been granted
+func getGrantedDefaultPrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	role string,
+	privilege apiv1.DefaultPrivilege,
+) (*stringset.Data, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT a.privilege_type
+		FROM pg_default_acl d,
+		LATERAL aclexplode(d.defaclacl) AS a
+		WHERE d.defaclrole = (SELECT oid FROM pg_roles WHERE rolname = $1)
+		AND d.defaclnamespace = COALESCE((SELECT oid FROM pg_namespace WHERE nspname = $2), 0)
+		AND d.defaclobjtype = $3
+		AND a.grantee = CASE WHEN upper($4) = 'PUBLIC' THEN 0
+			ELSE (SELECT oid FROM pg_roles WHERE rolname = $4) END`,
+		role, privilege.Schema, defaultPrivilegeObjectTypes[privilege.ObjectType], privilege.Grantee)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := stringset.New()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result.Put(name)
+	}
+
+	return result, rows.Err()
+}
+
+// buildDefaultPrivilegeStatement returns the statement granting the
+// privileges of the passed default privilege which are not granted yet,
+// or an empty string when every privilege is already granted
+func buildDefaultPrivilegeStatement(role string, privilege apiv1.DefaultPrivilege, granted *stringset.Data) string {
+	var missing []string
+	for _, name := range privilege.GetPrivileges() {
+		if !granted.Has(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+
+	statement := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s", pgx.Identifier{role}.Sanitize())
+	if privilege.Schema != "" {
+		statement += fmt.Sprintf(" IN SCHEMA %s", pgx.Identifier{privilege.Schema}.Sanitize())
+	}
+
+	grantee := pgx.Identifier{privilege.Grantee}.Sanitize()
+	if strings.EqualFold(privilege.Grantee, "PUBLIC") {
+		grantee = "PUBLIC"
+	}
+
+	return fmt.Sprintf("%s GRANT %s ON %s TO %s",
+		statement, strings.Join(missing, ", "), strings.ToUpper(string(privilege.ObjectType)), grantee)
+}
+
+// reconcileDatabaseDefaults applies the configuration parameters and the
+// default privileges of the declared databases on the primary instance
+func (r *InstanceReconciler) reconcileDatabaseDefaults(ctx context.Context, cluster *apiv1.Cluster) error {
+	databases := cluster.Spec.PostgresConfiguration.DatabaseDefaults
+	if len(databases) == 0 {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	var errors []string
+	for _, database := range databases {
+		if err := r.reconcileDatabaseParameters(ctx, superUserDB, database); err != nil {
+			errors = append(errors, fmt.Sprintf("database %s: %v", database.Name, err))
+			continue
+		}
+		if err := r.reconcileDefaultPrivileges(ctx, superUserDB, database); err != nil {
+			errors = append(errors, fmt.Sprintf("database %s: %v", database.Name, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("cannot update the database defaults: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// reconcileDatabaseParameters applies the configuration parameters of a
+// database
+func (r *InstanceReconciler) reconcileDatabaseParameters(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	database apiv1.DatabaseDefaultsConfiguration,
+) error {
+	current, err := getDatabaseParameters(ctx, superUserDB, database.Name)
+	if err != nil {
+		return fmt.Errorf("while reading the parameters: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	for _, statement := range buildDatabaseParametersStatements(database.Name, current, database.Parameters) {
+		contextLogger.Info("Updating the parameters of the database", "statement", statement)
+		if _, err := superUserDB.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%s: %w", statement, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileDefaultPrivileges grants the default privileges of a database.
+// The default privileges are stored per database, so they are applied
+// through a connection to it
+func (r *InstanceReconciler) reconcileDefaultPrivileges(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	database apiv1.DatabaseDefaultsConfiguration,
+) error {
+	if len(database.DefaultPrivileges) == 0 {
+		return nil
+	}
+
+	var owner string
+	row := superUserDB.QueryRowContext(ctx,
+		"SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_catalog.pg_database WHERE datname = $1",
+		database.Name)
+	if err := row.Scan(&owner); err != nil {
+		return fmt.Errorf("while reading the owner: %w", err)
+	}
+
+	db, err := r.instance.ConnectionPool().Connection(database.Name)
+	if err != nil {
+		return fmt.Errorf("while connecting: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	for _, privilege := range database.DefaultPrivileges {
+		role := privilege.Role
+		if role == "" {
+			role = owner
+		}
+
+		granted, err := getGrantedDefaultPrivileges(ctx, db, role, privilege)
+		if err != nil {
+			return fmt.Errorf("while reading the default privileges: %w", err)
+		}
+
+		statement := buildDefaultPrivilegeStatement(role, privilege, granted)
+		if statement == "" {
+			continue
+		}
+
+		contextLogger.Info("Granting the default privileges", "database", database.Name, "statement", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%s: %w", statement, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("database parameters", func() {
+	It("sets the missing and the changed parameters", func() {
+		current := map[string]string{
+			"statement_timeout": "1min",
+		}
+		desired := map[string]string{
+			"statement_timeout": "30s",
+			"search_path":       `"$user", app, public`,
+		}
+		Expect(buildDatabaseParametersStatements("app", current, desired)).To(Equal([]string{
+			`ALTER DATABASE "app" SET search_path TO '$user', 'app', 'public'`,
+			`ALTER DATABASE "app" SET statement_timeout TO '30s'`,
+		}))
+	})
+
+	It("doesn't change the list parameters that are already correct", func() {
+		current := map[string]string{
+			"search_path": `"$user", app, public`,
+		}
+		desired := map[string]string{
+			"search_path": `"$user",app,  "public"`,
+		}
+		Expect(buildDatabaseParametersStatements("app", current, desired)).To(BeEmpty())
+	})
+
+	It("resets the parameters that are not desired anymore, except synchronous_commit", func() {
+		current := map[string]string{
+			"work_mem":           "64MB",
+			"synchronous_commit": "off",
+		}
+		Expect(buildDatabaseParametersStatements("app", current, nil)).To(Equal([]string{
+			`ALTER DATABASE "app" RESET work_mem`,
+		}))
+	})
+})
+
+var _ = Describe("database default privileges", func() {
+	It("grants the missing privileges", func() {
+		privilege := apiv1.DefaultPrivilege{
+			Schema:     "app",
+			ObjectType: apiv1.DefaultPrivilegeObjectTypeTables,
+			Privileges: []string{"select", "insert"},
+			Grantee:    "reporting",
+		}
+		Expect(buildDefaultPrivilegeStatement("app", privilege, stringset.From([]string{"SELECT"}))).To(Equal(
+			`ALTER DEFAULT PRIVILEGES FOR ROLE "app" IN SCHEMA "app" GRANT INSERT ON TABLES TO "reporting"`))
+	})
+
+	It("expands ALL and grants to PUBLIC", func() {
+		privilege := apiv1.DefaultPrivilege{
+			ObjectType: apiv1.DefaultPrivilegeObjectTypeSequences,
+			Privileges: []string{"ALL"},
+			Grantee:    "public",
+		}
+		Expect(buildDefaultPrivilegeStatement("app", privilege, stringset.New())).To(Equal(
+			`ALTER DEFAULT PRIVILEGES FOR ROLE "app" GRANT USAGE, SELECT, UPDATE ON SEQUENCES TO PUBLIC`))
+	})
+
+	It("doesn't grant anything when every privilege is already granted", func() {
+		privilege := apiv1.DefaultPrivilege{
+			ObjectType: apiv1.DefaultPrivilegeObjectTypeFunctions,
+			Privileges: []string{"EXECUTE"},
+			Grantee:    "reporting",
+		}
+		Expect(buildDefaultPrivilegeStatement("app", privilege, stringset.From([]string{"EXECUTE"}))).To(BeEmpty())
+	})
+})
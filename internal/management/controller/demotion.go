@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileDemotion demotes the primary instance of a cluster whose replica
+// mode has been enabled, making it the designated primary of the replica
+// cluster. The replica configuration is written before restarting the
+// instance, so that the clean shutdown creates the checkpoint described
+// by the demotion token
+func (r *InstanceReconciler) reconcileDemotion(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (restarted bool, err error) {
+	if !cluster.IsReplica() || cluster.Status.TargetPrimary != r.instance.PodName {
+		return false, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return false, err
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Info("The replica mode has been enabled, demoting the primary instance " +
+		"to the designated primary of the replica cluster")
+
+	r.instance.RunInstanceHooks(ctx, apiv1.InstanceHookEventPreDemote)
+
+	if _, err := r.writeReplicaConfigurationForDesignatedPrimary(ctx, cluster); err != nil {
+		return false, err
+	}
+
+	if err := r.instance.RequestAndWaitRestartSmartFast(); err != nil {
+		return true, err
+	}
+
+	cluster.LogTimestampsWithMessage(ctx, "Primary instance demoted to designated primary")
+	return true, nil
+}
+
+// reconcileDemotionToken writes the demotion token in the cluster status
+// when this instance is the designated primary of a replica cluster
+func (r *InstanceReconciler) reconcileDemotionToken(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.IsReplica() || cluster.Status.CurrentPrimary != r.instance.PodName {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || isPrimary {
+		return err
+	}
+
+	token, err := r.instance.GetDemotionToken()
+	if err != nil {
+		return err
+	}
+	if token == cluster.Status.DemotionToken {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.DemotionToken = token
+	if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Updated the demotion token of the cluster")
+	return nil
+}
+
+// verifyPromotionToken checks whether the designated primary of a replica
+// cluster can be promoted, according to the promotion token set when
+// disabling the replica mode. A token is verified only once, so that the
+// following failovers are not affected by it
+func (r *InstanceReconciler) verifyPromotionToken(ctx context.Context, cluster *apiv1.Cluster) error {
+	token := cluster.GetPromotionToken()
+	if token == "" || token == cluster.Status.LastPromotionToken {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Verifying the promotion token before the promotion")
+	return r.instance.VerifyPromotionToken(token)
+}
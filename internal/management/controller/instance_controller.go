@@ -54,6 +54,7 @@ import (
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -180,6 +181,10 @@ func (r *InstanceReconciler) Reconcile(
 	}
 
 	restarted, err := r.reconcilePrimary(ctx, cluster)
+	if errors.Is(err, postgres.ErrPromotionTokenNotReached) {
+		contextLogger.Info("Waiting for the WALs of the former primary to be replayed before the promotion")
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -191,6 +196,16 @@ func (r *InstanceReconciler) Reconcile(
 
 	restarted = restarted || restartedFromOldPrimary
 
+	restartedFromDemotion, err := r.reconcileDemotion(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	restarted = restarted || restartedFromDemotion
+	if restartedFromDemotion {
+		// the demotion token is written as soon as the instance is up again
+		requeue = true
+	}
+
 	if r.IsDBUp(ctx) != nil {
 		return reconcile.Result{RequeueAfter: time.Second}, nil
 	}
@@ -213,14 +228,43 @@ func (r *InstanceReconciler) Reconcile(
 		}
 	}
 
+	r.reconcileConfigurationPropagation(ctx, cluster, reloadConfigNeeded, restarted)
+	r.reconcileMonitoringQueriesValidation(ctx, cluster)
+
 	if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
 
+	if err := r.reconcileTablespaces(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile tablespaces: %w", err)
+	}
+
 	if err := r.reconcileDatabases(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := r.reconcileStatisticsAfterUpgrade(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot generate the optimizer statistics after the upgrade: %w", err)
+	}
+
+	// The failures are reported in the cluster status, without blocking
+	// the rest of the reconciliation
+	if r.reconcileSynchronousCommit(ctx, cluster) {
+		requeue = true
+	}
+
+	if err := r.reconcileDatabaseDefaults(ctx, cluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileLogicalDecoding(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the logical replication slots: %w", err)
+	}
+
+	if err := r.reconcileDemotionToken(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot update the demotion token: %w", err)
+	}
+
 	// Extremely important.
 	// It could happen that current primary is reconciled before all the topology is extracted by the operator.
 	// We should detect that and schedule the instance manager for another run otherwise we will end up having
@@ -280,6 +324,12 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	reloadIdent, err := r.instance.RefreshPGIdent(cluster)
+	if err != nil {
+		return false, err
+	}
+	reloadNeeded = reloadNeeded || reloadIdent
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster)
@@ -333,6 +383,10 @@ func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Clus
 		return err
 	}
 
+	if err := ensureTablespaceDirectories(cluster); err != nil {
+		return err
+	}
+
 	r.instance.SetFencing(cluster.IsInstanceFenced(r.instance.PodName))
 
 	return nil
@@ -366,7 +420,7 @@ func (r *InstanceReconciler) verifyParametersForFollower(cluster *apiv1.Cluster)
 		return err
 	}
 
-	clusterParams := cluster.Spec.PostgresConfiguration.Parameters
+	clusterParams := cluster.GetPostgresqlParameters()
 	options := make(map[string]string)
 	for key, enforcedparam := range enforcedParams {
 		clusterparam, found := clusterParams[key]
@@ -418,18 +472,40 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		return false, err
 	}
 
-	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
-
-	db, err := r.instance.GetSuperUserDB()
-	if err != nil {
-		contextLogger.Error(err, "Cannot connect to primary server")
+	// Remove this instance from the -rw service as soon as possible
+	if cluster.IsRestrictedReplica(r.instance.PodName) {
+		r.updateRoleLabel(ctx, specs.ClusterRoleLabelRestricted)
 	} else {
-		_, err = db.Exec("CHECKPOINT")
-		if err != nil {
-			contextLogger.Error(err, "Error while requesting a checkpoint")
+		r.updateRoleLabel(ctx, specs.ClusterRoleLabelReplica)
+	}
+
+	// Client connections are drained only during a switchover, as in
+	// the case of a failover we want the instance to be demoted as soon
+	// as possible
+	if cluster.IsConnectionDrainingEnabled() && cluster.Status.TargetPrimary != apiv1.PendingFailoverMarker {
+		contextLogger.Info("This is an old primary node. Draining client connections before demotion",
+			"gracePeriod", cluster.GetConnectionDrainingGracePeriod())
+		if err := r.instance.DrainConnections(ctx, cluster.GetConnectionDrainingGracePeriod()); err != nil {
+			contextLogger.Error(err, "Error while draining client connections, proceeding with the demotion")
+		}
+	}
+
+	r.instance.RunInstanceHooks(ctx, apiv1.InstanceHookEventPreDemote)
+
+	// Prepared transactions and logical replication workers are looked for only
+	// during a switchover, following the same reasoning used for the draining
+	if cluster.Status.TargetPrimary != apiv1.PendingFailoverMarker {
+		if err := r.instance.ApplySwitchoverGuardrail(ctx, cluster.GetSwitchoverGuardrailPolicy()); err != nil {
+			contextLogger.Error(err, "Error while checking the prepared transactions, proceeding with the demotion")
 		}
 	}
 
+	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
+
+	if err := r.instance.CheckpointBeforeShutdown(ctx); err != nil {
+		contextLogger.Error(err, "Error while requesting a checkpoint")
+	}
+
 	contextLogger.Info("This is an old primary node. Shutting it down to get it demoted to a replica")
 
 	// Here we need to invoke a fast shutdown on the instance, and wait the instance
@@ -709,8 +785,15 @@ func (r *InstanceReconciler) reconcileMetrics(
 		}
 	}
 
-	exporter.Metrics.SyncReplicas.WithLabelValues("min").Set(float64(cluster.Spec.MinSyncReplicas))
-	exporter.Metrics.SyncReplicas.WithLabelValues("max").Set(float64(cluster.Spec.MaxSyncReplicas))
+	minSyncReplicas, maxSyncReplicas := cluster.Spec.MinSyncReplicas, cluster.Spec.MaxSyncReplicas
+	if synchronous := cluster.Spec.PostgresConfiguration.Synchronous; synchronous != nil {
+		minSyncReplicas, maxSyncReplicas = 0, synchronous.Number
+		if synchronous.IsRequired() {
+			minSyncReplicas = synchronous.Number
+		}
+	}
+	exporter.Metrics.SyncReplicas.WithLabelValues("min").Set(float64(minSyncReplicas))
+	exporter.Metrics.SyncReplicas.WithLabelValues("max").Set(float64(maxSyncReplicas))
 
 	syncReplicas, _ := cluster.GetSyncReplicasData()
 	exporter.Metrics.SyncReplicas.WithLabelValues("expected").Set(float64(syncReplicas))
@@ -723,7 +806,7 @@ func (r *InstanceReconciler) reconcileMetrics(
 }
 
 // reconcileMonitoringQueries applies the custom monitoring queries to the
-// web server
+// web server, scheduling their validation when they change
 func (r *InstanceReconciler) reconcileMonitoringQueries(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -739,8 +822,13 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 	queriesCollector := metrics.NewQueriesCollector("cnpg", r.instance, dbname)
 	queriesCollector.InjectUserQueries(metricserver.DefaultQueries)
 
+	// The content of every source of queries and the problems found while
+	// loading them, used to validate the queries when they change
+	var sources, problems []string
+
 	if cluster.Spec.Monitoring == nil {
 		r.metricsServerExporter.SetCustomQueries(queriesCollector)
+		r.scheduleMonitoringQueriesValidation(ctx, queriesCollector, sources, problems)
 		return
 	}
 
@@ -754,6 +842,7 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 			contextLogger.Warning("Unable to get configMap containing custom monitoring queries",
 				"reference", reference,
 				"error", err.Error())
+			problems = append(problems, fmt.Sprintf("configMap %s: %v", reference.Name, err))
 			continue
 		}
 
@@ -761,14 +850,17 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 		if !ok {
 			contextLogger.Warning("Missing key in configMap",
 				"reference", reference)
+			problems = append(problems, fmt.Sprintf("configMap %s: missing key %s", reference.Name, reference.Key))
 			continue
 		}
+		sources = append(sources, data)
 
 		err = queriesCollector.ParseQueries([]byte(data))
 		if err != nil {
 			contextLogger.Warning("Error while parsing custom queries in ConfigMap",
 				"reference", reference,
 				"error", err.Error())
+			problems = append(problems, fmt.Sprintf("configMap %s: %v", reference.Name, err))
 			continue
 		}
 	}
@@ -780,6 +872,7 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 			contextLogger.Warning("Unable to get secret containing custom monitoring queries",
 				"reference", reference,
 				"error", err.Error())
+			problems = append(problems, fmt.Sprintf("secret %s: %v", reference.Name, err))
 			continue
 		}
 
@@ -787,19 +880,23 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 		if !ok {
 			contextLogger.Warning("Missing key in secret",
 				"reference", reference)
+			problems = append(problems, fmt.Sprintf("secret %s: missing key %s", reference.Name, reference.Key))
 			continue
 		}
+		sources = append(sources, string(data))
 
 		err = queriesCollector.ParseQueries(data)
 		if err != nil {
 			contextLogger.Warning("Error while parsing custom queries in Secret",
 				"reference", reference,
 				"error", err.Error())
+			problems = append(problems, fmt.Sprintf("secret %s: %v", reference.Name, err))
 			continue
 		}
 	}
 
 	r.metricsServerExporter.SetCustomQueries(queriesCollector)
+	r.scheduleMonitoringQueriesValidation(ctx, queriesCollector, sources, problems)
 }
 
 // RefreshSecrets is called when the PostgreSQL secrets are changed
@@ -875,6 +972,12 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.PgCtlTimeoutForPromotion = cluster.GetPgCtlTimeoutForPromotion()
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
+	r.instance.ShutdownCheckpointEnabled = cluster.IsShutdownCheckpointEnabled()
+	r.instance.ShutdownCheckpointMaxWalSize = cluster.GetShutdownCheckpointMaxWalSize()
+	r.instance.LongRunningTransactionsThreshold = cluster.GetLongRunningTransactionsThreshold()
+	r.instance.ReplicationSSLMode = cluster.GetReplicationSSLMode()
+	r.instance.Plugins = cluster.GetPluginNames()
+	r.instance.SetInstanceHooks(cluster.Spec.InstanceHooks)
 }
 
 func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster) error {
@@ -1073,11 +1176,23 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 
 	// If I'm not the primary, let's promote myself
 	if !isPrimary {
+		// The promotion of a replica cluster waits for the WALs
+		// of the demoted primary cluster to be replayed
+		if err := r.verifyPromotionToken(ctx, cluster); err != nil {
+			return false, err
+		}
+
 		cluster.LogTimestampsWithMessage(ctx, "Setting myself as primary")
 		if err := r.promoteAndWait(ctx, cluster); err != nil {
 			return false, err
 		}
 		restarted = true
+
+		// The demotion token of this cluster is meaningless after the promotion
+		cluster.Status.DemotionToken = ""
+		if token := cluster.GetPromotionToken(); token != "" {
+			cluster.Status.LastPromotionToken = token
+		}
 	}
 
 	// if the currentPrimary doesn't match the PodName we set the correct value.
@@ -1097,6 +1212,12 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 		return restarted, nil
 	}
 
+	// The designated primary of a replica cluster is already the current primary
+	if cluster.Status.DemotionToken != oldCluster.Status.DemotionToken ||
+		cluster.Status.LastPromotionToken != oldCluster.Status.LastPromotionToken {
+		return restarted, r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+	}
+
 	// If it is already the current primary, everything is ok
 	return restarted, nil
 }
@@ -1113,15 +1234,46 @@ func (r *InstanceReconciler) promoteAndWait(ctx context.Context, cluster *apiv1.
 		}
 	}
 
+	r.instance.RunInstanceHooks(ctx, apiv1.InstanceHookEventPrePromote)
+
 	contextLogger.Info("I'm the target primary, applying WALs and promoting my instance")
 	// I must promote my instance here
 	err := r.instance.PromoteAndWait()
 	if err != nil {
 		return fmt.Errorf("error promoting instance: %w", err)
 	}
+
+	r.updateRoleLabel(ctx, specs.ClusterRoleLabelPrimary)
+	r.instance.RunInstanceHooks(ctx, apiv1.InstanceHookEventPostPromote)
+	r.startPrewarm(ctx, cluster)
 	return nil
 }
 
+// startPrewarm loads the configured relations in the shared buffers of the
+// newly promoted primary. The operation is executed in background as it
+// must not delay the completion of the promotion. The reconciliation
+// context is derived from the one of the manager and is not cancelled when
+// the reconciliation completes, so it stops the prewarm only when the
+// instance manager is shutting down
+func (r *InstanceReconciler) startPrewarm(ctx context.Context, cluster *apiv1.Cluster) {
+	prewarm := cluster.Spec.PostgresConfiguration.Prewarm
+	if prewarm == nil || len(prewarm.Relations) == 0 {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx)
+	relations := make([]apiv1.PrewarmRelation, len(prewarm.Relations))
+	copy(relations, prewarm.Relations)
+	maxParallel := prewarm.MaxParallel
+
+	go func() {
+		contextLogger.Info("Prewarming relations after the promotion", "relations", len(relations))
+		if err := r.instance.Prewarm(ctx, relations, maxParallel); err != nil {
+			contextLogger.Error(err, "Error while prewarming relations after the promotion")
+		}
+	}()
+}
+
 // Reconciler designated primary logic for replica clusters
 func (r *InstanceReconciler) reconcileDesignatedPrimary(
 	ctx context.Context,
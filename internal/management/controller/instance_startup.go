@@ -210,6 +210,17 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(
 			return err
 		}
 
+		// The data of this instance is discarded and cloned from the new
+		// primary again, as requested by the re-attach strategy
+		if cluster.GetReattachStrategy() == apiv1.ReattachStrategyReclone {
+			contextLogger.Info("Cloning the new primary again, as required by the re-attach strategy",
+				"reattachStrategy", cluster.GetReattachStrategy())
+			if err := r.instance.Reclone(); err != nil {
+				return err
+			}
+			return r.instance.Demote(cluster)
+		}
+
 		tag := pkgUtils.GetImageTag(cluster.GetImageName())
 		pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
 		if err != nil {
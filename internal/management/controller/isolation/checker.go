@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package isolation
+
+import (
+	"context"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkerIdleInterval is the interval between two checks of the cluster
+// configuration when the isolation check is not enabled
+const checkerIdleInterval = 30 * time.Second
+
+// A Checker is a runner that periodically verifies whether the primary
+// instance is isolated from both the Kubernetes API server and its replicas
+type Checker struct {
+	instance *postgres.Instance
+
+	// client is the cached client used to read the cluster configuration
+	client client.Client
+
+	// apiReader is used to check whether the Kubernetes API server is
+	// reachable, and must not be backed by a cache
+	apiReader client.Reader
+
+	// readOnly is true when this runner made the instance read-only, and
+	// nil until the first change, as the instance manager may have been
+	// restarted while the instance was read-only
+	readOnly *bool
+}
+
+// NewChecker creates a new isolation Checker
+func NewChecker(instance *postgres.Instance, cli client.Client, apiReader client.Reader) *Checker {
+	return &Checker{
+		instance:  instance,
+		client:    cli,
+		apiReader: apiReader,
+	}
+}
+
+// Start starts running the isolation Checker
+func (c *Checker) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("isolation_checker")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	go func() {
+		interval := checkerIdleInterval
+		ticker := time.NewTicker(interval)
+
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated isolation Checker loop")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newInterval, err := c.check(ctx)
+			if err != nil {
+				contextLog.Warning("checking the network isolation of the primary instance", "err", err)
+			}
+
+			if newInterval != interval {
+				ticker.Reset(newInterval)
+				interval = newInterval
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// check runs the isolation check, applying the configured action when the
+// primary instance is isolated, and returns the time to wait before
+// the next check
+func (c *Checker) check(ctx context.Context) (time.Duration, error) {
+	contextLog := log.FromContext(ctx)
+
+	var cluster apiv1.Cluster
+	if err := c.client.Get(ctx, c.clusterKey(), &cluster); err != nil {
+		return checkerIdleInterval, err
+	}
+
+	if !cluster.IsIsolationCheckEnabled() {
+		return checkerIdleInterval, c.setReadOnly(ctx, false)
+	}
+
+	config := cluster.Spec.IsolationCheck
+	interval := config.GetInterval()
+
+	isPrimary, err := c.instance.IsPrimary()
+	if err != nil {
+		return interval, err
+	}
+	if !isPrimary {
+		// The read-only status must not survive a demotion, as the
+		// instance may be promoted again later
+		return interval, c.setReadOnly(ctx, false)
+	}
+
+	apiReachable := c.isAPIServerReachable(ctx, config.GetTimeout())
+	streamingReplicas := 0
+	if !apiReachable {
+		if streamingReplicas, err = c.instance.CountStreamingReplicas(ctx); err != nil {
+			return interval, err
+		}
+	}
+
+	quorum := cluster.GetIsolationCheckPeerQuorum()
+	if !isIsolated(apiReachable, streamingReplicas, quorum) {
+		return interval, c.setReadOnly(ctx, false)
+	}
+
+	contextLog.Warning("The primary instance is isolated from the Kubernetes API server and from its replicas",
+		"streamingReplicas", streamingReplicas,
+		"peerQuorum", quorum,
+		"action", config.GetAction())
+
+	switch config.GetAction() {
+	case apiv1.IsolationCheckActionReadOnly:
+		return interval, c.setReadOnly(ctx, true)
+	default:
+		c.instance.RequestFastImmediateShutdown()
+		return interval, nil
+	}
+}
+
+// isIsolated checks if the primary instance can neither reach the
+// Kubernetes API server nor receive a quorum of streaming replicas
+func isIsolated(apiReachable bool, streamingReplicas, quorum int) bool {
+	return !apiReachable && streamingReplicas < quorum
+}
+
+// isAPIServerReachable checks if the Kubernetes API server answers within
+// the passed timeout. Any answer, even an error, proves it's reachable
+func (c *Checker) isAPIServerReachable(ctx context.Context, timeout time.Duration) bool {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cluster apiv1.Cluster
+	err := c.apiReader.Get(checkCtx, c.clusterKey(), &cluster)
+	return err == nil ||
+		apierrs.IsNotFound(err) ||
+		apierrs.IsForbidden(err) ||
+		apierrs.IsUnauthorized(err)
+}
+
+// setReadOnly makes the instance read-only, or reverts the change made by
+// this runner. The first call is always applied, to reconcile the
+// setting persisted in postgresql.auto.conf
+func (c *Checker) setReadOnly(ctx context.Context, enabled bool) error {
+	if c.readOnly != nil && *c.readOnly == enabled {
+		return nil
+	}
+
+	if err := c.instance.SetDefaultTransactionReadOnly(ctx, enabled); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Changed the default read-only status of the transactions", "readOnly", enabled)
+	c.readOnly = &enabled
+	return nil
+}
+
+// clusterKey returns the key of the Cluster this instance belongs to
+func (c *Checker) clusterKey() types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: c.instance.Namespace,
+		Name:      c.instance.ClusterName,
+	}
+}
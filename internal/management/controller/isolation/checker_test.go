@@ -0,0 +1,42 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package isolation
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("isolation detection", func() {
+	It("is not isolated while the Kubernetes API server is reachable", func() {
+		Expect(isIsolated(true, 0, 1)).To(BeFalse())
+	})
+
+	It("is not isolated while a quorum of replicas is streaming", func() {
+		Expect(isIsolated(false, 1, 1)).To(BeFalse())
+		Expect(isIsolated(false, 3, 2)).To(BeFalse())
+	})
+
+	It("is isolated when neither the API server nor a quorum of replicas are reachable", func() {
+		Expect(isIsolated(false, 0, 1)).To(BeTrue())
+		Expect(isIsolated(false, 1, 2)).To(BeTrue())
+	})
+
+	It("is never isolated when no replica is required", func() {
+		Expect(isIsolated(false, 0, 0)).To(BeFalse())
+	})
+})
@@ -0,0 +1,269 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// existingReplicationSlot is a replication slot found on the instance
+type existingReplicationSlot struct {
+	slotType string
+	database string
+	plugin   string
+	failover bool
+}
+
+// getReplicationSlots reads the replication slots of the instance. The
+// failover flag is only read when requested, as it is not available
+// before PostgreSQL 17
+func getReplicationSlots(
+	ctx context.Context,
+	db *sql.DB,
+	withFailover bool,
+) (map[string]existingReplicationSlot, error) {
+	failoverColumn := "false"
+	if withFailover {
+		failoverColumn = "failover"
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT slot_name, slot_type, COALESCE(database, ''), COALESCE(plugin, ''), "+failoverColumn+
+			" FROM pg_catalog.pg_replication_slots")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]existingReplicationSlot)
+	for rows.Next() {
+		var name string
+		var slot existingReplicationSlot
+		if err := rows.Scan(&name, &slot.slotType, &slot.database, &slot.plugin, &slot.failover); err != nil {
+			return nil, err
+		}
+		result[name] = slot
+	}
+
+	return result, rows.Err()
+}
+
+// checkExistingReplicationSlot verifies that an existing replication slot
+// matches its declaration
+func checkExistingReplicationSlot(
+	declared apiv1.LogicalReplicationSlot,
+	existing existingReplicationSlot,
+	failover bool,
+) error {
+	if existing.slotType != "logical" {
+		return fmt.Errorf("slot %s exists and is a %s one", declared.Name, existing.slotType)
+	}
+
+	if existing.database != declared.Database || existing.plugin != declared.GetPlugin() {
+		return fmt.Errorf("slot %s exists with the %s plugin on the %s database",
+			declared.Name, existing.plugin, existing.database)
+	}
+
+	if failover && !existing.failover {
+		return fmt.Errorf("slot %s exists and is not synchronized to the replicas, "+
+			"drop it to have it created again", declared.Name)
+	}
+
+	return nil
+}
+
+// checkMissingReplicationSlot verifies that a missing replication slot
+// can be created. A failover slot which has already been created, and is
+// missing here, has not been synchronized to this instance before its
+// promotion: creating it again would silently lose the changes not yet
+// consumed by its clients
+func checkMissingReplicationSlot(
+	declared apiv1.LogicalReplicationSlot,
+	createdSlots *stringset.Data,
+	failover bool,
+) error {
+	if failover && createdSlots.Has(declared.Name) {
+		return fmt.Errorf("slot %s was not synchronized to this instance before its promotion, "+
+			"remove it from the declared slots and add it again to have it created", declared.Name)
+	}
+
+	return nil
+}
+
+// getDeclaredSlots gets the sorted list of the passed slots which are
+// still declared
+func getDeclaredSlots(slots *stringset.Data, declaredSlots *stringset.Data) []string {
+	result := make([]string, 0, slots.Len())
+	for _, name := range slots.ToSortedList() {
+		if declaredSlots.Has(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// buildLogicalDecodingCondition builds the condition reporting the
+// problems found while creating the logical replication slots
+func buildLogicalDecodingCondition(problems []string) *metav1.Condition {
+	if len(problems) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionLogicalDecoding),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonLogicalReplicationSlotsReady),
+			Message: "Every logical replication slot exists",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionLogicalDecoding),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonLogicalReplicationSlotsFailed),
+		Message: strings.Join(problems, "; "),
+	}
+}
+
+// reconcileLogicalDecoding creates the missing logical replication slots
+// on the primary instance, reporting the ones which cannot be created in
+// the cluster conditions. Unless the failover slots are enabled, the
+// logical replication slots are not kept by the replicas and are created
+// again after a failover
+func (r *InstanceReconciler) reconcileLogicalDecoding(ctx context.Context, cluster *apiv1.Cluster) error {
+	logicalDecoding := cluster.Spec.PostgresConfiguration.LogicalDecoding
+	if logicalDecoding == nil || len(logicalDecoding.Slots) == 0 {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	failover := logicalDecoding.AreFailoverSlotsEnabled()
+	existingSlots, err := getReplicationSlots(ctx, superUserDB, failover)
+	if err != nil {
+		return fmt.Errorf("while reading the replication slots: %w", err)
+	}
+
+	createdSlots := stringset.From(cluster.Status.LogicalReplicationSlots)
+	declaredSlots := stringset.New()
+	var problems []string
+	for _, slot := range logicalDecoding.Slots {
+		declaredSlots.Put(slot.Name)
+		if existing, found := existingSlots[slot.Name]; found {
+			if err := checkExistingReplicationSlot(slot, existing, failover); err != nil {
+				problems = append(problems, err.Error())
+			}
+			createdSlots.Put(slot.Name)
+			continue
+		}
+
+		if err := checkMissingReplicationSlot(slot, createdSlots, failover); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		if err := r.createLogicalReplicationSlot(ctx, slot, failover); err != nil {
+			problems = append(problems, fmt.Sprintf("slot %s: %v", slot.Name, err))
+			continue
+		}
+		createdSlots.Put(slot.Name)
+	}
+
+	if err := r.updateCreatedLogicalReplicationSlots(ctx, cluster, createdSlots, declaredSlots); err != nil {
+		return fmt.Errorf("while updating the created logical replication slots: %w", err)
+	}
+
+	return conditions.Update(ctx, r.client, cluster, buildLogicalDecodingCondition(problems))
+}
+
+// updateCreatedLogicalReplicationSlots stores in the cluster status the
+// created logical replication slots which are still declared, forgetting
+// the ones removed from the declaration
+func (r *InstanceReconciler) updateCreatedLogicalReplicationSlots(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	createdSlots *stringset.Data,
+	declaredSlots *stringset.Data,
+) error {
+	slots := getDeclaredSlots(createdSlots, declaredSlots)
+	if stringset.From(slots).Eq(stringset.From(cluster.Status.LogicalReplicationSlots)) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LogicalReplicationSlots = slots
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// createLogicalReplicationSlot creates a logical replication slot, after
+// checking that its output plugin can be loaded. When failover is set,
+// the slot is synchronized to the replicas
+func (r *InstanceReconciler) createLogicalReplicationSlot(
+	ctx context.Context,
+	slot apiv1.LogicalReplicationSlot,
+	failover bool,
+) error {
+	db, err := r.instance.ConnectionPool().Connection(slot.Database)
+	if err != nil {
+		return fmt.Errorf("while connecting to the %s database: %w", slot.Database, err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("while connecting to the %s database: %w", slot.Database, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, "LOAD "+pq.QuoteLiteral(slot.GetPlugin())); err != nil {
+		return fmt.Errorf("the %s output plugin is not available: %w", slot.GetPlugin(), err)
+	}
+
+	log.FromContext(ctx).Info("Creating the logical replication slot",
+		"slot", slot.Name, "database", slot.Database, "plugin", slot.GetPlugin(), "failover", failover)
+	query := "SELECT pg_catalog.pg_create_logical_replication_slot($1, $2)"
+	args := []interface{}{slot.Name, slot.GetPlugin()}
+	if failover {
+		query = "SELECT pg_catalog.pg_create_logical_replication_slot($1, $2, false, false, $3)"
+		args = append(args, true)
+	}
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logical replication slots", func() {
+	slot := apiv1.LogicalReplicationSlot{Name: "debezium", Database: "app"}
+
+	It("accepts the existing slots matching their declaration", func() {
+		Expect(checkExistingReplicationSlot(slot, existingReplicationSlot{
+			slotType: "logical",
+			database: "app",
+			plugin:   "pgoutput",
+		}, false)).To(Succeed())
+	})
+
+	It("complains about the existing slots with a different plugin or type", func() {
+		Expect(checkExistingReplicationSlot(slot, existingReplicationSlot{
+			slotType: "logical",
+			database: "app",
+			plugin:   "wal2json",
+		}, false)).ToNot(Succeed())
+		Expect(checkExistingReplicationSlot(slot, existingReplicationSlot{
+			slotType: "physical",
+		}, false)).ToNot(Succeed())
+	})
+
+	It("complains about the existing slots not synchronized to the replicas when requested", func() {
+		existing := existingReplicationSlot{
+			slotType: "logical",
+			database: "app",
+			plugin:   "pgoutput",
+		}
+		Expect(checkExistingReplicationSlot(slot, existing, true)).ToNot(Succeed())
+
+		existing.failover = true
+		Expect(checkExistingReplicationSlot(slot, existing, true)).To(Succeed())
+	})
+
+	It("doesn't create again the failover slots missing after a promotion", func() {
+		createdSlots := stringset.From([]string{"debezium"})
+		Expect(checkMissingReplicationSlot(slot, createdSlots, true)).ToNot(Succeed())
+		Expect(checkMissingReplicationSlot(slot, createdSlots, false)).To(Succeed())
+		Expect(checkMissingReplicationSlot(slot, stringset.New(), true)).To(Succeed())
+	})
+
+	It("forgets the created slots which are not declared anymore", func() {
+		createdSlots := stringset.From([]string{"debezium", "audit", "removed"})
+		declaredSlots := stringset.From([]string{"debezium", "audit"})
+		Expect(getDeclaredSlots(createdSlots, declaredSlots)).To(Equal([]string{"audit", "debezium"}))
+	})
+
+	It("reports the problems in the condition", func() {
+		Expect(buildLogicalDecodingCondition(nil).Status).To(Equal(metav1.ConditionTrue))
+
+		condition := buildLogicalDecodingCondition([]string{"slot audit: the wal2json output plugin is not available"})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonLogicalReplicationSlotsFailed)))
+		Expect(condition.Message).To(ContainSubstring("wal2json"))
+	})
+})
@@ -46,6 +46,20 @@ type InstanceReconciler struct {
 	systemInitialization  *concurrency.Executed
 	firstReconcileDone    atomic.Bool
 	metricsServerExporter *metricserver.Exporter
+
+	// analyzingAfterUpgrade is true while the optimizer statistics of
+	// the upgraded data directory are being generated
+	analyzingAfterUpgrade atomic.Bool
+
+	// configurationGeneration is the last generation of the cluster whose
+	// configuration has been applied to the instance
+	configurationGeneration int64
+
+	// validatedMonitoringQueriesHash identifies the last validated set of
+	// monitoring queries, while pendingMonitoringQueriesValidation contains
+	// the queries waiting to be validated
+	validatedMonitoringQueriesHash     string
+	pendingMonitoringQueriesValidation *monitoringQueriesValidation
 }
 
 // NewInstanceReconciler creates a new instance reconciler
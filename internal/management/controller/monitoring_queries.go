@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+// monitoringQueriesValidationTimeout is the statement timeout used to
+// dry-run the monitoring queries
+const monitoringQueriesValidationTimeout = 5 * time.Second
+
+// monitoringQueriesValidation contains the monitoring queries waiting to
+// be validated
+type monitoringQueriesValidation struct {
+	// hash identifies the sources of the queries
+	hash string
+
+	// collector contains the parsed queries
+	collector *metrics.QueriesCollector
+
+	// problems are the errors found while loading the queries
+	problems []string
+}
+
+// scheduleMonitoringQueriesValidation schedules the validation of the
+// passed queries, unless queries loaded from the same sources have already
+// been validated
+func (r *InstanceReconciler) scheduleMonitoringQueriesValidation(
+	ctx context.Context,
+	collector *metrics.QueriesCollector,
+	sources []string,
+	problems []string,
+) {
+	sourcesHash, err := hash.ComputeHash(struct {
+		Sources  []string
+		Problems []string
+	}{sources, problems})
+	if err != nil {
+		log.FromContext(ctx).Warning("Cannot compute the hash of the monitoring queries", "error", err)
+		return
+	}
+
+	if sourcesHash == r.validatedMonitoringQueriesHash {
+		r.pendingMonitoringQueriesValidation = nil
+		return
+	}
+
+	r.pendingMonitoringQueriesValidation = &monitoringQueriesValidation{
+		hash:      sourcesHash,
+		collector: collector,
+		problems:  problems,
+	}
+}
+
+// reconcileMonitoringQueriesValidation grants the privileges needed by the
+// monitoring queries that changed and dry-runs them, reporting the broken
+// ones in the cluster conditions. This is
+// done by the primary instance only, as the condition refers to the whole
+// cluster. Failures are not fatal, as the validation is retried in the next
+// reconciliation loop
+func (r *InstanceReconciler) reconcileMonitoringQueriesValidation(ctx context.Context, cluster *apiv1.Cluster) {
+	validation := r.pendingMonitoringQueriesValidation
+	if validation == nil {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx)
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || !isPrimary {
+		return
+	}
+
+	// The privileges are granted before the validation, as they are needed
+	// to execute the queries
+	grantErrors, err := validation.collector.GrantPrivileges(ctx)
+	if err != nil {
+		contextLogger.Warning("Cannot grant the privileges needed by the monitoring queries", "error", err)
+		return
+	}
+
+	brokenQueries, err := validation.collector.ValidateQueries(monitoringQueriesValidationTimeout)
+	if err != nil {
+		contextLogger.Warning("Cannot validate the monitoring queries", "error", err)
+		return
+	}
+	for name, err := range grantErrors {
+		brokenQueries[name] = err
+	}
+
+	condition := buildMonitoringQueriesCondition(validation.problems, brokenQueries)
+	if err := conditions.Update(ctx, r.client, cluster, condition); err != nil {
+		contextLogger.Warning("Cannot update the monitoring queries condition", "error", err)
+		return
+	}
+
+	r.validatedMonitoringQueriesHash = validation.hash
+	r.pendingMonitoringQueriesValidation = nil
+}
+
+// buildMonitoringQueriesCondition builds the condition reporting the
+// problems found while loading the monitoring queries and the broken ones
+func buildMonitoringQueriesCondition(problems []string, brokenQueries map[string]error) *metav1.Condition {
+	messages := append([]string{}, problems...)
+
+	names := make([]string, 0, len(brokenQueries))
+	for name := range brokenQueries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("query %s: %v", name, brokenQueries[name]))
+	}
+
+	if len(messages) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionMonitoringQueries),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonMonitoringQueriesValid),
+			Message: "The monitoring queries have been validated",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionMonitoringQueries),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonMonitoringQueriesInvalid),
+		Message: strings.Join(messages, "; "),
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("monitoring queries validation", func() {
+	It("reports the valid queries", func() {
+		condition := buildMonitoringQueriesCondition(nil, map[string]error{})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("MonitoringQueriesValid"))
+	})
+
+	It("reports the broken queries and the loading problems", func() {
+		condition := buildMonitoringQueriesCondition(
+			[]string{"configMap queries: missing key custom-queries"},
+			map[string]error{
+				"pg_replication": errors.New("on database app: canceling statement due to statement timeout"),
+				"broken":         errors.New("on database app: syntax error"),
+			})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("MonitoringQueriesInvalid"))
+		Expect(condition.Message).To(Equal("configMap queries: missing key custom-queries; " +
+			"query broken: on database app: syntax error; " +
+			"query pg_replication: on database app: canceling statement due to statement timeout"))
+	})
+
+	It("validates the queries only when their sources change", func() {
+		ctx := context.Background()
+		reconciler := &InstanceReconciler{}
+		collector := metrics.NewQueriesCollector("cnpg", nil, "app")
+
+		reconciler.scheduleMonitoringQueriesValidation(ctx, collector, []string{"queries"}, nil)
+		Expect(reconciler.pendingMonitoringQueriesValidation).ToNot(BeNil())
+
+		reconciler.validatedMonitoringQueriesHash = reconciler.pendingMonitoringQueriesValidation.hash
+		reconciler.scheduleMonitoringQueriesValidation(ctx, collector, []string{"queries"}, nil)
+		Expect(reconciler.pendingMonitoringQueriesValidation).To(BeNil())
+
+		reconciler.scheduleMonitoringQueriesValidation(ctx, collector, []string{"changed queries"}, nil)
+		Expect(reconciler.pendingMonitoringQueriesValidation).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// probingInterval is the interval between two probes of the plugins
+const probingInterval = 30 * time.Second
+
+// A Prober is a runner that periodically probes the plugins of the
+// instance, storing their status in the instance
+type Prober struct {
+	instance *postgres.Instance
+}
+
+// NewProber creates a new plugin status Prober
+func NewProber(instance *postgres.Instance) *Prober {
+	return &Prober{
+		instance: instance,
+	}
+}
+
+// Start starts running the plugin status Prober
+func (p *Prober) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("plugin_status_prober")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	go func() {
+		ticker := time.NewTicker(probingInterval)
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated plugin status Prober loop")
+		}()
+
+		for {
+			p.instance.RefreshPluginStatus(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// buildRoleLabelPatch creates the merge patch setting the role label
+// of a Pod to the passed value
+func buildRoleLabelPatch(role string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{
+				specs.ClusterRoleLabelName: role,
+			},
+		},
+	})
+}
+
+// updateRoleLabel sets the role label of the Pod running this instance,
+// letting the services follow a promotion or a demotion without waiting for
+// the operator to reconcile the labels. Failures are not fatal, as the
+// operator will eventually set the correct label
+func (r *InstanceReconciler) updateRoleLabel(ctx context.Context, role string) {
+	contextLogger := log.FromContext(ctx)
+
+	patch, err := buildRoleLabelPatch(role)
+	if err != nil {
+		contextLogger.Error(err, "Cannot build the role label patch", "role", role)
+		return
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.instance.PodName,
+			Namespace: r.instance.Namespace,
+		},
+	}
+	if err := r.client.Patch(ctx, pod, ctrl.RawPatch(types.MergePatchType, patch)); err != nil {
+		contextLogger.Warning("Cannot update the role label of the instance Pod, "+
+			"waiting for the operator to do it",
+			"role", role,
+			"error", err)
+		return
+	}
+
+	contextLogger.Info("Updated the role label of the instance Pod", "role", role)
+}
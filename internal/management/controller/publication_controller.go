@@ -0,0 +1,136 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// publicationRefreshDelay is the delay between two refreshes of the status
+// of a publication. The replicas use it, too, to check whether they have
+// been promoted
+const publicationRefreshDelay = 30 * time.Second
+
+// PublicationReconciler reconciles the Publication objects of the cluster
+// on the primary instance
+type PublicationReconciler struct {
+	client   client.Client
+	instance *postgres.Instance
+}
+
+// NewPublicationReconciler creates a new PublicationReconciler
+func NewPublicationReconciler(instance *postgres.Instance, client client.Client) *PublicationReconciler {
+	return &PublicationReconciler{
+		client:   client,
+		instance: instance,
+	}
+}
+
+// Reconcile applies the publication specification and reports the
+// status of the logical replication slots used by its subscribers
+func (r *PublicationReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var publication apiv1.Publication
+	if err := r.client.Get(ctx, req.NamespacedName, &publication); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Every instance manager of the namespace receives the publications
+	// of every cluster
+	if publication.Spec.Cluster.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	// The PostgreSQL publication is kept when the Publication object is deleted
+	if !publication.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		return reconcile.Result{RequeueAfter: publicationRefreshDelay}, nil
+	}
+
+	if err := r.instance.ReconcilePublication(ctx, publication.Spec); err != nil {
+		contextLogger.Warning("Cannot apply the publication", "publication", publication.Name, "error", err.Error())
+		return reconcile.Result{RequeueAfter: publicationRefreshDelay}, r.updateStatus(
+			ctx, &publication, publication.Status.ReplicationSlots,
+			metav1.ConditionFalse, apiv1.PublicationReasonReconciliationFailed, err.Error())
+	}
+
+	slots, err := r.instance.GetLogicalReplicationSlots(ctx, publication.Spec.DBName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: publicationRefreshDelay}, r.updateStatus(
+		ctx, &publication, slots,
+		metav1.ConditionTrue, apiv1.PublicationReasonReconciled,
+		"Publication applied on "+r.instance.PodName)
+}
+
+// updateStatus sets the replication slots and the Ready condition of the
+// publication, patching the status only when it changes
+func (r *PublicationReconciler) updateStatus(
+	ctx context.Context,
+	publication *apiv1.Publication,
+	slots []apiv1.LogicalReplicationSlotStatus,
+	status metav1.ConditionStatus,
+	reason, message string,
+) error {
+	existingPublication := publication.DeepCopy()
+	publication.Status.ReplicationSlots = slots
+	publication.Status.Ready = status == metav1.ConditionTrue
+	if publication.Status.Ready {
+		publication.Status.ObservedGeneration = publication.Generation
+	}
+	meta.SetStatusCondition(&publication.Status.Conditions, metav1.Condition{
+		Type:               apiv1.PublicationConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: publication.Generation,
+	})
+	if reflect.DeepEqual(existingPublication.Status, publication.Status) {
+		return nil
+	}
+
+	return r.client.Status().Patch(ctx, publication, client.MergeFrom(existingPublication))
+}
+
+// SetupWithManager setup this controller inside the controller manager
+func (r *PublicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Publication{}).
+		Complete(r)
+}
@@ -60,7 +60,12 @@ func (r *InstanceReconciler) refreshReplicaConfiguration(
 
 func (r *InstanceReconciler) writeReplicaConfigurationForReplica(cluster *apiv1.Cluster) (changed bool, err error) {
 	slotName := cluster.GetSlotNameFromInstanceName(r.instance.PodName)
-	return postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetPrimaryConnInfo(), slotName)
+	primaryConnInfo := r.instance.GetPrimaryConnInfo()
+	if cluster.Spec.PostgresConfiguration.LogicalDecoding.AreFailoverSlotsEnabled() {
+		// The slot synchronization worker needs to connect to a database
+		primaryConnInfo += " dbname=postgres"
+	}
+	return postgres.UpdateReplicaConfiguration(r.instance.PgData, primaryConnInfo, slotName)
 }
 
 func (r *InstanceReconciler) writeReplicaConfigurationForDesignatedPrimary(
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageforecast
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const (
+	// samplingInterval is the interval between two samples of the usage
+	// of the volumes
+	samplingInterval = time.Minute
+
+	// pgDataVolume is the name of the volume containing PGDATA
+	pgDataVolume = "pgdata"
+
+	// walVolume is the name of the volume containing the WAL files, when
+	// they are stored separately
+	walVolume = "wal"
+)
+
+// A Sampler is a runner that periodically samples the usage of the volumes
+// of the instance, updating their forecast and the related metrics
+type Sampler struct {
+	instance *postgres.Instance
+	client   client.Client
+	exporter *metricserver.Exporter
+}
+
+// NewSampler creates a new storage Sampler
+func NewSampler(instance *postgres.Instance, cli client.Client, exporter *metricserver.Exporter) *Sampler {
+	return &Sampler{
+		instance: instance,
+		client:   cli,
+		exporter: exporter,
+	}
+}
+
+// Start starts running the storage Sampler
+func (s *Sampler) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("storage_sampler")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	go func() {
+		ticker := time.NewTicker(samplingInterval)
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated storage Sampler loop")
+		}()
+
+		for {
+			if err := s.tick(ctx); err != nil {
+				contextLog.Warning("sampling the usage of the volumes", "err", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// tick samples the usage of every volume of the instance
+func (s *Sampler) tick(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := s.client.Get(ctx, types.NamespacedName{
+		Namespace: s.instance.Namespace,
+		Name:      s.instance.ClusterName,
+	}, &cluster); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for volume, path := range getVolumePaths(&cluster, s.instance.PgData) {
+		usedBytes, capacityBytes, err := compatibility.GetFilesystemUsage(path)
+		if err != nil {
+			return fmt.Errorf("while getting the usage of the %s volume: %w", volume, err)
+		}
+
+		forecast := s.instance.RecordStorageUsage(volume, usedBytes, capacityBytes, now)
+		s.updateMetrics(forecast)
+	}
+
+	return nil
+}
+
+// updateMetrics exposes the forecast of a volume in the metrics
+func (s *Sampler) updateMetrics(forecast postgresutils.StorageForecast) {
+	metrics := &s.exporter.Metrics.StorageGrowth
+	metrics.UsedBytes.WithLabelValues(forecast.Volume).Set(float64(forecast.UsedBytes))
+	metrics.CapacityBytes.WithLabelValues(forecast.Volume).Set(float64(forecast.CapacityBytes))
+	metrics.GrowthBytesPerSecond.WithLabelValues(forecast.Volume).Set(forecast.GrowthBytesPerSecond)
+	if forecast.DaysUntilFull != nil {
+		metrics.DaysUntilFull.WithLabelValues(forecast.Volume).Set(*forecast.DaysUntilFull)
+	} else {
+		metrics.DaysUntilFull.DeleteLabelValues(forecast.Volume)
+	}
+}
+
+// getVolumePaths returns the paths where the volumes of the instance are
+// mounted, indexed by the name of the volume
+func getVolumePaths(cluster *apiv1.Cluster, pgData string) map[string]string {
+	result := map[string]string{pgDataVolume: pgData}
+	if cluster.ShouldCreateWalArchiveVolume() {
+		result[walVolume] = specs.PgWalVolumePath
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageforecast
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage sampling", func() {
+	It("samples PGDATA", func() {
+		Expect(getVolumePaths(&apiv1.Cluster{}, "/var/lib/postgresql/data/pgdata")).To(Equal(map[string]string{
+			pgDataVolume: "/var/lib/postgresql/data/pgdata",
+		}))
+	})
+
+	It("samples the WAL volume when present", func() {
+		cluster := &apiv1.Cluster{Spec: apiv1.ClusterSpec{WalStorage: &apiv1.StorageConfiguration{}}}
+		Expect(getVolumePaths(cluster, "/var/lib/postgresql/data/pgdata")).To(Equal(map[string]string{
+			pgDataVolume: "/var/lib/postgresql/data/pgdata",
+			walVolume:    specs.PgWalVolumePath,
+		}))
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// subscriptionRefreshDelay is the delay between two refreshes of the status
+// of a subscription. The replicas use it, too, to check whether they have
+// been promoted
+const subscriptionRefreshDelay = 30 * time.Second
+
+// SubscriptionReconciler reconciles the Subscription objects of the cluster
+// on the primary instance
+type SubscriptionReconciler struct {
+	client   client.Client
+	instance *postgres.Instance
+}
+
+// NewSubscriptionReconciler creates a new SubscriptionReconciler
+func NewSubscriptionReconciler(instance *postgres.Instance, client client.Client) *SubscriptionReconciler {
+	return &SubscriptionReconciler{
+		client:   client,
+		instance: instance,
+	}
+}
+
+// Reconcile applies the subscription specification and reports the
+// progress of the subscription
+func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var subscription apiv1.Subscription
+	if err := r.client.Get(ctx, req.NamespacedName, &subscription); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Every instance manager of the namespace receives the subscriptions
+	// of every cluster
+	if subscription.Spec.Cluster.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	// The PostgreSQL subscription is kept when the Subscription object is deleted
+	if !subscription.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		return reconcile.Result{RequeueAfter: subscriptionRefreshDelay}, nil
+	}
+
+	if err := r.applySubscription(ctx, &subscription); err != nil {
+		contextLogger.Warning("Cannot apply the subscription", "subscription", subscription.Name, "error", err.Error())
+		return reconcile.Result{RequeueAfter: subscriptionRefreshDelay}, r.updateStatus(
+			ctx, &subscription, subscription.Status,
+			metav1.ConditionFalse, apiv1.SubscriptionReasonReconciliationFailed, err.Error())
+	}
+
+	progress, err := r.instance.GetSubscriptionStatus(ctx, subscription.Spec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: subscriptionRefreshDelay}, r.updateStatus(
+		ctx, &subscription, progress,
+		metav1.ConditionTrue, apiv1.SubscriptionReasonReconciled,
+		"Subscription applied on "+r.instance.PodName)
+}
+
+// applySubscription creates or alters the subscription, connecting to
+// the external cluster hosting the publication
+func (r *SubscriptionReconciler) applySubscription(ctx context.Context, subscription *apiv1.Subscription) error {
+	var cluster apiv1.Cluster
+	if err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: r.instance.Namespace,
+		Name:      r.instance.ClusterName,
+	}, &cluster); err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", r.instance.ClusterName, err)
+	}
+
+	server, ok := cluster.ExternalCluster(subscription.Spec.ExternalClusterName)
+	if !ok {
+		return fmt.Errorf("unknown external cluster %s", subscription.Spec.ExternalClusterName)
+	}
+
+	connectionString, err := r.getPublisherConnectionString(ctx, server, subscription.Spec.PublicationDBName)
+	if err != nil {
+		return err
+	}
+
+	return r.instance.ReconcileSubscription(ctx, subscription.Spec, connectionString)
+}
+
+// getPublisherConnectionString builds the connection string used by the
+// subscription to reach the publisher. The secrets are dumped into files
+// which are readable by the PostgreSQL server process
+func (r *SubscriptionReconciler) getPublisherConnectionString(
+	ctx context.Context,
+	server apiv1.ExternalCluster,
+	dbname string,
+) (string, error) {
+	if dbname != "" {
+		connectionParameters := make(map[string]string, len(server.ConnectionParameters)+1)
+		for key, value := range server.ConnectionParameters {
+			connectionParameters[key] = value
+		}
+		connectionParameters["dbname"] = dbname
+		server.ConnectionParameters = connectionParameters
+	}
+
+	connectionString, pgpassfile, err := external.ConfigureConnectionToServer(
+		ctx, r.client, r.instance.Namespace, &server)
+	if err != nil {
+		return "", err
+	}
+
+	if pgpassfile != "" {
+		connectionString = fmt.Sprintf("%v passfile=%v",
+			connectionString,
+			pgpassfile)
+	}
+
+	return connectionString, nil
+}
+
+// updateStatus sets the progress and the Ready condition of the
+// subscription, patching the status only when it changes
+func (r *SubscriptionReconciler) updateStatus(
+	ctx context.Context,
+	subscription *apiv1.Subscription,
+	progress apiv1.SubscriptionStatus,
+	status metav1.ConditionStatus,
+	reason, message string,
+) error {
+	existingSubscription := subscription.DeepCopy()
+	subscription.Status.State = progress.State
+	subscription.Status.ReceivedLsn = progress.ReceivedLsn
+	subscription.Status.LatestEndLsn = progress.LatestEndLsn
+	subscription.Status.LatestEndTime = progress.LatestEndTime
+	subscription.Status.Ready = status == metav1.ConditionTrue
+	if subscription.Status.Ready {
+		subscription.Status.ObservedGeneration = subscription.Generation
+	}
+	meta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
+		Type:               apiv1.SubscriptionConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: subscription.Generation,
+	})
+	if reflect.DeepEqual(existingSubscription.Status, subscription.Status) {
+		return nil
+	}
+
+	return r.client.Status().Patch(ctx, subscription, client.MergeFrom(existingSubscription))
+}
+
+// SetupWithManager setup this controller inside the controller manager
+func (r *SubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Subscription{}).
+		Complete(r)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// synchronousCommitTarget identifies the sessions a default
+// synchronous_commit setting applies to. An empty field matches every
+// database or every role
+type synchronousCommitTarget struct {
+	database string
+	role     string
+}
+
+// getSynchronousCommitDefaults reads the default synchronous_commit
+// settings of databases and roles, excluding the ones applying to the
+// whole instance
+func getSynchronousCommitDefaults(ctx context.Context, db *sql.DB) (map[synchronousCommitTarget]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT COALESCE(d.datname, ''), COALESCE(r.rolname, ''), cfg
+		FROM pg_db_role_setting s
+		LEFT JOIN pg_database d ON d.oid = s.setdatabase
+		LEFT JOIN pg_roles r ON r.oid = s.setrole,
+		LATERAL unnest(s.setconfig) AS cfg
+		WHERE cfg LIKE 'synchronous\_commit=%' AND (s.setdatabase <> 0 OR s.setrole <> 0)`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[synchronousCommitTarget]string)
+	for rows.Next() {
+		var target synchronousCommitTarget
+		var setting string
+		if err := rows.Scan(&target.database, &target.role, &setting); err != nil {
+			return nil, err
+		}
+		_, value, _ := strings.Cut(setting, "=")
+		result[target] = value
+	}
+
+	return result, rows.Err()
+}
+
+// buildSynchronousCommitStatements returns the statements setting the
+// desired default synchronous_commit values and resetting the ones that
+// are not desired anymore, in a stable order
+func buildSynchronousCommitStatements(
+	current map[synchronousCommitTarget]string,
+	desired []apiv1.SynchronousCommitDefault,
+) []string {
+	desiredValues := make(map[synchronousCommitTarget]string, len(desired))
+	for _, setting := range desired {
+		desiredValues[synchronousCommitTarget{database: setting.Database, role: setting.Role}] = setting.Value
+	}
+
+	var statements []string
+	for target, value := range desiredValues {
+		if current[target] != value {
+			statements = append(statements,
+				fmt.Sprintf("%s SET synchronous_commit TO %s", target.alterCommand(), pq.QuoteLiteral(value)))
+		}
+	}
+	for target := range current {
+		if _, found := desiredValues[target]; !found {
+			statements = append(statements, fmt.Sprintf("%s RESET synchronous_commit", target.alterCommand()))
+		}
+	}
+
+	sort.Strings(statements)
+	return statements
+}
+
+// alterCommand returns the beginning of the command changing the
+// settings of the target sessions
+func (target synchronousCommitTarget) alterCommand() string {
+	switch {
+	case target.role == "":
+		return fmt.Sprintf("ALTER DATABASE %s", pgx.Identifier{target.database}.Sanitize())
+	case target.database == "":
+		return fmt.Sprintf("ALTER ROLE %s", pgx.Identifier{target.role}.Sanitize())
+	default:
+		return fmt.Sprintf("ALTER ROLE %s IN DATABASE %s",
+			pgx.Identifier{target.role}.Sanitize(),
+			pgx.Identifier{target.database}.Sanitize())
+	}
+}
+
+// reconcileSynchronousCommit applies the default synchronous_commit
+// settings of databases and roles on the primary instance, reporting the
+// outcome in the SynchronousCommit condition. It returns true when the
+// settings need to be applied again
+func (r *InstanceReconciler) reconcileSynchronousCommit(ctx context.Context, cluster *apiv1.Cluster) bool {
+	syncCommit := cluster.Spec.PostgresConfiguration.SynchronousCommit
+	if syncCommit == nil {
+		return false
+	}
+
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || !isPrimary {
+		return false
+	}
+
+	contextLogger := log.FromContext(ctx)
+	err := r.applySynchronousCommit(ctx, syncCommit.Defaults)
+	if err != nil {
+		contextLogger.Warning("Cannot update the synchronous_commit settings", "error", err)
+	}
+
+	if err := conditions.Update(ctx, r.client, cluster, buildSynchronousCommitCondition(err)); err != nil {
+		contextLogger.Warning("Cannot update the synchronous_commit condition", "error", err)
+	}
+
+	return err != nil
+}
+
+// applySynchronousCommit aligns the synchronous_commit settings of
+// databases and roles to the desired ones
+func (r *InstanceReconciler) applySynchronousCommit(
+	ctx context.Context,
+	defaults []apiv1.SynchronousCommitDefault,
+) error {
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	current, err := getSynchronousCommitDefaults(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while reading the synchronous_commit settings: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	var errors []string
+	for _, statement := range buildSynchronousCommitStatements(current, defaults) {
+		contextLogger.Info("Updating the default synchronous_commit setting", "statement", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", statement, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("cannot update the synchronous_commit settings: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// buildSynchronousCommitCondition builds the condition reporting whether
+// the synchronous_commit settings have been applied
+func buildSynchronousCommitCondition(err error) *metav1.Condition {
+	if err != nil {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionSynchronousCommit),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSynchronousCommitFailed),
+			Message: err.Error(),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionSynchronousCommit),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonSynchronousCommitApplied),
+		Message: "The synchronous_commit settings of databases and roles have been applied",
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("synchronous_commit defaults", func() {
+	It("sets the missing and the changed values", func() {
+		current := map[synchronousCommitTarget]string{
+			{database: "app"}: "on",
+		}
+		desired := []apiv1.SynchronousCommitDefault{
+			{Database: "app", Value: "off"},
+			{Role: "reporting", Value: "local"},
+			{Database: "app", Role: "batch", Value: "remote_apply"},
+		}
+		Expect(buildSynchronousCommitStatements(current, desired)).To(Equal([]string{
+			`ALTER DATABASE "app" SET synchronous_commit TO 'off'`,
+			`ALTER ROLE "batch" IN DATABASE "app" SET synchronous_commit TO 'remote_apply'`,
+			`ALTER ROLE "reporting" SET synchronous_commit TO 'local'`,
+		}))
+	})
+
+	It("doesn't change the values that are already correct", func() {
+		current := map[synchronousCommitTarget]string{
+			{database: "app"}: "off",
+		}
+		desired := []apiv1.SynchronousCommitDefault{
+			{Database: "app", Value: "off"},
+		}
+		Expect(buildSynchronousCommitStatements(current, desired)).To(BeEmpty())
+	})
+
+	It("resets the values that are not desired anymore", func() {
+		current := map[synchronousCommitTarget]string{
+			{database: "app"}:                    "off",
+			{database: "app", role: "reporting"}: "local",
+		}
+		Expect(buildSynchronousCommitStatements(current, nil)).To(Equal([]string{
+			`ALTER DATABASE "app" RESET synchronous_commit`,
+			`ALTER ROLE "reporting" IN DATABASE "app" RESET synchronous_commit`,
+		}))
+	})
+
+	It("reports the settings that cannot be applied in the condition", func() {
+		condition := buildSynchronousCommitCondition(errors.New(`role "batch" does not exist`))
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionSynchronousCommit)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("batch"))
+
+		condition = buildSynchronousCommitCondition(nil)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSynchronousCommitApplied)))
+	})
+})
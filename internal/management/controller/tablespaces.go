@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// ensureTablespaceDirectories creates the locations of the tablespaces
+// inside their volumes. They are needed on every instance, as a replica
+// fails to replay the creation of a tablespace without its location
+func ensureTablespaceDirectories(cluster *apiv1.Cluster) error {
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		location := specs.LocationForTablespace(tablespace.Name)
+		if err := os.MkdirAll(location, 0o700); err != nil {
+			return fmt.Errorf("while creating the location of tablespace %s: %w", tablespace.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getTablespaceNames reads the names of the existing tablespaces
+func getTablespaceNames(ctx context.Context, db *sql.DB) (*stringset.Data, error) {
+	rows, err := db.QueryContext(ctx, "SELECT spcname FROM pg_tablespace")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := stringset.New()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result.Put(name)
+	}
+
+	return result, rows.Err()
+}
+
+// buildCreateTablespaceStatements returns the statements creating the
+// tablespaces which don't exist yet
+func buildCreateTablespaceStatements(
+	existing *stringset.Data,
+	tablespaces []apiv1.TablespaceConfiguration,
+) []string {
+	var statements []string
+	for _, tablespace := range tablespaces {
+		if existing.Has(tablespace.Name) {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("CREATE TABLESPACE %s LOCATION %s",
+			pgx.Identifier{tablespace.Name}.Sanitize(),
+			pq.QuoteLiteral(specs.LocationForTablespace(tablespace.Name))))
+	}
+
+	return statements
+}
+
+// reconcileTablespaces creates the tablespaces of the cluster on the
+// primary instance. The tablespaces are never dropped, as they may
+// contain data
+func (r *InstanceReconciler) reconcileTablespaces(ctx context.Context, cluster *apiv1.Cluster) error {
+	if len(cluster.Spec.Tablespaces) == 0 {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	existing, err := getTablespaceNames(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while reading the tablespaces: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	var errors []string
+	for _, statement := range buildCreateTablespaceStatements(existing, cluster.Spec.Tablespaces) {
+		contextLogger.Info("Creating the tablespace", "statement", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", statement, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("cannot create the tablespaces: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
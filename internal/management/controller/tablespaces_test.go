@@ -0,0 +1,44 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tablespaces", func() {
+	tablespaces := []apiv1.TablespaceConfiguration{
+		{Name: "fast"},
+		{Name: "temp", Temporary: true},
+	}
+
+	It("creates the missing tablespaces", func() {
+		Expect(buildCreateTablespaceStatements(stringset.From([]string{"pg_default", "fast"}), tablespaces)).
+			To(Equal([]string{
+				`CREATE TABLESPACE "temp" LOCATION '/var/lib/postgresql/tablespaces/temp/data'`,
+			}))
+	})
+
+	It("doesn't create the existing tablespaces", func() {
+		Expect(buildCreateTablespaceStatements(stringset.From([]string{"fast", "temp"}), tablespaces)).
+			To(BeEmpty())
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logicalimport"
+)
+
+// reconcileStatisticsAfterUpgrade generates the optimizer statistics of a
+// primary instance whose data directory has been upgraded to a new major
+// version, as pg_upgrade doesn't transfer them. The statistics are
+// generated in background, and the request is removed from the data
+// directory only once they have been generated, so that a generation
+// interrupted by a restart is started again. The replicas, cloned from
+// the upgraded primary, receive the statistics through the replication
+func (r *InstanceReconciler) reconcileStatisticsAfterUpgrade(ctx context.Context, cluster *apiv1.Cluster) error {
+	filePath := filepath.Join(r.instance.PgData, postgres.AnalyzeAfterUpgradeFile)
+	requested, err := fileutils.FileExists(filePath)
+	if err != nil || !requested {
+		return err
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !isPrimary {
+		return fileutils.RemoveFile(filePath)
+	}
+
+	if !r.analyzingAfterUpgrade.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		r.analyzingAfterUpgrade.Store(false)
+		return err
+	}
+	databases, errs := r.getAllAccessibleDatabases(ctx, db)
+	if len(errs) > 0 {
+		r.analyzingAfterUpgrade.Store(false)
+		return fmt.Errorf("while listing the databases: %v", errs)
+	}
+
+	contextLogger := log.FromContext(ctx)
+	cluster = cluster.DeepCopy()
+	go func() {
+		defer r.analyzingAfterUpgrade.Store(false)
+
+		contextLogger.Info("Generating the optimizer statistics after the major version upgrade",
+			"databases", databases)
+		if err := logicalimport.Analyze(
+			ctx, r.client, cluster, r.instance.ConnectionPool(), databases, false,
+		); err != nil {
+			contextLogger.Error(err, "Error while generating the optimizer statistics after the upgrade")
+			return
+		}
+
+		if err := fileutils.RemoveFile(filePath); err != nil {
+			contextLogger.Error(err, "Cannot remove the request to generate the optimizer statistics",
+				"file", filePath)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,359 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstreamer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// StreamingDirectory is the directory where pg_receivewal writes
+	// the WAL files it receives
+	StreamingDirectory = postgresutils.ScratchDataDirectory + "/wal-streaming"
+
+	// streamerIdleInterval is the interval between two checks of the cluster
+	// configuration when the WAL streaming is not enabled
+	streamerIdleInterval = 30 * time.Second
+
+	// streamerApplicationName is the application name used by pg_receivewal
+	streamerApplicationName = "cnpg_wal_streamer"
+
+	pgReceiveWalName = "pg_receivewal"
+
+	// partialSuffix is the suffix pg_receivewal uses for the WAL segment
+	// being written
+	partialSuffix = ".partial"
+)
+
+// A Streamer is a runner that, while the local instance is the primary,
+// keeps a pg_receivewal process streaming the WAL and periodically uploads
+// the partial WAL segment into the object store. Completed segments are
+// discarded, as they are archived by the archive_command
+type Streamer struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// receiverCancel stops the running pg_receivewal process, if any
+	receiverCancel context.CancelFunc
+
+	// receiverDone is closed when the pg_receivewal process terminates
+	receiverDone chan struct{}
+
+	// uploadedPartial is the modification time of the partial WAL segment
+	// that has been uploaded the last time, indexed by its name
+	uploadedPartial map[string]time.Time
+}
+
+// NewStreamer creates a new WAL Streamer
+func NewStreamer(instance *postgres.Instance, cli client.Client) *Streamer {
+	return &Streamer{
+		instance:        instance,
+		client:          cli,
+		uploadedPartial: make(map[string]time.Time),
+	}
+}
+
+// Start starts running the WAL Streamer
+func (s *Streamer) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("wal_streamer")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	go func() {
+		interval := streamerIdleInterval
+		ticker := time.NewTicker(interval)
+
+		defer func() {
+			ticker.Stop()
+			s.stopReceiver()
+			contextLog.Info("Terminated WAL Streamer loop")
+		}()
+
+		for {
+			newInterval, err := s.stream(ctx)
+			if err != nil {
+				contextLog.Warning("streaming the WAL into the object store", "err", err)
+			}
+
+			if newInterval != interval {
+				ticker.Reset(newInterval)
+				interval = newInterval
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// stream keeps pg_receivewal running while needed and uploads the partial
+// WAL segment, returning the time to wait before the next upload
+func (s *Streamer) stream(ctx context.Context) (time.Duration, error) {
+	var cluster apiv1.Cluster
+	if err := s.client.Get(ctx, types.NamespacedName{
+		Namespace: s.instance.Namespace,
+		Name:      s.instance.ClusterName,
+	}, &cluster); err != nil {
+		return streamerIdleInterval, err
+	}
+
+	if cluster.Spec.Backup == nil || !cluster.Spec.Backup.BarmanObjectStore.IsWalStreamingEnabled() {
+		s.stopReceiver()
+		return streamerIdleInterval, nil
+	}
+
+	interval := cluster.Spec.Backup.BarmanObjectStore.Wal.Streaming.GetPartialUploadInterval()
+
+	// The WAL is streamed only from the primary of a cluster that is
+	// not a replica, as that's the only instance generating it
+	if cluster.IsReplica() || cluster.Status.CurrentPrimary != s.instance.PodName {
+		s.stopReceiver()
+		return interval, nil
+	}
+
+	isPrimary, err := s.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		s.stopReceiver()
+		return interval, err
+	}
+
+	if err := s.ensureReceiver(ctx); err != nil {
+		return interval, err
+	}
+
+	return interval, s.upload(ctx, &cluster)
+}
+
+// ensureReceiver starts pg_receivewal if it is not running. If the
+// process terminates, it will be restarted by the next iteration
+func (s *Streamer) ensureReceiver(ctx context.Context) error {
+	if s.receiverDone != nil {
+		select {
+		case <-s.receiverDone:
+			s.receiverCancel()
+			s.receiverDone = nil
+		default:
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(StreamingDirectory, 0o700); err != nil {
+		return fmt.Errorf("while creating the WAL streaming directory: %w", err)
+	}
+
+	receiverCtx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(receiverCtx, pgReceiveWalName, // #nosec G204
+		buildReceiverOptions(postgres.BuildLocalReplicationConnInfo(streamerApplicationName))...)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := execlog.RunStreaming(cmd, pgReceiveWalName); err != nil && receiverCtx.Err() == nil {
+			log.FromContext(ctx).Warning("pg_receivewal terminated, it will be restarted", "err", err)
+		}
+	}()
+
+	s.receiverCancel = cancel
+	s.receiverDone = done
+	log.FromContext(ctx).Info("Started streaming the WAL into the object store")
+	return nil
+}
+
+// stopReceiver stops the running pg_receivewal process, if any
+func (s *Streamer) stopReceiver() {
+	if s.receiverDone == nil {
+		return
+	}
+
+	s.receiverCancel()
+	<-s.receiverDone
+	s.receiverCancel = nil
+	s.receiverDone = nil
+}
+
+// upload uploads the partial WAL segment into the object store, if
+// it changed since the last upload, and removes the completed segments
+func (s *Streamer) upload(ctx context.Context, cluster *apiv1.Cluster) error {
+	partials, completed, err := scanStreamingDirectory(StreamingDirectory)
+	if err != nil {
+		return err
+	}
+
+	// The completed WAL segments are archived by the archive_command
+	for _, name := range completed {
+		if err := os.Remove(filepath.Join(StreamingDirectory, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("while removing the completed WAL segment %s: %w", name, err)
+		}
+	}
+
+	// The partial WAL segments will be uploaded when the WAL archiving
+	// is resumed
+	if len(partials) == 0 || utils.IsWalArchivingPaused(&cluster.ObjectMeta) {
+		return nil
+	}
+
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return fmt.Errorf("while getting the environment for the WAL archive: %w", err)
+	}
+
+	options, err := walarchive.BarmanCloudWalArchiveOptions(cluster, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("while getting barman-cloud-wal-archive options: %w", err)
+	}
+
+	keyring, err := encryption.FromEnv(env)
+	if err != nil {
+		return fmt.Errorf("while loading the WAL encryption keys: %w", err)
+	}
+
+	stalePartials := getStalePartials(partials)
+	uploaded := make(map[string]time.Time, len(partials))
+	for _, name := range partials {
+		walPath := filepath.Join(StreamingDirectory, name)
+		info, err := os.Stat(walPath)
+		if errors.Is(err, os.ErrNotExist) {
+			// The segment has just been completed
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if lastUpload, ok := s.uploadedPartial[name]; ok && !info.ModTime().After(lastUpload) {
+			uploaded[name] = lastUpload
+			continue
+		}
+
+		if err := uploadPartial(walPath, options, env, keyring); err != nil {
+			return fmt.Errorf("while uploading the partial WAL segment %s: %w", name, err)
+		}
+
+		uploaded[name] = info.ModTime()
+		log.FromContext(ctx).Debug("Uploaded partial WAL segment", "walName", name)
+	}
+	s.uploadedPartial = uploaded
+
+	// The stale partial segments have been uploaded in their final version
+	// and are not written anymore
+	for _, name := range stalePartials {
+		if err := os.Remove(filepath.Join(StreamingDirectory, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("while removing the stale partial WAL segment %s: %w", name, err)
+		}
+		delete(s.uploadedPartial, name)
+	}
+
+	return nil
+}
+
+// getStalePartials gets the partial WAL segments which are not being
+// written by pg_receivewal anymore, such as the ones left behind by a
+// timeline switch. Only the latest partial segment is being written
+func getStalePartials(partials []string) []string {
+	if len(partials) < 2 {
+		return nil
+	}
+
+	sorted := append([]string{}, partials...)
+	sort.Strings(sorted)
+	return sorted[:len(sorted)-1]
+}
+
+// uploadPartial uploads a partial WAL segment with barman-cloud-wal-archive,
+// encrypting it first when the client-side encryption is enabled
+func uploadPartial(walPath string, options []string, env []string, keyring *encryption.Keyring) error {
+	if keyring != nil {
+		encryptedPath, err := keyring.EncryptToScratchDirectory(walPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.Remove(encryptedPath)
+		}()
+		walPath = encryptedPath
+	}
+
+	cmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, // #nosec G204
+		append(options, walPath)...)
+	cmd.Env = env
+	return execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudWalArchive)
+}
+
+// buildReceiverOptions creates the options of pg_receivewal. Every WAL
+// record is flushed as soon as it is received, and the process
+// terminates on connection errors to be restarted by the Streamer
+func buildReceiverOptions(connInfo string) []string {
+	return []string{
+		"--directory", StreamingDirectory,
+		"--dbname", connInfo,
+		"--synchronous",
+		"--no-loop",
+		"--no-password",
+	}
+}
+
+// scanStreamingDirectory returns the partial and the completed WAL segments
+// contained in the passed directory
+func scanStreamingDirectory(directory string) (partials []string, completed []string, err error) {
+	entries, err := os.ReadDir(directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("while reading the WAL streaming directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			continue
+		case strings.HasSuffix(name, partialSuffix) &&
+			postgresutils.IsWALFile(strings.TrimSuffix(name, partialSuffix)):
+			partials = append(partials, name)
+		case postgresutils.IsWALFile(name):
+			completed = append(completed, name)
+		}
+	}
+
+	return partials, completed, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstreamer
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL streaming directory", func() {
+	It("separates the partial segment from the completed ones", func() {
+		directory := GinkgoT().TempDir()
+		for _, name := range []string{
+			"000000010000000000000003",
+			"000000010000000000000004.partial",
+			"00000002.history",
+			"000000010000000000000004.partial.tmp",
+		} {
+			Expect(os.WriteFile(filepath.Join(directory, name), nil, 0o600)).To(Succeed())
+		}
+
+		partials, completed, err := scanStreamingDirectory(directory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(partials).To(ConsistOf("000000010000000000000004.partial"))
+		Expect(completed).To(ConsistOf("000000010000000000000003"))
+	})
+
+	It("is empty when the directory has not been created yet", func() {
+		partials, completed, err := scanStreamingDirectory(filepath.Join(GinkgoT().TempDir(), "missing"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(partials).To(BeEmpty())
+		Expect(completed).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pg_receivewal options", func() {
+	It("considers stale every partial segment but the latest one", func() {
+		Expect(getStalePartials([]string{"000000010000000000000003.partial"})).To(BeEmpty())
+		Expect(getStalePartials([]string{
+			"000000020000000000000004.partial",
+			"000000010000000000000003.partial",
+		})).To(Equal([]string{"000000010000000000000003.partial"}))
+	})
+
+	It("flushes the WAL as soon as it is received and does not loop", func() {
+		options := buildReceiverOptions("host=localhost")
+		Expect(options).To(ContainElements("--synchronous", "--no-loop", "--no-password"))
+		Expect(options).To(ContainElements("--directory", StreamingDirectory))
+		Expect(options).To(ContainElements("--dbname", "host=localhost"))
+	})
+})
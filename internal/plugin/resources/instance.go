@@ -159,6 +159,16 @@ func GetInstancePVCs(
 		pvcs = append(pvcs, *pgWal)
 	}
 
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		pgTablespace, err := getPVC(ctx, specs.GetTablespacePVCName(instanceName, tablespace.Name))
+		if err != nil {
+			return nil, err
+		}
+		if pgTablespace != nil {
+			pvcs = append(pvcs, *pgTablespace)
+		}
+	}
+
 	return pvcs, nil
 }
 
@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"reflect"
 	"strings"
 	"time"
 
@@ -187,7 +188,16 @@ func (pair KeyPair) createAndSignPairWithValidity(
 		Subject: pkix.Name{
 			CommonName: host,
 		},
-		DNSNames: altDNSNames,
+	}
+
+	// IPv4 and IPv6 addresses are valid subject alternative names only
+	// when stored as IP addresses
+	for _, name := range altDNSNames {
+		if ip := net.ParseIP(name); ip != nil {
+			leafTemplate.IPAddresses = append(leafTemplate.IPAddresses, ip)
+		} else {
+			leafTemplate.DNSNames = append(leafTemplate.DNSNames, name)
+		}
 	}
 
 	leafTemplate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement
@@ -321,6 +331,40 @@ func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
 	return false, &cert.NotAfter, nil
 }
 
+// HasSubjectAltNames checks whether the subject alternative names of the
+// certificate, both DNS names and IP addresses, are exactly the ones that
+// CreateAndSignPair would set given the passed parameters, regardless
+// of their order
+func (pair *KeyPair) HasSubjectAltNames(host string, usage CertType, altDNSNames []string) (bool, error) {
+	cert, err := pair.ParseCertificate()
+	if err != nil {
+		return false, err
+	}
+
+	expectedNames := altDNSNames
+	if usage == CertTypeServer {
+		expectedNames = append(strings.Split(host, ","), altDNSNames...)
+	}
+
+	expected := make(map[string]bool, len(expectedNames))
+	for _, name := range expectedNames {
+		if ip := net.ParseIP(name); ip != nil {
+			name = ip.String()
+		}
+		expected[name] = true
+	}
+
+	current := make(map[string]bool, len(cert.DNSNames)+len(cert.IPAddresses))
+	for _, name := range cert.DNSNames {
+		current[name] = true
+	}
+	for _, ip := range cert.IPAddresses {
+		current[ip.String()] = true
+	}
+
+	return reflect.DeepEqual(expected, current), nil
+}
+
 // CreateDerivedCA create a new CA derived from the certificate in the
 // keypair
 func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit string) (*KeyPair, error) {
@@ -136,6 +136,46 @@ var _ = Describe("Keypair generation", func() {
 			Expect(cert.CheckSignatureFrom(caCert)).To(BeNil())
 		})
 
+		It("should store the IPv4 and IPv6 alternative names as IP addresses", func() {
+			rootCA, err := CreateRootCA("test", "namespace")
+			Expect(err).To(BeNil())
+
+			pair, err := rootCA.CreateAndSignPair("this.host.name.com", CertTypeServer,
+				[]string{"cluster-rw.default.svc", "10.0.0.1", "fd00::1"})
+			Expect(err).To(BeNil())
+
+			cert, err := pair.ParseCertificate()
+			Expect(err).To(BeNil())
+
+			Expect(cert.DNSNames).To(ConsistOf("cluster-rw.default.svc", "this.host.name.com"))
+			Expect(cert.IPAddresses).To(HaveLen(2))
+			Expect(cert.VerifyHostname("10.0.0.1")).To(BeNil())
+			Expect(cert.VerifyHostname("fd00::1")).To(BeNil())
+		})
+
+		It("should detect a change of the alternative names", func() {
+			rootCA, err := CreateRootCA("test", "namespace")
+			Expect(err).To(BeNil())
+
+			altDNSNames := []string{"cluster-rw.default.svc", "10.0.0.1"}
+			pair, err := rootCA.CreateAndSignPair("this.host.name.com", CertTypeServer, altDNSNames)
+			Expect(err).To(BeNil())
+
+			matching, err := pair.HasSubjectAltNames("this.host.name.com", CertTypeServer,
+				[]string{"10.0.0.1", "cluster-rw.default.svc"})
+			Expect(err).To(BeNil())
+			Expect(matching).To(BeTrue())
+
+			matching, err = pair.HasSubjectAltNames("this.host.name.com", CertTypeServer,
+				append(altDNSNames, "pooler-rw.default.svc"))
+			Expect(err).To(BeNil())
+			Expect(matching).To(BeFalse())
+
+			matching, err = pair.HasSubjectAltNames("this.host.name.com", CertTypeServer, altDNSNames[:1])
+			Expect(err).To(BeNil())
+			Expect(matching).To(BeFalse())
+		})
+
 		It("should create a CA K8s corev1/secret resource structure", func() {
 			rootCA, err := CreateRootCA("test", "namespace")
 			Expect(err).To(BeNil())
@@ -133,3 +133,62 @@ func RemoveOptionFromConfigurationContents(content string, option string) string
 
 	return strings.Join(resultContent, "\n") + "\n"
 }
+
+// ParseConfigurationContents reads the options set in a configuration file
+// whose content is passed. When an option is set more than once, the last
+// value wins, as PostgreSQL does
+func ParseConfigurationContents(content string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	for idx, line := range splitLines(content) {
+		// Skip empty lines and comments
+		trimLine := strings.TrimSpace(line)
+		if len(trimLine) == 0 || trimLine[0] == '#' {
+			continue
+		}
+
+		// The equal sign between the name and the value is optional
+		keyEnd := strings.IndexAny(trimLine, " \t=")
+		if keyEnd < 0 {
+			return nil, fmt.Errorf("missing value for option %q at line %d", trimLine, idx+1)
+		}
+		key := trimLine[:keyEnd]
+		rawValue := strings.TrimSpace(trimLine[keyEnd:])
+		rawValue = strings.TrimSpace(strings.TrimPrefix(rawValue, "="))
+
+		value, err := parseConfigurationValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for option %q at line %d: %w", key, idx+1, err)
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// parseConfigurationValue parses the value of an option, which can be
+// quoted and followed by a comment
+func parseConfigurationValue(rawValue string) (string, error) {
+	if !strings.HasPrefix(rawValue, "'") {
+		value, _, _ := strings.Cut(rawValue, "#")
+		return strings.TrimSpace(value), nil
+	}
+
+	var value strings.Builder
+	for idx := 1; idx < len(rawValue); idx++ {
+		switch {
+		case rawValue[idx] == '\\' && idx+1 < len(rawValue):
+			idx++
+			value.WriteByte(rawValue[idx])
+		case rawValue[idx] == '\'' && idx+1 < len(rawValue) && rawValue[idx+1] == '\'':
+			idx++
+			value.WriteByte('\'')
+		case rawValue[idx] == '\'':
+			return value.String(), nil
+		default:
+			value.WriteByte(rawValue[idx])
+		}
+	}
+
+	return "", fmt.Errorf("unterminated quoted string")
+}
@@ -199,3 +199,35 @@ var _ = Describe("Remove configuration files option", func() {
 		Expect(updatedContent).To(Equal(wantedContent))
 	})
 })
+
+var _ = Describe("Parse configuration files", func() {
+	It("reads quoted and unquoted values, skipping the comments", func() {
+		content := "# A comment\n" +
+			"standby_mode = on\n" +
+			"primary_conninfo = 'host=someHost user=someUser' # the primary\n" +
+			"restore_command='cp /archive/%f \"%p\"'\n" +
+			"recovery_target_name 'it''s a \\'name\\''\n"
+
+		Expect(ParseConfigurationContents(content)).To(Equal(map[string]string{
+			"standby_mode":         "on",
+			"primary_conninfo":     "host=someHost user=someUser",
+			"restore_command":      "cp /archive/%f \"%p\"",
+			"recovery_target_name": "it's a 'name'",
+		}))
+	})
+
+	It("keeps the last value of an option set more than once", func() {
+		Expect(ParseConfigurationContents("trigger_file = 'a'\ntrigger_file = 'b'\n")).To(
+			HaveKeyWithValue("trigger_file", "b"))
+	})
+
+	It("fails with an unterminated quoted string", func() {
+		_, err := ParseConfigurationContents("primary_conninfo = 'host=someHost\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails with an option without a value", func() {
+		_, err := ParseConfigurationContents("standby_mode\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
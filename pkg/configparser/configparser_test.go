@@ -38,6 +38,9 @@ type FakeData struct {
 
 	// EnablePodDebugging enable debugging mode in new generated pods
 	EnablePodDebugging bool `json:"enablePodDebugging" env:"POD_DEBUG"`
+
+	// MaxConcurrency is an example of an integer configuration parameter
+	MaxConcurrency int `json:"maxConcurrency" env:"MAX_CONCURRENCY"`
 }
 
 var defaultInheritedAnnotations = []string{
@@ -90,6 +93,22 @@ var _ = Describe("Data test suite", func() {
 		Expect(config.InheritedAnnotations).To(Equal(defaultInheritedAnnotations))
 		Expect(config.InheritedLabels).To(BeNil())
 	})
+
+	It("loads integer values", func() {
+		config := &FakeData{}
+		config.readConfigMap(map[string]string{
+			"MAX_CONCURRENCY": "5",
+		}, NewFakeEnvironment(nil))
+		Expect(config.MaxConcurrency).To(Equal(5))
+	})
+
+	It("keeps the default value when an integer is not valid", func() {
+		config := &FakeData{}
+		config.readConfigMap(map[string]string{
+			"MAX_CONCURRENCY": "many",
+		}, NewFakeEnvironment(nil))
+		Expect(config.MaxConcurrency).To(BeZero())
+	})
 })
 
 // FakeEnvironment is an EnvironmentSource that fetches data from an internal map
@@ -33,3 +33,17 @@ func CreateFifo(fileName string) error {
 	}
 	return nil
 }
+
+// GetFilesystemUsage gets the used bytes and the capacity of the filesystem
+// containing the passed path. The capacity is the space usable by an
+// unprivileged process, which excludes the blocks reserved to root
+func GetFilesystemUsage(path string) (usedBytes uint64, capacityBytes uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	usedBytes = (stat.Blocks - stat.Bfree) * blockSize
+	return usedBytes, usedBytes + stat.Bavail*blockSize, nil
+}
@@ -26,3 +26,8 @@ import "fmt"
 func CreateFifo(fileName string) error {
 	panic(fmt.Sprintf("function CreateFifo() should not be used in Windows"))
 }
+
+// GetFilesystemUsage fakes function for cross-compiling compatibility
+func GetFilesystemUsage(path string) (uint64, uint64, error) {
+	panic(fmt.Sprintf("function GetFilesystemUsage() should not be used in Windows"))
+}
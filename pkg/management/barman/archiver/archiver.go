@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -31,6 +32,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -55,6 +57,10 @@ type WALArchiver struct {
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
 
+	// The keys used to encrypt the WAL files before uploading them, nil
+	// when the client-side encryption is not enabled
+	keyring *encryption.Keyring
+
 	pgDataDirectory string
 }
 
@@ -89,10 +95,16 @@ func New(
 		return nil, fmt.Errorf("while creating spool directory: %w", err)
 	}
 
+	keyring, err := encryption.FromEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("while loading the WAL encryption keys: %w", err)
+	}
+
 	archiver = &WALArchiver{
 		cluster:         cluster,
 		spool:           walArchiveSpool,
 		env:             env,
+		keyring:         keyring,
 		pgDataDirectory: pgDataDirectory,
 	}
 	return archiver, nil
@@ -169,7 +181,19 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	}
 	options := make([]string, optionsLength, optionsLength+1)
 	copy(options, baseOptions)
-	options = append(options, walName)
+
+	walPath := walName
+	if archiver.keyring != nil {
+		encryptedPath, err := archiver.keyring.EncryptToScratchDirectory(walName)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.Remove(encryptedPath)
+		}()
+		walPath = encryptedPath
+	}
+	options = append(options, walPath)
 
 	log.Trace("Executing "+barmanCapabilities.BarmanCloudWalArchive,
 		"walName", walName,
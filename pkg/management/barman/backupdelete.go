@@ -126,7 +126,10 @@ func DeleteBackupsNotInCatalog(
 
 	var errors []error
 	for id, backup := range backups.Items {
+		// The backups taken with the volumeSnapshot method are not
+		// part of the barman catalog
 		if backup.Spec.Cluster.Name != cluster.GetName() ||
+			backup.IsVolumeSnapshot() ||
 			backup.Status.Phase != v1.BackupPhaseCompleted ||
 			!useSameBackupLocation(&backup.Status, cluster) {
 			continue
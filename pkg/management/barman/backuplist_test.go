@@ -86,6 +86,9 @@ var _ = Describe("barman-cloud-backup-list parsing", func() {
 		Expect(result.List[0].SystemID).To(Equal("6885668674852188181"))
 		Expect(result.List[0].BeginTimeString).To(Equal("Tue Oct 20 11:52:31 2020"))
 		Expect(result.List[0].EndTimeString).To(Equal("Tue Oct 20 11:52:34 2020"))
+		Expect(result.List[0].Size).To(BeNil())
+		Expect(result.List[0].DeduplicatedSize).To(BeNil())
+		Expect(result.List[0].CopyStats.TotalTime).To(BeNumerically("~", 4.285494))
 	})
 
 	It("must extract the latest backup id", func() {
@@ -0,0 +1,240 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bucket creates the bucket, or the container, of an object store
+// when it doesn't exist yet
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// azureBlobStorageDomain is the domain of the Azure Blob Storage
+// endpoints. Any other domain is considered an emulated storage
+const azureBlobStorageDomain = ".blob.core.windows.net"
+
+// createBucketScript creates the bucket, or the container, passed as
+// argument when it doesn't exist, using the same credentials used by
+// barman-cloud. The lifecycle rules are only applied to the buckets
+// created by the script, to not overwrite the ones chosen by the user.
+// The noncurrent objects only exist in the buckets having the versioning
+// enabled, which is why it is enabled together with the expiration rules
+const createBucketScript = `
+import argparse
+import os
+import sys
+
+
+def create_s3_bucket(config):
+    import boto3
+    from botocore.exceptions import ClientError
+
+    s3 = boto3.client("s3", endpoint_url=config.endpoint_url or None)
+    try:
+        s3.head_bucket(Bucket=config.bucket_name)
+        return
+    except ClientError as exc:
+        if exc.response["Error"]["Code"] not in ("404", "NoSuchBucket"):
+            raise
+
+    region = s3.meta.region_name
+    if region and region != "us-east-1":
+        s3.create_bucket(Bucket=config.bucket_name,
+                         CreateBucketConfiguration={"LocationConstraint": region})
+    else:
+        s3.create_bucket(Bucket=config.bucket_name)
+    print("Created bucket %s" % config.bucket_name)
+
+    rules = [{
+        "ID": "cnpg-abort-incomplete-uploads",
+        "Status": "Enabled",
+        "Filter": {"Prefix": ""},
+        "AbortIncompleteMultipartUpload": {"DaysAfterInitiation": 1},
+    }]
+    if config.retention_days > 0:
+        s3.put_bucket_versioning(Bucket=config.bucket_name,
+                                 VersioningConfiguration={"Status": "Enabled"})
+        rules.append({
+            "ID": "cnpg-retention-policy",
+            "Status": "Enabled",
+            "Filter": {"Prefix": ""},
+            "NoncurrentVersionExpiration": {"NoncurrentDays": config.retention_days},
+            "Expiration": {"ExpiredObjectDeleteMarker": True},
+        })
+    s3.put_bucket_lifecycle_configuration(Bucket=config.bucket_name,
+                                          LifecycleConfiguration={"Rules": rules})
+
+
+def create_gcs_bucket(config):
+    from google.cloud import storage
+
+    client = storage.Client()
+    if client.lookup_bucket(config.bucket_name) is not None:
+        return
+
+    bucket = storage.Bucket(client, name=config.bucket_name)
+    if config.retention_days > 0:
+        bucket.versioning_enabled = True
+        bucket.add_lifecycle_delete_rule(days_since_noncurrent_time=config.retention_days)
+    client.create_bucket(bucket)
+    print("Created bucket %s" % config.bucket_name)
+
+
+def create_azure_container(config):
+    from azure.storage.blob import BlobServiceClient
+
+    if os.environ.get("AZURE_STORAGE_CONNECTION_STRING"):
+        service = BlobServiceClient.from_connection_string(os.environ["AZURE_STORAGE_CONNECTION_STRING"])
+    else:
+        credential = os.environ.get("AZURE_STORAGE_SAS_TOKEN") or os.environ.get("AZURE_STORAGE_KEY")
+        if not credential:
+            from azure.identity import ManagedIdentityCredential
+            credential = ManagedIdentityCredential()
+        service = BlobServiceClient(account_url=config.endpoint_url, credential=credential)
+
+    container = service.get_container_client(config.bucket_name)
+    if not container.exists():
+        container.create_container()
+        print("Created container %s" % config.bucket_name)
+
+
+parser = argparse.ArgumentParser(
+    description="Create the bucket of an object store used by barman-cloud if it doesn't exist")
+parser.add_argument("--cloud-provider", required=True,
+                    choices=["aws-s3", "azure-blob-storage", "google-cloud-storage"])
+parser.add_argument("--endpoint-url", help="the endpoint of S3, or the account URL of Azure")
+parser.add_argument("--retention-days", type=int, default=0,
+                    help="the days after which the noncurrent objects expire")
+parser.add_argument("bucket_name")
+config = parser.parse_args()
+
+if config.cloud_provider == "aws-s3":
+    create_s3_bucket(config)
+elif config.cloud_provider == "google-cloud-storage":
+    create_gcs_bucket(config)
+else:
+    create_azure_container(config)
+`
+
+// Ensure creates the bucket, or the container, of the passed object store
+// when it doesn't exist, applying the lifecycle rules matching the
+// retention policy. The environment must contain the credentials used
+// by barman-cloud
+func Ensure(
+	ctx context.Context,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	retentionPolicy string,
+	env []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	args, err := buildArgs(configuration, retentionPolicy)
+	if err != nil {
+		return err
+	}
+	bucketName := args[len(args)-1]
+
+	contextLogger.Info("Creating the bucket of the object store if it doesn't exist",
+		"bucket", bucketName)
+
+	cmd, err := barman.NewPythonCommand(barmanCapabilities.BarmanCloudWalArchive, createBucketScript, args...)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+	if err := execlog.RunStreaming(cmd, "create-bucket"); err != nil {
+		return fmt.Errorf("while creating the bucket %s: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+// buildArgs returns the arguments of the script creating the bucket
+func buildArgs(configuration *apiv1.BarmanObjectStoreConfiguration, retentionPolicy string) ([]string, error) {
+	days := 0
+	if retentionPolicy != "" {
+		var err error
+		if days, err = utils.GetRecoveryWindowDays(retentionPolicy); err != nil {
+			return nil, fmt.Errorf("while parsing the retention policy %q: %w", retentionPolicy, err)
+		}
+	}
+
+	destination, err := url.Parse(configuration.DestinationPath)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the destination path: %w", err)
+	}
+
+	var provider, bucket, endpoint string
+	switch {
+	case configuration.AWS != nil:
+		provider = "aws-s3"
+		bucket = destination.Host
+		endpoint = configuration.EndpointURL
+	case configuration.Google != nil:
+		provider = "google-cloud-storage"
+		bucket = destination.Host
+	case configuration.Azure != nil:
+		provider = "azure-blob-storage"
+		bucket, endpoint = getAzureContainer(destination)
+	default:
+		return nil, fmt.Errorf("no credentials defined for the object store")
+	}
+
+	if bucket == "" {
+		return nil, fmt.Errorf("cannot find the bucket name in the destination path %q",
+			configuration.DestinationPath)
+	}
+
+	args := []string{"--cloud-provider", provider}
+	if endpoint != "" {
+		args = append(args, "--endpoint-url", endpoint)
+	}
+	if days > 0 {
+		args = append(args, "--retention-days", strconv.Itoa(days))
+	}
+	return append(args, bucket), nil
+}
+
+// getAzureContainer returns the container name and the account URL of
+// an Azure destination path, that is in the form
+// `<scheme>://<account>.blob.core.windows.net/<container>/<path>`, or
+// `<scheme>://<host>:<port>/<account>/<container>/<path>` when an
+// emulated storage is used
+func getAzureContainer(destination *url.URL) (string, string) {
+	segments := strings.Split(strings.TrimPrefix(destination.Path, "/"), "/")
+	accountURL := destination.Scheme + "://" + destination.Host
+
+	if !strings.HasSuffix(destination.Hostname(), azureBlobStorageDomain) {
+		if len(segments) < 2 {
+			return "", ""
+		}
+		accountURL += "/" + segments[0]
+		segments = segments[1:]
+	}
+
+	return segments[0], accountURL
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("building the arguments of the bucket creation", func() {
+	It("uses the host of the destination path with S3", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}},
+			DestinationPath:   "s3://backups/cluster-example",
+			EndpointURL:       "https://minio:9000",
+		}
+		Expect(buildArgs(configuration, "2w")).To(Equal(
+			[]string{"--cloud-provider", "aws-s3", "--endpoint-url", "https://minio:9000", "--retention-days", "14", "backups"}))
+	})
+
+	It("doesn't set an age for the redundancy policies", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{Google: &apiv1.GoogleCredentials{}},
+			DestinationPath:   "gs://backups/cluster-example",
+		}
+		Expect(buildArgs(configuration, "5b")).To(Equal(
+			[]string{"--cloud-provider", "google-cloud-storage", "backups"}))
+		Expect(buildArgs(configuration, "")).To(Equal(
+			[]string{"--cloud-provider", "google-cloud-storage", "backups"}))
+	})
+
+	It("finds the container and the account URL with Azure", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{Azure: &apiv1.AzureCredentials{}},
+			DestinationPath:   "https://account.blob.core.windows.net/backups/cluster-example",
+		}
+		Expect(buildArgs(configuration, "7d")).To(Equal(
+			[]string{
+				"--cloud-provider", "azure-blob-storage",
+				"--endpoint-url", "https://account.blob.core.windows.net",
+				"--retention-days", "7",
+				"backups",
+			}))
+	})
+
+	It("finds the container and the account URL with an emulated Azure storage", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{Azure: &apiv1.AzureCredentials{}},
+			DestinationPath:   "http://azurite:10000/storageaccount/backups/cluster-example",
+		}
+		Expect(buildArgs(configuration, "")).To(Equal(
+			[]string{
+				"--cloud-provider", "azure-blob-storage",
+				"--endpoint-url", "http://azurite:10000/storageaccount",
+				"backups",
+			}))
+	})
+
+	It("complains when the bucket name is missing", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{Azure: &apiv1.AzureCredentials{}},
+			DestinationPath:   "http://azurite:10000/storageaccount",
+		}
+		_, err := buildArgs(configuration, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("complains about a wrong retention policy", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}},
+			DestinationPath:   "s3://backups/cluster-example",
+		}
+		_, err := buildArgs(configuration, "7x")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,194 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeModules are the Python modules replacing the SDKs of the cloud
+// providers, recording the calls made by the script
+var fakeModules = map[string]string{
+	"fake_calls.py": `
+import json
+import os
+
+
+def record(method, **kwargs):
+    with open(os.environ["FAKE_CALLS"], "a") as calls:
+        calls.write(json.dumps({"method": method, "args": kwargs}) + "\n")
+`,
+	"boto3/__init__.py": `
+import os
+
+import fake_calls
+from botocore.exceptions import ClientError
+
+
+class _Meta:
+    region_name = "eu-west-1"
+
+
+class _Client:
+    meta = _Meta()
+
+    def __getattr__(self, method):
+        def call(**kwargs):
+            fake_calls.record(method, **kwargs)
+            if method == "head_bucket" and not os.environ.get("FAKE_EXISTS"):
+                raise ClientError({"Error": {"Code": "404"}})
+        return call
+
+
+def client(service, endpoint_url=None):
+    return _Client()
+`,
+	"botocore/__init__.py": "",
+	"botocore/exceptions.py": `
+class ClientError(Exception):
+    def __init__(self, response):
+        self.response = response
+`,
+	"google/__init__.py":       "",
+	"google/cloud/__init__.py": "",
+	"google/cloud/storage.py": `
+import os
+
+import fake_calls
+
+
+class Bucket:
+    def __init__(self, client, name):
+        self.name = name
+        self.versioning_enabled = False
+        self.rules = []
+
+    def add_lifecycle_delete_rule(self, **kwargs):
+        self.rules.append(kwargs)
+
+
+class Client:
+    def lookup_bucket(self, name):
+        fake_calls.record("lookup_bucket", name=name)
+        return Bucket(self, name) if os.environ.get("FAKE_EXISTS") else None
+
+    def create_bucket(self, bucket):
+        fake_calls.record("create_bucket", name=bucket.name,
+                          versioning=bucket.versioning_enabled, rules=bucket.rules)
+`,
+}
+
+type fakeCall struct {
+	Method string                 `json:"method"`
+	Args   map[string]interface{} `json:"args"`
+}
+
+var _ = Describe("the script creating the bucket", func() {
+	var modulesDir string
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("python3"); err != nil {
+			Skip("python3 is not available")
+		}
+
+		modulesDir = GinkgoT().TempDir()
+		for name, content := range fakeModules {
+			fileName := filepath.Join(modulesDir, name)
+			Expect(os.MkdirAll(filepath.Dir(fileName), 0o700)).To(Succeed())
+			Expect(os.WriteFile(fileName, []byte(content), 0o600)).To(Succeed())
+		}
+	})
+
+	runScript := func(exists bool, args ...string) []fakeCall {
+		callsFile := filepath.Join(modulesDir, "calls.json")
+		cmd := exec.Command("python3", append([]string{"-c", createBucketScript}, args...)...) // #nosec G204
+		cmd.Env = append(os.Environ(), "PYTHONPATH="+modulesDir, "FAKE_CALLS="+callsFile)
+		if exists {
+			cmd.Env = append(cmd.Env, "FAKE_EXISTS=1")
+		}
+		output, err := cmd.CombinedOutput()
+		Expect(err).ToNot(HaveOccurred(), string(output))
+
+		content, err := os.ReadFile(callsFile) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		var calls []fakeCall
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			var call fakeCall
+			Expect(json.Unmarshal([]byte(line), &call)).To(Succeed())
+			calls = append(calls, call)
+		}
+		return calls
+	}
+
+	methods := func(calls []fakeCall) []string {
+		result := make([]string, len(calls))
+		for i, call := range calls {
+			result[i] = call.Method
+		}
+		return result
+	}
+
+	It("enables the versioning of a new S3 bucket expiring the noncurrent objects", func() {
+		calls := runScript(false, "--cloud-provider", "aws-s3", "--retention-days", "14", "backups")
+		Expect(methods(calls)).To(Equal([]string{
+			"head_bucket", "create_bucket", "put_bucket_versioning", "put_bucket_lifecycle_configuration",
+		}))
+		Expect(calls[2].Args).To(HaveKeyWithValue("VersioningConfiguration",
+			map[string]interface{}{"Status": "Enabled"}))
+
+		rules := calls[3].Args["LifecycleConfiguration"].(map[string]interface{})["Rules"].([]interface{})
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[1]).To(HaveKeyWithValue("NoncurrentVersionExpiration",
+			map[string]interface{}{"NoncurrentDays": float64(14)}))
+		Expect(rules[1]).To(HaveKeyWithValue("Expiration",
+			map[string]interface{}{"ExpiredObjectDeleteMarker": true}))
+	})
+
+	It("only aborts the incomplete uploads without a recovery window", func() {
+		calls := runScript(false, "--cloud-provider", "aws-s3", "backups")
+		Expect(methods(calls)).To(Equal([]string{
+			"head_bucket", "create_bucket", "put_bucket_lifecycle_configuration",
+		}))
+		rules := calls[2].Args["LifecycleConfiguration"].(map[string]interface{})["Rules"].([]interface{})
+		Expect(rules).To(HaveLen(1))
+	})
+
+	It("leaves an existing S3 bucket untouched", func() {
+		calls := runScript(true, "--cloud-provider", "aws-s3", "--retention-days", "14", "backups")
+		Expect(methods(calls)).To(Equal([]string{"head_bucket"}))
+	})
+
+	It("enables the versioning of a new Google Cloud Storage bucket", func() {
+		calls := runScript(false, "--cloud-provider", "google-cloud-storage", "--retention-days", "7", "backups")
+		Expect(methods(calls)).To(Equal([]string{"lookup_bucket", "create_bucket"}))
+		Expect(calls[1].Args).To(HaveKeyWithValue("versioning", true))
+		Expect(calls[1].Args).To(HaveKeyWithValue("rules", ConsistOf(
+			map[string]interface{}{"days_since_noncurrent_time": float64(7)})))
+	})
+
+	It("leaves an existing Google Cloud Storage bucket untouched", func() {
+		calls := runScript(true, "--cloud-provider", "google-cloud-storage", "backups")
+		Expect(methods(calls)).To(Equal([]string{"lookup_bucket"}))
+	})
+})
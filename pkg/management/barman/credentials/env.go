@@ -26,6 +26,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -73,15 +74,46 @@ func envSetCloudCredentials(
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	env []string,
 ) (envs []string, err error) {
-	if configuration.BarmanCredentials.AWS != nil {
-		return envSetAWSCredentials(ctx, c, namespace, configuration.BarmanCredentials.AWS, env)
+	switch {
+	case configuration.BarmanCredentials.AWS != nil:
+		envs, err = envSetAWSCredentials(ctx, c, namespace, configuration.BarmanCredentials.AWS, env)
+	case configuration.BarmanCredentials.Google != nil:
+		envs, err = envSetGoogleCredentials(ctx, c, namespace, configuration.BarmanCredentials.Google, env)
+	default:
+		envs, err = envSetAzureCredentials(ctx, c, namespace, configuration, env)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return envSetEncryptionKeys(ctx, c, namespace, configuration.Encryption, envs)
+}
+
+// envSetEncryptionKeys sets the environment variables containing the keys
+// used for the client-side encryption of the WAL files
+func envSetEncryptionKeys(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	configuration *apiv1.ClientSideEncryptionConfiguration,
+	env []string,
+) ([]string, error) {
+	if configuration == nil {
+		return env, nil
 	}
 
-	if configuration.BarmanCredentials.Google != nil {
-		return envSetGoogleCredentials(ctx, c, namespace, configuration.BarmanCredentials.Google, env)
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: configuration.KeysSecret.Name}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("while getting secret %s: %w", configuration.KeysSecret.Name, err)
+	}
+
+	keyring, err := encryption.NewKeyring(secret.Data, configuration.ActiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the encryption keys in secret %s: %w", configuration.KeysSecret.Name, err)
 	}
 
-	return envSetAzureCredentials(ctx, c, namespace, configuration, env)
+	return append(env, keyring.Env()...), nil
 }
 
 // envSetAWSCredentials sets the AWS environment variables given the configuration
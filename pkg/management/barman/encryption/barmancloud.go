@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"os/exec"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+)
+
+// instanceManagerPath is the location of the instance manager, which
+// encrypts and decrypts the base backups on behalf of barman-cloud
+const instanceManagerPath = "/controller/manager"
+
+// barmanCloudScript runs barman-cloud-backup or barman-cloud-restore,
+// passed as the second argument, with the following arguments. Every
+// part of the base backup is encrypted by the instance manager, passed
+// as the first argument, before barman-cloud uploads it, and the
+// downloaded files are decrypted by the instance manager while they
+// are extracted
+const barmanCloudScript = `
+import io
+import os
+import subprocess
+import sys
+import tarfile
+import threading
+
+from barman import cloud
+
+manager, command = sys.argv[1], sys.argv[2]
+sys.argv = [command] + sys.argv[3:]
+
+upload_part = cloud.CloudInterface.async_upload_part
+
+
+def encrypted_upload_part(self, upload_metadata, key, body, part_number):
+    body.seek(0)
+    encrypted = subprocess.run(
+        [manager, "backup-encryption", "encrypt-part", "--part", str(part_number)],
+        input=body.read(), stdout=subprocess.PIPE, check=True).stdout
+
+    # barman-cloud removes the temporary file containing the part once
+    # uploaded, and it will upload the encrypted copy in its place
+    name = getattr(body, "name", None)
+    if isinstance(name, str):
+        body.close()
+        try:
+            os.unlink(name)
+        except OSError:
+            pass
+
+    return upload_part(self, upload_metadata, key, io.BytesIO(encrypted), part_number)
+
+
+def decrypted_extract_tar(self, key, dst):
+    extension = os.path.splitext(key)[-1]
+    compression = "" if extension == ".tar" else extension[1:]
+    source = self.remote_open(key)
+    decrypt = subprocess.Popen(
+        [manager, "backup-encryption", "decrypt"],
+        stdin=subprocess.PIPE, stdout=subprocess.PIPE)
+
+    def feed():
+        try:
+            while True:
+                chunk = source.read(1024 * 1024)
+                if not chunk:
+                    break
+                decrypt.stdin.write(chunk)
+        except OSError:
+            pass
+        finally:
+            try:
+                decrypt.stdin.close()
+            except OSError:
+                pass
+
+    feeder = threading.Thread(target=feed, daemon=True)
+    feeder.start()
+    with tarfile.open(fileobj=decrypt.stdout, mode="r|" + compression) as tar:
+        tar.extractall(path=dst)
+    while decrypt.stdout.read(1024 * 1024):
+        pass
+    feeder.join()
+    if decrypt.wait() != 0:
+        raise RuntimeError("cannot decrypt %s" % key)
+
+
+if command == "barman-cloud-backup":
+    cloud.CloudInterface.async_upload_part = encrypted_upload_part
+    from barman.clients.cloud_backup import main
+elif command == "barman-cloud-restore":
+    cloud.CloudInterface.extract_tar = decrypted_extract_tar
+    from barman.clients.cloud_restore import main
+else:
+    sys.exit("unsupported command %s" % command)
+
+main()
+`
+
+// NewBarmanCloudCommand creates the command running barman-cloud-backup
+// or barman-cloud-restore, encrypting the uploaded base backup or
+// decrypting the downloaded one. The keys are read by the instance manager
+// from the environment of the command
+func NewBarmanCloudCommand(name string, args ...string) (*exec.Cmd, error) {
+	return barman.NewPythonCommand(name, barmanCloudScript, append([]string{instanceManagerPath, name}, args...)...)
+}
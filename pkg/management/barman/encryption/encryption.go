@@ -0,0 +1,286 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption implements the client-side encryption of the WAL
+// files and of the base backups stored in the object store, with the
+// keys provided by the user
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// KeysEnvVar is the environment variable containing the encryption
+	// keys, as a comma separated list of `name:base64-key` entries
+	KeysEnvVar = "CNPG_WAL_ENCRYPTION_KEYS"
+
+	// ActiveKeyEnvVar is the environment variable containing the name of
+	// the key used to encrypt the new files
+	ActiveKeyEnvVar = "CNPG_WAL_ENCRYPTION_ACTIVE_KEY"
+
+	// KeySize is the size, in bytes, of the AES-256 keys
+	KeySize = 32
+
+	// ScratchDirectory is the directory where the encrypted copies of the
+	// WAL files are written before being uploaded
+	ScratchDirectory = postgres.ScratchDataDirectory + "/wal-encryption"
+)
+
+// magic is the prefix of every encrypted file, followed by the length of
+// the name of the key, the name of the key, the nonce and the ciphertext
+var magic = []byte("CNPGENC1")
+
+// ErrMissingKeys is returned when decrypting a file without the keys
+var ErrMissingKeys = errors.New("the file is encrypted, but no encryption key has been configured")
+
+// Keyring contains the keys used to encrypt and decrypt the files
+type Keyring struct {
+	keys      map[string][]byte
+	activeKey string
+}
+
+// NewKeyring creates a keyring with the passed keys, encrypting the new
+// files with the active one
+func NewKeyring(keys map[string][]byte, activeKey string) (*Keyring, error) {
+	for name, key := range keys {
+		if name == "" || len(name) > math.MaxUint8 || strings.ContainsAny(name, ":,") {
+			return nil, fmt.Errorf("invalid encryption key name %q", name)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("the encryption key %q is %d bytes long, expected %d", name, len(key), KeySize)
+		}
+	}
+	if _, ok := keys[activeKey]; !ok {
+		return nil, fmt.Errorf("the active encryption key %q is not defined", activeKey)
+	}
+
+	return &Keyring{keys: keys, activeKey: activeKey}, nil
+}
+
+// Env returns the environment variables describing the keyring
+func (keyring *Keyring) Env() []string {
+	names := make([]string, 0, len(keyring.keys))
+	for name := range keyring.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]string, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, name+":"+base64.StdEncoding.EncodeToString(keyring.keys[name]))
+	}
+
+	return []string{
+		KeysEnvVar + "=" + strings.Join(entries, ","),
+		ActiveKeyEnvVar + "=" + keyring.activeKey,
+	}
+}
+
+// FromEnv creates a keyring from the passed environment variables,
+// returning nil when no encryption key is defined
+func FromEnv(env []string) (*Keyring, error) {
+	var rawKeys, activeKey string
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		switch name {
+		case KeysEnvVar:
+			rawKeys = value
+		case ActiveKeyEnvVar:
+			activeKey = value
+		}
+	}
+	if rawKeys == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(rawKeys, ",") {
+		name, encodedKey, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid encryption key entry in %s", KeysEnvVar)
+		}
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("while decoding the encryption key %q: %w", name, err)
+		}
+		keys[name] = key
+	}
+
+	return NewKeyring(keys, activeKey)
+}
+
+// IsEncrypted checks if the passed content has been encrypted
+func IsEncrypted(content []byte) bool {
+	return bytes.HasPrefix(content, magic)
+}
+
+// Encrypt encrypts the passed content with the active key, using AES-256-GCM
+func (keyring *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	return keyring.seal(plaintext, nil)
+}
+
+// seal encrypts the passed content with the active key, authenticating
+// the header together with the passed additional data, which is not
+// stored in the result
+func (keyring *Keyring) seal(plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(keyring.keys[keyring.activeKey])
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(magic)+1+len(keyring.activeKey))
+	header = append(header, magic...)
+	header = append(header, byte(len(keyring.activeKey)))
+	header = append(header, keyring.activeKey...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("while generating the nonce: %w", err)
+	}
+
+	result := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	result = append(result, header...)
+	result = append(result, nonce...)
+	return aead.Seal(result, nonce, plaintext, append(header, additionalData...)), nil
+}
+
+// Decrypt decrypts the passed content with the key it has been encrypted
+// with, which must be contained in the keyring. Content that is not
+// encrypted is returned as is
+func (keyring *Keyring) Decrypt(content []byte) ([]byte, error) {
+	if !IsEncrypted(content) {
+		return content, nil
+	}
+
+	return keyring.open(content, nil)
+}
+
+// open decrypts the passed encrypted content, authenticating it together
+// with the passed additional data
+func (keyring *Keyring) open(content []byte, additionalData []byte) ([]byte, error) {
+	if keyring == nil {
+		return nil, ErrMissingKeys
+	}
+
+	if len(content) < len(magic)+1 {
+		return nil, fmt.Errorf("truncated encryption header")
+	}
+	nameLength := int(content[len(magic)])
+	headerLength := len(magic) + 1 + nameLength
+	if len(content) < headerLength {
+		return nil, fmt.Errorf("truncated encryption header")
+	}
+	header := content[:headerLength]
+	keyName := string(header[len(magic)+1:])
+
+	key, ok := keyring.keys[keyName]
+	if !ok {
+		return nil, fmt.Errorf("the file has been encrypted with the unknown key %q", keyName)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) < headerLength+aead.NonceSize() {
+		return nil, fmt.Errorf("truncated encryption header")
+	}
+	nonce := content[headerLength : headerLength+aead.NonceSize()]
+	authenticatedData := append(append([]byte{}, header...), additionalData...)
+	plaintext, err := aead.Open(nil, nonce, content[headerLength+aead.NonceSize():], authenticatedData)
+	if err != nil {
+		return nil, fmt.Errorf("while decrypting with the key %q: %w", keyName, err)
+	}
+
+	return plaintext, nil
+}
+
+// EncryptFile writes to the destination file the encrypted content of
+// the source one
+func (keyring *Keyring) EncryptFile(source, destination string) error {
+	content, err := fileutils.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := keyring.Encrypt(content)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(destination, encrypted, 0o600)
+	return err
+}
+
+// EncryptToScratchDirectory writes the encrypted copy of the passed file
+// in the scratch directory, with the same name, returning its path. The
+// caller is responsible for removing it
+func (keyring *Keyring) EncryptToScratchDirectory(fileName string) (string, error) {
+	destination := filepath.Join(ScratchDirectory, filepath.Base(fileName))
+	if err := keyring.EncryptFile(fileName, destination); err != nil {
+		return "", fmt.Errorf("while encrypting %s: %w", fileName, err)
+	}
+
+	return destination, nil
+}
+
+// DecryptFile decrypts in place the passed file, if it is encrypted
+func (keyring *Keyring) DecryptFile(fileName string) error {
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	if !IsEncrypted(content) {
+		return nil
+	}
+
+	decrypted, err := keyring.Decrypt(content)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	_, err = fileutils.WriteFileAtomic(fileName, decrypted, info.Mode().Perm())
+	return err
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
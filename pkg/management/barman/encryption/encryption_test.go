@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keyring", func() {
+	oldKey := bytes.Repeat([]byte{1}, KeySize)
+	newKey := bytes.Repeat([]byte{2}, KeySize)
+	content := []byte("the content of a WAL segment")
+
+	It("validates the keys", func() {
+		_, err := NewKeyring(map[string][]byte{"old": oldKey}, "new")
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewKeyring(map[string][]byte{"old": []byte("short")}, "old")
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewKeyring(map[string][]byte{"a:b": oldKey}, "a:b")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("encrypts and decrypts the content", func() {
+		keyring, err := NewKeyring(map[string][]byte{"old": oldKey}, "old")
+		Expect(err).ToNot(HaveOccurred())
+
+		encrypted, err := keyring.Encrypt(content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(IsEncrypted(encrypted)).To(BeTrue())
+		Expect(bytes.Contains(encrypted, content)).To(BeFalse())
+
+		Expect(keyring.Decrypt(encrypted)).To(Equal(content))
+	})
+
+	It("decrypts the content encrypted before a key rotation", func() {
+		oldKeyring, err := NewKeyring(map[string][]byte{"old": oldKey}, "old")
+		Expect(err).ToNot(HaveOccurred())
+		encrypted, err := oldKeyring.Encrypt(content)
+		Expect(err).ToNot(HaveOccurred())
+
+		rotatedKeyring, err := NewKeyring(map[string][]byte{"old": oldKey, "new": newKey}, "new")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotatedKeyring.Decrypt(encrypted)).To(Equal(content))
+
+		newKeyring, err := NewKeyring(map[string][]byte{"new": newKey}, "new")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = newKeyring.Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("detects the tampered content", func() {
+		keyring, err := NewKeyring(map[string][]byte{"old": oldKey}, "old")
+		Expect(err).ToNot(HaveOccurred())
+		encrypted, err := keyring.Encrypt(content)
+		Expect(err).ToNot(HaveOccurred())
+
+		encrypted[len(encrypted)-1] ^= 0xff
+		_, err = keyring.Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns the content not encrypted as is, even without keys", func() {
+		var keyring *Keyring
+		Expect(keyring.Decrypt(content)).To(Equal(content))
+
+		encrypted, err := (&Keyring{keys: map[string][]byte{"old": oldKey}, activeKey: "old"}).Encrypt(content)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = keyring.Decrypt(encrypted)
+		Expect(err).To(MatchError(ErrMissingKeys))
+	})
+
+	It("is passed through the environment", func() {
+		keyring, err := NewKeyring(map[string][]byte{"old": oldKey, "new": newKey}, "new")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(FromEnv(append([]string{"PATH=/bin"}, keyring.Env()...))).To(Equal(keyring))
+		Expect(FromEnv([]string{"PATH=/bin"})).To(BeNil())
+	})
+
+	It("encrypts and decrypts the files", func() {
+		tempDir := GinkgoT().TempDir()
+		source := filepath.Join(tempDir, "000000010000000000000001")
+		destination := filepath.Join(tempDir, "encrypted", "000000010000000000000001")
+		Expect(os.WriteFile(source, content, 0o600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Dir(destination), 0o700)).To(Succeed())
+
+		keyring, err := NewKeyring(map[string][]byte{"old": oldKey}, "old")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keyring.EncryptFile(source, destination)).To(Succeed())
+		Expect(keyring.DecryptFile(destination)).To(Succeed())
+		Expect(os.ReadFile(destination)).To(Equal(content))
+
+		// Decrypting a file that is not encrypted doesn't change it
+		Expect(keyring.DecryptFile(destination)).To(Succeed())
+		Expect(os.ReadFile(destination)).To(Equal(content))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// partHeaderSize is the size of the length prefixing every encrypted
+// part of a base backup
+const partHeaderSize = 4
+
+// EncryptPart encrypts a part of a file of a base backup, as uploaded by
+// barman-cloud-backup, with the active key. The result is prefixed by its
+// length, so that the encrypted parts can be concatenated by the object
+// store. The part number is authenticated together with the content, so
+// that the parts can't be reordered or omitted
+func (keyring *Keyring) EncryptPart(plaintext []byte, partNumber uint32) ([]byte, error) {
+	encrypted, err := keyring.seal(plaintext, partAdditionalData(partNumber))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(encrypted)) > math.MaxUint32 {
+		return nil, fmt.Errorf("the part %d is too big to be encrypted", partNumber)
+	}
+
+	result := make([]byte, partHeaderSize, partHeaderSize+len(encrypted))
+	binary.BigEndian.PutUint32(result, uint32(len(encrypted)))
+	return append(result, encrypted...), nil
+}
+
+// DecryptStream writes to the destination the decrypted content of a file
+// of a base backup, made of the concatenation of the parts encrypted by
+// EncryptPart. The content of the base backups taken without the
+// client-side encryption is copied as is
+func (keyring *Keyring) DecryptStream(destination io.Writer, source io.Reader) error {
+	reader := bufio.NewReader(source)
+	prefix, err := reader.Peek(partHeaderSize + len(magic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if len(prefix) < partHeaderSize || !IsEncrypted(prefix[partHeaderSize:]) {
+		_, err = io.Copy(destination, reader)
+		return err
+	}
+
+	lengthBuffer := make([]byte, partHeaderSize)
+	for partNumber := uint32(1); ; partNumber++ {
+		if _, err := io.ReadFull(reader, lengthBuffer); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("while reading the part %d: %w", partNumber, err)
+		}
+
+		encrypted := make([]byte, binary.BigEndian.Uint32(lengthBuffer))
+		if _, err := io.ReadFull(reader, encrypted); err != nil {
+			return fmt.Errorf("while reading the part %d: %w", partNumber, err)
+		}
+		if !IsEncrypted(encrypted) {
+			return fmt.Errorf("the part %d is not encrypted", partNumber)
+		}
+
+		plaintext, err := keyring.open(encrypted, partAdditionalData(partNumber))
+		if err != nil {
+			return fmt.Errorf("while decrypting the part %d: %w", partNumber, err)
+		}
+		if _, err := destination.Write(plaintext); err != nil {
+			return err
+		}
+	}
+}
+
+// partAdditionalData is the additional data authenticated together with
+// the encrypted part having the passed number
+func partAdditionalData(partNumber uint32) []byte {
+	result := make([]byte, 4)
+	binary.BigEndian.PutUint32(result, partNumber)
+	return result
+}
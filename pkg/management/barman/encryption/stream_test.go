@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Base backup encryption", func() {
+	key := bytes.Repeat([]byte{1}, KeySize)
+	parts := [][]byte{[]byte("the first part of data.tar"), []byte("the second part of data.tar")}
+
+	var keyring *Keyring
+
+	BeforeEach(func() {
+		var err error
+		keyring, err = NewKeyring(map[string][]byte{"old": key}, "old")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	encryptParts := func(partNumbers ...uint32) []byte {
+		var result []byte
+		for idx, partNumber := range partNumbers {
+			encrypted, err := keyring.EncryptPart(parts[idx], partNumber)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Contains(encrypted, parts[idx])).To(BeFalse())
+			result = append(result, encrypted...)
+		}
+		return result
+	}
+
+	It("decrypts the concatenation of the encrypted parts", func() {
+		var output bytes.Buffer
+		Expect(keyring.DecryptStream(&output, bytes.NewReader(encryptParts(1, 2)))).To(Succeed())
+		Expect(output.Bytes()).To(Equal(bytes.Join(parts, nil)))
+	})
+
+	It("detects the reordered parts", func() {
+		var output bytes.Buffer
+		Expect(keyring.DecryptStream(&output, bytes.NewReader(encryptParts(2, 1)))).ToNot(Succeed())
+	})
+
+	It("detects the truncated parts", func() {
+		encrypted := encryptParts(1, 2)
+		var output bytes.Buffer
+		Expect(keyring.DecryptStream(&output, bytes.NewReader(encrypted[:len(encrypted)-1]))).ToNot(Succeed())
+	})
+
+	It("copies the base backups taken without encryption as is", func() {
+		content := bytes.Join(parts, nil)
+		var output bytes.Buffer
+		Expect(keyring.DecryptStream(&output, bytes.NewReader(content))).To(Succeed())
+		Expect(output.Bytes()).To(Equal(content))
+
+		output.Reset()
+		Expect(keyring.DecryptStream(&output, bytes.NewReader(nil))).To(Succeed())
+		Expect(output.Bytes()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// NewPythonCommand creates the command running the passed Python script,
+// with the following arguments, through the interpreter of the passed
+// barman-cloud executable. It is the only interpreter having the barman
+// modules and the SDKs of the cloud providers
+func NewPythonCommand(barmanCloudCommand, script string, args ...string) (*exec.Cmd, error) {
+	executable, err := exec.LookPath(barmanCloudCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	interpreter, err := getInterpreter(executable)
+	if err != nil {
+		return nil, err
+	}
+
+	commandArgs := append([]string{}, interpreter[1:]...)
+	commandArgs = append(commandArgs, "-c", script)
+	commandArgs = append(commandArgs, args...)
+	return exec.Command(interpreter[0], commandArgs...), nil // #nosec G204
+}
+
+// getInterpreter gets the interpreter, with its arguments, from the
+// shebang of the passed script
+func getInterpreter(script string) ([]string, error) {
+	file, err := os.Open(script) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	firstLine, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && firstLine == "" {
+		return nil, fmt.Errorf("while reading %s: %w", script, err)
+	}
+
+	interpreter := strings.Fields(strings.TrimPrefix(firstLine, "#!"))
+	if !strings.HasPrefix(firstLine, "#!") || len(interpreter) == 0 {
+		return nil, fmt.Errorf("%s is not a script", script)
+	}
+
+	return interpreter, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud interpreter", func() {
+	writeScript := func(content string) string {
+		script := filepath.Join(GinkgoT().TempDir(), "barman-cloud-backup")
+		Expect(os.WriteFile(script, []byte(content), 0o600)).To(Succeed())
+		return script
+	}
+
+	It("reads the interpreter from the shebang", func() {
+		Expect(getInterpreter(writeScript("#!/usr/bin/python3\nimport sys\n"))).
+			To(Equal([]string{"/usr/bin/python3"}))
+		Expect(getInterpreter(writeScript("#! /usr/bin/env python3"))).
+			To(Equal([]string{"/usr/bin/env", "python3"}))
+	})
+
+	It("rejects the executables that are not scripts", func() {
+		_, err := getInterpreter(writeScript("\x7fELF"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = getInterpreter(writeScript(""))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -22,12 +22,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
 	"os/exec"
 	"sync"
 	"time"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -51,6 +53,9 @@ type WALRestorer struct {
 
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
+
+	// The keys used to decrypt the restored WAL files
+	keyring *encryption.Keyring
 }
 
 // Result is the structure filled by the restore process on completion
@@ -84,10 +89,16 @@ func New(ctx context.Context, cluster *apiv1.Cluster, env []string, spoolDirecto
 		return nil, fmt.Errorf("while creating spool directory: %w", err)
 	}
 
+	keyring, err := encryption.FromEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("while loading the WAL encryption keys: %w", err)
+	}
+
 	archiver = &WALRestorer{
 		cluster: cluster,
 		spool:   walRecoverSpool,
 		env:     env,
+		keyring: keyring,
 	}
 	return archiver, nil
 }
@@ -234,6 +245,10 @@ func (restorer *WALRestorer) Restore(walName, destinationPath string, baseOption
 	barmanCloudWalRestoreCmd.Env = restorer.env
 	err := execlog.RunStreaming(barmanCloudWalRestoreCmd, barmanCapabilities.BarmanCloudWalRestore)
 	if err == nil {
+		if err := restorer.keyring.DecryptFile(destinationPath); err != nil {
+			_ = os.Remove(destinationPath)
+			return fmt.Errorf("while decrypting the WAL file %s: %w", walName, err)
+		}
 		return nil
 	}
 
@@ -226,6 +226,23 @@ type BarmanBackup struct {
 
 	// The TimeLine
 	TimeLine int `json:"timeline"`
+
+	// The size of the backup, when reported by Barman
+	Size *int64 `json:"size"`
+
+	// The size of the data actually copied when Barman deduplicates the
+	// files not changed since the previous backup
+	DeduplicatedSize *int64 `json:"deduplicated_size"`
+
+	// The statistics about the copy of the data
+	CopyStats *BarmanCopyStats `json:"copy_stats"`
+}
+
+// BarmanCopyStats contains the statistics about the copy of the
+// data of a backup
+type BarmanCopyStats struct {
+	// The time spent copying the data, in seconds
+	TotalTime float64 `json:"total_time"`
 }
 
 func (b *BarmanBackup) isBackupDone() bool {
@@ -69,7 +69,7 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
+* = host={{ .Host }}
 
 [pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
@@ -81,6 +81,7 @@ auth_query = {{ .AuthQuery }}
 	pgbouncerHBAFileTemplateString = `
 local pgbouncer pgbouncer peer
 host all all 0.0.0.0/0 md5
+host all all ::/0 md5
 `
 
 	pgBouncerUserListTemplateString = `
@@ -167,6 +168,19 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 
 	parameters := buildPgBouncerParameters(pooler.Spec.PgBouncer.Parameters)
 
+	// The TLS mode of the server connections follows the one
+	// of the streaming replication connections of the cluster
+	if pooler.Status.ServerTLSMode != "" {
+		parameters["server_tls_sslmode"] = pooler.Status.ServerTLSMode
+	}
+
+	// PgBouncer opens its server connections on the backends in turn,
+	// unless it needs to prefer the first one, which is the replica
+	// with the fewest client connections
+	if pooler.IsLeastConnectionsLoadBalancingEnabled() {
+		parameters["load_balance_hosts"] = "disable"
+	}
+
 	if isCertAuth {
 		parameters["server_tls_cert_file"] = authUserCrtPath
 		parameters["server_tls_key_file"] = authUserKeyPath
@@ -176,12 +190,14 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 
 	templateData := struct {
 		Pooler            *apiv1.Pooler
+		Host              string
 		AuthQuery         string
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
 	}{
 		Pooler:            pooler,
+		Host:              pooler.GetBackendHost(),
 		AuthQuery:         pooler.GetAuthQuery(),
 		AuthQueryUser:     authQueryUser,
 		AuthQueryPassword: authQueryPassword,
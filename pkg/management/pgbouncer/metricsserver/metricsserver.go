@@ -40,11 +40,18 @@ var (
 	// exporter is the exporter for predefined queries and for
 	// custom ones
 	exporter *Exporter
+
+	// metricsServiceHost is the host name of the headless service
+	// resolving to all the PgBouncer instances of the pooler
+	metricsServiceHost string
 )
 
 // Setup configure the web statusServer for a certain PostgreSQL instance, and
-// must be invoked before starting the real web statusServer
-func Setup() error {
+// must be invoked before starting the real web statusServer. The passed host
+// name is the one of the headless metrics service of the pooler
+func Setup(serviceHost string) error {
+	metricsServiceHost = serviceHost
+
 	// create the exporter and serve it on the /metrics endpoint
 	registry = prometheus.NewRegistry()
 	exporter = NewExporter()
@@ -61,6 +68,8 @@ func Setup() error {
 func ListenAndServe() error {
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	serveMux.HandleFunc(url.PathPgBouncerSaturation, saturationHandler)
+	serveMux.HandleFunc(url.PathPgBouncerPoolerSaturation, poolerSaturationHandler)
 
 	server = &http.Server{
 		Addr:              fmt.Sprintf(":%d", url.PgBouncerMetricsPort),
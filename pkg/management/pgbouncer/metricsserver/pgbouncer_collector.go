@@ -50,6 +50,7 @@ type metrics struct {
 	ShowLists          ShowListsMetrics
 	ShowPools          *ShowPoolsMetrics
 	ShowStats          *ShowStatsMetrics
+	Saturation         *SaturationMetrics
 }
 
 // NewExporter creates an exporter
@@ -93,9 +94,10 @@ func newMetrics() *metrics {
 			Name:      "collection_duration_seconds",
 			Help:      "Collection time duration in seconds",
 		}, []string{"collector"}),
-		ShowLists: NewShowListsMetrics(subsystem),
-		ShowPools: NewShowPoolsMetrics(subsystem),
-		ShowStats: NewShowStatsMetrics(subsystem),
+		ShowLists:  NewShowListsMetrics(subsystem),
+		ShowPools:  NewShowPoolsMetrics(subsystem),
+		ShowStats:  NewShowStatsMetrics(subsystem),
+		Saturation: NewSaturationMetrics(subsystem),
 	}
 }
 
@@ -108,6 +110,7 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.ShowLists.Describe(ch)
 	e.Metrics.ShowPools.Describe(ch)
 	e.Metrics.ShowStats.Describe(ch)
+	e.Metrics.Saturation.Describe(ch)
 }
 
 // Collect implements prometheus.Collector, collecting the Metrics values to
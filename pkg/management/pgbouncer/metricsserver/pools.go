@@ -180,6 +180,7 @@ func (e *Exporter) collectShowPools(ch chan<- prometheus.Metric, db *sql.DB) {
 		maxWait     int
 		maxWaitUs   int
 		poolMode    string
+		saturation  Saturation
 	)
 
 	for rows.Next() {
@@ -211,6 +212,7 @@ func (e *Exporter) collectShowPools(ch chan<- prometheus.Metric, db *sql.DB) {
 		e.Metrics.ShowPools.MaxWait.WithLabelValues(database, user).Set(float64(maxWait))
 		e.Metrics.ShowPools.MaxWaitUs.WithLabelValues(database, user).Set(float64(maxWaitUs))
 		e.Metrics.ShowPools.PoolMode.WithLabelValues(database, user).Set(float64(poolModeToInt(poolMode)))
+		saturation.add(clActive, clWaiting)
 	}
 
 	e.Metrics.ShowPools.ClActive.Collect(ch)
@@ -224,6 +226,8 @@ func (e *Exporter) collectShowPools(ch chan<- prometheus.Metric, db *sql.DB) {
 	e.Metrics.ShowPools.MaxWait.Collect(ch)
 	e.Metrics.ShowPools.MaxWaitUs.Collect(ch)
 	e.Metrics.ShowPools.PoolMode.Collect(ch)
+	e.Metrics.Saturation.Set(saturation)
+	e.Metrics.Saturation.Collect(ch)
 
 	if err = rows.Err(); err != nil {
 		e.Metrics.Error.Set(1)
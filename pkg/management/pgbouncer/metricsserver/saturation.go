@@ -0,0 +1,263 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// saturationRequestTimeout is the timeout used when querying the
+// saturation of the other PgBouncer instances of the pooler
+const saturationRequestTimeout = 2 * time.Second
+
+// Saturation is the load of a PgBouncer instance, summed across all
+// its pools. It is exposed as JSON to be consumed by the KEDA
+// `metrics-api` scaler
+type Saturation struct {
+	// ClientConnections is the number of client connections that are
+	// either linked to a server connection or waiting for one
+	ClientConnections int `json:"clientConnections"`
+
+	// WaitingClients is the number of client connections waiting for a
+	// server connection
+	WaitingClients int `json:"waitingClients"`
+}
+
+// PoolerSaturation is the load of all the PgBouncer instances of a pooler
+type PoolerSaturation struct {
+	Saturation
+
+	// Instances is the number of PgBouncer instances which reported
+	// their saturation
+	Instances int `json:"instances"`
+}
+
+// SaturationMetrics contains the metrics describing the load of PgBouncer
+type SaturationMetrics struct {
+	ClientConnections,
+	WaitingClients prometheus.Gauge
+}
+
+// NewSaturationMetrics builds the default SaturationMetrics
+func NewSaturationMetrics(subsystem string) *SaturationMetrics {
+	subsystem += "_saturation"
+	return &SaturationMetrics{
+		ClientConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "client_connections",
+			Help: "Client connections, summed across all the pools, that are linked to a server " +
+				"connection or waiting for one.",
+		}),
+		WaitingClients: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "waiting_clients",
+			Help:      "Client connections, summed across all the pools, waiting for a server connection.",
+		}),
+	}
+}
+
+// Describe produces the description for all the contained Metrics
+func (r *SaturationMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.ClientConnections.Desc()
+	ch <- r.WaitingClients.Desc()
+}
+
+// Set updates the contained Metrics with the passed saturation
+func (r *SaturationMetrics) Set(saturation Saturation) {
+	r.ClientConnections.Set(float64(saturation.ClientConnections))
+	r.WaitingClients.Set(float64(saturation.WaitingClients))
+}
+
+// Collect sends the contained Metrics to the passed channel
+func (r *SaturationMetrics) Collect(ch chan<- prometheus.Metric) {
+	ch <- r.ClientConnections
+	ch <- r.WaitingClients
+}
+
+// add accounts the client connections of a pool in the saturation
+func (s *Saturation) add(clActive, clWaiting int) {
+	s.ClientConnections += clActive + clWaiting
+	s.WaitingClients += clWaiting
+}
+
+// querySaturation computes the saturation of PgBouncer from the output
+// of SHOW POOLS, looking up the columns by name since their number
+// depends on the version of PgBouncer
+func querySaturation(db *sql.DB) (Saturation, error) {
+	var saturation Saturation
+
+	rows, err := db.Query("SHOW POOLS;")
+	if err != nil {
+		return saturation, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for SHOW POOLS")
+		}
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return saturation, err
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	destinations := make([]interface{}, len(columns))
+	for idx := range values {
+		destinations[idx] = &values[idx]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(destinations...); err != nil {
+			return saturation, err
+		}
+
+		var clActive, clWaiting int
+		for idx, column := range columns {
+			switch column {
+			case "cl_active":
+				clActive, err = strconv.Atoi(string(values[idx]))
+			case "cl_waiting":
+				clWaiting, err = strconv.Atoi(string(values[idx]))
+			}
+			if err != nil {
+				return saturation, fmt.Errorf("while parsing the %s column: %w", column, err)
+			}
+		}
+		saturation.add(clActive, clWaiting)
+	}
+
+	return saturation, rows.Err()
+}
+
+// saturationHandler serves the current saturation of PgBouncer as JSON
+func saturationHandler(w http.ResponseWriter, _ *http.Request) {
+	db, err := exporter.GetPgBouncerDB()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	saturation, err := querySaturation(db)
+	if err != nil {
+		log.Error(err, "Error while computing the PgBouncer saturation")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(saturation); err != nil {
+		log.Error(err, "while writing the PgBouncer saturation")
+	}
+}
+
+// poolerSaturationHandler serves the saturation of all the PgBouncer
+// instances of the pooler as JSON, discovering them through the
+// headless metrics service
+func poolerSaturationHandler(w http.ResponseWriter, r *http.Request) {
+	addresses, err := net.DefaultResolver.LookupHost(r.Context(), metricsServiceHost)
+	if err != nil {
+		log.Error(err, "Error while looking up the PgBouncer instances", "host", metricsServiceHost)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	urls := make([]string, len(addresses))
+	for idx, address := range addresses {
+		urls[idx] = fmt.Sprintf("http://%s%s",
+			net.JoinHostPort(address, strconv.Itoa(url.PgBouncerMetricsPort)), url.PathPgBouncerSaturation)
+	}
+
+	saturation, err := collectPoolerSaturation(r.Context(), urls)
+	if err != nil {
+		log.Error(err, "Error while computing the saturation of the pooler")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(saturation); err != nil {
+		log.Error(err, "while writing the pooler saturation")
+	}
+}
+
+// collectPoolerSaturation sums the saturation reported by the passed
+// URLs. The instances which can't be queried, such as the ones being
+// started, are skipped, failing only when none of them answers
+func collectPoolerSaturation(ctx context.Context, urls []string) (PoolerSaturation, error) {
+	var result PoolerSaturation
+	var lastErr error
+
+	httpClient := &http.Client{Timeout: saturationRequestTimeout}
+	for _, saturationURL := range urls {
+		saturation, err := getSaturation(ctx, httpClient, saturationURL)
+		if err != nil {
+			log.Info("Skipping the saturation of a PgBouncer instance", "url", saturationURL, "err", err)
+			lastErr = err
+			continue
+		}
+
+		result.ClientConnections += saturation.ClientConnections
+		result.WaitingClients += saturation.WaitingClients
+		result.Instances++
+	}
+
+	if result.Instances == 0 && lastErr != nil {
+		return result, fmt.Errorf("no PgBouncer instance reported its saturation: %w", lastErr)
+	}
+
+	return result, nil
+}
+
+// getSaturation gets the saturation reported by a PgBouncer instance
+func getSaturation(ctx context.Context, httpClient *http.Client, saturationURL string) (Saturation, error) {
+	var saturation Saturation
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, saturationURL, nil)
+	if err != nil {
+		return saturation, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return saturation, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return saturation, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&saturation)
+	return saturation, err
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pooler saturation", func() {
+	newInstance := func(saturation Saturation) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(saturation)
+		}))
+		DeferCleanup(server.Close)
+		return server
+	}
+
+	It("sums the saturation of all the instances", func() {
+		first := newInstance(Saturation{ClientConnections: 10, WaitingClients: 2})
+		second := newInstance(Saturation{ClientConnections: 30, WaitingClients: 1})
+
+		saturation, err := collectPoolerSaturation(context.Background(), []string{first.URL, second.URL})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(saturation.ClientConnections).To(Equal(40))
+		Expect(saturation.WaitingClients).To(Equal(3))
+		Expect(saturation.Instances).To(Equal(2))
+	})
+
+	It("skips the instances which can't be queried", func() {
+		instance := newInstance(Saturation{ClientConnections: 10})
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		DeferCleanup(failing.Close)
+
+		saturation, err := collectPoolerSaturation(context.Background(), []string{instance.URL, failing.URL})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(saturation.ClientConnections).To(Equal(10))
+		Expect(saturation.Instances).To(Equal(1))
+
+		_, err = collectPoolerSaturation(context.Background(), []string{failing.URL})
+		Expect(err).To(HaveOccurred())
+	})
+})
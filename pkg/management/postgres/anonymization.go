@@ -0,0 +1,98 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// anonymizerExtensionQueries are the queries running the static masking
+// of the postgresql_anonymizer extension, which replaces the data with
+// the masking rules declared with security labels
+var anonymizerExtensionQueries = []string{
+	"CREATE EXTENSION IF NOT EXISTS anon CASCADE",
+	"SELECT anon.init()",
+	"SELECT anon.anonymize_database()",
+}
+
+// anonymizeRecoveredData anonymizes the recovered data, when requested,
+// reporting the result in the conditions of the cluster. The recovery
+// fails when the anonymization fails, as the cluster must never start
+// with the original data
+func (info InitInfo) anonymizeRecoveredData(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	env []string,
+) error {
+	anonymization := cluster.GetRecoveryAnonymization()
+	if anonymization == nil {
+		return nil
+	}
+
+	database := cluster.GetRecoveryAnonymizationDatabase()
+	log.Info("Anonymizing the recovered data", "database", database)
+
+	instance := info.GetInstance()
+	instance.Env = env
+	err := instance.WithActiveInstance(func() error {
+		db, err := instance.ConnectionPool().Connection(database)
+		if err != nil {
+			return fmt.Errorf("could not get connection to the %s database: %w", database, err)
+		}
+
+		if anonymization.UseAnonymizerExtension {
+			if err := info.executeQueries(db, anonymizerExtensionQueries); err != nil {
+				return fmt.Errorf("could not run the postgresql_anonymizer static masking: %w", err)
+			}
+		}
+
+		return info.executeSQLRefs(db, info.AnonymizationSQLRefsFolder)
+	})
+
+	condition := &metav1.Condition{
+		Type:    string(apiv1.ConditionAnonymized),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonAnonymizationCompleted),
+		Message: fmt.Sprintf("The %s database has been anonymized", database),
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonAnonymizationFailed)
+		condition.Message = err.Error()
+	}
+
+	if updateErr := conditions.Update(ctx, typedClient, cluster, condition); updateErr != nil {
+		log.Error(updateErr, "Cannot update the anonymization condition")
+		if err == nil {
+			return updateErr
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("while anonymizing the recovered data: %w", err)
+	}
+	return nil
+}
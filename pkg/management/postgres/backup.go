@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -39,6 +40,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -64,6 +66,10 @@ type BackupCommand struct {
 	Env      []string
 	Log      log.Logger
 	Instance *Instance
+
+	// hookSessions are the sessions of the pre-backup SQL hooks, kept
+	// open while the backup is running
+	hookSessions []backupHookSession
 }
 
 // NewBackupCommand initializes a BackupCommand object
@@ -243,8 +249,17 @@ func waitForWalArchiveWorking() error {
 }
 
 // Start initiates a backup for this instance using
-// barman-cloud-backup
+// barman-cloud-backup, by taking a snapshot of its volumes, or
+// delegating it to a plugin
 func (b *BackupCommand) Start(ctx context.Context) error {
+	if b.Backup.IsVolumeSnapshot() {
+		return b.startVolumeSnapshot(ctx)
+	}
+
+	if b.Backup.IsPlugin() {
+		return b.startPlugin(ctx)
+	}
+
 	if err := b.ensureBarmanCompatibility(); err != nil {
 		return err
 	}
@@ -322,66 +337,40 @@ func (b *BackupCommand) run(ctx context.Context) {
 	}
 	b.Log.Info("Backup started", "options", options)
 
-	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
-
-	// Update backup status in cluster conditions on startup
-	condition := metav1.Condition{
-		Type:    string(apiv1.ConditionBackup),
-		Status:  metav1.ConditionFalse,
-		Reason:  string(apiv1.ConditionBackupStarted),
-		Message: "New Backup starting up",
-	}
-	if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
-		b.Log.Error(condErr, "Error changing backup condition (backup started)")
-	}
+	b.backupStarted(ctx)
 
 	if err := fileutils.EnsureDirectoryExist(postgres.BackupTemporaryDirectory); err != nil {
 		b.Log.Error(err, "Cannot create backup temporary directory", "err", err)
 		return
 	}
 
-	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
-	cmd.Env = b.Env
-	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
-	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup)
-	if err != nil {
-		// Set the status to failed and exit
-		b.Log.Error(err, "Backup failed")
-		backupStatus.SetAsFailed(err)
-		b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
-
-		// Update backup status in cluster conditions on failure
-		condition = metav1.Condition{
-			Type:    string(apiv1.ConditionBackup),
-			Status:  metav1.ConditionFalse,
-			Reason:  string(apiv1.ConditionReasonLastBackupFailed),
-			Message: err.Error(),
-		}
-		if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
-			b.Log.Error(condErr, "Error changing backup condition (backup failed)")
-		}
-		if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
-			b.Log.Error(err, "Can't mark backup as failed")
+	b.setupBackupStatistics()
+
+	err = b.runHooks(ctx, apiv1.BackupHookStagePre)
+	if err == nil {
+		var cmd *exec.Cmd
+		cmd, err = newBarmanCloudCommand(
+			barmanConfiguration.Encryption != nil, barmanCapabilities.BarmanCloudBackup, options...)
+		if err == nil {
+			cmd.Env = b.Env
+			cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
+			err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup)
 		}
-		return
 	}
 
-	// Set the status to completed
-	b.Log.Info("Backup completed")
-	backupStatus.SetAsCompleted()
-	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
-
-	// Update backup status in cluster conditions on backup completion
-	condition = metav1.Condition{
-		Type:    string(apiv1.ConditionBackup),
-		Status:  metav1.ConditionTrue,
-		Reason:  string(apiv1.ConditionReasonLastBackupSucceeded),
-		Message: "Backup has successful",
+	// The post-backup hooks are executed even if the backup failed,
+	// letting the applications resume their normal operations
+	if hookErr := b.runHooks(ctx, apiv1.BackupHookStagePost); hookErr != nil && err == nil {
+		err = hookErr
 	}
-	if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
-		b.Log.Error(condErr, "Error changing backup condition (backup succeeded)")
+
+	if err != nil {
+		b.backupFailed(ctx, err)
+		return
 	}
 
+	b.backupCompleted(ctx)
+
 	// Delete backups per policy
 	if b.Cluster.Spec.Backup.RetentionPolicy != "" {
 		b.Log.Info("Applying backup retention policy",
@@ -419,6 +408,12 @@ func (b *BackupCommand) run(ctx context.Context) {
 		b.Log.Error(err, "Can't set backup status as completed")
 	}
 
+	if backupStatus.Statistics != nil {
+		if err = b.setClusterLastBackupStatistics(ctx, backupStatus.Statistics); err != nil {
+			b.Log.Error(err, "Can't update the statistics of the last backup")
+		}
+	}
+
 	// Set the first recoverability point
 	if ts := backupList.FirstRecoverabilityPoint(); ts != nil {
 		firstRecoverabilityPoint := ts.Format(time.RFC3339)
@@ -429,6 +424,77 @@ func (b *BackupCommand) run(ctx context.Context) {
 	}
 }
 
+// newBarmanCloudCommand creates the command running the passed barman-cloud
+// executable, encrypting or decrypting the base backup when the
+// client-side encryption is enabled
+func newBarmanCloudCommand(withEncryption bool, name string, args ...string) (*exec.Cmd, error) {
+	if withEncryption {
+		return encryption.NewBarmanCloudCommand(name, args...)
+	}
+
+	return exec.Command(name, args...), nil // #nosec G204
+}
+
+// backupStarted records the start of the backup in the events and in
+// the conditions of the cluster
+func (b *BackupCommand) backupStarted(ctx context.Context) {
+	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+
+	// Update backup status in cluster conditions on startup
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionBackup),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionBackupStarted),
+		Message: "New Backup starting up",
+	}
+	if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
+		b.Log.Error(condErr, "Error changing backup condition (backup started)")
+	}
+}
+
+// backupFailed marks the backup as failed, recording the error in the
+// backup status and in the conditions of the cluster
+func (b *BackupCommand) backupFailed(ctx context.Context, err error) {
+	// Set the status to failed and exit
+	b.Log.Error(err, "Backup failed")
+	b.Backup.GetStatus().SetAsFailed(err)
+	b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+
+	// Update backup status in cluster conditions on failure
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionBackup),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonLastBackupFailed),
+		Message: err.Error(),
+	}
+	if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
+		b.Log.Error(condErr, "Error changing backup condition (backup failed)")
+	}
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't mark backup as failed")
+	}
+}
+
+// backupCompleted marks the backup as completed in its status and in the
+// conditions of the cluster. The status is not stored in the API server
+func (b *BackupCommand) backupCompleted(ctx context.Context) {
+	// Set the status to completed
+	b.Log.Info("Backup completed")
+	b.Backup.GetStatus().SetAsCompleted()
+	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+
+	// Update backup status in cluster conditions on backup completion
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionBackup),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonLastBackupSucceeded),
+		Message: "Backup has successful",
+	}
+	if condErr := conditions.Update(ctx, b.Client, b.Cluster, &condition); condErr != nil {
+		b.Log.Error(condErr, "Error changing backup condition (backup succeeded)")
+	}
+}
+
 // UpdateBackupStatusAndRetry updates a certain backup's status in the k8s database,
 // retries when error occurs
 func UpdateBackupStatusAndRetry(
@@ -472,6 +538,58 @@ func (b *BackupCommand) setClusterFirstRecoverabilityPoint(
 	})
 }
 
+// setClusterLastBackupStatistics sets the statistics of the last completed
+// backup in the status
+func (b *BackupCommand) setClusterLastBackupStatistics(
+	ctx context.Context,
+	statistics *apiv1.BackupStatistics,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newCluster := &apiv1.Cluster{}
+		namespacedName := types.NamespacedName{Namespace: b.Cluster.GetNamespace(), Name: b.Cluster.GetName()}
+		err := b.Client.Get(ctx, namespacedName, newCluster)
+		if err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(newCluster.Status.LastBackupStatistics, statistics) {
+			return nil
+		}
+
+		newCluster.Status.LastBackupStatistics = statistics.DeepCopy()
+		return b.Client.Status().Update(ctx, newCluster)
+	})
+}
+
+// setupBackupStatistics records the size of the databases before the
+// backup is started. A failure is not fatal, as the statistics are
+// only informative
+func (b *BackupCommand) setupBackupStatistics() {
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.Statistics = &apiv1.BackupStatistics{}
+
+	db, err := b.Instance.GetSuperUserDB()
+	if err != nil {
+		b.Log.Warning("Cannot connect to PostgreSQL to measure the databases size", "error", err)
+		return
+	}
+
+	if backupStatus.Statistics.DataSize, err = getDatabasesSize(db); err != nil {
+		b.Log.Warning("Cannot measure the databases size", "error", err)
+	}
+}
+
+// getDatabasesSize gets the size in bytes of all the databases
+func getDatabasesSize(db *sql.DB) (int64, error) {
+	var size int64
+	row := db.QueryRow("SELECT COALESCE(SUM(pg_database_size(oid)), 0)::bigint FROM pg_database")
+	if err := row.Scan(&size); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
 // setupBackupStatus configures the backup's status from the provided configuration and instance
 func (b *BackupCommand) setupBackupStatus() {
 	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
@@ -479,6 +597,7 @@ func (b *BackupCommand) setupBackupStatus() {
 
 	backupStatus.BarmanCredentials = barmanConfiguration.BarmanCredentials
 	backupStatus.EndpointCA = barmanConfiguration.EndpointCA
+	backupStatus.ClientSideEncryption = barmanConfiguration.Encryption
 	backupStatus.EndpointURL = barmanConfiguration.EndpointURL
 	backupStatus.DestinationPath = barmanConfiguration.DestinationPath
 	if barmanConfiguration.Data != nil {
@@ -490,6 +609,7 @@ func (b *BackupCommand) setupBackupStatus() {
 	if backupStatus.ServerName == "" {
 		backupStatus.ServerName = b.Cluster.Name
 	}
+	backupStatus.Method = b.Backup.GetMethod()
 	backupStatus.Phase = apiv1.BackupPhaseRunning
 }
 
@@ -508,4 +628,15 @@ func (b *BackupCommand) updateCompletedBackupStatus(backupList *catalog.Catalog)
 	backupStatus.EndWal = latestBackup.EndWal
 	backupStatus.BeginLSN = latestBackup.BeginLSN
 	backupStatus.EndLSN = latestBackup.EndLSN
+
+	if backupStatus.Statistics == nil {
+		backupStatus.Statistics = &apiv1.BackupStatistics{}
+	}
+	backupStatus.Statistics.Size = latestBackup.Size
+	backupStatus.Statistics.DeduplicatedSize = latestBackup.DeduplicatedSize
+	duration := latestBackup.EndTime.Sub(latestBackup.BeginTime)
+	if latestBackup.CopyStats != nil && latestBackup.CopyStats.TotalTime > 0 {
+		duration = time.Duration(latestBackup.CopyStats.TotalTime * float64(time.Second))
+	}
+	backupStatus.Statistics.Duration = &metav1.Duration{Duration: duration}
 }
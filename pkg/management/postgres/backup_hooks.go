@@ -0,0 +1,207 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+)
+
+// getBackupHooks returns the hooks that are configured to be executed at the
+// passed stage of the backup
+func getBackupHooks(cluster *apiv1.Cluster, stage apiv1.BackupHookStage) []apiv1.BackupHook {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.Hooks == nil {
+		return nil
+	}
+
+	switch stage {
+	case apiv1.BackupHookStagePre:
+		return cluster.Spec.Backup.Hooks.Pre
+	case apiv1.BackupHookStagePost:
+		return cluster.Spec.Backup.Hooks.Post
+	default:
+		return nil
+	}
+}
+
+// runHooks executes the backup hooks configured for the passed stage,
+// recording their result in the backup status. Pre-backup hooks are
+// interrupted by the first failure not ignored by the failure policy, while
+// post-backup hooks are always executed. The returned error is the first
+// one that should make the backup fail. The sessions of the pre-backup
+// SQL hooks are closed before executing the post-backup hooks
+func (b *BackupCommand) runHooks(ctx context.Context, stage apiv1.BackupHookStage) error {
+	if stage == apiv1.BackupHookStagePost {
+		b.closeHookSessions()
+	}
+
+	hooks := getBackupHooks(b.Cluster, stage)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	backupStatus := b.Backup.GetStatus()
+	var result error
+	for _, hook := range hooks {
+		hookStatus := apiv1.BackupHookStatus{
+			Name:      hook.Name,
+			Stage:     stage,
+			StartedAt: &metav1.Time{Time: time.Now()},
+		}
+
+		b.Log.Info("Executing backup hook", "stage", stage, "hook", hook.Name)
+		err := b.runHook(ctx, stage, hook)
+		hookStatus.StoppedAt = &metav1.Time{Time: time.Now()}
+		if err != nil {
+			hookStatus.Error = err.Error()
+			hookStatus.Ignored = hook.IsFailureIgnored()
+			b.Log.Warning("Backup hook failed",
+				"stage", stage,
+				"hook", hook.Name,
+				"ignored", hookStatus.Ignored,
+				"error", err)
+		}
+		backupStatus.Hooks = append(backupStatus.Hooks, hookStatus)
+
+		if err == nil || hook.IsFailureIgnored() {
+			continue
+		}
+
+		if result == nil {
+			result = fmt.Errorf("%s-backup hook %s failed: %w", stage, hook.Name, err)
+		}
+		if stage == apiv1.BackupHookStagePre {
+			break
+		}
+	}
+
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't update the backup hooks status")
+	}
+
+	return result
+}
+
+// runHook executes a single backup hook, enforcing its timeout
+func (b *BackupCommand) runHook(ctx context.Context, stage apiv1.BackupHookStage, hook apiv1.BackupHook) error {
+	timeout := hook.GetTimeout()
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case hook.SQL != "" && stage == apiv1.BackupHookStagePre:
+		err = b.openSQLHookSession(ctx, hookCtx, hook)
+	case hook.SQL != "":
+		err = b.runSQLHook(hookCtx, hook)
+	default:
+		err = runCommandHook(hookCtx, hook)
+	}
+
+	if err != nil && hookCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timeout of %v expired: %w", timeout, err)
+	}
+	return err
+}
+
+// backupHookSession is the session where the SQL statement of a
+// pre-backup hook has been executed
+type backupHookSession struct {
+	name string
+	db   *sql.DB
+	tx   *sql.Tx
+}
+
+// openSQLHookSession executes the SQL statement of a pre-backup hook in a
+// transaction of a dedicated session. The session is kept open until the
+// backup is complete, so that the locks taken by the statement are held
+// while the backup is running. The statement is bound to the timeout of
+// the hook, while the transaction lasts as long as the backup
+func (b *BackupCommand) openSQLHookSession(
+	ctx context.Context,
+	hookCtx context.Context,
+	hook apiv1.BackupHook,
+) error {
+	db, err := sql.Open("pgx", b.Instance.ConnectionPool().GetDsn(hook.GetDatabase()))
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", hook.GetDatabase(), err)
+	}
+	db.SetMaxOpenConns(1)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("while starting a transaction in database %s: %w", hook.GetDatabase(), err)
+	}
+
+	if _, err := tx.ExecContext(hookCtx, hook.SQL); err != nil {
+		_ = tx.Rollback()
+		_ = db.Close()
+		return err
+	}
+
+	b.hookSessions = append(b.hookSessions, backupHookSession{name: hook.Name, db: db, tx: tx})
+	return nil
+}
+
+// closeHookSessions commits the transactions of the pre-backup SQL hooks,
+// in reverse order, and closes their sessions, releasing their locks
+func (b *BackupCommand) closeHookSessions() {
+	for idx := len(b.hookSessions) - 1; idx >= 0; idx-- {
+		session := b.hookSessions[idx]
+		if err := session.tx.Commit(); err != nil {
+			b.Log.Warning("Cannot commit the transaction of the backup hook",
+				"hook", session.name,
+				"error", err)
+		}
+		if err := session.db.Close(); err != nil {
+			b.Log.Warning("Cannot close the session of the backup hook",
+				"hook", session.name,
+				"error", err)
+		}
+	}
+	b.hookSessions = nil
+}
+
+// runSQLHook executes the SQL statement of a post-backup hook in a
+// dedicated session, which is closed as soon as the statement completes
+func (b *BackupCommand) runSQLHook(ctx context.Context, hook apiv1.BackupHook) error {
+	db, err := sql.Open("pgx", b.Instance.ConnectionPool().GetDsn(hook.GetDatabase()))
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", hook.GetDatabase(), err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	_, err = db.ExecContext(ctx, hook.SQL)
+	return err
+}
+
+// runCommandHook executes the command of a backup hook
+func runCommandHook(ctx context.Context, hook apiv1.BackupHook) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...) // #nosec G204
+	return execlog.RunStreaming(cmd, hook.Name)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup hooks", func() {
+	var backupCommand *BackupCommand
+
+	newBackupCommand := func(hooks *apiv1.BackupHooks) *BackupCommand {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{Hooks: hooks},
+			},
+		}
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: "default"},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, backup).
+			Build()
+
+		return NewBackupCommand(cluster, backup, cli, record.NewFakeRecorder(10), nil, log.GetLogger())
+	}
+
+	It("does nothing when no hook is configured", func() {
+		backupCommand = newBackupCommand(nil)
+		Expect(backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePre)).To(Succeed())
+		Expect(backupCommand.Backup.Status.Hooks).To(BeEmpty())
+	})
+
+	It("records the result of the successful hooks", func() {
+		backupCommand = newBackupCommand(&apiv1.BackupHooks{
+			Pre: []apiv1.BackupHook{{Name: "flush", Command: []string{"true"}}},
+		})
+		Expect(backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePre)).To(Succeed())
+		Expect(backupCommand.Backup.Status.Hooks).To(HaveLen(1))
+		Expect(backupCommand.Backup.Status.Hooks[0].Name).To(Equal("flush"))
+		Expect(backupCommand.Backup.Status.Hooks[0].Stage).To(Equal(apiv1.BackupHookStagePre))
+		Expect(backupCommand.Backup.Status.Hooks[0].Error).To(BeEmpty())
+		Expect(backupCommand.Backup.Status.Hooks[0].StoppedAt).ToNot(BeNil())
+	})
+
+	It("stops at the first failing pre-backup hook", func() {
+		backupCommand = newBackupCommand(&apiv1.BackupHooks{
+			Pre: []apiv1.BackupHook{
+				{Name: "failing", Command: []string{"false"}},
+				{Name: "never-executed", Command: []string{"true"}},
+			},
+		})
+		err := backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePre)
+		Expect(err).To(MatchError(ContainSubstring("pre-backup hook failing failed")))
+		Expect(backupCommand.Backup.Status.Hooks).To(HaveLen(1))
+		Expect(backupCommand.Backup.Status.Hooks[0].Error).ToNot(BeEmpty())
+		Expect(backupCommand.Backup.Status.Hooks[0].Ignored).To(BeFalse())
+	})
+
+	It("goes on when the failure policy ignores the error", func() {
+		backupCommand = newBackupCommand(&apiv1.BackupHooks{
+			Pre: []apiv1.BackupHook{
+				{Name: "failing", Command: []string{"false"}, FailurePolicy: apiv1.BackupHookFailurePolicyIgnore},
+				{Name: "flush", Command: []string{"true"}},
+			},
+		})
+		Expect(backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePre)).To(Succeed())
+		Expect(backupCommand.Backup.Status.Hooks).To(HaveLen(2))
+		Expect(backupCommand.Backup.Status.Hooks[0].Ignored).To(BeTrue())
+		Expect(backupCommand.Backup.Status.Hooks[1].Error).To(BeEmpty())
+	})
+
+	It("executes every post-backup hook even when one fails", func() {
+		backupCommand = newBackupCommand(&apiv1.BackupHooks{
+			Post: []apiv1.BackupHook{
+				{Name: "failing", Command: []string{"false"}},
+				{Name: "thaw", Command: []string{"true"}},
+			},
+		})
+		err := backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePost)
+		Expect(err).To(MatchError(ContainSubstring("post-backup hook failing failed")))
+		Expect(backupCommand.Backup.Status.Hooks).To(HaveLen(2))
+	})
+
+	It("fails the hooks exceeding their timeout", func() {
+		backupCommand = newBackupCommand(&apiv1.BackupHooks{
+			Pre: []apiv1.BackupHook{{Name: "slow", Command: []string{"sleep", "10"}, Timeout: 1}},
+		})
+		err := backupCommand.runHooks(context.TODO(), apiv1.BackupHookStagePre)
+		Expect(err).To(MatchError(ContainSubstring("timeout of 1s expired")))
+	})
+})
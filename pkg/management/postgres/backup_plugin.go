@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+)
+
+// startPlugin initiates a backup for this instance delegating it to
+// the plugin running as a sidecar of the instance
+func (b *BackupCommand) startPlugin(ctx context.Context) error {
+	configuration := b.Backup.Spec.PluginConfiguration
+	if configuration == nil {
+		return fmt.Errorf("the plugin taking the backup is not specified")
+	}
+	if _, ok := b.Cluster.GetPlugin(configuration.Name); !ok {
+		return fmt.Errorf("the plugin %s is not declared in the cluster", configuration.Name)
+	}
+
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.Method = apiv1.BackupMethodPlugin
+	backupStatus.PluginName = configuration.Name
+	backupStatus.Phase = apiv1.BackupPhaseRunning
+
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		return fmt.Errorf("can't set backup as running: %v", err)
+	}
+
+	// Run the actual backup process
+	go b.runPlugin(ctx)
+
+	return nil
+}
+
+// runPlugin asks the plugin to take the backup and updates the status.
+// This method will take long time and is supposed to run inside a
+// dedicated goroutine.
+func (b *BackupCommand) runPlugin(ctx context.Context) {
+	b.Log.Info("Backup started", "method", apiv1.BackupMethodPlugin, "plugin", b.Backup.Status.PluginName)
+	b.backupStarted(ctx)
+	b.setupBackupStatistics()
+
+	err := b.runHooks(ctx, apiv1.BackupHookStagePre)
+	if err == nil {
+		err = b.takePluginBackup(ctx)
+	}
+
+	// The post-backup hooks are executed even if the backup failed,
+	// letting the applications resume their normal operations
+	if hookErr := b.runHooks(ctx, apiv1.BackupHookStagePost); hookErr != nil && err == nil {
+		err = hookErr
+	}
+
+	if err != nil {
+		b.backupFailed(ctx, err)
+		return
+	}
+
+	b.backupCompleted(ctx)
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup status as completed")
+	}
+
+	if backupStatus := b.Backup.GetStatus(); backupStatus.Statistics != nil {
+		if err := b.setClusterLastBackupStatistics(ctx, backupStatus.Statistics); err != nil {
+			b.Log.Error(err, "Can't update the statistics of the last backup")
+		}
+	}
+}
+
+// takePluginBackup calls the plugin taking the backup, storing in the
+// backup status the information it returns
+func (b *BackupCommand) takePluginBackup(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+	name := backupStatus.PluginName
+
+	client, err := plugin.Connect(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			b.Log.Error(err, "Error while closing the connection to the plugin")
+		}
+	}()
+
+	response, err := client.Backup.Backup(ctx, &pluginapi.BackupRequest{
+		Cluster:    plugin.NewClusterInfo(b.Cluster.Namespace, b.Cluster.Name, b.Instance.PodName),
+		BackupName: b.Backup.Name,
+		Parameters: b.Cluster.GetPluginParameters(name, b.Backup.Spec.PluginConfiguration.Parameters),
+		PgData:     b.Instance.PgData,
+	})
+	if err != nil {
+		return fmt.Errorf("while taking the backup with the plugin %s: %w", name, err)
+	}
+
+	updatePluginBackupStatus(backupStatus, response)
+	return nil
+}
+
+// updatePluginBackupStatus stores in the backup status the information
+// returned by the plugin which took the backup
+func updatePluginBackupStatus(backupStatus *apiv1.BackupStatus, response *pluginapi.BackupResponse) {
+	backupStatus.BackupID = response.GetBackupId()
+	backupStatus.ServerName = response.GetServerName()
+	backupStatus.BeginWal = response.GetBeginWal()
+	backupStatus.EndWal = response.GetEndWal()
+	backupStatus.BeginLSN = response.GetBeginLsn()
+	backupStatus.EndLSN = response.GetEndLsn()
+	backupStatus.PluginMetadata = response.GetMetadata()
+
+	online := response.GetOnline()
+	backupStatus.Online = &online
+
+	if response.GetStartedAt() != 0 {
+		backupStatus.StartedAt = &metav1.Time{Time: time.Unix(response.GetStartedAt(), 0)}
+	}
+	if response.GetStoppedAt() != 0 {
+		backupStatus.StoppedAt = &metav1.Time{Time: time.Unix(response.GetStoppedAt(), 0)}
+	}
+
+	if backupStatus.Statistics == nil {
+		backupStatus.Statistics = &apiv1.BackupStatistics{}
+	}
+	if size := response.GetSize(); size != 0 {
+		backupStatus.Statistics.Size = &size
+	}
+	if backupStatus.StartedAt != nil && backupStatus.StoppedAt != nil {
+		backupStatus.Statistics.Duration = &metav1.Duration{
+			Duration: backupStatus.StoppedAt.Sub(backupStatus.StartedAt.Time),
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("plugin backup status", func() {
+	It("stores the information returned by the plugin", func() {
+		startedAt := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+		backupStatus := &apiv1.BackupStatus{}
+		updatePluginBackupStatus(backupStatus, &pluginapi.BackupResponse{
+			BackupId:   "20231001T100000F",
+			ServerName: "cluster-example",
+			BeginWal:   "000000010000000000000002",
+			EndWal:     "000000010000000000000003",
+			BeginLsn:   "0/2000028",
+			EndLsn:     "0/3000100",
+			StartedAt:  startedAt.Unix(),
+			StoppedAt:  startedAt.Add(time.Minute).Unix(),
+			Online:     true,
+			Size:       1024,
+			Metadata:   map[string]string{"repository": "repo1"},
+		})
+
+		Expect(backupStatus.BackupID).To(Equal("20231001T100000F"))
+		Expect(backupStatus.ServerName).To(Equal("cluster-example"))
+		Expect(backupStatus.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(backupStatus.EndLSN).To(Equal("0/3000100"))
+		Expect(*backupStatus.Online).To(BeTrue())
+		Expect(backupStatus.StartedAt.Time.Equal(startedAt)).To(BeTrue())
+		Expect(*backupStatus.Statistics.Size).To(BeEquivalentTo(1024))
+		Expect(backupStatus.Statistics.Duration.Duration).To(Equal(time.Minute))
+		Expect(backupStatus.PluginMetadata).To(HaveKeyWithValue("repository", "repo1"))
+	})
+
+	It("doesn't set the times and the size not reported by the plugin", func() {
+		backupStatus := &apiv1.BackupStatus{}
+		updatePluginBackupStatus(backupStatus, &pluginapi.BackupResponse{BackupId: "backup"})
+		Expect(backupStatus.StartedAt).To(BeNil())
+		Expect(backupStatus.StoppedAt).To(BeNil())
+		Expect(backupStatus.Statistics.Size).To(BeNil())
+		Expect(backupStatus.Statistics.Duration).To(BeNil())
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup statistics", func() {
+	var backupCommand *BackupCommand
+
+	beginTime := time.Date(2022, 10, 20, 11, 52, 31, 0, time.UTC)
+
+	BeforeEach(func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: "default"},
+			Status: apiv1.BackupStatus{
+				Statistics: &apiv1.BackupStatistics{DataSize: 4096},
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, backup).
+			Build()
+
+		backupCommand = NewBackupCommand(cluster, backup, cli, record.NewFakeRecorder(10), nil, log.GetLogger())
+	})
+
+	It("uses the copy time reported by Barman as duration", func() {
+		backupCommand.updateCompletedBackupStatus(&catalog.Catalog{List: []catalog.BarmanBackup{
+			{
+				ID:               "20221020T115231",
+				BeginTime:        beginTime,
+				EndTime:          beginTime.Add(time.Minute),
+				Size:             pointer.Int64(2048),
+				DeduplicatedSize: pointer.Int64(1024),
+				CopyStats:        &catalog.BarmanCopyStats{TotalTime: 4.5},
+			},
+		}})
+
+		statistics := backupCommand.Backup.Status.Statistics
+		Expect(statistics.DataSize).To(BeEquivalentTo(4096))
+		Expect(*statistics.Size).To(BeEquivalentTo(2048))
+		Expect(*statistics.DeduplicatedSize).To(BeEquivalentTo(1024))
+		Expect(statistics.Duration.Duration).To(Equal(4500 * time.Millisecond))
+	})
+
+	It("falls back to the backup times when the copy time is not reported", func() {
+		backupCommand.updateCompletedBackupStatus(&catalog.Catalog{List: []catalog.BarmanBackup{
+			{
+				ID:        "20221020T115231",
+				BeginTime: beginTime,
+				EndTime:   beginTime.Add(time.Minute),
+			},
+		}})
+
+		statistics := backupCommand.Backup.Status.Statistics
+		Expect(statistics.Size).To(BeNil())
+		Expect(statistics.DeduplicatedSize).To(BeNil())
+		Expect(statistics.Duration.Duration).To(Equal(time.Minute))
+	})
+
+	It("stores the statistics of the last backup in the cluster status", func() {
+		ctx := context.TODO()
+		statistics := &apiv1.BackupStatistics{DataSize: 4096, Size: pointer.Int64(2048)}
+		Expect(backupCommand.setClusterLastBackupStatistics(ctx, statistics)).To(Succeed())
+
+		var cluster apiv1.Cluster
+		Expect(backupCommand.Client.Get(
+			ctx,
+			types.NamespacedName{Name: "cluster-example", Namespace: "default"},
+			&cluster,
+		)).To(Succeed())
+		Expect(cluster.Status.LastBackupStatistics).To(Equal(statistics))
+	})
+})
@@ -0,0 +1,311 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// volumeSnapshotPollInterval is the time between two checks of the
+// progress of a VolumeSnapshot
+var volumeSnapshotPollInterval = 5 * time.Second
+
+// startVolumeSnapshot initiates a backup for this instance taking a
+// snapshot of its volumes
+func (b *BackupCommand) startVolumeSnapshot(ctx context.Context) error {
+	if !b.Cluster.Spec.Backup.IsVolumeSnapshotBackupConfigured() {
+		return fmt.Errorf("the volumeSnapshot backup method is not configured in the cluster")
+	}
+
+	configuration := b.Cluster.Spec.Backup.VolumeSnapshot
+	backupStatus := b.Backup.GetStatus()
+
+	// The WAL archive, when present, is recorded in the backup status
+	// as it is used to recover from an online backup
+	if b.Cluster.Spec.Backup.IsBarmanBackupConfigured() {
+		b.setupBackupStatus()
+	} else {
+		backupStatus.Method = apiv1.BackupMethodVolumeSnapshot
+		backupStatus.Phase = apiv1.BackupPhaseRunning
+	}
+	online := configuration.IsOnline()
+	backupStatus.Online = &online
+
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		return fmt.Errorf("can't set backup as running: %v", err)
+	}
+
+	// pg_backup_stop waits for the WAL files of an online backup
+	// to be archived
+	if online {
+		if err := waitForWalArchiveWorking(); err != nil {
+			b.Log.Info("WAL archiving is not working")
+			backupStatus.Phase = apiv1.BackupPhaseWalArchivingFailing
+			return UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup)
+		}
+	}
+
+	// Run the actual backup process
+	go b.runVolumeSnapshot(ctx)
+
+	return nil
+}
+
+// runVolumeSnapshot takes the snapshots of the volumes and updates the
+// status. This method will take long time and is supposed to run inside
+// a dedicated goroutine.
+func (b *BackupCommand) runVolumeSnapshot(ctx context.Context) {
+	b.Log.Info("Backup started", "method", apiv1.BackupMethodVolumeSnapshot)
+	b.backupStarted(ctx)
+	b.setupBackupStatistics()
+
+	err := b.runHooks(ctx, apiv1.BackupHookStagePre)
+	if err == nil {
+		err = b.takeVolumeSnapshots(ctx)
+	}
+
+	// The post-backup hooks are executed even if the backup failed,
+	// letting the applications resume their normal operations
+	if hookErr := b.runHooks(ctx, apiv1.BackupHookStagePost); hookErr != nil && err == nil {
+		err = hookErr
+	}
+
+	if err != nil {
+		b.backupFailed(ctx, err)
+		return
+	}
+
+	b.backupCompleted(ctx)
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup status as completed")
+	}
+
+	if backupStatus := b.Backup.GetStatus(); backupStatus.Statistics != nil {
+		if err := b.setClusterLastBackupStatistics(ctx, backupStatus.Statistics); err != nil {
+			b.Log.Error(err, "Can't update the statistics of the last backup")
+		}
+	}
+}
+
+// takeVolumeSnapshots snapshots the PGDATA volume and, when present, the WAL
+// volume of the instance, waiting for the snapshots to be ready to use
+func (b *BackupCommand) takeVolumeSnapshots(ctx context.Context) error {
+	configuration := b.Cluster.Spec.Backup.VolumeSnapshot
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.StartedAt = &metav1.Time{Time: time.Now()}
+
+	var err error
+	if configuration.IsOnline() {
+		err = b.takeOnlineVolumeSnapshots(ctx)
+	} else {
+		err = b.takeCrashConsistentVolumeSnapshots(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	backupStatus.StoppedAt = &metav1.Time{Time: time.Now()}
+	if backupStatus.Statistics == nil {
+		backupStatus.Statistics = &apiv1.BackupStatistics{}
+	}
+	backupStatus.Statistics.Duration = &metav1.Duration{
+		Duration: backupStatus.StoppedAt.Sub(backupStatus.StartedAt.Time),
+	}
+
+	// The snapshots are stored in the status as soon as they are taken,
+	// and the backup is not usable until the data has been copied
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't store the volume snapshots in the backup status")
+	}
+	for _, snapshot := range backupStatus.Snapshots {
+		if err := b.waitForVolumeSnapshot(ctx, snapshot.Name, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeCrashConsistentVolumeSnapshots snapshots the PGDATA volume while
+// PostgreSQL is running. Restoring such a backup requires a crash recovery.
+// The volumes are snapshotted one at a time, so the snapshots of the WAL
+// and of the tablespaces volumes wouldn't be consistent with the PGDATA one
+func (b *BackupCommand) takeCrashConsistentVolumeSnapshots(ctx context.Context) error {
+	if b.Cluster.ShouldCreateWalArchiveVolume() || len(b.Cluster.Spec.Tablespaces) > 0 {
+		return fmt.Errorf("crash-consistent volume snapshot backups can't be taken when the WAL files " +
+			"or the tablespaces are stored in dedicated volumes")
+	}
+
+	return b.takeVolumeSnapshot(ctx, utils.PVCRolePgData)
+}
+
+// takeOnlineVolumeSnapshots snapshots the PGDATA and the tablespaces volumes
+// between pg_backup_start and pg_backup_stop, and the WAL volume after the
+// end of the backup. The backup is aborted by PostgreSQL when the session used to
+// start it is closed
+func (b *BackupCommand) takeOnlineVolumeSnapshots(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+
+	postgresVersion, err := b.Instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	db, err := b.Instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			b.Log.Error(err, "Error while closing the backup connection")
+		}
+	}()
+
+	startQuery, stopQuery := "SELECT pg_backup_start($1, $2)",
+		"SELECT lsn, labelfile, spcmapfile FROM pg_backup_stop(true)"
+	if postgresVersion.Major < 15 {
+		startQuery, stopQuery = "SELECT pg_start_backup($1, $2, false)",
+			"SELECT lsn, labelfile, spcmapfile FROM pg_stop_backup(false, true)"
+	}
+
+	b.Log.Info("Starting the online backup")
+	row := conn.QueryRowContext(ctx, startQuery,
+		b.Backup.Name, b.Cluster.Spec.Backup.VolumeSnapshot.ImmediateCheckpoint)
+	if err := row.Scan(&backupStatus.BeginLSN); err != nil {
+		return fmt.Errorf("while starting the online backup: %w", err)
+	}
+
+	if err := b.takeVolumeSnapshot(ctx, utils.PVCRolePgData); err != nil {
+		return err
+	}
+
+	if err := b.takeTablespacesVolumeSnapshots(ctx); err != nil {
+		return err
+	}
+
+	b.Log.Info("Stopping the online backup")
+	var tablespaceMap sql.NullString
+	var backupLabel string
+	row = conn.QueryRowContext(ctx, stopQuery)
+	if err := row.Scan(&backupStatus.EndLSN, &backupLabel, &tablespaceMap); err != nil {
+		return fmt.Errorf("while stopping the online backup: %w", err)
+	}
+	backupStatus.BackupLabelFile = []byte(backupLabel)
+	if tablespaceMap.Valid {
+		backupStatus.TablespaceMapFile = []byte(tablespaceMap.String)
+	}
+
+	if b.Cluster.ShouldCreateWalArchiveVolume() {
+		return b.takeVolumeSnapshot(ctx, utils.PVCRolePgWal)
+	}
+
+	return nil
+}
+
+// takeVolumeSnapshot creates the snapshot of the volume of the instance
+// with the passed role, waiting for the point-in-time copy to be taken
+func (b *BackupCommand) takeVolumeSnapshot(ctx context.Context, role utils.PVCRole) error {
+	configuration := b.Cluster.Spec.Backup.VolumeSnapshot
+	className := configuration.ClassName
+	if role == utils.PVCRolePgWal {
+		className = configuration.GetWalClassName()
+	}
+
+	pvcName := specs.GetPVCName(*b.Cluster, b.Instance.PodName, role)
+	snapshot := specs.CreateVolumeSnapshot(*b.Backup, pvcName, role, className)
+	return b.createVolumeSnapshot(ctx, pvcName, snapshot, apiv1.BackupSnapshotElementStatus{
+		Name: snapshot.GetName(),
+		Type: string(role),
+	})
+}
+
+// takeTablespacesVolumeSnapshots creates the snapshots of the volumes of
+// the instance storing the tablespaces
+func (b *BackupCommand) takeTablespacesVolumeSnapshots(ctx context.Context) error {
+	className := b.Cluster.Spec.Backup.VolumeSnapshot.ClassName
+	for _, tablespace := range b.Cluster.Spec.Tablespaces {
+		pvcName := specs.GetTablespacePVCName(b.Instance.PodName, tablespace.Name)
+		snapshot := specs.CreateTablespaceVolumeSnapshot(*b.Backup, pvcName, tablespace.Name, className)
+		if err := b.createVolumeSnapshot(ctx, pvcName, snapshot, apiv1.BackupSnapshotElementStatus{
+			Name:           snapshot.GetName(),
+			Type:           string(utils.PVCRolePgTablespace),
+			TablespaceName: tablespace.Name,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createVolumeSnapshot creates the passed snapshot of a volume, adding it
+// to the backup status and waiting for the point-in-time copy to be taken
+func (b *BackupCommand) createVolumeSnapshot(
+	ctx context.Context,
+	pvcName string,
+	snapshot *unstructured.Unstructured,
+	snapshotStatus apiv1.BackupSnapshotElementStatus,
+) error {
+	b.Log.Info("Taking the volume snapshot", "pvc", pvcName, "snapshot", snapshot.GetName())
+	if err := b.Client.Create(ctx, snapshot); err != nil {
+		return fmt.Errorf("while creating the volume snapshot of %s: %w", pvcName, err)
+	}
+
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.Snapshots = append(backupStatus.Snapshots, snapshotStatus)
+
+	return b.waitForVolumeSnapshot(ctx, snapshot.GetName(), false)
+}
+
+// waitForVolumeSnapshot waits for the passed VolumeSnapshot to be taken or,
+// when requested, to be ready to use
+func (b *BackupCommand) waitForVolumeSnapshot(ctx context.Context, name string, waitForReady bool) error {
+	return wait.PollImmediateInfiniteWithContext(ctx, volumeSnapshotPollInterval,
+		func(ctx context.Context) (bool, error) {
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetGroupVersionKind(specs.VolumeSnapshotGVK)
+			if err := b.Client.Get(ctx, types.NamespacedName{Namespace: b.Backup.Namespace, Name: name},
+				snapshot); err != nil {
+				return false, err
+			}
+
+			status, err := specs.GetVolumeSnapshotStatus(snapshot)
+			if err != nil {
+				return false, err
+			}
+			if waitForReady {
+				return status.Ready, nil
+			}
+			return status.Taken, nil
+		})
+}
@@ -22,6 +22,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -57,7 +58,7 @@ func InstallPgDataFileContent(pgdata, contents, destinationFile string) (bool, e
 func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster)
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, instance.PodName)
 	if err != nil {
 		return false, err
 	}
@@ -98,8 +99,15 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 		defaultAuthenticationMethod = "md5"
 	}
 
+	// The rules rendered by the operator from the structured rules come
+	// after the ones written by the user
+	hbaRules := make([]string, 0,
+		len(cluster.Spec.PostgresConfiguration.PgHBA)+len(cluster.Status.PgHBARules))
+	hbaRules = append(hbaRules, cluster.Spec.PostgresConfiguration.PgHBA...)
+	hbaRules = append(hbaRules, cluster.Status.PgHBARules...)
+
 	return postgres.CreateHBARules(
-		cluster.Spec.PostgresConfiguration.PgHBA,
+		hbaRules,
 		defaultAuthenticationMethod,
 		buildLDAPConfigString(cluster, ldapBindPassword))
 }
@@ -135,7 +143,7 @@ func buildLDAPConfigString(cluster *apiv1.Cluster, ldapBindPassword string) stri
 	}
 	ldapConfig := cluster.Spec.PostgresConfiguration.LDAP
 
-	ldapConfigString += fmt.Sprintf("host all all 0.0.0.0/0 ldap ldapserver=%s", ldapConfig.Server)
+	ldapConfigString += fmt.Sprintf("host all all all ldap ldapserver=%s", ldapConfig.Server)
 
 	if ldapConfig.Port != 0 {
 		ldapConfigString += fmt.Sprintf(" ldapport=%d", ldapConfig.Port)
@@ -287,8 +295,8 @@ func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err err
 }
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
-// used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, error) {
+// used for the passed instance of this cluster and return it and its sha256 checksum
+func createPostgresqlConfiguration(cluster *apiv1.Cluster, instanceName string) (string, string, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
@@ -298,11 +306,14 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 	info := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     fromVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetInstancePostgresqlParameters(instanceName),
 		IncludingMandatory:               true,
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
+		TemporaryTablespaces:             cluster.GetTemporaryTablespaceNames(),
+		IsWalLogHintsRequired:            cluster.IsWalLogHintsRequired(),
+		ArchiveTimeout:                   cluster.Spec.Backup.GetArchiveTimeout(),
 	}
 
 	// Compute the actual number of sync replicas
@@ -313,6 +324,18 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 	// Ensure a consistent ordering to avoid spurious configuration changes
 	sort.Strings(info.SyncReplicasElectable)
 
+	if synchronous := cluster.Spec.PostgresConfiguration.Synchronous; synchronous != nil {
+		info.SyncReplicasMethod = strings.ToUpper(string(synchronous.Method))
+		info.SyncReplicasStandbyNamesPre = synchronous.StandbyNamesPre
+		info.SyncReplicasStandbyNamesPost = synchronous.StandbyNamesPost
+	}
+
+	// Keep the logical replication slots flagged for failover on the replicas
+	if cluster.Spec.PostgresConfiguration.LogicalDecoding.AreFailoverSlotsEnabled() {
+		info.SynchronizeLogicalSlots = true
+		info.SynchronizedStandbySlots = cluster.GetSynchronizedStandbySlots(instanceName)
+	}
+
 	// Set cluster name
 	info.ClusterName = cluster.Name
 
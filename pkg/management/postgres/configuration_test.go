@@ -81,7 +81,7 @@ var _ = Describe("testing the building of the ldap config string", func() {
 	})
 	It("correctly builds a bindSearchAuth string", func() {
 		str := buildLDAPConfigString(&cluster, ldapPassword)
-		Expect(str).To(Equal(fmt.Sprintf("host all all 0.0.0.0/0 ldap ldapserver=%s ldapport=%d "+
+		Expect(str).To(Equal(fmt.Sprintf("host all all all ldap ldapserver=%s ldapport=%d "+
 			"ldapscheme=%s ldaptls=1 ldapbasedn=\"%s\" ldapbinddn=\"%s\" "+
 			"ldapbindpasswd=%s ldapsearchfilter=%s ldapsearchattribute=%s", ldapServer, ldapPort, ldapScheme,
 			ldapBaseDN, ldapBindDN, ldapPassword, ldapSearchFilter, ldapSearchAttribute)))
@@ -94,7 +94,40 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			Suffix: ldapSuffix,
 		}
 		str := buildLDAPConfigString(baaCluster, ldapPassword)
-		Expect(str).To(Equal(fmt.Sprintf("host all all 0.0.0.0/0 ldap ldapserver=%s ldapport=%d ldapscheme=%s "+
+		Expect(str).To(Equal(fmt.Sprintf("host all all all ldap ldapserver=%s ldapport=%d ldapscheme=%s "+
 			"ldaptls=1 ldapprefix=\"%s\" ldapsuffix=\"%s\"", ldapServer, ldapPort, ldapScheme, ldapPrefix, ldapSuffix)))
 	})
 })
+
+var _ = Describe("pg_hba and pg_ident generation", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:16",
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				PgHBA: []string{"host app app 10.0.0.0/8 md5"},
+				PgIdent: []apiv1.PgIdentMap{
+					{MapName: "certs", SystemUsername: "app.example.com", DatabaseUsername: "app"},
+				},
+			},
+		},
+		Status: apiv1.ClusterStatus{
+			PgHBARules: []string{
+				"hostssl all all all cert map=certs",
+				"host all all 192.168.1.10/32 scram-sha-256",
+			},
+		},
+	}
+
+	It("appends the rendered rules after the ones written by the user", func() {
+		instance := &Instance{}
+		hba, err := instance.GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(MatchRegexp(
+			`(?s)host app app 10\.0\.0\.0/8 md5.*hostssl all all all cert map=certs.*192\.168\.1\.10/32`))
+	})
+
+	It("adds the user name maps after the local one", func() {
+		Expect(generatePostgresUserMaps(cluster)).To(MatchRegexp(
+			"^local \\S+ postgres\ncerts app.example.com app\n$"))
+	})
+})
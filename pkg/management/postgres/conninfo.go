@@ -24,7 +24,8 @@ import (
 )
 
 // buildPrimaryConnInfo builds the connection string to connect to primaryHostname
-func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
+// using the passed libpq "sslmode"
+func buildPrimaryConnInfo(primaryHostname, applicationName, sslMode string) string {
 	// We should have been using configfile.CreateConnectionString
 	// but doing that we would cause an unnecessary restart of
 	// existing PostgreSQL 12 clusters.
@@ -35,6 +36,15 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		fmt.Sprintf("sslcert=%v ", postgres.StreamingReplicaCertificateLocation) +
 		fmt.Sprintf("sslrootcert=%v ", postgres.ServerCACertificateLocation) +
 		fmt.Sprintf("application_name=%v ", applicationName) +
-		"sslmode=verify-ca"
+		fmt.Sprintf("sslmode=%v", sslMode)
 	return primaryConnInfo
 }
+
+// BuildLocalReplicationConnInfo creates the connection string that a
+// replication client running in the same Pod of the instance can use
+// to stream the WAL files, authenticating as the streaming replica user.
+// The host name cannot be verified, as the server certificate doesn't
+// contain "localhost"
+func BuildLocalReplicationConnInfo(applicationName string) string {
+	return buildPrimaryConnInfo("localhost", applicationName, "verify-ca")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ErrDatabaseNotAlterable is raised when an existing database doesn't
+// match the fields of its specification which can be set only at
+// creation time
+var ErrDatabaseNotAlterable = errors.New("the database cannot be altered to match its specification")
+
+// ErrExtensionNotAvailable is raised when an extension to be created in a
+// database is not available in the instance
+var ErrExtensionNotAvailable = errors.New("the extension is not available")
+
+// existingDatabase is the state of a database inside PostgreSQL
+type existingDatabase struct {
+	owner    string
+	encoding string
+	collate  string
+}
+
+// buildCreateDatabaseStatement builds the statement creating the passed
+// database. The template0 database is used, as it allows choosing any
+// encoding and locale
+func buildCreateDatabaseStatement(spec apiv1.DatabaseSpec) string {
+	statement := fmt.Sprintf(
+		"CREATE DATABASE %s OWNER %s TEMPLATE template0 ENCODING %s",
+		pgx.Identifier{spec.Name}.Sanitize(),
+		pgx.Identifier{spec.Owner}.Sanitize(),
+		pq.QuoteLiteral(spec.GetEncoding()))
+	if spec.Locale != "" {
+		statement += fmt.Sprintf(
+			" LC_COLLATE %s LC_CTYPE %s",
+			pq.QuoteLiteral(spec.Locale),
+			pq.QuoteLiteral(spec.Locale))
+	}
+	return statement
+}
+
+// checkDatabaseAlterable verifies that an existing database has the
+// encoding and the locale required by its specification
+func checkDatabaseAlterable(spec apiv1.DatabaseSpec, database existingDatabase) error {
+	if !strings.EqualFold(database.encoding, spec.GetEncoding()) {
+		return fmt.Errorf("%w: the encoding is %s instead of %s",
+			ErrDatabaseNotAlterable, database.encoding, spec.GetEncoding())
+	}
+
+	if spec.Locale != "" && database.collate != spec.Locale {
+		return fmt.Errorf("%w: the locale is %s instead of %s",
+			ErrDatabaseNotAlterable, database.collate, spec.Locale)
+	}
+
+	return nil
+}
+
+// ReconcileDatabase creates or alters a database to match the passed
+// specification, then creates its schemas and extensions.
+// Schemas and extensions are never dropped
+func (instance *Instance) ReconcileDatabase(ctx context.Context, spec apiv1.DatabaseSpec) error {
+	contextLogger := log.FromContext(ctx).WithValues("database", spec.Name)
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting superuser database: %w", err)
+	}
+
+	var database existingDatabase
+	row := superUserDB.QueryRowContext(ctx,
+		"SELECT pg_catalog.pg_get_userbyid(datdba), pg_catalog.pg_encoding_to_char(encoding), datcollate "+
+			"FROM pg_catalog.pg_database WHERE datname = $1",
+		spec.Name)
+	err = row.Scan(&database.owner, &database.encoding, &database.collate)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		contextLogger.Info("Creating database")
+		if _, err = superUserDB.ExecContext(ctx, buildCreateDatabaseStatement(spec)); err != nil {
+			return fmt.Errorf("while creating database %s: %w", spec.Name, err)
+		}
+
+	case err != nil:
+		return fmt.Errorf("while reading database %s: %w", spec.Name, err)
+
+	default:
+		if err = checkDatabaseAlterable(spec, database); err != nil {
+			return err
+		}
+
+		if database.owner != spec.Owner {
+			contextLogger.Info("Changing the owner of the database", "owner", spec.Owner)
+			if _, err = superUserDB.ExecContext(ctx, fmt.Sprintf(
+				"ALTER DATABASE %s OWNER TO %s",
+				pgx.Identifier{spec.Name}.Sanitize(),
+				pgx.Identifier{spec.Owner}.Sanitize())); err != nil {
+				return fmt.Errorf("while changing the owner of database %s: %w", spec.Name, err)
+			}
+		}
+	}
+
+	if len(spec.Schemas) == 0 && len(spec.Extensions) == 0 {
+		return nil
+	}
+
+	db, err := instance.ConnectionPool().Connection(spec.Name)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", spec.Name, err)
+	}
+
+	for _, schema := range spec.Schemas {
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(
+			"CREATE SCHEMA IF NOT EXISTS %s AUTHORIZATION %s",
+			pgx.Identifier{schema}.Sanitize(),
+			pgx.Identifier{spec.Owner}.Sanitize())); err != nil {
+			return fmt.Errorf("while creating schema %s: %w", schema, err)
+		}
+	}
+
+	for _, extension := range spec.Extensions {
+		var available bool
+		row := db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_available_extensions WHERE name = $1) "+
+				"OR EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = $1)",
+			extension)
+		if err = row.Scan(&available); err != nil {
+			return fmt.Errorf("while checking the availability of extension %s: %w", extension, err)
+		}
+		if !available {
+			return fmt.Errorf("%w: %s needs to be included in the image or in the extensions of the cluster",
+				ErrExtensionNotAvailable, extension)
+		}
+
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(
+			"CREATE EXTENSION IF NOT EXISTS %s",
+			pgx.Identifier{extension}.Sanitize())); err != nil {
+			return fmt.Errorf("while creating extension %s: %w", extension, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative databases", func() {
+	spec := apiv1.DatabaseSpec{
+		Name:  "catalog",
+		Owner: "catalog_owner",
+	}
+
+	It("creates the database from template0 with the default encoding", func() {
+		Expect(buildCreateDatabaseStatement(spec)).To(Equal(
+			`CREATE DATABASE "catalog" OWNER "catalog_owner" TEMPLATE template0 ENCODING 'UTF8'`))
+	})
+
+	It("creates the database with the requested locale", func() {
+		withLocale := spec
+		withLocale.Encoding = "LATIN1"
+		withLocale.Locale = "it_IT"
+		Expect(buildCreateDatabaseStatement(withLocale)).To(Equal(
+			`CREATE DATABASE "catalog" OWNER "catalog_owner" TEMPLATE template0 ENCODING 'LATIN1' ` +
+				`LC_COLLATE 'it_IT' LC_CTYPE 'it_IT'`))
+	})
+
+	It("quotes the identifiers and the literals", func() {
+		quoted := apiv1.DatabaseSpec{Name: `my"db`, Owner: "owner", Locale: "it_IT'"}
+		Expect(buildCreateDatabaseStatement(quoted)).To(Equal(
+			`CREATE DATABASE "my""db" OWNER "owner" TEMPLATE template0 ENCODING 'UTF8' ` +
+				`LC_COLLATE 'it_IT''' LC_CTYPE 'it_IT'''`))
+	})
+
+	It("accepts an existing database with the same encoding and locale", func() {
+		existing := existingDatabase{owner: "postgres", encoding: "UTF8", collate: "C"}
+		Expect(checkDatabaseAlterable(spec, existing)).To(Succeed())
+
+		withLocale := spec
+		withLocale.Encoding = "utf8"
+		withLocale.Locale = "C"
+		Expect(checkDatabaseAlterable(withLocale, existing)).To(Succeed())
+	})
+
+	It("refuses an existing database with a different encoding or locale", func() {
+		existing := existingDatabase{owner: "postgres", encoding: "LATIN1", collate: "C"}
+		Expect(checkDatabaseAlterable(spec, existing)).To(MatchError(ErrDatabaseNotAlterable))
+
+		withLocale := spec
+		withLocale.Encoding = "LATIN1"
+		withLocale.Locale = "it_IT"
+		Expect(checkDatabaseAlterable(withLocale, existing)).To(MatchError(ErrDatabaseNotAlterable))
+	})
+})
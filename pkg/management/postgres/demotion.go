@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// GetPgControldata gets the output of pg_controldata for this instance
+func (instance *Instance) GetPgControldata() (map[string]string, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgControlDataCmd := exec.Command(pgControlDataName,
+		"-D",
+		instance.PgData) // #nosec G204
+	pgControlDataCmd.Stdout = &stdoutBuffer
+	pgControlDataCmd.Stderr = &stderrBuffer
+	pgControlDataCmd.Env = append(os.Environ(), "LANG=C", "LC_MESSAGES=C")
+	if err := pgControlDataCmd.Run(); err != nil {
+		log.Error(err, "while reading pg_controldata",
+			"stderr", stderrBuffer.String(),
+			"stdout", stdoutBuffer.String())
+		return nil, err
+	}
+
+	return postgres.ParsePgControldataOutput(stdoutBuffer.String()), nil
+}
+
+// GetDemotionToken gets the encoded demotion token of this instance,
+// describing its latest checkpoint
+func (instance *Instance) GetDemotionToken() (string, error) {
+	controlData, err := instance.GetPgControldata()
+	if err != nil {
+		return "", err
+	}
+
+	token, err := postgres.NewDemotionTokenFromControlData(controlData)
+	if err != nil {
+		return "", err
+	}
+
+	return token.Encode()
+}
+
+// VerifyPromotionToken checks whether this replica has replayed the WALs
+// up to the checkpoint contained in the passed promotion token. A
+// checkpoint is requested before the check, to create the restartpoint
+// corresponding to the shutdown checkpoint of the former primary.
+// postgres.ErrPromotionTokenNotReached is returned while the WALs are
+// still being replayed
+func (instance *Instance) VerifyPromotionToken(promotionToken string) error {
+	token, err := postgres.DecodeDemotionToken(promotionToken)
+	if err != nil {
+		return err
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("CHECKPOINT"); err != nil {
+		return fmt.Errorf("while requesting a restartpoint: %w", err)
+	}
+
+	controlData, err := instance.GetPgControldata()
+	if err != nil {
+		return err
+	}
+
+	return token.ValidateAgainstControlData(controlData)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// clientBackendsFilter selects the client backends that are subject to
+// draining, excluding the ones opened by the instance manager itself
+const clientBackendsFilter = `backend_type = 'client backend'
+	AND pid <> pg_backend_pid()
+	AND application_name <> '` + instanceManagerApplicationName + `'`
+
+// drainPollingInterval is the interval between two checks of the active
+// client sessions while draining the connections
+const drainPollingInterval = 1 * time.Second
+
+// DrainConnections gracefully terminates the client sessions connected to
+// this instance. Idle sessions are terminated as soon as they are detected,
+// while active ones are given up to gracePeriod to complete before being
+// terminated
+func (instance *Instance) DrainConnections(ctx context.Context, gracePeriod time.Duration) error {
+	contextLogger := log.FromContext(ctx)
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(gracePeriod)
+	for {
+		terminated, err := terminateClientBackends(ctx, db, "state = 'idle'")
+		if err != nil {
+			return fmt.Errorf("while terminating idle sessions: %w", err)
+		}
+		if terminated > 0 {
+			contextLogger.Info("Terminated idle client sessions", "sessions", terminated)
+		}
+
+		var active int
+		row := db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM pg_catalog.pg_stat_activity WHERE "+clientBackendsFilter)
+		if err := row.Scan(&active); err != nil {
+			return fmt.Errorf("while counting active sessions: %w", err)
+		}
+
+		if active == 0 {
+			contextLogger.Info("All client sessions have been drained")
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			break
+		}
+
+		contextLogger.Info("Waiting for active client sessions to complete",
+			"sessions", active,
+			"deadline", deadline)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(drainPollingInterval):
+		}
+	}
+
+	terminated, err := terminateClientBackends(ctx, db, "TRUE")
+	if err != nil {
+		return fmt.Errorf("while terminating active sessions: %w", err)
+	}
+	contextLogger.Info("Grace period expired, terminated the remaining client sessions",
+		"sessions", terminated)
+
+	return nil
+}
+
+// terminateClientBackends terminates the client backends matching the passed
+// condition, returning the number of terminated sessions
+func terminateClientBackends(ctx context.Context, db *sql.DB, condition string) (int, error) {
+	var terminated int
+	row := db.QueryRowContext(ctx,
+		"SELECT COUNT(pg_catalog.pg_terminate_backend(pid)) FROM pg_catalog.pg_stat_activity WHERE "+
+			clientBackendsFilter+" AND "+condition)
+	if err := row.Scan(&terminated); err != nil {
+		return 0, err
+	}
+
+	return terminated, nil
+}
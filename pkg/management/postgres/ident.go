@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"os/user"
 	"path/filepath"
+	"strings"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
@@ -29,6 +31,48 @@ import (
 // WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
 // maps the current user to "postgres" user.
 func WritePostgresUserMaps(pgData string) error {
+	_, err := fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
+		generateLocalUserMap())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RefreshPGIdent writes down the pg_ident.conf file, containing the
+// "local" map followed by the user name maps of the cluster
+func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (postgresIdentChanged bool, err error) {
+	postgresIdentChanged, err = InstallPgDataFileContent(
+		instance.PgData,
+		generatePostgresUserMaps(cluster),
+		constants.PostgresqlIdentFile)
+	if err != nil {
+		return postgresIdentChanged, fmt.Errorf(
+			"installing postgresql user name maps: %w",
+			err)
+	}
+
+	return postgresIdentChanged, nil
+}
+
+// generatePostgresUserMaps generates the content of pg_ident.conf, with
+// the "local" map used by the operator and the ones of the cluster
+func generatePostgresUserMaps(cluster *apiv1.Cluster) string {
+	var result strings.Builder
+	result.WriteString(generateLocalUserMap())
+	for _, identMap := range cluster.Spec.PostgresConfiguration.PgIdent {
+		result.WriteString(fmt.Sprintf("%s %s %s\n",
+			identMap.MapName,
+			identMap.SystemUsername,
+			identMap.DatabaseUsername))
+	}
+	return result.String()
+}
+
+// generateLocalUserMap generates the "local" map, mapping the current
+// user to the "postgres" user
+func generateLocalUserMap() string {
 	var username string
 
 	currentUser, err := user.Current()
@@ -39,11 +83,5 @@ func WritePostgresUserMaps(pgData string) error {
 		username = currentUser.Username
 	}
 
-	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return fmt.Sprintf("local %s postgres\n", username)
 }
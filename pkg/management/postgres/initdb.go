@@ -93,6 +93,10 @@ type InitInfo struct {
 	// PostInitApplicationSQLRefsFolder is the folder which contains a bunch
 	// of SQL files to be executed just after having configured a new instance
 	PostInitApplicationSQLRefsFolder string
+
+	// AnonymizationSQLRefsFolder is the folder which contains a bunch
+	// of SQL files to be executed to anonymize the recovered data
+	AnonymizationSQLRefsFolder string
 }
 
 // VerifyPGData verifies if the passed configuration is OK, otherwise it returns an error
@@ -260,18 +264,24 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 }
 
 func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
-	if info.PostInitApplicationSQLRefsFolder == "" {
+	return info.executeSQLRefs(sqlUser, info.PostInitApplicationSQLRefsFolder)
+}
+
+// executeSQLRefs executes, in alphabetical order, the SQL files contained
+// in the passed folder. Nothing is done if the folder is empty
+func (info InitInfo) executeSQLRefs(sqlUser *sql.DB, folder string) error {
+	if folder == "" {
 		return nil
 	}
 
-	if err := fileutils.EnsureDirectoryExist(info.PostInitApplicationSQLRefsFolder); err != nil {
-		return fmt.Errorf("could not find directory: %s, err: %w", info.PostInitApplicationSQLRefsFolder, err)
+	if err := fileutils.EnsureDirectoryExist(folder); err != nil {
+		return fmt.Errorf("could not find directory: %s, err: %w", folder, err)
 	}
 
-	files, err := fileutils.GetDirectoryContent(info.PostInitApplicationSQLRefsFolder)
+	files, err := fileutils.GetDirectoryContent(folder)
 	if err != nil {
 		return fmt.Errorf("could not get directory content from: %s, err: %w",
-			info.PostInitApplicationSQLRefsFolder, err)
+			folder, err)
 	}
 
 	// Sorting ensures that we execute the files in the correct order.
@@ -279,7 +289,7 @@ func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
 	sort.Strings(files)
 
 	for _, file := range files {
-		sql, ioErr := fileutils.ReadFile(path.Join(info.PostInitApplicationSQLRefsFolder, file))
+		sql, ioErr := fileutils.ReadFile(path.Join(folder, file))
 		if ioErr != nil {
 			return fmt.Errorf("could not read file: %s, err; %w", file, err)
 		}
@@ -322,6 +332,10 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		return err
 	}
 
+	if err := info.RunStorageBenchmark(ctx, typedClient, cluster); err != nil {
+		return err
+	}
+
 	err = info.CreateDataDirectory()
 	if err != nil {
 		return err
@@ -337,7 +351,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	configurationInfo := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     postgresVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetPostgresqlParameters(),
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
 		IncludingSharedPreloadLibraries:  true,
@@ -352,7 +366,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	}
 
 	if postgresVersion >= 120000 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetPrimaryConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -397,9 +411,9 @@ func executeLogicalImport(
 	cloneType := cluster.Spec.Bootstrap.InitDB.Import.Type
 	switch cloneType {
 	case apiv1.MicroserviceSnapshotType:
-		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Microservice(ctx, client, cluster, destinationPool, originPool)
 	case apiv1.MonolithSnapshotType:
-		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Monolith(ctx, client, cluster, destinationPool, originPool)
 	default:
 		return fmt.Errorf("unrecognized clone type %s", cloneType)
 	}
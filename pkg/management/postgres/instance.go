@@ -164,9 +164,33 @@ type Instance struct {
 	// PgRewindIsRunning tells if there is a `pg_rewind` process running
 	PgRewindIsRunning bool
 
+	// PgBaseBackupIsRunning tells if the data directory is being cloned
+	// again from the primary
+	PgBaseBackupIsRunning bool
+
 	// MaxStopDelay is the current MaxStopDelay of the cluster
 	MaxStopDelay int32
 
+	// ShutdownCheckpointEnabled tells if a checkpoint should be requested
+	// before the planned restarts and switchovers
+	ShutdownCheckpointEnabled bool
+
+	// ShutdownCheckpointMaxWalSize is the value of max_wal_size applied
+	// from the checkpoint requested before a planned shutdown
+	ShutdownCheckpointMaxWalSize string
+
+	// LongRunningTransactionsThreshold is the age, in seconds, over which
+	// a transaction is considered long-running
+	LongRunningTransactionsThreshold int32
+
+	// ReplicationSSLMode is the libpq "sslmode" used to connect
+	// to the primary, defaulting to "verify-ca"
+	ReplicationSSLMode string
+
+	// Plugins is the list of the names of the plugins running as
+	// sidecars of the instance
+	Plugins []string
+
 	// canCheckReadiness specifies whether the instance can start being checked for readiness
 	// Is set to true before the instance is run and to false once it exits,
 	// it's used by the readiness probe to know whether it should be short-circuited
@@ -181,6 +205,19 @@ type Instance struct {
 
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
+
+	// instanceHooks contains the hooks executed when the role or the
+	// state of the instance changes
+	instanceHooks atomic.Value
+
+	// storageGrowth tracks the usage of the volumes of the instance
+	storageGrowth storageGrowthTracker
+
+	// pluginStatus keeps the status of the plugins of the instance
+	pluginStatus pluginStatusTracker
+
+	// lockChains keeps the sessions involved in lock chains
+	lockChains lockChainsTracker
 }
 
 // IsFenced checks whether the instance is marked as fenced
@@ -439,6 +476,14 @@ func (instance *Instance) Run() (*execlog.StreamingCmd, error) {
 	// We don't have a postmaster running and we need to create
 	// one.
 
+	if err := instance.removePersistedReadOnly(); err != nil {
+		return nil, err
+	}
+
+	if err := instance.removeShutdownMaxWalSize(); err != nil {
+		return nil, err
+	}
+
 	socketDir := GetSocketDir()
 	if err := fileutils.EnsureDirectoryExist(socketDir); err != nil {
 		return nil, fmt.Errorf("while creating socket directory: %w", err)
@@ -522,9 +567,12 @@ func (instance *Instance) GetPgVersion() (semver.Version, error) {
 	return *parsedVersion, nil
 }
 
+// instanceManagerApplicationName is the application name used by the
+// connections opened by the instance manager
+const instanceManagerApplicationName = "cnpg-instance-manager"
+
 // ConnectionPool gets or initializes the connection pool for this instance
 func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
-	const applicationName = "cnpg-instance-manager"
 	if instance.pool == nil {
 		socketDir := GetSocketDir()
 		dsn := fmt.Sprintf(
@@ -532,7 +580,7 @@ func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 			socketDir,
 			GetServerPort(),
 			"postgres",
-			applicationName,
+			instanceManagerApplicationName,
 		)
 
 		instance.pool = pool.NewConnectionPool(dsn)
@@ -742,6 +790,156 @@ func (instance *Instance) removePgControlFileBackup() error {
 	return nil
 }
 
+// Reclone discards the data directory of this instance, cloning it again
+// from the primary node. The clone is done in temporary directories, next
+// to the current ones, which are replaced only when pg_basebackup succeeds:
+// this requires enough free space to store both copies of the data
+func (instance *Instance) Reclone() error {
+	// Signal the liveness probe that we are cloning the primary before starting postgres
+	instance.PgBaseBackupIsRunning = true
+	defer func() {
+		instance.PgBaseBackupIsRunning = false
+	}()
+
+	instance.LogPgControldata("before pg_basebackup")
+
+	walDir, tablespaceDirs, err := getLinkedDirectories(instance.PgData)
+	if err != nil {
+		return err
+	}
+
+	recloneWalDir := ""
+	if walDir != "" {
+		recloneWalDir = getRecloneDirectory(walDir)
+	}
+	recloneDirectories := []string{getRecloneDirectory(instance.PgData), recloneWalDir}
+	tablespaceMapping := make(map[string]string, len(tablespaceDirs))
+	for _, tablespaceDir := range tablespaceDirs {
+		tablespaceMapping[tablespaceDir] = getRecloneDirectory(tablespaceDir)
+		recloneDirectories = append(recloneDirectories, tablespaceMapping[tablespaceDir])
+	}
+
+	// Remove what an interrupted attempt could have left behind
+	if err := removeDirectories(recloneDirectories); err != nil {
+		return err
+	}
+
+	primaryConnInfo := instance.GetPrimaryConnInfo() + " dbname=postgres connect_timeout=5"
+	if err := clonePgData(
+		primaryConnInfo,
+		getRecloneDirectory(instance.PgData),
+		recloneWalDir,
+		tablespaceMapping,
+	); err != nil {
+		log.Info("Cloning the primary failed, keeping the current data directory", "err", err)
+		if removeErr := removeDirectories(recloneDirectories); removeErr != nil {
+			log.Warning("Cannot remove the temporary directories of the clone", "err", removeErr)
+		}
+		return err
+	}
+
+	return replaceReclonedDirectories(instance.PgData, walDir, tablespaceDirs)
+}
+
+// getRecloneDirectory gets the temporary directory where the passed
+// one is cloned from the primary
+func getRecloneDirectory(directory string) string {
+	return directory + "-reclone"
+}
+
+// removeDirectories removes the passed directories, ignoring the empty ones
+func removeDirectories(directories []string) error {
+	for _, directory := range directories {
+		if directory == "" {
+			continue
+		}
+		if err := os.RemoveAll(directory); err != nil {
+			return fmt.Errorf("while removing %s: %w", directory, err)
+		}
+	}
+	return nil
+}
+
+// replaceReclonedDirectories replaces the data directory, the WAL directory
+// and the tablespace directories with the ones cloned from the primary,
+// pointing the links of the new data directory to their final location
+func replaceReclonedDirectories(pgData, walDir string, tablespaceDirs []string) error {
+	directories := append([]string{pgData}, tablespaceDirs...)
+	if walDir != "" {
+		directories = append(directories, walDir)
+	}
+	for _, directory := range directories {
+		log.Info("Replacing the directory with the one cloned from the primary", "directory", directory)
+		if err := os.RemoveAll(directory); err != nil {
+			return fmt.Errorf("while removing %s: %w", directory, err)
+		}
+		if err := os.Rename(getRecloneDirectory(directory), directory); err != nil {
+			return fmt.Errorf("while replacing %s: %w", directory, err)
+		}
+	}
+
+	links := make(map[string]string, len(tablespaceDirs)+1)
+	if walDir != "" {
+		links[filepath.Join(pgData, "pg_wal")] = walDir
+	}
+	entries, err := os.ReadDir(filepath.Join(pgData, "pg_tblspc"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		link := filepath.Join(pgData, "pg_tblspc", entry.Name())
+		target, err := os.Readlink(link)
+		if err != nil {
+			return err
+		}
+		for _, tablespaceDir := range tablespaceDirs {
+			if target == getRecloneDirectory(tablespaceDir) {
+				links[link] = tablespaceDir
+			}
+		}
+	}
+
+	for link, target := range links {
+		if err := os.Remove(link); err != nil {
+			return err
+		}
+		if err := os.Symlink(target, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLinkedDirectories gets the directories the passed data directory
+// links to: the one containing the WAL, if not inside the data directory,
+// and the ones of the tablespaces
+func getLinkedDirectories(pgData string) (string, []string, error) {
+	var walDir string
+	pgWal := filepath.Join(pgData, "pg_wal")
+	if info, err := os.Lstat(pgWal); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if walDir, err = os.Readlink(pgWal); err != nil {
+			return "", nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(pgData, "pg_tblspc"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", nil, err
+	}
+
+	tablespaceDirs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(pgData, "pg_tblspc", entry.Name()))
+		if err != nil {
+			return "", nil, err
+		}
+		tablespaceDirs = append(tablespaceDirs, target)
+	}
+
+	return walDir, tablespaceDirs, nil
+}
+
 // Rewind uses pg_rewind to align this data directory with the contents of the primary node.
 // If postgres major version is >= 13, add "--restore-target-wal" option
 func (instance *Instance) Rewind(postgresMajorVersion int) error {
@@ -952,5 +1150,9 @@ func (instance *Instance) DropConnections() error {
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
 func (instance *Instance) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
+	sslMode := instance.ReplicationSSLMode
+	if sslMode == "" {
+		sslMode = "verify-ca"
+	}
+	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName, sslMode)
 }
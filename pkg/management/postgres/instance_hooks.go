@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// SetInstanceHooks stores the configuration of the instance hooks, which
+// can be changed by the reconciliation loop while the lifecycle manager
+// is running them
+func (instance *Instance) SetInstanceHooks(hooks *apiv1.InstanceHooksConfiguration) {
+	instance.instanceHooks.Store(hooks.DeepCopy())
+}
+
+// getInstanceHooks returns the configuration of the instance hooks, if any
+func (instance *Instance) getInstanceHooks() *apiv1.InstanceHooksConfiguration {
+	hooks, _ := instance.instanceHooks.Load().(*apiv1.InstanceHooksConfiguration)
+	return hooks
+}
+
+// RunInstanceHooks executes, in order, the instance hooks configured for
+// the passed event. Failures are logged and never interrupt the execution
+// of the remaining hooks, as they must not prevent the instance from
+// changing its role or shutting down
+func (instance *Instance) RunInstanceHooks(ctx context.Context, event apiv1.InstanceHookEvent) {
+	hooks := instance.getInstanceHooks().GetHooks(event)
+	if len(hooks) == 0 {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx)
+	for _, hook := range hooks {
+		contextLogger.Info("Executing instance hook", "event", event, "hook", hook.Name)
+		start := time.Now()
+		err := instance.runInstanceHook(ctx, event, hook)
+		duration := time.Since(start)
+		if err != nil {
+			var exitError *exec.ExitError
+			exitCode := -1
+			if errors.As(err, &exitError) {
+				exitCode = exitError.ExitCode()
+			}
+			contextLogger.Warning("Instance hook failed",
+				"event", event,
+				"hook", hook.Name,
+				"exitCode", exitCode,
+				"duration", duration.String(),
+				"error", err)
+			continue
+		}
+
+		contextLogger.Info("Instance hook completed",
+			"event", event,
+			"hook", hook.Name,
+			"duration", duration.String())
+	}
+}
+
+// runInstanceHook executes a single instance hook, enforcing its timeout
+func (instance *Instance) runInstanceHook(
+	ctx context.Context,
+	event apiv1.InstanceHookEvent,
+	hook apiv1.InstanceHook,
+) error {
+	timeout := hook.GetTimeout()
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, hook.Command[0], hook.Command[1:]...) // #nosec G204
+	cmd.Env = append(os.Environ(),
+		"CNPG_HOOK_EVENT="+string(event),
+		"CNPG_CLUSTER_NAME="+instance.ClusterName,
+		"CNPG_NAMESPACE="+instance.Namespace,
+		"CNPG_POD_NAME="+instance.PodName,
+	)
+	err := execlog.RunStreaming(cmd, hook.Name)
+	if err != nil && hookCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timeout of %v expired: %w", timeout, err)
+	}
+	return err
+}
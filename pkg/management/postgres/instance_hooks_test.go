@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance hooks", func() {
+	var instance *Instance
+	var outputFile string
+
+	BeforeEach(func() {
+		instance = &Instance{
+			Namespace:   "default",
+			PodName:     "cluster-example-1",
+			ClusterName: "cluster-example",
+		}
+		outputFile = filepath.Join(GinkgoT().TempDir(), "output")
+	})
+
+	It("does nothing when no hook is configured", func() {
+		instance.RunInstanceHooks(context.TODO(), apiv1.InstanceHookEventPrePromote)
+		instance.SetInstanceHooks(nil)
+		instance.RunInstanceHooks(context.TODO(), apiv1.InstanceHookEventPrePromote)
+	})
+
+	It("passes the event and the instance to the hooks", func() {
+		instance.SetInstanceHooks(&apiv1.InstanceHooksConfiguration{
+			PostPromote: []apiv1.InstanceHook{{
+				Name: "env",
+				Command: []string{
+					"sh", "-c",
+					"echo $CNPG_HOOK_EVENT $CNPG_NAMESPACE $CNPG_CLUSTER_NAME $CNPG_POD_NAME > " + outputFile,
+				},
+			}},
+		})
+
+		instance.RunInstanceHooks(context.TODO(), apiv1.InstanceHookEventPrePromote)
+		Expect(outputFile).ToNot(BeAnExistingFile())
+
+		instance.RunInstanceHooks(context.TODO(), apiv1.InstanceHookEventPostPromote)
+		content, err := os.ReadFile(outputFile) //nolint:gosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("postPromote default cluster-example cluster-example-1\n"))
+	})
+
+	It("executes the remaining hooks when one fails or times out", func() {
+		instance.SetInstanceHooks(&apiv1.InstanceHooksConfiguration{
+			PreShutdown: []apiv1.InstanceHook{
+				{Name: "failing", Command: []string{"false"}},
+				{Name: "slow", Command: []string{"sleep", "10"}, Timeout: 1},
+				{Name: "touch", Command: []string{"touch", outputFile}},
+			},
+		})
+
+		start := time.Now()
+		instance.RunInstanceHooks(context.TODO(), apiv1.InstanceHookEventPreShutdown)
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		Expect(outputFile).To(BeAnExistingFile())
+	})
+})
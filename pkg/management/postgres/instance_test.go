@@ -224,3 +224,65 @@ var _ = Describe("check atomic bool", func() {
 		Expect(unAvailable).To(BeTrue())
 	})
 })
+
+var _ = Describe("directories linked from the data directory", func() {
+	It("finds the WAL and the tablespaces kept outside of the data directory", func() {
+		tempDir := GinkgoT().TempDir()
+		pgData := filepath.Join(tempDir, "pgdata")
+		walDir := filepath.Join(tempDir, "wal")
+		tablespaceDir := filepath.Join(tempDir, "tbs")
+		for _, dir := range []string{filepath.Join(pgData, "pg_tblspc"), walDir, tablespaceDir} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+		}
+		Expect(os.Symlink(walDir, filepath.Join(pgData, "pg_wal"))).To(Succeed())
+		Expect(os.Symlink(tablespaceDir, filepath.Join(pgData, "pg_tblspc", "16384"))).To(Succeed())
+
+		linkedWalDir, tablespaceDirs, err := getLinkedDirectories(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linkedWalDir).To(Equal(walDir))
+		Expect(tablespaceDirs).To(ConsistOf(tablespaceDir))
+	})
+
+	It("ignores the WAL kept inside the data directory", func() {
+		pgData := GinkgoT().TempDir()
+		Expect(os.Mkdir(filepath.Join(pgData, "pg_wal"), 0o700)).To(Succeed())
+
+		linkedWalDir, tablespaceDirs, err := getLinkedDirectories(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linkedWalDir).To(BeEmpty())
+		Expect(tablespaceDirs).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replacing the directories cloned from the primary", func() {
+	It("replaces the data, the WAL and the tablespaces, fixing the links", func() {
+		tempDir := GinkgoT().TempDir()
+		pgData := filepath.Join(tempDir, "pgdata")
+		walDir := filepath.Join(tempDir, "wal")
+		tablespaceDir := filepath.Join(tempDir, "tbs")
+
+		for _, dir := range []string{pgData, walDir, tablespaceDir} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "old"), nil, 0o600)).To(Succeed())
+			Expect(os.MkdirAll(getRecloneDirectory(dir), 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(getRecloneDirectory(dir), "new"), nil, 0o600)).To(Succeed())
+		}
+		recloneTblspc := filepath.Join(getRecloneDirectory(pgData), "pg_tblspc")
+		Expect(os.Mkdir(recloneTblspc, 0o700)).To(Succeed())
+		Expect(os.Symlink(getRecloneDirectory(walDir),
+			filepath.Join(getRecloneDirectory(pgData), "pg_wal"))).To(Succeed())
+		Expect(os.Symlink(getRecloneDirectory(tablespaceDir), filepath.Join(recloneTblspc, "16384"))).To(Succeed())
+
+		Expect(replaceReclonedDirectories(pgData, walDir, []string{tablespaceDir})).To(Succeed())
+
+		for _, dir := range []string{pgData, walDir, tablespaceDir} {
+			Expect(filepath.Join(dir, "new")).To(BeAnExistingFile())
+			Expect(filepath.Join(dir, "old")).ToNot(BeAnExistingFile())
+			Expect(getRecloneDirectory(dir)).ToNot(BeADirectory())
+		}
+		linkedWalDir, tablespaceDirs, err := getLinkedDirectories(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linkedWalDir).To(Equal(walDir))
+		Expect(tablespaceDirs).To(ConsistOf(tablespaceDir))
+	})
+})
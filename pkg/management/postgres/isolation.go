@@ -0,0 +1,141 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// IsolationReadOnlyFile is the name of the file, stored in PGDATA, marking
+// that the read-only status of the transactions has been persisted in
+// postgresql.auto.conf by the isolation checker
+const IsolationReadOnlyFile = ".isolation-read-only"
+
+// streamingReplicasQuery counts the replicas of this cluster that are
+// streaming from this instance. On PostgreSQL 12 and later, only the
+// replicas whose last reply has been received recently are counted
+const streamingReplicasQuery = `SELECT COUNT(*)
+	FROM pg_catalog.pg_stat_replication
+	WHERE application_name LIKE $1 AND usename = $2 AND state = 'streaming'`
+
+// recentReplyFilter selects the replicas that sent a reply within twice the
+// status interval, which is the time after which a replica that didn't
+// lose connectivity is expected to have reported its position
+const recentReplyFilter = ` AND reply_time > pg_catalog.now() - GREATEST(
+		2 * pg_catalog.current_setting('wal_receiver_status_interval')::interval,
+		interval '20 seconds')`
+
+// CountStreamingReplicas returns the number of replicas that are
+// currently streaming from this instance
+func (instance *Instance) CountStreamingReplicas(ctx context.Context) (int, error) {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	query := streamingReplicasQuery
+	if version, err := instance.GetPgVersion(); err == nil && version.Major >= 12 {
+		query += recentReplyFilter
+	}
+
+	var count int
+	row := db.QueryRowContext(ctx, query,
+		fmt.Sprintf("%s-%%", instance.ClusterName),
+		v1.StreamingReplicationUser)
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SetDefaultTransactionReadOnly sets the default read-only status of the
+// transactions of this instance and reloads the configuration. Sessions
+// that explicitly changed the parameter are not affected. The marker file
+// is created before persisting the read-only status and removed after
+// resetting it, so that it is never missing while the status is persisted
+func (instance *Instance) SetDefaultTransactionReadOnly(ctx context.Context, enabled bool) error {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	markerFile := filepath.Join(instance.PgData, IsolationReadOnlyFile)
+	statement := "ALTER SYSTEM RESET default_transaction_read_only"
+	if enabled {
+		statement = "ALTER SYSTEM SET default_transaction_read_only TO on"
+		if err := fileutils.CreateEmptyFile(markerFile); err != nil {
+			return fmt.Errorf("while creating %s: %w", markerFile, err)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, statement); err != nil {
+		return err
+	}
+
+	if !enabled {
+		if err := fileutils.RemoveFile(markerFile); err != nil {
+			return fmt.Errorf("while removing %s: %w", markerFile, err)
+		}
+	}
+
+	_, err = db.ExecContext(ctx, "SELECT pg_catalog.pg_reload_conf()")
+	return err
+}
+
+// removePersistedReadOnly removes from postgresql.auto.conf the read-only
+// status of the transactions applied by the isolation checker, as marked by
+// IsolationReadOnlyFile. The need to revert it is tracked only in the
+// memory of the instance manager, so it must be removed before PostgreSQL
+// is started: the isolation checker applies it again if the instance is
+// still isolated. A read-only status set by the user is left untouched
+func (instance *Instance) removePersistedReadOnly() error {
+	markerFile := filepath.Join(instance.PgData, IsolationReadOnlyFile)
+	marked, err := fileutils.FileExists(markerFile)
+	if err != nil || !marked {
+		return err
+	}
+
+	autoConf := filepath.Join(instance.PgData, "postgresql.auto.conf")
+	exists, err := fileutils.FileExists(autoConf)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		changed, err := configfile.UpdatePostgresConfigurationFile(
+			autoConf,
+			map[string]string{},
+			"default_transaction_read_only",
+		)
+		if err != nil {
+			return fmt.Errorf("while removing default_transaction_read_only: %w", err)
+		}
+		if changed {
+			log.Info("Removed the read-only status applied by the isolation checker")
+		}
+	}
+
+	return fileutils.RemoveFile(markerFile)
+}
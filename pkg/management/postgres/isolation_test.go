@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("isolation read-only status", func() {
+	It("is removed from postgresql.auto.conf before the instance is started", func() {
+		instance := &Instance{PgData: GinkgoT().TempDir()}
+		autoConf := filepath.Join(instance.PgData, "postgresql.auto.conf")
+		Expect(os.WriteFile(autoConf, []byte(
+			"default_transaction_read_only = 'on'\nwork_mem = '8MB'\n"), 0o600)).To(Succeed())
+		markerFile := filepath.Join(instance.PgData, IsolationReadOnlyFile)
+		Expect(os.WriteFile(markerFile, nil, 0o600)).To(Succeed())
+
+		Expect(instance.removePersistedReadOnly()).To(Succeed())
+		content, err := os.ReadFile(autoConf) //nolint:gosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).ToNot(ContainSubstring("default_transaction_read_only"))
+		Expect(string(content)).To(ContainSubstring("work_mem"))
+		Expect(markerFile).ToNot(BeAnExistingFile())
+	})
+
+	It("is kept when it has not been applied by the isolation checker", func() {
+		instance := &Instance{PgData: GinkgoT().TempDir()}
+		autoConf := filepath.Join(instance.PgData, "postgresql.auto.conf")
+		Expect(os.WriteFile(autoConf, []byte(
+			"default_transaction_read_only = 'on'\n"), 0o600)).To(Succeed())
+
+		Expect(instance.removePersistedReadOnly()).To(Succeed())
+		content, err := os.ReadFile(autoConf) //nolint:gosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("default_transaction_read_only"))
+	})
+
+	It("ignores a missing postgresql.auto.conf", func() {
+		instance := &Instance{PgData: GinkgoT().TempDir()}
+		Expect(os.WriteFile(filepath.Join(instance.PgData, IsolationReadOnlyFile), nil, 0o600)).To(Succeed())
+		Expect(instance.removePersistedReadOnly()).To(Succeed())
+	})
+})
@@ -32,6 +32,16 @@ import (
 // ClonePgData clones an existing server, given its connection string,
 // to a certain data directory
 func ClonePgData(connectionString, targetPgData, walDir string) error {
+	return clonePgData(connectionString, targetPgData, walDir, nil)
+}
+
+// clonePgData clones an existing server, given its connection string,
+// to a certain data directory, relocating the tablespaces as requested
+// by the passed mapping between their original and target directories
+func clonePgData(
+	connectionString, targetPgData, walDir string,
+	tablespaceMapping map[string]string,
+) error {
 	// To initiate streaming replication, the frontend sends the replication parameter
 	// in the startup message. A Boolean value of true (or on, yes, 1) tells the backend
 	// to go into physical replication walsender mode, wherein a small set of replication
@@ -65,6 +75,10 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		options = append(options, "--waldir", walDir)
 	}
 
+	for oldDir, newDir := range tablespaceMapping {
+		options = append(options, fmt.Sprintf("--tablespace-mapping=%s=%s", oldDir, newDir))
+	}
+
 	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
 	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
 	if err != nil {
@@ -76,7 +90,8 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 
 // Join creates a new instance joined to an existing PostgreSQL cluster
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
-	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
+	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName, cluster.GetReplicationSSLMode()) +
+		" dbname=postgres connect_timeout=5"
 
 	err := ClonePgData(primaryConnInfo, info.PgData, info.PgWal)
 	if err != nil {
@@ -84,6 +99,6 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	}
 
 	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
-	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
+	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(cluster), slotName)
 	return err
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/jackc/pgx/v4"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
@@ -32,6 +33,7 @@ import (
 
 type databaseSnapshotter struct {
 	cluster *apiv1.Cluster
+	client  client.Client
 }
 
 func (ds *databaseSnapshotter) getDatabaseList(ctx context.Context, target *pool.ConnectionPool) ([]string, error) {
@@ -99,6 +101,9 @@ func (ds *databaseSnapshotter) exportDatabases(
 			"-d", dsn,
 			"-v",
 		}
+		if ds.isStatisticsExportSupported() {
+			options = append(options, "--with-statistics")
+		}
 
 		contextLogger.Info("Running pg_dump", "cmd", pgDump,
 			"options", options)
@@ -275,27 +280,6 @@ func (ds *databaseSnapshotter) executePostImportQueries(
 	return nil
 }
 
-func (ds *databaseSnapshotter) analyze(
-	ctx context.Context,
-	target *pool.ConnectionPool,
-	databases []string,
-) error {
-	contextLogger := log.FromContext(ctx)
-
-	for _, database := range databases {
-		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
-		db, err := target.Connection(database)
-		if err != nil {
-			return err
-		}
-		if _, err := db.Exec("ANALYZE VERBOSE"); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // dropExtensionsFromDatabase will drop every extension installed in a database.
 // This is useful before restoring a backup, as the restore process will execute
 // the "CREATE EXTENSION" commands that are needed
@@ -19,6 +19,8 @@ package logicalimport
 import (
 	"context"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
@@ -27,12 +29,13 @@ import (
 // Microservice executes the microservice clone type
 func Microservice(
 	ctx context.Context,
+	typedClient client.Client,
 	cluster *apiv1.Cluster,
 	destination *pool.ConnectionPool,
 	origin *pool.ConnectionPool,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{cluster: cluster, client: typedClient}
 	databases := cluster.Spec.Bootstrap.InitDB.Import.Databases
 	contextLogger.Info("starting microservice clone process")
 
@@ -19,6 +19,8 @@ package logicalimport
 import (
 	"context"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
@@ -27,6 +29,7 @@ import (
 // Monolith executes the monolith clone type
 func Monolith(
 	ctx context.Context,
+	typedClient client.Client,
 	cluster *apiv1.Cluster,
 	destination *pool.ConnectionPool,
 	origin *pool.ConnectionPool,
@@ -42,7 +45,7 @@ func Monolith(
 		return err
 	}
 
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{cluster: cluster, client: typedClient}
 	databases, err := ds.getDatabaseList(ctx, origin)
 	if err != nil {
 		return err
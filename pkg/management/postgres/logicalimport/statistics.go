@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+const vacuumdb executable = "vacuumdb"
+
+// isStatisticsExportSupported checks whether the optimizer statistics can be
+// exported by pg_dump and imported by pg_restore, which is possible
+// since PostgreSQL 18
+func (ds *databaseSnapshotter) isStatisticsExportSupported() bool {
+	majorVersion, err := ds.cluster.GetPostgresqlVersion()
+	return err == nil && majorVersion >= 180000
+}
+
+// buildVacuumdbOptions creates the options of vacuumdb to generate the
+// optimizer statistics of the passed database in stages, starting with
+// a minimal statistics target to quickly get usable query plans. When the
+// statistics have been imported, only the missing ones are generated
+func buildVacuumdbOptions(dsn string, missingStatsOnly bool) []string {
+	options := []string{
+		"--analyze-in-stages",
+		"--dbname", dsn,
+	}
+	if missingStatsOnly {
+		options = append(options, "--missing-stats-only")
+	}
+
+	return options
+}
+
+// analyze generates the optimizer statistics of the imported databases,
+// reporting the progress in the conditions of the cluster
+func (ds *databaseSnapshotter) analyze(
+	ctx context.Context,
+	target *pool.ConnectionPool,
+	databases []string,
+) error {
+	return Analyze(ctx, ds.client, ds.cluster, target, databases, ds.isStatisticsExportSupported())
+}
+
+// Analyze generates the optimizer statistics of the passed databases,
+// reporting the progress in the conditions of the cluster. When
+// missingStatsOnly is set, only the statistics that haven't been
+// imported are generated
+func Analyze(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	target *pool.ConnectionPool,
+	databases []string,
+	missingStatsOnly bool,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx, database := range databases {
+		reportStatisticsProgress(ctx, cli, cluster, metav1.ConditionFalse,
+			apiv1.ConditionReasonOptimizerStatisticsGenerating,
+			fmt.Sprintf("Generating the optimizer statistics of database %s (%d of %d)",
+				database, idx+1, len(databases)))
+
+		options := buildVacuumdbOptions(target.GetDsn(database), missingStatsOnly)
+		contextLogger.Info("Running vacuumdb",
+			"databaseName", database,
+			"cmd", vacuumdb,
+			"missingStatsOnly", missingStatsOnly)
+
+		vacuumdbCommand := exec.Command(vacuumdb, options...) // #nosec
+		if err := execlog.RunStreaming(vacuumdbCommand, vacuumdb); err != nil {
+			reportStatisticsProgress(ctx, cli, cluster, metav1.ConditionFalse,
+				apiv1.ConditionReasonOptimizerStatisticsFailed,
+				fmt.Sprintf("Cannot generate the optimizer statistics of database %s: %v", database, err))
+			return fmt.Errorf("error while executing vacuumdb on database %s: %w", database, err)
+		}
+	}
+
+	reportStatisticsProgress(ctx, cli, cluster, metav1.ConditionTrue,
+		apiv1.ConditionReasonOptimizerStatisticsGenerated,
+		fmt.Sprintf("The optimizer statistics of %d databases have been generated", len(databases)))
+	return nil
+}
+
+// reportStatisticsProgress updates the condition reporting the generation
+// of the optimizer statistics. Failures are not fatal, as the condition is
+// only informative
+func reportStatisticsProgress(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	status metav1.ConditionStatus,
+	reason apiv1.ConditionReason,
+	message string,
+) {
+	if cli == nil {
+		return
+	}
+
+	condition := &metav1.Condition{
+		Type:    string(apiv1.ConditionOptimizerStatistics),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+	if err := conditions.Update(ctx, cli, cluster, condition); err != nil {
+		log.FromContext(ctx).Warning("Cannot update the optimizer statistics condition", "error", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cachedQueryResult is the result of a query, kept until its expiration
+type cachedQueryResult struct {
+	metrics    []prometheus.Metric
+	expiration time.Time
+}
+
+// queryResultsCache contains the results of the queries with a
+// `cache_seconds` setting, avoiding running expensive queries at every
+// scrape
+type queryResultsCache struct {
+	mutex   sync.Mutex
+	results map[string]cachedQueryResult
+	now     func() time.Time
+}
+
+// newQueryResultsCache creates an empty cache
+func newQueryResultsCache() *queryResultsCache {
+	return &queryResultsCache{
+		results: make(map[string]cachedQueryResult),
+		now:     time.Now,
+	}
+}
+
+// get returns the metrics of the passed query, if they are not expired
+func (cache *queryResultsCache) get(name string) ([]prometheus.Metric, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	result, found := cache.results[name]
+	if !found || !cache.now().Before(result.expiration) {
+		delete(cache.results, name)
+		return nil, false
+	}
+
+	return result.metrics, true
+}
+
+// put stores the metrics of the passed query for the passed duration
+func (cache *queryResultsCache) put(name string, metrics []prometheus.Metric, duration time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.results[name] = cachedQueryResult{
+		metrics:    metrics,
+		expiration: cache.now().Add(duration),
+	}
+}
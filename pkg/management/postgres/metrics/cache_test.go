@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("query results cache", func() {
+	var (
+		cache  *queryResultsCache
+		now    time.Time
+		metric prometheus.Metric
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache = newQueryResultsCache()
+		cache.now = func() time.Time { return now }
+		metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("test_metric", "test", nil, nil), prometheus.GaugeValue, 1)
+	})
+
+	It("returns nothing for the queries which have not been cached", func() {
+		_, found := cache.get("some_query")
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns the cached metrics until they expire", func() {
+		cache.put("some_query", []prometheus.Metric{metric}, 30*time.Second)
+
+		now = now.Add(29 * time.Second)
+		metrics, found := cache.get("some_query")
+		Expect(found).To(BeTrue())
+		Expect(metrics).To(ConsistOf(metric))
+
+		now = now.Add(time.Second)
+		_, found = cache.get("some_query")
+		Expect(found).To(BeFalse())
+	})
+})
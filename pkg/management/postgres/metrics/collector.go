@@ -24,10 +24,12 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/prometheus/client_golang/prometheus"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics/histogram"
@@ -47,6 +49,8 @@ type QueriesCollector struct {
 
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
+
+	cache *queryResultsCache
 }
 
 // Name returns the name of this collector, as supplied by the user in the configMap
@@ -96,6 +100,16 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 			continue
 		}
 
+		if userQuery.CacheSeconds > 0 {
+			if cachedMetrics, found := q.cache.get(name); found {
+				queryLogger.Debug("Using the cached data")
+				for _, metric := range cachedMetrics {
+					ch <- metric
+				}
+				continue
+			}
+		}
+
 		queryLogger.Debug("Collecting data")
 
 		targetDatabases := userQuery.TargetDatabases
@@ -119,25 +133,64 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 		}
 
 		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
-		for targetDatabase := range allTargetDatabases {
-			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
-			if err != nil {
-				q.reportUserQueryErrorMetric(name + ": " + err.Error())
-				continue
-			}
+		if userQuery.CacheSeconds == 0 {
+			q.collectUserQuery(name, collector, allTargetDatabases, ch)
+			continue
+		}
 
-			err = collector.collect(conn, ch)
-			if err != nil {
-				queryLogger.Error(err, "Error collecting user query",
-					"targetDatabase", targetDatabase)
-				// Increment metrics counters.
-				q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
+		// The metrics are sent while being recorded, and they are cached
+		// only when the query succeeded on every target database
+		var queryMetrics []prometheus.Metric
+		queryCh := make(chan prometheus.Metric)
+		done := make(chan struct{})
+		go func() {
+			for metric := range queryCh {
+				queryMetrics = append(queryMetrics, metric)
+				ch <- metric
 			}
+			close(done)
+		}()
+		succeeded := q.collectUserQuery(name, collector, allTargetDatabases, queryCh)
+		close(queryCh)
+		<-done
+
+		if succeeded {
+			q.cache.put(name, queryMetrics, time.Duration(userQuery.CacheSeconds)*time.Second)
 		}
 	}
 	return nil
 }
 
+// collectUserQuery runs a query on the passed databases, reporting whether
+// it succeeded on every one of them
+func (q *QueriesCollector) collectUserQuery(
+	name string,
+	collector QueryCollector,
+	targetDatabases map[string]bool,
+	ch chan<- prometheus.Metric,
+) bool {
+	queryLogger := log.WithValues("query", name)
+	succeeded := true
+	for targetDatabase := range targetDatabases {
+		conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
+		if err != nil {
+			q.reportUserQueryErrorMetric(name + ": " + err.Error())
+			succeeded = false
+			continue
+		}
+
+		err = collector.collect(conn, ch)
+		if err != nil {
+			queryLogger.Error(err, "Error collecting user query",
+				"targetDatabase", targetDatabase)
+			// Increment metrics counters.
+			q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
+			succeeded = false
+		}
+	}
+	return succeeded
+}
+
 func (q QueriesCollector) toBeChecked(name string, userQuery UserQuery, isPrimary bool, queryLogger log.Logger) bool {
 	if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
 		queryLogger.Debug("Skipping because runs only on primary")
@@ -258,6 +311,7 @@ func NewQueriesCollector(
 		variableLabels: make(map[string]VariableSet),
 		userQueries:    make(UserQueries),
 		defaultDBName:  defaultDBName,
+		cache:          newQueryResultsCache(),
 		errorUserQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: name,
 			Name:      "errors_total",
@@ -439,8 +493,16 @@ func (c QueryCollector) collectColumns(columns []string, columnData []interface{
 	}
 }
 
+// monitoringRoleQuery sets the role of the monitoring transaction to the
+// dedicated monitoring role, falling back to `pg_monitor` while the
+// former has not been created yet
+var monitoringRoleQuery = fmt.Sprintf(
+	"SELECT pg_catalog.set_config('role', CASE WHEN EXISTS "+
+		"(SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = '%s') THEN '%s' ELSE 'pg_monitor' END, false)",
+	apiv1.MonitoringRoleName, apiv1.MonitoringRoleName)
+
 // createMonitoringTx create a monitoring transaction with read-only access
-// and role set to `pg_monitor`
+// and role set to the monitoring role
 func createMonitoringTx(conn *sql.DB) (*sql.Tx, error) {
 	tx, err := conn.BeginTx(context.Background(), &sql.TxOptions{
 		ReadOnly: true,
@@ -460,7 +522,7 @@ func createMonitoringTx(conn *sql.DB) (*sql.Tx, error) {
 		return nil, err
 	}
 
-	_, err = tx.Exec("SET ROLE TO pg_monitor")
+	_, err = tx.Exec(monitoringRoleQuery)
 
 	return tx, err
 }
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// Grant is a privilege needed by a query, granted to the monitoring role
+// in every target database of the query
+type Grant struct {
+	// Privilege is the granted privilege. Only `SELECT` is allowed on
+	// tables, and only `USAGE` on schemas, which are also the defaults
+	Privilege string `yaml:"privilege"`
+
+	// On is the type of the object, `table`, `schema`, or
+	// `all tables in schema`. Defaults to `table`
+	On string `yaml:"on"`
+
+	// Object is the name of the object, optionally qualified with
+	// the schema when it is a table
+	Object string `yaml:"object"`
+}
+
+// grantableObjects maps every type of object allowed in a grant to the
+// only privilege that can be granted on it. As the grants are executed
+// by the superuser, anything that could give the monitoring role more
+// than read-only access to the data is refused
+var grantableObjects = map[string]string{
+	"table":                "SELECT",
+	"schema":               "USAGE",
+	"all tables in schema": "SELECT",
+}
+
+// ToSQL returns the statement granting this privilege to the passed role
+func (g Grant) ToSQL(role string) (string, error) {
+	on := strings.ToLower(strings.Join(strings.Fields(g.On), " "))
+	if on == "" {
+		on = "table"
+	}
+
+	privilege, ok := grantableObjects[on]
+	if !ok {
+		return "", fmt.Errorf("cannot grant privileges on %q", g.On)
+	}
+	if g.Privilege != "" && !strings.EqualFold(g.Privilege, privilege) {
+		return "", fmt.Errorf("only the %s privilege can be granted on %s, got %q", privilege, on, g.Privilege)
+	}
+
+	if g.Object == "" {
+		return "", fmt.Errorf("missing object name in the grant of %s", privilege)
+	}
+	identifier := pgx.Identifier(strings.Split(g.Object, "."))
+	if on != "table" && len(identifier) != 1 {
+		return "", fmt.Errorf("invalid schema name %q", g.Object)
+	}
+	if len(identifier) > 2 {
+		return "", fmt.Errorf("invalid table name %q", g.Object)
+	}
+
+	return fmt.Sprintf("GRANT %s ON %s %s TO %s",
+		privilege,
+		strings.ToUpper(on),
+		identifier.Sanitize(),
+		pgx.Identifier{role}.Sanitize()), nil
+}
+
+// GrantPrivileges grants the monitoring role the privileges declared by
+// the queries in every one of their target databases, returning the
+// errors of the queries whose privileges cannot be granted, indexed by
+// query name. The privileges that are not declared anymore are not revoked
+func (q *QueriesCollector) GrantPrivileges(ctx context.Context) (map[string]error, error) {
+	var allAccessibleDatabasesCache []string
+	result := make(map[string]error)
+	for name, userQuery := range q.userQueries {
+		if len(userQuery.Grants) == 0 {
+			continue
+		}
+
+		statements := make([]string, 0, len(userQuery.Grants))
+		for _, grant := range userQuery.Grants {
+			statement, err := grant.ToSQL(apiv1.MonitoringRoleName)
+			if err != nil {
+				result[name] = err
+				break
+			}
+			statements = append(statements, statement)
+		}
+		if result[name] != nil {
+			continue
+		}
+
+		targetDatabases := userQuery.TargetDatabases
+		if len(targetDatabases) == 0 {
+			targetDatabases = append(targetDatabases, q.defaultDBName)
+		}
+
+		if allAccessibleDatabasesCache == nil && hasPathPattern(targetDatabases) {
+			databases, err := q.getAllAccessibleDatabases()
+			if err != nil {
+				return nil, err
+			}
+			allAccessibleDatabasesCache = databases
+		}
+
+		for targetDatabase := range q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache) {
+			if err := q.executeGrants(ctx, targetDatabase, statements); err != nil {
+				log.Warning("Cannot grant the privileges needed by a monitoring query",
+					"query", name, "targetDatabase", targetDatabase, "error", err.Error())
+				result[name] = fmt.Errorf("on database %s: %w", targetDatabase, err)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// executeGrants executes the passed statements in the target database
+func (q *QueriesCollector) executeGrants(ctx context.Context, targetDatabase string, statements []string) error {
+	db, err := q.instance.ConnectionPool().Connection(targetDatabase)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec("SET LOCAL synchronous_commit TO local"); err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
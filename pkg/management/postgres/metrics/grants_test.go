@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Monitoring role grants", func() {
+	It("parses the grants of a query", func() {
+		queries, err := ParseQueries([]byte(`
+orders:
+  query: SELECT count(*) AS total FROM sales.orders
+  grants:
+    - object: sales
+      on: schema
+    - object: sales.orders
+  metrics:
+    - total:
+        usage: GAUGE
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries["orders"].Grants).To(Equal([]Grant{
+			{On: "schema", Object: "sales"},
+			{Object: "sales.orders"},
+		}))
+	})
+
+	DescribeTable("builds the grant statements",
+		func(grant Grant, expected string) {
+			Expect(grant.ToSQL("cnpg_monitor")).To(Equal(expected))
+		},
+		Entry("table", Grant{Object: "sales.orders"},
+			`GRANT SELECT ON TABLE "sales"."orders" TO "cnpg_monitor"`),
+		Entry("schema", Grant{On: "SCHEMA", Privilege: "usage", Object: "sales"},
+			`GRANT USAGE ON SCHEMA "sales" TO "cnpg_monitor"`),
+		Entry("all the tables of a schema", Grant{On: "all  tables in schema", Object: "sales"},
+			`GRANT SELECT ON ALL TABLES IN SCHEMA "sales" TO "cnpg_monitor"`),
+		Entry("quoted identifiers", Grant{Object: `sales.orders"; DROP TABLE x; --`},
+			`GRANT SELECT ON TABLE "sales"."orders""; DROP TABLE x; --" TO "cnpg_monitor"`),
+	)
+
+	DescribeTable("refuses the grants giving more than read-only access",
+		func(grant Grant) {
+			_, err := grant.ToSQL("cnpg_monitor")
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("write privilege", Grant{Privilege: "INSERT", Object: "sales.orders"}),
+		Entry("unsupported object", Grant{On: "database", Object: "app"}),
+		Entry("role membership", Grant{On: "role", Object: "postgres"}),
+		Entry("missing object", Grant{On: "table"}),
+		Entry("qualified schema", Grant{On: "schema", Object: "sales.orders"}),
+		Entry("invalid table", Grant{Object: "a.b.c"}),
+	)
+})
@@ -35,6 +35,7 @@ type UserQuery struct {
 	CacheSeconds    uint64    `yaml:"cache_seconds"`
 	RunOnServer     string    `yaml:"runonserver"`
 	TargetDatabases []string  `yaml:"target_databases"`
+	Grants          []Grant   `yaml:"grants"`
 }
 
 // Mapping decide how a certain field, extracted from the query's result, should be used
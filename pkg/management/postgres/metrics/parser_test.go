@@ -17,11 +17,36 @@ limitations under the License.
 package metrics
 
 import (
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Metrics parser", func() {
+	It("parses the default monitoring queries", func() {
+		content, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "config", "manager", "default-monitoring.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+
+		var configMap corev1.ConfigMap
+		Expect(yaml.Unmarshal(content, &configMap)).To(Succeed())
+
+		result, err := ParseQueries([]byte(configMap.Data["queries"]))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(result).To(HaveKey("pg_largeobject"))
+		Expect(result["pg_largeobject"].TargetDatabases).To(Equal([]string{"*"}))
+		Expect(result["pg_largeobject"].Metrics).To(HaveLen(3))
+
+		Expect(result).To(HaveKey("pg_toast"))
+		Expect(result["pg_toast"].TargetDatabases).To(Equal([]string{"*"}))
+		Expect(result["pg_toast"].Metrics).To(HaveLen(7))
+	})
+
 	It("correctly handles the postgres_exporter example queries", func() {
 		result, err := ParseQueries([]byte(pgExporterQueries))
 		Expect(err).To(BeNil())
@@ -0,0 +1,143 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ValidateQueries dry-runs, with the passed statement timeout, every query
+// that would be executed by this instance against its target databases,
+// returning the errors of the broken queries indexed by query name
+func (q *QueriesCollector) ValidateQueries(timeout time.Duration) (map[string]error, error) {
+	isPrimary, err := q.instance.IsPrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	var allAccessibleDatabasesCache []string
+	result := make(map[string]error)
+	for name, userQuery := range q.userQueries {
+		if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
+			continue
+		}
+
+		if runOnServer := userQuery.RunOnServer; runOnServer != "" {
+			matchesVersion, err := q.checkRunOnServerMatches(runOnServer, name)
+			if err != nil {
+				result[name] = fmt.Errorf("invalid runonserver range %q: %w", runOnServer, err)
+				continue
+			}
+			if !matchesVersion {
+				continue
+			}
+		}
+
+		targetDatabases := userQuery.TargetDatabases
+		if len(targetDatabases) == 0 {
+			targetDatabases = append(targetDatabases, q.defaultDBName)
+		}
+
+		if allAccessibleDatabasesCache == nil && hasPathPattern(targetDatabases) {
+			databases, err := q.getAllAccessibleDatabases()
+			if err != nil {
+				return nil, err
+			}
+			allAccessibleDatabasesCache = databases
+		}
+
+		for targetDatabase := range q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache) {
+			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
+			if err == nil {
+				err = validateQuery(conn, userQuery.Query, len(q.mappings[name]), timeout)
+			}
+			if err != nil {
+				log.Warning("Broken monitoring query", "query", name, "targetDatabase", targetDatabase,
+					"error", err.Error())
+				result[name] = fmt.Errorf("on database %s: %w", targetDatabase, err)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// hasPathPattern checks whether one of the passed target databases contains
+// a pattern
+func hasPathPattern(targetDatabases []string) bool {
+	for _, targetDatabase := range targetDatabases {
+		if isPathPattern.MatchString(targetDatabase) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateQuery executes the passed query inside a read-only transaction,
+// which is rolled back, checking that it completes within the timeout
+// and that it returns the expected number of columns
+func validateQuery(conn *sql.DB, query string, expectedColumns int, timeout time.Duration) error {
+	tx, err := createMonitoringTx(conn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout TO %d", timeout.Milliseconds())); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := checkColumnsNumber(len(columns), expectedColumns); err != nil {
+		return err
+	}
+
+	// The errors raised while executing the query and the statement timeout
+	// are only reported when fetching the rows
+	for rows.Next() {
+		continue
+	}
+	return rows.Err()
+}
+
+// checkColumnsNumber checks that the number of columns returned by a query
+// matches the number of mapped columns, as the metrics of a query not
+// respecting this are never collected
+func checkColumnsNumber(columns, expectedColumns int) error {
+	if columns != expectedColumns {
+		return fmt.Errorf("the query returns %d columns, while %d are mapped", columns, expectedColumns)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queries validation", func() {
+	It("detects the target databases containing a pattern", func() {
+		Expect(hasPathPattern([]string{"app", "postgres"})).To(BeFalse())
+		Expect(hasPathPattern([]string{"app", "db_*"})).To(BeTrue())
+	})
+
+	It("checks the number of the returned columns", func() {
+		Expect(checkColumnsNumber(2, 2)).To(Succeed())
+		Expect(checkColumnsNumber(3, 2)).To(MatchError("the query returns 3 columns, while 2 are mapped"))
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// pluginStatusTimeout is the time allowed to probe the plugins of the
+// instance
+const pluginStatusTimeout = 5 * time.Second
+
+// pluginStatusTracker keeps the status of the plugins of the instance
+// collected by the last probe, to not connect to every plugin each time
+// the status of the instance is requested
+type pluginStatusTracker struct {
+	lock     sync.Mutex
+	statuses []postgres.PluginStatus
+}
+
+// RefreshPluginStatus probes the plugins running as sidecars of the
+// instance, storing their status
+func (instance *Instance) RefreshPluginStatus(ctx context.Context) {
+	var statuses []postgres.PluginStatus
+	if plugins := instance.Plugins; len(plugins) > 0 {
+		ctx, cancel := context.WithTimeout(ctx, pluginStatusTimeout)
+		defer cancel()
+
+		statuses = make([]postgres.PluginStatus, 0, len(plugins))
+		for _, name := range plugins {
+			statuses = append(statuses, plugin.GetStatus(ctx, name))
+		}
+	}
+
+	tracker := &instance.pluginStatus
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	tracker.statuses = statuses
+}
+
+// getPluginStatus returns the status of the plugins collected by the
+// last probe
+func (instance *Instance) getPluginStatus() []postgres.PluginStatus {
+	tracker := &instance.pluginStatus
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if len(tracker.statuses) == 0 {
+		return nil
+	}
+
+	result := make([]postgres.PluginStatus, len(tracker.statuses))
+	copy(result, tracker.statuses)
+	return result
+}
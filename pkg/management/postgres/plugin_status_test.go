@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("plugin status", func() {
+	It("reports the status collected by the last probe", func() {
+		instance := &Instance{}
+		Expect(instance.getPluginStatus()).To(BeNil())
+
+		instance.pluginStatus.statuses = []postgres.PluginStatus{{Name: "pgbackrest", Ready: true}}
+		statuses := instance.getPluginStatus()
+		Expect(statuses).To(Equal([]postgres.PluginStatus{{Name: "pgbackrest", Ready: true}}))
+
+		statuses[0].Ready = false
+		Expect(instance.getPluginStatus()[0].Ready).To(BeTrue())
+	})
+
+	It("forgets the status of the plugins when they are removed", func() {
+		instance := &Instance{}
+		instance.pluginStatus.statuses = []postgres.PluginStatus{{Name: "pgbackrest", Ready: true}}
+
+		instance.RefreshPluginStatus(context.Background())
+		Expect(instance.getPluginStatus()).To(BeNil())
+	})
+})
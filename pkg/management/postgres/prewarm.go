@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// groupPrewarmRelationsByDatabase returns the passed relations grouped by
+// the database containing them, preserving the order in which databases
+// and relations have been specified
+func groupPrewarmRelationsByDatabase(relations []apiv1.PrewarmRelation) ([]string, map[string][]string) {
+	var databases []string
+	relationsByDatabase := make(map[string][]string)
+	for _, relation := range relations {
+		if _, ok := relationsByDatabase[relation.Database]; !ok {
+			databases = append(databases, relation.Database)
+		}
+		relationsByDatabase[relation.Database] = append(relationsByDatabase[relation.Database], relation.Name)
+	}
+
+	return databases, relationsByDatabase
+}
+
+// Prewarm loads the passed relations into the shared buffers of this instance
+// using the `pg_prewarm` extension, processing at most maxParallel relations
+// at the same time. Errors on single relations are logged and do not stop the
+// prewarm of the remaining ones
+func (instance *Instance) Prewarm(
+	ctx context.Context,
+	relations []apiv1.PrewarmRelation,
+	maxParallel int,
+) error {
+	contextLogger := log.FromContext(ctx)
+	if len(relations) == 0 {
+		return nil
+	}
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	databases, relationsByDatabase := groupPrewarmRelationsByDatabase(relations)
+
+	var failures int
+	var lastErr error
+	var failuresMutex sync.Mutex
+	recordFailure := func(err error) {
+		failuresMutex.Lock()
+		defer failuresMutex.Unlock()
+		failures++
+		lastErr = err
+	}
+
+	startTime := time.Now()
+	semaphore := make(chan struct{}, maxParallel)
+	var waitGroup sync.WaitGroup
+	for _, database := range databases {
+		db, err := instance.ConnectionPool().Connection(database)
+		if err != nil {
+			recordFailure(fmt.Errorf("while connecting to database %s: %w", database, err))
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pg_prewarm"); err != nil {
+			recordFailure(fmt.Errorf("while creating the pg_prewarm extension in database %s: %w", database, err))
+			continue
+		}
+
+		for _, relation := range relationsByDatabase[database] {
+			waitGroup.Add(1)
+			semaphore <- struct{}{}
+			go func(database, relation string) {
+				defer func() {
+					<-semaphore
+					waitGroup.Done()
+				}()
+
+				var blocks int64
+				row := db.QueryRowContext(ctx, "SELECT pg_prewarm($1::regclass)", relation)
+				if err := row.Scan(&blocks); err != nil {
+					contextLogger.Warning("Error while prewarming relation",
+						"database", database,
+						"relation", relation,
+						"error", err)
+					recordFailure(fmt.Errorf("while prewarming %s in database %s: %w", relation, database, err))
+					return
+				}
+
+				contextLogger.Info("Prewarmed relation",
+					"database", database,
+					"relation", relation,
+					"blocks", blocks)
+			}(database, relation)
+		}
+	}
+
+	waitGroup.Wait()
+	contextLogger.Info("Prewarm completed",
+		"relations", len(relations),
+		"failures", failures,
+		"elapsedTime", time.Since(startTime))
+
+	if lastErr != nil {
+		return fmt.Errorf("%d prewarm operations failed, last error: %w", failures, lastErr)
+	}
+
+	return nil
+}
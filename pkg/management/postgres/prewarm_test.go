@@ -0,0 +1,43 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("prewarm relations grouping", func() {
+	It("groups the relations by database preserving their order", func() {
+		databases, relations := groupPrewarmRelationsByDatabase([]apiv1.PrewarmRelation{
+			{Database: "app", Name: "orders"},
+			{Database: "analytics", Name: "events"},
+			{Database: "app", Name: "orders_pkey"},
+		})
+		Expect(databases).To(Equal([]string{"app", "analytics"}))
+		Expect(relations).To(HaveKeyWithValue("app", []string{"orders", "orders_pkey"}))
+		Expect(relations).To(HaveKeyWithValue("analytics", []string{"events"}))
+	})
+
+	It("returns nothing when no relation is configured", func() {
+		databases, relations := groupPrewarmRelationsByDatabase(nil)
+		Expect(databases).To(BeEmpty())
+		Expect(relations).To(BeEmpty())
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -106,11 +108,19 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 			-- True if at least one column requires a restart
 			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart),
 			-- The size of database in human readable format
-			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database)`)
-	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize)
+			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database),
+			-- The number of connections opened by the clients
+			(SELECT count(*) FROM pg_catalog.pg_stat_activity WHERE backend_type = 'client backend'),
+			-- True if the settings allow pg_rewind to be used
+			(current_setting('data_checksums')::bool OR current_setting('wal_log_hints')::bool)
+				AND current_setting('full_page_writes')::bool`)
+	var isRewindSupported bool
+	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize,
+		&result.ClientConnections, &isRewindSupported)
 	if err != nil {
 		return result, err
 	}
+	result.IsRewindSupported = &isRewindSupported
 
 	if result.PendingRestart {
 		err = updateResultForDecrease(instance, superUserDB, result)
@@ -124,6 +134,16 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
+	// The detection of long-running transactions is not essential to the
+	// status of the instance, so its failures are not reported
+	result.LongRunningTransactions, err = instance.GetLongRunningTransactions(superUserDB)
+	if err != nil {
+		log.Warning("Cannot detect the long-running transactions", "error", err)
+	}
+
+	result.PluginStatus = instance.getPluginStatus()
+	result.StorageForecasts = instance.GetStorageForecasts()
+
 	result.InstanceArch = runtime.GOARCH
 
 	result.ExecutableHash, err = executablehash.Get()
@@ -293,8 +313,36 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 		&result.CurrentLsn,
 		&result.TimeLineID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	result.TimelineHistory, err = instance.readTimelineHistory(result.TimeLineID)
+	if err != nil {
+		// The timeline history is only used to diagnose the replicas
+		// that diverged, and shouldn't prevent the status from being reported
+		log.Warning("Cannot read the timeline history", "timeline", result.TimeLineID, "err", err)
+	}
+
+	return nil
+}
+
+// readTimelineHistory reads the history file of the passed timeline from the
+// WAL directory. The first timeline has no history
+func (instance *Instance) readTimelineHistory(timelineID int) ([]postgres.TimelineHistoryEntry, error) {
+	if timelineID <= 1 {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(instance.PgData, "pg_wal", postgres.TimelineHistoryFileName(timelineID)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return postgres.ParseTimelineHistory(string(content))
 }
 
 func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
@@ -385,8 +433,10 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 			coalesce(flush_lag, '0'::interval),
 			coalesce(replay_lag, '0'::interval),
 			coalesce(sync_state, ''),
-			coalesce(sync_priority, 0)
+			coalesce(sync_priority, 0),
+			coalesce(ssl, false)
 		FROM pg_catalog.pg_stat_replication
+		LEFT JOIN pg_catalog.pg_stat_ssl USING (pid)
 		WHERE application_name LIKE $1 AND usename = $2`,
 		fmt.Sprintf("%s-%%", instance.ClusterName),
 		v1.StreamingReplicationUser,
@@ -399,6 +449,7 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 
 	for rows.Next() {
 		pgr := postgres.PgStatReplication{}
+		var encrypted bool
 		err := rows.Scan(
 			&pgr.ApplicationName,
 			&pgr.State,
@@ -411,10 +462,12 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 			&pgr.ReplayLag,
 			&pgr.SyncState,
 			&pgr.SyncPriority,
+			&encrypted,
 		)
 		if err != nil {
 			return err
 		}
+		pgr.Encrypted = &encrypted
 		replicationInfo = append(replicationInfo, pgr)
 	}
 	result.ReplicationInfo = replicationInfo
@@ -439,10 +492,13 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 	}
 
 	// pg_last_wal_receive_lsn may be NULL when using non-streaming
-	// replicas
+	// replicas. The timeline being received is reported by the WAL
+	// receiver, as the one of the last checkpoint lags behind it: only
+	// the non-streaming replicas fall back to the latter
 	row := superUserDB.QueryRow(
 		"SELECT " +
-			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
+			"COALESCE((SELECT received_tli FROM pg_catalog.pg_stat_wal_receiver), " +
+			"(SELECT timeline_id FROM pg_catalog.pg_control_checkpoint())), " +
 			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
 			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
 			"pg_is_wal_replay_paused()")
@@ -463,9 +519,51 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 	if err != nil {
 		return err
 	}
+
+	result.SynchronizedLogicalSlots, err = instance.getSynchronizedLogicalSlots()
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+// getSynchronizedLogicalSlots gets the logical replication slots which
+// have been synchronized from the primary and can be used after a
+// promotion. This requires PostgreSQL 17 or newer.
+func (instance *Instance) getSynchronizedLogicalSlots() ([]string, error) {
+	version, err := instance.GetPgVersion()
+	if err != nil || version.Major < 17 {
+		return nil, err
+	}
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := superUserDB.Query(
+		"SELECT slot_name FROM pg_catalog.pg_replication_slots " +
+			"WHERE slot_type = 'logical' AND synced AND NOT temporary AND invalidation_reason IS NULL " +
+			"ORDER BY slot_name")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var slots []string
+	for rows.Next() {
+		var slotName string
+		if err := rows.Scan(&slotName); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slotName)
+	}
+
+	return slots, rows.Err()
+}
+
 // IsWALReceiverActive check if the WAL receiver process is active by looking
 // at the number of records in the `pg_stat_wal_receiver` table
 func (instance *Instance) IsWALReceiverActive() (bool, error) {
@@ -0,0 +1,192 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ErrPublicationNotAlterable is raised when an existing publication doesn't
+// match the fields of its specification which can be set only at
+// creation time
+var ErrPublicationNotAlterable = errors.New("the publication cannot be altered to match its specification")
+
+// buildLogicalReplicationParameters builds the list of parameters used in
+// the WITH and SET clauses of publications and subscriptions, sorted
+// by name to get a stable statement
+func buildLogicalReplicationParameters(parameters map[string]string) string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := make([]string, 0, len(names))
+	for _, name := range names {
+		definitions = append(definitions, fmt.Sprintf(
+			"%s = %s",
+			pgx.Identifier{name}.Sanitize(),
+			pq.QuoteLiteral(parameters[name])))
+	}
+
+	return strings.Join(definitions, ", ")
+}
+
+// buildPublicationTableList builds the list of the tables to be published
+func buildPublicationTableList(tables []apiv1.PublicationTable) string {
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if table.Schema == "" {
+			names = append(names, pgx.Identifier{table.Name}.Sanitize())
+			continue
+		}
+		names = append(names, pgx.Identifier{table.Schema, table.Name}.Sanitize())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// buildCreatePublicationStatement builds the statement creating the
+// passed publication
+func buildCreatePublicationStatement(spec apiv1.PublicationSpec) string {
+	statement := fmt.Sprintf("CREATE PUBLICATION %s", pgx.Identifier{spec.Name}.Sanitize())
+	if spec.Target.AllTables {
+		statement += " FOR ALL TABLES"
+	} else {
+		statement += " FOR TABLE " + buildPublicationTableList(spec.Target.Tables)
+	}
+
+	if len(spec.Parameters) > 0 {
+		statement += fmt.Sprintf(" WITH (%s)", buildLogicalReplicationParameters(spec.Parameters))
+	}
+
+	return statement
+}
+
+// buildAlterPublicationStatements builds the statements aligning an
+// existing publication to its specification
+func buildAlterPublicationStatements(spec apiv1.PublicationSpec) []string {
+	var statements []string
+	name := pgx.Identifier{spec.Name}.Sanitize()
+
+	if !spec.Target.AllTables {
+		statements = append(statements, fmt.Sprintf(
+			"ALTER PUBLICATION %s SET TABLE %s",
+			name,
+			buildPublicationTableList(spec.Target.Tables)))
+	}
+
+	if len(spec.Parameters) > 0 {
+		statements = append(statements, fmt.Sprintf(
+			"ALTER PUBLICATION %s SET (%s)",
+			name,
+			buildLogicalReplicationParameters(spec.Parameters)))
+	}
+
+	return statements
+}
+
+// ReconcilePublication creates or alters a publication to match the
+// passed specification. Parameters removed from the specification
+// are not reset
+func (instance *Instance) ReconcilePublication(ctx context.Context, spec apiv1.PublicationSpec) error {
+	contextLogger := log.FromContext(ctx).WithValues("publication", spec.Name, "dbname", spec.DBName)
+
+	db, err := instance.ConnectionPool().Connection(spec.DBName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", spec.DBName, err)
+	}
+
+	var allTables bool
+	row := db.QueryRowContext(ctx,
+		"SELECT puballtables FROM pg_catalog.pg_publication WHERE pubname = $1",
+		spec.Name)
+	err = row.Scan(&allTables)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		contextLogger.Info("Creating publication")
+		if _, err = db.ExecContext(ctx, buildCreatePublicationStatement(spec)); err != nil {
+			return fmt.Errorf("while creating publication %s: %w", spec.Name, err)
+		}
+		return nil
+
+	case err != nil:
+		return fmt.Errorf("while reading publication %s: %w", spec.Name, err)
+	}
+
+	if allTables != spec.Target.AllTables {
+		return fmt.Errorf("%w: the publication of all the tables is %v instead of %v",
+			ErrPublicationNotAlterable, allTables, spec.Target.AllTables)
+	}
+
+	for _, statement := range buildAlterPublicationStatements(spec) {
+		if _, err = db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while altering publication %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetLogicalReplicationSlots returns the status of the logical replication
+// slots of the passed database, which are used by the subscribers of the
+// publications in it
+func (instance *Instance) GetLogicalReplicationSlots(
+	ctx context.Context,
+	dbname string,
+) ([]apiv1.LogicalReplicationSlotStatus, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, fmt.Errorf("while getting superuser database: %w", err)
+	}
+
+	rows, err := superUserDB.QueryContext(ctx,
+		"SELECT slot_name, active, "+
+			"COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn), 0)::bigint "+
+			"FROM pg_catalog.pg_replication_slots "+
+			"WHERE slot_type = 'logical' AND database = $1 "+
+			"ORDER BY slot_name",
+		dbname)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var slots []apiv1.LogicalReplicationSlotStatus
+	for rows.Next() {
+		var slot apiv1.LogicalReplicationSlotStatus
+		if err := rows.Scan(&slot.Name, &slot.Active, &slot.LagBytes); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
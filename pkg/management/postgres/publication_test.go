@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative publications", func() {
+	tablesSpec := apiv1.PublicationSpec{
+		Name:   "pub",
+		DBName: "app",
+		Target: apiv1.PublicationTarget{
+			Tables: []apiv1.PublicationTable{
+				{Schema: "sales", Name: "orders"},
+				{Name: "customers"},
+			},
+		},
+	}
+
+	It("creates a publication of all the tables", func() {
+		spec := apiv1.PublicationSpec{Name: "pub", DBName: "app", Target: apiv1.PublicationTarget{AllTables: true}}
+		Expect(buildCreatePublicationStatement(spec)).To(Equal(`CREATE PUBLICATION "pub" FOR ALL TABLES`))
+	})
+
+	It("creates a publication of a list of tables with parameters", func() {
+		spec := tablesSpec
+		spec.Parameters = map[string]string{"publish_via_partition_root": "true", "publish": "insert, update"}
+		Expect(buildCreatePublicationStatement(spec)).To(Equal(
+			`CREATE PUBLICATION "pub" FOR TABLE "sales"."orders", "customers" ` +
+				`WITH ("publish" = 'insert, update', "publish_via_partition_root" = 'true')`))
+	})
+
+	It("aligns the tables and the parameters of an existing publication", func() {
+		spec := tablesSpec
+		spec.Parameters = map[string]string{"publish": "insert"}
+		Expect(buildAlterPublicationStatements(spec)).To(Equal([]string{
+			`ALTER PUBLICATION "pub" SET TABLE "sales"."orders", "customers"`,
+			`ALTER PUBLICATION "pub" SET ("publish" = 'insert')`,
+		}))
+	})
+
+	It("doesn't alter a publication of all the tables without parameters", func() {
+		spec := apiv1.PublicationSpec{Name: "pub", DBName: "app", Target: apiv1.PublicationTarget{AllTables: true}}
+		Expect(buildAlterPublicationStatements(spec)).To(BeEmpty())
+	})
+
+	It("quotes the identifiers and the literals", func() {
+		spec := apiv1.PublicationSpec{
+			Name:       `my"pub`,
+			Target:     apiv1.PublicationTarget{Tables: []apiv1.PublicationTable{{Name: `my"table`}}},
+			Parameters: map[string]string{"publish": "insert'"},
+		}
+		Expect(buildCreatePublicationStatement(spec)).To(Equal(
+			`CREATE PUBLICATION "my""pub" FOR TABLE "my""table" WITH ("publish" = 'insert''')`))
+	})
+})
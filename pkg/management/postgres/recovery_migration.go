@@ -0,0 +1,220 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+)
+
+const (
+	// legacyRecoveryConfFile is the file containing the recovery settings
+	// before PostgreSQL 12
+	legacyRecoveryConfFile = "recovery.conf"
+
+	// migratedRecoveryConfFile is the name given to the legacy recovery
+	// file after its settings have been migrated
+	migratedRecoveryConfFile = "recovery.conf.migrated"
+)
+
+// recoveryGUCs are the options of a legacy recovery.conf file which are
+// plain GUCs since PostgreSQL 12
+var recoveryGUCs = map[string]bool{
+	"archive_cleanup_command":   true,
+	"primary_conninfo":          true,
+	"primary_slot_name":         true,
+	"recovery_end_command":      true,
+	"recovery_min_apply_delay":  true,
+	"recovery_target":           true,
+	"recovery_target_action":    true,
+	"recovery_target_inclusive": true,
+	"recovery_target_lsn":       true,
+	"recovery_target_name":      true,
+	"recovery_target_time":      true,
+	"recovery_target_timeline":  true,
+	"recovery_target_xid":       true,
+	"restore_command":           true,
+}
+
+// recoveryTargets are the options setting the recovery target. Since
+// PostgreSQL 12 at most one of them can be used
+var recoveryTargets = []string{
+	"recovery_target",
+	"recovery_target_lsn",
+	"recovery_target_name",
+	"recovery_target_time",
+	"recovery_target_xid",
+}
+
+// MigrateLegacyRecoveryConfiguration translates the recovery.conf file found
+// in the data directory of PostgreSQL 12 or newer, which would refuse to
+// start, to the signal file and the GUCs in postgresql.auto.conf. This
+// happens with data directories coming from an old backup tool or adopted
+// from a different deployment. The recovery.conf file is kept as
+// recovery.conf.migrated. An error is returned when the file cannot be migrated
+func MigrateLegacyRecoveryConfiguration(pgData string) (migrated bool, err error) {
+	recoveryConfPath := filepath.Join(pgData, legacyRecoveryConfFile)
+	exists, err := fileutils.FileExists(recoveryConfPath)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	major, err := postgresutils.GetMajorVersion(pgData)
+	if err != nil {
+		return false, err
+	}
+	if major < 12 {
+		// recovery.conf is still the way to configure the recovery
+		return false, nil
+	}
+
+	content, err := fileutils.ReadFile(recoveryConfPath)
+	if err != nil {
+		return false, err
+	}
+
+	options, err := configfile.ParseConfigurationContents(string(content))
+	if err != nil {
+		return false, fmt.Errorf("cannot parse %s: %w", legacyRecoveryConfFile, err)
+	}
+
+	gucs, standby, err := translateLegacyRecoveryOptions(options, major)
+	if err != nil {
+		return false, fmt.Errorf("cannot migrate %s to PostgreSQL %d: %w", legacyRecoveryConfFile, major, err)
+	}
+
+	if _, err := configfile.UpdatePostgresConfigurationFile(
+		filepath.Join(pgData, "postgresql.auto.conf"), gucs); err != nil {
+		return false, err
+	}
+
+	signalFile := "recovery.signal"
+	if standby {
+		signalFile = "standby.signal"
+	}
+	if err := fileutils.CreateEmptyFile(filepath.Join(pgData, signalFile)); err != nil {
+		return false, err
+	}
+
+	if err := fileutils.MoveFile(recoveryConfPath, filepath.Join(pgData, migratedRecoveryConfFile)); err != nil {
+		return false, err
+	}
+
+	// The values are not logged, as primary_conninfo may contain a password
+	migratedOptions := make([]string, 0, len(gucs))
+	for name := range gucs {
+		migratedOptions = append(migratedOptions, name)
+	}
+	sort.Strings(migratedOptions)
+	log.Info("Migrated the legacy recovery configuration",
+		"pgdata", pgData,
+		"signalFile", signalFile,
+		"options", migratedOptions)
+	return true, nil
+}
+
+// discardLegacyRecoveryConfiguration renames the recovery.conf file of the
+// data directory, if found, to recovery.conf.migrated without migrating its settings
+func discardLegacyRecoveryConfiguration(pgData string) error {
+	recoveryConfPath := filepath.Join(pgData, legacyRecoveryConfFile)
+	exists, err := fileutils.FileExists(recoveryConfPath)
+	if err != nil || !exists {
+		return err
+	}
+
+	log.Info("Discarding the legacy recovery configuration found in the backup", "pgdata", pgData)
+	return fileutils.MoveFile(recoveryConfPath, filepath.Join(pgData, migratedRecoveryConfFile))
+}
+
+// translateLegacyRecoveryOptions translates the options of a recovery.conf
+// file to the GUCs of the passed PostgreSQL major version, telling whether
+// the instance is a standby
+func translateLegacyRecoveryOptions(
+	options map[string]string,
+	major int,
+) (gucs map[string]string, standby bool, err error) {
+	gucs = make(map[string]string, len(options))
+
+	for name, value := range options {
+		switch {
+		case recoveryGUCs[name]:
+			gucs[name] = value
+
+		case name == "standby_mode":
+			if standby, err = parseBoolean(value); err != nil {
+				return nil, false, fmt.Errorf("invalid standby_mode: %w", err)
+			}
+
+		case name == "trigger_file" && major < 16:
+			gucs["promote_trigger_file"] = value
+
+		case name == "trigger_file":
+			// The trigger file is not supported anymore, and the
+			// operator promotes the instances with pg_ctl
+			log.Warning("Ignoring the trigger_file option, not supported by this PostgreSQL version",
+				"triggerFile", value, "major", major)
+
+		case name == "pause_at_recovery_target":
+			pause, err := parseBoolean(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid pause_at_recovery_target: %w", err)
+			}
+			if _, found := options["recovery_target_action"]; found {
+				return nil, false, fmt.Errorf(
+					"pause_at_recovery_target and recovery_target_action cannot be both set")
+			}
+			gucs["recovery_target_action"] = "promote"
+			if pause {
+				gucs["recovery_target_action"] = "pause"
+			}
+
+		default:
+			return nil, false, fmt.Errorf("unsupported option %s", name)
+		}
+	}
+
+	var targets []string
+	for _, target := range recoveryTargets {
+		if _, found := gucs[target]; found {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) > 1 {
+		return nil, false, fmt.Errorf("multiple recovery targets set: %s", strings.Join(targets, ", "))
+	}
+
+	return gucs, standby, nil
+}
+
+// parseBoolean parses a boolean value with the syntax of PostgreSQL
+func parseBoolean(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1", "t", "y":
+		return true, nil
+	case "off", "false", "no", "0", "f", "n":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%q is not a boolean", value)
+}
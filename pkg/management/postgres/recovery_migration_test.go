@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("legacy recovery configuration migration", func() {
+	var pgData string
+
+	writeFile := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(pgData, name), []byte(content), 0o600)).To(Succeed())
+	}
+
+	fileExists := func(name string) bool {
+		exists, err := fileutils.FileExists(filepath.Join(pgData, name))
+		Expect(err).ToNot(HaveOccurred())
+		return exists
+	}
+
+	BeforeEach(func() {
+		var err error
+		pgData, err = os.MkdirTemp("", "recovery-migration")
+		Expect(err).ToNot(HaveOccurred())
+		writeFile("postgresql.auto.conf", "")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(pgData)).To(Succeed())
+	})
+
+	It("does nothing without a recovery.conf file", func() {
+		writeFile("PG_VERSION", "14\n")
+		Expect(MigrateLegacyRecoveryConfiguration(pgData)).To(BeFalse())
+	})
+
+	It("keeps the recovery.conf file of PostgreSQL 11", func() {
+		writeFile("PG_VERSION", "11\n")
+		writeFile("recovery.conf", "standby_mode = 'on'\n")
+		Expect(MigrateLegacyRecoveryConfiguration(pgData)).To(BeFalse())
+		Expect(fileExists("recovery.conf")).To(BeTrue())
+	})
+
+	It("migrates a standby to the signal file and the GUCs", func() {
+		writeFile("PG_VERSION", "14\n")
+		writeFile("recovery.conf", "standby_mode = 'on'\n"+
+			"primary_conninfo = 'host=primary user=streaming_replica'\n"+
+			"trigger_file = '/tmp/promote'\n")
+
+		Expect(MigrateLegacyRecoveryConfiguration(pgData)).To(BeTrue())
+		Expect(fileExists("standby.signal")).To(BeTrue())
+		Expect(fileExists("recovery.signal")).To(BeFalse())
+		Expect(fileExists("recovery.conf")).To(BeFalse())
+		Expect(fileExists("recovery.conf.migrated")).To(BeTrue())
+
+		autoConf, err := fileutils.ReadFile(filepath.Join(pgData, "postgresql.auto.conf"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(autoConf)).To(ContainSubstring("primary_conninfo = 'host=primary user=streaming_replica'"))
+		Expect(string(autoConf)).To(ContainSubstring("promote_trigger_file = '/tmp/promote'"))
+		Expect(string(autoConf)).ToNot(ContainSubstring("standby_mode"))
+	})
+
+	It("migrates a point in time recovery to the recovery signal file", func() {
+		writeFile("PG_VERSION", "16\n")
+		writeFile("recovery.conf", "restore_command = 'cp /archive/%f %p'\n"+
+			"recovery_target_time = '2022-10-20 11:52:31+00'\n"+
+			"pause_at_recovery_target = false\n"+
+			"trigger_file = '/tmp/promote'\n")
+
+		Expect(MigrateLegacyRecoveryConfiguration(pgData)).To(BeTrue())
+		Expect(fileExists("recovery.signal")).To(BeTrue())
+		Expect(fileExists("standby.signal")).To(BeFalse())
+
+		autoConf, err := fileutils.ReadFile(filepath.Join(pgData, "postgresql.auto.conf"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(autoConf)).To(ContainSubstring("recovery_target_action = 'promote'"))
+		Expect(string(autoConf)).ToNot(ContainSubstring("trigger_file"))
+	})
+
+	It("fails clearly when the recovery.conf file cannot be migrated", func() {
+		writeFile("PG_VERSION", "14\n")
+		writeFile("recovery.conf", "standby_mode = 'on'\nunknown_option = 'value'\n")
+
+		_, err := MigrateLegacyRecoveryConfiguration(pgData)
+		Expect(err).To(MatchError(ContainSubstring("unsupported option unknown_option")))
+		Expect(fileExists("recovery.conf")).To(BeTrue())
+		Expect(fileExists("standby.signal")).To(BeFalse())
+	})
+
+	It("refuses multiple recovery targets", func() {
+		_, _, err := translateLegacyRecoveryOptions(map[string]string{
+			"recovery_target_name": "before-upgrade",
+			"recovery_target_xid":  "1234",
+		}, 14)
+		Expect(err).To(MatchError(ContainSubstring("recovery_target_name, recovery_target_xid")))
+	})
+
+	It("refuses pause_at_recovery_target together with recovery_target_action", func() {
+		_, _, err := translateLegacyRecoveryOptions(map[string]string{
+			"pause_at_recovery_target": "on",
+			"recovery_target_action":   "shutdown",
+		}, 14)
+		Expect(err).To(HaveOccurred())
+	})
+})
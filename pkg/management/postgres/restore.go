@@ -50,6 +50,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 )
 
 var (
@@ -104,8 +105,32 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.restoreDataDir(backup, env); err != nil {
-		return err
+	// The sidecar of the plugin restoring the backup would prevent the
+	// recovery job from completing
+	if backup.IsPlugin() {
+		defer terminatePlugin(ctx, backup.Status.PluginName)
+	}
+
+	if backup.IsVolumeSnapshot() {
+		// The PGDATA volume has been provisioned from the snapshot
+		if err := info.prepareVolumeSnapshotDataDir(backup); err != nil {
+			return err
+		}
+	} else {
+		if err := info.VerifyPGData(); err != nil {
+			return err
+		}
+		if err := info.RunStorageBenchmark(ctx, typedClient, cluster); err != nil {
+			return err
+		}
+		if backup.IsPlugin() {
+			err = info.restorePluginDataDir(ctx, cluster, backup)
+		} else {
+			err = info.restoreDataDir(backup, cluster.Spec.Tablespaces, env)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	if _, err := info.restoreCustomWalDir(ctx); err != nil {
@@ -137,11 +162,19 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	if backup.IsVolumeSnapshot() && !isWalArchiveRecoveryNeeded(backup) {
+		if err := info.writeCrashRecoveryConfig(cluster); err != nil {
+			return err
+		}
+	} else if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+		return err
+	}
+
+	if err := info.ConfigureInstanceAfterRestore(cluster, env); err != nil {
 		return err
 	}
 
-	return info.ConfigureInstanceAfterRestore(cluster, env)
+	return info.anonymizeRecoveredData(ctx, typedClient, cluster, env)
 }
 
 // restoreCustomWalDir moves the current pg_wal data to the specified custom wal dir and applies the symlink
@@ -180,8 +213,13 @@ func (info InitInfo) restoreCustomWalDir(ctx context.Context) (bool, error) {
 	return true, os.Symlink(info.PgWal, pgDataWal)
 }
 
-// restoreDataDir restores PGDATA from an existing backup
-func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
+// restoreDataDir restores PGDATA and the passed tablespaces from an
+// existing backup
+func (info InitInfo) restoreDataDir(
+	backup *apiv1.Backup,
+	tablespaces []apiv1.TablespaceConfiguration,
+	env []string,
+) error {
 	var options []string
 
 	if backup.Status.EndpointURL != "" {
@@ -196,12 +234,22 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 		return err
 	}
 
+	// The tablespaces are restored in the volumes of this instance
+	for _, tablespace := range tablespaces {
+		options = append(options, "--tablespace",
+			fmt.Sprintf("%s:%s", tablespace.Name, specs.LocationForTablespace(tablespace.Name)))
+	}
+
 	options = append(options, info.PgData)
 
 	log.Info("Starting barman-cloud-restore",
 		"options", options)
 
-	cmd := exec.Command(barmanCapabilities.BarmanCloudRestore, options...) // #nosec G204
+	cmd, err := newBarmanCloudCommand(
+		backup.Status.ClientSideEncryption != nil, barmanCapabilities.BarmanCloudRestore, options...)
+	if err != nil {
+		return err
+	}
 	cmd.Env = env
 	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudRestore)
 	if err != nil {
@@ -259,6 +307,26 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	}
 	serverName := server.GetServerName()
 
+	// The plugin chooses the backup to restore, being the only one
+	// knowing its catalog
+	if server.PluginConfiguration != nil {
+		return &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{
+					Name: serverName,
+				},
+				Method:              apiv1.BackupMethodPlugin,
+				PluginConfiguration: server.PluginConfiguration.DeepCopy(),
+			},
+			Status: apiv1.BackupStatus{
+				Method:     apiv1.BackupMethodPlugin,
+				PluginName: server.PluginConfiguration.Name,
+				ServerName: serverName,
+				Phase:      apiv1.BackupPhaseCompleted,
+			},
+		}, os.Environ(), nil
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -298,22 +366,23 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			},
 		},
 		Status: apiv1.BackupStatus{
-			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
-			EndpointCA:        server.BarmanObjectStore.EndpointCA,
-			EndpointURL:       server.BarmanObjectStore.EndpointURL,
-			DestinationPath:   server.BarmanObjectStore.DestinationPath,
-			ServerName:        serverName,
-			BackupID:          targetBackup.ID,
-			Phase:             apiv1.BackupPhaseCompleted,
-			StartedAt:         &metav1.Time{Time: targetBackup.BeginTime},
-			StoppedAt:         &metav1.Time{Time: targetBackup.EndTime},
-			BeginWal:          targetBackup.BeginWal,
-			EndWal:            targetBackup.EndWal,
-			BeginLSN:          targetBackup.BeginLSN,
-			EndLSN:            targetBackup.EndLSN,
-			Error:             targetBackup.Error,
-			CommandOutput:     "",
-			CommandError:      "",
+			BarmanCredentials:    server.BarmanObjectStore.BarmanCredentials,
+			EndpointCA:           server.BarmanObjectStore.EndpointCA,
+			ClientSideEncryption: server.BarmanObjectStore.Encryption,
+			EndpointURL:          server.BarmanObjectStore.EndpointURL,
+			DestinationPath:      server.BarmanObjectStore.DestinationPath,
+			ServerName:           serverName,
+			BackupID:             targetBackup.ID,
+			Phase:                apiv1.BackupPhaseCompleted,
+			StartedAt:            &metav1.Time{Time: targetBackup.BeginTime},
+			StoppedAt:            &metav1.Time{Time: targetBackup.EndTime},
+			BeginWal:             targetBackup.BeginWal,
+			EndWal:               targetBackup.EndWal,
+			BeginLSN:             targetBackup.BeginLSN,
+			EndLSN:               targetBackup.EndLSN,
+			Error:                targetBackup.Error,
+			CommandOutput:        "",
+			CommandError:         "",
 		},
 	}, env, nil
 }
@@ -333,6 +402,14 @@ func (info InitInfo) loadBackupFromReference(
 		return nil, nil, err
 	}
 
+	// A backup taken with the volumeSnapshot method has no WAL archive
+	// when the object store was not configured in the cluster, and the
+	// plugins manage the credentials of their backups
+	if backup.IsPlugin() || (backup.IsVolumeSnapshot() && !backup.Status.BarmanCredentials.ArePopulated()) {
+		log.Info("Recovering existing backup", "backup", backup)
+		return &backup, os.Environ(), nil
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -343,6 +420,7 @@ func (info InitInfo) loadBackupFromReference(
 			EndpointURL:       backup.Status.EndpointURL,
 			DestinationPath:   backup.Status.DestinationPath,
 			ServerName:        backup.Status.ServerName,
+			Encryption:        backup.Status.ClientSideEncryption,
 		},
 		os.Environ())
 	if err != nil {
@@ -353,15 +431,43 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
-// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
-// to complete the WAL recovery from the object storage and then start
-// as a new primary
-func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
-	// Ensure restore_command is used to correctly recover WALs
-	// from the object storage
-	major, err := postgresutils.GetMajorVersion(info.PgData)
-	if err != nil {
-		return fmt.Errorf("cannot detect major version: %w", err)
+// isPartialWALRestoreEnabled checks if the recovery source is receiving
+// the partial WAL segments from the WAL streamer
+func isPartialWALRestoreEnabled(cluster *apiv1.Cluster) bool {
+	sourceName := cluster.Spec.Bootstrap.Recovery.Source
+	if sourceName == "" {
+		return false
+	}
+
+	externalCluster, found := cluster.ExternalCluster(sourceName)
+	return found && externalCluster.BarmanObjectStore.IsWalStreamingEnabled()
+}
+
+// buildRestoreCommand creates the restore_command from the passed
+// barman-cloud-wal-restore invocation. When required, the partial WAL
+// segment is restored in place of a WAL file that has not been archived,
+// allowing the recovery to replay the streamed WAL, and the restored
+// file is decrypted with the client-side encryption keys
+func buildRestoreCommand(cmd []string, withPartialWAL bool, withDecryption bool) string {
+	restoreCommand := strings.Join(append(cmd, "%f", "%p"), " ")
+	if withPartialWAL {
+		restoreCommand = restoreCommand + " || " + strings.Join(append(cmd, "%f.partial", "%p"), " ")
+	}
+	if !withDecryption {
+		return restoreCommand
+	}
+
+	if withPartialWAL {
+		restoreCommand = "(" + restoreCommand + ")"
+	}
+	return restoreCommand + " && /controller/manager wal-decrypt %p"
+}
+
+// getRestoreCommand creates the restore_command fetching the WAL files
+// from the archive storing the passed backup
+func getRestoreCommand(backup *apiv1.Backup, cluster *apiv1.Cluster) (string, error) {
+	if backup.IsPlugin() {
+		return buildPluginRestoreCommand(backup), nil
 	}
 
 	const barmanCloudWalRestoreName = "barman-cloud-wal-restore"
@@ -373,18 +479,35 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 	cmd = append(cmd, backup.Status.DestinationPath)
 	cmd = append(cmd, backup.Status.ServerName)
 
-	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
+	cmd, err := barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return buildRestoreCommand(cmd, isPartialWALRestoreEnabled(cluster), backup.Status.ClientSideEncryption != nil), nil
+}
+
+// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
+// to complete the WAL recovery from the object storage and then start
+// as a new primary
+func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
+	// Ensure restore_command is used to correctly recover WALs
+	// from the object storage
+	major, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect major version: %w", err)
 	}
 
-	cmd = append(cmd, "%f", "%p")
+	restoreCommand, err := getRestoreCommand(backup, cluster)
+	if err != nil {
+		return err
+	}
 
 	recoveryFileContents := fmt.Sprintf(
 		"recovery_target_action = promote\n"+
 			"restore_command = '%s'\n"+
 			"%s",
-		strings.Join(cmd, " "),
+		restoreCommand,
 		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
 
 	log.Info("Generated recovery configuration", "configuration", recoveryFileContents)
@@ -414,6 +537,12 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 	}
 
 	if major >= 12 {
+		// The recovery is entirely configured here, and a recovery.conf
+		// file found in the backup would prevent PostgreSQL from starting
+		if err := discardLegacyRecoveryConfiguration(info.PgData); err != nil {
+			return err
+		}
+
 		// Append restore_command to the end of the
 		// custom configs file
 		err = fileutils.AppendStringToFile(
@@ -602,7 +731,7 @@ func (info InitInfo) ConfigureInstanceAfterRestore(cluster *apiv1.Cluster, env [
 	}
 
 	if majorVersion >= 12 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetPrimaryConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -627,8 +756,8 @@ func (info InitInfo) ConfigureInstanceAfterRestore(cluster *apiv1.Cluster, env [
 }
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
-func (info InitInfo) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName)
+func (info InitInfo) GetPrimaryConnInfo(cluster *apiv1.Cluster) string {
+	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName, cluster.GetReplicationSSLMode())
 }
 
 func (info *InitInfo) checkBackupDestination(
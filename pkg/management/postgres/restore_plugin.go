@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// getBackupPluginParameters gets the parameters the plugin storing the
+// passed backup is called with
+func getBackupPluginParameters(cluster *apiv1.Cluster, backup *apiv1.Backup) map[string]string {
+	var overrides map[string]string
+	if backup.Spec.PluginConfiguration != nil {
+		overrides = backup.Spec.PluginConfiguration.Parameters
+	}
+	return cluster.GetPluginParameters(backup.Status.PluginName, overrides)
+}
+
+// restorePluginDataDir restores PGDATA, the WAL volume and the passed
+// tablespaces asking the plugin storing the backup
+func (info InitInfo) restorePluginDataDir(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) error {
+	pluginName := backup.Status.PluginName
+	pluginClient, err := plugin.Connect(ctx, pluginName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	// The tablespaces are restored in the volumes of this instance
+	tablespaces := make(map[string]string, len(cluster.Spec.Tablespaces))
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		tablespaces[tablespace.Name] = specs.LocationForTablespace(tablespace.Name)
+	}
+
+	log.Info("Starting the restore with the plugin",
+		"plugin", pluginName,
+		"backupID", backup.Status.BackupID,
+		"serverName", backup.Status.ServerName)
+	if _, err := pluginClient.Restore.Restore(ctx, &pluginapi.RestoreRequest{
+		Cluster:     plugin.NewClusterInfo(cluster.Namespace, cluster.Name, info.PodName),
+		Parameters:  getBackupPluginParameters(cluster, backup),
+		BackupId:    backup.Status.BackupID,
+		ServerName:  backup.Status.ServerName,
+		Metadata:    backup.Status.PluginMetadata,
+		PgData:      info.PgData,
+		PgWal:       info.PgWal,
+		Tablespaces: tablespaces,
+	}); err != nil {
+		return fmt.Errorf("while restoring the backup with the plugin %s: %w", pluginName, err)
+	}
+
+	log.Info("Restore completed")
+	return nil
+}
+
+// terminatePlugin asks the plugin which restored the backup to exit,
+// letting the recovery job complete
+func terminatePlugin(ctx context.Context, pluginName string) {
+	pluginClient, err := plugin.Connect(ctx, pluginName)
+	if err != nil {
+		log.Warning("Cannot connect to the plugin to terminate it", "plugin", pluginName, "error", err)
+		return
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	if _, err := pluginClient.Identity.Terminate(ctx, &pluginapi.TerminateRequest{}); err != nil {
+		log.Warning("Cannot terminate the plugin", "plugin", pluginName, "error", err)
+	}
+}
+
+// buildPluginRestoreCommand creates the restore_command restoring the
+// WAL files with the plugin storing the passed backup
+func buildPluginRestoreCommand(backup *apiv1.Backup) string {
+	return strings.Join([]string{
+		"/controller/manager", "wal-restore",
+		"--plugin", backup.Status.PluginName,
+		"--server-name", backup.Status.ServerName,
+		"%f", "%p",
+	}, " ")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/thoas/go-funk"
 	"k8s.io/utils/strings/slices"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -148,3 +149,37 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("restore_command", func() {
+	cmd := []string{"barman-cloud-wal-restore", "s3://bucket", "server"}
+
+	It("restores only the archived WAL files by default", func() {
+		Expect(buildRestoreCommand(cmd, false, false)).To(Equal("barman-cloud-wal-restore s3://bucket server %f %p"))
+	})
+
+	It("falls back to the partial WAL segment when required", func() {
+		Expect(buildRestoreCommand(cmd, true, false)).To(Equal(
+			"barman-cloud-wal-restore s3://bucket server %f %p || " +
+				"barman-cloud-wal-restore s3://bucket server %f.partial %p"))
+	})
+
+	It("decrypts the restored WAL file when required", func() {
+		Expect(buildRestoreCommand(cmd, false, true)).To(Equal(
+			"barman-cloud-wal-restore s3://bucket server %f %p && /controller/manager wal-decrypt %p"))
+		Expect(buildRestoreCommand(cmd, true, true)).To(Equal(
+			"(barman-cloud-wal-restore s3://bucket server %f %p || " +
+				"barman-cloud-wal-restore s3://bucket server %f.partial %p) && /controller/manager wal-decrypt %p"))
+	})
+
+	It("restores the WAL files with the plugin storing the backup", func() {
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{Method: apiv1.BackupMethodPlugin},
+			Status: apiv1.BackupStatus{
+				PluginName: "pgbackrest",
+				ServerName: "source",
+			},
+		}
+		Expect(getRestoreCommand(backup, &apiv1.Cluster{})).To(Equal(
+			"/controller/manager wal-restore --plugin pgbackrest --server-name source %f %p"))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+)
+
+// isWalArchiveRecoveryNeeded returns true if restoring the passed backup,
+// taken with the volumeSnapshot method, needs the WAL files in the archive
+func isWalArchiveRecoveryNeeded(backup *apiv1.Backup) bool {
+	return backup.Status.Online != nil && *backup.Status.Online &&
+		backup.Status.DestinationPath != ""
+}
+
+// prepareVolumeSnapshotDataDir prepares the PGDATA provisioned from the
+// snapshot of a running instance to be started. The backup_label and
+// tablespace_map files of an online backup are written, as they are
+// returned by pg_backup_stop instead of being stored in the snapshot
+func (info InitInfo) prepareVolumeSnapshotDataDir(backup *apiv1.Backup) error {
+	pgDataExists, err := fileutils.FileExists(info.PgData)
+	if err != nil {
+		return err
+	}
+	if !pgDataExists {
+		return fmt.Errorf("PGData not found in the volume provisioned from backup %s", backup.Name)
+	}
+
+	// The snapshot has been taken while the instance was running
+	if err := fileutils.RemoveFile(path.Join(info.PgData, PostgresqlPidFile)); err != nil {
+		return fmt.Errorf("while removing the PID file: %w", err)
+	}
+
+	if len(backup.Status.BackupLabelFile) > 0 {
+		log.Info("Writing the backup label of the online volume snapshot backup")
+		if err := os.WriteFile(path.Join(info.PgData, "backup_label"), backup.Status.BackupLabelFile,
+			0o600); err != nil {
+			return fmt.Errorf("while writing the backup label: %w", err)
+		}
+	}
+
+	if len(backup.Status.TablespaceMapFile) > 0 {
+		if err := os.WriteFile(path.Join(info.PgData, "tablespace_map"), backup.Status.TablespaceMapFile,
+			0o600); err != nil {
+			return fmt.Errorf("while writing the tablespace map: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeCrashRecoveryConfig configures PostgreSQL to start from a
+// crash-consistent volume snapshot. The instance replays the WAL files
+// found in the volumes and starts as a new primary, without any
+// point-in-time recovery
+func (info InitInfo) writeCrashRecoveryConfig(cluster *apiv1.Cluster) error {
+	if cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
+		return fmt.Errorf("a recovery target requires an online backup with a WAL archive")
+	}
+
+	// Disable archiving of the WAL files of the original cluster
+	err := fileutils.AppendStringToFile(
+		path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile),
+		"archive_command = 'cd .'\n")
+	if err != nil {
+		return fmt.Errorf("cannot write recovery config: %w", err)
+	}
+
+	// The configuration of the original instance must not be used
+	return os.WriteFile(
+		path.Join(info.PgData, "postgresql.auto.conf"),
+		[]byte(""),
+		0o600)
+}
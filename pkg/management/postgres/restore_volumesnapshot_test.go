@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restoring a volume snapshot backup", func() {
+	var info InitInfo
+
+	BeforeEach(func() {
+		info = InitInfo{PgData: path.Join(GinkgoT().TempDir(), "pgdata")}
+	})
+
+	It("fails when the PGDATA has not been provisioned from the snapshot", func() {
+		Expect(info.prepareVolumeSnapshotDataDir(&apiv1.Backup{})).ToNot(Succeed())
+	})
+
+	It("removes the PID file and writes the files returned by pg_backup_stop", func() {
+		Expect(os.MkdirAll(info.PgData, 0o700)).To(Succeed())
+		Expect(os.WriteFile(path.Join(info.PgData, PostgresqlPidFile), []byte("42"), 0o600)).To(Succeed())
+
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				BackupLabelFile:   []byte("START WAL LOCATION: 0/2000028"),
+				TablespaceMapFile: []byte("16384 /tablespaces/tbs"),
+			},
+		}
+		Expect(info.prepareVolumeSnapshotDataDir(backup)).To(Succeed())
+
+		Expect(path.Join(info.PgData, PostgresqlPidFile)).ToNot(BeAnExistingFile())
+		Expect(os.ReadFile(path.Join(info.PgData, "backup_label"))).To(BeEquivalentTo(backup.Status.BackupLabelFile))
+		Expect(os.ReadFile(path.Join(info.PgData, "tablespace_map"))).To(BeEquivalentTo(backup.Status.TablespaceMapFile))
+	})
+
+	It("doesn't write a backup label for crash-consistent backups", func() {
+		Expect(os.MkdirAll(info.PgData, 0o700)).To(Succeed())
+		Expect(info.prepareVolumeSnapshotDataDir(&apiv1.Backup{})).To(Succeed())
+		Expect(path.Join(info.PgData, "backup_label")).ToNot(BeAnExistingFile())
+	})
+
+	It("uses the WAL archive only for online backups", func() {
+		backup := &apiv1.Backup{Status: apiv1.BackupStatus{DestinationPath: "s3://bucket/path"}}
+		Expect(isWalArchiveRecoveryNeeded(backup)).To(BeFalse())
+
+		backup.Status.Online = pointer.Bool(true)
+		Expect(isWalArchiveRecoveryNeeded(backup)).To(BeTrue())
+
+		backup.Status.DestinationPath = ""
+		Expect(isWalArchiveRecoveryNeeded(backup)).To(BeFalse())
+	})
+
+	It("refuses a recovery target on crash-consistent backups", func() {
+		Expect(os.MkdirAll(info.PgData, 0o700)).To(Succeed())
+		customConfigurationFile := path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile)
+		Expect(os.WriteFile(customConfigurationFile, nil, 0o600)).To(Succeed())
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						RecoveryTarget: &apiv1.RecoveryTarget{TargetImmediate: pointer.Bool(true)},
+					},
+				},
+			},
+		}
+		Expect(info.writeCrashRecoveryConfig(cluster)).ToNot(Succeed())
+
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget = nil
+		Expect(info.writeCrashRecoveryConfig(cluster)).To(Succeed())
+		Expect(os.ReadFile(path.Join(info.PgData, "postgresql.auto.conf"))).To(BeEmpty())
+		Expect(os.ReadFile(customConfigurationFile)).To(ContainSubstring("archive_command = 'cd .'"))
+	})
+})
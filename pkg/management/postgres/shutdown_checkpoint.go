@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// CheckpointBeforeShutdown requests a checkpoint while the instance is
+// still serving its clients, so that the checkpoint written by the
+// following shutdown has few dirty buffers left to flush. When a
+// max_wal_size has been configured for the shutdown, it is applied before
+// the checkpoint and kept until the instance is stopped: it is removed
+// before PostgreSQL is started again.
+// The checkpoint is spread over checkpoint_completion_target, to avoid
+// hitting the storage with a burst of writes while the clients are still
+// being served. As the CHECKPOINT command is always immediate, it is
+// requested by starting an online backup, which is discarded
+func (instance *Instance) CheckpointBeforeShutdown(ctx context.Context) error {
+	postgresVersion, err := instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	if maxWalSize := instance.ShutdownCheckpointMaxWalSize; maxWalSize != "" {
+		log.FromContext(ctx).Info("Raising max_wal_size until the instance is shut down",
+			"maxWalSize", maxWalSize)
+		if _, err := db.ExecContext(ctx,
+			fmt.Sprintf("ALTER SYSTEM SET max_wal_size TO %s", pq.QuoteLiteral(maxWalSize))); err != nil {
+			return fmt.Errorf("while setting max_wal_size: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, "SELECT pg_catalog.pg_reload_conf()"); err != nil {
+			return fmt.Errorf("while reloading the configuration: %w", err)
+		}
+	}
+
+	// The backup is aborted by PostgreSQL when the session used to start
+	// it is closed, so a dedicated connection is required
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "Error while closing the checkpoint connection")
+		}
+	}()
+
+	startQuery, stopQuery := "SELECT pg_catalog.pg_backup_start($1, false)",
+		"SELECT pg_catalog.pg_backup_stop(false)"
+	if postgresVersion.Major < 15 {
+		startQuery, stopQuery = "SELECT pg_catalog.pg_start_backup($1, false, false)",
+			"SELECT pg_catalog.pg_stop_backup(false, false)"
+	}
+
+	if _, err := conn.ExecContext(ctx, startQuery, shutdownCheckpointLabel); err != nil {
+		return fmt.Errorf("while requesting the checkpoint: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, stopQuery); err != nil {
+		return fmt.Errorf("while completing the checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// shutdownCheckpointLabel is the label of the online backup used to
+// request the spread checkpoint ahead of the shutdown
+const shutdownCheckpointLabel = "cnpg-shutdown-checkpoint"
+
+// removeShutdownMaxWalSize removes from postgresql.auto.conf the value of
+// max_wal_size applied by CheckpointBeforeShutdown, restoring the one set
+// in the configuration of the cluster. It must be called before PostgreSQL
+// is started, as the instance manager which applied it could have been
+// restarted in the meantime
+func (instance *Instance) removeShutdownMaxWalSize() error {
+	autoConf := filepath.Join(instance.PgData, "postgresql.auto.conf")
+	exists, err := fileutils.FileExists(autoConf)
+	if err != nil || !exists {
+		return err
+	}
+
+	changed, err := configfile.UpdatePostgresConfigurationFile(
+		autoConf,
+		map[string]string{},
+		"max_wal_size",
+	)
+	if err != nil {
+		return fmt.Errorf("while removing max_wal_size: %w", err)
+	}
+	if changed {
+		log.Info("Removed the max_wal_size applied before the last shutdown")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("max_wal_size raised for the shutdown checkpoint", func() {
+	It("is removed from postgresql.auto.conf before the instance is started", func() {
+		instance := &Instance{PgData: GinkgoT().TempDir()}
+		autoConf := filepath.Join(instance.PgData, "postgresql.auto.conf")
+		Expect(os.WriteFile(autoConf, []byte(
+			"max_wal_size = '4GB'\nwork_mem = '8MB'\n"), 0o600)).To(Succeed())
+
+		Expect(instance.removeShutdownMaxWalSize()).To(Succeed())
+		content, err := os.ReadFile(autoConf) //nolint:gosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).ToNot(ContainSubstring("max_wal_size"))
+		Expect(string(content)).To(ContainSubstring("work_mem"))
+	})
+
+	It("ignores a missing postgresql.auto.conf", func() {
+		instance := &Instance{PgData: GinkgoT().TempDir()}
+		Expect(instance.removeShutdownMaxWalSize()).To(Succeed())
+	})
+})
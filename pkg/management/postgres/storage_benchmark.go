@@ -0,0 +1,201 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgTestFsyncName = "pg_test_fsync"
+
+	// storageBenchmarkFileName is the name of the file written by
+	// pg_test_fsync in the root of the volume
+	storageBenchmarkFileName = "pg_test_fsync.out"
+
+	// pgTestFsyncOneWriteSection is the header of the section of the
+	// pg_test_fsync output comparing the sync methods using one 8kB write
+	pgTestFsyncOneWriteSection = "Compare file sync methods using one 8kB write:"
+)
+
+// RunStorageBenchmark runs pg_test_fsync on the volume holding the WAL
+// files of the instance being bootstrapped, when requested in the cluster,
+// and stores the result in the cluster status. An error is returned only
+// when the storage doesn't reach the minimum rate set in the cluster
+func (info InitInfo) RunStorageBenchmark(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	configuration := cluster.Spec.StorageBenchmark
+	if !configuration.IsEnabled() {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	directory := filepath.Dir(info.PgData)
+	if info.PgWal != "" {
+		directory = filepath.Dir(info.PgWal)
+	}
+
+	contextLogger.Info("Running the storage benchmark",
+		"directory", directory,
+		"secondsPerTest", configuration.GetSecondsPerTest())
+	result := runPgTestFsync(filepath.Join(directory, storageBenchmarkFileName), configuration.GetSecondsPerTest())
+	contextLogger.Info("Storage benchmark completed",
+		"opsPerSecond", result.OpsPerSecond,
+		"error", result.Error)
+
+	if err := info.storeStorageBenchmarkResult(ctx, typedClient, result); err != nil {
+		contextLogger.Error(err, "Cannot store the result of the storage benchmark")
+	}
+
+	return checkStorageBenchmarkResult(configuration, result)
+}
+
+// runPgTestFsync runs pg_test_fsync writing the passed file, which is
+// removed afterwards
+func runPgTestFsync(fileName string, secondsPerTest int32) apiv1.StorageBenchmarkResult {
+	result := apiv1.StorageBenchmarkResult{
+		Timestamp: utils.GetCurrentTimestamp(),
+	}
+
+	defer func() {
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			log.Warning("Cannot remove the file written by the storage benchmark",
+				"fileName", fileName, "error", err)
+		}
+	}()
+
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgTestFsyncCmd := exec.Command(pgTestFsyncName, // #nosec G204
+		"-f", fileName,
+		"-s", strconv.Itoa(int(secondsPerTest)))
+	pgTestFsyncCmd.Stdout = &stdoutBuffer
+	pgTestFsyncCmd.Stderr = &stderrBuffer
+	pgTestFsyncCmd.Env = append(os.Environ(), "LANG=C", "LC_MESSAGES=C")
+	if err := pgTestFsyncCmd.Run(); err != nil {
+		result.Error = fmt.Sprintf("%v: %s", err, strings.TrimSpace(stderrBuffer.String()))
+		return result
+	}
+
+	result.OpsPerSecond = parsePgTestFsyncOutput(stdoutBuffer.String())
+	if len(result.OpsPerSecond) == 0 {
+		result.Error = "no sync method found in the output of pg_test_fsync"
+	}
+
+	return result
+}
+
+// parsePgTestFsyncOutput extracts from the pg_test_fsync output the number
+// of operations per second of each sync method using one 8kB write.
+// Methods not supported by the volume are skipped
+func parsePgTestFsyncOutput(output string) map[string]int64 {
+	result := make(map[string]int64)
+
+	inSection := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == pgTestFsyncOneWriteSection {
+			inSection = true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if line == "" && len(result) > 0 {
+			break
+		}
+
+		// i.e. "fdatasync      1124.850 ops/sec     889 usecs/op"
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != "ops/sec" {
+			continue
+		}
+		opsPerSecond, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		result[fields[0]] = int64(math.Round(opsPerSecond))
+	}
+
+	return result
+}
+
+// checkStorageBenchmarkResult checks the result of the storage benchmark
+// against the minimum rate set in the configuration
+func checkStorageBenchmarkResult(
+	configuration *apiv1.StorageBenchmarkConfiguration,
+	result apiv1.StorageBenchmarkResult,
+) error {
+	if configuration.MinFdatasyncOpsPerSecond == 0 {
+		return nil
+	}
+
+	if result.Error != "" {
+		return fmt.Errorf("cannot check the storage performance: %s", result.Error)
+	}
+
+	opsPerSecond, ok := result.OpsPerSecond["fdatasync"]
+	if !ok {
+		return fmt.Errorf("the storage doesn't support fdatasync")
+	}
+	if opsPerSecond < configuration.MinFdatasyncOpsPerSecond {
+		return fmt.Errorf("the storage is too slow: %d fdatasync operations per second, less than %d",
+			opsPerSecond, configuration.MinFdatasyncOpsPerSecond)
+	}
+
+	return nil
+}
+
+// storeStorageBenchmarkResult stores the result of the storage
+// benchmark of this instance in the cluster status
+func (info InitInfo) storeStorageBenchmarkResult(
+	ctx context.Context,
+	typedClient client.Client,
+	result apiv1.StorageBenchmarkResult,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster, err := info.loadCluster(ctx, typedClient)
+		if err != nil {
+			return err
+		}
+
+		if cluster.Status.StorageBenchmarks == nil {
+			cluster.Status.StorageBenchmarks = make(map[string]apiv1.StorageBenchmarkResult)
+		}
+		cluster.Status.StorageBenchmarks[info.PodName] = result
+		return typedClient.Status().Update(ctx, cluster)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage benchmark", func() {
+	const output = `2 seconds per test
+O_DIRECT supported on this platform for open_datasync and open_sync.
+
+Compare file sync methods using one 8kB write:
+(in wal_sync_method preference order, except fdatasync is Linux's default)
+        open_datasync                      1203.275 ops/sec     831 usecs/op
+        fdatasync                          1124.850 ops/sec     889 usecs/op
+        fsync                               906.417 ops/sec    1103 usecs/op
+        fsync_writethrough                              n/a
+        open_sync                           998.501 ops/sec    1002 usecs/op
+
+Compare file sync methods using two 8kB writes:
+(in wal_sync_method preference order, except fdatasync is Linux's default)
+        open_datasync                       601.128 ops/sec    1664 usecs/op
+        fdatasync                          1001.555 ops/sec     998 usecs/op
+`
+
+	It("parses the rate of the sync methods using one 8kB write", func() {
+		Expect(parsePgTestFsyncOutput(output)).To(Equal(map[string]int64{
+			"open_datasync": 1203,
+			"fdatasync":     1125,
+			"fsync":         906,
+			"open_sync":     999,
+		}))
+		Expect(parsePgTestFsyncOutput("")).To(BeEmpty())
+	})
+
+	It("checks the result against the minimum rate, if set", func() {
+		result := apiv1.StorageBenchmarkResult{OpsPerSecond: parsePgTestFsyncOutput(output)}
+		configuration := &apiv1.StorageBenchmarkConfiguration{Enabled: true}
+		Expect(checkStorageBenchmarkResult(configuration, result)).To(Succeed())
+		Expect(checkStorageBenchmarkResult(configuration, apiv1.StorageBenchmarkResult{Error: "failed"})).
+			To(Succeed())
+
+		configuration.MinFdatasyncOpsPerSecond = 1000
+		Expect(checkStorageBenchmarkResult(configuration, result)).To(Succeed())
+		Expect(checkStorageBenchmarkResult(configuration, apiv1.StorageBenchmarkResult{Error: "failed"})).
+			ToNot(Succeed())
+
+		configuration.MinFdatasyncOpsPerSecond = 2000
+		Expect(checkStorageBenchmarkResult(configuration, result)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// StorageGrowthWindow is the period covered by the samples used to
+	// compute the growth rate of the volumes
+	StorageGrowthWindow = 24 * time.Hour
+
+	// storageGrowthMinimumSpan is the minimum period that the samples
+	// must cover before the time left before a volume is full is forecast
+	storageGrowthMinimumSpan = time.Hour
+)
+
+// storageUsageSample is the usage of a volume at a given time
+type storageUsageSample struct {
+	time      time.Time
+	usedBytes uint64
+}
+
+// storageGrowthTracker keeps the recent usage samples of the volumes of
+// the instance, and the forecasts computed from them
+type storageGrowthTracker struct {
+	lock      sync.Mutex
+	samples   map[string][]storageUsageSample
+	forecasts map[string]postgres.StorageForecast
+}
+
+// RecordStorageUsage adds a usage sample of a volume of the instance,
+// discarding the ones older than the forecast window, and returns the
+// updated forecast of the volume
+func (instance *Instance) RecordStorageUsage(
+	volume string,
+	usedBytes, capacityBytes uint64,
+	now time.Time,
+) postgres.StorageForecast {
+	tracker := &instance.storageGrowth
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if tracker.samples == nil {
+		tracker.samples = make(map[string][]storageUsageSample)
+		tracker.forecasts = make(map[string]postgres.StorageForecast)
+	}
+
+	samples := append(tracker.samples[volume], storageUsageSample{time: now, usedBytes: usedBytes})
+	firstInWindow := 0
+	for firstInWindow < len(samples) && now.Sub(samples[firstInWindow].time) > StorageGrowthWindow {
+		firstInWindow++
+	}
+	samples = samples[firstInWindow:]
+	tracker.samples[volume] = samples
+
+	forecast := computeStorageForecast(volume, samples, capacityBytes)
+	tracker.forecasts[volume] = forecast
+	return forecast
+}
+
+// GetStorageForecasts returns the last forecasts of the volumes of the
+// instance, sorted by volume
+func (instance *Instance) GetStorageForecasts() []postgres.StorageForecast {
+	tracker := &instance.storageGrowth
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if len(tracker.forecasts) == 0 {
+		return nil
+	}
+
+	result := make([]postgres.StorageForecast, 0, len(tracker.forecasts))
+	for _, forecast := range tracker.forecasts {
+		result = append(result, forecast)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Volume < result[j].Volume
+	})
+	return result
+}
+
+// computeStorageForecast computes the growth rate of a volume with a
+// least squares linear regression of its usage samples, which are sorted
+// by time, and the days left before it is full at that rate
+func computeStorageForecast(
+	volume string,
+	samples []storageUsageSample,
+	capacityBytes uint64,
+) postgres.StorageForecast {
+	last := samples[len(samples)-1]
+	result := postgres.StorageForecast{
+		Volume:        volume,
+		UsedBytes:     last.usedBytes,
+		CapacityBytes: capacityBytes,
+	}
+
+	if last.time.Sub(samples[0].time) < storageGrowthMinimumSpan {
+		return result
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.time.Sub(samples[0].time).Seconds()
+		y := float64(sample.usedBytes)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	count := float64(len(samples))
+	denominator := count*sumXX - sumX*sumX
+	if denominator == 0 {
+		return result
+	}
+	result.GrowthBytesPerSecond = (count*sumXY - sumX*sumY) / denominator
+
+	if result.GrowthBytesPerSecond > 0 && capacityBytes > last.usedBytes {
+		daysUntilFull := float64(capacityBytes-last.usedBytes) / result.GrowthBytesPerSecond /
+			(24 * time.Hour).Seconds()
+		result.DaysUntilFull = &daysUntilFull
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage growth forecast", func() {
+	const gigabyte = uint64(1 << 30)
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("doesn't forecast before the samples cover enough time", func() {
+		instance := &Instance{}
+		instance.RecordStorageUsage("pgdata", gigabyte, 10*gigabyte, start)
+		forecast := instance.RecordStorageUsage("pgdata", 2*gigabyte, 10*gigabyte, start.Add(time.Minute))
+		Expect(forecast.UsedBytes).To(Equal(2 * gigabyte))
+		Expect(forecast.CapacityBytes).To(Equal(10 * gigabyte))
+		Expect(forecast.GrowthBytesPerSecond).To(BeZero())
+		Expect(forecast.DaysUntilFull).To(BeNil())
+	})
+
+	It("forecasts the days until a growing volume is full", func() {
+		instance := &Instance{}
+		// The volume grows by one gigabyte per day
+		for hour := 0; hour <= 24; hour++ {
+			instance.RecordStorageUsage("pgdata", gigabyte+uint64(hour)*gigabyte/24, 10*gigabyte,
+				start.Add(time.Duration(hour)*time.Hour))
+		}
+
+		forecasts := instance.GetStorageForecasts()
+		Expect(forecasts).To(HaveLen(1))
+		Expect(forecasts[0].GrowthBytesPerSecond).To(BeNumerically("~", float64(gigabyte)/86400, 1))
+		Expect(forecasts[0].DaysUntilFull).ToNot(BeNil())
+		Expect(*forecasts[0].DaysUntilFull).To(BeNumerically("~", 8, 0.01))
+	})
+
+	It("doesn't forecast when the volume is not growing", func() {
+		instance := &Instance{}
+		instance.RecordStorageUsage("wal", 2*gigabyte, 10*gigabyte, start)
+		forecast := instance.RecordStorageUsage("wal", gigabyte, 10*gigabyte, start.Add(2*time.Hour))
+		Expect(forecast.GrowthBytesPerSecond).To(BeNumerically("<", 0))
+		Expect(forecast.DaysUntilFull).To(BeNil())
+	})
+
+	It("discards the samples older than the window", func() {
+		instance := &Instance{}
+		instance.RecordStorageUsage("pgdata", 9*gigabyte, 10*gigabyte, start)
+		instance.RecordStorageUsage("pgdata", gigabyte, 10*gigabyte, start.Add(StorageGrowthWindow))
+		forecast := instance.RecordStorageUsage("pgdata", gigabyte, 10*gigabyte,
+			start.Add(StorageGrowthWindow+2*time.Hour))
+		Expect(forecast.GrowthBytesPerSecond).To(BeZero())
+	})
+
+	It("reports the forecasts sorted by volume", func() {
+		instance := &Instance{}
+		Expect(instance.GetStorageForecasts()).To(BeNil())
+		instance.RecordStorageUsage("wal", gigabyte, 10*gigabyte, start)
+		instance.RecordStorageUsage("pgdata", gigabyte, 10*gigabyte, start)
+		forecasts := instance.GetStorageForecasts()
+		Expect(forecasts).To(HaveLen(2))
+		Expect(forecasts[0].Volume).To(Equal("pgdata"))
+		Expect(forecasts[1].Volume).To(Equal("wal"))
+	})
+})
@@ -0,0 +1,187 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// existingSubscription is the state of a subscription inside PostgreSQL
+type existingSubscription struct {
+	connectionString string
+	publications     []string
+}
+
+// buildCreateSubscriptionStatement builds the statement creating the
+// passed subscription, connecting to the publisher with the passed
+// connection string
+func buildCreateSubscriptionStatement(spec apiv1.SubscriptionSpec, connectionString string) string {
+	statement := fmt.Sprintf(
+		"CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s",
+		pgx.Identifier{spec.Name}.Sanitize(),
+		pq.QuoteLiteral(connectionString),
+		pgx.Identifier{spec.PublicationName}.Sanitize())
+
+	if len(spec.Parameters) > 0 {
+		statement += fmt.Sprintf(" WITH (%s)", buildLogicalReplicationParameters(spec.Parameters))
+	}
+
+	return statement
+}
+
+// buildAlterSubscriptionStatements builds the statements aligning an
+// existing subscription to its specification
+func buildAlterSubscriptionStatements(
+	spec apiv1.SubscriptionSpec,
+	connectionString string,
+	subscription existingSubscription,
+) []string {
+	var statements []string
+	name := pgx.Identifier{spec.Name}.Sanitize()
+
+	if subscription.connectionString != connectionString {
+		statements = append(statements, fmt.Sprintf(
+			"ALTER SUBSCRIPTION %s CONNECTION %s",
+			name,
+			pq.QuoteLiteral(connectionString)))
+	}
+
+	if len(subscription.publications) != 1 || subscription.publications[0] != spec.PublicationName {
+		statements = append(statements, fmt.Sprintf(
+			"ALTER SUBSCRIPTION %s SET PUBLICATION %s",
+			name,
+			pgx.Identifier{spec.PublicationName}.Sanitize()))
+	}
+
+	return statements
+}
+
+// getSubscriptionState summarizes the state of a subscription
+func getSubscriptionState(enabled, workerRunning bool, tablesNotReady int) apiv1.SubscriptionState {
+	switch {
+	case !enabled:
+		return apiv1.SubscriptionStateDisabled
+	case tablesNotReady > 0:
+		return apiv1.SubscriptionStateInitializing
+	case workerRunning:
+		return apiv1.SubscriptionStateStreaming
+	default:
+		return apiv1.SubscriptionStateDown
+	}
+}
+
+// ReconcileSubscription creates or alters a subscription to match the
+// passed specification, connecting to the publisher with the passed
+// connection string. The parameters are used only when creating the
+// subscription
+func (instance *Instance) ReconcileSubscription(
+	ctx context.Context,
+	spec apiv1.SubscriptionSpec,
+	connectionString string,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("subscription", spec.Name, "dbname", spec.DBName)
+
+	db, err := instance.ConnectionPool().Connection(spec.DBName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", spec.DBName, err)
+	}
+
+	var subscription existingSubscription
+	row := db.QueryRowContext(ctx,
+		"SELECT subconninfo, subpublications FROM pg_catalog.pg_subscription "+
+			"WHERE subname = $1 AND subdbid = "+
+			"(SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())",
+		spec.Name)
+	err = row.Scan(&subscription.connectionString, pq.Array(&subscription.publications))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		contextLogger.Info("Creating subscription")
+		if _, err = db.ExecContext(ctx, buildCreateSubscriptionStatement(spec, connectionString)); err != nil {
+			return fmt.Errorf("while creating subscription %s: %w", spec.Name, err)
+		}
+		return nil
+
+	case err != nil:
+		return fmt.Errorf("while reading subscription %s: %w", spec.Name, err)
+	}
+
+	for _, statement := range buildAlterSubscriptionStatements(spec, connectionString, subscription) {
+		contextLogger.Info("Altering subscription")
+		if _, err = db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while altering subscription %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetSubscriptionStatus returns the state of the passed subscription
+// and the progress of its apply worker
+func (instance *Instance) GetSubscriptionStatus(
+	ctx context.Context,
+	spec apiv1.SubscriptionSpec,
+) (apiv1.SubscriptionStatus, error) {
+	db, err := instance.ConnectionPool().Connection(spec.DBName)
+	if err != nil {
+		return apiv1.SubscriptionStatus{}, fmt.Errorf("while connecting to database %s: %w", spec.DBName, err)
+	}
+
+	var (
+		enabled        bool
+		workerRunning  bool
+		receivedLsn    string
+		latestEndLsn   string
+		latestEndTime  sql.NullTime
+		tablesNotReady int
+	)
+	row := db.QueryRowContext(ctx,
+		"SELECT s.subenabled, ss.pid IS NOT NULL, "+
+			"COALESCE(ss.received_lsn::text, ''), COALESCE(ss.latest_end_lsn::text, ''), ss.latest_end_time, "+
+			"(SELECT count(*) FROM pg_catalog.pg_subscription_rel r "+
+			"WHERE r.srsubid = s.oid AND r.srsubstate <> 'r') "+
+			"FROM pg_catalog.pg_subscription s "+
+			"LEFT JOIN pg_catalog.pg_stat_subscription ss ON ss.subid = s.oid AND ss.relid IS NULL "+
+			"WHERE s.subname = $1 AND s.subdbid = "+
+			"(SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database()) "+
+			"ORDER BY ss.received_lsn DESC NULLS LAST LIMIT 1",
+		spec.Name)
+	if err := row.Scan(&enabled, &workerRunning, &receivedLsn, &latestEndLsn, &latestEndTime,
+		&tablesNotReady); err != nil {
+		return apiv1.SubscriptionStatus{}, fmt.Errorf("while reading subscription %s: %w", spec.Name, err)
+	}
+
+	status := apiv1.SubscriptionStatus{
+		State:        getSubscriptionState(enabled, workerRunning, tablesNotReady),
+		ReceivedLsn:  receivedLsn,
+		LatestEndLsn: latestEndLsn,
+	}
+	if latestEndTime.Valid {
+		status.LatestEndTime = latestEndTime.Time.UTC().Format(time.RFC3339)
+	}
+
+	return status, nil
+}
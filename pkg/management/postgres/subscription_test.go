@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative subscriptions", func() {
+	spec := apiv1.SubscriptionSpec{
+		Name:                "sub",
+		DBName:              "app",
+		PublicationName:     "pub",
+		ExternalClusterName: "cluster-blue",
+	}
+	const connectionString = "dbname='app' host='cluster-blue-rw'"
+
+	It("creates the subscription with the connection string and the parameters", func() {
+		withParameters := spec
+		withParameters.Parameters = map[string]string{"copy_data": "false"}
+		Expect(buildCreateSubscriptionStatement(withParameters, connectionString)).To(Equal(
+			`CREATE SUBSCRIPTION "sub" CONNECTION 'dbname=''app'' host=''cluster-blue-rw''' ` +
+				`PUBLICATION "pub" WITH ("copy_data" = 'false')`))
+	})
+
+	It("doesn't alter a subscription matching its specification", func() {
+		existing := existingSubscription{connectionString: connectionString, publications: []string{"pub"}}
+		Expect(buildAlterSubscriptionStatements(spec, connectionString, existing)).To(BeEmpty())
+	})
+
+	It("aligns the connection string and the publication of an existing subscription", func() {
+		existing := existingSubscription{connectionString: "host='old'", publications: []string{"pub", "other"}}
+		Expect(buildAlterSubscriptionStatements(spec, connectionString, existing)).To(Equal([]string{
+			`ALTER SUBSCRIPTION "sub" CONNECTION 'dbname=''app'' host=''cluster-blue-rw'''`,
+			`ALTER SUBSCRIPTION "sub" SET PUBLICATION "pub"`,
+		}))
+	})
+
+	It("summarizes the state of the subscription", func() {
+		Expect(getSubscriptionState(false, true, 0)).To(Equal(apiv1.SubscriptionStateDisabled))
+		Expect(getSubscriptionState(true, true, 2)).To(Equal(apiv1.SubscriptionStateInitializing))
+		Expect(getSubscriptionState(true, true, 0)).To(Equal(apiv1.SubscriptionStateStreaming))
+		Expect(getSubscriptionState(true, false, 0)).To(Equal(apiv1.SubscriptionStateDown))
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// PreparedTransaction is a transaction prepared for two-phase commit
+type PreparedTransaction struct {
+	// The global identifier of the transaction
+	GID string
+
+	// The database where the transaction has been prepared
+	Database string
+
+	// The owner of the transaction
+	Owner string
+}
+
+// GetPreparedTransactions lists the transactions prepared for two-phase
+// commit and never committed or rolled back
+func GetPreparedTransactions(ctx context.Context, db *sql.DB) ([]PreparedTransaction, error) {
+	rows, err := db.QueryContext(ctx, "SELECT gid, database, owner FROM pg_catalog.pg_prepared_xacts ORDER BY prepared")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []PreparedTransaction
+	for rows.Next() {
+		var transaction PreparedTransaction
+		if err := rows.Scan(&transaction.GID, &transaction.Database, &transaction.Owner); err != nil {
+			return nil, err
+		}
+		result = append(result, transaction)
+	}
+
+	return result, rows.Err()
+}
+
+// getLogicalReplicationWorkers lists the PIDs of the workers applying
+// the changes of a subscription, which are bound to a replication origin
+func getLogicalReplicationWorkers(ctx context.Context, db *sql.DB) ([]int, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT pid FROM pg_catalog.pg_stat_activity WHERE backend_type = 'logical replication worker'")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []int
+	for rows.Next() {
+		var pid int
+		if err := rows.Scan(&pid); err != nil {
+			return nil, err
+		}
+		result = append(result, pid)
+	}
+
+	return result, rows.Err()
+}
+
+// ApplySwitchoverGuardrail looks for the prepared transactions and the
+// logical replication workers of the primary instance before it gets
+// demoted, and reports or aborts them according to the passed policy
+func (instance *Instance) ApplySwitchoverGuardrail(
+	ctx context.Context,
+	policy apiv1.SwitchoverGuardrailPolicy,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	transactions, err := GetPreparedTransactions(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while listing the prepared transactions: %w", err)
+	}
+	workers, err := getLogicalReplicationWorkers(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while listing the logical replication workers: %w", err)
+	}
+
+	if len(transactions) == 0 && len(workers) == 0 {
+		return nil
+	}
+
+	for _, transaction := range transactions {
+		contextLogger.Warning("Found a prepared transaction on the primary before the switchover",
+			"gid", transaction.GID,
+			"database", transaction.Database,
+			"owner", transaction.Owner,
+			"policy", policy)
+	}
+	if len(workers) > 0 {
+		contextLogger.Warning("Found logical replication workers on the primary before the switchover",
+			"pids", workers,
+			"policy", policy)
+	}
+
+	if policy != apiv1.SwitchoverGuardrailPolicyAbort {
+		return nil
+	}
+
+	for _, transaction := range transactions {
+		if err := instance.rollbackPreparedTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("while rolling back the prepared transaction %s: %w", transaction.GID, err)
+		}
+		contextLogger.Info("Rolled back a prepared transaction before the switchover",
+			"gid", transaction.GID,
+			"database", transaction.Database)
+	}
+
+	for _, pid := range workers {
+		if _, err := db.ExecContext(ctx, "SELECT pg_catalog.pg_terminate_backend($1)", pid); err != nil {
+			return fmt.Errorf("while terminating the logical replication worker %d: %w", pid, err)
+		}
+	}
+	if len(workers) > 0 {
+		contextLogger.Info("Terminated the logical replication workers before the switchover", "pids", workers)
+	}
+
+	return nil
+}
+
+// rollbackPreparedTransaction rolls back a prepared transaction, from
+// the database where it has been prepared
+func (instance *Instance) rollbackPreparedTransaction(ctx context.Context, transaction PreparedTransaction) error {
+	db, err := instance.ConnectionPool().Connection(transaction.Database)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ROLLBACK PREPARED %s", pq.QuoteLiteral(transaction.GID)))
+	return err
+}
@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// lockChainsCheckInterval is the minimum time between two detections of
+// the lock chains, as pg_blocking_pids needs to briefly lock the shared
+// state of the lock manager, delaying the sessions acquiring locks
+const lockChainsCheckInterval = 30 * time.Second
+
+// lockChainsTracker keeps the sessions involved in lock chains as detected
+// by the last check
+type lockChainsTracker struct {
+	lock             sync.Mutex
+	checkedAt        time.Time
+	blockedSessions  int
+	blockingSessions int
+}
+
+// GetLongRunningTransactionsThreshold returns the age, in seconds, over
+// which a transaction is considered long-running
+func (instance *Instance) GetLongRunningTransactionsThreshold() int32 {
+	if instance.LongRunningTransactionsThreshold < 1 {
+		return apiv1.DefaultLongRunningTransactionsThreshold
+	}
+	return instance.LongRunningTransactionsThreshold
+}
+
+// GetLongRunningTransactions detects the client transactions and the
+// prepared transactions older than the threshold, together with the
+// sessions blocked by, or blocking, other sessions. The lock chains are
+// detected at most once every lockChainsCheckInterval, and the previous
+// result is reported in between
+func (instance *Instance) GetLongRunningTransactions(db *sql.DB) (*postgres.LongRunningTransactions, error) {
+	result, err := getLongRunningTransactions(db, instance.GetLongRunningTransactionsThreshold())
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := &instance.lockChains
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if now := time.Now(); now.Sub(tracker.checkedAt) >= lockChainsCheckInterval {
+		row := db.QueryRow(
+			`SELECT
+				(SELECT count(*) FROM pg_stat_activity WHERE cardinality(pg_blocking_pids(pid)) > 0),
+				(SELECT count(DISTINCT blocking_pid) FROM pg_stat_activity,
+					LATERAL unnest(pg_blocking_pids(pid)) AS blocking_pid)`)
+		if err := row.Scan(&tracker.blockedSessions, &tracker.blockingSessions); err != nil {
+			return nil, err
+		}
+		tracker.checkedAt = now
+	}
+
+	result.BlockedSessions = tracker.blockedSessions
+	result.BlockingSessions = tracker.blockingSessions
+	return result, nil
+}
+
+// getLongRunningTransactions detects the client transactions and the
+// prepared transactions older than the passed number of seconds
+func getLongRunningTransactions(db *sql.DB, threshold int32) (*postgres.LongRunningTransactions, error) {
+	var result postgres.LongRunningTransactions
+	var oldestTransactionStart, oldestPreparedTransactionStart sql.NullTime
+	row := db.QueryRow(
+		`SELECT
+			(SELECT count(*) FROM pg_stat_activity
+				WHERE backend_type = 'client backend' AND xact_start < now() - make_interval(secs => $1)),
+			(SELECT COALESCE(EXTRACT(EPOCH FROM max(now() - xact_start)), 0) FROM pg_stat_activity
+				WHERE backend_type = 'client backend'),
+			(SELECT min(xact_start) FROM pg_stat_activity
+				WHERE backend_type = 'client backend' AND xact_start < now() - make_interval(secs => $1)),
+			(SELECT count(*) FROM pg_prepared_xacts WHERE prepared < now() - make_interval(secs => $1)),
+			(SELECT COALESCE(EXTRACT(EPOCH FROM max(now() - prepared)), 0) FROM pg_prepared_xacts),
+			(SELECT min(prepared) FROM pg_prepared_xacts WHERE prepared < now() - make_interval(secs => $1))`,
+		float64(threshold))
+	if err := row.Scan(
+		&result.Transactions,
+		&result.OldestTransactionAge,
+		&oldestTransactionStart,
+		&result.PreparedTransactions,
+		&result.OldestPreparedTransactionAge,
+		&oldestPreparedTransactionStart,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, start := range []sql.NullTime{oldestTransactionStart, oldestPreparedTransactionStart} {
+		if start.Valid && (result.OldestTransactionStart == nil || start.Time.Before(*result.OldestTransactionStart)) {
+			startTime := start.Time
+			result.OldestTransactionStart = &startTime
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,469 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	pgConfigName  = "pg_config"
+	pgUpgradeName = "pg_upgrade"
+
+	// OldBinariesDirectory is where the binaries of the previous PostgreSQL
+	// major version are copied to be used by pg_upgrade. The absolute paths
+	// of the original installation are preserved under this directory,
+	// letting the binaries find their libraries and shared files
+	OldBinariesDirectory = postgres.ScratchDataDirectory + "/old"
+
+	// oldBinDirFileName is the name of the file, inside OldBinariesDirectory,
+	// containing the original path of the binaries
+	oldBinDirFileName = "bindir"
+
+	// upgradeNewSuffix is appended to the directories of the new cluster
+	// until pg_upgrade completes
+	upgradeNewSuffix = "-new"
+
+	// upgradeOldSuffix is appended to the directories of the old cluster
+	// while they are being replaced by the upgraded ones
+	upgradeOldSuffix = "-old"
+
+	// upgradeSwapMarkerName is the name of the file, created next to the
+	// data directory, marking that pg_upgrade completed and that the old
+	// directories are being replaced by the upgraded ones. From then on,
+	// the upgrade can only be completed, not rolled back
+	upgradeSwapMarkerName = "cnpg-upgrade-swap"
+
+	// AnalyzeAfterUpgradeFile is the name of the file, inside the upgraded
+	// data directory, requesting the primary instance to generate the
+	// optimizer statistics, which are not transferred by pg_upgrade
+	AnalyzeAfterUpgradeFile = ".analyze-after-upgrade"
+)
+
+// UpgradeRecoveryResult is the outcome of the recovery of a data directory
+// whose upgrade has been interrupted
+type UpgradeRecoveryResult string
+
+const (
+	// UpgradeRecoveryResultCompleted means that the data directory had
+	// already been upgraded, and the replacement of the old one completed
+	UpgradeRecoveryResultCompleted UpgradeRecoveryResult = "completed"
+
+	// UpgradeRecoveryResultRolledBack means that the old data directory
+	// has been made usable again
+	UpgradeRecoveryResultRolledBack UpgradeRecoveryResult = "rolledBack"
+)
+
+// UpgradeInfo contains all the info needed to upgrade the data directory
+// of an instance to a new PostgreSQL major version
+type UpgradeInfo struct {
+	// The data directory to be upgraded
+	PgData string
+
+	// The directory storing the WAL files, if not inside PgData
+	PgWal string
+
+	// How pg_upgrade transfers the data files
+	Method apiv1.PgUpgradeMethod
+
+	// The options passed to initdb when creating the new data directory
+	InitDBOptions []string
+}
+
+// CopyBinariesForUpgrade copies the binaries, the libraries and the shared
+// files of the PostgreSQL installation in the current image to the
+// destination directory, where pg_upgrade can use them from an image
+// containing the new major version
+func CopyBinariesForUpgrade(destination string) error {
+	directories, err := getPgConfigDirectories("--bindir", "--pkglibdir", "--sharedir")
+	if err != nil {
+		return err
+	}
+
+	for _, directory := range directories {
+		log.Info("Copying the PostgreSQL installation", "directory", directory, "destination", destination)
+		if err := copyDirectoryTree(directory, path.Join(destination, directory)); err != nil {
+			return fmt.Errorf("while copying %s: %w", directory, err)
+		}
+	}
+
+	_, err = fileutils.WriteStringToFile(path.Join(destination, oldBinDirFileName), directories[0])
+	return err
+}
+
+// Upgrade creates a data directory for the new major version, upgrades
+// the data with pg_upgrade and replaces the old data directory. If
+// pg_upgrade fails, the old data directory is left usable. When a previous
+// attempt was interrupted while replacing the data directories, the
+// replacement is resumed
+func (info UpgradeInfo) Upgrade() error {
+	swapping, err := info.isSwapping()
+	if err != nil {
+		return err
+	}
+	if swapping {
+		log.Info("Resuming the replacement of the upgraded data directory", "pgdata", info.PgData)
+		return info.replaceDataDirectories()
+	}
+
+	oldBinDir, err := fileutils.ReadFile(path.Join(OldBinariesDirectory, oldBinDirFileName))
+	if err != nil {
+		return fmt.Errorf("while reading the location of the old binaries: %w", err)
+	}
+
+	newBinDir, err := getPgConfigDirectories("--bindir")
+	if err != nil {
+		return err
+	}
+
+	// Clean up the leftovers of any previous attempt
+	if err := info.Rollback(); err != nil {
+		return err
+	}
+
+	newInfo := InitInfo{
+		PgData:        info.PgData + upgradeNewSuffix,
+		InitDBOptions: info.InitDBOptions,
+	}
+	if info.PgWal != "" {
+		newInfo.PgWal = info.PgWal + upgradeNewSuffix
+	}
+	if err := newInfo.CreateDataDirectory(); err != nil {
+		return err
+	}
+
+	options := buildPgUpgradeOptions(
+		path.Join(OldBinariesDirectory, strings.TrimSpace(string(oldBinDir))),
+		newBinDir[0],
+		info.PgData,
+		newInfo.PgData,
+		info.Method,
+	)
+
+	log.Info("Upgrading the data directory", "pgdata", info.PgData, "options", options)
+	pgUpgradeCmd := exec.Command(pgUpgradeName, options...) // #nosec
+	// pg_upgrade writes its logs and the sockets in the working directory
+	pgUpgradeCmd.Dir = filepath.Dir(info.PgData)
+	if err := execlog.RunStreaming(pgUpgradeCmd, pgUpgradeName); err != nil {
+		if rollbackErr := info.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr, "Cannot roll back the failed upgrade")
+		}
+		return fmt.Errorf("error while upgrading the data directory: %w", err)
+	}
+
+	if err := info.prepareSwap(); err != nil {
+		return err
+	}
+
+	return info.replaceDataDirectories()
+}
+
+// Recover brings the data directory back to a consistent state after the
+// upgrade job has been interrupted, i.e. because its Pod was killed. If
+// pg_upgrade completed, the replacement of the old data directory is
+// completed, otherwise the old data directory is made usable again
+func (info UpgradeInfo) Recover() (UpgradeRecoveryResult, error) {
+	newMajorVersion, err := getPgConfigMajorVersion()
+	if err != nil {
+		return "", err
+	}
+
+	return info.recover(newMajorVersion)
+}
+
+func (info UpgradeInfo) recover(newMajorVersion int) (UpgradeRecoveryResult, error) {
+	swapping, err := info.isSwapping()
+	if err != nil {
+		return "", err
+	}
+	if swapping {
+		log.Info("Completing the replacement of the upgraded data directory", "pgdata", info.PgData)
+		return UpgradeRecoveryResultCompleted, info.replaceDataDirectories()
+	}
+
+	// The job may have been interrupted after having removed the marker
+	rawVersion, err := fileutils.ReadFile(path.Join(info.PgData, "PG_VERSION"))
+	if err != nil {
+		return "", fmt.Errorf("while reading the version of the data directory: %w", err)
+	}
+	majorVersion, err := postgres.GetPostgresMajorVersionFromTag(strings.TrimSpace(string(rawVersion)))
+	if err != nil {
+		return "", err
+	}
+	if majorVersion == newMajorVersion {
+		log.Info("The data directory has already been upgraded", "pgdata", info.PgData)
+		return UpgradeRecoveryResultCompleted, info.removeOldDirectories()
+	}
+
+	log.Info("Rolling back the interrupted upgrade", "pgdata", info.PgData)
+	return UpgradeRecoveryResultRolledBack, info.Rollback()
+}
+
+// Rollback removes the directories of the new cluster and makes the old
+// data directory usable again. When pg_upgrade fails in link mode after
+// having started linking the files, the control file of the old cluster
+// is renamed, but the old cluster is still usable given that the new one
+// has never been started. Once pg_upgrade completed and the replacement
+// of the old data directory started, the upgrade can't be rolled back
+func (info UpgradeInfo) Rollback() error {
+	swapping, err := info.isSwapping()
+	if err != nil {
+		return err
+	}
+	if swapping {
+		return fmt.Errorf("cannot roll back the upgrade of %s, as pg_upgrade already completed", info.PgData)
+	}
+
+	for _, dir := range []string{info.PgData + upgradeNewSuffix, info.PgWal + upgradeNewSuffix} {
+		if dir == upgradeNewSuffix {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("while removing %s: %w", dir, err)
+		}
+	}
+
+	pgControl := path.Join(info.PgData, "global", "pg_control")
+	oldPgControl := pgControl + ".old"
+	oldPgControlExists, err := fileutils.FileExists(oldPgControl)
+	if err != nil || !oldPgControlExists {
+		return err
+	}
+
+	log.Info("Restoring the control file of the old data directory", "pgdata", info.PgData)
+	return os.Rename(oldPgControl, pgControl)
+}
+
+// getSwapMarker gets the path of the file marking that the old
+// directories are being replaced by the upgraded ones
+func (info UpgradeInfo) getSwapMarker() string {
+	return path.Join(filepath.Dir(info.PgData), upgradeSwapMarkerName)
+}
+
+// isSwapping checks if pg_upgrade completed and the old directories are
+// being replaced by the upgraded ones
+func (info UpgradeInfo) isSwapping() (bool, error) {
+	return fileutils.FileExists(info.getSwapMarker())
+}
+
+// getDirectories gets the directories of the data being upgraded
+func (info UpgradeInfo) getDirectories() []string {
+	directories := []string{info.PgData}
+	if info.PgWal != "" {
+		directories = append(directories, info.PgWal)
+	}
+	return directories
+}
+
+// prepareSwap completes the upgraded data directory and creates the
+// marker that makes the replacement of the old directories resumable
+func (info UpgradeInfo) prepareSwap() error {
+	newPgData := info.PgData + upgradeNewSuffix
+	// The configuration applied with ALTER SYSTEM is not copied by pg_upgrade
+	autoConf := path.Join(info.PgData, "postgresql.auto.conf")
+	autoConfExists, err := fileutils.FileExists(autoConf)
+	if err != nil {
+		return err
+	}
+	if autoConfExists {
+		if err := fileutils.CopyFile(autoConf, path.Join(newPgData, "postgresql.auto.conf")); err != nil {
+			return err
+		}
+	}
+
+	// The upgraded cluster has a new system identifier, and its WAL files
+	// must not be archived together with the ones of the old cluster, as
+	// with a new cluster. Its optimizer statistics are generated once the
+	// primary instance is up
+	for _, fileName := range []string{archiver.CheckEmptyWalArchiveFile, AnalyzeAfterUpgradeFile} {
+		if err := fileutils.CreateEmptyFile(path.Join(newPgData, fileName)); err != nil {
+			return fmt.Errorf("could not create the %s file: %w", fileName, err)
+		}
+	}
+
+	_, err = fileutils.WriteStringToFile(info.getSwapMarker(), info.PgData)
+	return err
+}
+
+// replaceDataDirectories moves the upgraded data directory, and the
+// WAL directory if any, in place of the old ones and removes them. Every
+// step can be repeated, so that an interrupted replacement can be resumed
+func (info UpgradeInfo) replaceDataDirectories() error {
+	for _, dir := range info.getDirectories() {
+		newDirExists, err := fileutils.FileExists(dir + upgradeNewSuffix)
+		if err != nil {
+			return err
+		}
+		if !newDirExists {
+			// Already replaced by a previous attempt
+			continue
+		}
+
+		dirExists, err := fileutils.FileExists(dir)
+		if err != nil {
+			return err
+		}
+		if dirExists {
+			if err := os.Rename(dir, dir+upgradeOldSuffix); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(dir+upgradeNewSuffix, dir); err != nil {
+			return err
+		}
+	}
+
+	if info.PgWal != "" {
+		// initdb linked pg_wal to the new WAL directory, which has been renamed
+		pgWalLink := path.Join(info.PgData, "pg_wal")
+		if target, err := os.Readlink(pgWalLink); err != nil || target != info.PgWal {
+			if err := os.Remove(pgWalLink); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err := os.Symlink(info.PgWal, pgWalLink); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := info.removeOldDirectories(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(info.getSwapMarker()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// removeOldDirectories removes the directories replaced by the upgraded ones
+func (info UpgradeInfo) removeOldDirectories() error {
+	for _, dir := range info.getDirectories() {
+		log.Info("Removing the old directory", "directory", dir+upgradeOldSuffix)
+		if err := os.RemoveAll(dir + upgradeOldSuffix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildPgUpgradeOptions builds the options to run pg_upgrade
+func buildPgUpgradeOptions(
+	oldBinDir, newBinDir, oldPgData, newPgData string,
+	method apiv1.PgUpgradeMethod,
+) []string {
+	options := []string{
+		"--username", "postgres",
+		"--old-bindir", oldBinDir,
+		"--new-bindir", newBinDir,
+		"--old-datadir", oldPgData,
+		"--new-datadir", newPgData,
+	}
+
+	if method != apiv1.PgUpgradeMethodCopy {
+		options = append(options, "--link")
+	}
+
+	return options
+}
+
+// getPgConfigDirectories gets the directories of the PostgreSQL installation
+// corresponding to the passed pg_config options
+func getPgConfigDirectories(options ...string) ([]string, error) {
+	out, err := exec.Command(pgConfigName, options...).Output() // #nosec
+	if err != nil {
+		return nil, fmt.Errorf("while running %s: %w", pgConfigName, err)
+	}
+
+	directories := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(directories) != len(options) {
+		return nil, fmt.Errorf("unexpected %s output: %s", pgConfigName, out)
+	}
+
+	return directories, nil
+}
+
+// getPgConfigMajorVersion gets the major version of the PostgreSQL
+// installation in the current image
+func getPgConfigMajorVersion() (int, error) {
+	out, err := exec.Command(pgConfigName, "--version").Output() // #nosec
+	if err != nil {
+		return 0, fmt.Errorf("while running %s: %w", pgConfigName, err)
+	}
+
+	// The output is like "PostgreSQL 15.1 (Debian 15.1-1.pgdg110+1)"
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected %s output: %s", pgConfigName, out)
+	}
+
+	return postgres.GetPostgresMajorVersionFromTag(fields[1])
+}
+
+// copyDirectoryTree copies the content of a directory, preserving
+// the permissions of the files and the symbolic links
+func copyDirectoryTree(source, destination string) error {
+	return filepath.WalkDir(source, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, filePath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(filePath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+
+		default:
+			if err := fileutils.CopyFile(filePath, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+	})
+}
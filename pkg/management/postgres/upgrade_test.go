@@ -0,0 +1,165 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("the major version upgrade", func() {
+	var tempDir string
+	var info UpgradeInfo
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "upgrade-")
+		Expect(err).NotTo(HaveOccurred())
+		info = UpgradeInfo{
+			PgData: filepath.Join(tempDir, "pgdata"),
+			PgWal:  filepath.Join(tempDir, "wal", "pg_wal"),
+		}
+		Expect(os.MkdirAll(filepath.Join(info.PgData, "global"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(info.PgWal, 0o700)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("uses hard links unless the copy method is requested", func() {
+		Expect(buildPgUpgradeOptions("/old/bin", "/new/bin", "/pgdata", "/pgdata-new", apiv1.PgUpgradeMethodLink)).
+			To(Equal([]string{
+				"--username", "postgres",
+				"--old-bindir", "/old/bin",
+				"--new-bindir", "/new/bin",
+				"--old-datadir", "/pgdata",
+				"--new-datadir", "/pgdata-new",
+				"--link",
+			}))
+		Expect(buildPgUpgradeOptions("/old/bin", "/new/bin", "/pgdata", "/pgdata-new", apiv1.PgUpgradeMethodCopy)).
+			NotTo(ContainElement("--link"))
+	})
+
+	It("rolls back the new directories and the control file of the old cluster", func() {
+		Expect(os.MkdirAll(info.PgData+upgradeNewSuffix, 0o700)).To(Succeed())
+		Expect(os.MkdirAll(info.PgWal+upgradeNewSuffix, 0o700)).To(Succeed())
+		pgControl := filepath.Join(info.PgData, "global", "pg_control")
+		Expect(os.WriteFile(pgControl+".old", []byte("control"), 0o600)).To(Succeed())
+
+		Expect(info.Rollback()).To(Succeed())
+
+		Expect(info.PgData + upgradeNewSuffix).NotTo(BeADirectory())
+		Expect(info.PgWal + upgradeNewSuffix).NotTo(BeADirectory())
+		Expect(pgControl).To(BeARegularFile())
+		Expect(pgControl + ".old").NotTo(BeAnExistingFile())
+	})
+
+	It("replaces the old directories with the upgraded ones", func() {
+		newPgData := info.PgData + upgradeNewSuffix
+		newPgWal := info.PgWal + upgradeNewSuffix
+		Expect(os.MkdirAll(newPgData, 0o700)).To(Succeed())
+		Expect(os.MkdirAll(newPgWal, 0o700)).To(Succeed())
+		Expect(os.Symlink(newPgWal, filepath.Join(newPgData, "pg_wal"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(newPgData, "PG_VERSION"), []byte("15"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.PgData, "postgresql.auto.conf"), []byte("work_mem = '8MB'"), 0o600)).
+			To(Succeed())
+
+		Expect(info.prepareSwap()).To(Succeed())
+		Expect(info.replaceDataDirectories()).To(Succeed())
+
+		Expect(filepath.Join(info.PgData, "PG_VERSION")).To(BeARegularFile())
+		Expect(os.ReadFile(filepath.Join(info.PgData, "postgresql.auto.conf"))).To(BeEquivalentTo("work_mem = '8MB'"))
+		Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(filepath.Join(info.PgData, archiver.CheckEmptyWalArchiveFile)).To(BeARegularFile())
+		Expect(filepath.Join(info.PgData, AnalyzeAfterUpgradeFile)).To(BeARegularFile())
+		Expect(newPgData).NotTo(BeADirectory())
+		Expect(info.PgData + upgradeOldSuffix).NotTo(BeADirectory())
+		Expect(info.PgWal + upgradeOldSuffix).NotTo(BeADirectory())
+		Expect(info.getSwapMarker()).NotTo(BeAnExistingFile())
+	})
+
+	It("resumes an interrupted replacement instead of rolling it back", func() {
+		newPgData := info.PgData + upgradeNewSuffix
+		newPgWal := info.PgWal + upgradeNewSuffix
+		Expect(os.MkdirAll(newPgData, 0o700)).To(Succeed())
+		Expect(os.MkdirAll(newPgWal, 0o700)).To(Succeed())
+		Expect(os.Symlink(newPgWal, filepath.Join(newPgData, "pg_wal"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(newPgData, "PG_VERSION"), []byte("15"), 0o600)).To(Succeed())
+		Expect(info.prepareSwap()).To(Succeed())
+
+		// The data directory was replaced, but not the WAL one
+		Expect(os.Rename(info.PgData, info.PgData+upgradeOldSuffix)).To(Succeed())
+		Expect(os.Rename(newPgData, info.PgData)).To(Succeed())
+
+		Expect(info.Rollback()).To(MatchError(ContainSubstring("pg_upgrade already completed")))
+		Expect(newPgWal).To(BeADirectory())
+
+		result, err := info.recover(15)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(UpgradeRecoveryResultCompleted))
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("15"))
+		Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(newPgWal).NotTo(BeADirectory())
+		Expect(info.PgData + upgradeOldSuffix).NotTo(BeADirectory())
+		Expect(info.getSwapMarker()).NotTo(BeAnExistingFile())
+	})
+
+	It("rolls back an upgrade interrupted before pg_upgrade completed", func() {
+		Expect(os.WriteFile(filepath.Join(info.PgData, "PG_VERSION"), []byte("14"), 0o600)).To(Succeed())
+		Expect(os.MkdirAll(info.PgData+upgradeNewSuffix, 0o700)).To(Succeed())
+		pgControl := filepath.Join(info.PgData, "global", "pg_control")
+		Expect(os.WriteFile(pgControl+".old", []byte("control"), 0o600)).To(Succeed())
+
+		result, err := info.recover(15)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(UpgradeRecoveryResultRolledBack))
+		Expect(info.PgData + upgradeNewSuffix).NotTo(BeADirectory())
+		Expect(pgControl).To(BeARegularFile())
+	})
+
+	It("recognizes an upgrade interrupted after the replacement", func() {
+		Expect(os.WriteFile(filepath.Join(info.PgData, "PG_VERSION"), []byte("15"), 0o600)).To(Succeed())
+		Expect(os.MkdirAll(info.PgData+upgradeOldSuffix, 0o700)).To(Succeed())
+
+		result, err := info.recover(15)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(UpgradeRecoveryResultCompleted))
+		Expect(info.PgData + upgradeOldSuffix).NotTo(BeADirectory())
+	})
+
+	It("copies a directory tree preserving the permissions and the links", func() {
+		source := filepath.Join(tempDir, "source")
+		Expect(os.MkdirAll(filepath.Join(source, "bin"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(source, "bin", "postgres"), []byte("binary"), 0o755)).To(Succeed())
+		Expect(os.Symlink("postgres", filepath.Join(source, "bin", "postmaster"))).To(Succeed())
+
+		destination := filepath.Join(tempDir, "destination")
+		Expect(copyDirectoryTree(source, destination)).To(Succeed())
+
+		stat, err := os.Stat(filepath.Join(destination, "bin", "postgres"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0o755)))
+		Expect(os.Readlink(filepath.Join(destination, "bin", "postmaster"))).To(Equal("postgres"))
+	})
+})
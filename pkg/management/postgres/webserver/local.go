@@ -33,12 +33,14 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 type localWebserverEndpoints struct {
 	typedClient   client.Client
 	instance      *postgres.Instance
 	eventRecorder record.EventRecorder
+	pprofMux      *http.ServeMux
 }
 
 // NewLocalWebServer returns a webserver that allows connection only from localhost
@@ -56,11 +58,15 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 		typedClient:   typedClient,
 		instance:      instance,
 		eventRecorder: eventRecorder,
+		pprofMux:      NewPprofServeMux(),
 	}
 
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPprof, endpoints.servePprof)
+	serveMux.HandleFunc(url.PathVeleroPreBackup, endpoints.veleroPreBackup)
+	serveMux.HandleFunc(url.PathVeleroPostBackup, endpoints.veleroPostBackup)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -126,6 +132,28 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	_, _ = w.Write(js)
 }
 
+// This function serves the pprof endpoints, when they are enabled in the
+// cached cluster
+func (ws *localWebserverEndpoints) servePprof(w http.ResponseWriter, r *http.Request) {
+	cluster, err := cache.LoadCluster()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		http.Error(w, "cluster not yet available", http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		log.Error(err, "while loading cached cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !utils.IsInstancePprofEnabled(&cluster.ObjectMeta) {
+		http.Error(w, fmt.Sprintf("pprof is disabled, set the %s annotation to enabled on the cluster",
+			utils.InstancePprofAnnotationName), http.StatusNotFound)
+		return
+	}
+
+	ws.pprofMux.ServeHTTP(w, r)
+}
+
 // This function schedule a backup
 func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.Request) {
 	var cluster apiv1.Cluster
@@ -161,7 +189,13 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	switch {
+	case backup.IsVolumeSnapshot() && !cluster.Spec.Backup.IsVolumeSnapshotBackupConfigured():
+		http.Error(w, "Volume snapshot backups not configured in the cluster", http.StatusConflict)
+		return
+	case backup.IsPlugin():
+		// The plugin configuration is checked when the backup is started
+	case !backup.IsVolumeSnapshot() && (cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil):
 		http.Error(w, "Backup not configured in the cluster", http.StatusConflict)
 		return
 	}
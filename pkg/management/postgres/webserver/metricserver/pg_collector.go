@@ -27,12 +27,14 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	m "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // PrometheusNamespace is the namespace to be used for all custom metrics exposed by instances
@@ -67,9 +69,67 @@ type metrics struct {
 	PgVersion                *prometheus.GaugeVec
 	FirstRecoverabilityPoint prometheus.Gauge
 	FencingOn                prometheus.Gauge
+	WalArchivingPaused       prometheus.Gauge
+	TargetRPO                prometheus.Gauge
+	ConnectionsAvailable     prometheus.Gauge
+	ConnectionsUsed          prometheus.Gauge
+	ConnectionsUsageWarning  prometheus.Gauge
+	LongRunningTransactions  LongRunningTransactionsMetrics
+	LastBackup               LastBackupMetrics
+	ConfigurationPropagation ConfigurationPropagationMetrics
+	BackupVerification       BackupVerificationMetrics
+	StorageGrowth            StorageGrowthMetrics
 	PgStatWalMetrics         PgStatWalMetrics
 }
 
+// BackupVerificationMetrics contains the metrics about the last
+// verification of the backups and of the WAL archive
+type BackupVerificationMetrics struct {
+	IncompleteBackups prometheus.Gauge
+	MissingWALs       prometheus.Gauge
+	CorruptedWALs     prometheus.Gauge
+	LastVerification  prometheus.Gauge
+}
+
+// ConfigurationPropagationMetrics contains the metrics about the time
+// needed for a change of the PostgreSQL configuration in the Cluster
+// specification to be live on the instance, labelled by the path used
+// to apply it (reload or restart)
+type ConfigurationPropagationMetrics struct {
+	Latency      *prometheus.GaugeVec
+	Propagations *prometheus.CounterVec
+}
+
+// LastBackupMetrics contains the metrics about the size and the
+// duration of the last completed backup
+type LastBackupMetrics struct {
+	DataSize         prometheus.Gauge
+	Size             prometheus.Gauge
+	DeduplicatedSize prometheus.Gauge
+	Duration         prometheus.Gauge
+}
+
+// LongRunningTransactionsMetrics contains the metrics about the transactions
+// and the locks that are blocking vacuum or other sessions
+type LongRunningTransactionsMetrics struct {
+	Transactions                 prometheus.Gauge
+	OldestTransactionAge         prometheus.Gauge
+	PreparedTransactions         prometheus.Gauge
+	OldestPreparedTransactionAge prometheus.Gauge
+	BlockedSessions              prometheus.Gauge
+	BlockingSessions             prometheus.Gauge
+}
+
+// StorageGrowthMetrics contains the metrics about the usage of the
+// volumes of the instance and the forecast of the time left before they
+// are full, labelled by volume
+type StorageGrowthMetrics struct {
+	UsedBytes            *prometheus.GaugeVec
+	CapacityBytes        *prometheus.GaugeVec
+	GrowthBytesPerSecond *prometheus.GaugeVec
+	DaysUntilFull        *prometheus.GaugeVec
+}
+
 // PgStatWalMetrics is available from PG14+
 type PgStatWalMetrics struct {
 	WalRecords     *prometheus.GaugeVec
@@ -175,6 +235,168 @@ func newMetrics() *metrics {
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		WalArchivingPaused: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wal_archiving_paused",
+			Help:      "1 if the WAL archiving has been paused by the user, 0 otherwise",
+		}),
+		TargetRPO: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "target_rpo_seconds",
+			Help:      "The target recovery point objective of the cluster, 0 if not set",
+		}),
+		ConnectionsAvailable: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "connections_available",
+			Help:      "Number of connection slots available to the non-superusers",
+		}),
+		ConnectionsUsed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "connections_used",
+			Help:      "Number of client connections to the instance",
+		}),
+		ConnectionsUsageWarning: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "connections_usage_warning",
+			Help: "1 if the used connections are over the usage warning threshold " +
+				"of the available connection slots, 0 otherwise",
+		}),
+		LastBackup: LastBackupMetrics{
+			DataSize: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "last_backup_data_size_bytes",
+				Help:      "Size of the databases when the last completed backup was started",
+			}),
+			Size: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "last_backup_size_bytes",
+				Help:      "Size of the last completed backup, as reported by Barman",
+			}),
+			DeduplicatedSize: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "last_backup_deduplicated_size_bytes",
+				Help:      "Size of the data copied by the last completed backup, when deduplicated by Barman",
+			}),
+			Duration: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "last_backup_duration_seconds",
+				Help:      "Time spent copying the data of the last completed backup",
+			}),
+		},
+		ConfigurationPropagation: ConfigurationPropagationMetrics{
+			Latency: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "last_configuration_propagation_seconds",
+				Help: "Time between the acceptance of the last configuration change in the Cluster " +
+					"and the change being live on the instance",
+			}, []string{"path"}),
+			Propagations: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "configuration_propagations_total",
+				Help:      "Total number of configuration changes made live on the instance",
+			}, []string{"path"}),
+		},
+		BackupVerification: BackupVerificationMetrics{
+			IncompleteBackups: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "backup_verification_incomplete_backups",
+				Help:      "Number of incomplete or failed backups found by the last verification",
+			}),
+			MissingWALs: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "backup_verification_missing_wals",
+				Help:      "Number of WAL files missing from the archive found by the last verification",
+			}),
+			CorruptedWALs: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "backup_verification_corrupted_wals",
+				Help:      "Number of WAL files that cannot be fetched from the archive found by the last verification",
+			}),
+			LastVerification: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "backup_verification_last_timestamp",
+				Help:      "The last time the backups and the WAL archive have been verified, as unix timestamp",
+			}),
+		},
+		StorageGrowth: StorageGrowthMetrics{
+			UsedBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "volume_used_bytes",
+				Help:      "The bytes used in the volume",
+			}, []string{"volume"}),
+			CapacityBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "volume_capacity_bytes",
+				Help:      "The size of the volume in bytes",
+			}, []string{"volume"}),
+			GrowthBytesPerSecond: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "volume_growth_bytes_per_second",
+				Help:      "The growth rate of the bytes used in the volume over the last 24 hours",
+			}, []string{"volume"}),
+			DaysUntilFull: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "volume_days_until_full",
+				Help:      "The days left before the volume is full at the current growth rate, if it is growing",
+			}, []string{"volume"}),
+		},
+		LongRunningTransactions: LongRunningTransactionsMetrics{
+			Transactions: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "long_running_transactions",
+				Help:      "Number of client transactions older than the long-running transactions threshold",
+			}),
+			OldestTransactionAge: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "oldest_transaction_age_seconds",
+				Help:      "Age of the oldest client transaction",
+			}),
+			PreparedTransactions: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "long_running_prepared_transactions",
+				Help:      "Number of prepared transactions older than the long-running transactions threshold",
+			}),
+			OldestPreparedTransactionAge: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "oldest_prepared_transaction_age_seconds",
+				Help:      "Age of the oldest prepared transaction",
+			}),
+			BlockedSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "lock_blocked_sessions",
+				Help:      "Number of sessions waiting for a lock held by another session",
+			}),
+			BlockingSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "lock_blocking_sessions",
+				Help:      "Number of sessions holding a lock other sessions are waiting for",
+			}),
+		},
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -250,6 +472,31 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	ch <- e.Metrics.WalArchivingPaused.Desc()
+	ch <- e.Metrics.TargetRPO.Desc()
+	ch <- e.Metrics.ConnectionsAvailable.Desc()
+	ch <- e.Metrics.ConnectionsUsed.Desc()
+	ch <- e.Metrics.ConnectionsUsageWarning.Desc()
+	ch <- e.Metrics.LongRunningTransactions.Transactions.Desc()
+	ch <- e.Metrics.LongRunningTransactions.OldestTransactionAge.Desc()
+	ch <- e.Metrics.LongRunningTransactions.PreparedTransactions.Desc()
+	ch <- e.Metrics.LongRunningTransactions.OldestPreparedTransactionAge.Desc()
+	ch <- e.Metrics.LongRunningTransactions.BlockedSessions.Desc()
+	ch <- e.Metrics.LongRunningTransactions.BlockingSessions.Desc()
+	ch <- e.Metrics.LastBackup.DataSize.Desc()
+	ch <- e.Metrics.LastBackup.Size.Desc()
+	ch <- e.Metrics.LastBackup.DeduplicatedSize.Desc()
+	ch <- e.Metrics.LastBackup.Duration.Desc()
+	e.Metrics.ConfigurationPropagation.Latency.Describe(ch)
+	e.Metrics.ConfigurationPropagation.Propagations.Describe(ch)
+	ch <- e.Metrics.BackupVerification.IncompleteBackups.Desc()
+	ch <- e.Metrics.BackupVerification.MissingWALs.Desc()
+	ch <- e.Metrics.BackupVerification.CorruptedWALs.Desc()
+	ch <- e.Metrics.BackupVerification.LastVerification.Desc()
+	e.Metrics.StorageGrowth.UsedBytes.Describe(ch)
+	e.Metrics.StorageGrowth.CapacityBytes.Describe(ch)
+	e.Metrics.StorageGrowth.GrowthBytesPerSecond.Describe(ch)
+	e.Metrics.StorageGrowth.DaysUntilFull.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -284,6 +531,31 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
+	ch <- e.Metrics.WalArchivingPaused
+	ch <- e.Metrics.TargetRPO
+	ch <- e.Metrics.ConnectionsAvailable
+	ch <- e.Metrics.ConnectionsUsed
+	ch <- e.Metrics.ConnectionsUsageWarning
+	ch <- e.Metrics.LongRunningTransactions.Transactions
+	ch <- e.Metrics.LongRunningTransactions.OldestTransactionAge
+	ch <- e.Metrics.LongRunningTransactions.PreparedTransactions
+	ch <- e.Metrics.LongRunningTransactions.OldestPreparedTransactionAge
+	ch <- e.Metrics.LongRunningTransactions.BlockedSessions
+	ch <- e.Metrics.LongRunningTransactions.BlockingSessions
+	ch <- e.Metrics.LastBackup.DataSize
+	ch <- e.Metrics.LastBackup.Size
+	ch <- e.Metrics.LastBackup.DeduplicatedSize
+	ch <- e.Metrics.LastBackup.Duration
+	e.Metrics.ConfigurationPropagation.Latency.Collect(ch)
+	e.Metrics.ConfigurationPropagation.Propagations.Collect(ch)
+	ch <- e.Metrics.BackupVerification.IncompleteBackups
+	ch <- e.Metrics.BackupVerification.MissingWALs
+	ch <- e.Metrics.BackupVerification.CorruptedWALs
+	ch <- e.Metrics.BackupVerification.LastVerification
+	e.Metrics.StorageGrowth.UsedBytes.Collect(ch)
+	e.Metrics.StorageGrowth.CapacityBytes.Collect(ch)
+	e.Metrics.StorageGrowth.GrowthBytesPerSecond.Collect(ch)
+	e.Metrics.StorageGrowth.DaysUntilFull.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -357,6 +629,15 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 
 		// getting the first point of recoverability
 		e.collectFromPrimaryFirstPointOnTimeRecovery()
+
+		// getting the statistics of the last completed backup
+		e.collectFromPrimaryLastBackupStatistics()
+
+		// getting whether the WAL archiving has been paused
+		e.collectFromPrimaryWalArchivingPaused()
+
+		// getting the target RPO of the cluster
+		e.collectFromPrimaryTargetRPO()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -380,6 +661,18 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.PgVersion.Reset()
 	}
 
+	if err := collectConnectionsUsage(e, db); err != nil {
+		log.Error(err, "while collecting connections usage metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ConnectionsUsage").Inc()
+	}
+
+	if err := collectLongRunningTransactions(e, db); err != nil {
+		log.Error(err, "while collecting long-running transactions metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.LongRunningTransactions").Inc()
+	}
+
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		if err := collectPGWALStat(e); err != nil {
 			log.Error(err, "while collecting pg_wal_stat")
@@ -432,6 +725,57 @@ func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
 	e.Metrics.FirstRecoverabilityPoint.Set(float64(parsedTS.Unix()))
 }
 
+func (e *Exporter) collectFromPrimaryLastBackupStatistics() {
+	cluster, err := cache.LoadCluster()
+	// there isn't a cached object yet, and the errors are already
+	// reported while collecting the first recoverability point
+	if err != nil {
+		return
+	}
+
+	statistics := cluster.Status.LastBackupStatistics
+	if statistics == nil {
+		return
+	}
+
+	e.Metrics.LastBackup.DataSize.Set(float64(statistics.DataSize))
+	if statistics.Size != nil {
+		e.Metrics.LastBackup.Size.Set(float64(*statistics.Size))
+	}
+	if statistics.DeduplicatedSize != nil {
+		e.Metrics.LastBackup.DeduplicatedSize.Set(float64(*statistics.DeduplicatedSize))
+	}
+	if statistics.Duration != nil {
+		e.Metrics.LastBackup.Duration.Set(statistics.Duration.Seconds())
+	}
+}
+
+func (e *Exporter) collectFromPrimaryWalArchivingPaused() {
+	cluster, err := cache.LoadCluster()
+	// there isn't a cached object yet, and the errors are already
+	// reported while collecting the first recoverability point
+	if err != nil {
+		return
+	}
+
+	if utils.IsWalArchivingPaused(&cluster.ObjectMeta) {
+		e.Metrics.WalArchivingPaused.Set(1)
+	} else {
+		e.Metrics.WalArchivingPaused.Set(0)
+	}
+}
+
+func (e *Exporter) collectFromPrimaryTargetRPO() {
+	cluster, err := cache.LoadCluster()
+	// there isn't a cached object yet, and the errors are already
+	// reported while collecting the first recoverability point
+	if err != nil {
+		return
+	}
+
+	e.Metrics.TargetRPO.Set(cluster.Spec.Backup.GetTargetRPO().Seconds())
+}
+
 func (e *Exporter) collectFromPrimarySynchronousStandbysNumber(db *sql.DB) {
 	nStandbys, err := getSynchronousStandbysNumber(db)
 	if err != nil {
@@ -463,6 +807,58 @@ func collectPGVersion(e *Exporter) error {
 	return nil
 }
 
+// collectConnectionsUsage compares the client connections with the
+// connection slots available to the non-superusers, raising a warning when
+// the usage is over the threshold set in the cluster
+func collectConnectionsUsage(e *Exporter, db *sql.DB) error {
+	var available, used int
+	row := db.QueryRow(
+		"SELECT current_setting('max_connections')::int - current_setting('superuser_reserved_connections')::int, " +
+			"(SELECT count(*) FROM pg_stat_activity WHERE backend_type = 'client backend')")
+	if err := row.Scan(&available, &used); err != nil {
+		return err
+	}
+
+	threshold := int32(apiv1.DefaultConnectionsUsageWarningThreshold)
+	if cluster, err := cache.LoadCluster(); err == nil {
+		threshold = cluster.GetConnectionsUsageWarningThreshold()
+	}
+
+	e.Metrics.ConnectionsAvailable.Set(float64(available))
+	e.Metrics.ConnectionsUsed.Set(float64(used))
+	if isConnectionsUsageOverThreshold(used, available, threshold) {
+		e.Metrics.ConnectionsUsageWarning.Set(1)
+	} else {
+		e.Metrics.ConnectionsUsageWarning.Set(0)
+	}
+	return nil
+}
+
+// collectLongRunningTransactions exports the number of the transactions
+// older than the threshold set in the cluster and of the sessions involved
+// in lock chains
+func collectLongRunningTransactions(e *Exporter, db *sql.DB) error {
+	transactions, err := e.instance.GetLongRunningTransactions(db)
+	if err != nil {
+		return err
+	}
+
+	transactionsMetrics := e.Metrics.LongRunningTransactions
+	transactionsMetrics.Transactions.Set(float64(transactions.Transactions))
+	transactionsMetrics.OldestTransactionAge.Set(transactions.OldestTransactionAge)
+	transactionsMetrics.PreparedTransactions.Set(float64(transactions.PreparedTransactions))
+	transactionsMetrics.OldestPreparedTransactionAge.Set(transactions.OldestPreparedTransactionAge)
+	transactionsMetrics.BlockedSessions.Set(float64(transactions.BlockedSessions))
+	transactionsMetrics.BlockingSessions.Set(float64(transactions.BlockingSessions))
+	return nil
+}
+
+// isConnectionsUsageOverThreshold checks if the used connections are over
+// the passed percentage of the available ones
+func isConnectionsUsageOverThreshold(used, available int, threshold int32) bool {
+	return available > 0 && used*100 >= available*int(threshold)
+}
+
 func collectPGWalArchiveMetric(exporter *Exporter) error {
 	ready, done, err := postgres.GetWALArchiveCounters()
 	if err != nil {
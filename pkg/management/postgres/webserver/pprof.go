@@ -0,0 +1,36 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewPprofServeMux returns a mux serving the pprof profiles and the
+// execution traces of the running process
+func NewPprofServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(url.PathPprof, pprof.Index)
+	mux.HandleFunc(url.PathPprof+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(url.PathPprof+"profile", pprof.Profile)
+	mux.HandleFunc(url.PathPprof+"symbol", pprof.Symbol)
+	mux.HandleFunc(url.PathPprof+"trace", pprof.Trace)
+	return mux
+}
@@ -21,9 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -56,6 +58,7 @@ func NewRemoteWebServer(
 	serveMux.HandleFunc(url.PathHealth, endpoints.isServerHealthy)
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
 	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
+	serveMux.HandleFunc(url.PathPgDatabase, endpoints.pgDatabase)
 	serveMux.HandleFunc(url.PathUpdate,
 		endpoints.updateInstanceManager(cancelFunc, exitedConditions))
 
@@ -70,10 +73,10 @@ func NewRemoteWebServer(
 }
 
 func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *http.Request) {
-	// If `pg_rewind` is running the Pod is starting up.
+	// If `pg_rewind` or `pg_basebackup` is running the Pod is starting up.
 	// We need to report it healthy to avoid being killed by the kubelet.
 	// Same goes for instances with fencing on.
-	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() {
+	if ws.instance.PgRewindIsRunning || ws.instance.PgBaseBackupIsRunning || ws.instance.MightBeUnavailable() {
 		log.Trace("Liveness probe skipped")
 		_, _ = fmt.Fprint(w, "Skipped")
 	}
@@ -113,6 +116,11 @@ func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// The operator compares the heartbeat with its own clock
+	// to detect a clock skew
+	heartbeat := time.Now()
+	status.Heartbeat = &heartbeat
+
 	// Marshal the status back to the operator
 	log.Trace("Instance status probe succeeding")
 	js, err := json.Marshal(status)
@@ -128,6 +136,39 @@ func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Requ
 	_, _ = w.Write(js)
 }
 
+// pgDatabase applies the database specification received from the
+// operator. Only the primary instance can apply it
+func (ws *remoteWebserverEndpoints) pgDatabase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var spec apiv1.DatabaseSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("while decoding the database specification: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !isPrimary {
+		http.Error(w, "the database can be applied only on the primary instance", http.StatusConflict)
+		return
+	}
+
+	if err := ws.instance.ReconcileDatabase(r.Context(), spec); err != nil {
+		log.Info("Cannot apply the database", "database", spec.Name, "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
@@ -0,0 +1,160 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// veleroFencingPollInterval is the interval between two checks of the
+// shutdown of a replica fenced by the Velero pre-backup hook
+const veleroFencingPollInterval = time.Second
+
+// This function prepares the instance to be backed up by Velero,
+// requesting a checkpoint and, when configured, fencing the replica
+func (ws *localWebserverEndpoints) veleroPreBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cluster, err := ws.getVeleroCluster(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	superUserDB, err := ws.instance.GetSuperUserDB()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error while connecting to the instance: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, err := superUserDB.ExecContext(ctx, "CHECKPOINT"); err != nil {
+		http.Error(w, fmt.Sprintf("error while requesting a checkpoint: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error while detecting the role of the instance: %v", err),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if cluster.Spec.Backup.Velero.FenceReplicas && !isPrimary {
+		if err := ws.fenceForVelero(ctx, cluster); err != nil {
+			http.Error(w, fmt.Sprintf("error while fencing the instance: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// This function lifts the fence set by the Velero pre-backup hook
+func (ws *localWebserverEndpoints) veleroPostBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cluster, err := ws.getVeleroCluster(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if cluster.Annotations[utils.VeleroFencedInstanceAnnotationName] != ws.instance.PodName {
+		_, _ = fmt.Fprint(w, "OK")
+		return
+	}
+
+	origCluster := cluster.DeepCopy()
+	err = utils.RemoveFencedInstance(ws.instance.PodName, &cluster.ObjectMeta)
+	if err != nil && !errors.Is(err, utils.ErrorServerAlreadyUnfenced) {
+		http.Error(w, fmt.Sprintf("error while unfencing the instance: %v", err), http.StatusInternalServerError)
+		return
+	}
+	delete(cluster.Annotations, utils.VeleroFencedInstanceAnnotationName)
+	delete(cluster.Annotations, utils.VeleroFenceExpirationAnnotationName)
+	if err := ws.typedClient.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		http.Error(w, fmt.Sprintf("error while unfencing the instance: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("Instance unfenced after the Velero backup")
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// getVeleroCluster gets the cluster of this instance, checking that the
+// integration with Velero is enabled
+func (ws *localWebserverEndpoints) getVeleroCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      ws.instance.ClusterName,
+	}, &cluster); err != nil {
+		return nil, fmt.Errorf("error while getting cluster: %w", err)
+	}
+
+	if !cluster.IsVeleroEnabled() {
+		return nil, fmt.Errorf("velero integration not configured in the cluster")
+	}
+
+	return &cluster, nil
+}
+
+// fenceForVelero fences this replica and waits for PostgreSQL to be shut
+// down. Only one replica at a time is fenced, the others are backed up
+// relying on the checkpoint
+func (ws *localWebserverEndpoints) fenceForVelero(ctx context.Context, cluster *apiv1.Cluster) error {
+	if fencedInstance, ok := cluster.Annotations[utils.VeleroFencedInstanceAnnotationName]; ok {
+		log.Info("Another instance has been fenced for the Velero backup, skipping fencing",
+			"fencedInstance", fencedInstance)
+		return nil
+	}
+	if cluster.IsInstanceFenced(ws.instance.PodName) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if err := utils.AddFencedInstance(ws.instance.PodName, &cluster.ObjectMeta); err != nil {
+		return err
+	}
+	cluster.Annotations[utils.VeleroFencedInstanceAnnotationName] = ws.instance.PodName
+	cluster.Annotations[utils.VeleroFenceExpirationAnnotationName] = time.Now().
+		Add(cluster.Spec.Backup.Velero.GetFenceTimeout()).UTC().Format(time.RFC3339)
+	if err := ws.typedClient.Patch(
+		ctx,
+		cluster,
+		client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}),
+	); err != nil {
+		return err
+	}
+
+	log.Info("Instance fenced for the Velero backup, waiting for the shutdown")
+	timeout := time.Duration(cluster.GetMaxStopDelay()) * time.Second
+	return wait.PollImmediateWithContext(ctx, veleroFencingPollInterval, timeout,
+		func(context.Context) (bool, error) {
+			return ws.instance.IsFenced() && ws.instance.IsServerHealthy() != nil, nil
+		})
+}
@@ -19,6 +19,8 @@ package url
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 )
 
 const (
@@ -40,18 +42,40 @@ const (
 	// PathPgStatus is the URL path for PostgreSQL Status
 	PathPgStatus string = "/pg/status"
 
+	// PathPgDatabase is the URL path for the declarative databases
+	PathPgDatabase string = "/pg/database"
+
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathVeleroPreBackup is the URL path for the Velero pre-backup hook
+	PathVeleroPreBackup string = "/velero/pre-backup"
+
+	// PathVeleroPostBackup is the URL path for the Velero post-backup hook
+	PathVeleroPostBackup string = "/velero/post-backup"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
+	// PathPgBouncerSaturation is the URL path for the saturation of PgBouncer
+	PathPgBouncerSaturation string = "/saturation"
+
+	// PathPgBouncerPoolerSaturation is the URL path for the saturation of
+	// PgBouncer, summed across all the instances of the pooler
+	PathPgBouncerPoolerSaturation string = "/saturation/pooler"
+
 	// PathUpdate is the URL path for the instance manager update function
 	PathUpdate string = "/update"
 
 	// PathCache is the URL path for cached resources
 	PathCache string = "/cache/"
 
+	// PathPprof is the URL path for the pprof and trace endpoints
+	PathPprof string = "/debug/pprof/"
+
+	// OperatorPprofPort is the port of the pprof server of the operator
+	OperatorPprofPort int = 6060
+
 	// StatusPort is the port for status HTTP requests
 	StatusPort int = 8000
 )
@@ -67,5 +91,5 @@ func Build(hostname, path string, port int) string {
 	if path[0] == '/' {
 		path = path[1:]
 	}
-	return fmt.Sprintf("http://%s:%d/%s", hostname, port, path)
+	return fmt.Sprintf("http://%s/%s", net.JoinHostPort(hostname, strconv.Itoa(port)), path)
 }
@@ -0,0 +1,529 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: plugin.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// IdentityClient is the client API for Identity service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IdentityClient interface {
+	// GetPluginMetadata returns the name and the version of the plugin
+	GetPluginMetadata(ctx context.Context, in *GetPluginMetadataRequest, opts ...grpc.CallOption) (*GetPluginMetadataResponse, error)
+	// GetCapabilities returns the services implemented by the plugin
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+	// Probe tells whether the plugin is ready to serve requests
+	Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error)
+	// Terminate asks the plugin to exit. It is used at the end of the
+	// Jobs, where the plugin sidecar would otherwise keep them running
+	Terminate(ctx context.Context, in *TerminateRequest, opts ...grpc.CallOption) (*TerminateResponse, error)
+}
+
+type identityClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIdentityClient(cc grpc.ClientConnInterface) IdentityClient {
+	return &identityClient{cc}
+}
+
+func (c *identityClient) GetPluginMetadata(ctx context.Context, in *GetPluginMetadataRequest, opts ...grpc.CallOption) (*GetPluginMetadataResponse, error) {
+	out := new(GetPluginMetadataResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Identity/GetPluginMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identityClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Identity/GetCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identityClient) Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error) {
+	out := new(ProbeResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Identity/Probe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identityClient) Terminate(ctx context.Context, in *TerminateRequest, opts ...grpc.CallOption) (*TerminateResponse, error) {
+	out := new(TerminateResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Identity/Terminate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IdentityServer is the server API for Identity service.
+// All implementations must embed UnimplementedIdentityServer
+// for forward compatibility
+type IdentityServer interface {
+	// GetPluginMetadata returns the name and the version of the plugin
+	GetPluginMetadata(context.Context, *GetPluginMetadataRequest) (*GetPluginMetadataResponse, error)
+	// GetCapabilities returns the services implemented by the plugin
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	// Probe tells whether the plugin is ready to serve requests
+	Probe(context.Context, *ProbeRequest) (*ProbeResponse, error)
+	// Terminate asks the plugin to exit. It is used at the end of the
+	// Jobs, where the plugin sidecar would otherwise keep them running
+	Terminate(context.Context, *TerminateRequest) (*TerminateResponse, error)
+	mustEmbedUnimplementedIdentityServer()
+}
+
+// UnimplementedIdentityServer must be embedded to have forward compatible implementations.
+type UnimplementedIdentityServer struct {
+}
+
+func (UnimplementedIdentityServer) GetPluginMetadata(context.Context, *GetPluginMetadataRequest) (*GetPluginMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPluginMetadata not implemented")
+}
+func (UnimplementedIdentityServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedIdentityServer) Probe(context.Context, *ProbeRequest) (*ProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Probe not implemented")
+}
+func (UnimplementedIdentityServer) Terminate(context.Context, *TerminateRequest) (*TerminateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Terminate not implemented")
+}
+func (UnimplementedIdentityServer) mustEmbedUnimplementedIdentityServer() {}
+
+// UnsafeIdentityServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IdentityServer will
+// result in compilation errors.
+type UnsafeIdentityServer interface {
+	mustEmbedUnimplementedIdentityServer()
+}
+
+func RegisterIdentityServer(s grpc.ServiceRegistrar, srv IdentityServer) {
+	s.RegisterService(&Identity_ServiceDesc, srv)
+}
+
+func _Identity_GetPluginMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPluginMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentityServer).GetPluginMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Identity/GetPluginMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentityServer).GetPluginMetadata(ctx, req.(*GetPluginMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identity_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentityServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Identity/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentityServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identity_Probe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentityServer).Probe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Identity/Probe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentityServer).Probe(ctx, req.(*ProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identity_Terminate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TerminateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentityServer).Terminate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Identity/Terminate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentityServer).Terminate(ctx, req.(*TerminateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Identity_ServiceDesc is the grpc.ServiceDesc for Identity service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Identity_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cnpg.plugin.v1.Identity",
+	HandlerType: (*IdentityServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPluginMetadata",
+			Handler:    _Identity_GetPluginMetadata_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _Identity_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "Probe",
+			Handler:    _Identity_Probe_Handler,
+		},
+		{
+			MethodName: "Terminate",
+			Handler:    _Identity_Terminate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// BackupClient is the client API for Backup service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BackupClient interface {
+	// Backup takes a physical base backup of the instance, returning
+	// when the backup is completed
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
+}
+
+type backupClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackupClient(cc grpc.ClientConnInterface) BackupClient {
+	return &backupClient{cc}
+}
+
+func (c *backupClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error) {
+	out := new(BackupResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Backup/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BackupServer is the server API for Backup service.
+// All implementations must embed UnimplementedBackupServer
+// for forward compatibility
+type BackupServer interface {
+	// Backup takes a physical base backup of the instance, returning
+	// when the backup is completed
+	Backup(context.Context, *BackupRequest) (*BackupResponse, error)
+	mustEmbedUnimplementedBackupServer()
+}
+
+// UnimplementedBackupServer must be embedded to have forward compatible implementations.
+type UnimplementedBackupServer struct {
+}
+
+func (UnimplementedBackupServer) Backup(context.Context, *BackupRequest) (*BackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (UnimplementedBackupServer) mustEmbedUnimplementedBackupServer() {}
+
+// UnsafeBackupServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BackupServer will
+// result in compilation errors.
+type UnsafeBackupServer interface {
+	mustEmbedUnimplementedBackupServer()
+}
+
+func RegisterBackupServer(s grpc.ServiceRegistrar, srv BackupServer) {
+	s.RegisterService(&Backup_ServiceDesc, srv)
+}
+
+func _Backup_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Backup/Backup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupServer).Backup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Backup_ServiceDesc is the grpc.ServiceDesc for Backup service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Backup_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cnpg.plugin.v1.Backup",
+	HandlerType: (*BackupServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Backup",
+			Handler:    _Backup_Backup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// WALClient is the client API for WAL service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WALClient interface {
+	// Archive copies a WAL file into the archive
+	Archive(ctx context.Context, in *ArchiveWALRequest, opts ...grpc.CallOption) (*ArchiveWALResponse, error)
+	// Restore copies a WAL file from the archive. A NOT_FOUND status
+	// is returned when the WAL file is not in the archive
+	Restore(ctx context.Context, in *RestoreWALRequest, opts ...grpc.CallOption) (*RestoreWALResponse, error)
+}
+
+type wALClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWALClient(cc grpc.ClientConnInterface) WALClient {
+	return &wALClient{cc}
+}
+
+func (c *wALClient) Archive(ctx context.Context, in *ArchiveWALRequest, opts ...grpc.CallOption) (*ArchiveWALResponse, error) {
+	out := new(ArchiveWALResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.WAL/Archive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wALClient) Restore(ctx context.Context, in *RestoreWALRequest, opts ...grpc.CallOption) (*RestoreWALResponse, error) {
+	out := new(RestoreWALResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.WAL/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WALServer is the server API for WAL service.
+// All implementations must embed UnimplementedWALServer
+// for forward compatibility
+type WALServer interface {
+	// Archive copies a WAL file into the archive
+	Archive(context.Context, *ArchiveWALRequest) (*ArchiveWALResponse, error)
+	// Restore copies a WAL file from the archive. A NOT_FOUND status
+	// is returned when the WAL file is not in the archive
+	Restore(context.Context, *RestoreWALRequest) (*RestoreWALResponse, error)
+	mustEmbedUnimplementedWALServer()
+}
+
+// UnimplementedWALServer must be embedded to have forward compatible implementations.
+type UnimplementedWALServer struct {
+}
+
+func (UnimplementedWALServer) Archive(context.Context, *ArchiveWALRequest) (*ArchiveWALResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Archive not implemented")
+}
+func (UnimplementedWALServer) Restore(context.Context, *RestoreWALRequest) (*RestoreWALResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedWALServer) mustEmbedUnimplementedWALServer() {}
+
+// UnsafeWALServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WALServer will
+// result in compilation errors.
+type UnsafeWALServer interface {
+	mustEmbedUnimplementedWALServer()
+}
+
+func RegisterWALServer(s grpc.ServiceRegistrar, srv WALServer) {
+	s.RegisterService(&WAL_ServiceDesc, srv)
+}
+
+func _WAL_Archive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveWALRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WALServer).Archive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.WAL/Archive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WALServer).Archive(ctx, req.(*ArchiveWALRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WAL_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreWALRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WALServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.WAL/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WALServer).Restore(ctx, req.(*RestoreWALRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WAL_ServiceDesc is the grpc.ServiceDesc for WAL service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WAL_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cnpg.plugin.v1.WAL",
+	HandlerType: (*WALServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Archive",
+			Handler:    _WAL_Archive_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _WAL_Restore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// RestoreClient is the client API for Restore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RestoreClient interface {
+	// Restore copies the content of a base backup in the data directory
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
+}
+
+type restoreClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRestoreClient(cc grpc.ClientConnInterface) RestoreClient {
+	return &restoreClient{cc}
+}
+
+func (c *restoreClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/cnpg.plugin.v1.Restore/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestoreServer is the server API for Restore service.
+// All implementations must embed UnimplementedRestoreServer
+// for forward compatibility
+type RestoreServer interface {
+	// Restore copies the content of a base backup in the data directory
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
+	mustEmbedUnimplementedRestoreServer()
+}
+
+// UnimplementedRestoreServer must be embedded to have forward compatible implementations.
+type UnimplementedRestoreServer struct {
+}
+
+func (UnimplementedRestoreServer) Restore(context.Context, *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedRestoreServer) mustEmbedUnimplementedRestoreServer() {}
+
+// UnsafeRestoreServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RestoreServer will
+// result in compilation errors.
+type UnsafeRestoreServer interface {
+	mustEmbedUnimplementedRestoreServer()
+}
+
+func RegisterRestoreServer(s grpc.ServiceRegistrar, srv RestoreServer) {
+	s.RegisterService(&Restore_ServiceDesc, srv)
+}
+
+func _Restore_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RestoreServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cnpg.plugin.v1.Restore/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RestoreServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Restore_ServiceDesc is the grpc.ServiceDesc for Restore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Restore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cnpg.plugin.v1.Restore",
+	HandlerType: (*RestoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Restore",
+			Handler:    _Restore_Restore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin contains the client of the plugins providing the backup,
+// WAL archiving and recovery engines, running as sidecars of the instances
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// SocketDirectory is the directory, shared by the instance manager and
+	// the plugins, where the plugins create their unix sockets
+	SocketDirectory = "/plugins"
+
+	// SocketVolumeName is the name of the volume containing the sockets
+	// of the plugins
+	SocketVolumeName = "plugins"
+
+	// SocketEnvName is the environment variable telling a plugin the path
+	// of the unix socket it should listen on
+	SocketEnvName = "PLUGIN_SOCKET"
+
+	// ContainerNamePrefix is the prefix of the name of the containers of
+	// the plugins
+	ContainerNamePrefix = "plugin-"
+)
+
+// DialTimeout is the time allowed to connect to a plugin
+var DialTimeout = 10 * time.Second
+
+// SocketPath gets the path of the unix socket of the plugin with the
+// passed name
+func SocketPath(name string) string {
+	return path.Join(SocketDirectory, name+".sock")
+}
+
+// ContainerName gets the name of the container of the plugin with the
+// passed name
+func ContainerName(name string) string {
+	return ContainerNamePrefix + name
+}
+
+// Client is a connection to a plugin, exposing its services
+type Client struct {
+	conn *grpc.ClientConn
+
+	Identity pluginapi.IdentityClient
+	Backup   pluginapi.BackupClient
+	WAL      pluginapi.WALClient
+	Restore  pluginapi.RestoreClient
+}
+
+// Connect connects to the plugin with the passed name
+func Connect(ctx context.Context, name string) (*Client, error) {
+	return ConnectToSocket(ctx, SocketPath(name))
+}
+
+// ConnectToSocket connects to the plugin listening on the passed unix socket
+func ConnectToSocket(ctx context.Context, socketPath string) (*Client, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(
+		dialCtx,
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("while connecting to the plugin on %s: %w", socketPath, err)
+	}
+
+	return &Client{
+		conn:     conn,
+		Identity: pluginapi.NewIdentityClient(conn),
+		Backup:   pluginapi.NewBackupClient(conn),
+		WAL:      pluginapi.NewWALClient(conn),
+		Restore:  pluginapi.NewRestoreClient(conn),
+	}, nil
+}
+
+// Close closes the connection to the plugin
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// GetStatus gets the metadata, the capabilities and the readiness of
+// the plugin. The failures are reported as a plugin not being ready
+func (client *Client) GetStatus(ctx context.Context, name string) postgres.PluginStatus {
+	result := postgres.PluginStatus{Name: name}
+
+	metadata, err := client.Identity.GetPluginMetadata(ctx, &pluginapi.GetPluginMetadataRequest{})
+	if err != nil {
+		result.Message = fmt.Sprintf("while getting the plugin metadata: %v", err)
+		return result
+	}
+	result.Version = metadata.GetVersion()
+
+	capabilities, err := client.Identity.GetCapabilities(ctx, &pluginapi.GetCapabilitiesRequest{})
+	if err != nil {
+		result.Message = fmt.Sprintf("while getting the plugin capabilities: %v", err)
+		return result
+	}
+	for _, capability := range capabilities.GetCapabilities() {
+		result.Capabilities = append(result.Capabilities, capability.GetType().String())
+	}
+
+	probe, err := client.Identity.Probe(ctx, &pluginapi.ProbeRequest{})
+	if err != nil {
+		result.Message = fmt.Sprintf("while probing the plugin: %v", err)
+		return result
+	}
+	result.Ready = probe.GetReady()
+	result.Message = probe.GetMessage()
+
+	return result
+}
+
+// GetStatus connects to the plugin with the passed name and gets its status
+func GetStatus(ctx context.Context, name string) postgres.PluginStatus {
+	client, err := Connect(ctx, name)
+	if err != nil {
+		return postgres.PluginStatus{Name: name, Message: err.Error()}
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	return client.GetStatus(ctx, name)
+}
+
+// HasCapability checks whether a plugin status reports the passed capability
+func HasCapability(pluginStatus postgres.PluginStatus, capability pluginapi.PluginCapability_Type) bool {
+	for _, item := range pluginStatus.Capabilities {
+		if item == capability.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound checks whether the error returned by a plugin means that the
+// requested object, i.e. a WAL file, is not in the archive
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// NewClusterInfo creates the information identifying the cluster in the
+// requests to the plugins
+func NewClusterInfo(namespace, clusterName, podName string) *pluginapi.ClusterInfo {
+	return &pluginapi.ClusterInfo{
+		Name:      clusterName,
+		Namespace: namespace,
+		PodName:   podName,
+	}
+}
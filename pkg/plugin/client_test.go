@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginapi "github.com/cloudnative-pg/cloudnative-pg/pkg/plugin/api"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeIdentityServer struct {
+	pluginapi.UnimplementedIdentityServer
+	ready bool
+}
+
+func (server *fakeIdentityServer) GetPluginMetadata(
+	context.Context,
+	*pluginapi.GetPluginMetadataRequest,
+) (*pluginapi.GetPluginMetadataResponse, error) {
+	return &pluginapi.GetPluginMetadataResponse{Name: "fake", Version: "1.0.0"}, nil
+}
+
+func (server *fakeIdentityServer) GetCapabilities(
+	context.Context,
+	*pluginapi.GetCapabilitiesRequest,
+) (*pluginapi.GetCapabilitiesResponse, error) {
+	return &pluginapi.GetCapabilitiesResponse{
+		Capabilities: []*pluginapi.PluginCapability{
+			{Type: pluginapi.PluginCapability_BACKUP},
+			{Type: pluginapi.PluginCapability_WAL_RESTORE},
+		},
+	}, nil
+}
+
+func (server *fakeIdentityServer) Probe(
+	context.Context,
+	*pluginapi.ProbeRequest,
+) (*pluginapi.ProbeResponse, error) {
+	if !server.ready {
+		return &pluginapi.ProbeResponse{Message: "repository not initialized"}, nil
+	}
+	return &pluginapi.ProbeResponse{Ready: true}, nil
+}
+
+type fakeWALServer struct {
+	pluginapi.UnimplementedWALServer
+}
+
+func (server *fakeWALServer) Restore(
+	context.Context,
+	*pluginapi.RestoreWALRequest,
+) (*pluginapi.RestoreWALResponse, error) {
+	return nil, status.Error(codes.NotFound, "WAL file not found")
+}
+
+var _ = Describe("Plugin client", func() {
+	var socketPath string
+	var identity *fakeIdentityServer
+
+	BeforeEach(func() {
+		socketDir, err := os.MkdirTemp("", "plugin")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(socketDir)).To(Succeed())
+		})
+
+		socketPath = path.Join(socketDir, "fake.sock")
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		identity = &fakeIdentityServer{ready: true}
+		server := grpc.NewServer()
+		pluginapi.RegisterIdentityServer(server, identity)
+		pluginapi.RegisterWALServer(server, &fakeWALServer{})
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		DeferCleanup(server.Stop)
+	})
+
+	It("computes the names of the sockets and of the containers", func() {
+		Expect(SocketPath("pgbackrest")).To(Equal("/plugins/pgbackrest.sock"))
+		Expect(ContainerName("pgbackrest")).To(Equal("plugin-pgbackrest"))
+	})
+
+	It("gets the status of the plugin", func(ctx SpecContext) {
+		client, err := ConnectToSocket(ctx, socketPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			Expect(client.Close()).To(Succeed())
+		}()
+
+		pluginStatus := client.GetStatus(ctx, "fake")
+		Expect(pluginStatus.Name).To(Equal("fake"))
+		Expect(pluginStatus.Version).To(Equal("1.0.0"))
+		Expect(pluginStatus.Capabilities).To(Equal([]string{"BACKUP", "WAL_RESTORE"}))
+		Expect(pluginStatus.Ready).To(BeTrue())
+		Expect(HasCapability(pluginStatus, pluginapi.PluginCapability_BACKUP)).To(BeTrue())
+		Expect(HasCapability(pluginStatus, pluginapi.PluginCapability_RESTORE)).To(BeFalse())
+
+		identity.ready = false
+		pluginStatus = client.GetStatus(ctx, "fake")
+		Expect(pluginStatus.Ready).To(BeFalse())
+		Expect(pluginStatus.Message).To(Equal("repository not initialized"))
+	})
+
+	It("reports the WAL files missing from the archive", func(ctx SpecContext) {
+		client, err := ConnectToSocket(ctx, socketPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			Expect(client.Close()).To(Succeed())
+		}()
+
+		_, err = client.WAL.Restore(ctx, &pluginapi.RestoreWALRequest{SourceWalName: "000000010000000000000001"})
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(IsNotFound(errors.New("connection refused"))).To(BeFalse())
+
+		_, err = client.WAL.Archive(ctx, &pluginapi.ArchiveWALRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+	})
+
+	It("reports the plugins that can't be reached as not ready", func(ctx SpecContext) {
+		DialTimeout = 100 * time.Millisecond
+		DeferCleanup(func() {
+			DialTimeout = 10 * time.Second
+		})
+
+		pluginStatus := GetStatus(ctx, "missing")
+		Expect(pluginStatus.Ready).To(BeFalse())
+		Expect(pluginStatus.Message).ToNot(BeEmpty())
+	})
+})
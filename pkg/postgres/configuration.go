@@ -216,6 +216,24 @@ type ConfigurationInfo struct {
 	// The number of desired number of synchronous replicas
 	SyncReplicas int
 
+	// The method used to select the synchronous replicas, `ANY` when empty
+	SyncReplicasMethod string
+
+	// The standby names listed before the replicas
+	SyncReplicasStandbyNamesPre []string
+
+	// The standby names listed after the replicas
+	SyncReplicasStandbyNamesPost []string
+
+	// Whether the logical replication slots flagged for failover are
+	// synchronized to the replicas
+	SynchronizeLogicalSlots bool
+
+	// The physical replication slots of the replicas, which need to have
+	// received the changes before they are sent to the logical
+	// replication clients
+	SynchronizedStandbySlots []string
+
 	// If the generated configuration should contain shared_preload_libraries too or no
 	IncludingSharedPreloadLibraries bool
 
@@ -224,6 +242,20 @@ type ConfigurationInfo struct {
 
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The list of tablespaces used to store the temporary objects
+	TemporaryTablespaces []string
+
+	// Whether wal_log_hints must be enabled for pg_rewind to work
+	IsWalLogHintsRequired bool
+
+	// The archive_timeout replacing the global default one, as
+	// configured in the operator
+	DefaultArchiveTimeout string
+
+	// The archive_timeout derived from the target RPO of the cluster.
+	// When set, it overrides the value chosen by the user
+	ArchiveTimeout string
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -309,7 +341,6 @@ var (
 		"unix_socket_group":         blockedConfigurationParameter,
 		"unix_socket_permissions":   blockedConfigurationParameter,
 		"wal_level":                 fixedConfigurationParameter,
-		"wal_log_hints":             fixedConfigurationParameter,
 
 		// The following parameters need a reload to be applied
 		"archive_cleanup_command":                blockedConfigurationParameter,
@@ -365,6 +396,10 @@ var (
 			"dynamic_shared_memory_type": "posix",
 			"wal_sender_timeout":         "5s",
 			"wal_receiver_timeout":       "5s",
+			// Enabled by default to keep pg_rewind available; it can only
+			// be disabled when pg_rewind is not needed to re-attach the
+			// former primaries
+			"wal_log_hints": "on",
 			// Workaround for PostgreSQL not behaving correctly when
 			// a default value is not explicit in the postgresql.conf and
 			// the parameter cannot be changed without a restart.
@@ -392,7 +427,6 @@ var (
 				LogPath, LogFileName),
 			"port":                fmt.Sprint(ServerPort),
 			"wal_level":           "logical",
-			"wal_log_hints":       "on",
 			"full_page_writes":    "on",
 			"ssl":                 "on",
 			"ssl_cert_file":       ServerCertificateLocation,
@@ -508,6 +542,16 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		}
 	}
 
+	// pg_rewind needs wal_log_hints, unless data checksums are enabled
+	if info.IncludingMandatory && info.IsWalLogHintsRequired {
+		configuration.OverwriteConfig("wal_log_hints", "on")
+	}
+
+	// The target RPO takes precedence over the archive_timeout chosen by the user
+	if info.ArchiveTimeout != "" {
+		configuration.OverwriteConfig("archive_timeout", info.ArchiveTimeout)
+	}
+
 	// Apply the correct archive_mode
 	if info.IsReplicaCluster {
 		configuration.OverwriteConfig("archive_mode", "always")
@@ -515,9 +559,17 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig("archive_mode", "on")
 	}
 
+	// Apply the temporary tablespaces, unless chosen by the user
+	if _, found := info.UserSettings["temp_tablespaces"]; !found && len(info.TemporaryTablespaces) > 0 {
+		configuration.OverwriteConfig("temp_tablespaces", strings.Join(info.TemporaryTablespaces, ","))
+	}
+
 	// Apply the list of replicas
 	setReplicasListConfigurations(info, configuration)
 
+	// Apply the synchronization of the logical replication slots
+	setLogicalSlotsSynchronizationConfigurations(info, configuration)
+
 	if info.IncludingSharedPreloadLibraries {
 		// Set all managed shared preload libraries
 		setManagedSharedPreloadLibraries(info, configuration)
@@ -537,6 +589,11 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 		configuration.OverwriteConfig(key, value)
 	}
 
+	// the operator can be configured with a different archive_timeout
+	if info.DefaultArchiveTimeout != "" {
+		configuration.OverwriteConfig("archive_timeout", info.DefaultArchiveTimeout)
+	}
+
 	// apply settings relative to a certain PostgreSQL version
 	for constraints, settings := range info.Settings.DefaultSettings {
 		if constraints.Min == MajorVersionRangeUnlimited || (constraints.Min <= info.MajorVersion) {
@@ -583,15 +640,40 @@ func setUserSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConf
 	}
 }
 
+// setLogicalSlotsSynchronizationConfigurations sets the parameters
+// synchronizing the logical replication slots to the replicas
+func setLogicalSlotsSynchronizationConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
+	if !info.SynchronizeLogicalSlots {
+		return
+	}
+
+	configuration.OverwriteConfig("sync_replication_slots", "on")
+	configuration.OverwriteConfig("hot_standby_feedback", "on")
+	if len(info.SynchronizedStandbySlots) > 0 {
+		configuration.OverwriteConfig("synchronized_standby_slots", strings.Join(info.SynchronizedStandbySlots, ","))
+	}
+}
+
 // setReplicasListConfigurations sets the standby node list
 func setReplicasListConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
-	if info.SyncReplicasElectable != nil && info.SyncReplicas > 0 {
-		escapedReplicas := make([]string, len(info.SyncReplicasElectable))
-		for idx, name := range info.SyncReplicasElectable {
+	standbyNames := make([]string, 0,
+		len(info.SyncReplicasStandbyNamesPre)+len(info.SyncReplicasElectable)+len(info.SyncReplicasStandbyNamesPost))
+	standbyNames = append(standbyNames, info.SyncReplicasStandbyNamesPre...)
+	standbyNames = append(standbyNames, info.SyncReplicasElectable...)
+	standbyNames = append(standbyNames, info.SyncReplicasStandbyNamesPost...)
+
+	if len(standbyNames) > 0 && info.SyncReplicas > 0 {
+		escapedReplicas := make([]string, len(standbyNames))
+		for idx, name := range standbyNames {
 			escapedReplicas[idx] = escapePostgresConfLiteral(name)
 		}
+		method := info.SyncReplicasMethod
+		if method == "" {
+			method = "ANY"
+		}
 		configuration.OverwriteConfig(SynchronousStandbyNames, fmt.Sprintf(
-			"ANY %v (%v)",
+			"%s %v (%v)",
+			method,
 			info.SyncReplicas,
 			strings.Join(escapedReplicas, ",")))
 	}
@@ -161,6 +161,69 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			ContainElements("some_library", "another_library"), Not(ContainElement(""))))
 	})
 
+	It("sets the temporary tablespaces unless chosen by the user", func() {
+		info := ConfigurationInfo{
+			Settings:             CnpgConfigurationSettings,
+			MajorVersion:         130000,
+			IncludingMandatory:   true,
+			TemporaryTablespaces: []string{"temp1", "temp2"},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("temp_tablespaces")).To(Equal("temp1,temp2"))
+
+		info.UserSettings = map[string]string{"temp_tablespaces": "temp2"}
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("temp_tablespaces")).To(Equal("temp2"))
+	})
+
+	It("enables wal_log_hints only when required by pg_rewind", func() {
+		info := ConfigurationInfo{
+			Settings:              CnpgConfigurationSettings,
+			MajorVersion:          130000,
+			UserSettings:          map[string]string{"wal_log_hints": "off"},
+			IncludingMandatory:    true,
+			IsWalLogHintsRequired: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("wal_log_hints")).To(Equal("on"))
+
+		info.IsWalLogHintsRequired = false
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("wal_log_hints")).To(Equal("off"))
+		Expect(config.GetConfig("full_page_writes")).To(Equal("on"))
+	})
+
+	It("keeps wal_log_hints enabled by default when not required", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("wal_log_hints")).To(Equal("on"))
+	})
+
+	It("applies the archive_timeout configured in the operator and derived from the target RPO", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 130000,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("archive_timeout")).To(Equal("5min"))
+
+		info.DefaultArchiveTimeout = "10min"
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("archive_timeout")).To(Equal("10min"))
+
+		info.UserSettings = map[string]string{"archive_timeout": "2min"}
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("archive_timeout")).To(Equal("2min"))
+
+		info.ArchiveTimeout = "60s"
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("archive_timeout")).To(Equal("60s"))
+	})
+
 	When("we are using synchronous replication", func() {
 		It("generate the correct value for the synchronous_standby_names parameter", func() {
 			info := ConfigurationInfo{
@@ -179,6 +242,42 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("synchronous_standby_names")).
 				To(Equal("ANY 2 (\"one\",\"two\",\"three\")"))
 		})
+
+		It("lists the external standby names around the replicas with the configured method", func() {
+			info := ConfigurationInfo{
+				Settings:                     CnpgConfigurationSettings,
+				MajorVersion:                 130000,
+				UserSettings:                 settings,
+				IncludingMandatory:           true,
+				SyncReplicasElectable:        []string{"one"},
+				SyncReplicas:                 2,
+				SyncReplicasMethod:           "FIRST",
+				SyncReplicasStandbyNamesPre:  []string{"dr-1"},
+				SyncReplicasStandbyNamesPost: []string{"dr-2"},
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("synchronous_standby_names")).
+				To(Equal("FIRST 2 (\"dr-1\",\"one\",\"dr-2\")"))
+		})
+	})
+
+	It("synchronizes the logical replication slots to the replicas when requested", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       170000,
+			UserSettings:       map[string]string{"hot_standby_feedback": "off"},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("sync_replication_slots")).To(BeEmpty())
+		Expect(config.GetConfig("synchronized_standby_slots")).To(BeEmpty())
+
+		info.SynchronizeLogicalSlots = true
+		info.SynchronizedStandbySlots = []string{"_cnpg_one", "_cnpg_two"}
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("sync_replication_slots")).To(Equal("on"))
+		Expect(config.GetConfig("hot_standby_feedback")).To(Equal("on"))
+		Expect(config.GetConfig("synchronized_standby_slots")).To(Equal("_cnpg_one,_cnpg_two"))
 	})
 
 	It("checks if PreserveFixedSettingsFromUser works properly", func() {
@@ -0,0 +1,175 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPromotionTokenNotReached is raised when an instance has not yet
+// replayed the WALs up to the checkpoint contained in a promotion token
+var ErrPromotionTokenNotReached = errors.New("the promotion token has not been reached yet")
+
+// The keys of the pg_controldata output used in the demotion token
+const (
+	pgControldataSystemIdentifier = "Database system identifier"
+	pgControldataTimelineID       = "Latest checkpoint's TimeLineID"
+	pgControldataREDOLocation     = "Latest checkpoint's REDO location"
+	pgControldataREDOWALFile      = "Latest checkpoint's REDO WAL file"
+	pgControldataLatestCheckpoint = "Time of latest checkpoint"
+)
+
+// DemotionToken is the information written by the designated primary of
+// a demoted cluster, taken from the shutdown checkpoint of the former
+// primary. The replica cluster taking its place is promoted only after
+// having replayed the WALs up to that checkpoint
+type DemotionToken struct {
+	// DatabaseSystemIdentifier is the system identifier of the cluster
+	DatabaseSystemIdentifier string `json:"databaseSystemIdentifier"`
+
+	// LatestCheckpointTimelineID is the timeline of the latest checkpoint
+	LatestCheckpointTimelineID string `json:"latestCheckpointTimelineID"`
+
+	// REDOLocation is the REDO location of the latest checkpoint
+	REDOLocation string `json:"redoLocation"`
+
+	// REDOWALFile is the WAL file containing the REDO location
+	REDOWALFile string `json:"redoWalFile"`
+
+	// LatestCheckpointTime is the time of the latest checkpoint
+	LatestCheckpointTime string `json:"latestCheckpointTime"`
+}
+
+// ParsePgControldataOutput parses the output of pg_controldata
+// into a map of its keys and values
+func ParsePgControldataOutput(data string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+// NewDemotionTokenFromControlData creates a demotion token from the
+// parsed output of pg_controldata
+func NewDemotionTokenFromControlData(controlData map[string]string) (*DemotionToken, error) {
+	token := &DemotionToken{
+		DatabaseSystemIdentifier:   controlData[pgControldataSystemIdentifier],
+		LatestCheckpointTimelineID: controlData[pgControldataTimelineID],
+		REDOLocation:               controlData[pgControldataREDOLocation],
+		REDOWALFile:                controlData[pgControldataREDOWALFile],
+		LatestCheckpointTime:       controlData[pgControldataLatestCheckpoint],
+	}
+	if err := token.IsValid(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// IsValid checks whether the token contains every required information
+func (token *DemotionToken) IsValid() error {
+	switch {
+	case token.DatabaseSystemIdentifier == "":
+		return fmt.Errorf("missing database system identifier")
+	case token.LatestCheckpointTimelineID == "":
+		return fmt.Errorf("missing latest checkpoint timeline ID")
+	case token.REDOLocation == "":
+		return fmt.Errorf("missing REDO location")
+	}
+
+	if _, err := LSN(token.REDOLocation).Parse(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Encode encodes the token in the format used in the Cluster status
+func (token *DemotionToken) Encode() (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeDemotionToken decodes and validates a token created by Encode
+func DecodeDemotionToken(value string) (*DemotionToken, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("while decoding the demotion token: %w", err)
+	}
+
+	var token DemotionToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("while parsing the demotion token: %w", err)
+	}
+
+	if err := token.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid demotion token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// ValidateAgainstControlData checks whether an instance, described by the
+// parsed output of its pg_controldata, can be promoted according to this
+// token. ErrPromotionTokenNotReached is returned when the instance is still
+// replaying the WALs of the former primary
+func (token *DemotionToken) ValidateAgainstControlData(controlData map[string]string) error {
+	systemIdentifier := controlData[pgControldataSystemIdentifier]
+	if systemIdentifier != token.DatabaseSystemIdentifier {
+		return fmt.Errorf("mismatching database system identifier: token has %q, instance has %q",
+			token.DatabaseSystemIdentifier, systemIdentifier)
+	}
+
+	tokenTimeline, err := strconv.Atoi(token.LatestCheckpointTimelineID)
+	if err != nil {
+		return fmt.Errorf("while parsing the timeline of the token: %w", err)
+	}
+	timeline, err := strconv.Atoi(controlData[pgControldataTimelineID])
+	if err != nil {
+		return fmt.Errorf("while parsing the timeline of the instance: %w", err)
+	}
+	if timeline > tokenTimeline {
+		return fmt.Errorf("the instance is on timeline %d, after the one of the token (%d)",
+			timeline, tokenTimeline)
+	}
+
+	redoLocation := LSN(controlData[pgControldataREDOLocation])
+	if _, err := redoLocation.Parse(); err != nil {
+		return err
+	}
+	if timeline < tokenTimeline || redoLocation.Less(LSN(token.REDOLocation)) {
+		return ErrPromotionTokenNotReached
+	}
+	if redoLocation != LSN(token.REDOLocation) {
+		return fmt.Errorf("the instance REDO location %s is after the one of the token (%s)",
+			redoLocation, token.REDOLocation)
+	}
+
+	return nil
+}
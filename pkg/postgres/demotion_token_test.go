@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Demotion token", func() {
+	const controlData = `pg_control version number:            1300
+Catalog version number:               202307071
+Database system identifier:           7296712156551992651
+Database cluster state:               shut down
+pg_control last modified:             Mon 09 Oct 2023 10:11:12 AM UTC
+Latest checkpoint location:           0/5000028
+Latest checkpoint's REDO location:    0/5000028
+Latest checkpoint's REDO WAL file:    000000010000000000000005
+Latest checkpoint's TimeLineID:       1
+Time of latest checkpoint:            Mon 09 Oct 2023 10:11:10 AM UTC
+`
+
+	It("parses the output of pg_controldata", func() {
+		parsed := ParsePgControldataOutput(controlData)
+		Expect(parsed).To(HaveKeyWithValue("Database cluster state", "shut down"))
+		Expect(parsed).To(HaveKeyWithValue("Latest checkpoint's REDO location", "0/5000028"))
+		Expect(parsed).To(HaveKeyWithValue("pg_control last modified", "Mon 09 Oct 2023 10:11:12 AM UTC"))
+	})
+
+	It("is created from the control data and survives an encoding round trip", func() {
+		token, err := NewDemotionTokenFromControlData(ParsePgControldataOutput(controlData))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*token).To(Equal(DemotionToken{
+			DatabaseSystemIdentifier:   "7296712156551992651",
+			LatestCheckpointTimelineID: "1",
+			REDOLocation:               "0/5000028",
+			REDOWALFile:                "000000010000000000000005",
+			LatestCheckpointTime:       "Mon 09 Oct 2023 10:11:10 AM UTC",
+		}))
+
+		encoded, err := token.Encode()
+		Expect(err).ToNot(HaveOccurred())
+		decoded, err := DecodeDemotionToken(encoded)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decoded).To(Equal(token))
+	})
+
+	It("rejects incomplete or malformed tokens", func() {
+		_, err := NewDemotionTokenFromControlData(map[string]string{})
+		Expect(err).To(HaveOccurred())
+
+		_, err = DecodeDemotionToken("not a token")
+		Expect(err).To(HaveOccurred())
+
+		encoded, err := (&DemotionToken{
+			DatabaseSystemIdentifier:   "7296712156551992651",
+			LatestCheckpointTimelineID: "1",
+			REDOLocation:               "wrong",
+		}).Encode()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = DecodeDemotionToken(encoded)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("validation against the control data of an instance", func() {
+		token := &DemotionToken{
+			DatabaseSystemIdentifier:   "7296712156551992651",
+			LatestCheckpointTimelineID: "2",
+			REDOLocation:               "0/5000028",
+		}
+		controlData := func(systemIdentifier, timeline, redoLocation string) map[string]string {
+			return map[string]string{
+				"Database system identifier":        systemIdentifier,
+				"Latest checkpoint's TimeLineID":    timeline,
+				"Latest checkpoint's REDO location": redoLocation,
+			}
+		}
+
+		It("succeeds when the instance has reached the checkpoint of the token", func() {
+			Expect(token.ValidateAgainstControlData(controlData("7296712156551992651", "2", "0/5000028"))).
+				To(Succeed())
+		})
+
+		It("waits for the instance to reach the checkpoint of the token", func() {
+			Expect(token.ValidateAgainstControlData(controlData("7296712156551992651", "2", "0/4000028"))).
+				To(MatchError(ErrPromotionTokenNotReached))
+			Expect(token.ValidateAgainstControlData(controlData("7296712156551992651", "1", "0/6000028"))).
+				To(MatchError(ErrPromotionTokenNotReached))
+		})
+
+		It("fails when the instance is not following the former primary", func() {
+			for _, data := range []map[string]string{
+				controlData("1234", "2", "0/5000028"),
+				controlData("7296712156551992651", "3", "0/5000028"),
+				controlData("7296712156551992651", "2", "0/6000028"),
+			} {
+				err := token.ValidateAgainstControlData(data)
+				Expect(err).To(HaveOccurred())
+				Expect(err).ToNot(MatchError(ErrPromotionTokenNotReached))
+			}
+		})
+	})
+})
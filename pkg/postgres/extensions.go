@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// ExtensionsBaseDirectory is the directory where the volumes
+	// containing the extensions are mounted
+	ExtensionsBaseDirectory = "/extensions"
+
+	// ExtensionImageDirectory is the directory containing the files of
+	// the extension inside an extension image
+	ExtensionImageDirectory = "/extension"
+)
+
+// GetExtensionDirectory gets the directory where the volume containing
+// an extension is mounted
+func GetExtensionDirectory(name string) string {
+	return path.Join(ExtensionsBaseDirectory, name)
+}
+
+// GetSharedExtensionsDirectory gets the directory where PostgreSQL looks for
+// the control files of the extensions, given the version of PostgreSQL,
+// according to the layout of the PGDG Debian packages used by the
+// operand images
+func GetSharedExtensionsDirectory(version int) string {
+	major := version / 10000
+	if major < firstMajorWithoutMinor {
+		return fmt.Sprintf("/usr/share/postgresql/%d.%d/extension", major, version/100%100)
+	}
+
+	return fmt.Sprintf("/usr/share/postgresql/%d/extension", major)
+}
+
+// BuildDynamicLibraryPath builds the value of the "dynamic_library_path"
+// parameter, looking for the libraries in the default directory first and
+// then in the directories of the passed extensions
+func BuildDynamicLibraryPath(extensionNames []string) string {
+	directories := make([]string, 0, len(extensionNames)+1)
+	directories = append(directories, "$libdir")
+	for _, name := range extensionNames {
+		directories = append(directories, path.Join(GetExtensionDirectory(name), "lib"))
+	}
+
+	return strings.Join(directories, ":")
+}
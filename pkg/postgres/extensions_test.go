@@ -0,0 +1,35 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL extension directories", func() {
+	It("follows the layout of the PGDG packages", func() {
+		Expect(GetSharedExtensionsDirectory(150001)).To(Equal("/usr/share/postgresql/15/extension"))
+		Expect(GetSharedExtensionsDirectory(90603)).To(Equal("/usr/share/postgresql/9.6/extension"))
+	})
+
+	It("looks for the libraries in the default directory first", func() {
+		Expect(BuildDynamicLibraryPath(nil)).To(Equal("$libdir"))
+		Expect(BuildDynamicLibraryPath([]string{"pgvector", "postgis"})).To(
+			Equal("$libdir:/extensions/pgvector/lib:/extensions/postgis/lib"))
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSharedBuffers is the value of shared_buffers PostgreSQL
+	// uses when it is not configured
+	DefaultSharedBuffers = "128MB"
+
+	// DefaultWorkMem is the value of work_mem PostgreSQL uses when it
+	// is not configured
+	DefaultWorkMem = "4MB"
+
+	// SharedBuffersUnit is the number of bytes of a shared_buffers
+	// value without a unit, which is expressed in blocks
+	SharedBuffersUnit = 8 * 1024
+
+	// WorkMemUnit is the number of bytes of a work_mem value without a
+	// unit, which is expressed in kilobytes
+	WorkMemUnit = 1024
+)
+
+// memoryUnits are the memory units accepted by PostgreSQL, which are
+// case-sensitive and always use multiples of 1024
+var memoryUnits = map[string]int64{
+	"B":  1,
+	"kB": 1024,
+	"MB": 1024 * 1024,
+	"GB": 1024 * 1024 * 1024,
+	"TB": 1024 * 1024 * 1024 * 1024,
+}
+
+// ParseMemoryValue parses the value of a PostgreSQL memory parameter,
+// returning it in bytes. Values without a unit are multiplied by
+// the passed default unit, expressed in bytes
+func ParseMemoryValue(value string, defaultUnit int64) (int64, error) {
+	value = strings.TrimSpace(value)
+	numberEnd := strings.IndexFunc(value, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if numberEnd == -1 {
+		numberEnd = len(value)
+	}
+
+	number, err := strconv.ParseInt(value[:numberEnd], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory value %q: %w", value, err)
+	}
+
+	unit := strings.TrimSpace(value[numberEnd:])
+	if unit == "" {
+		return number * defaultUnit, nil
+	}
+
+	multiplier, ok := memoryUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid memory unit in %q, valid units are B, kB, MB, GB and TB", value)
+	}
+	return number * multiplier, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL memory values", func() {
+	It("parses values with a unit", func() {
+		Expect(ParseMemoryValue("128MB", SharedBuffersUnit)).To(Equal(int64(128 * 1024 * 1024)))
+		Expect(ParseMemoryValue("4 GB", WorkMemUnit)).To(Equal(int64(4 * 1024 * 1024 * 1024)))
+		Expect(ParseMemoryValue("64kB", WorkMemUnit)).To(Equal(int64(64 * 1024)))
+	})
+
+	It("uses the default unit for values without a unit", func() {
+		Expect(ParseMemoryValue("16384", SharedBuffersUnit)).To(Equal(int64(128 * 1024 * 1024)))
+		Expect(ParseMemoryValue("4096", WorkMemUnit)).To(Equal(int64(4 * 1024 * 1024)))
+	})
+
+	It("raises errors for invalid values", func() {
+		_, err := ParseMemoryValue("", WorkMemUnit)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMemoryValue("4mb", WorkMemUnit)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMemoryValue("MB", WorkMemUnit)
+		Expect(err).To(HaveOccurred())
+	})
+})
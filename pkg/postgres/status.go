@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -41,6 +42,7 @@ type PostgresqlStatus struct {
 	Pod                       corev1.Pod `json:"pod"`
 	IsPgRewindRunning         bool       `json:"isPgRewindRunning"`
 	TotalInstanceSize         string     `json:"totalInstanceSize"`
+	ClientConnections         int        `json:"clientConnections,omitempty"`
 	MightBeUnavailable        bool       `json:"mightBeUnavailable"`
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
@@ -68,6 +70,17 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The history of the current timeline, only populated on the primary
+	TimelineHistory []TimelineHistoryEntry `json:"timelineHistory,omitempty"`
+
+	// Whether the settings of the instance allow pg_rewind to be used:
+	// data checksums or wal_log_hints, together with full_page_writes,
+	// need to be enabled. This is not reported by the older instance
+	// managers
+	// SELECT (current_setting('data_checksums')::bool OR current_setting('wal_log_hints')::bool)
+	//		AND current_setting('full_page_writes')::bool
+	IsRewindSupported *bool `json:"isRewindSupported,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error error `json:"-"`
@@ -92,6 +105,116 @@ type PostgresqlStatus struct {
 	ReplicationInfo PgStatReplicationList `json:"replicationInfo,omitempty"`
 	// contains the PgReplicationSlot rows content.
 	ReplicationSlotsInfo PgReplicationSlotList `json:"replicationSlotsInfo,omitempty"`
+
+	// The logical replication slots synchronized from the primary that
+	// can be used after the promotion of this replica
+	SynchronizedLogicalSlots []string `json:"synchronizedLogicalSlots,omitempty"`
+
+	// contains the transactions and the locks that are blocking vacuum
+	// or other sessions
+	LongRunningTransactions *LongRunningTransactions `json:"longRunningTransactions,omitempty"`
+
+	// The status of the plugins running as sidecars of the instance
+	PluginStatus []PluginStatus `json:"pluginStatus,omitempty"`
+
+	// The usage and the growth forecast of the volumes of the instance
+	StorageForecasts []StorageForecast `json:"storageForecasts,omitempty"`
+
+	// The time of the instance when the status has been sent to the operator
+	Heartbeat *time.Time `json:"heartbeat,omitempty"`
+
+	// The difference between the clock of the instance and the one of the
+	// operator, estimated from the heartbeat. This field is only populated
+	// in the operator
+	ClockSkew time.Duration `json:"-"`
+
+	// True when the clock skew is above the maximum allowed by the
+	// cluster. This field is only populated in the operator
+	IsClockSkewed bool `json:"-"`
+
+	// The priority of the instance when a new primary is elected. This
+	// field is only populated in the operator
+	PromotionPriority int32 `json:"-"`
+
+	// True when every declared logical replication slot has been
+	// synchronized to this replica, so that the logical replication
+	// clients can resume streaming after its promotion. This field is
+	// only populated in the operator
+	HasFailoverSlots bool `json:"-"`
+}
+
+// PluginStatus is the status of a plugin, as detected by the instance
+// manager probing it
+type PluginStatus struct {
+	// The name of the plugin
+	Name string `json:"name"`
+
+	// The version of the plugin, as reported by its metadata
+	Version string `json:"version,omitempty"`
+
+	// The services implemented by the plugin
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// True when the plugin is ready to serve requests
+	Ready bool `json:"ready"`
+
+	// The reason why the plugin is not ready
+	Message string `json:"message,omitempty"`
+}
+
+// StorageForecast is the usage of a volume of the instance, with the
+// time left before it is full at its current growth rate
+type StorageForecast struct {
+	// The volume, `pgdata` or `wal`
+	Volume string `json:"volume"`
+
+	// The bytes used in the volume
+	UsedBytes uint64 `json:"usedBytes"`
+
+	// The size of the volume in bytes
+	CapacityBytes uint64 `json:"capacityBytes"`
+
+	// The growth rate of the used bytes per second, computed over the
+	// samples taken in the forecast window
+	GrowthBytesPerSecond float64 `json:"growthBytesPerSecond"`
+
+	// The days left before the volume is full at the current growth rate.
+	// It is not set when the volume is not growing, or when the samples
+	// don't cover enough time to make a forecast
+	DaysUntilFull *float64 `json:"daysUntilFull,omitempty"`
+}
+
+// LongRunningTransactions contains the transactions older than the
+// configured threshold and the sessions involved in lock chains
+type LongRunningTransactions struct {
+	// The number of transactions older than the threshold
+	Transactions int `json:"transactions"`
+
+	// The age, in seconds, of the oldest transaction
+	OldestTransactionAge float64 `json:"oldestTransactionAge"`
+
+	// The number of prepared transactions older than the threshold
+	PreparedTransactions int `json:"preparedTransactions"`
+
+	// The age, in seconds, of the oldest prepared transaction
+	OldestPreparedTransactionAge float64 `json:"oldestPreparedTransactionAge"`
+
+	// The start time of the oldest transaction, or prepared transaction,
+	// older than the threshold
+	OldestTransactionStart *time.Time `json:"oldestTransactionStart,omitempty"`
+
+	// The number of sessions waiting for a lock held by another session
+	BlockedSessions int `json:"blockedSessions"`
+
+	// The number of sessions holding a lock other sessions are waiting for
+	BlockingSessions int `json:"blockingSessions"`
+}
+
+// IsEmpty checks if no long-running transaction and no lock chain has
+// been detected
+func (transactions *LongRunningTransactions) IsEmpty() bool {
+	return transactions == nil ||
+		(transactions.Transactions == 0 && transactions.PreparedTransactions == 0 && transactions.BlockedSessions == 0)
 }
 
 // PgStatReplication contains the replications of replicas as reported by the primary instance
@@ -107,6 +230,9 @@ type PgStatReplication struct {
 	ReplayLag       string `json:"replayLag,omitempty"`
 	SyncState       string `json:"syncState,omitempty"`
 	SyncPriority    string `json:"syncPriority,omitempty"`
+	// Whether the connection is encrypted, nil when not
+	// reported by the instance manager
+	Encrypted *bool `json:"encrypted,omitempty"`
 }
 
 // AddPod store the Pod inside the status
@@ -265,9 +391,23 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return false
 	}
 
-	// Compare received LSN (bigger LSN orders first)
-	if list.Items[i].ReceivedLsn != list.Items[j].ReceivedLsn {
-		return !list.Items[i].ReceivedLsn.Less(list.Items[j].ReceivedLsn)
+	// Compare the most advanced LSN available on the instances, be it
+	// received via streaming replication or restored from the WAL
+	// archive (bigger LSN orders first)
+	if lsnI, lsnJ := list.Items[i].GetAvailableLsn(), list.Items[j].GetAvailableLsn(); lsnI != lsnJ {
+		return !lsnI.Less(lsnJ)
+	}
+
+	// Between replicas having the same WAL, prefer the ones where the
+	// logical replication slots have been synchronized
+	if list.Items[i].HasFailoverSlots != list.Items[j].HasFailoverSlots {
+		return list.Items[i].HasFailoverSlots
+	}
+
+	// Between replicas having the same WAL, prefer the ones having
+	// the highest promotion priority
+	if list.Items[i].PromotionPriority != list.Items[j].PromotionPriority {
+		return list.Items[i].PromotionPriority > list.Items[j].PromotionPriority
 	}
 
 	// Compare replay LSN (bigger LSN orders first)
@@ -275,9 +415,25 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return !list.Items[i].ReplayLsn.Less(list.Items[j].ReplayLsn)
 	}
 
+	// Between equally up-to-date replicas, prefer the ones whose clock
+	// is synchronized, as the time-based decisions taken after the
+	// promotion depend on it
+	if list.Items[i].IsClockSkewed != list.Items[j].IsClockSkewed {
+		return !list.Items[i].IsClockSkewed
+	}
+
 	return list.Items[i].Pod.Name < list.Items[j].Pod.Name
 }
 
+// GetAvailableLsn returns the most advanced LSN between the received and
+// the replayed ones, which is the WAL that would be kept after a promotion
+func (status PostgresqlStatus) GetAvailableLsn() LSN {
+	if status.ReceivedLsn.Less(status.ReplayLsn) || status.ReceivedLsn == "" {
+		return status.ReplayLsn
+	}
+	return status.ReceivedLsn
+}
+
 // AreWalReceiversDown checks if every WAL receiver of the cluster is down
 // ignoring the status of the primary, that does not matter during
 // a switchover or a failover
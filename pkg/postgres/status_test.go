@@ -191,3 +191,50 @@ var _ = Describe("PostgreSQL status real", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL status with clock skew", func() {
+	It("prefers the synchronized replicas between the equally up-to-date ones", func() {
+		list := PostgresqlStatusList{Items: []PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}}, ReceivedLsn: "1/23", IsClockSkewed: true},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}}, ReceivedLsn: "1/23"},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}}, ReceivedLsn: "1/24", IsClockSkewed: true},
+		}}
+		sort.Sort(&list)
+		Expect(list.Items[0].Pod.Name).To(Equal("server-3"))
+		Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+		Expect(list.Items[2].Pod.Name).To(Equal("server-1"))
+	})
+})
+
+var _ = Describe("PostgreSQL status with promotion priorities", func() {
+	It("considers the replayed LSN when it is more advanced than the received one", func() {
+		status := PostgresqlStatus{ReceivedLsn: "1/23", ReplayLsn: "1/25"}
+		Expect(status.GetAvailableLsn()).To(Equal(LSN("1/25")))
+		status = PostgresqlStatus{ReplayLsn: "1/25"}
+		Expect(status.GetAvailableLsn()).To(Equal(LSN("1/25")))
+		status = PostgresqlStatus{ReceivedLsn: "1/26", ReplayLsn: "1/25"}
+		Expect(status.GetAvailableLsn()).To(Equal(LSN("1/26")))
+	})
+
+	It("prefers the replicas with the highest priority between the equally up-to-date ones", func() {
+		list := PostgresqlStatusList{Items: []PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}}, ReceivedLsn: "1/23"},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}}, ReceivedLsn: "1/23", PromotionPriority: 10},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}}, ReplayLsn: "1/24", PromotionPriority: -1},
+		}}
+		sort.Sort(&list)
+		Expect(list.Items[0].Pod.Name).To(Equal("server-3"))
+		Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+		Expect(list.Items[2].Pod.Name).To(Equal("server-1"))
+	})
+})
+
+var _ = Describe("long-running transactions", func() {
+	It("is empty when nothing has been detected", func() {
+		var transactions *LongRunningTransactions
+		Expect(transactions.IsEmpty()).To(BeTrue())
+		Expect((&LongRunningTransactions{OldestTransactionAge: 10, BlockingSessions: 0}).IsEmpty()).To(BeTrue())
+		Expect((&LongRunningTransactions{PreparedTransactions: 1}).IsEmpty()).To(BeFalse())
+		Expect((&LongRunningTransactions{BlockedSessions: 1, BlockingSessions: 1}).IsEmpty()).To(BeFalse())
+	})
+})
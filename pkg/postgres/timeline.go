@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TimelineHistoryEntry is a line of a timeline history file, telling the
+// position where the server switched from a timeline to the following one
+type TimelineHistoryEntry struct {
+	// The timeline that has been abandoned
+	TimelineID int `json:"timelineID"`
+
+	// The position where the timeline has been abandoned
+	SwitchPoint LSN `json:"switchPoint"`
+}
+
+// TimelineHistoryFileName gets the name of the history file of the passed
+// timeline
+func TimelineHistoryFileName(timelineID int) string {
+	return fmt.Sprintf("%08X.history", timelineID)
+}
+
+// ParseTimelineHistory parses the content of a timeline history file.
+// Each line contains the parent timeline, the switch point and a reason,
+// separated by whitespaces. Empty lines and comments are skipped
+func ParseTimelineHistory(content string) ([]TimelineHistoryEntry, error) {
+	var result []TimelineHistoryEntry
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("malformed timeline history line: %q", line)
+		}
+
+		timelineID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed timeline history line: %q: %w", line, err)
+		}
+
+		switchPoint := LSN(fields[1])
+		if _, err := switchPoint.Parse(); err != nil {
+			return nil, fmt.Errorf("malformed timeline history line: %q: %w", line, err)
+		}
+
+		result = append(result, TimelineHistoryEntry{
+			TimelineID:  timelineID,
+			SwitchPoint: switchPoint,
+		})
+	}
+
+	return result, scanner.Err()
+}
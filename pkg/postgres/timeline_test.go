@@ -0,0 +1,52 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("timeline history", func() {
+	It("generates the name of the history files", func() {
+		Expect(TimelineHistoryFileName(3)).To(Equal("00000003.history"))
+		Expect(TimelineHistoryFileName(26)).To(Equal("0000001A.history"))
+	})
+
+	It("parses a history file", func() {
+		history, err := ParseTimelineHistory(
+			"1\t0/3000060\tno recovery target specified\n\n" +
+				"# a comment\n" +
+				"2\t0/5000148\tno recovery target specified\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(Equal([]TimelineHistoryEntry{
+			{TimelineID: 1, SwitchPoint: "0/3000060"},
+			{TimelineID: 2, SwitchPoint: "0/5000148"},
+		}))
+	})
+
+	It("rejects malformed lines", func() {
+		_, err := ParseTimelineHistory("1\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTimelineHistory("one\t0/3000060\treason\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTimelineHistory("1\tnot-an-lsn\treason\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -137,3 +137,27 @@ func CanUpgrade(fromImage, toImage string) (bool, error) {
 
 	return IsUpgradePossible(fromVersion, toVersion), nil
 }
+
+// IsMajorVersionUpgrade checks if moving from one image to another
+// increases the PostgreSQL major version
+func IsMajorVersionUpgrade(fromImage, toImage string) (bool, error) {
+	fromTag := utils.GetImageTag(fromImage)
+	toTag := utils.GetImageTag(toImage)
+
+	if fromTag == "latest" || toTag == "latest" {
+		// We don't really know which major version "latest" is
+		return false, nil
+	}
+
+	fromVersion, err := GetPostgresVersionFromTag(fromTag)
+	if err != nil {
+		return false, err
+	}
+
+	toVersion, err := GetPostgresVersionFromTag(toTag)
+	if err != nil {
+		return false, err
+	}
+
+	return GetPostgresMajorVersion(toVersion) > GetPostgresMajorVersion(fromVersion), nil
+}
@@ -118,4 +118,25 @@ var _ = Describe("PostgreSQL version handling", func() {
 			Expect(status).To(BeFalse())
 		})
 	})
+
+	Describe("detect whenever an image change is a major version upgrade", func() {
+		It("succeed when the major version increases", func() {
+			Expect(IsMajorVersionUpgrade("postgres:14.5", "postgres:15.1")).To(BeTrue())
+			Expect(IsMajorVersionUpgrade("postgres:9.6.4", "postgres:10")).To(BeTrue())
+		})
+
+		It("fails when the major version is the same or decreases", func() {
+			Expect(IsMajorVersionUpgrade("postgres:15.0", "postgres:15.1")).To(BeFalse())
+			Expect(IsMajorVersionUpgrade("postgres:15.1", "postgres:14.5")).To(BeFalse())
+		})
+
+		It("prevent using 'latest'", func() {
+			Expect(IsMajorVersionUpgrade("postgres:latest", "postgres:15.1")).To(BeFalse())
+		})
+
+		It("raise errors when the image tag can't be parsed", func() {
+			_, err := IsMajorVersionUpgrade("postgres:14.5", "postgres:fifteen")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// CertManagerAPIGroup is the API group of the cert-manager resources
+	CertManagerAPIGroup = "cert-manager.io"
+
+	// CertManagerCertificateKind is the kind of the cert-manager Certificate resource
+	CertManagerCertificateKind = "Certificate"
+)
+
+// CertManagerCertificateGVK is the GroupVersionKind of the cert-manager
+// Certificate resource. The cert-manager types are not a dependency of the
+// operator, and the Certificates are handled as unstructured objects
+var CertManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   CertManagerAPIGroup,
+	Version: "v1",
+	Kind:    CertManagerCertificateKind,
+}
+
+// CreateCertManagerServerCertificate creates the Certificate issuing the
+// server TLS certificate of the cluster
+func CreateCertManagerServerCertificate(cluster apiv1.Cluster, altDNSNames []string) *unstructured.Unstructured {
+	return createCertManagerCertificate(
+		cluster,
+		cluster.GetServerTLSSecretName(),
+		cluster.GetServiceReadWriteName(),
+		altDNSNames,
+		"server auth",
+	)
+}
+
+// CreateCertManagerReplicationCertificate creates the Certificate issuing
+// the client certificate of the `streaming_replica` user
+func CreateCertManagerReplicationCertificate(cluster apiv1.Cluster) *unstructured.Unstructured {
+	return createCertManagerCertificate(
+		cluster,
+		cluster.GetReplicationSecretName(),
+		apiv1.StreamingReplicationUser,
+		nil,
+		"client auth",
+	)
+}
+
+// createCertManagerCertificate creates a Certificate writing the passed
+// secret. The secret is labelled to be watched by the operator, which
+// propagates the rotated certificates to the instances
+func createCertManagerCertificate(
+	cluster apiv1.Cluster,
+	secretName string,
+	commonName string,
+	dnsNames []string,
+	usage string,
+) *unstructured.Unstructured {
+	issuerRef := cluster.Spec.Certificates.CertManager.IssuerRef
+	issuerReference := map[string]interface{}{
+		"name": issuerRef.Name,
+	}
+	if issuerRef.Kind != "" {
+		issuerReference["kind"] = issuerRef.Kind
+	}
+	if issuerRef.Group != "" {
+		issuerReference["group"] = issuerRef.Group
+	}
+
+	isController := true
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetName(secretName)
+	certificate.SetNamespace(cluster.Namespace)
+	certificate.SetLabels(map[string]string{
+		utils.ClusterLabelName: cluster.Name,
+	})
+	certificate.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+			Controller: &isController,
+		},
+	})
+
+	spec := map[string]interface{}{
+		"secretName": secretName,
+		"commonName": commonName,
+		"usages": []interface{}{
+			"digital signature",
+			"key encipherment",
+			usage,
+		},
+		"privateKey": map[string]interface{}{
+			"algorithm":      "ECDSA",
+			"size":           int64(256),
+			"rotationPolicy": "Always",
+		},
+		"issuerRef": issuerReference,
+		"secretTemplate": map[string]interface{}{
+			"labels": map[string]interface{}{
+				utils.ClusterLabelName: cluster.Name,
+				WatchedLabelName:       "true",
+			},
+		},
+	}
+	if len(dnsNames) > 0 {
+		names := make([]interface{}, len(dnsNames))
+		for i, name := range dnsNames {
+			names[i] = name
+		}
+		spec["dnsNames"] = names
+	}
+	certificate.Object["spec"] = spec
+
+	return certificate
+}
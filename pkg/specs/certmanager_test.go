@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager certificates", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Certificates: &apiv1.CertificatesConfiguration{
+				CertManager: &apiv1.CertManagerConfiguration{
+					IssuerRef: apiv1.CertManagerIssuerReference{Name: "issuer", Kind: "ClusterIssuer"},
+				},
+			},
+		},
+	}
+
+	It("issues the server certificate for the services of the cluster", func() {
+		certificate := CreateCertManagerServerCertificate(cluster, []string{"cluster-example-rw"})
+		Expect(certificate.GroupVersionKind()).To(Equal(CertManagerCertificateGVK))
+		Expect(certificate.GetName()).To(Equal("cluster-example-server"))
+		Expect(certificate.GetOwnerReferences()).To(HaveLen(1))
+		Expect(certificate.GetOwnerReferences()[0].Kind).To(Equal(apiv1.ClusterKind))
+
+		spec := certificate.Object["spec"].(map[string]interface{})
+		Expect(spec["secretName"]).To(Equal("cluster-example-server"))
+		Expect(spec["commonName"]).To(Equal("cluster-example-rw"))
+		Expect(spec["dnsNames"]).To(Equal([]interface{}{"cluster-example-rw"}))
+		Expect(spec["usages"]).To(ContainElement("server auth"))
+		Expect(spec["issuerRef"]).To(Equal(map[string]interface{}{"name": "issuer", "kind": "ClusterIssuer"}))
+
+		labels, _, err := unstructured.NestedStringMap(certificate.Object, "spec", "secretTemplate", "labels")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(labels).To(HaveKeyWithValue(WatchedLabelName, "true"))
+		Expect(labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+	})
+
+	It("issues the client certificate of the streaming_replica user", func() {
+		certificate := CreateCertManagerReplicationCertificate(cluster)
+		Expect(certificate.GetName()).To(Equal("cluster-example-replication"))
+
+		spec := certificate.Object["spec"].(map[string]interface{})
+		Expect(spec["commonName"]).To(Equal(apiv1.StreamingReplicationUser))
+		Expect(spec["usages"]).To(ContainElement("client auth"))
+		Expect(spec).ToNot(HaveKey("dnsNames"))
+	})
+})
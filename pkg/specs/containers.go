@@ -29,18 +29,18 @@ import (
 
 // createBootstrapContainer creates the init container bootstrapping the operator
 // executable inside the generated Pods
-func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
+func createBootstrapContainer(cluster apiv1.Cluster, resources corev1.ResourceRequirements) corev1.Container {
 	container := corev1.Container{
 		Name:            BootstrapControllerContainerName,
-		Image:           configuration.Current.OperatorImageName,
-		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Image:           configuration.Current.GetOperatorImageName(),
+		ImagePullPolicy: cluster.GetImagePullPolicy(),
 		Command: []string{
 			"/manager",
 			"bootstrap",
 			"/controller/manager",
 		},
 		VolumeMounts:    createPostgresVolumeMounts(cluster),
-		Resources:       cluster.Spec.Resources,
+		Resources:       resources,
 		SecurityContext: CreateContainerSecurityContext(),
 	}
 
@@ -0,0 +1,213 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+const (
+	// ExtensionsHashAnnotationName is the name of the annotation containing
+	// the hash of the extensions configuration used to create a Pod
+	ExtensionsHashAnnotationName = MetadataNamespace + "/extensionsHash"
+
+	// extensionsShareVolumeName is the name of the volume containing the
+	// control and SQL files of the standard and of the additional extensions
+	extensionsShareVolumeName = "extensions-share"
+
+	// extensionsShareStagingDirectory is where the init container
+	// populating the extensions share volume mounts it
+	extensionsShareStagingDirectory = "/extensions-share"
+
+	// extensionsShareContainerName is the name of the init container
+	// populating the extensions share volume
+	extensionsShareContainerName = "extensions-share"
+)
+
+// getExtensionVolumeName gets the name of the volume containing an extension
+func getExtensionVolumeName(name string) string {
+	return "extension-" + name
+}
+
+// GetExtensionsHash gets the hash of the extensions configuration of the
+// cluster, or an empty string when there are no extensions
+func GetExtensionsHash(cluster apiv1.Cluster) (string, error) {
+	if len(cluster.Spec.PostgresConfiguration.Extensions) == 0 {
+		return "", nil
+	}
+
+	return hash.ComputeHash(cluster.Spec.PostgresConfiguration.Extensions)
+}
+
+// createExtensionsVolumes creates the volumes containing the extensions.
+// The extensions delivered by an image are copied into an empty directory
+func createExtensionsVolumes(cluster apiv1.Cluster) []corev1.Volume {
+	extensions := cluster.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	volumes := make([]corev1.Volume, 0, len(extensions)+1)
+	for _, extension := range extensions {
+		volumeSource := corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		if extension.VolumeSource != nil {
+			volumeSource = *extension.VolumeSource
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         getExtensionVolumeName(extension.Name),
+			VolumeSource: volumeSource,
+		})
+	}
+
+	if _, err := cluster.GetPostgresqlVersion(); err == nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: extensionsShareVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	return volumes
+}
+
+// createExtensionsVolumeMounts creates the volume mounts making the
+// extensions available to PostgreSQL. The control files cannot be loaded
+// from a different directory, and the extensions share volume replaces the
+// directory of the PostgreSQL installation containing them
+func createExtensionsVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
+	extensions := cluster.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	volumeMounts := make([]corev1.VolumeMount, 0, len(extensions)+1)
+	for _, extension := range extensions {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      getExtensionVolumeName(extension.Name),
+			MountPath: postgres.GetExtensionDirectory(extension.Name),
+			ReadOnly:  true,
+		})
+	}
+
+	if version, err := cluster.GetPostgresqlVersion(); err == nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      extensionsShareVolumeName,
+			MountPath: postgres.GetSharedExtensionsDirectory(version),
+			ReadOnly:  true,
+		})
+	}
+
+	return volumeMounts
+}
+
+// createExtensionsInitContainers creates the init containers copying the
+// extensions delivered by an image into their volumes, and then merging
+// the control and SQL files of the extensions with the ones of the
+// PostgreSQL installation, pointing their references to $libdir to the
+// library directory of each extension. They are meant to run after the bootstrap
+// controller, using the manager installed by it, with the resources of
+// the instance they are created for
+func createExtensionsInitContainers(
+	cluster apiv1.Cluster,
+	resources corev1.ResourceRequirements,
+) []corev1.Container {
+	extensions := cluster.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	controllerVolumeMount := corev1.VolumeMount{
+		Name:      "scratch-data",
+		MountPath: postgres.ScratchDataDirectory,
+	}
+
+	var containers []corev1.Container
+	for _, extension := range extensions {
+		if extension.Image == "" {
+			continue
+		}
+
+		container := corev1.Container{
+			Name:            getExtensionVolumeName(extension.Name),
+			Image:           configuration.Current.RewriteImageName(extension.Image),
+			ImagePullPolicy: cluster.GetImagePullPolicy(),
+			Command: []string{
+				"/controller/manager",
+				"extension",
+				"copy",
+				"--source", postgres.ExtensionImageDirectory,
+				"--target", postgres.GetExtensionDirectory(extension.Name),
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				controllerVolumeMount,
+				{
+					Name:      getExtensionVolumeName(extension.Name),
+					MountPath: postgres.GetExtensionDirectory(extension.Name),
+				},
+			},
+			Resources:       resources,
+			SecurityContext: CreateContainerSecurityContext(),
+		}
+		addManagerLoggingOptions(cluster, &container)
+		containers = append(containers, container)
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return containers
+	}
+
+	shareContainer := corev1.Container{
+		Name:            extensionsShareContainerName,
+		Image:           cluster.GetImageName(),
+		ImagePullPolicy: cluster.GetImagePullPolicy(),
+		Command: []string{
+			"/controller/manager",
+			"extension",
+			"copy",
+			"--source", postgres.GetSharedExtensionsDirectory(version),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			controllerVolumeMount,
+			{
+				Name:      extensionsShareVolumeName,
+				MountPath: extensionsShareStagingDirectory,
+			},
+		},
+		Resources:       resources,
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+	for _, extension := range extensions {
+		shareContainer.Command = append(shareContainer.Command,
+			"--extension", postgres.GetExtensionDirectory(extension.Name))
+		shareContainer.VolumeMounts = append(shareContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      getExtensionVolumeName(extension.Name),
+			MountPath: postgres.GetExtensionDirectory(extension.Name),
+			ReadOnly:  true,
+		})
+	}
+	shareContainer.Command = append(shareContainer.Command, "--target", extensionsShareStagingDirectory)
+	addManagerLoggingOptions(cluster, &shareContainer)
+
+	return append(containers, shareContainer)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extensions", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Extensions: []apiv1.ExtensionConfiguration{
+					{Name: "pgvector", Image: "pgvector:0.3.2"},
+					{Name: "postgis", VolumeSource: &corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "postgis"},
+					}},
+				},
+			},
+		},
+	}
+
+	It("doesn't change the Pods of the clusters without extensions", func() {
+		plainCluster := apiv1.Cluster{ObjectMeta: cluster.ObjectMeta}
+		pod := PodWithExistingStorage(plainCluster, 1)
+		Expect(pod.Spec.InitContainers).To(HaveLen(1))
+		Expect(pod.Annotations).ToNot(HaveKey(ExtensionsHashAnnotationName))
+	})
+
+	It("creates a volume for every extension", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		volumes := make(map[string]corev1.VolumeSource)
+		for _, volume := range pod.Spec.Volumes {
+			volumes[volume.Name] = volume.VolumeSource
+		}
+		Expect(volumes["extension-pgvector"].EmptyDir).ToNot(BeNil())
+		Expect(volumes["extension-postgis"].PersistentVolumeClaim.ClaimName).To(Equal("postgis"))
+		Expect(volumes[extensionsShareVolumeName].EmptyDir).ToNot(BeNil())
+	})
+
+	It("copies the extensions and merges their control files before starting PostgreSQL", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.InitContainers).To(HaveLen(3))
+		Expect(pod.Spec.InitContainers[0].Name).To(Equal(BootstrapControllerContainerName))
+
+		Expect(pod.Spec.InitContainers[1].Name).To(Equal("extension-pgvector"))
+		Expect(pod.Spec.InitContainers[1].Image).To(Equal("pgvector:0.3.2"))
+		Expect(pod.Spec.InitContainers[1].Command).To(ContainElements(
+			"--source", "/extension", "--target", "/extensions/pgvector"))
+
+		Expect(pod.Spec.InitContainers[2].Name).To(Equal(extensionsShareContainerName))
+		Expect(pod.Spec.InitContainers[2].Image).To(Equal(cluster.Spec.ImageName))
+		Expect(pod.Spec.InitContainers[2].Command).To(ContainElements(
+			"/usr/share/postgresql/15/extension",
+			"--extension", "/extensions/pgvector",
+			"--extension", "/extensions/postgis",
+			extensionsShareStagingDirectory))
+
+		Expect(pod.Annotations).To(HaveKey(ExtensionsHashAnnotationName))
+	})
+
+	It("gives the resources of the instance to the extensions containers", func() {
+		overriddenCluster := cluster.DeepCopy()
+		overriddenCluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		overriddenCluster.Spec.InstanceOverrides = []apiv1.InstanceOverride{{
+			Instances: []int{1},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+		}}
+
+		pod := PodWithExistingStorage(*overriddenCluster, 1)
+		Expect(pod.Spec.InitContainers[1].Resources.Limits.Memory().String()).To(Equal("4Gi"))
+		Expect(pod.Spec.InitContainers[2].Resources.Limits.Memory().String()).To(Equal("4Gi"))
+
+		pod = PodWithExistingStorage(*overriddenCluster, 2)
+		Expect(pod.Spec.InitContainers[1].Resources.Limits.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("mounts the extensions into the PostgreSQL container", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: "extension-pgvector", MountPath: "/extensions/pgvector", ReadOnly: true},
+			corev1.VolumeMount{Name: "extension-postgis", MountPath: "/extensions/postgis", ReadOnly: true},
+			corev1.VolumeMount{
+				Name:      extensionsShareVolumeName,
+				MountPath: "/usr/share/postgresql/15/extension",
+				ReadOnly:  true,
+			},
+		))
+	})
+})
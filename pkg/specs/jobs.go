@@ -34,6 +34,19 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// anonymizationSQLRefsFolder points to the folder of the anonymization
+	// SQL files in the primary job with recovery.
+	anonymizationSQLRefsFolder = "/etc/anonymization-sql"
+
+	// MajorUpgradeJobRole is the role of the job upgrading the data of the
+	// primary instance to a new PostgreSQL major version
+	MajorUpgradeJobRole = "major-upgrade"
+
+	// MajorUpgradeRecoveryJobRole is the role of the job completing or
+	// rolling back the upgrade of the data of the primary instance after
+	// the upgrade job failed
+	MajorUpgradeRecoveryJobRole = "major-upgrade-recovery"
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
@@ -123,6 +136,12 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.ICULocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
 	initCommand = append(
 		initCommand,
 		"--initdb-flags",
@@ -141,9 +160,26 @@ func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *
 
 	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
 
+	if cluster.ShouldRecoveryRunAnonymizationSQLRefs() {
+		initCommand = append(initCommand,
+			"--anonymization-sql-refs-folder", anonymizationSQLRefsFolder)
+	}
+
 	job := createPrimaryJob(cluster, nodeSerial, "full-recovery", initCommand)
 
 	addBarmanEndpointCAToJobFromCluster(cluster, backup, job)
+	addRecoveryPluginToJob(cluster, backup, cluster.GetInstanceName(nodeSerial), job)
+
+	if cluster.ShouldRecoveryRunAnonymizationSQLRefs() {
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(
+			cluster.Spec.Bootstrap.Recovery.Anonymization.SQLRefs,
+			anonymizationSQLRefsFolder,
+			"anonymization-sql",
+		)
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
+		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+			job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+	}
 
 	return job
 }
@@ -199,11 +235,85 @@ func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 	return createPrimaryJob(cluster, nodeSerial, "join", initCommand)
 }
 
+// CreateMajorUpgradeJob creates a job upgrading the data of an instance
+// to the PostgreSQL major version of the cluster image. The binaries of
+// the previous major version are copied from the source image by an
+// init container
+func CreateMajorUpgradeJob(cluster apiv1.Cluster, nodeSerial int, sourceImage string) *batchv1.Job {
+	upgradeCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"execute",
+		"--method", string(cluster.GetPgUpgradeMethod()),
+	}
+
+	// The new data directory needs the same settings of the old one
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		upgradeCommand = append(upgradeCommand, buildInitDBFlags(cluster)...)
+	}
+
+	upgradeCommand = append(upgradeCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, MajorUpgradeJobRole, upgradeCommand)
+
+	podSpec := &job.Spec.Template.Spec
+	prepareContainer := corev1.Container{
+		Name:            "prepare-upgrade",
+		Image:           sourceImage,
+		ImagePullPolicy: cluster.GetImagePullPolicy(),
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       podSpec.Containers[0].Resources,
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+	podSpec.InitContainers = append(podSpec.InitContainers, prepareContainer)
+
+	// The tail of the logs is reported in the cluster status when the
+	// upgrade fails, as the job is removed when rolling back
+	podSpec.Containers[0].TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	// A failed upgrade is rolled back instead of being retried
+	backoffLimit := int32(0)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	return job
+}
+
+// CreateMajorUpgradeRecoveryJob creates a job, using the image of the
+// new major version, that brings the data directory of the primary
+// instance back to a consistent state after the upgrade job failed
+func CreateMajorUpgradeRecoveryJob(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
+	recoverCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"recover",
+	}
+	recoverCommand = append(recoverCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, MajorUpgradeRecoveryJobRole, recoverCommand)
+
+	// The outcome of the recovery is reported in the termination message
+	job.Spec.Template.Spec.Containers[0].TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	backoffLimit := int32(2)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	return job
+}
+
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 	var flags []string
 
 	if cluster.ShouldCreateWalArchiveVolume() {
-		flags = append(flags, "--pg-wal", path.Join(pgWalVolumePath, "/pg_wal"))
+		flags = append(flags, "--pg-wal", path.Join(PgWalVolumePath, "/pg_wal"))
 	}
 
 	return flags
@@ -212,9 +322,16 @@ func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 // createPrimaryJob create a job that executes the provided command.
 // The role should describe the purpose of the executed job
 func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCommand []string) *batchv1.Job {
-	instanceName := GetInstanceName(cluster.Name, nodeSerial)
+	instanceName := cluster.GetInstanceName(nodeSerial)
 	jobName := GetJobName(cluster.Name, nodeSerial, role)
 
+	// Every job, except the one joining a new replica, creates a primary
+	overrideRole := apiv1.InstanceOverrideRolePrimary
+	if role == "join" {
+		overrideRole = apiv1.InstanceOverrideRoleReplica
+	}
+	resources := cluster.GetInstanceResources(nodeSerial, overrideRole)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
@@ -235,18 +352,18 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 				Spec: corev1.PodSpec{
 					Hostname:  jobName,
 					Subdomain: cluster.GetServiceAnyName(),
-					InitContainers: []corev1.Container{
-						createBootstrapContainer(cluster),
-					},
+					InitContainers: append(
+						[]corev1.Container{createBootstrapContainer(cluster, resources)},
+						createExtensionsInitContainers(cluster, resources)...),
 					Containers: []corev1.Container{
 						{
 							Name:            role,
 							Image:           cluster.GetImageName(),
-							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							ImagePullPolicy: cluster.GetImagePullPolicy(),
 							Env:             createEnvVarPostgresContainer(cluster, instanceName),
 							Command:         initCommand,
 							VolumeMounts:    createPostgresVolumeMounts(cluster),
-							Resources:       cluster.Spec.Resources,
+							Resources:       resources,
 							SecurityContext: CreateContainerSecurityContext(),
 						},
 					},
@@ -264,6 +381,8 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 
 	utils.LabelJobRole(&job.ObjectMeta, role)
 	utils.LabelClusterName(&job.ObjectMeta, cluster.Name)
+	excludeFromVeleroBackup(cluster, &job.ObjectMeta)
+	excludeFromVeleroBackup(cluster, &job.Spec.Template.ObjectMeta)
 	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
 	if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 		utils.AnnotateAppArmor(&job.ObjectMeta, cluster.Annotations)
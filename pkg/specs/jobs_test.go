@@ -164,4 +164,115 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitApplicationSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
+
+	It("contains the ICU locale options", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						WalSegmentSize: 32,
+						LocaleProvider: "icu",
+						ICULocale:      "en-US",
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(
+			ContainElement("--wal-segsize=32 --locale-provider=icu --icu-locale=en-US"))
+	})
+})
+
+var _ = Describe("Job created via recovery", func() {
+	It("mounts the anonymization SQL scripts", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Anonymization: &apiv1.RecoveryAnonymization{
+							Enabled: true,
+							SQLRefs: &apiv1.PostInitApplicationSQLRefs{
+								ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+									{
+										Key: "mask.sql",
+										LocalObjectReference: apiv1.LocalObjectReference{
+											Name: "masking",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaRecovery(cluster, 1, nil)
+		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(anonymizationSQLRefsFolder))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).Should(ContainElement(corev1.VolumeMount{
+			Name:      "0-anonymization-sql",
+			MountPath: anonymizationSQLRefsFolder + "/0.sql",
+			SubPath:   "0.sql",
+			ReadOnly:  true,
+		}))
+	})
+
+	It("doesn't mount anything when the anonymization is disabled", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Anonymization: &apiv1.RecoveryAnonymization{
+							SQLRefs: &apiv1.PostInitApplicationSQLRefs{
+								ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+									{
+										Key: "mask.sql",
+										LocalObjectReference: apiv1.LocalObjectReference{
+											Name: "masking",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaRecovery(cluster, 1, nil)
+		Expect(job.Spec.Template.Spec.Containers[0].Command).ShouldNot(ContainElement(anonymizationSQLRefsFolder))
+	})
+})
+
+var _ = Describe("Major version upgrade job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:15.1",
+			MajorVersionUpgrade: &apiv1.MajorVersionUpgradeConfiguration{
+				Method: apiv1.PgUpgradeMethodCopy,
+			},
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Encoding: "UTF8",
+				},
+			},
+		},
+	}
+
+	It("copies the old binaries from the source image and runs pg_upgrade in the new one", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "postgres:14.5")
+		Expect(job.Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+
+		podSpec := job.Spec.Template.Spec
+		prepareContainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+		Expect(prepareContainer.Image).To(Equal("postgres:14.5"))
+		Expect(prepareContainer.Command).To(ContainElements("upgrade", "prepare"))
+
+		Expect(podSpec.Containers[0].Image).To(Equal("postgres:15.1"))
+		Expect(podSpec.Containers[0].Command).To(ContainElements("upgrade", "execute", "copy", "--initdb-flags"))
+		Expect(podSpec.Containers[0].TerminationMessagePolicy).To(Equal(corev1.TerminationMessageFallbackToLogsOnError))
+	})
 })
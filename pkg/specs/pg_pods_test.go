@@ -17,6 +17,8 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -43,3 +45,64 @@ var _ = Describe("Extract the used image name", func() {
 		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
 	})
 })
+
+var _ = Describe("Instance overrides", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterName",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			InstanceOverrides: []apiv1.InstanceOverride{
+				{
+					Instances: []int{3},
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+					},
+				},
+			},
+		},
+	}
+
+	It("uses the resources of the cluster for the other instances", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(cluster.Spec.Resources))
+		Expect(pod.Spec.InitContainers[0].Resources).To(Equal(cluster.Spec.Resources))
+		Expect(pod.Annotations).To(HaveKeyWithValue(InstanceOverrideRoleAnnotationName, "replica"))
+	})
+
+	It("uses the overridden resources for the selected instances", func() {
+		pod := PodWithExistingStorage(cluster, 3)
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(*cluster.Spec.InstanceOverrides[0].Resources))
+		Expect(pod.Spec.InitContainers[0].Resources).To(Equal(*cluster.Spec.InstanceOverrides[0].Resources))
+	})
+
+	It("uses the overridden resources in the jobs", func() {
+		job := JoinReplicaInstance(cluster, 3)
+		Expect(job.Spec.Template.Spec.Containers[0].Resources).To(Equal(*cluster.Spec.InstanceOverrides[0].Resources))
+	})
+})
+
+var _ = Describe("Streaming readiness gate", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+
+	It("is not added by default", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.ReadinessGates).To(BeEmpty())
+	})
+
+	It("is added when enabled", func() {
+		clusterWithGate := cluster.DeepCopy()
+		clusterWithGate.Spec.EnableStreamingReadinessGate = true
+		pod := PodWithExistingStorage(*clusterWithGate, 1)
+		Expect(pod.Spec.ReadinessGates).To(ConsistOf(corev1.PodReadinessGate{ConditionType: StreamingConditionType}))
+	})
+})
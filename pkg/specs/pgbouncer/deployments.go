@@ -75,7 +75,7 @@ func Deployment(pooler *apiv1.Pooler,
 			},
 		}).
 		WithSecurityContext(specs.CreatePodSecurityContext(998, 996), true).
-		WithContainerImage("pgbouncer", DefaultPgbouncerImage, false).
+		WithContainerImage("pgbouncer", config.Current.RewriteImageName(DefaultPgbouncerImage), false).
 		WithContainerCommand("pgbouncer", []string{
 			"/controller/manager",
 			"pgbouncer",
@@ -89,7 +89,7 @@ func Deployment(pooler *apiv1.Pooler,
 			Name:          "metrics",
 			ContainerPort: int32(url.PgBouncerMetricsPort),
 		}).
-		WithInitContainerImage(specs.BootstrapControllerContainerName, config.Current.OperatorImageName, true).
+		WithInitContainerImage(specs.BootstrapControllerContainerName, config.Current.GetOperatorImageName(), true).
 		WithInitContainerCommand(specs.BootstrapControllerContainerName,
 			[]string{"/manager", "bootstrap", "/controller/manager"},
 			true).
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// ScaledObjectGVK is the GroupVersionKind of the KEDA ScaledObject
+var ScaledObjectGVK = schema.GroupVersionKind{
+	Group:   "keda.sh",
+	Version: "v1alpha1",
+	Kind:    "ScaledObject",
+}
+
+// ScaledObject create a new KEDA ScaledObject scaling the pooler through
+// its scale subresource, depending on the client connections reported by
+// the PgBouncer instances via the metrics service. The queried endpoint
+// sums the client connections of all the instances, and the `AverageValue`
+// metric type makes KEDA divide them by the target of each instance
+func ScaledObject(pooler *apiv1.Pooler) *unstructured.Unstructured {
+	autoscaling := pooler.Spec.Autoscaling
+	saturationURL := fmt.Sprintf("http://%s.%s.svc:%d%s",
+		pooler.GetMetricsServiceName(), pooler.Namespace, url.PgBouncerMetricsPort, url.PathPgBouncerPoolerSaturation)
+
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(ScaledObjectGVK)
+	scaledObject.SetName(pooler.Name)
+	scaledObject.SetNamespace(pooler.Namespace)
+	scaledObject.SetLabels(map[string]string{
+		PgbouncerNameLabel: pooler.Name,
+	})
+	scaledObject.Object["spec"] = map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": apiv1.GroupVersion.String(),
+			"kind":       apiv1.PoolerKind,
+			"name":       pooler.Name,
+		},
+		"minReplicaCount": int64(autoscaling.GetMinInstances()),
+		"maxReplicaCount": int64(autoscaling.MaxInstances),
+		"triggers": []interface{}{
+			map[string]interface{}{
+				"type":       "metrics-api",
+				"metricType": "AverageValue",
+				"metadata": map[string]interface{}{
+					"url":           saturationURL,
+					"format":        "json",
+					"valueLocation": "clientConnections",
+					"targetValue":   strconv.Itoa(int(autoscaling.GetTargetClientConnections())),
+				},
+			},
+		},
+	}
+
+	return scaledObject
+}
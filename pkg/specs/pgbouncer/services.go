@@ -23,6 +23,8 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // Service create the specification for the service of
@@ -49,3 +51,35 @@ func Service(pooler *apiv1.Pooler) *corev1.Service {
 		},
 	}
 }
+
+// MetricsService create the specification for the headless service
+// exposing the metrics exporter of every pgbouncer instance, to be
+// scraped by a ServiceMonitor
+func MetricsService(pooler *apiv1.Pooler) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pooler.GetMetricsServiceName(),
+			Namespace: pooler.Namespace,
+			Labels: map[string]string{
+				PgbouncerNameLabel:            pooler.Name,
+				utils.MetricsServiceLabelName: "true",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromString("metrics"),
+					Port:       int32(url.PgBouncerMetricsPort),
+				},
+			},
+			Selector: map[string]string{
+				PgbouncerNameLabel: pooler.Name,
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+// PluginsHashAnnotationName is the name of the annotation containing
+// the hash of the plugins configuration used to create a Pod
+const PluginsHashAnnotationName = MetadataNamespace + "/pluginsHash"
+
+// GetPluginsHash gets the hash of the plugins configuration of the
+// cluster, or an empty string when there are no plugins
+func GetPluginsHash(cluster apiv1.Cluster) (string, error) {
+	if len(cluster.Spec.Plugins) == 0 {
+		return "", nil
+	}
+
+	return hash.ComputeHash(cluster.Spec.Plugins)
+}
+
+// createPluginsVolumes creates the volume where the plugins create
+// their unix sockets
+func createPluginsVolumes(cluster apiv1.Cluster) []corev1.Volume {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil
+	}
+
+	return []corev1.Volume{
+		{
+			Name: plugin.SocketVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+}
+
+// createPluginsVolumeMounts creates the volume mount giving access to
+// the unix sockets of the plugins
+func createPluginsVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      plugin.SocketVolumeName,
+			MountPath: plugin.SocketDirectory,
+		},
+	}
+}
+
+// createPluginContainer creates the sidecar container running a plugin.
+// The plugin shares the volumes of PostgreSQL, so that it can copy the
+// data files and the WAL files, and connect to the instance through its
+// unix socket
+func createPluginContainer(
+	cluster apiv1.Cluster,
+	configuration apiv1.PluginConfiguration,
+	podName string,
+) corev1.Container {
+	env := append(
+		createEnvVarPostgresContainer(cluster, podName),
+		corev1.EnvVar{
+			Name:  plugin.SocketEnvName,
+			Value: plugin.SocketPath(configuration.Name),
+		})
+	env = append(env, configuration.Env...)
+
+	return corev1.Container{
+		Name:            plugin.ContainerName(configuration.Name),
+		Image:           configuration.Image,
+		ImagePullPolicy: cluster.GetImagePullPolicy(),
+		Env:             env,
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+}
+
+// createPluginContainers creates the sidecar containers of every plugin
+// of the cluster
+func createPluginContainers(cluster apiv1.Cluster, podName string) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(cluster.Spec.Plugins))
+	for _, configuration := range cluster.Spec.Plugins {
+		containers = append(containers, createPluginContainer(cluster, configuration, podName))
+	}
+	return containers
+}
+
+// getRecoveryPluginName gets the name of the plugin storing the backup
+// to recover from, or an empty string when the recovery doesn't use a
+// plugin
+func getRecoveryPluginName(cluster apiv1.Cluster, backup *apiv1.Backup) string {
+	if backup != nil && backup.IsPlugin() {
+		return backup.Status.PluginName
+	}
+
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return ""
+	}
+
+	externalCluster, ok := cluster.ExternalCluster(cluster.Spec.Bootstrap.Recovery.Source)
+	if !ok || externalCluster.PluginConfiguration == nil {
+		return ""
+	}
+
+	return externalCluster.PluginConfiguration.Name
+}
+
+// addRecoveryPluginToJob adds to the recovery job the sidecar of the
+// plugin storing the backup to recover from. The instance manager asks
+// the plugin to terminate at the end of the recovery, letting the job
+// complete
+func addRecoveryPluginToJob(
+	cluster apiv1.Cluster,
+	backup *apiv1.Backup,
+	instanceName string,
+	job *batchv1.Job,
+) {
+	configuration, ok := cluster.GetPlugin(getRecoveryPluginName(cluster, backup))
+	if !ok {
+		return
+	}
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Containers = append(podSpec.Containers, createPluginContainer(cluster, *configuration, instanceName))
+}
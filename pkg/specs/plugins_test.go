@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugins", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Plugins: []apiv1.PluginConfiguration{
+				{
+					Name:  "pgbackrest",
+					Image: "pgbackrest:latest",
+					Env:   []corev1.EnvVar{{Name: "PGBACKREST_LOG_LEVEL", Value: "info"}},
+				},
+			},
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name:                "origin",
+					PluginConfiguration: &apiv1.BackupPluginConfiguration{Name: "pgbackrest"},
+				},
+			},
+		},
+	}
+
+	It("doesn't change the Pods when there are no plugins", func() {
+		plainCluster := apiv1.Cluster{ObjectMeta: cluster.ObjectMeta}
+		pod := PodWithExistingStorage(plainCluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "plugins")))
+		Expect(pod.Annotations).ToNot(HaveKey(PluginsHashAnnotationName))
+	})
+
+	It("runs the plugins as sidecars of the instances", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Annotations).To(HaveKey(PluginsHashAnnotationName))
+		Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", "plugins")))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "plugins",
+			MountPath: "/plugins",
+		}))
+
+		sidecar := pod.Spec.Containers[1]
+		Expect(sidecar.Name).To(Equal("plugin-pgbackrest"))
+		Expect(sidecar.Image).To(Equal("pgbackrest:latest"))
+		Expect(sidecar.Env).To(ContainElements(
+			corev1.EnvVar{Name: "PGDATA", Value: PgDataPath},
+			corev1.EnvVar{Name: "POD_NAME", Value: "cluster-example-1"},
+			corev1.EnvVar{Name: "PLUGIN_SOCKET", Value: "/plugins/pgbackrest.sock"},
+			corev1.EnvVar{Name: "PGBACKREST_LOG_LEVEL", Value: "info"},
+		))
+		Expect(sidecar.VolumeMounts).To(Equal(pod.Spec.Containers[0].VolumeMounts))
+	})
+
+	It("changes the hash of the Pods when the plugins change", func() {
+		hash, err := GetPluginsHash(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		changedCluster := cluster.DeepCopy()
+		changedCluster.Spec.Plugins[0].Image = "pgbackrest:2.50"
+		changedHash, err := GetPluginsHash(*changedCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changedHash).ToNot(Equal(hash))
+	})
+
+	It("adds the plugin to the recovery job only when it stores the backup", func() {
+		recoveryCluster := cluster.DeepCopy()
+		recoveryCluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+			Recovery: &apiv1.BootstrapRecovery{Source: "origin"},
+		}
+		job := CreatePrimaryJobViaRecovery(*recoveryCluster, 1, nil)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(2))
+		Expect(job.Spec.Template.Spec.Containers[1].Name).To(Equal("plugin-pgbackrest"))
+
+		backup := &apiv1.Backup{Spec: apiv1.BackupSpec{Method: apiv1.BackupMethodBarmanObjectStore}}
+		recoveryCluster.Spec.Bootstrap.Recovery = &apiv1.BootstrapRecovery{
+			Backup: &apiv1.BackupSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "backup"}},
+		}
+		job = CreatePrimaryJobViaRecovery(*recoveryCluster, 1, backup)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+		backup.Spec.Method = apiv1.BackupMethodPlugin
+		backup.Status.PluginName = "pgbackrest"
+		job = CreatePrimaryJobViaRecovery(*recoveryCluster, 1, backup)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+		job = JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+	})
+})
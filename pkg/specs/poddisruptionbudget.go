@@ -28,12 +28,22 @@ import (
 // BuildReplicasPodDisruptionBudget creates a pod disruption budget telling
 // K8s to avoid removing more than one replica at a time
 func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisruptionBudget {
+	if cluster == nil {
+		return nil
+	}
+
 	// We should ensure that in a cluster of n instances,
-	// with n-1 replicas, at least n-2 are always available
-	if cluster == nil || cluster.Spec.Instances < 3 {
+	// with n-1 replicas, at least n-2 are always available.
+	// The restricted replicas have their own role label and
+	// are not covered by this budget
+	instances := cluster.Spec.Instances
+	if cluster.Spec.RestrictedReplicas != nil {
+		instances -= len(cluster.Spec.RestrictedReplicas.Instances)
+	}
+	if instances < 3 {
 		return nil
 	}
-	minAvailableReplicas := cluster.Spec.Instances - 2
+	minAvailableReplicas := instances - 2
 	allReplicasButOne := intstr.FromInt(minAvailableReplicas)
 
 	return &policyv1.PodDisruptionBudget{
@@ -49,6 +49,17 @@ var _ = Describe("POD Disruption Budget specifications", func() {
 		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailableReplicas)))
 	})
 
+	It("do not count the restricted replicas", func() {
+		restrictedCluster := cluster.DeepCopy()
+		restrictedCluster.Spec.Instances = 4
+		restrictedCluster.Spec.RestrictedReplicas = &apiv1.RestrictedReplicasConfiguration{Instances: []int{4}}
+		result := BuildReplicasPodDisruptionBudget(restrictedCluster)
+		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailableReplicas)))
+
+		restrictedCluster.Spec.RestrictedReplicas.Instances = []int{3, 4}
+		Expect(BuildReplicasPodDisruptionBudget(restrictedCluster)).To(BeNil())
+	})
+
 	It("require at least one primary instance to be available at all times", func() {
 		result := BuildPrimaryPodDisruptionBudget(cluster)
 		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailablePrimary)))
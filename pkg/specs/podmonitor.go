@@ -18,7 +18,9 @@ package specs
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -48,3 +50,102 @@ func CreatePodMonitor(cluster *apiv1.Cluster) *monitoringv1.PodMonitor {
 		Spec:       spec,
 	}
 }
+
+// CreateServiceMonitor create a new servicemonitor for cluster, scraping
+// the instances through the metrics service
+func CreateServiceMonitor(cluster *apiv1.Cluster) *monitoringv1.ServiceMonitor {
+	meta := metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	utils.LabelClusterName(&meta, cluster.Name)
+
+	spec := monitoringv1.ServiceMonitorSpec{
+		Selector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				utils.ClusterLabelName:        cluster.Name,
+				utils.MetricsServiceLabelName: "true",
+			},
+		},
+		Endpoints: []monitoringv1.Endpoint{
+			{
+				Port: "metrics",
+			},
+		},
+	}
+
+	return &monitoringv1.ServiceMonitor{
+		ObjectMeta: meta,
+		Spec:       spec,
+	}
+}
+
+const (
+	// OperatorServiceMonitorName is the name of the servicemonitor scraping
+	// the metrics of the operator, and of the service it uses
+	OperatorServiceMonitorName = "cnpg-controller-manager"
+
+	// OperatorMetricsPort is the port where the operator exports its
+	// metrics, as in the default deployment
+	OperatorMetricsPort = 8080
+
+	// operatorNameLabelValue is the value of the "app.kubernetes.io/name"
+	// label set on the operator pods
+	operatorNameLabelValue = "cloudnative-pg"
+)
+
+// CreateOperatorMetricsService create the headless service exposing the
+// metrics of the operator installed in the passed namespace, which is
+// used by the servicemonitor of the operator
+func CreateOperatorMetricsService(namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      OperatorServiceMonitorName + "-metrics",
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      operatorNameLabelValue,
+				utils.MetricsServiceLabelName: "true",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: corev1.ClusterIPNone,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromString("metrics"),
+					Port:       OperatorMetricsPort,
+				},
+			},
+			Selector: map[string]string{
+				"app.kubernetes.io/name": operatorNameLabelValue,
+			},
+		},
+	}
+}
+
+// CreateOperatorServiceMonitor create a new servicemonitor for the operator
+// installed in the passed namespace, scraping it through the service
+// created by CreateOperatorMetricsService
+func CreateOperatorServiceMonitor(namespace string) *monitoringv1.ServiceMonitor {
+	return &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      OperatorServiceMonitorName,
+			Labels: map[string]string{
+				"app.kubernetes.io/name": operatorNameLabelValue,
+			},
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: CreateOperatorMetricsService(namespace).Labels,
+			},
+			Endpoints: []monitoringv1.Endpoint{
+				{
+					Port: "metrics",
+				},
+			},
+		},
+	}
+}
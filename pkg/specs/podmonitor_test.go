@@ -44,4 +44,32 @@ var _ = Describe("PodMonitor test", func() {
 		Expect(monitor.Spec.Selector.MatchLabels[utils.ClusterLabelName]).To(Equal(clusterName))
 		Expect(monitor.Spec.PodMetricsEndpoints).To(ContainElement(monitoringv1.PodMetricsEndpoint{Port: "metrics"}))
 	})
+	It("should create a servicemonitor selecting the metrics service", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "test",
+			},
+		}
+		monitor := CreateServiceMonitor(&cluster)
+		Expect(monitor.Name).To(Equal("test"))
+		Expect(monitor.Labels[utils.ClusterLabelName]).To(Equal("test"))
+		Expect(monitor.Spec.Selector.MatchLabels).To(Equal(
+			CreateClusterMetricsService(cluster).Labels,
+		))
+		Expect(monitor.Spec.Endpoints).To(ContainElement(monitoringv1.Endpoint{Port: "metrics"}))
+	})
+	It("should create a servicemonitor for the operator", func() {
+		service := CreateOperatorMetricsService("cnpg-system")
+		Expect(service.Namespace).To(Equal("cnpg-system"))
+		Expect(service.Spec.Selector).To(HaveKeyWithValue("app.kubernetes.io/name", "cloudnative-pg"))
+		Expect(service.Spec.Ports).To(HaveLen(1))
+		Expect(service.Spec.Ports[0].Name).To(Equal("metrics"))
+
+		monitor := CreateOperatorServiceMonitor("cnpg-system")
+		Expect(monitor.Name).To(Equal(OperatorServiceMonitorName))
+		Expect(monitor.Namespace).To(Equal("cnpg-system"))
+		Expect(monitor.Spec.Selector.MatchLabels).To(Equal(service.Labels))
+		Expect(monitor.Spec.Endpoints).To(ContainElement(monitoringv1.Endpoint{Port: "metrics"}))
+	})
 })
@@ -19,7 +19,6 @@ limitations under the License.
 package specs
 
 import (
-	"fmt"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -40,6 +39,11 @@ const (
 	// serial number of the node
 	ClusterSerialAnnotationName = MetadataNamespace + "/nodeSerial"
 
+	// InstanceOverrideRoleAnnotationName is the name of the annotation
+	// containing the role used to select the instance overrides when the
+	// Pod or the PVC was created
+	InstanceOverrideRoleAnnotationName = MetadataNamespace + "/instanceOverrideRole"
+
 	// ClusterRestartAnnotationName is the name of the annotation containing the
 	// latest required restart time
 	ClusterRestartAnnotationName = "kubectl.kubernetes.io/restartedAt"
@@ -48,6 +52,11 @@ const (
 	// latest required restart time
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// StreamingConditionType is the type of the Pod condition, managed by
+	// the operator, telling whether the instance is the primary or a replica
+	// streaming from it
+	StreamingConditionType corev1.PodConditionType = MetadataNamespace + "/streaming"
+
 	// ClusterRoleLabelName label is applied to Pods to mark primary ones
 	ClusterRoleLabelName = "role"
 
@@ -57,6 +66,10 @@ const (
 	// ClusterRoleLabelReplica is written in labels to represent replica servers
 	ClusterRoleLabelReplica = "replica"
 
+	// ClusterRoleLabelRestricted is written in labels to represent replica
+	// servers that can never be promoted
+	ClusterRoleLabelRestricted = "restricted"
+
 	// WatchedLabelName label is for Secrets or ConfigMaps that needs to be reloaded
 	WatchedLabelName = MetadataNamespace + "/reload"
 
@@ -125,12 +138,13 @@ func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []core
 func createPostgresContainers(
 	cluster apiv1.Cluster,
 	podName string,
+	resources corev1.ResourceRequirements,
 ) []corev1.Container {
 	containers := []corev1.Container{
 		{
 			Name:            PostgresContainerName,
 			Image:           cluster.GetImageName(),
-			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			ImagePullPolicy: cluster.GetImagePullPolicy(),
 			Env:             createEnvVarPostgresContainer(cluster, podName),
 			VolumeMounts:    createPostgresVolumeMounts(cluster),
 			ReadinessProbe: &corev1.Probe{
@@ -163,7 +177,7 @@ func createPostgresContainers(
 				"instance",
 				"run",
 			},
-			Resources: cluster.Spec.Resources,
+			Resources: resources,
 			Ports: []corev1.ContainerPort{
 				{
 					Name:          "postgresql",
@@ -299,8 +313,10 @@ func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
 
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
-	podName := GetInstanceName(cluster.Name, nodeSerial)
+	podName := cluster.GetInstanceName(nodeSerial)
 	gracePeriod := int64(cluster.GetMaxStopDelay())
+	overrideRole := cluster.GetInstanceRole(podName)
+	resources := cluster.GetInstanceResources(nodeSerial, overrideRole)
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -311,7 +327,8 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 				utils.PodRoleLabelName:      string(utils.PodRoleInstance),
 			},
 			Annotations: map[string]string{
-				ClusterSerialAnnotationName: strconv.Itoa(nodeSerial),
+				ClusterSerialAnnotationName:        strconv.Itoa(nodeSerial),
+				InstanceOverrideRoleAnnotationName: string(overrideRole),
 			},
 			Name:      podName,
 			Namespace: cluster.Namespace,
@@ -319,10 +336,12 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		Spec: corev1.PodSpec{
 			Hostname:  podName,
 			Subdomain: cluster.GetServiceAnyName(),
-			InitContainers: []corev1.Container{
-				createBootstrapContainer(cluster),
-			},
-			Containers:                    createPostgresContainers(cluster, podName),
+			InitContainers: append(
+				[]corev1.Container{createBootstrapContainer(cluster, resources)},
+				createExtensionsInitContainers(cluster, resources)...),
+			Containers: append(
+				createPostgresContainers(cluster, podName, resources),
+				createPluginContainers(cluster, podName)...),
 			Volumes:                       createPostgresVolumes(cluster, podName),
 			SecurityContext:               CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
 			Affinity:                      CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
@@ -333,17 +352,28 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		},
 	}
 
+	if cluster.Spec.EnableStreamingReadinessGate {
+		pod.Spec.ReadinessGates = []corev1.PodReadinessGate{
+			{ConditionType: StreamingConditionType},
+		}
+	}
+
+	if extensionsHash, err := GetExtensionsHash(cluster); err == nil && extensionsHash != "" {
+		pod.Annotations[ExtensionsHashAnnotationName] = extensionsHash
+	}
+
+	if pluginsHash, err := GetPluginsHash(cluster); err == nil && pluginsHash != "" {
+		pod.Annotations[PluginsHashAnnotationName] = pluginsHash
+	}
+
+	addVeleroBackupHooks(cluster, &pod.ObjectMeta)
+
 	if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 		utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 	}
 	return pod
 }
 
-// GetInstanceName returns a string indicating the instance name
-func GetInstanceName(clusterName string, nodeSerial int) string {
-	return fmt.Sprintf("%s-%v", clusterName, nodeSerial)
-}
-
 // AddBarmanEndpointCAToPodSpec adds the required volumes and env variables needed by barman to work correctly
 func AddBarmanEndpointCAToPodSpec(
 	podSpec *corev1.PodSpec,
@@ -18,6 +18,7 @@ package specs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -25,7 +26,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -45,6 +50,11 @@ const (
 
 	// PVCStatusDetached is the annotation value for PVC detached status
 	PVCStatusDetached = "detached"
+
+	// PVCVolumeAttributesClassAnnotationName is the annotation recording the
+	// VolumeAttributesClass applied to the PVC. The field is not part of the
+	// PVC API known to the operator, which cannot read it back
+	PVCVolumeAttributesClassAnnotationName = MetadataNamespace + "/volumeAttributesClassName"
 )
 
 // ErrorInvalidSize is raised when the size specified by the
@@ -81,7 +91,7 @@ func CreatePVC(
 	nodeSerial int,
 	role utils.PVCRole,
 ) (*corev1.PersistentVolumeClaim, error) {
-	instanceName := GetInstanceName(cluster.Name, nodeSerial)
+	instanceName := cluster.GetInstanceName(nodeSerial)
 	pvcName := GetPVCName(cluster, instanceName, role)
 
 	result := &corev1.PersistentVolumeClaim{
@@ -133,9 +143,68 @@ func CreatePVC(
 		return nil, ErrorInvalidSize
 	}
 
+	if storageConfiguration.VolumeAttributesClassName != nil {
+		result.Annotations[PVCVolumeAttributesClassAnnotationName] = *storageConfiguration.VolumeAttributesClassName
+	}
+
+	return result, nil
+}
+
+// PVCWithVolumeAttributesClass gets the object to be used to create the
+// passed PVC. When a VolumeAttributesClass is requested, the PVC is
+// converted to an unstructured object carrying it in its specification
+func PVCWithVolumeAttributesClass(pvc *corev1.PersistentVolumeClaim) (client.Object, error) {
+	className, ok := pvc.Annotations[PVCVolumeAttributesClassAnnotationName]
+	if !ok {
+		return pvc, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pvc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &unstructured.Unstructured{Object: content}
+	result.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+	if err := unstructured.SetNestedField(result.Object, className, "spec", "volumeAttributesClassName"); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// VolumeAttributesClassPatch builds the merge patch applying the passed
+// VolumeAttributesClass to an existing PVC, recording it in the annotations
+func VolumeAttributesClassPatch(className string) client.Patch {
+	content, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				PVCVolumeAttributesClassAnnotationName: className,
+			},
+		},
+		"spec": map[string]interface{}{
+			"volumeAttributesClassName": className,
+		},
+	})
+	return client.RawPatch(types.MergePatchType, content)
+}
+
+// CreateTablespacePVC create spec of the PVC storing the passed tablespace
+func CreateTablespacePVC(
+	cluster apiv1.Cluster,
+	nodeSerial int,
+	tablespace apiv1.TablespaceConfiguration,
+) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := CreatePVC(tablespace.Storage, cluster, nodeSerial, utils.PVCRolePgTablespace)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc.Name = GetTablespacePVCName(cluster.GetInstanceName(nodeSerial), tablespace.Name)
+	pvc.Labels[utils.TablespaceNameLabelName] = tablespace.Name
+	return pvc, nil
+}
+
 // GetPVCName builds the name for a given PVC of the instance
 func GetPVCName(cluster apiv1.Cluster, instanceName string, role utils.PVCRole) string {
 	pvcName := instanceName
@@ -145,6 +214,12 @@ func GetPVCName(cluster apiv1.Cluster, instanceName string, role utils.PVCRole)
 	return pvcName
 }
 
+// GetTablespacePVCName builds the name of the PVC storing the passed
+// tablespace for the instance
+func GetTablespacePVCName(instanceName string, tablespaceName string) string {
+	return instanceName + "-" + VolumeNameForTablespace(tablespaceName)
+}
+
 // FilterInstancePVCs returns all the corev1.PersistentVolumeClaim that are used inside the podSpec
 func FilterInstancePVCs(
 	pvcs []corev1.PersistentVolumeClaim,
@@ -212,7 +287,7 @@ func DetectPVCs(
 	// and detect if there is an attached Pod or Job
 instancesLoop:
 	for serial, pvcs := range instances {
-		instanceName := fmt.Sprintf("%s-%v", cluster.Name, serial)
+		instanceName := cluster.GetInstanceName(serial)
 		expectedPVCs := getExpectedInstancePVCNames(cluster, instanceName)
 		pvcNames := getNamesFromPVCList(pvcs)
 
@@ -342,6 +417,10 @@ func getExpectedInstancePVCNames(cluster *apiv1.Cluster, instanceName string) []
 		names = append(names, instanceName+cluster.GetWalArchiveVolumeSuffix())
 	}
 
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		names = append(names, GetTablespacePVCName(instanceName, tablespace.Name))
+	}
+
 	return names
 }
 
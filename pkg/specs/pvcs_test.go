@@ -23,6 +23,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -169,4 +171,86 @@ var _ = Describe("PVC Creation", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
 	})
+
+	It("passes through the fields of the template", func() {
+		pvc, err := CreatePVC(
+			apiv1.StorageConfiguration{
+				Size: "1Gi",
+				PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod},
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"topology.kubernetes.io/zone": "eu-west-1a"},
+					},
+					DataSource: &corev1.TypedLocalObjectReference{
+						APIGroup: pointer.String("snapshot.storage.k8s.io"),
+						Kind:     "VolumeSnapshot",
+						Name:     "seed",
+					},
+				},
+			},
+			apiv1.Cluster{},
+			0,
+			utils.PVCRolePgData,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOncePod))
+		Expect(pvc.Spec.Selector.MatchLabels).To(HaveKeyWithValue("topology.kubernetes.io/zone", "eu-west-1a"))
+		Expect(pvc.Spec.DataSource.Name).To(Equal("seed"))
+	})
+
+	It("sets the VolumeAttributesClass of the PVC", func() {
+		pvc, err := CreatePVC(
+			apiv1.StorageConfiguration{
+				Size:                      "1Gi",
+				VolumeAttributesClassName: pointer.String("gold"),
+			},
+			apiv1.Cluster{},
+			0,
+			utils.PVCRolePgData,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvc.Annotations).To(HaveKeyWithValue(PVCVolumeAttributesClassAnnotationName, "gold"))
+
+		object, err := PVCWithVolumeAttributesClass(pvc)
+		Expect(err).NotTo(HaveOccurred())
+		content := object.(*unstructured.Unstructured).Object
+		className, found, err := unstructured.NestedString(content, "spec", "volumeAttributesClassName")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(className).To(Equal("gold"))
+		Expect(object.GetObjectKind().GroupVersionKind().Kind).To(Equal("PersistentVolumeClaim"))
+	})
+
+	It("creates the PVC as is without a VolumeAttributesClass", func() {
+		pvc, err := CreatePVC(apiv1.StorageConfiguration{Size: "1Gi"}, apiv1.Cluster{}, 0, utils.PVCRolePgData)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(PVCWithVolumeAttributesClass(pvc)).To(BeIdenticalTo(pvc))
+	})
+
+	It("creates the PVCs of the tablespaces", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+		pvc, err := CreateTablespacePVC(cluster, 1, apiv1.TablespaceConfiguration{
+			Name:    "fast_disk",
+			Storage: apiv1.StorageConfiguration{Size: "1Gi", StorageClass: &storageClass},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvc.Name).To(Equal("cluster-example-1-tbs-fast-disk"))
+		Expect(pvc.Labels).To(HaveKeyWithValue(utils.PvcRoleLabelName, string(utils.PVCRolePgTablespace)))
+		Expect(pvc.Labels).To(HaveKeyWithValue(utils.TablespaceNameLabelName, "fast_disk"))
+		Expect(*pvc.Spec.StorageClassName).To(Equal(storageClass))
+	})
+
+	It("expects the PVCs of the tablespaces for every instance", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{{Name: "fast_disk"}},
+			},
+		}
+		Expect(DoesPVCBelongToInstance(cluster, "cluster-example-1", "cluster-example-1-tbs-fast-disk")).
+			To(BeTrue())
+		Expect(DoesPVCBelongToInstance(cluster, "cluster-example-1", "cluster-example-2-tbs-fast-disk")).
+			To(BeFalse())
+	})
 })
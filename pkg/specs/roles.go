@@ -54,6 +54,10 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 
+	if cluster.Spec.FailoverWitness != nil && cluster.Spec.FailoverWitness.KubeconfigSecret != nil {
+		involvedSecretNames = append(involvedSecretNames, cluster.Spec.FailoverWitness.KubeconfigSecret.Name)
+	}
+
 	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -152,6 +156,78 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"patch",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications",
+				"subscriptions",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications/status",
+				"subscriptions/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+	}
+
+	// The instance manager updates the role label of its own Pod on
+	// promotion and demotion. The rule is restricted to the known instances,
+	// as a rule without resource names would apply to every Pod
+	if len(cluster.Status.InstanceNames) > 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+			},
+			Verbs: []string{
+				"patch",
+			},
+			ResourceNames: cluster.Status.InstanceNames,
+		})
+	}
+
+	// The primary instance renews the failover witness lease. When the
+	// witness is kept in a different Kubernetes cluster, the permissions
+	// come from the configured kubeconfig
+	if cluster.Spec.FailoverWitness != nil && cluster.Spec.FailoverWitness.KubeconfigSecret == nil {
+		rules = append(rules, failoverWitnessRules(cluster.Spec.FailoverWitness.LeaseName)...)
+	}
+
+	// The primary instance snapshots its own volumes when taking a backup
+	// with the volumeSnapshot method
+	if cluster.Spec.Backup.IsVolumeSnapshotBackupConfigured() {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{
+				VolumeSnapshotAPIGroup,
+			},
+			Resources: []string{
+				"volumesnapshots",
+			},
+			Verbs: []string{
+				"create",
+				"get",
+				"list",
+				"watch",
+			},
+		})
 	}
 
 	return rbacv1.Role{
@@ -163,6 +239,39 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
+// failoverWitnessRules returns the rules needed to renew the passed lease.
+// The "create" verb cannot be restricted by resource name
+func failoverWitnessRules(leaseName string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"coordination.k8s.io",
+			},
+			Resources: []string{
+				"leases",
+			},
+			Verbs: []string{
+				"get",
+				"update",
+			},
+			ResourceNames: []string{
+				leaseName,
+			},
+		},
+		{
+			APIGroups: []string{
+				"coordination.k8s.io",
+			},
+			Resources: []string{
+				"leases",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
+	}
+}
+
 func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
@@ -196,6 +305,9 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 			if barmanObjStore.EndpointCA != nil {
 				result = append(result, barmanObjStore.EndpointCA.Name)
 			}
+			if barmanObjStore.Encryption != nil {
+				result = append(result, barmanObjStore.Encryption.KeysSecret.Name)
+			}
 		}
 	}
 
@@ -216,6 +328,11 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 		result = append(
 			result,
 			googleCredentialsSecrets(cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google)...)
+		if cluster.Spec.Backup.BarmanObjectStore.Encryption != nil {
+			result = append(
+				result,
+				cluster.Spec.Backup.BarmanObjectStore.Encryption.KeysSecret.Name)
+		}
 	}
 
 	// Secrets needed by Barman, if set
@@ -235,6 +352,11 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 		result = append(
 			result,
 			googleCredentialsSecrets(backupOrigin.Status.BarmanCredentials.Google)...)
+		if backupOrigin.Status.ClientSideEncryption != nil {
+			result = append(
+				result,
+				backupOrigin.Status.ClientSideEncryption.KeysSecret.Name)
+		}
 	}
 
 	return result
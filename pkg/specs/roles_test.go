@@ -154,7 +154,63 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(len(serviceAccount.Rules)).To(Equal(7))
+		Expect(len(serviceAccount.Rules)).To(Equal(9))
+	})
+
+	It("allows the instance manager to patch only the Pods of the known instances", func() {
+		clusterWithInstances := cluster.DeepCopy()
+		clusterWithInstances.Status.InstanceNames = []string{"thisTest-1", "thisTest-2"}
+		role := CreateRole(*clusterWithInstances, nil)
+		Expect(role.Rules).To(HaveLen(10))
+		podsRule := role.Rules[9]
+		Expect(podsRule.Resources).To(Equal([]string{"pods"}))
+		Expect(podsRule.Verbs).To(Equal([]string{"patch"}))
+		Expect(podsRule.ResourceNames).To(ConsistOf("thisTest-1", "thisTest-2"))
+	})
+
+	It("allows the instance manager to renew a local failover witness lease", func() {
+		clusterWithWitness := cluster.DeepCopy()
+		clusterWithWitness.Spec.FailoverWitness = &apiv1.FailoverWitnessConfiguration{LeaseName: "witness"}
+		role := CreateRole(*clusterWithWitness, nil)
+		Expect(role.Rules).To(HaveLen(11))
+		Expect(role.Rules[9].Resources).To(Equal([]string{"leases"}))
+		Expect(role.Rules[9].Verbs).To(ConsistOf("get", "update"))
+		Expect(role.Rules[9].ResourceNames).To(Equal([]string{"witness"}))
+		Expect(role.Rules[10].Verbs).To(Equal([]string{"create"}))
+	})
+
+	It("allows the instance manager to read the kubeconfig of a remote failover witness", func() {
+		clusterWithWitness := cluster.DeepCopy()
+		clusterWithWitness.Spec.FailoverWitness = &apiv1.FailoverWitnessConfiguration{
+			LeaseName: "witness",
+			KubeconfigSecret: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "witness-kubeconfig"},
+				Key:                  "kubeconfig",
+			},
+		}
+		role := CreateRole(*clusterWithWitness, nil)
+		Expect(role.Rules).To(HaveLen(9))
+		Expect(role.Rules[1].ResourceNames).To(ContainElement("witness-kubeconfig"))
+	})
+
+	It("allows the instance manager to snapshot the volumes when configured", func() {
+		clusterWithSnapshots := cluster.DeepCopy()
+		clusterWithSnapshots.Spec.Backup = &apiv1.BackupConfiguration{
+			VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{ClassName: "csi-snapclass"},
+		}
+		role := CreateRole(*clusterWithSnapshots, nil)
+		Expect(role.Rules).To(HaveLen(10))
+		Expect(role.Rules[9].APIGroups).To(Equal([]string{VolumeSnapshotAPIGroup}))
+		Expect(role.Rules[9].Resources).To(Equal([]string{"volumesnapshots"}))
+		Expect(role.Rules[9].Verbs).To(ConsistOf("create", "get", "list", "watch"))
+	})
+
+	It("allows the instance manager to reconcile the publications and the subscriptions", func() {
+		role := CreateRole(cluster, nil)
+		Expect(role.Rules[7].Resources).To(ConsistOf("publications", "subscriptions"))
+		Expect(role.Rules[7].Verbs).To(ConsistOf("get", "list", "watch"))
+		Expect(role.Rules[8].Resources).To(ConsistOf("publications/status", "subscriptions/status"))
+		Expect(role.Rules[8].Verbs).To(ConsistOf("get", "patch", "update"))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
@@ -219,4 +275,29 @@ var _ = Describe("Secrets", func() {
 		secrets = backupSecrets(cluster, nil)
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-endpoint-ca-name"))
 	})
+
+	It("include the client-side encryption keys", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+					},
+					Encryption: &apiv1.ClientSideEncryptionConfiguration{
+						KeysSecret: apiv1.LocalObjectReference{Name: "test-keys"},
+						ActiveKey:  "current",
+					},
+				},
+			},
+		}
+		backupOrigin := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				ClientSideEncryption: &apiv1.ClientSideEncryptionConfiguration{
+					KeysSecret: apiv1.LocalObjectReference{Name: "test-origin-keys"},
+					ActiveKey:  "current",
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, backupOrigin)).To(ConsistOf("test-keys", "test-origin-keys"))
+	})
 })
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -46,6 +47,8 @@ func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 		},
 		Spec: corev1.ServiceSpec{
 			Type:                     corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy:           cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:               cluster.GetServicesIPFamilies(),
 			PublishNotReadyAddresses: true,
 			Ports:                    buildInstanceServicePorts(),
 			Selector: map[string]string{
@@ -63,8 +66,10 @@ func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:     cluster.GetServicesIPFamilies(),
+			Ports:          buildInstanceServicePorts(),
 			Selector: map[string]string{
 				utils.ClusterLabelName: cluster.Name,
 			},
@@ -72,19 +77,102 @@ func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
 	}
 }
 
-// CreateClusterReadOnlyService create a service insisting on all the ready pods
+// CreateClusterReadOnlyService create a service insisting on all the ready replicas
 func CreateClusterReadOnlyService(cluster apiv1.Cluster) *corev1.Service {
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.GetServiceReadOnlyName(),
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:     cluster.GetServicesIPFamilies(),
+			Ports:          buildInstanceServicePorts(),
+			Selector:       BuildReadOnlyServiceSelector(cluster, false),
+		},
+	}
+
+	if cluster.GetReadOnlyServiceSelectorPolicy() == apiv1.ReadOnlyServiceSelectorPolicySessionPinned {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	}
+
+	return service
+}
+
+// BuildReadOnlyServiceSelector creates the selector of the `-ro` service,
+// that points to the primary when the replicas cannot be used and the
+// selector policy allows it
+func BuildReadOnlyServiceSelector(cluster apiv1.Cluster, fallbackToPrimary bool) map[string]string {
+	if fallbackToPrimary &&
+		cluster.GetReadOnlyServiceSelectorPolicy() == apiv1.ReadOnlyServiceSelectorPolicyReplicasWithPrimaryFallback {
+		return map[string]string{
+			utils.ClusterLabelName: cluster.Name,
+			ClusterRoleLabelName:   ClusterRoleLabelPrimary,
+		}
+	}
+
+	selector := map[string]string{
+		utils.ClusterLabelName: cluster.Name,
+		ClusterRoleLabelName:   ClusterRoleLabelReplica,
+	}
+	if cluster.ShouldExcludeDelayedReplicas() {
+		selector[utils.ReadOnlyServiceMemberLabelName] = "true"
+	}
+
+	return selector
+}
+
+// CreateClusterRestrictedService create a service insisting on the ready
+// restricted replicas
+func CreateClusterRestrictedService(cluster apiv1.Cluster) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetServiceRestrictedName(),
+			Namespace: cluster.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:     cluster.GetServicesIPFamilies(),
+			Ports:          buildInstanceServicePorts(),
+			Selector: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+				ClusterRoleLabelName:   ClusterRoleLabelRestricted,
+			},
+		},
+	}
+}
+
+// CreateClusterMetricsService create a headless service insisting on all
+// the pods, exposing the metrics exporter of every instance to be scraped
+// by a ServiceMonitor
+func CreateClusterMetricsService(cluster apiv1.Cluster) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetServiceMetricsName(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:        cluster.Name,
+				utils.MetricsServiceLabelName: "true",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			IPFamilyPolicy:           cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:               cluster.GetServicesIPFamilies(),
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromString("metrics"),
+					Port:       int32(url.PostgresMetricsPort),
+				},
+			},
 			Selector: map[string]string{
 				utils.ClusterLabelName: cluster.Name,
-				ClusterRoleLabelName:   ClusterRoleLabelReplica,
 			},
 		},
 	}
@@ -98,8 +186,10 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: cluster.GetServicesIPFamilyPolicy(),
+			IPFamilies:     cluster.GetServicesIPFamilies(),
+			Ports:          buildInstanceServicePorts(),
 			Selector: map[string]string{
 				utils.ClusterLabelName: cluster.Name,
 				ClusterRoleLabelName:   ClusterRoleLabelPrimary,
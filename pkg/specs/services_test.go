@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -55,6 +56,53 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelReplica))
 	})
 
+	It("pins the sessions of the -ro service when requested", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				ReadOnly: &apiv1.ReadOnlyServiceConfiguration{
+					SelectorPolicy: apiv1.ReadOnlyServiceSelectorPolicySessionPinned,
+				},
+			},
+		}
+		service := CreateClusterReadOnlyService(*cluster)
+		Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelReplica))
+	})
+
+	It("falls back to the primary only when the policy allows it", func() {
+		Expect(BuildReadOnlyServiceSelector(postgresql, true)[ClusterRoleLabelName]).
+			To(Equal(ClusterRoleLabelReplica))
+
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				ReadOnly: &apiv1.ReadOnlyServiceConfiguration{
+					SelectorPolicy: apiv1.ReadOnlyServiceSelectorPolicyReplicasWithPrimaryFallback,
+				},
+			},
+		}
+		Expect(BuildReadOnlyServiceSelector(*cluster, false)[ClusterRoleLabelName]).
+			To(Equal(ClusterRoleLabelReplica))
+		Expect(BuildReadOnlyServiceSelector(*cluster, true)[ClusterRoleLabelName]).
+			To(Equal(ClusterRoleLabelPrimary))
+	})
+
+	It("selects only the members of the -ro service when excluding delayed replicas", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				ReadOnly: &apiv1.ReadOnlyServiceConfiguration{
+					ExcludeDelayedReplicas: true,
+				},
+			},
+		}
+		Expect(BuildReadOnlyServiceSelector(*cluster, false)).
+			To(HaveKeyWithValue(utils.ReadOnlyServiceMemberLabelName, "true"))
+		Expect(BuildReadOnlyServiceSelector(postgresql, false)).
+			ToNot(HaveKey(utils.ReadOnlyServiceMemberLabelName))
+	})
+
 	It("create a configured -rw service", func() {
 		service := CreateClusterReadWriteService(postgresql)
 		Expect(service.Name).To(Equal("clustername-rw"))
@@ -62,4 +110,44 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
+	It("create a configured -restricted service", func() {
+		service := CreateClusterRestrictedService(postgresql)
+		Expect(service.Name).To(Equal("clustername-restricted"))
+		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelRestricted))
+	})
+	It("create a configured headless -metrics service", func() {
+		service := CreateClusterMetricsService(postgresql)
+		Expect(service.Name).To(Equal("clustername-metrics"))
+		Expect(service.Labels[utils.MetricsServiceLabelName]).To(Equal("true"))
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(service.Spec.Selector).To(Equal(map[string]string{utils.ClusterLabelName: "clustername"}))
+		Expect(service.Spec.Ports).To(HaveLen(1))
+		Expect(service.Spec.Ports[0].Name).To(Equal("metrics"))
+	})
+	It("applies the IP family configuration to every service", func() {
+		dualStack := corev1.IPFamilyPolicyPreferDualStack
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				IPFamilyPolicy: &dualStack,
+				IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			},
+		}
+		for _, service := range []*corev1.Service{
+			CreateClusterAnyService(*cluster),
+			CreateClusterReadService(*cluster),
+			CreateClusterReadOnlyService(*cluster),
+			CreateClusterReadWriteService(*cluster),
+			CreateClusterRestrictedService(*cluster),
+		} {
+			Expect(service.Spec.IPFamilyPolicy).To(Equal(&dualStack))
+			Expect(service.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}))
+		}
+
+		service := CreateClusterReadWriteService(postgresql)
+		Expect(service.Spec.IPFamilyPolicy).To(BeNil())
+		Expect(service.Spec.IPFamilies).To(BeEmpty())
+	})
 })
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// VeleroPreBackupContainerAnnotationName is the annotation telling
+	// Velero in which container the pre-backup hook is run
+	VeleroPreBackupContainerAnnotationName = "pre.hook.backup.velero.io/container"
+
+	// VeleroPreBackupCommandAnnotationName is the annotation containing
+	// the command of the Velero pre-backup hook
+	VeleroPreBackupCommandAnnotationName = "pre.hook.backup.velero.io/command"
+
+	// VeleroPreBackupTimeoutAnnotationName is the annotation containing
+	// the timeout of the Velero pre-backup hook
+	VeleroPreBackupTimeoutAnnotationName = "pre.hook.backup.velero.io/timeout"
+
+	// VeleroPostBackupContainerAnnotationName is the annotation telling
+	// Velero in which container the post-backup hook is run
+	VeleroPostBackupContainerAnnotationName = "post.hook.backup.velero.io/container"
+
+	// VeleroPostBackupCommandAnnotationName is the annotation containing
+	// the command of the Velero post-backup hook
+	VeleroPostBackupCommandAnnotationName = "post.hook.backup.velero.io/command"
+
+	// VeleroExcludeFromBackupLabelName is the label excluding an object
+	// from the Velero backups
+	VeleroExcludeFromBackupLabelName = "velero.io/exclude-from-backup"
+
+	// veleroHookTimeoutMargin is the time given to the pre-backup hook to
+	// request the checkpoint, in addition to the time needed to shut down
+	// a fenced replica
+	veleroHookTimeoutMargin = 60
+)
+
+// addVeleroBackupHooks annotates the Pod of an instance with the Velero
+// hooks preparing it to be backed up
+func addVeleroBackupHooks(cluster apiv1.Cluster, meta *metav1.ObjectMeta) {
+	if !cluster.IsVeleroEnabled() {
+		return
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[VeleroPreBackupContainerAnnotationName] = PostgresContainerName
+	meta.Annotations[VeleroPreBackupCommandAnnotationName] =
+		`["/controller/manager", "velero", "pre-backup"]`
+	meta.Annotations[VeleroPreBackupTimeoutAnnotationName] =
+		fmt.Sprintf("%ds", cluster.GetMaxStopDelay()+veleroHookTimeoutMargin)
+	meta.Annotations[VeleroPostBackupContainerAnnotationName] = PostgresContainerName
+	meta.Annotations[VeleroPostBackupCommandAnnotationName] =
+		`["/controller/manager", "velero", "post-backup"]`
+}
+
+// excludeFromVeleroBackup labels an object so that it is not backed up
+// by Velero, as the operator recreates it when needed
+func excludeFromVeleroBackup(cluster apiv1.Cluster, meta *metav1.ObjectMeta) {
+	if !cluster.IsVeleroEnabled() {
+		return
+	}
+
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	meta.Labels[VeleroExcludeFromBackupLabelName] = "true"
+}
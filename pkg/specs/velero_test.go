@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Velero integration", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				Velero: &apiv1.VeleroConfiguration{},
+			},
+		},
+	}
+
+	It("doesn't change the Pods and the Jobs when Velero is not enabled", func() {
+		plainCluster := apiv1.Cluster{ObjectMeta: cluster.ObjectMeta}
+		pod := PodWithExistingStorage(plainCluster, 1)
+		Expect(pod.Annotations).ToNot(HaveKey(VeleroPreBackupCommandAnnotationName))
+		job := JoinReplicaInstance(plainCluster, 2)
+		Expect(job.Labels).ToNot(HaveKey(VeleroExcludeFromBackupLabelName))
+	})
+
+	It("annotates the Pods with the backup hooks", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(VeleroPreBackupContainerAnnotationName, PostgresContainerName))
+		Expect(pod.Annotations).To(HaveKeyWithValue(VeleroPreBackupCommandAnnotationName,
+			`["/controller/manager", "velero", "pre-backup"]`))
+		Expect(pod.Annotations).To(HaveKeyWithValue(VeleroPreBackupTimeoutAnnotationName, "90s"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(VeleroPostBackupCommandAnnotationName,
+			`["/controller/manager", "velero", "post-backup"]`))
+		Expect(pod.Labels).ToNot(HaveKey(VeleroExcludeFromBackupLabelName))
+	})
+
+	It("excludes the Jobs from the backups", func() {
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Labels).To(HaveKeyWithValue(VeleroExcludeFromBackupLabelName, "true"))
+		Expect(job.Spec.Template.Labels).To(HaveKeyWithValue(VeleroExcludeFromBackupLabelName, "true"))
+	})
+})
@@ -18,6 +18,8 @@ package specs
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -25,8 +27,19 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
-// pgWalVolumePath its the path used by the WAL volume when present
-const pgWalVolumePath = "/var/lib/postgresql/wal"
+// PgWalVolumePath its the path used by the WAL volume when present
+const PgWalVolumePath = "/var/lib/postgresql/wal"
+
+// tablespacesVolumesPath is the path where the volumes of the tablespaces
+// are mounted, each of them in a directory named after the tablespace
+const tablespacesVolumesPath = "/var/lib/postgresql/tablespaces"
+
+// InstanceHooksVolumeName is the name of the volume containing the
+// scripts of the instance hooks
+const InstanceHooksVolumeName = "instance-hooks"
+
+// instanceHooksFileMode makes the scripts of the instance hooks executable
+var instanceHooksFileMode int32 = 0o555
 
 func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volume {
 	result := []corev1.Volume{
@@ -92,11 +105,52 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 			})
 	}
 
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		result = append(result,
+			corev1.Volume{
+				Name: VolumeNameForTablespace(tablespace.Name),
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: GetTablespacePVCName(podName, tablespace.Name),
+					},
+				},
+			})
+	}
+
+	if hooks := cluster.Spec.InstanceHooks; hooks != nil && hooks.ScriptsConfigMap != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: InstanceHooksVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: hooks.ScriptsConfigMap.Name,
+						},
+						DefaultMode: &instanceHooksFileMode,
+					},
+				},
+			})
+	}
+
+	result = append(result, createExtensionsVolumes(cluster)...)
+	result = append(result, createPluginsVolumes(cluster)...)
+
 	return result
 }
 
 func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 	refs *apiv1.PostInitApplicationSQLRefs,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	return createVolumesAndVolumeMountsForSQLRefs(refs, postInitApplicationSQLRefsFolder, "post-init-application-sql")
+}
+
+// createVolumesAndVolumeMountsForSQLRefs creates the volumes mounting the
+// passed SQL scripts into the given folder, naming them so that they are
+// executed in order
+func createVolumesAndVolumeMountsForSQLRefs(
+	refs *apiv1.PostInitApplicationSQLRefs,
+	folder string,
+	volumeSuffix string,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
 	length := len(refs.ConfigMapRefs) + len(refs.SecretRefs)
 	digitsCount := len(fmt.Sprintf("%d", length))
@@ -105,7 +159,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.SecretRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					SecretName: refs.SecretRefs[i].Name,
@@ -120,8 +174,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i),
 			ReadOnly:  true,
 		})
@@ -129,7 +183,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.ConfigMapRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -146,8 +200,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i+len(refs.SecretRefs)),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i+len(refs.SecretRefs)),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i+len(refs.SecretRefs)),
 			ReadOnly:  true,
 		})
@@ -198,10 +252,51 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
 				Name:      "pg-wal",
-				MountPath: pgWalVolumePath,
+				MountPath: PgWalVolumePath,
+			},
+		)
+	}
+
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      VolumeNameForTablespace(tablespace.Name),
+				MountPath: MountForTablespace(tablespace.Name),
+			},
+		)
+	}
+
+	if hooks := cluster.Spec.InstanceHooks; hooks != nil && hooks.ScriptsConfigMap != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      InstanceHooksVolumeName,
+				MountPath: apiv1.InstanceHooksMountPath,
+				ReadOnly:  true,
 			},
 		)
 	}
 
+	volumeMounts = append(volumeMounts, createExtensionsVolumeMounts(cluster)...)
+	volumeMounts = append(volumeMounts, createPluginsVolumeMounts(cluster)...)
+
 	return volumeMounts
 }
+
+// VolumeNameForTablespace returns the name of the Pod volume storing the
+// passed tablespace. Underscores are not allowed in volume names
+func VolumeNameForTablespace(tablespaceName string) string {
+	return "tbs-" + strings.ReplaceAll(tablespaceName, "_", "-")
+}
+
+// MountForTablespace returns the path where the volume of the passed
+// tablespace is mounted
+func MountForTablespace(tablespaceName string) string {
+	return path.Join(tablespacesVolumesPath, tablespaceName)
+}
+
+// LocationForTablespace returns the location of the passed tablespace. A
+// subdirectory of the mount point is used, as PostgreSQL requires the
+// location to be empty while the root of the volume may not be
+func LocationForTablespace(tablespaceName string) string {
+	return path.Join(MountForTablespace(tablespaceName), "data")
+}
@@ -290,3 +290,30 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("tablespaces volumes", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Tablespaces: []apiv1.TablespaceConfiguration{{Name: "fast_disk"}},
+		},
+	}
+
+	It("mounts a PVC for every tablespace", func() {
+		Expect(createPostgresVolumes(cluster, "cluster-example-1")).To(ContainElement(corev1.Volume{
+			Name: "tbs-fast-disk",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "cluster-example-1-tbs-fast-disk",
+				},
+			},
+		}))
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      "tbs-fast-disk",
+			MountPath: "/var/lib/postgresql/tablespaces/fast_disk",
+		}))
+	})
+
+	It("locates the tablespaces in a directory of their volume", func() {
+		Expect(LocationForTablespace("fast_disk")).To(Equal("/var/lib/postgresql/tablespaces/fast_disk/data"))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// VolumeSnapshotAPIGroup is the API group of the VolumeSnapshot resource
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+
+	// VolumeSnapshotKind is the kind of the VolumeSnapshot resource
+	VolumeSnapshotKind = "VolumeSnapshot"
+
+	// BackupNameLabelName is the name of the label put on the volume
+	// snapshots to identify the backup they belong to
+	BackupNameLabelName = MetadataNamespace + "/backupName"
+)
+
+// VolumeSnapshotGVK is the GroupVersionKind of the VolumeSnapshot resource.
+// The external-snapshotter types are not a dependency of the operator, and
+// the VolumeSnapshots are handled as unstructured objects
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   VolumeSnapshotAPIGroup,
+	Version: "v1",
+	Kind:    VolumeSnapshotKind,
+}
+
+// GetVolumeSnapshotName builds the name of the snapshot of the volume with
+// the passed role taken for a backup
+func GetVolumeSnapshotName(backup apiv1.Backup, role utils.PVCRole) string {
+	if role == utils.PVCRolePgWal {
+		return backup.Name + "-wal"
+	}
+	return backup.Name
+}
+
+// GetTablespaceVolumeSnapshotName builds the name of the snapshot of the
+// volume storing the passed tablespace taken for a backup
+func GetTablespaceVolumeSnapshotName(backup apiv1.Backup, tablespaceName string) string {
+	return backup.Name + "-" + VolumeNameForTablespace(tablespaceName)
+}
+
+// CreateVolumeSnapshot creates the spec of the snapshot of the passed PVC,
+// owned by the backup it belongs to
+func CreateVolumeSnapshot(
+	backup apiv1.Backup,
+	pvcName string,
+	role utils.PVCRole,
+	className string,
+) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetName(GetVolumeSnapshotName(backup, role))
+	snapshot.SetNamespace(backup.Namespace)
+	snapshot.SetLabels(map[string]string{
+		utils.ClusterLabelName: backup.Spec.Cluster.Name,
+		BackupNameLabelName:    backup.Name,
+		utils.PvcRoleLabelName: string(role),
+	})
+	snapshot.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.BackupKind,
+			Name:       backup.Name,
+			UID:        backup.UID,
+		},
+	})
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+	snapshot.Object["spec"] = spec
+
+	return snapshot
+}
+
+// CreateTablespaceVolumeSnapshot creates the spec of the snapshot of the
+// passed PVC storing a tablespace, owned by the backup it belongs to
+func CreateTablespaceVolumeSnapshot(
+	backup apiv1.Backup,
+	pvcName string,
+	tablespaceName string,
+	className string,
+) *unstructured.Unstructured {
+	snapshot := CreateVolumeSnapshot(backup, pvcName, utils.PVCRolePgTablespace, className)
+	snapshot.SetName(GetTablespaceVolumeSnapshotName(backup, tablespaceName))
+
+	labels := snapshot.GetLabels()
+	labels[utils.TablespaceNameLabelName] = tablespaceName
+	snapshot.SetLabels(labels)
+
+	return snapshot
+}
+
+// VolumeSnapshotStatus is the progress of a VolumeSnapshot
+type VolumeSnapshotStatus struct {
+	// Taken is true when the point-in-time copy of the volume has been cut,
+	// and the volume can be written again without altering the snapshot
+	Taken bool
+
+	// Ready is true when the snapshot can be used to create a volume
+	Ready bool
+}
+
+// GetVolumeSnapshotStatus returns the progress of the passed VolumeSnapshot,
+// or an error if the snapshot failed
+func GetVolumeSnapshotStatus(snapshot *unstructured.Unstructured) (VolumeSnapshotStatus, error) {
+	var status VolumeSnapshotStatus
+
+	message, found, err := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	if err != nil {
+		return status, err
+	}
+	if found {
+		return status, fmt.Errorf("volume snapshot %s failed: %s", snapshot.GetName(), message)
+	}
+
+	creationTime, _, err := unstructured.NestedString(snapshot.Object, "status", "creationTime")
+	if err != nil {
+		return status, err
+	}
+	status.Taken = creationTime != ""
+
+	if status.Ready, _, err = unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); err != nil {
+		return status, err
+	}
+	// A snapshot can't be used before being taken
+	status.Taken = status.Taken || status.Ready
+
+	return status, nil
+}
+
+// GetVolumeSnapshotDataSource returns the data source to be used to create a
+// PVC from the passed VolumeSnapshot
+func GetVolumeSnapshotDataSource(snapshotName string) *corev1.TypedLocalObjectReference {
+	apiGroup := VolumeSnapshotAPIGroup
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     VolumeSnapshotKind,
+		Name:     snapshotName,
+	}
+}
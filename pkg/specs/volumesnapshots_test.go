@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Volume snapshots", func() {
+	backup := apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-example",
+			Namespace: "default",
+			UID:       "backup-uid",
+		},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+			Method:  apiv1.BackupMethodVolumeSnapshot,
+		},
+	}
+
+	It("are created from the PVC of the instance and owned by the backup", func() {
+		snapshot := CreateVolumeSnapshot(backup, "cluster-example-1-wal", utils.PVCRolePgWal, "csi-snapclass")
+		Expect(snapshot.GroupVersionKind()).To(Equal(VolumeSnapshotGVK))
+		Expect(snapshot.GetName()).To(Equal("backup-example-wal"))
+		Expect(snapshot.GetNamespace()).To(Equal("default"))
+		Expect(snapshot.GetLabels()).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+		Expect(snapshot.GetLabels()).To(HaveKeyWithValue(BackupNameLabelName, "backup-example"))
+		Expect(snapshot.GetOwnerReferences()).To(HaveLen(1))
+		Expect(snapshot.GetOwnerReferences()[0].Kind).To(Equal(apiv1.BackupKind))
+		Expect(snapshot.GetOwnerReferences()[0].UID).To(BeEquivalentTo("backup-uid"))
+
+		pvcName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		Expect(pvcName).To(Equal("cluster-example-1-wal"))
+		className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		Expect(className).To(Equal("csi-snapclass"))
+	})
+
+	It("use the default snapshot class when none is specified", func() {
+		snapshot := CreateVolumeSnapshot(backup, "cluster-example-1", utils.PVCRolePgData, "")
+		Expect(snapshot.GetName()).To(Equal("backup-example"))
+		_, found, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		Expect(found).To(BeFalse())
+	})
+
+	It("report their progress", func() {
+		snapshot := CreateVolumeSnapshot(backup, "cluster-example-1", utils.PVCRolePgData, "")
+		status, err := GetVolumeSnapshotStatus(snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(VolumeSnapshotStatus{}))
+
+		snapshot.Object["status"] = map[string]interface{}{
+			"creationTime": "2023-01-01T00:00:00Z",
+			"readyToUse":   false,
+		}
+		status, err = GetVolumeSnapshotStatus(snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(VolumeSnapshotStatus{Taken: true}))
+
+		snapshot.Object["status"] = map[string]interface{}{
+			"creationTime": "2023-01-01T00:00:00Z",
+			"readyToUse":   true,
+		}
+		status, err = GetVolumeSnapshotStatus(snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(VolumeSnapshotStatus{Taken: true, Ready: true}))
+	})
+
+	It("report their failure", func() {
+		snapshot := CreateVolumeSnapshot(backup, "cluster-example-1", utils.PVCRolePgData, "")
+		snapshot.Object["status"] = map[string]interface{}{
+			"error": map[string]interface{}{
+				"message": "driver failure",
+			},
+		}
+		_, err := GetVolumeSnapshotStatus(snapshot)
+		Expect(err).To(MatchError(ContainSubstring("driver failure")))
+	})
+
+	It("are used as data source of the PVCs", func() {
+		dataSource := GetVolumeSnapshotDataSource("backup-example")
+		Expect(*dataSource.APIGroup).To(Equal(VolumeSnapshotAPIGroup))
+		Expect(dataSource.Kind).To(Equal(VolumeSnapshotKind))
+		Expect(dataSource.Name).To(Equal("backup-example"))
+	})
+
+	It("are named after the tablespace they store", func() {
+		snapshot := CreateTablespaceVolumeSnapshot(backup, "cluster-example-1-tbs-fast-disk", "fast_disk", "")
+		Expect(snapshot.GetName()).To(Equal(backup.Name + "-tbs-fast-disk"))
+		Expect(snapshot.GetLabels()).To(HaveKeyWithValue(utils.PvcRoleLabelName, string(utils.PVCRolePgTablespace)))
+		Expect(snapshot.GetLabels()).To(HaveKeyWithValue(utils.TablespaceNameLabelName, "fast_disk"))
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 // Package stringset implements a basic set of strings
 package stringset
 
+import "sort"
+
 // Data represent a set of strings
 type Data struct {
 	innerMap map[string]struct{}
@@ -71,6 +73,14 @@ func (set *Data) ToList() (result []string) {
 	return
 }
 
+// ToSortedList returns the strings contained in this set as
+// a sorted string slice
+func (set *Data) ToSortedList() []string {
+	result := set.ToList()
+	sort.Strings(result)
+	return result
+}
+
 // Eq compares two string sets for equality
 func (set *Data) Eq(other *Data) bool {
 	if set == nil || other == nil {
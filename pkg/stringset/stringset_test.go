@@ -51,6 +51,10 @@ var _ = Describe("String set", func() {
 		Expect(From([]string{"one", "two"}).ToList()).To(ContainElements("one", "two"))
 	})
 
+	It("constructs a sorted string slice given a set", func() {
+		Expect(From([]string{"two", "one", "three"}).ToSortedList()).To(Equal([]string{"one", "three", "two"}))
+	})
+
 	It("compares two string set for equality", func() {
 		Expect(From([]string{"one", "two"}).Eq(From([]string{"one", "two"}))).To(BeTrue())
 		Expect(From([]string{"one", "two"}).Eq(From([]string{"two", "three"}))).To(BeFalse())
@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// BackupOptions are the options of a backup triggered on demand
+type BackupOptions struct {
+	// The name of the Backup object. When empty, the name is generated
+	// from the name of the cluster and the current time
+	Name string
+
+	// The backup method, using the default one of the operator when empty
+	Method apiv1.BackupMethod
+}
+
+// buildBackup builds the Backup object requesting a backup of the
+// passed cluster
+func buildBackup(namespace, clusterName string, options BackupOptions, now time.Time) *apiv1.Backup {
+	name := options.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%d", clusterName, now.Unix())
+	}
+
+	return &apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: clusterName,
+			},
+		},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: clusterName},
+			Method:  options.Method,
+		},
+	}
+}
+
+// TriggerBackup requests a backup of the passed cluster, returning the
+// created Backup object. Use WaitForBackupCompleted to wait for its result
+func (c *Client) TriggerBackup(
+	ctx context.Context,
+	namespace, clusterName string,
+	options BackupOptions,
+) (*apiv1.Backup, error) {
+	backup := buildBackup(namespace, clusterName, options, time.Now())
+	if err := c.client.Create(ctx, backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedclient
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+)
+
+// Client is a typed client for the resources managed by the operator
+type Client struct {
+	client client.Client
+}
+
+// New creates a typed client using the passed controller-runtime client,
+// whose scheme needs to include the CloudNativePG API
+func New(c client.Client) *Client {
+	return &Client{client: c}
+}
+
+// NewForConfig creates a typed client connecting to the Kubernetes
+// cluster described by the passed configuration
+func NewForConfig(config *rest.Config) (*Client, error) {
+	c, err := client.New(config, client.Options{
+		Scheme: scheme.New().WithClientGoScheme().WithAPIV1().Build(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return New(c), nil
+}
+
+// ControllerRuntimeClient returns the controller-runtime client used by
+// this client, to access the resources not covered by the typed helpers
+func (c *Client) ControllerRuntimeClient() client.Client {
+	return c.client
+}
+
+// GetCluster gets the passed cluster
+func (c *Client) GetCluster(ctx context.Context, namespace, name string) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &cluster); err != nil {
+		return nil, err
+	}
+	return &cluster, nil
+}
+
+// ListClusters lists the clusters of the passed namespace, or of every
+// namespace when the namespace is empty
+func (c *Client) ListClusters(ctx context.Context, namespace string) ([]apiv1.Cluster, error) {
+	var clusters apiv1.ClusterList
+	if err := c.client.List(ctx, &clusters, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return clusters.Items, nil
+}
+
+// GetBackup gets the passed backup
+func (c *Client) GetBackup(ctx context.Context, namespace, name string) (*apiv1.Backup, error) {
+	var backup apiv1.Backup
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// ListBackups lists the backups of the passed cluster
+func (c *Client) ListBackups(ctx context.Context, namespace, clusterName string) ([]apiv1.Backup, error) {
+	var backups apiv1.BackupList
+	if err := c.client.List(ctx, &backups, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	result := make([]apiv1.Backup, 0, len(backups.Items))
+	for _, backup := range backups.Items {
+		if backup.Spec.Cluster.Name == clusterName {
+			result = append(result, backup)
+		}
+	}
+	return result, nil
+}
+
+// GetPooler gets the passed pooler
+func (c *Client) GetPooler(ctx context.Context, namespace, name string) (*apiv1.Pooler, error) {
+	var pooler apiv1.Pooler
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pooler); err != nil {
+		return nil, err
+	}
+	return &pooler, nil
+}
+
+// ListPoolers lists the poolers of the passed cluster
+func (c *Client) ListPoolers(ctx context.Context, namespace, clusterName string) ([]apiv1.Pooler, error) {
+	var poolers apiv1.PoolerList
+	if err := c.client.List(ctx, &poolers, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	result := make([]apiv1.Pooler, 0, len(poolers.Items))
+	for _, pooler := range poolers.Items {
+		if pooler.Spec.Cluster.Name == clusterName {
+			result = append(result, pooler)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedclient
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed client", func() {
+	newClient := func(objects ...client.Object) *Client {
+		return New(fake.NewClientBuilder().
+			WithScheme(scheme.New().WithClientGoScheme().WithAPIV1().Build()).
+			WithObjects(objects...).
+			Build())
+	}
+
+	newReadyCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				ReadyInstances: 3,
+				Conditions: []metav1.Condition{
+					{Type: string(apiv1.ConditionClusterReady), Status: metav1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	It("lists the backups and the poolers of a cluster", func() {
+		ctx := context.Background()
+		c := newClient(
+			&apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+			},
+			&apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-2", Namespace: "default"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "other"}},
+			},
+			&apiv1.Pooler{
+				ObjectMeta: metav1.ObjectMeta{Name: "pooler-ro", Namespace: "default"},
+				Spec:       apiv1.PoolerSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+			},
+		)
+
+		backups, err := c.ListBackups(ctx, "default", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Name).To(Equal("backup-1"))
+
+		poolers, err := c.ListPoolers(ctx, "default", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(poolers).To(HaveLen(1))
+	})
+
+	It("considers a cluster ready when every instance is ready", func() {
+		cluster := newReadyCluster()
+		Expect(IsClusterReady(cluster)).To(BeTrue())
+
+		cluster.Status.ReadyInstances = 2
+		Expect(IsClusterReady(cluster)).To(BeFalse())
+	})
+
+	It("waits for a cluster to be ready", func() {
+		c := newClient(newReadyCluster())
+		cluster, err := c.WaitForClusterReady(context.Background(), "default", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Name).To(Equal("cluster-example"))
+	})
+
+	It("stops waiting for a cluster when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := newClient().WaitForClusterReady(ctx, "default", "cluster-example")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("triggers a backup of a cluster", func() {
+		ctx := context.Background()
+		c := newClient()
+		backup, err := c.TriggerBackup(ctx, "default", "cluster-example", BackupOptions{
+			Method: apiv1.BackupMethodVolumeSnapshot,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.Spec.Cluster.Name).To(Equal("cluster-example"))
+		Expect(backup.Spec.Method).To(Equal(apiv1.BackupMethodVolumeSnapshot))
+		Expect(backup.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+
+		stored, err := c.GetBackup(ctx, "default", backup.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.Spec.Cluster.Name).To(Equal("cluster-example"))
+	})
+
+	It("generates the name of a backup when not specified", func() {
+		now := time.Unix(1700000000, 0)
+		Expect(buildBackup("default", "cluster-example", BackupOptions{}, now).Name).
+			To(Equal("cluster-example-1700000000"))
+		Expect(buildBackup("default", "cluster-example", BackupOptions{Name: "nightly"}, now).Name).
+			To(Equal("nightly"))
+	})
+
+	It("reports the failed backups", func() {
+		c := newClient(&apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default"},
+			Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseFailed, Error: "no space left"},
+		})
+		_, err := c.WaitForBackupCompleted(context.Background(), "default", "backup-1")
+		Expect(err).To(MatchError(ErrBackupFailed))
+		Expect(err.Error()).To(ContainSubstring("no space left"))
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// DefaultPollInterval is the interval between two checks of the
+// status of a resource while waiting for it
+const DefaultPollInterval = 2 * time.Second
+
+// ErrBackupFailed is returned when waiting for a backup which failed
+var ErrBackupFailed = errors.New("backup failed")
+
+// IsClusterReady checks if every instance of the cluster is ready and
+// the cluster is in a healthy state
+func IsClusterReady(cluster *apiv1.Cluster) bool {
+	return cluster.Status.Phase == apiv1.PhaseHealthy &&
+		cluster.Status.ReadyInstances == cluster.Spec.Instances &&
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionClusterReady))
+}
+
+// WaitForClusterReady waits for the passed cluster to be ready, or the
+// context to be done. A cluster which doesn't exist yet is waited for
+func (c *Client) WaitForClusterReady(ctx context.Context, namespace, name string) (*apiv1.Cluster, error) {
+	var cluster *apiv1.Cluster
+	err := wait.PollImmediateUntilWithContext(ctx, DefaultPollInterval, func(ctx context.Context) (bool, error) {
+		var err error
+		cluster, err = c.GetCluster(ctx, namespace, name)
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return IsClusterReady(cluster), nil
+	})
+	if err != nil {
+		return cluster, fmt.Errorf("while waiting for cluster %s/%s to be ready: %w", namespace, name, err)
+	}
+
+	return cluster, nil
+}
+
+// WaitForBackupCompleted waits for the passed backup to be done, or the
+// context to be done. ErrBackupFailed is returned when the backup failed
+func (c *Client) WaitForBackupCompleted(ctx context.Context, namespace, name string) (*apiv1.Backup, error) {
+	var backup *apiv1.Backup
+	err := wait.PollImmediateUntilWithContext(ctx, DefaultPollInterval, func(ctx context.Context) (bool, error) {
+		var err error
+		backup, err = c.GetBackup(ctx, namespace, name)
+		if err != nil {
+			return false, err
+		}
+		return backup.Status.IsDone(), nil
+	})
+	if err != nil {
+		return backup, fmt.Errorf("while waiting for backup %s/%s: %w", namespace, name, err)
+	}
+
+	if backup.Status.Phase == apiv1.BackupPhaseFailed {
+		return backup, fmt.Errorf("%w: %s", ErrBackupFailed, backup.Status.Error)
+	}
+
+	return backup, nil
+}
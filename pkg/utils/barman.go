@@ -20,11 +20,12 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"strconv"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/cnpgerrors"
 )
 
-var regexPolicy = regexp.MustCompile(`([1-9][0-9]*)([dwm])$`)
+var regexPolicy = regexp.MustCompile(`([1-9][0-9]*)([dwmb])$`)
 
 // ParsePolicy ensure that the policy string follows the
 // rules required by Barman
@@ -39,9 +40,36 @@ func ParsePolicy(policy string) (string, error) {
 		return "", fmt.Errorf("not a valid policy")
 	}
 
+	if matches[2] == "b" {
+		return fmt.Sprintf("REDUNDANCY %v", matches[1]), nil
+	}
+
 	return fmt.Sprintf("RECOVERY WINDOW OF %v %v", matches[1], unitName[matches[2]]), nil
 }
 
+// GetRecoveryWindowDays returns the number of days covered by the
+// recovery window of the passed policy. Zero is returned for the
+// redundancy policies, that are not bound to the age of the backups
+func GetRecoveryWindowDays(policy string) (int, error) {
+	unitDays := map[string]int{
+		"d": 1,
+		"w": 7,
+		"m": 30,
+		"b": 0,
+	}
+	matches := regexPolicy.FindStringSubmatch(policy)
+	if len(matches) < 3 {
+		return 0, fmt.Errorf("not a valid policy")
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+
+	return value * unitDays[matches[2]], nil
+}
+
 // MapToBarmanTagsFormat will transform a map[string]string into the
 // Barman tags format needed
 func MapToBarmanTagsFormat(option string, mapTags map[string]string) ([]string, error) {
@@ -27,6 +27,7 @@ var _ = Describe("parsing policy", func() {
 		Expect(ParsePolicy("10w")).To(BeEquivalentTo("RECOVERY WINDOW OF 10 WEEKS"))
 		Expect(ParsePolicy("7w")).To(BeEquivalentTo("RECOVERY WINDOW OF 7 WEEKS"))
 		Expect(ParsePolicy("7d")).To(BeEquivalentTo("RECOVERY WINDOW OF 7 DAYS"))
+		Expect(ParsePolicy("7b")).To(BeEquivalentTo("REDUNDANCY 7"))
 	})
 
 	It("must complain with a wrong policy", func() {
@@ -38,6 +39,19 @@ var _ = Describe("parsing policy", func() {
 
 		_, err = ParsePolicy("00d")
 		Expect(err).ToNot(BeNil())
+
+		_, err = ParsePolicy("0b")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("computes the days of the recovery window", func() {
+		Expect(GetRecoveryWindowDays("7d")).To(Equal(7))
+		Expect(GetRecoveryWindowDays("2w")).To(Equal(14))
+		Expect(GetRecoveryWindowDays("3m")).To(Equal(90))
+		Expect(GetRecoveryWindowDays("7b")).To(BeZero())
+
+		_, err := GetRecoveryWindowDays("30")
+		Expect(err).ToNot(BeNil())
 	})
 })
 
@@ -0,0 +1,371 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ClusterCapabilities is a snapshot of the features of the Kubernetes
+// cluster the operator depends on
+type ClusterCapabilities struct {
+	// HaveSCC is true when the cluster implements the OpenShift
+	// Security Context Constraints
+	HaveSCC bool `json:"haveSCC"`
+
+	// HaveSeccompSupport is true when the SeccompProfile should be
+	// set in the Pods
+	HaveSeccompSupport bool `json:"haveSeccompSupport"`
+
+	// HaveNodesAccess is true when the operator can list and watch the Nodes
+	HaveNodesAccess bool `json:"haveNodesAccess"`
+
+	// HaveNamespacesAccess is true when the operator can list and
+	// watch the Namespaces
+	HaveNamespacesAccess bool `json:"haveNamespacesAccess"`
+
+	// HaveClusterImageCatalogsAccess is true when the operator can list
+	// and watch the ClusterImageCatalogs
+	HaveClusterImageCatalogsAccess bool `json:"haveClusterImageCatalogsAccess"`
+
+	// HavePodMonitor is true when the PodMonitor resource of the
+	// Prometheus Operator is installed
+	HavePodMonitor bool `json:"havePodMonitor"`
+
+	// HaveServiceMonitor is true when the ServiceMonitor resource of the
+	// Prometheus Operator is installed
+	HaveServiceMonitor bool `json:"haveServiceMonitor"`
+
+	// HaveVolumeSnapshot is true when the snapshot.storage.k8s.io/v1
+	// VolumeSnapshot resource is installed
+	HaveVolumeSnapshot bool `json:"haveVolumeSnapshot"`
+
+	// HaveCertManager is true when the cert-manager.io/v1 Certificate
+	// resource is installed
+	HaveCertManager bool `json:"haveCertManager"`
+
+	// HaveKyverno is true when the kyverno.io/v1 ClusterPolicy resource
+	// of the Kyverno policy engine is installed
+	HaveKyverno bool `json:"haveKyverno"`
+
+	// HaveGatekeeper is true when the templates.gatekeeper.sh/v1
+	// ConstraintTemplate resource of the OPA Gatekeeper policy engine
+	// is installed
+	HaveGatekeeper bool `json:"haveGatekeeper"`
+
+	// HaveKEDA is true when the keda.sh/v1alpha1 ScaledObject resource
+	// is installed
+	HaveKEDA bool `json:"haveKEDA"`
+}
+
+// HavePolicyEngine is true when a policy engine, that may reject the
+// resources created by the operator, is installed
+func (capabilities ClusterCapabilities) HavePolicyEngine() bool {
+	return capabilities.HaveKyverno || capabilities.HaveGatekeeper
+}
+
+// capabilityFields maps the JSON names of the fields of the passed
+// capabilities to the fields themselves
+func capabilityFields(capabilities *ClusterCapabilities) map[string]*bool {
+	return map[string]*bool{
+		"haveSCC":                        &capabilities.HaveSCC,
+		"haveSeccompSupport":             &capabilities.HaveSeccompSupport,
+		"haveNodesAccess":                &capabilities.HaveNodesAccess,
+		"haveNamespacesAccess":           &capabilities.HaveNamespacesAccess,
+		"haveClusterImageCatalogsAccess": &capabilities.HaveClusterImageCatalogsAccess,
+		"havePodMonitor":                 &capabilities.HavePodMonitor,
+		"haveServiceMonitor":             &capabilities.HaveServiceMonitor,
+		"haveVolumeSnapshot":             &capabilities.HaveVolumeSnapshot,
+		"haveCertManager":                &capabilities.HaveCertManager,
+		"haveKyverno":                    &capabilities.HaveKyverno,
+		"haveGatekeeper":                 &capabilities.HaveGatekeeper,
+		"haveKEDA":                       &capabilities.HaveKEDA,
+	}
+}
+
+// CapabilitiesOverrides forces the value of some capabilities of the
+// Kubernetes cluster, whose detection is skipped. The keys are the JSON
+// names of the fields of ClusterCapabilities
+type CapabilitiesOverrides map[string]bool
+
+// ParseCapabilitiesOverrides parses a list of `name=value` rules, where
+// `name` is the JSON name of a capability and `value` a boolean.
+// Invalid rules are logged and ignored
+func ParseCapabilitiesOverrides(rules []string) CapabilitiesOverrides {
+	knownFields := capabilityFields(&ClusterCapabilities{})
+	overrides := make(CapabilitiesOverrides, len(rules))
+	for _, rule := range rules {
+		name, rawValue, found := strings.Cut(rule, "=")
+		name = strings.TrimSpace(name)
+		value, err := strconv.ParseBool(strings.TrimSpace(rawValue))
+		if _, known := knownFields[name]; !found || !known || err != nil {
+			log.Info("Skipping invalid capability override rule", "rule", rule)
+			continue
+		}
+		overrides[name] = value
+	}
+
+	return overrides
+}
+
+// CapabilitiesListener is called when a detection finds the
+// capabilities of the Kubernetes cluster changed
+type CapabilitiesListener func(ctx context.Context, old, new ClusterCapabilities)
+
+// CapabilitiesRegistry detects the capabilities of the Kubernetes cluster,
+// re-running the detection periodically or when triggered, so that
+// installing the Prometheus Operator after the operator doesn't
+// require a restart to use it
+type CapabilitiesRegistry struct {
+	discoveryClient *discovery.DiscoveryClient
+	kubeClient      client.Client
+	interval        time.Duration
+	trigger         chan struct{}
+
+	mutex     sync.RWMutex
+	current   ClusterCapabilities
+	degraded  bool
+	overrides CapabilitiesOverrides
+	listeners []CapabilitiesListener
+}
+
+// degradedRetryInterval is the interval between the detections of a
+// degraded registry
+const degradedRetryInterval = 30 * time.Second
+
+// NewCapabilitiesRegistry creates a new registry re-running the detection
+// every interval. A zero interval disables the periodic detection
+func NewCapabilitiesRegistry(
+	discoveryClient *discovery.DiscoveryClient,
+	kubeClient client.Client,
+	interval time.Duration,
+) *CapabilitiesRegistry {
+	return &CapabilitiesRegistry{
+		discoveryClient: discoveryClient,
+		kubeClient:      kubeClient,
+		interval:        interval,
+		trigger:         make(chan struct{}, 1),
+		current:         GetCurrentCapabilities(),
+	}
+}
+
+// Get returns the last detected capabilities. A nil registry returns
+// the capabilities detected by the package-level functions
+func (r *CapabilitiesRegistry) Get() ClusterCapabilities {
+	if r == nil {
+		return GetCurrentCapabilities()
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.current
+}
+
+// AddListener registers a function to be called every time the
+// capabilities change. It must be called before the registry is started
+func (r *CapabilitiesRegistry) AddListener(listener CapabilitiesListener) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.listeners = append(r.listeners, listener)
+}
+
+// SetOverrides forces the value of the passed capabilities, skipping their
+// detection. It must be called before the registry is started
+func (r *CapabilitiesRegistry) SetOverrides(overrides CapabilitiesOverrides) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.overrides = overrides
+}
+
+// Detect detects the capabilities of the Kubernetes cluster, notifying the
+// listeners if they changed. The previous capabilities are kept on error
+func (r *CapabilitiesRegistry) Detect(ctx context.Context) error {
+	capabilities, err := r.detect(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.update(ctx, capabilities, false)
+	return nil
+}
+
+// DetectWithBackoff detects the capabilities of the Kubernetes cluster,
+// retrying with the passed backoff on errors, i.e. when an aggregated API
+// is temporarily unavailable. When the detection keeps failing, the
+// capabilities that couldn't be detected are assumed to be missing and
+// the registry is degraded, re-attempting the detection in background
+// once started until it succeeds
+func (r *CapabilitiesRegistry) DetectWithBackoff(ctx context.Context, backoff wait.Backoff) {
+	var capabilities ClusterCapabilities
+	err := retry.OnError(backoff, func(error) bool { return true }, func() error {
+		var err error
+		capabilities, err = r.detect(ctx)
+		return err
+	})
+	if err != nil {
+		log.FromContext(ctx).Warning(
+			"Cannot detect the capabilities of the Kubernetes cluster, "+
+				"assuming the ones that couldn't be detected are missing",
+			"error", err.Error(),
+			"retryInterval", degradedRetryInterval)
+	}
+
+	r.update(ctx, capabilities, err != nil)
+}
+
+// IsDegraded is true when the last detection at startup failed, and the
+// capabilities that couldn't be detected are assumed to be missing
+func (r *CapabilitiesRegistry) IsDegraded() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.degraded
+}
+
+// detect runs every detector, returning the detected capabilities and the
+// errors encountered. The capabilities that couldn't be detected are
+// assumed to be missing
+func (r *CapabilitiesRegistry) detect(ctx context.Context) (ClusterCapabilities, error) {
+	var capabilities ClusterCapabilities
+	detectors := []struct {
+		name   string
+		detect func() (bool, error)
+	}{
+		{"haveSCC", func() (bool, error) { return detectSecurityContextConstraints(r.discoveryClient) }},
+		{"haveSeccompSupport", func() (bool, error) { return detectSeccompSupport(r.discoveryClient) }},
+		{"havePodMonitor", func() (bool, error) { return PodMonitorExist(r.discoveryClient) }},
+		{"haveServiceMonitor", func() (bool, error) { return ServiceMonitorExist(r.discoveryClient) }},
+		{"haveVolumeSnapshot", func() (bool, error) { return VolumeSnapshotExist(r.discoveryClient) }},
+		{"haveCertManager", func() (bool, error) { return CertManagerExist(r.discoveryClient) }},
+		{"haveKyverno", func() (bool, error) { return KyvernoExist(r.discoveryClient) }},
+		{"haveGatekeeper", func() (bool, error) { return GatekeeperExist(r.discoveryClient) }},
+		{"haveKEDA", func() (bool, error) { return ScaledObjectExist(r.discoveryClient) }},
+		{"haveNodesAccess", func() (bool, error) { return canListAndWatch(ctx, r.kubeClient, "", "nodes") }},
+		{"haveNamespacesAccess", func() (bool, error) {
+			return canListAndWatch(ctx, r.kubeClient, "", "namespaces")
+		}},
+		{"haveClusterImageCatalogsAccess", func() (bool, error) {
+			return canListAndWatch(ctx, r.kubeClient, "postgresql.cnpg.io", "clusterimagecatalogs")
+		}},
+	}
+
+	r.mutex.RLock()
+	overrides := r.overrides
+	r.mutex.RUnlock()
+
+	var errs []error
+	fields := capabilityFields(&capabilities)
+	for _, detector := range detectors {
+		if value, overridden := overrides[detector.name]; overridden {
+			*fields[detector.name] = value
+			continue
+		}
+
+		value, err := detector.detect()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("while detecting %s: %w", detector.name, err))
+			continue
+		}
+		*fields[detector.name] = value
+	}
+
+	return capabilities, utilerrors.NewAggregate(errs)
+}
+
+// update replaces the current capabilities, notifying the listeners if
+// they changed
+func (r *CapabilitiesRegistry) update(ctx context.Context, capabilities ClusterCapabilities, degraded bool) {
+	r.mutex.Lock()
+	old := r.current
+	r.current = capabilities
+	r.degraded = degraded
+	listeners := r.listeners
+	r.mutex.Unlock()
+
+	updateCurrentCapabilities(func(current *ClusterCapabilities) {
+		*current = capabilities
+	})
+
+	if old == capabilities {
+		return
+	}
+
+	log.FromContext(ctx).Info("Detected a change in the capabilities of the Kubernetes cluster",
+		"old", old, "new", capabilities)
+	for _, listener := range listeners {
+		listener(ctx, old, capabilities)
+	}
+}
+
+// Trigger requests a new detection, without waiting for it to be done
+func (r *CapabilitiesRegistry) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+		// A detection is already pending
+	}
+}
+
+// Start re-runs the detection periodically and when triggered, until the
+// context is canceled. It implements the manager.Runnable interface
+func (r *CapabilitiesRegistry) Start(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("capabilities")
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	var ticks <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		// A degraded registry re-attempts the detection until it succeeds
+		var retries <-chan time.Time
+		if r.IsDegraded() {
+			retries = time.After(degradedRetryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticks:
+		case <-retries:
+		case <-r.trigger:
+		}
+
+		if err := r.Detect(ctx); err != nil {
+			contextLogger.Error(err, "Cannot detect the capabilities of the Kubernetes cluster")
+		}
+	}
+}
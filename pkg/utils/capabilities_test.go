@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capabilities registry", func() {
+	It("returns the capabilities detected by the package functions when nil", func() {
+		var registry *CapabilitiesRegistry
+		Expect(registry.Get()).To(Equal(GetCurrentCapabilities()))
+	})
+
+	It("starts from the capabilities detected by the package functions", func() {
+		registry := NewCapabilitiesRegistry(nil, nil, 0)
+		Expect(registry.Get()).To(Equal(GetCurrentCapabilities()))
+	})
+
+	It("coalesces the pending detection requests", func() {
+		registry := NewCapabilitiesRegistry(nil, nil, 0)
+		registry.Trigger()
+		registry.Trigger()
+		Expect(registry.trigger).To(HaveLen(1))
+	})
+
+	It("skips the detection of the overridden capabilities", func(ctx SpecContext) {
+		previous := GetCurrentCapabilities()
+		DeferCleanup(func() {
+			updateCurrentCapabilities(func(capabilities *ClusterCapabilities) {
+				*capabilities = previous
+			})
+		})
+
+		registry := NewCapabilitiesRegistry(nil, nil, 0)
+		overrides := CapabilitiesOverrides{}
+		for name := range capabilityFields(&ClusterCapabilities{}) {
+			overrides[name] = true
+		}
+		registry.SetOverrides(overrides)
+
+		Expect(registry.Detect(ctx)).To(Succeed())
+		Expect(registry.Get().HavePodMonitor).To(BeTrue())
+		Expect(registry.Get().HaveSCC).To(BeTrue())
+	})
+
+	It("degrades to conservative capabilities when the discovery fails", func(ctx SpecContext) {
+		previous := GetCurrentCapabilities()
+		DeferCleanup(func() {
+			updateCurrentCapabilities(func(capabilities *ClusterCapabilities) {
+				*capabilities = previous
+			})
+		})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		DeferCleanup(server.Close)
+
+		registry := NewCapabilitiesRegistry(
+			discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: server.URL}), nil, 0)
+		registry.SetOverrides(CapabilitiesOverrides{
+			"haveNodesAccess":                true,
+			"haveNamespacesAccess":           false,
+			"haveClusterImageCatalogsAccess": false,
+		})
+		registry.current.HaveSCC = true
+
+		Expect(registry.Detect(ctx)).ToNot(Succeed())
+		Expect(registry.Get().HaveSCC).To(BeTrue())
+		Expect(registry.IsDegraded()).To(BeFalse())
+
+		registry.DetectWithBackoff(ctx, wait.Backoff{Duration: time.Millisecond, Steps: 2})
+		Expect(registry.IsDegraded()).To(BeTrue())
+		Expect(registry.Get()).To(Equal(ClusterCapabilities{HaveNodesAccess: true}))
+	})
+})
+
+var _ = Describe("Capabilities overrides", func() {
+	It("parses the valid rules", func() {
+		Expect(ParseCapabilitiesOverrides([]string{"havePodMonitor=false", " haveSCC = true "})).To(Equal(
+			CapabilitiesOverrides{"havePodMonitor": false, "haveSCC": true}))
+	})
+
+	It("ignores the invalid rules", func() {
+		Expect(ParseCapabilitiesOverrides([]string{"havePodMonitor", "unknown=true", "haveSCC=maybe"})).To(BeEmpty())
+	})
+})
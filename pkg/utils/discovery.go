@@ -17,21 +17,32 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
+	"sync"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// This variable stores the result of the DetectSecurityContextConstraints check
-var haveSCC bool
-
-// This variable specifies whether we should set the SeccompProfile or not in the pods
-var supportSeccomp bool
+// currentCapabilities stores the result of the last detection of the
+// capabilities of the Kubernetes cluster. The access to the Nodes, the
+// Namespaces and the ClusterImageCatalogs defaults to true, as the standard
+// installation grants those permissions
+var (
+	currentCapabilities = ClusterCapabilities{
+		HaveNodesAccess:                true,
+		HaveNamespacesAccess:           true,
+		HaveClusterImageCatalogsAccess: true,
+	}
+	currentCapabilitiesMutex sync.RWMutex
+)
 
 // `minorVersionRegexp` is used to extract the minor version from
 // the Kubernetes API server version. Some providers, like AWS,
@@ -76,20 +87,26 @@ func resourceExist(client *discovery.DiscoveryClient, groupVersion, kind string)
 
 // DetectSecurityContextConstraints connects to the discovery API and find out if
 // we're running under a system that implements OpenShift Security Context Constraints
-func DetectSecurityContextConstraints(client *discovery.DiscoveryClient) (err error) {
-	haveSCC, err = resourceExist(client, "security.openshift.io/v1", "securitycontextconstraints")
+func DetectSecurityContextConstraints(client *discovery.DiscoveryClient) error {
+	haveSCC, err := detectSecurityContextConstraints(client)
 	if err != nil {
 		return err
 	}
 
+	updateCurrentCapabilities(func(capabilities *ClusterCapabilities) {
+		capabilities.HaveSCC = haveSCC
+	})
 	return nil
 }
 
+func detectSecurityContextConstraints(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "security.openshift.io/v1", "securitycontextconstraints")
+}
+
 // HaveSecurityContextConstraints returns true if we're running under a system that implements
 // OpenShift Security Context Constraints
-// It panics if called before DetectSecurityContextConstraints
 func HaveSecurityContextConstraints() bool {
-	return haveSCC
+	return GetCurrentCapabilities().HaveSCC
 }
 
 // PodMonitorExist tries to find the PodMonitor resource in the current cluster
@@ -102,10 +119,115 @@ func PodMonitorExist(client *discovery.DiscoveryClient) (bool, error) {
 	return exist, nil
 }
 
+// ServiceMonitorExist tries to find the ServiceMonitor resource in the current cluster
+func ServiceMonitorExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "monitoring.coreos.com/v1", "servicemonitors")
+}
+
+// VolumeSnapshotExist checks if the VolumeSnapshot resource exists in the
+// current cluster, which is true when an external snapshotter is installed
+func VolumeSnapshotExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "snapshot.storage.k8s.io/v1", "volumesnapshots")
+}
+
+// CertManagerExist checks if the Certificate resource of cert-manager
+// exists in the current cluster
+func CertManagerExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "cert-manager.io/v1", "certificates")
+}
+
+// KyvernoExist checks if the ClusterPolicy resource of Kyverno exists in
+// the current cluster
+func KyvernoExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "kyverno.io/v1", "clusterpolicies")
+}
+
+// GatekeeperExist checks if the ConstraintTemplate resource of OPA
+// Gatekeeper exists in the current cluster
+func GatekeeperExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "templates.gatekeeper.sh/v1", "constrainttemplates")
+}
+
+// ScaledObjectExist checks if the ScaledObject resource of KEDA exists in
+// the current cluster
+func ScaledObjectExist(client *discovery.DiscoveryClient) (bool, error) {
+	return resourceExist(client, "keda.sh/v1alpha1", "scaledobjects")
+}
+
+// DetectClusterScopedAccess checks whether the operator is allowed to list and
+// watch the Nodes, the Namespaces and the ClusterImageCatalogs. Those
+// permissions are not granted when
+// the operator is installed with namespaced RBAC, and the features depending
+// on them are disabled
+func DetectClusterScopedAccess(ctx context.Context, kubeClient client.Client) error {
+	haveNodesAccess, err := canListAndWatch(ctx, kubeClient, "", "nodes")
+	if err != nil {
+		return err
+	}
+
+	haveNamespacesAccess, err := canListAndWatch(ctx, kubeClient, "", "namespaces")
+	if err != nil {
+		return err
+	}
+
+	haveClusterImageCatalogsAccess, err := canListAndWatch(ctx, kubeClient, "postgresql.cnpg.io", "clusterimagecatalogs")
+	if err != nil {
+		return err
+	}
+
+	updateCurrentCapabilities(func(capabilities *ClusterCapabilities) {
+		capabilities.HaveNodesAccess = haveNodesAccess
+		capabilities.HaveNamespacesAccess = haveNamespacesAccess
+		capabilities.HaveClusterImageCatalogsAccess = haveClusterImageCatalogsAccess
+	})
+	return nil
+}
+
+// CanWatchCustomResourceDefinitions checks whether the operator is allowed
+// to list and watch every CustomResourceDefinition, which is not the case when
+// it is installed with namespaced RBAC
+func CanWatchCustomResourceDefinitions(ctx context.Context, kubeClient client.Client) (bool, error) {
+	return canListAndWatch(ctx, kubeClient, "apiextensions.k8s.io", "customresourcedefinitions")
+}
+
+// canListAndWatch checks, with a SelfSubjectAccessReview, whether the
+// current user can list and watch a cluster-scoped resource
+func canListAndWatch(ctx context.Context, kubeClient client.Client, group, resource string) (bool, error) {
+	for _, verb := range []string{"list", "watch"} {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Group:    group,
+					Resource: resource,
+				},
+			},
+		}
+		if err := kubeClient.Create(ctx, review); err != nil {
+			return false, err
+		}
+		if !review.Status.Allowed {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// HaveNodesAccess returns true if the operator can list and watch the Nodes
+func HaveNodesAccess() bool {
+	return GetCurrentCapabilities().HaveNodesAccess
+}
+
+// HaveNamespacesAccess returns true if the operator can list and watch the Namespaces
+func HaveNamespacesAccess() bool {
+	return GetCurrentCapabilities().HaveNamespacesAccess
+}
+
 // HaveSeccompSupport returns true if Seccomp is supported. If it is, we should
 // set the SeccompProfile in the pods
 func HaveSeccompSupport() bool {
-	return supportSeccomp
+	return GetCurrentCapabilities().HaveSeccompSupport
 }
 
 // extractK8sMinorVersion extracts and parses the Kubernetes minor version from
@@ -122,21 +244,42 @@ func extractK8sMinorVersion(info *version.Info) (int, error) {
 
 // DetectSeccompSupport checks the version of Kubernetes in the cluster to determine
 // whether Seccomp is supported
-func DetectSeccompSupport(client *discovery.DiscoveryClient) (err error) {
-	supportSeccomp = false
+func DetectSeccompSupport(client *discovery.DiscoveryClient) error {
+	supportSeccomp, err := detectSeccompSupport(client)
+	updateCurrentCapabilities(func(capabilities *ClusterCapabilities) {
+		capabilities.HaveSeccompSupport = supportSeccomp
+	})
+	return err
+}
+
+func detectSeccompSupport(client *discovery.DiscoveryClient) (bool, error) {
 	kubernetesVersion, err := client.ServerVersion()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	minor, err := extractK8sMinorVersion(kubernetesVersion)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if minor >= 24 {
-		supportSeccomp = true
-	}
+	return minor >= 24, nil
+}
+
+// GetCurrentCapabilities returns the last detected capabilities of the
+// Kubernetes cluster
+func GetCurrentCapabilities() ClusterCapabilities {
+	currentCapabilitiesMutex.RLock()
+	defer currentCapabilitiesMutex.RUnlock()
+
+	return currentCapabilities
+}
+
+// updateCurrentCapabilities changes the last detected capabilities
+// of the Kubernetes cluster
+func updateCurrentCapabilities(update func(capabilities *ClusterCapabilities)) {
+	currentCapabilitiesMutex.Lock()
+	defer currentCapabilitiesMutex.Unlock()
 
-	return
+	update(&currentCapabilities)
 }
@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -41,6 +42,19 @@ var _ = Describe("Job conditions", func() {
 		Expect(IsJobComplete(completeJob)).To(BeTrue())
 	})
 
+	It("detects if a certain job has failed", func() {
+		failedJob := batchv1.Job{
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+		Expect(IsJobFailed(nonCompleteJob)).To(BeFalse())
+		Expect(IsJobFailed(completeJob)).To(BeFalse())
+		Expect(IsJobFailed(failedJob)).To(BeTrue())
+	})
+
 	It("can count the number of complete jobs", func() {
 		Expect(CountCompleteJobs([]batchv1.Job{nonCompleteJob, completeJob})).To(Equal(1))
 		Expect(CountCompleteJobs([]batchv1.Job{nonCompleteJob})).To(Equal(0))
@@ -38,9 +38,22 @@ const (
 	// PodRoleLabelName is the name of the label containing the podRole value
 	PodRoleLabelName = "cnpg.io/podRole"
 
+	// TablespaceNameLabelName is the name of the label containing the name
+	// of the tablespace stored in a PVC
+	TablespaceNameLabelName = "cnpg.io/tablespaceName"
+
 	// InstanceNameLabelName is the name of the label containing the instance name
 	InstanceNameLabelName = "cnpg.io/instanceName"
 
+	// ReadOnlyServiceMemberLabelName is the name of the label telling if a
+	// replica can be selected by the `-ro` service, used when the delayed
+	// replicas are excluded from it
+	ReadOnlyServiceMemberLabelName = "cnpg.io/readOnlyServiceMember"
+
+	// MetricsServiceLabelName is the name of the label set on the headless
+	// services exposing the metrics, used by the ServiceMonitors to select them
+	MetricsServiceLabelName = "cnpg.io/metricsService"
+
 	// OperatorVersionAnnotationName is the name of the annotation containing
 	// the version of the operator that generated a certain object
 	OperatorVersionAnnotationName = "cnpg.io/operatorVersion"
@@ -59,10 +72,59 @@ const (
 	// HibernatePgControlDataAnnotationName contains the pg_controldata output of the hibernated cluster
 	HibernatePgControlDataAnnotationName = "cnpg.io/hibernatePgControlData"
 
+	// PausedDuringSwitchoverAnnotationName is the name of the annotation
+	// marking the poolers that have been paused by the operator while a
+	// switchover of the referenced cluster is in progress
+	PausedDuringSwitchoverAnnotationName = "cnpg.io/pausedDuringSwitchover"
+
+	// SplitBrainAcknowledgedAnnotationName is the name of the annotation
+	// the user sets on a cluster to resume the reconciliation loop after a
+	// split-brain has been detected and resolved
+	SplitBrainAcknowledgedAnnotationName = "cnpg.io/splitBrainAcknowledged"
+
+	// ClusterExpirationAnnotationName is the name of the annotation
+	// containing the time, in RFC3339 format, after which the operator
+	// deletes a cluster, as done for the ephemeral clones
+	ClusterExpirationAnnotationName = "cnpg.io/expiresAt"
+
+	// SkipFinalBackupAnnotationName is the name of the annotation the user
+	// sets on a deleted cluster to release its resources without waiting
+	// for the final backup
+	SkipFinalBackupAnnotationName = "cnpg.io/skipFinalBackup"
+
+	// WalArchivingPausedAnnotationName is the name of the annotation the
+	// user sets on a cluster to pause the WAL archiving, i.e. during a
+	// planned maintenance of the object store
+	WalArchivingPausedAnnotationName = "cnpg.io/walArchivingPaused"
+
+	// WalArchivingPausedMaxSizeAnnotationName is the name of the annotation
+	// limiting the size of the WAL files kept waiting to be archived while
+	// the WAL archiving is paused
+	WalArchivingPausedMaxSizeAnnotationName = "cnpg.io/walArchivingPausedMaxSize"
+
+	// InstancePprofAnnotationName is the name of the annotation enabling
+	// the pprof endpoints of the instance managers of a cluster
+	InstancePprofAnnotationName = "cnpg.io/instancePprof"
+
+	// VeleroFencedInstanceAnnotationName is the name of the annotation
+	// recording the instance fenced by the Velero pre-backup hook, which
+	// is to be unfenced by the post-backup hook or when restoring the cluster
+	VeleroFencedInstanceAnnotationName = "cnpg.io/veleroFencedInstance"
+
+	// VeleroFenceExpirationAnnotationName is the name of the annotation
+	// containing the time, in RFC3339 format, after which the operator
+	// lifts the fence set by the Velero pre-backup hook
+	VeleroFenceExpirationAnnotationName = "cnpg.io/veleroFenceExpiration"
+
 	// skipEmptyWalArchiveCheck turns off the checks that ensure that the WAL archive is empty before writing data
 	skipEmptyWalArchiveCheck = "cnpg.io/skipEmptyWalArchiveCheck"
 )
 
+// FinalBackupFinalizerName is the name of the finalizer preventing the
+// resources of a deleted cluster to be released before the final backup
+// is completed
+const FinalBackupFinalizerName = "cnpg.io/finalBackup"
+
 type annotationStatus string
 
 const (
@@ -86,6 +148,8 @@ const (
 	PVCRolePgData PVCRole = "PG_DATA"
 	// PVCRolePgWal is a PVC used for storing PG_WAL
 	PVCRolePgWal PVCRole = "PG_WAL"
+	// PVCRolePgTablespace is a PVC used for storing a tablespace
+	PVCRolePgTablespace PVCRole = "PG_TABLESPACE"
 )
 
 // LabelClusterName labels the object with the cluster name
@@ -202,6 +266,18 @@ func IsReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[ReconciliationLoopAnnotationName] == string(annotationStatusDisabled)
 }
 
+// IsInstancePprofEnabled checks if the pprof endpoints of the instance
+// manager are enabled on the given resource
+func IsInstancePprofEnabled(object *metav1.ObjectMeta) bool {
+	return object.Annotations[InstancePprofAnnotationName] == string(annotationStatusEnabled)
+}
+
+// IsWalArchivingPaused returns a boolean indicating if the WAL archiving
+// has been paused by the user
+func IsWalArchivingPaused(object *metav1.ObjectMeta) bool {
+	return object.Annotations[WalArchivingPausedAnnotationName] == "true"
+}
+
 // IsEmptyWalArchiveCheckEnabled returns a boolean indicating if we should run the logic that checks if the WAL archive
 // storage is empty
 func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
@@ -121,3 +121,27 @@ var _ = Describe("Annotate pods management", func() {
 		Expect(pod.ObjectMeta.Annotations[AppArmorAnnotationPrefix+"/apparmor_profile"]).To(Equal("unconfined"))
 	})
 })
+
+var _ = Describe("Instance pprof annotation", func() {
+	It("enables the pprof endpoints only when explicitly requested", func() {
+		Expect(IsInstancePprofEnabled(&metav1.ObjectMeta{})).To(BeFalse())
+		Expect(IsInstancePprofEnabled(&metav1.ObjectMeta{
+			Annotations: map[string]string{InstancePprofAnnotationName: "disabled"},
+		})).To(BeFalse())
+		Expect(IsInstancePprofEnabled(&metav1.ObjectMeta{
+			Annotations: map[string]string{InstancePprofAnnotationName: "enabled"},
+		})).To(BeTrue())
+	})
+})
+
+var _ = Describe("WAL archiving paused annotation", func() {
+	It("pauses the WAL archiving only when explicitly requested", func() {
+		Expect(IsWalArchivingPaused(&metav1.ObjectMeta{})).To(BeFalse())
+		Expect(IsWalArchivingPaused(&metav1.ObjectMeta{
+			Annotations: map[string]string{WalArchivingPausedAnnotationName: "false"},
+		})).To(BeFalse())
+		Expect(IsWalArchivingPaused(&metav1.ObjectMeta{
+			Annotations: map[string]string{WalArchivingPausedAnnotationName: "true"},
+		})).To(BeTrue())
+	})
+})
@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// storageVersionMigrationPageSize is the number of objects retrieved
+// by every list request while migrating a resource
+const storageVersionMigrationPageSize = 100
+
+// MigrateStoredVersions rewrites the objects of the passed CRDs in their
+// storage version, and then removes the previous versions from the stored
+// ones. Without this, an API version cannot be dropped from the CRDs in a
+// future release of the operator. The CRDs which are not installed are skipped.
+// A failure doesn't stop the migration of the other CRDs, and every failure
+// is returned
+func MigrateStoredVersions(ctx context.Context, kubeClient client.Client, crdNames []string) error {
+	var errs []error
+	for _, name := range crdNames {
+		if err := migrateCustomResourceDefinition(ctx, kubeClient, name); err != nil {
+			errs = append(errs, fmt.Errorf("while migrating the stored versions of %s: %w", name, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// migrateCustomResourceDefinition migrates the objects of the passed CRD
+// when more than one version of them may be stored in etcd
+func migrateCustomResourceDefinition(ctx context.Context, kubeClient client.Client, name string) error {
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: name}, &crd); err != nil {
+		if apierrs.IsNotFound(err) {
+			// This CRD has not been installed yet
+			return nil
+		}
+		return err
+	}
+
+	storageVersion := getStorageVersion(&crd)
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version found")
+	}
+
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Migrating the stored objects to the storage version",
+		"customResourceDefinition", name,
+		"storedVersions", crd.Status.StoredVersions,
+		"storageVersion", storageVersion)
+
+	gvk := schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: storageVersion,
+		Kind:    crd.Spec.Names.ListKind,
+	}
+	failedObjects, err := rewriteStoredObjects(ctx, kubeClient, gvk)
+	if err != nil {
+		return err
+	}
+	if len(failedObjects) > 0 {
+		// The objects which have not been rewritten may still be stored
+		// in a previous version
+		return fmt.Errorf("cannot rewrite %d objects, keeping the stored versions: %s",
+			len(failedObjects), strings.Join(failedObjects, ", "))
+	}
+
+	oldCrd := crd.DeepCopy()
+	crd.Status.StoredVersions = []string{storageVersion}
+	return kubeClient.Status().Patch(ctx, &crd, client.MergeFrom(oldCrd))
+}
+
+// rewriteStoredObjects updates every object of the passed list kind without
+// changing it, making the API server store it again in the storage version.
+// The objects which cannot be updated, i.e. because they are rejected by a
+// webhook, are reported and skipped, and their names are returned
+func rewriteStoredObjects(
+	ctx context.Context,
+	kubeClient client.Client,
+	gvk schema.GroupVersionKind,
+) ([]string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var failedObjects []string
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := kubeClient.List(
+			ctx,
+			list,
+			client.Limit(storageVersionMigrationPageSize),
+			client.Continue(continueToken),
+		); err != nil {
+			return failedObjects, err
+		}
+
+		for idx := range list.Items {
+			// An object which has been deleted doesn't need to be migrated,
+			// and a conflict means it has been already written again
+			object := &list.Items[idx]
+			err := kubeClient.Update(ctx, object)
+			if err != nil && !apierrs.IsNotFound(err) && !apierrs.IsConflict(err) {
+				objectName := client.ObjectKeyFromObject(object).String()
+				contextLogger.Warning("Cannot rewrite the object in the storage version",
+					"kind", object.GetKind(),
+					"object", objectName,
+					"error", err)
+				failedObjects = append(failedObjects, objectName)
+			}
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return failedObjects, nil
+		}
+	}
+}
+
+// getStorageVersion gets the name of the version used to store the
+// objects of the passed CRD
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// rejectingClient is a client whose updates of the object with the passed
+// name are rejected, like an admission webhook would do
+type rejectingClient struct {
+	client.Client
+	rejectedName string
+}
+
+func (c rejectingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if obj.GetName() == c.rejectedName {
+		return fmt.Errorf("admission webhook denied the request")
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+var _ = Describe("Stored versions migration", func() {
+	const crdName = "clusters.postgresql.cnpg.io"
+	gv := schema.GroupVersion{Group: "postgresql.cnpg.io", Version: "v1"}
+
+	buildCRD := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crdName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: gv.Group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     "Cluster",
+					ListKind: "ClusterList",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Served: true},
+					{Name: gv.Version, Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	buildClient := func(objects ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		scheme.AddKnownTypeWithName(gv.WithKind("Cluster"), &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gv.WithKind("ClusterList"), &unstructured.UnstructuredList{})
+
+		cluster := &unstructured.Unstructured{}
+		cluster.SetGroupVersionKind(gv.WithKind("Cluster"))
+		cluster.SetNamespace("default")
+		cluster.SetName("cluster-example")
+
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(objects, cluster)...).
+			Build()
+	}
+
+	getResourceVersion := func(ctx context.Context, kubeClient client.Client, name string) string {
+		cluster := &unstructured.Unstructured{}
+		cluster.SetGroupVersionKind(gv.WithKind("Cluster"))
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, cluster)).
+			To(Succeed())
+		return cluster.GetResourceVersion()
+	}
+
+	getClusterResourceVersion := func(ctx context.Context, kubeClient client.Client) string {
+		return getResourceVersion(ctx, kubeClient, "cluster-example")
+	}
+
+	It("rewrites the objects and prunes the stored versions", func(ctx SpecContext) {
+		kubeClient := buildClient(buildCRD("v1beta1", "v1"))
+		resourceVersion := getClusterResourceVersion(ctx, kubeClient)
+
+		Expect(MigrateStoredVersions(ctx, kubeClient, []string{crdName})).To(Succeed())
+		Expect(getClusterResourceVersion(ctx, kubeClient)).ToNot(Equal(resourceVersion))
+
+		var crd apiextensionsv1.CustomResourceDefinition
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: crdName}, &crd)).To(Succeed())
+		Expect(crd.Status.StoredVersions).To(Equal([]string{"v1"}))
+	})
+
+	It("doesn't touch the objects when only the storage version is stored", func(ctx SpecContext) {
+		kubeClient := buildClient(buildCRD("v1"))
+		resourceVersion := getClusterResourceVersion(ctx, kubeClient)
+
+		Expect(MigrateStoredVersions(ctx, kubeClient, []string{crdName})).To(Succeed())
+		Expect(getClusterResourceVersion(ctx, kubeClient)).To(Equal(resourceVersion))
+	})
+
+	It("skips the CRDs which are not installed", func(ctx SpecContext) {
+		kubeClient := buildClient()
+		Expect(MigrateStoredVersions(ctx, kubeClient, []string{crdName})).To(Succeed())
+	})
+
+	It("fails when the CRD has no storage version", func(ctx SpecContext) {
+		crd := buildCRD("v1beta1", "v1")
+		crd.Spec.Versions[1].Storage = false
+		kubeClient := buildClient(crd)
+		Expect(MigrateStoredVersions(ctx, kubeClient, []string{crdName})).ToNot(Succeed())
+	})
+
+	It("rewrites the other objects when one is rejected, keeping the stored versions", func(ctx SpecContext) {
+		rejected := &unstructured.Unstructured{}
+		rejected.SetGroupVersionKind(gv.WithKind("Cluster"))
+		rejected.SetNamespace("default")
+		rejected.SetName("cluster-rejected")
+		kubeClient := rejectingClient{
+			Client:       buildClient(buildCRD("v1beta1", "v1"), rejected),
+			rejectedName: "cluster-rejected",
+		}
+		resourceVersion := getClusterResourceVersion(ctx, kubeClient)
+
+		err := MigrateStoredVersions(ctx, kubeClient, []string{crdName})
+		Expect(err).To(MatchError(ContainSubstring("default/cluster-rejected")))
+		Expect(getClusterResourceVersion(ctx, kubeClient)).ToNot(Equal(resourceVersion))
+
+		var crd apiextensionsv1.CustomResourceDefinition
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: crdName}, &crd)).To(Succeed())
+		Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1", "v1"}))
+	})
+
+	It("migrates the other CRDs when one fails", func(ctx SpecContext) {
+		brokenCRD := buildCRD("v1beta1", "v1")
+		brokenCRD.Name = "poolers.postgresql.cnpg.io"
+		brokenCRD.Spec.Versions[1].Storage = false
+		kubeClient := buildClient(brokenCRD, buildCRD("v1beta1", "v1"))
+
+		err := MigrateStoredVersions(ctx, kubeClient, []string{brokenCRD.Name, crdName})
+		Expect(err).To(MatchError(ContainSubstring(brokenCRD.Name)))
+
+		var crd apiextensionsv1.CustomResourceDefinition
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: crdName}, &crd)).To(Succeed())
+		Expect(crd.Status.StoredVersions).To(Equal([]string{"v1"}))
+	})
+})
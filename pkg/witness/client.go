@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package witness
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// ClientProvider returns the client used to reach the Kubernetes cluster
+// hosting the witness Lease, caching the clients built from a kubeconfig
+type ClientProvider struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]cachedClient
+}
+
+// cachedClient is a client built from a kubeconfig
+type cachedClient struct {
+	kubeconfig []byte
+	client     client.Client
+}
+
+// GetLeaseKey returns the name and the namespace of the witness Lease of
+// the passed cluster
+func GetLeaseKey(cluster *apiv1.Cluster) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      cluster.Spec.FailoverWitness.LeaseName,
+		Namespace: cluster.Spec.FailoverWitness.GetLeaseNamespace(cluster.Namespace),
+	}
+}
+
+// GetClient returns the client to be used for the witness of the passed
+// cluster. When the witness is local, the passed client is used, otherwise
+// a client is built from the kubeconfig stored in the configured secret
+func (provider *ClientProvider) GetClient(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) (client.Client, error) {
+	selector := cluster.Spec.FailoverWitness.KubeconfigSecret
+	if selector == nil {
+		return cli, nil
+	}
+
+	secretKey := types.NamespacedName{Namespace: cluster.Namespace, Name: selector.Name}
+	var secret corev1.Secret
+	if err := cli.Get(ctx, secretKey, &secret); err != nil {
+		return nil, fmt.Errorf("while reading the witness kubeconfig secret: %w", err)
+	}
+
+	kubeconfig, ok := secret.Data[selector.Key]
+	if !ok {
+		return nil, fmt.Errorf("missing key %s in the witness kubeconfig secret %s", selector.Key, selector.Name)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if cached, ok := provider.clients[secretKey]; ok && bytes.Equal(cached.kubeconfig, kubeconfig) {
+		return cached.client, nil
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the witness kubeconfig: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	witnessClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("while creating the witness client: %w", err)
+	}
+
+	if provider.clients == nil {
+		provider.clients = make(map[types.NamespacedName]cachedClient)
+	}
+	provider.clients[secretKey] = cachedClient{kubeconfig: kubeconfig, client: witnessClient}
+	return witnessClient, nil
+}
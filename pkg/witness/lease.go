@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package witness
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsHeldBy checks if the passed Lease is held by the passed holder and
+// has not expired yet
+func IsHeldBy(lease *coordinationv1.Lease, holder string, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return false
+	}
+
+	return !isExpired(lease, now)
+}
+
+// isExpired checks if the passed Lease has not been renewed in time
+func isExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return lease.Spec.RenewTime.Add(duration).Before(now)
+}
+
+// Renew acquires or renews the witness Lease on behalf of the passed holder.
+// The Lease is taken over even when held by a different instance, as the
+// choice of the primary instance is made by the operator and the Lease is
+// only used to tell whether that instance is still running
+func Renew(
+	ctx context.Context,
+	cli client.Client,
+	key types.NamespacedName,
+	holder string,
+	duration time.Duration,
+	now time.Time,
+) error {
+	renewTime := metav1.NewMicroTime(now)
+	durationSeconds := int32(duration / time.Second)
+
+	var lease coordinationv1.Lease
+	err := cli.Get(ctx, key, &lease)
+	if apierrs.IsNotFound(err) {
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(holder),
+				LeaseDurationSeconds: pointer.Int32(durationSeconds),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		return cli.Create(ctx, &lease)
+	}
+	if err != nil {
+		return err
+	}
+
+	currentHolder := ""
+	if lease.Spec.HolderIdentity != nil {
+		currentHolder = *lease.Spec.HolderIdentity
+	}
+	if currentHolder != holder {
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		lease.Spec.HolderIdentity = pointer.String(holder)
+		lease.Spec.AcquireTime = &renewTime
+		lease.Spec.LeaseTransitions = pointer.Int32(transitions + 1)
+	}
+
+	lease.Spec.RenewTime = &renewTime
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(durationSeconds)
+	return cli.Update(ctx, &lease)
+}
+
+// IsPrimaryAlive checks if the witness Lease is still held by the passed
+// primary instance. A missing Lease means that the primary never reached
+// the witness
+func IsPrimaryAlive(
+	ctx context.Context,
+	cli client.Client,
+	key types.NamespacedName,
+	primary string,
+	now time.Time,
+) (bool, error) {
+	var lease coordinationv1.Lease
+	if err := cli.Get(ctx, key, &lease); err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return IsHeldBy(&lease, primary, now), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package witness
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover witness lease", func() {
+	var cli client.Client
+	key := types.NamespacedName{Namespace: "default", Name: "witness"}
+	now := time.Now()
+
+	BeforeEach(func() {
+		cli = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+	})
+
+	It("creates the lease when it doesn't exist", func() {
+		Expect(Renew(context.TODO(), cli, key, "cluster-example-1", 30*time.Second, now)).To(Succeed())
+
+		var lease coordinationv1.Lease
+		Expect(cli.Get(context.TODO(), key, &lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-1"))
+		Expect(*lease.Spec.LeaseDurationSeconds).To(BeEquivalentTo(30))
+		Expect(IsHeldBy(&lease, "cluster-example-1", now)).To(BeTrue())
+	})
+
+	It("takes over the lease when the primary changes", func() {
+		Expect(Renew(context.TODO(), cli, key, "cluster-example-1", 30*time.Second, now)).To(Succeed())
+		Expect(Renew(context.TODO(), cli, key, "cluster-example-2", 30*time.Second, now)).To(Succeed())
+
+		var lease coordinationv1.Lease
+		Expect(cli.Get(context.TODO(), key, &lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-2"))
+		Expect(*lease.Spec.LeaseTransitions).To(BeEquivalentTo(1))
+	})
+
+	It("considers the lease expired when it's not renewed in time", func() {
+		lease := &coordinationv1.Lease{
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String("cluster-example-1"),
+				LeaseDurationSeconds: pointer.Int32(30),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-time.Minute)},
+			},
+		}
+		Expect(IsHeldBy(lease, "cluster-example-1", now)).To(BeFalse())
+		Expect(IsHeldBy(lease, "cluster-example-1", now.Add(-45*time.Second))).To(BeTrue())
+		Expect(IsHeldBy(lease, "cluster-example-2", now.Add(-45*time.Second))).To(BeFalse())
+	})
+
+	It("reports the primary as alive only while it holds the lease", func() {
+		alive, err := IsPrimaryAlive(context.TODO(), cli, key, "cluster-example-1", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(alive).To(BeFalse())
+
+		Expect(Renew(context.TODO(), cli, key, "cluster-example-1", 30*time.Second, now)).To(Succeed())
+		alive, err = IsPrimaryAlive(context.TODO(), cli, key, "cluster-example-1", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(alive).To(BeTrue())
+
+		alive, err = IsPrimaryAlive(context.TODO(), cli, key, "cluster-example-1", now.Add(time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(alive).To(BeFalse())
+	})
+
+	It("uses the namespace of the cluster unless the witness is remote", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				FailoverWitness: &apiv1.FailoverWitnessConfiguration{
+					LeaseName:      "witness",
+					LeaseNamespace: "arbiter",
+				},
+			},
+		}
+		Expect(GetLeaseKey(cluster)).To(Equal(key))
+
+		cluster.Spec.FailoverWitness.KubeconfigSecret = &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "witness-kubeconfig"},
+			Key:                  "kubeconfig",
+		}
+		Expect(GetLeaseKey(cluster)).To(Equal(types.NamespacedName{Namespace: "arbiter", Name: "witness"}))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package witness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// renewerIdleInterval is the interval between two checks of the cluster
+	// configuration when the failover witness is not enabled
+	renewerIdleInterval = 30 * time.Second
+
+	// primaryHealthCheckTimeout is the time allowed to the health check of
+	// PostgreSQL before the lease is renewed
+	primaryHealthCheckTimeout = 5 * time.Second
+)
+
+// A Renewer is a runner that keeps the failover witness Lease renewed
+// while the local instance is the primary
+type Renewer struct {
+	instance       *postgres.Instance
+	client         client.Client
+	clientProvider ClientProvider
+}
+
+// NewRenewer creates a new failover witness Renewer
+func NewRenewer(instance *postgres.Instance, cli client.Client) *Renewer {
+	return &Renewer{
+		instance: instance,
+		client:   cli,
+	}
+}
+
+// Start starts running the failover witness Renewer
+func (r *Renewer) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("witness_renewer")
+	go func() {
+		interval := renewerIdleInterval
+		ticker := time.NewTicker(interval)
+
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated failover witness Renewer loop")
+		}()
+
+		for {
+			newInterval, err := r.renew(ctx)
+			if err != nil {
+				contextLog.Warning("renewing the failover witness lease", "err", err)
+			}
+
+			if newInterval != interval {
+				ticker.Reset(newInterval)
+				interval = newInterval
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// renew renews the failover witness Lease when needed, returning the time
+// to wait before the next renewal
+func (r *Renewer) renew(ctx context.Context) (time.Duration, error) {
+	var cluster apiv1.Cluster
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      r.instance.ClusterName,
+	}, &cluster); err != nil {
+		return renewerIdleInterval, err
+	}
+
+	if cluster.Spec.FailoverWitness == nil {
+		return renewerIdleInterval, nil
+	}
+
+	// The lease is renewed three times per lease duration, to tolerate
+	// a transient failure of the witness
+	leaseDuration := cluster.Spec.FailoverWitness.GetLeaseDuration()
+	interval := leaseDuration / 3
+
+	// An old primary that is being demoted must stop renewing the lease,
+	// letting the failover proceed as soon as possible
+	if cluster.Status.CurrentPrimary != r.instance.PodName ||
+		cluster.Status.TargetPrimary != r.instance.PodName {
+		return interval, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return interval, err
+	}
+
+	// The instance manager can be alive while PostgreSQL crashed or hangs:
+	// the lease is left to expire, not to block the failover forever
+	if err := r.checkPrimaryHealth(ctx); err != nil {
+		return interval, err
+	}
+
+	witnessClient, err := r.clientProvider.GetClient(ctx, r.client, &cluster)
+	if err != nil {
+		return interval, err
+	}
+
+	return interval, Renew(ctx, witnessClient, GetLeaseKey(&cluster), r.instance.PodName, leaseDuration, time.Now())
+}
+
+// checkPrimaryHealth checks that PostgreSQL is accepting queries and is
+// running as a primary
+func (r *Renewer) checkPrimaryHealth(ctx context.Context) error {
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while connecting to the primary: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, primaryHealthCheckTimeout)
+	defer cancel()
+
+	var isInRecovery bool
+	if err := db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&isInRecovery); err != nil {
+		return fmt.Errorf("while checking the health of the primary: %w", err)
+	}
+	if isInRecovery {
+		return fmt.Errorf("the instance is in recovery")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package witness
+
+import (
+	"context"
+	"os"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover witness renewer", func() {
+	It("doesn't renew the lease when PostgreSQL is down", func(ctx SpecContext) {
+		// No PostgreSQL server is listening in an empty socket directory
+		previousHost, hadHost := os.LookupEnv("PGHOST")
+		Expect(os.Setenv("PGHOST", GinkgoT().TempDir())).To(Succeed())
+		DeferCleanup(func() {
+			if hadHost {
+				_ = os.Setenv("PGHOST", previousHost)
+			} else {
+				_ = os.Unsetenv("PGHOST")
+			}
+		})
+
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				FailoverWitness: &apiv1.FailoverWitnessConfiguration{LeaseName: "witness"},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+
+		instance := postgres.NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		instance.Namespace = cluster.Namespace
+		instance.ClusterName = cluster.Name
+		instance.PodName = "cluster-example-1"
+		DeferCleanup(instance.ConnectionPool().ShutdownConnections)
+
+		interval, err := NewRenewer(instance, cli).renew(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(interval).To(Equal(10 * time.Second))
+
+		var lease coordinationv1.Lease
+		err = cli.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "witness"}, &lease)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})